@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/systems"
+)
+
+// Checkpoint is what an autosave records of a run: the seed and stream positions the
+// run's rolls continue from, the turn, and where the player is and how they fare.
+// Loading a checkpoint back isn't implemented yet.
+type Checkpoint struct {
+	RNG       systems.RNGState `json:"rng"`
+	Turn      int              `json:"turn"`
+	Depth     int              `json:"depth"` // Dungeon level of the player's map, 0 on the surface
+	X         int              `json:"x"`
+	Y         int              `json:"y"`
+	Health    int              `json:"health"`
+	MaxHealth int              `json:"max_health"`
+	Level     int              `json:"level"`
+	Exp       int              `json:"exp"`
+}
+
+// SetAutosave saves a checkpoint of the run to the file whenever the player finishes
+// resting at a camp. An empty path turns autosaving off.
+func (g *Game) SetAutosave(path string) {
+	if path == "" {
+		g.campSystem.SetSaveHandler(nil)
+		return
+	}
+	g.campSystem.SetSaveHandler(func() error {
+		return g.SaveCheckpoint(path)
+	})
+}
+
+// SaveCheckpoint writes a checkpoint of the current run to a JSON file
+func (g *Game) SaveCheckpoint(path string) error {
+	playerEntities := g.world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 || g.rng == nil {
+		return fmt.Errorf("no run in progress")
+	}
+	playerID := playerEntities[0].ID
+
+	checkpoint := Checkpoint{
+		RNG:  g.rng.State(),
+		Turn: g.timeSystem.Turn(g.world),
+	}
+	if posComp, exists := g.world.GetComponent(playerID, components.Position); exists {
+		pos := posComp.(*components.PositionComponent)
+		checkpoint.X, checkpoint.Y = pos.X, pos.Y
+	}
+	if statsComp, exists := g.world.GetComponent(playerID, components.Stats); exists {
+		stats := statsComp.(*components.StatsComponent)
+		checkpoint.Health, checkpoint.MaxHealth = stats.Health, stats.MaxHealth
+		checkpoint.Level, checkpoint.Exp = stats.Level, stats.Exp
+	}
+	if contextComp, exists := g.world.GetComponent(playerID, components.MapContextID); exists {
+		if mapTypeComp, exists := g.world.GetComponent(contextComp.(*components.MapContextComponent).MapID, components.MapType); exists {
+			checkpoint.Depth = mapTypeComp.(*components.MapTypeComponent).Level
+		}
+	}
+
+	fileData, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if err := os.WriteFile(path, fileData, 0644); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package components
+
+import (
+	"ebiten-rogue/ecs"
+)
+
+// BombComponent marks an item as an explosive that detonates a number of turns after it is armed.
+// The item's effects are applied to everything caught in the blast.
+type BombComponent struct {
+	Fuse    int          // Turns left until detonation once armed
+	Radius  int          // Blast radius in tiles
+	Armed   bool         // Whether the fuse is burning
+	OwnerID ecs.EntityID // Entity that armed the bomb, credited with kills
+}
+
+// NewBombComponent creates an unarmed bomb with the given fuse and blast radius
+func NewBombComponent(fuse, radius int) *BombComponent {
+	if radius < 1 {
+		radius = 1
+	}
+	return &BombComponent{
+		Fuse:   fuse,
+		Radius: radius,
+	}
+}
@@ -0,0 +1,20 @@
+package components
+
+// CampComponent marks a safe spot, such as a bedroll by a banked fire, where the
+// player can settle in and rest until fully healed. No reinforcements reach a floor
+// while the player is camped on it, nor for a while after they break camp.
+type CampComponent struct {
+	RestTurns  int // Turns a full rest takes
+	QuietTurns int // Turns after breaking camp that reinforcements still stay away
+}
+
+// NewCampComponent creates a camp with the given rest length and quiet spell
+func NewCampComponent(restTurns, quietTurns int) *CampComponent {
+	if restTurns < 1 {
+		restTurns = 1
+	}
+	return &CampComponent{
+		RestTurns:  restTurns,
+		QuietTurns: quietTurns,
+	}
+}
@@ -0,0 +1,22 @@
+package components
+
+import "ebiten-rogue/ecs"
+
+// CompanionComponent marks a creature that fights alongside its owner and goes with
+// them from map to map
+type CompanionComponent struct {
+	OwnerID        ecs.EntityID // Entity the companion follows, usually the player
+	FollowDistance int          // How close it stays to the owner with nothing to fight
+	LeashRange     int          // How far from the owner it will go after a monster
+	SightRange     int          // How far away it spots monsters
+}
+
+// NewCompanionComponent creates a companion that follows an owner at a short distance
+func NewCompanionComponent(ownerID ecs.EntityID) *CompanionComponent {
+	return &CompanionComponent{
+		OwnerID:        ownerID,
+		FollowDistance: 2,
+		LeashRange:     5,
+		SightRange:     6,
+	}
+}
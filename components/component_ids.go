@@ -16,15 +16,32 @@ const (
 	Camera     // Camera component for viewport management
 	Player
 	Stats
-	MapType        // Map type component for distinguishing between world map and dungeons
-	Name           // Name component for storing entity display names
-	MapContext     // Map context component for tracking which map an entity belongs to
-	Inventory      // Inventory component for storing items
-	Item           // Item component for collectible objects
-	FOV            // Field of vision component
-	Equipment      // Equipment component for equipped items
-	Container      // Container component for containers that can hold items
-	Rotation       // Rotation component for storing entity rotation
-	Effect         // Effect component for managing entity effects
-	MonsterAbility // Monster ability component for special abilities
+	MapType          // Map type component for distinguishing between world map and dungeons
+	Name             // Name component for storing entity display names
+	MapContext       // Map context component for tracking which map an entity belongs to
+	Inventory        // Inventory component for storing items
+	Item             // Item component for collectible objects
+	FOV              // Field of vision component
+	Equipment        // Equipment component for equipped items
+	Container        // Container component for containers that can hold items
+	Rotation         // Rotation component for storing entity rotation
+	Effect           // Effect component for managing entity effects
+	MonsterAbility   // Monster ability component for special abilities
+	Mechanism        // Mechanism component for levers and switches
+	Bomb             // Bomb component for explosives with a timed fuse
+	Resistance       // Resistance component for damage and status resistances
+	WorldState       // World state component for game-wide state like the turn counter
+	Scroll           // Scroll component for items read for a map or area effect
+	Pack             // Pack component for monsters that spawn and rout together
+	QuickSlots       // Quick slots component binding items to the player's quick-use keys
+	Destructible     // Destructible component for props that can be broken
+	Companion        // Companion component for creatures that follow and fight for an owner
+	FloorTheme       // Floor theme component recording the theme a dungeon floor was generated from
+	RegionNames      // Region names component holding the world map's generated place names
+	EquipmentSets    // Equipment sets component holding saved equipment configurations to swap between
+	TerrainChanges   // Terrain changes component tracking a map's transformed tiles until they revert
+	Camp             // Camp component for safe spots the player can rest at
+	PointsOfInterest // Points of interest component listing the ruins, caches and lairs on the world map
+	Torch            // Torch component for lights that can be thrown and burn out
+	Phase            // Phase component for monsters passing through walls
 )
@@ -0,0 +1,29 @@
+package components
+
+// DestructibleComponent marks a prop, such as a barrel or crate, that blocks movement
+// until it is broken by attacks. Broken props may drop loot or explode.
+type DestructibleComponent struct {
+	Health          int      // Damage left before the prop breaks
+	MaxHealth       int      // Health the prop started with
+	Loot            []string // Item templates one of which may drop when it breaks
+	LootChance      float64  // Chance of dropping loot when it breaks (0.0-1.0)
+	ExplosionRadius int      // Radius of the blast when it breaks, 0 for none
+	ExplosionDamage int      // Fire damage dealt to everything in the blast
+	NoiseRadius     int      // How far away monsters hear it break
+}
+
+// NewDestructibleComponent creates a prop with the given health and no loot or blast
+func NewDestructibleComponent(health int) *DestructibleComponent {
+	if health < 1 {
+		health = 1
+	}
+	return &DestructibleComponent{
+		Health:    health,
+		MaxHealth: health,
+	}
+}
+
+// Explodes returns true if the prop blows up when it breaks
+func (d *DestructibleComponent) Explodes() bool {
+	return d.ExplosionRadius > 0 && d.ExplosionDamage > 0
+}
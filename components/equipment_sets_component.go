@@ -0,0 +1,28 @@
+package components
+
+import "ebiten-rogue/ecs"
+
+// EquipmentSetCount is how many equipment sets a character can keep
+const EquipmentSetCount = 3
+
+// EquipmentSlots lists every equipment slot, main hand before off hand so a set can be
+// put on in this order
+var EquipmentSlots = []EquipmentSlot{SlotHead, SlotBody, SlotMainHand, SlotOffHand, SlotFeet, SlotAccessory}
+
+// EquipmentSet is a saved equipment configuration. Slots missing from Items were
+// empty when the set was saved.
+type EquipmentSet struct {
+	Name  string // Named after the main hand weapon when saved, like "Scrap Scythe set"
+	Items map[EquipmentSlot]ecs.EntityID
+}
+
+// IsEmpty returns whether nothing has been saved in the set
+func (s EquipmentSet) IsEmpty() bool {
+	return s.Items == nil
+}
+
+// EquipmentSetsComponent holds the equipment sets a character has saved to swap
+// between, such as a melee set and a ranged set
+type EquipmentSetsComponent struct {
+	Sets [EquipmentSetCount]EquipmentSet
+}
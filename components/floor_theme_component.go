@@ -0,0 +1,10 @@
+package components
+
+// FloorThemeComponent records what a dungeon floor was generated from, so the game
+// can hint at a floor before the player sets foot on it
+type FloorThemeComponent struct {
+	ThemeID      string
+	Name         string // Display name of the theme, like "Forest Caves"
+	Difficulty   int    // Theme difficulty, 1-10
+	DungeonLevel int    // Level the floor's monsters were drawn for
+}
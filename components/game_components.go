@@ -3,6 +3,9 @@ package components
 import (
 	"ebiten-rogue/ecs"
 	"image/color"
+	"math"
+	"strconv"
+	"strings"
 )
 
 // PositionComponent stores entity position
@@ -54,7 +57,9 @@ func NewRotationComponent(angle float64) *RotationComponent {
 }
 
 // PlayerComponent indicates that an entity is controlled by the player
-type PlayerComponent struct{}
+type PlayerComponent struct {
+	Sneaking bool // Moving carefully to avoid being noticed
+}
 
 // StatsComponent stores entity stats
 type StatsComponent struct {
@@ -64,10 +69,15 @@ type StatsComponent struct {
 	Defense         int
 	Level           int
 	Exp             int
-	Recovery        int // Recovery points for action point regeneration
-	ActionPoints    int // Current action points
-	MaxActionPoints int // Maximum action points
-	HealingFactor   int // Healing factor for health regeneration
+	Recovery        int            // Recovery points for action point regeneration
+	ActionPoints    int            // Current action points
+	MaxActionPoints int            // Maximum action points
+	HealingFactor   int            // Healing factor for health regeneration
+	Evasion         int            // Bonus to dodging attacks, on top of level
+	Accuracy        int            // Bonus to landing attacks, on top of level
+	Stealth         int            // Bonus to going unnoticed by monsters
+	Cleave          int            // Cells either side of the target a melee swing also strikes, 4 for all around
+	BonusDamage     map[string]int // Extra damage dealt per school on every hit, e.g. "fire"
 }
 
 // CollisionComponent indicates entity can collide with other entities
@@ -78,13 +88,30 @@ type CollisionComponent struct {
 // AIComponent stores AI behavior information
 type AIComponent struct {
 	Type             string     // Type of AI: "random", "chase", "slow_chase", etc.
+	TemplateID       string     // ID of the monster template that created this entity
 	SightRange       int        // How far the entity can see
 	Target           uint64     // Target entity ID (usually the player)
 	Path             []PathNode // Current path to target (if pathfinding)
 	LastKnownTargetX int        // Last known X position of target
 	LastKnownTargetY int        // Last known Y position of target
+	State            AIState    // Current state of the behavior state machine, empty until first tick
+	StateTurns       int        // Turns spent in the current state
+	HomeX, HomeY     int        // Where the entity returns to after losing its target
+	AlertTurns       int        // Turns left to show the alert over the entity after it spotted its target
 }
 
+// AIState is a state of a monster's behavior state machine
+type AIState string
+
+const (
+	AIStateIdle        AIState = "idle"        // Waiting or wandering near home
+	AIStateInvestigate AIState = "investigate" // Heading to where the target was last seen
+	AIStateChase       AIState = "chase"       // Pathing towards a visible target
+	AIStateAttack      AIState = "attack"      // Next to the target and fighting it
+	AIStateFlee        AIState = "flee"        // Running away from the target
+	AIStateReturn      AIState = "return"      // Walking back home
+)
+
 // PathNode represents a single point in a path
 type PathNode struct {
 	X, Y int
@@ -144,6 +171,16 @@ func (i *InventoryComponent) RemoveItem(itemID ecs.EntityID) bool {
 	return false
 }
 
+// Contains returns true if the item is in the inventory
+func (i *InventoryComponent) Contains(itemID ecs.EntityID) bool {
+	for _, id := range i.Items {
+		if id == itemID {
+			return true
+		}
+	}
+	return false
+}
+
 // GetItemByIndex returns the item at the given index or 0 if index is out of bounds
 func (i *InventoryComponent) GetItemByIndex(index int) ecs.EntityID {
 	if index < 0 || index >= len(i.Items) {
@@ -169,12 +206,17 @@ func (i *InventoryComponent) Size() int {
 
 // ItemComponent indicates that an entity is an item that can be collected
 type ItemComponent struct {
-	ItemType    string      // Type of item: "weapon", "armor", "potion", etc.
-	Value       int         // Base value/power of the item
-	Weight      int         // Weight of the item (for inventory capacity calculations)
-	Description string      // Description of the item
-	TemplateID  string      // ID of the template that created this item
-	Data        interface{} // Additional item-specific data
+	ItemType     string         // Type of item: "weapon", "armor", "potion", etc.
+	Value        int            // Base value/power of the item
+	Weight       int            // Weight of the item (for inventory capacity calculations)
+	Description  string         // Description of the item
+	TemplateID   string         // ID of the template that created this item
+	Data         interface{}    // Additional item-specific data
+	Sockets      int            // Number of gem sockets, 0 for none
+	SocketedGems []ecs.EntityID // Gems inserted into the sockets, in insertion order
+	Rarity       string         // Rarity tier: "common", "uncommon", "rare" or "epic"
+	TwoHanded    bool           // Weapon takes both hands; one-handed weapons can also go in the off hand
+	Junk         bool           // Marked by the player to be dropped or sold in bulk
 }
 
 // NewItemComponent creates a new item component
@@ -201,11 +243,17 @@ func NewItemComponentFromTemplate(templateID string, itemType string, value int,
 	}
 }
 
+// FreeSockets returns how many of the item's sockets are empty
+func (i *ItemComponent) FreeSockets() int {
+	return i.Sockets - len(i.SocketedGems)
+}
+
 // FOVComponent represents an entity's field of vision capabilities
 type FOVComponent struct {
 	Range       int  // How far the entity can see in tiles
 	LightSource bool // Whether this entity emits light
 	LightRange  int  // How far the light reaches if this is a light source
+	SightLimit  int  // Furthest the entity can see whatever its range, 0 for no limit
 }
 
 // NewFOVComponent creates a new FOV component with the specified range
@@ -245,12 +293,25 @@ type GameEffect struct {
 	Value     interface{}
 	Duration  int
 	Source    ecs.EntityID
+	School    string          // Damage or status school such as "fire" or "poison", empty for none
+	Stacking  StackingPolicy  // What happens when a matching effect is already present, empty for the type's default
+	Condition EffectCondition // When a conditional effect holds
+	Message   string          // Logged after the entity's name when a conditional effect takes hold
+	Active    bool            // Whether a conditional effect is currently applied
+	Applied   float64         // How much an active conditional effect changed its property, to undo it exactly
 	Target    struct {
 		Component string // Which component to affect (e.g., "Stats")
 		Property  string // Which property to modify (e.g., "Health")
 	}
 }
 
+// EffectCondition is what must be true for a conditional effect to apply. A zero
+// condition never holds.
+type EffectCondition struct {
+	HealthBelow float64 // Holds while health is under this fraction of max health
+	Weather     string  // Holds while the player is caught in the weather with this ID
+}
+
 // NewGameEffect creates a new effect with the given parameters
 func NewGameEffect(effectType EffectType, operation EffectOperation, value interface{}, duration int, source ecs.EntityID, targetComponent string, targetProperty string) GameEffect {
 	return GameEffect{
@@ -269,34 +330,107 @@ func NewGameEffect(effectType EffectType, operation EffectOperation, value inter
 	}
 }
 
-// EffectComponent stores active effects on an entity
-type EffectComponent struct {
-	Effects []GameEffect
+// StackingPolicy decides what happens when an effect lands on an entity that already
+// carries a matching one
+type StackingPolicy string
+
+const (
+	StackingStack           StackingPolicy = "stack"     // Keep both so they add up
+	StackingRefreshDuration StackingPolicy = "refresh"   // Replace the existing effect, restarting its duration
+	StackingIgnoreIfPresent StackingPolicy = "ignore"    // Keep the existing effect and drop the new one
+	StackingKeepStrongest   StackingPolicy = "strongest" // Keep whichever is stronger, the longer one on a tie
+)
+
+// StackingPolicy returns the effect's stacking policy. Effects without one use their
+// type's: instant effects all land, equipment and conditional effects never apply
+// twice, and anything that lasts is refreshed.
+func (e GameEffect) StackingPolicy() StackingPolicy {
+	if e.Stacking != "" {
+		return e.Stacking
+	}
+	switch e.Type {
+	case EffectTypeInstant:
+		return StackingStack
+	case EffectTypeEquipment, EffectTypeConditional:
+		return StackingIgnoreIfPresent
+	default:
+		return StackingRefreshDuration
+	}
 }
 
-// AddEffect adds a new effect to the component
-func (c *EffectComponent) AddEffect(effect GameEffect) {
-	// For periodic effects, we want to keep the original duration
-	if effect.Type == EffectTypePeriodic {
-		c.Effects = append(c.Effects, effect)
-		return
+// Matches returns whether two effects count as the same for stacking: they change the
+// same property the same way. Equipment effects are also keyed on the item they come
+// from, so two rings of strength both count while one ring can't apply twice, and
+// conditional effects on their condition.
+func (e GameEffect) Matches(other GameEffect) bool {
+	if e.Type != other.Type || e.Operation != other.Operation || e.School != other.School ||
+		e.Target.Component != other.Target.Component || e.Target.Property != other.Target.Property {
+		return false
+	}
+	switch e.Type {
+	case EffectTypeEquipment:
+		return e.Source == other.Source
+	case EffectTypeConditional:
+		return e.Condition == other.Condition
+	}
+	return true
+}
+
+// Strength returns the size of the effect's value for comparing effects, taking the
+// average for dice notation such as "2d6"
+func (e GameEffect) Strength() float64 {
+	switch v := e.Value.(type) {
+	case float64:
+		return math.Abs(v)
+	case int:
+		return math.Abs(float64(v))
+	case string:
+		if numDice, diceSize, found := strings.Cut(v, "d"); found {
+			count, err1 := strconv.Atoi(numDice)
+			size, err2 := strconv.Atoi(diceSize)
+			if err1 != nil || err2 != nil {
+				return 0
+			}
+			return float64(count) * float64(size+1) / 2
+		}
+		if num, err := strconv.ParseFloat(v, 64); err == nil {
+			return math.Abs(num)
+		}
 	}
+	return 0
+}
 
-	// For equipment and other effects, check if a similar effect already exists
-	for i, existing := range c.Effects {
-		if existing.Type == effect.Type &&
-			existing.Operation == effect.Operation &&
-			existing.Target.Component == effect.Target.Component &&
-			existing.Target.Property == effect.Target.Property &&
-			existing.Source == effect.Source { // Also check source for equipment effects
-			// Update the existing effect
+// EffectComponent stores active effects on an entity
+type EffectComponent struct {
+	Effects  []GameEffect
+	LastTurn int // Turn the effects last ticked on, so a turn reported twice ticks once
+}
+
+// AddEffect adds an effect, combining it with a matching one already present according
+// to the new effect's stacking policy. Returns false if the effect was dropped.
+func (c *EffectComponent) AddEffect(effect GameEffect) bool {
+	policy := effect.StackingPolicy()
+	if policy != StackingStack {
+		for i, existing := range c.Effects {
+			if !existing.Matches(effect) {
+				continue
+			}
+			switch policy {
+			case StackingIgnoreIfPresent:
+				return false
+			case StackingKeepStrongest:
+				if effect.Strength() < existing.Strength() ||
+					(effect.Strength() == existing.Strength() && effect.Duration <= existing.Duration) {
+					return false
+				}
+			}
 			c.Effects[i] = effect
-			return
+			return true
 		}
 	}
 
-	// If no similar effect exists, add the new one
 	c.Effects = append(c.Effects, effect)
+	return true
 }
 
 // RemoveEffect removes an effect at the given index
@@ -459,6 +593,27 @@ const (
 	TriggerOnHit       MonsterAbilityTrigger = "on_hit"
 	TriggerOnTurnStart MonsterAbilityTrigger = "on_turn_start"
 	TriggerOnTurnEnd   MonsterAbilityTrigger = "on_turn_end"
+	TriggerOnAggro     MonsterAbilityTrigger = "on_aggro" // The monster spots its target and gives chase
+	TriggerOnFlee      MonsterAbilityTrigger = "on_flee"  // The monster's nerve breaks and it starts to run
+	TriggerInRange     MonsterAbilityTrigger = "in_range" // The monster's target is in sight and within the ability's range
+)
+
+// MonsterAbilityAction names something an ability does beyond applying its effects
+type MonsterAbilityAction string
+
+const (
+	AbilityActionSplit       MonsterAbilityAction = "split"         // Bud off a copy of the monster onto a free adjacent tile
+	AbilityActionCallForHelp MonsterAbilityAction = "call_for_help" // Spend a turn calling, then reinforcements arrive
+	AbilityActionCharge      MonsterAbilityAction = "charge"        // Spend a turn marking an area, then hit everything still in it
+	AbilityActionPhase       MonsterAbilityAction = "phase"         // Spend a turn fading out, then move through walls for a while
+)
+
+// AbilityArea is the shape of the area a charged ability hits
+type AbilityArea string
+
+const (
+	AreaBurst AbilityArea = "burst" // Everything within the radius of the monster
+	AreaLine  AbilityArea = "line"  // A line from the monster through its target, out to the ability's range
 )
 
 // MonsterAbilityDef represents a single ability that a monster can use
@@ -472,6 +627,15 @@ type MonsterAbilityDef struct {
 	Cost        int
 	Effects     []GameEffect
 	Trigger     MonsterAbilityTrigger
+	Action      MonsterAbilityAction
+	Chance      float64     // Chance the ability fires when triggered, 0 for always
+	Summons     []string    // Templates a call for help brings in, the monster's own when empty
+	Count       int         // Monsters each call brings in
+	MaxUses     int         // Times the ability can be used, 0 for no limit
+	Uses        int         // Times the ability has been used
+	Area        AbilityArea // Shape a charged ability hits, a burst when empty
+	Radius      int         // Size of a charged burst
+	Duration    int         // Turns a phase lasts
 }
 
 // MonsterAbilityComponent stores a monster's abilities
@@ -528,3 +692,32 @@ func (m *MonsterAbilityComponent) UseAbility(name string) bool {
 	ability.CurrentCD = ability.Cooldown
 	return true
 }
+
+// ResistanceComponent scales the effects an entity receives by damage or status school.
+// A multiplier of 0 is an immunity, below 1 a resistance and above 1 a weakness.
+type ResistanceComponent struct {
+	Multipliers map[string]float64
+}
+
+// NewResistanceComponent creates a resistance component from school multipliers
+func NewResistanceComponent(multipliers map[string]float64) *ResistanceComponent {
+	if multipliers == nil {
+		multipliers = make(map[string]float64)
+	}
+	return &ResistanceComponent{
+		Multipliers: multipliers,
+	}
+}
+
+// Multiplier returns the multiplier for a school, 1 if the entity has no resistance to it
+func (r *ResistanceComponent) Multiplier(school string) float64 {
+	if multiplier, exists := r.Multipliers[school]; exists && school != "" {
+		return multiplier
+	}
+	return 1
+}
+
+// IsImmune returns true if effects of the school have no effect at all
+func (r *ResistanceComponent) IsImmune(school string) bool {
+	return r.Multiplier(school) <= 0
+}
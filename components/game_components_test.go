@@ -0,0 +1,115 @@
+package components
+
+import (
+	"testing"
+
+	"ebiten-rogue/ecs"
+)
+
+// poison is a periodic health drain of some strength and duration
+func poison(value interface{}, duration int) GameEffect {
+	effect := NewGameEffect(EffectTypePeriodic, EffectOpSubtract, value, duration, 0, "Stats", "Health")
+	effect.School = "poison"
+	return effect
+}
+
+// withStacking returns an effect with an explicit stacking policy
+func withStacking(effect GameEffect, policy StackingPolicy) GameEffect {
+	effect.Stacking = policy
+	return effect
+}
+
+// fromSource returns an effect credited to another entity
+func fromSource(effect GameEffect, source ecs.EntityID) GameEffect {
+	effect.Source = source
+	return effect
+}
+
+// whileBelow returns a conditional strength bonus that holds under a health fraction
+func whileBelow(fraction float64) GameEffect {
+	effect := NewGameEffect(EffectTypeConditional, EffectOpAdd, 2.0, 0, 0, "Stats", "Attack")
+	effect.Condition.HealthBelow = fraction
+	return effect
+}
+
+func TestAddEffectStacking(t *testing.T) {
+	strength := NewGameEffect(EffectTypeEquipment, EffectOpAdd, 2.0, 0, 0, "Stats", "Attack")
+	fire := poison(1.0, 3)
+	fire.School = "fire"
+
+	tests := []struct {
+		name     string
+		existing GameEffect
+		added    GameEffect
+		wantOK   bool
+		want     []GameEffect // Effects held afterwards
+	}{
+		{"lasting effects refresh by default", poison(1.0, 2), poison(1.0, 5), true, []GameEffect{poison(1.0, 5)}},
+		{"a refresh takes the new strength too", poison(3.0, 2), poison(1.0, 5), true, []GameEffect{poison(1.0, 5)}},
+		{
+			"stacking keeps both", withStacking(poison(1.0, 2), StackingStack), withStacking(poison(1.0, 5), StackingStack), true,
+			[]GameEffect{withStacking(poison(1.0, 2), StackingStack), withStacking(poison(1.0, 5), StackingStack)},
+		},
+		{
+			"ignoring keeps the first", withStacking(poison(1.0, 2), StackingIgnoreIfPresent), withStacking(poison(4.0, 9), StackingIgnoreIfPresent), false,
+			[]GameEffect{withStacking(poison(1.0, 2), StackingIgnoreIfPresent)},
+		},
+		{
+			"a stronger effect replaces a weaker one", withStacking(poison(1.0, 5), StackingKeepStrongest), withStacking(poison(2.0, 1), StackingKeepStrongest), true,
+			[]GameEffect{withStacking(poison(2.0, 1), StackingKeepStrongest)},
+		},
+		{
+			"a weaker effect is dropped", withStacking(poison(2.0, 1), StackingKeepStrongest), withStacking(poison(1.0, 5), StackingKeepStrongest), false,
+			[]GameEffect{withStacking(poison(2.0, 1), StackingKeepStrongest)},
+		},
+		{
+			"an equal effect that lasts longer replaces", withStacking(poison(2.0, 1), StackingKeepStrongest), withStacking(poison(2, 3), StackingKeepStrongest), true,
+			[]GameEffect{withStacking(poison(2, 3), StackingKeepStrongest)},
+		},
+		{
+			"an equal effect that doesn't last longer is dropped", withStacking(poison(2.0, 3), StackingKeepStrongest), withStacking(poison(2.0, 3), StackingKeepStrongest), false,
+			[]GameEffect{withStacking(poison(2.0, 3), StackingKeepStrongest)},
+		},
+		{
+			"dice are compared by their average", withStacking(poison(6.0, 3), StackingKeepStrongest), withStacking(poison("2d6", 3), StackingKeepStrongest), true,
+			[]GameEffect{withStacking(poison("2d6", 3), StackingKeepStrongest)},
+		},
+		{"other schools don't match", poison(1.0, 2), fire, true, []GameEffect{poison(1.0, 2), fire}},
+		{"one item can't apply twice", fromSource(strength, 7), fromSource(strength, 7), false, []GameEffect{fromSource(strength, 7)}},
+		{"two items apply independently", fromSource(strength, 7), fromSource(strength, 8), true, []GameEffect{fromSource(strength, 7), fromSource(strength, 8)}},
+		{"one condition can't apply twice", whileBelow(0.5), whileBelow(0.5), false, []GameEffect{whileBelow(0.5)}},
+		{"other conditions apply independently", whileBelow(0.5), whileBelow(0.25), true, []GameEffect{whileBelow(0.5), whileBelow(0.25)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effects := &EffectComponent{}
+			if !effects.AddEffect(tt.existing) {
+				t.Fatal("the first effect was dropped")
+			}
+			if ok := effects.AddEffect(tt.added); ok != tt.wantOK {
+				t.Errorf("AddEffect = %v, want %v", ok, tt.wantOK)
+			}
+			if len(effects.Effects) != len(tt.want) {
+				t.Fatalf("holding %d effects, want %d", len(effects.Effects), len(tt.want))
+			}
+			for i, effect := range effects.Effects {
+				if effect != tt.want[i] {
+					t.Errorf("effect %d = %+v, want %+v", i, effect, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInstantEffectsAlwaysStack(t *testing.T) {
+	heal := NewGameEffect(EffectTypeInstant, EffectOpAdd, 5.0, 0, 0, "Stats", "Health")
+	effects := &EffectComponent{}
+	for i := 0; i < 3; i++ {
+		if !effects.AddEffect(heal) {
+			t.Fatalf("instant effect %d was dropped", i+1)
+		}
+	}
+	if len(effects.Effects) != 3 {
+		t.Errorf("holding %d instant effects, want 3", len(effects.Effects))
+	}
+}
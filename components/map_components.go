@@ -70,6 +70,12 @@ const (
 	TileRailwayCross       = 116
 	// Special entity tiles
 	TileTrainSprite = 117 // Train sprite for player on world map
+	// Water on the world map, crossed only by bridge
+	TileRiver  = 118
+	TileBridge = 119
+	// Made by transforming other tiles
+	TileIce      = 120 // Frozen water
+	TileObsidian = 121 // Cooled lava
 )
 
 // TileDefinition describes the visual appearance of a tile type
@@ -141,6 +147,9 @@ func NewTileMappingComponent() *TileMappingComponent {
 	// Example: Use the fire symbol at position (15, 10) for lava
 	mapping.Definitions[TileLava] = NewTileDefinitionByPos(14, 7, color.RGBA{255, 0, 0, 255}) // Red
 
+	mapping.Definitions[TileIce] = NewTileDefinition('-', color.RGBA{170, 230, 255, 255})    // Pale blue
+	mapping.Definitions[TileObsidian] = NewTileDefinition(';', color.RGBA{90, 70, 110, 255}) // Dark purple
+
 	// Example: Use a nice grass symbol at position (5, 3) for grass
 	mapping.Definitions[TileGrass] = NewTileDefinitionByPos(0, 11, color.RGBA{0, 128, 0, 255}) // Green
 	// Example: Use a tree symbol at position (6, 4) for trees
@@ -170,6 +179,10 @@ func NewTileMappingComponent() *TileMappingComponent {
 	trainColor := color.RGBA{200, 200, 200, 255}                                     // Bright metallic color
 	mapping.Definitions[TileTrainSprite] = NewTileDefinitionByPos(13, 3, trainColor) // Train sprite
 
+	// Rivers and the bridges over them
+	mapping.Definitions[TileRiver] = NewTileDefinitionByPos(7, 15, color.RGBA{60, 110, 200, 255}) // Same waves as water
+	mapping.Definitions[TileBridge] = NewTileDefinition('=', color.RGBA{160, 110, 60, 255})       // Timber planks
+
 	return mapping
 }
 
@@ -227,6 +240,42 @@ func (m *MapComponent) IsWall(x, y int) bool {
 	return tileType == TileWall
 }
 
+// BlocksMovement returns true if nothing can walk onto the tile at (x, y): walls, and
+// rivers away from their bridges. Out of bounds always blocks.
+func (m *MapComponent) BlocksMovement(x, y int) bool {
+	if !m.InBounds(x, y) {
+		return true
+	}
+	return !GetTileProperties(m.Tiles[y][x]).IsWalkable
+}
+
+// BlocksSight returns true if the tile at (x, y) stops line of sight. Out of bounds
+// always blocks.
+func (m *MapComponent) BlocksSight(x, y int) bool {
+	if !m.InBounds(x, y) {
+		return true
+	}
+	return GetTileProperties(m.Tiles[y][x]).IsOpaque
+}
+
+// IsHazard returns true if standing on the tile at (x, y) is dangerous
+func (m *MapComponent) IsHazard(x, y int) bool {
+	return m.InBounds(x, y) && GetTileProperties(m.Tiles[y][x]).IsHazard
+}
+
+// MoveCost returns what pathfinding pays to step onto the tile at (x, y)
+func (m *MapComponent) MoveCost(x, y int) int {
+	if !m.InBounds(x, y) {
+		return 1
+	}
+	return GetTileProperties(m.Tiles[y][x]).MoveCost
+}
+
+// InBounds returns true if (x, y) lies on the map
+func (m *MapComponent) InBounds(x, y int) bool {
+	return x >= 0 && x < m.Width && y >= 0 && y < m.Height
+}
+
 // IsWallFunc is a function pointer set by generation/mapping_helper.go
 // It implements full wall tile type detection to avoid import cycles
 var IsWallFunc func(tileType int) bool
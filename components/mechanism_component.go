@@ -0,0 +1,24 @@
+package components
+
+// MechanismTarget is a map tile switched by a mechanism
+type MechanismTarget struct {
+	X, Y    int
+	OffTile int // Tile while the mechanism is off, e.g. TileWall for a closed passage
+	OnTile  int // Tile while the mechanism is on, e.g. TileDoor or TileFloor
+}
+
+// MechanismComponent links a lever or switch to the map tiles it controls
+type MechanismComponent struct {
+	Targets []MechanismTarget // Tiles toggled when the mechanism is activated
+	OneShot bool              // One-shot mechanisms can only be switched on, once
+	Active  bool              // Whether the mechanism is currently on
+	Used    bool              // Whether the mechanism has been activated at least once
+}
+
+// NewMechanismComponent creates a new mechanism controlling the given tiles
+func NewMechanismComponent(targets []MechanismTarget, oneShot bool) *MechanismComponent {
+	return &MechanismComponent{
+		Targets: targets,
+		OneShot: oneShot,
+	}
+}
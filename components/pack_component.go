@@ -0,0 +1,22 @@
+package components
+
+import (
+	"ebiten-rogue/ecs"
+)
+
+// PackComponent marks a monster as a member of a pack. Packs share a morale pool that
+// drops as members die; once it breaks, the survivors run.
+type PackComponent struct {
+	PackID     ecs.EntityID // Shared by every member, the ID of the member spawned first
+	Leader     bool         // Whether this member leads the pack
+	Leadership int          // Morale the member lends the pack while it leads
+}
+
+// NewPackComponent creates pack membership for a monster
+func NewPackComponent(packID ecs.EntityID, leader bool, leadership int) *PackComponent {
+	return &PackComponent{
+		PackID:     packID,
+		Leader:     leader,
+		Leadership: leadership,
+	}
+}
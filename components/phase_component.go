@@ -0,0 +1,14 @@
+package components
+
+// PhaseComponent marks a monster that has phased out of solid form and can pass
+// through walls until it runs out of turns
+type PhaseComponent struct {
+	TurnsLeft int // Turns before the monster is solid again
+}
+
+// NewPhaseComponent creates a phase lasting the given turns
+func NewPhaseComponent(turns int) *PhaseComponent {
+	return &PhaseComponent{
+		TurnsLeft: max(turns, 1),
+	}
+}
@@ -0,0 +1,35 @@
+package components
+
+// PointOfInterestKind is what's found at a point of interest on the world map
+type PointOfInterestKind int
+
+const (
+	POIRuin  PointOfInterestKind = iota // Surface ruins over a small dungeon
+	POICache                            // A one-time cache of supplies
+	POILair                             // The lair of a world boss
+)
+
+// PointOfInterest is a place on the world map worth seeking out
+type PointOfInterest struct {
+	Kind    PointOfInterestKind
+	Name    string // Display name, like "Ruins of Kelamoor"
+	X, Y    int    // World map tile
+	Biome   int    // Biome tile it was placed on
+	Visited bool   // Whether the player has come upon it
+}
+
+// PointsOfInterestComponent holds the points of interest placed on a world map. They
+// come from the run's seed, so the same seed always places them the same way.
+type PointsOfInterestComponent struct {
+	Places []PointOfInterest
+}
+
+// At returns the point of interest on a tile, or nil if there is none
+func (c *PointsOfInterestComponent) At(x, y int) *PointOfInterest {
+	for i := range c.Places {
+		if c.Places[i].X == x && c.Places[i].Y == y {
+			return &c.Places[i]
+		}
+	}
+	return nil
+}
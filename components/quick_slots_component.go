@@ -0,0 +1,55 @@
+package components
+
+import (
+	"ebiten-rogue/ecs"
+)
+
+// QuickSlotCount is how many quick-use slots the player has, bound to the keys 1-9
+const QuickSlotCount = 9
+
+// QuickSlotsComponent binds inventory items to quick-use keys so they can be used
+// without opening the inventory. An empty slot holds 0.
+type QuickSlotsComponent struct {
+	Slots [QuickSlotCount]ecs.EntityID
+}
+
+// NewQuickSlotsComponent creates a set of empty quick slots
+func NewQuickSlotsComponent() *QuickSlotsComponent {
+	return &QuickSlotsComponent{}
+}
+
+// Bind puts an item in a slot, taking it out of any other slot it was in
+func (q *QuickSlotsComponent) Bind(slot int, itemID ecs.EntityID) {
+	if slot < 0 || slot >= QuickSlotCount {
+		return
+	}
+	q.Clear(itemID)
+	q.Slots[slot] = itemID
+}
+
+// Clear empties every slot holding the item
+func (q *QuickSlotsComponent) Clear(itemID ecs.EntityID) {
+	for i, id := range q.Slots {
+		if id == itemID {
+			q.Slots[i] = 0
+		}
+	}
+}
+
+// Get returns the item in a slot, or 0 if it's empty
+func (q *QuickSlotsComponent) Get(slot int) ecs.EntityID {
+	if slot < 0 || slot >= QuickSlotCount {
+		return 0
+	}
+	return q.Slots[slot]
+}
+
+// SlotOf returns the slot an item is bound to, or -1 if it isn't bound
+func (q *QuickSlotsComponent) SlotOf(itemID ecs.EntityID) int {
+	for i, id := range q.Slots {
+		if id != 0 && id == itemID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,34 @@
+package components
+
+// NamedPlace is a named spot on the world map, such as a substation
+type NamedPlace struct {
+	Name string
+	X, Y int
+}
+
+// RegionNamesComponent holds the generated names of a world map's regions and
+// stations. The names come from the run's seed, so the same seed always names the
+// world the same way.
+type RegionNamesComponent struct {
+	Regions  []NamedPlace // Named biome regions, each at the tile its label is drawn on
+	Stations []NamedPlace // Named substations, at their tiles
+	RegionAt [][]int      // Index into Regions of each tile, -1 for tiles in no named region
+}
+
+// RegionName returns the name of the region holding a tile, or "" if it has none
+func (c *RegionNamesComponent) RegionName(x, y int) string {
+	if y < 0 || y >= len(c.RegionAt) || x < 0 || x >= len(c.RegionAt[y]) || c.RegionAt[y][x] < 0 {
+		return ""
+	}
+	return c.Regions[c.RegionAt[y][x]].Name
+}
+
+// StationName returns the name of the station on a tile, or "" if there is none
+func (c *RegionNamesComponent) StationName(x, y int) string {
+	for _, station := range c.Stations {
+		if station.X == x && station.Y == y {
+			return station.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package components
+
+// Scroll effects, chosen by the item template's "scroll" field
+const (
+	ScrollMagicMapping = "magic_mapping" // Reveals the whole current map
+	ScrollTeleport     = "teleport"      // Moves the reader to a random open tile
+	ScrollArea         = "area"          // Applies the item's effects around a chosen target
+	ScrollScan         = "scan"          // Shows every monster on the map as a radar blip for a while
+	ScrollChain        = "chain"         // Strikes a target, then jumps on to the monsters nearest it
+	ScrollFreeze       = "freeze"        // Freezes the water around the reader into ice
+	ScrollCool         = "cool"          // Cools the lava around the reader into obsidian
+)
+
+// ScrollComponent marks an item as a scroll. Scrolls act on the map or on an area
+// instead of applying their effects to the reader like potions do.
+type ScrollComponent struct {
+	Effect string // One of the Scroll* effects
+	Radius int    // Radius of an area scroll in tiles
+
+	// Chain scrolls
+	Jumps     int     // Monsters struck after the first
+	JumpRange int     // Furthest a jump reaches in tiles, through walls
+	Falloff   float64 // Share of the effects' strength lost at each jump
+
+	// Terrain scrolls
+	Duration int // Turns until the transformed tiles revert, 0 for good
+}
+
+// NewScrollComponent creates a scroll with the given effect and area radius
+func NewScrollComponent(effect string, radius int) *ScrollComponent {
+	return &ScrollComponent{
+		Effect: effect,
+		Radius: radius,
+	}
+}
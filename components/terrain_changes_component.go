@@ -0,0 +1,14 @@
+package components
+
+// TerrainChange is a tile an effect turned into another for a while
+type TerrainChange struct {
+	X, Y      int
+	From, To  int // Tile types before and after the change
+	TurnsLeft int // Turns until the tile goes back to From
+}
+
+// TerrainChangesComponent tracks the temporary tile changes on a map. Changes made for
+// good aren't tracked.
+type TerrainChangesComponent struct {
+	Changes []TerrainChange
+}
@@ -0,0 +1,75 @@
+package components
+
+// TileProperties describes how a tile type behaves, as opposed to how it looks, which
+// is the TileMappingComponent's job. Adding a tile type is one entry in TileTable.
+type TileProperties struct {
+	IsWalkable bool // Creatures can stand on it
+	IsOpaque   bool // It blocks sight
+	IsHazard   bool // Standing on it is dangerous, so autoexplore won't path through it
+	MoveCost   int  // What pathfinding pays to step onto it, 1 for plain ground
+	Glyph      rune // Reference character, used for ASCII dumps of a map
+}
+
+// wallProperties are shared by every wall tile, box drawing variants included
+var wallProperties = TileProperties{IsOpaque: true, MoveCost: 1, Glyph: '#'}
+
+// unknownTileProperties are returned for tile types missing from TileTable
+var unknownTileProperties = TileProperties{IsWalkable: true, MoveCost: 1, Glyph: '?'}
+
+// TileTable holds the properties of every tile type
+var TileTable = map[int]TileProperties{
+	TileFloor:      {IsWalkable: true, MoveCost: 1, Glyph: '.'},
+	TileWall:       wallProperties,
+	TileDoor:       {IsWalkable: true, MoveCost: 1, Glyph: '+'},
+	TileStairsDown: {IsWalkable: true, MoveCost: 1, Glyph: '>'},
+	TileStairsUp:   {IsWalkable: true, MoveCost: 1, Glyph: '<'},
+	TileWater:      {IsWalkable: true, MoveCost: 2, Glyph: '~'}, // Wading is slow going
+	TileLava:       {IsWalkable: true, IsHazard: true, MoveCost: 5, Glyph: '='},
+	TileGrass:      {IsWalkable: true, MoveCost: 1, Glyph: '"'},
+	TileTree:       {IsWalkable: true, MoveCost: 1, Glyph: 'T'},
+	TileIce:        {IsWalkable: true, MoveCost: 1, Glyph: '-'},
+	TileObsidian:   {IsWalkable: true, MoveCost: 1, Glyph: ';'},
+
+	TileWallHorizontal:  wallProperties,
+	TileWallVertical:    wallProperties,
+	TileWallTopLeft:     wallProperties,
+	TileWallTopRight:    wallProperties,
+	TileWallBottomLeft:  wallProperties,
+	TileWallBottomRight: wallProperties,
+	TileWallTeeLeft:     wallProperties,
+	TileWallTeeRight:    wallProperties,
+	TileWallTeeTop:      wallProperties,
+	TileWallTeeBottom:   wallProperties,
+	TileWallCross:       wallProperties,
+
+	// World map
+	TileWasteland:          {IsWalkable: true, MoveCost: 1, Glyph: ','},
+	TileDesert:             {IsWalkable: true, MoveCost: 1, Glyph: ':'},
+	TileDarkForest:         {IsWalkable: true, MoveCost: 1, Glyph: '&'},
+	TileMountains:          {IsWalkable: true, MoveCost: 1, Glyph: '^'},
+	TileRuinedRailway:      {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileSubstation:         {IsWalkable: true, MoveCost: 1, Glyph: 'S'},
+	TileRailwayHorizontal:  {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayVertical:    {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayTopLeft:     {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayTopRight:    {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayBottomLeft:  {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayBottomRight: {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayTeeLeft:     {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayTeeRight:    {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayTeeTop:      {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayTeeBottom:   {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileRailwayCross:       {IsWalkable: true, MoveCost: 1, Glyph: '='},
+	TileTrainSprite:        {IsWalkable: true, MoveCost: 1, Glyph: '@'},
+	TileRiver:              {MoveCost: 1, Glyph: '~'}, // Crossed only by bridge
+	TileBridge:             {IsWalkable: true, MoveCost: 1, Glyph: '='},
+}
+
+// GetTileProperties returns the properties of a tile type. Unknown types behave like
+// plain floor so a missing entry can't trap anyone.
+func GetTileProperties(tileType int) TileProperties {
+	if props, exists := TileTable[tileType]; exists {
+		return props
+	}
+	return unknownTileProperties
+}
@@ -0,0 +1,20 @@
+package components
+
+// TorchComponent marks an item as a torch that can be lit and thrown. Once lit it
+// lights the ground around it until it burns out.
+type TorchComponent struct {
+	BurnTurns  int  // Turns left to burn once lit
+	LightRange int  // Radius of the light it casts in tiles
+	Lit        bool // Whether the torch is burning
+}
+
+// NewTorchComponent creates an unlit torch that burns for the given turns
+func NewTorchComponent(burnTurns, lightRange int) *TorchComponent {
+	if lightRange < 1 {
+		lightRange = 1
+	}
+	return &TorchComponent{
+		BurnTurns:  burnTurns,
+		LightRange: lightRange,
+	}
+}
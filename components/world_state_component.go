@@ -0,0 +1,15 @@
+package components
+
+// WorldStateComponent holds game-wide state that belongs to no map or creature,
+// such as how many turns have passed. It lives on a single entity tagged "world_state".
+type WorldStateComponent struct {
+	Turn           int   // Turns the player has completed since the game started
+	Seed           int64 // Master seed the run's random streams are derived from
+	Practice       bool  // Death sends the player back to the floor's entrance instead of ending the run
+	PracticeDeaths int   // Times the player has died in practice mode
+}
+
+// NewWorldStateComponent creates world state at turn zero
+func NewWorldStateComponent() *WorldStateComponent {
+	return &WorldStateComponent{}
+}
@@ -0,0 +1,240 @@
+package config
+
+import "image/color"
+
+// Palette names the colors the interface is drawn in, so the look of the panels can
+// be changed in one place. Tile and monster colors come from their templates instead,
+// apart from hazard tiles, which a palette may recolor and give a glyph of their own.
+type Palette struct {
+	Background color.RGBA // Cleared screen and panel backgrounds, and what fades go to
+	Remembered color.RGBA // Explored tiles out of view whose own color can't be dimmed
+
+	// Side panels and the message log
+	PanelTitle         color.RGBA // Panel titles such as CHARACTER INFO
+	PanelHeading       color.RGBA // Section headings within a panel
+	PanelText          color.RGBA // Ordinary text and controls
+	PanelInfo          color.RGBA // Attack, experience, location and inventory entries
+	PanelDim           color.RGBA // Empty slots and unknown values
+	PanelSeparator     color.RGBA // Lines between sections
+	SelectionHighlight color.RGBA // The selected inventory item
+	BossTitle          color.RGBA // Panel title when inspecting a boss
+
+	// Stats
+	HealthText     color.RGBA
+	HealthBarFull  color.RGBA
+	HealthBarEmpty color.RGBA
+	DefenseText    color.RGBA
+	LevelText      color.RGBA
+	AccuracyText   color.RGBA
+	StealthText    color.RGBA
+	SneakingText   color.RGBA // Stealth line while sneaking
+	NegativeEffect color.RGBA // Effects that take something away, like bleeding
+	PoisonEffect   color.RGBA // Effects of the poison school
+	EffectCues     bool       // Mark effects with + or - so they don't rely on color alone
+
+	// Monster health, as a label or a tint, shading from high through mid to low
+	HealthHigh color.RGBA
+	HealthMid  color.RGBA
+	HealthLow  color.RGBA
+
+	// Hazard tiles such as lava
+	HazardTile  color.RGBA // Zero keeps the tile's own color
+	HazardGlyph rune       // Character drawn instead of the tile's picture, 0 to keep it
+
+	// Marks over monsters for what they are doing
+	AlertInvestigate color.RGBA // "?" over a monster searching for the player
+	AlertSpotted     color.RGBA // "!" over a monster that has just spotted the player
+
+	// Spell effects
+	LightningArc color.RGBA // Path a chain of lightning jumped along
+	Telegraph    color.RGBA // Tiles a charging monster is about to hit, drawn translucent
+
+	// Containers on the map
+	ContainerUnopened color.RGBA // Closed containers not yet looted
+	ContainerLocked   color.RGBA // Locked containers, also marked with a padlock
+	ContainerEmpty    color.RGBA // Opened containers with nothing left inside
+
+	// Equipment
+	EmptySlotGlyph  color.RGBA
+	FilledSlotGlyph color.RGBA
+	SocketedGem     color.RGBA
+
+	// Item rarity tiers
+	RarityCommon   color.RGBA
+	RarityUncommon color.RGBA
+	RarityRare     color.RGBA
+	RarityEpic     color.RGBA
+
+	// Debug window
+	DebugBackground color.RGBA
+	DebugPattern    color.RGBA
+	DebugText       color.RGBA
+}
+
+// DefaultPalette is the game's usual look
+var DefaultPalette = Palette{
+	Background: color.RGBA{0, 0, 0, 255},
+	Remembered: color.RGBA{40, 40, 40, 255},
+
+	PanelTitle:         color.RGBA{255, 255, 255, 255},
+	PanelHeading:       color.RGBA{255, 230, 150, 255},
+	PanelText:          color.RGBA{200, 200, 200, 255},
+	PanelInfo:          color.RGBA{200, 200, 255, 255},
+	PanelDim:           color.RGBA{150, 150, 150, 255},
+	PanelSeparator:     color.RGBA{180, 180, 180, 255},
+	SelectionHighlight: color.RGBA{255, 255, 100, 255},
+	BossTitle:          color.RGBA{255, 80, 40, 255},
+
+	HealthText:     color.RGBA{255, 200, 200, 255},
+	HealthBarFull:  color.RGBA{200, 0, 0, 255},
+	HealthBarEmpty: color.RGBA{100, 0, 0, 255},
+	DefenseText:    color.RGBA{200, 255, 200, 255},
+	LevelText:      color.RGBA{255, 255, 200, 255},
+	AccuracyText:   color.RGBA{255, 220, 200, 255},
+	StealthText:    color.RGBA{170, 170, 200, 255},
+	SneakingText:   color.RGBA{150, 200, 255, 255},
+	NegativeEffect: color.RGBA{255, 100, 100, 255},
+	PoisonEffect:   color.RGBA{255, 100, 100, 255},
+
+	HealthHigh: color.RGBA{80, 220, 60, 255},
+	HealthMid:  color.RGBA{230, 220, 60, 255},
+	HealthLow:  color.RGBA{230, 50, 50, 255},
+
+	AlertInvestigate: color.RGBA{255, 220, 80, 255},
+	AlertSpotted:     color.RGBA{255, 60, 40, 255},
+
+	LightningArc: color.RGBA{130, 200, 255, 255},
+	Telegraph:    color.RGBA{120, 38, 0, 120},
+
+	ContainerUnopened: color.RGBA{255, 215, 90, 255},
+	ContainerLocked:   color.RGBA{150, 170, 210, 255},
+	ContainerEmpty:    color.RGBA{70, 70, 70, 255},
+
+	EmptySlotGlyph:  color.RGBA{80, 80, 80, 255},
+	FilledSlotGlyph: color.RGBA{220, 220, 220, 255},
+	SocketedGem:     color.RGBA{255, 120, 120, 255},
+
+	RarityCommon:   color.RGBA{220, 220, 255, 255},
+	RarityUncommon: color.RGBA{100, 220, 100, 255},
+	RarityRare:     color.RGBA{90, 150, 255, 255},
+	RarityEpic:     color.RGBA{200, 110, 255, 255},
+
+	DebugBackground: color.RGBA{20, 20, 30, 255},
+	DebugPattern:    color.RGBA{40, 40, 60, 255},
+	DebugText:       color.RGBA{255, 255, 255, 255},
+}
+
+// HighContrastPalette trades the muted pastels for bright, fully saturated colors on
+// black, for players who find the default hard to read
+var HighContrastPalette = Palette{
+	Background: color.RGBA{0, 0, 0, 255},
+	Remembered: color.RGBA{90, 90, 90, 255},
+
+	PanelTitle:         color.RGBA{255, 255, 255, 255},
+	PanelHeading:       color.RGBA{255, 255, 0, 255},
+	PanelText:          color.RGBA{255, 255, 255, 255},
+	PanelInfo:          color.RGBA{140, 220, 255, 255},
+	PanelDim:           color.RGBA{190, 190, 190, 255},
+	PanelSeparator:     color.RGBA{255, 255, 255, 255},
+	SelectionHighlight: color.RGBA{255, 255, 0, 255},
+	BossTitle:          color.RGBA{255, 90, 0, 255},
+
+	HealthText:     color.RGBA{255, 150, 150, 255},
+	HealthBarFull:  color.RGBA{255, 0, 0, 255},
+	HealthBarEmpty: color.RGBA{70, 0, 0, 255},
+	DefenseText:    color.RGBA{130, 255, 130, 255},
+	LevelText:      color.RGBA{255, 255, 150, 255},
+	AccuracyText:   color.RGBA{255, 190, 130, 255},
+	StealthText:    color.RGBA{220, 220, 255, 255},
+	SneakingText:   color.RGBA{0, 230, 255, 255},
+	NegativeEffect: color.RGBA{255, 60, 60, 255},
+	PoisonEffect:   color.RGBA{255, 60, 60, 255},
+
+	HealthHigh: color.RGBA{80, 220, 60, 255},
+	HealthMid:  color.RGBA{230, 220, 60, 255},
+	HealthLow:  color.RGBA{230, 50, 50, 255},
+
+	AlertInvestigate: color.RGBA{255, 255, 0, 255},
+	AlertSpotted:     color.RGBA{255, 0, 0, 255},
+
+	LightningArc: color.RGBA{0, 255, 255, 255},
+	Telegraph:    color.RGBA{160, 0, 0, 160},
+
+	ContainerUnopened: color.RGBA{255, 255, 0, 255},
+	ContainerLocked:   color.RGBA{140, 220, 255, 255},
+	ContainerEmpty:    color.RGBA{110, 110, 110, 255},
+
+	EmptySlotGlyph:  color.RGBA{150, 150, 150, 255},
+	FilledSlotGlyph: color.RGBA{255, 255, 255, 255},
+	SocketedGem:     color.RGBA{255, 80, 80, 255},
+
+	RarityCommon:   color.RGBA{255, 255, 255, 255},
+	RarityUncommon: color.RGBA{0, 255, 0, 255},
+	RarityRare:     color.RGBA{80, 170, 255, 255},
+	RarityEpic:     color.RGBA{255, 90, 255, 255},
+
+	DebugBackground: color.RGBA{0, 0, 0, 255},
+	DebugPattern:    color.RGBA{70, 70, 70, 255},
+	DebugText:       color.RGBA{255, 255, 255, 255},
+}
+
+// ColorblindPalette keeps to hues that stay apart under deuteranopia and protanopia,
+// after the Okabe-Ito set: health runs from blue through yellow to vermilion rather
+// than green to red, poison is purple, and rarities avoid pairing red with green.
+// Effects are marked + or - and lava gets a glyph of its own, so none of them rely on
+// color alone.
+var ColorblindPalette = Palette{
+	Background: color.RGBA{0, 0, 0, 255},
+	Remembered: color.RGBA{50, 50, 50, 255},
+
+	PanelTitle:         color.RGBA{255, 255, 255, 255},
+	PanelHeading:       color.RGBA{240, 228, 66, 255},
+	PanelText:          color.RGBA{210, 210, 210, 255},
+	PanelInfo:          color.RGBA{86, 180, 233, 255},
+	PanelDim:           color.RGBA{150, 150, 150, 255},
+	PanelSeparator:     color.RGBA{180, 180, 180, 255},
+	SelectionHighlight: color.RGBA{240, 228, 66, 255},
+	BossTitle:          color.RGBA{230, 159, 0, 255},
+
+	HealthText:     color.RGBA{255, 200, 150, 255},
+	HealthBarFull:  color.RGBA{0, 114, 178, 255},
+	HealthBarEmpty: color.RGBA{90, 40, 0, 255},
+	DefenseText:    color.RGBA{160, 210, 240, 255},
+	LevelText:      color.RGBA{255, 255, 200, 255},
+	AccuracyText:   color.RGBA{255, 220, 170, 255},
+	StealthText:    color.RGBA{170, 170, 200, 255},
+	SneakingText:   color.RGBA{86, 180, 233, 255},
+	NegativeEffect: color.RGBA{230, 159, 0, 255},
+	PoisonEffect:   color.RGBA{204, 121, 167, 255},
+	EffectCues:     true,
+
+	HealthHigh: color.RGBA{86, 180, 233, 255},
+	HealthMid:  color.RGBA{240, 228, 66, 255},
+	HealthLow:  color.RGBA{213, 94, 0, 255},
+
+	HazardTile:  color.RGBA{230, 159, 0, 255},
+	HazardGlyph: '^',
+
+	AlertInvestigate: color.RGBA{240, 228, 66, 255},
+	AlertSpotted:     color.RGBA{213, 94, 0, 255},
+
+	LightningArc: color.RGBA{86, 180, 233, 255},
+	Telegraph:    color.RGBA{117, 52, 0, 140},
+
+	ContainerUnopened: color.RGBA{240, 228, 66, 255},
+	ContainerLocked:   color.RGBA{86, 180, 233, 255},
+	ContainerEmpty:    color.RGBA{70, 70, 70, 255},
+
+	EmptySlotGlyph:  color.RGBA{80, 80, 80, 255},
+	FilledSlotGlyph: color.RGBA{220, 220, 220, 255},
+	SocketedGem:     color.RGBA{204, 121, 167, 255},
+
+	RarityCommon:   color.RGBA{220, 220, 220, 255},
+	RarityUncommon: color.RGBA{240, 228, 66, 255},
+	RarityRare:     color.RGBA{86, 180, 233, 255},
+	RarityEpic:     color.RGBA{204, 121, 167, 255},
+
+	DebugBackground: color.RGBA{20, 20, 30, 255},
+	DebugPattern:    color.RGBA{40, 40, 60, 255},
+	DebugText:       color.RGBA{255, 255, 255, 255},
+}
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// LoadoutItem is an item stack the player starts with
+type LoadoutItem struct {
+	TemplateID string `json:"template_id"` // Item template ID
+	Count      int    `json:"count"`       // Number of items, defaults to 1
+}
+
+// Loadout represents a starting class: the player's stats, gear and supplies
+type Loadout struct {
+	ID          string `json:"id"`          // Unique identifier
+	Name        string `json:"name"`        // Display name
+	Description string `json:"description"` // Description text
+	Order       int    `json:"order"`       // Position in the class selection list
+
+	// Starting stats
+	Stats struct {
+		Health        int `json:"health"`
+		Attack        int `json:"attack"`
+		Defense       int `json:"defense"`
+		Evasion       int `json:"evasion"`
+		Accuracy      int `json:"accuracy"`
+		Stealth       int `json:"stealth"`
+		HealingFactor int `json:"healing_factor"`
+		SightRange    int `json:"sight_range"` // FOV range in tiles
+	} `json:"stats"`
+
+	Equipment  []string      `json:"equipment"`   // Item template IDs equipped at the start
+	Inventory  []LoadoutItem `json:"inventory"`   // Additional items carried in the inventory
+	ChestItems []LoadoutItem `json:"chest_items"` // Added to the starting theme's chest, if it has one
+}
+
+// ValidateLoadout ensures that the loadout has all required fields
+func ValidateLoadout(loadout *Loadout) error {
+	if loadout.ID == "" {
+		return fmt.Errorf("loadout missing ID")
+	}
+	if loadout.Name == "" {
+		return fmt.Errorf("loadout '%s' missing name", loadout.ID)
+	}
+	if loadout.Stats.Health <= 0 {
+		return fmt.Errorf("loadout '%s' must have positive health", loadout.ID)
+	}
+	return nil
+}
+
+// LoadLoadoutFromFile loads a single loadout from a JSON file
+func (m *EntityTemplateManager) LoadLoadoutFromFile(filePath string) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var loadout Loadout
+	if err := json.Unmarshal(data, &loadout); err != nil {
+		return err
+	}
+
+	// Validate required fields
+	if err := ValidateLoadout(&loadout); err != nil {
+		return fmt.Errorf("invalid loadout in %s: %w", filePath, err)
+	}
+	for _, item := range loadout.ChestItems {
+		if _, exists := m.GetItemTemplate(item.TemplateID); !exists {
+			return fmt.Errorf("invalid loadout in %s: chest item '%s' has no item template", filePath, item.TemplateID)
+		}
+	}
+
+	m.Loadouts[loadout.ID] = &loadout
+	return nil
+}
+
+// LoadLoadoutsFromDirectory loads all JSON loadout files from a directory
+func (m *EntityTemplateManager) LoadLoadoutsFromDirectory(dirPath string) error {
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return fmt.Errorf("failed to read loadout directory: %w", err)
+	}
+
+	for _, file := range files {
+		if filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		fullPath := filepath.Join(dirPath, file.Name())
+		if err := m.LoadLoadoutFromFile(fullPath); err != nil {
+			return fmt.Errorf("failed to load loadout from %s: %w", file.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// GetLoadout returns a loadout by ID
+func (m *EntityTemplateManager) GetLoadout(id string) (*Loadout, bool) {
+	loadout, ok := m.Loadouts[id]
+	return loadout, ok
+}
+
+// GetLoadouts returns all loadouts in selection order
+func (m *EntityTemplateManager) GetLoadouts() []*Loadout {
+	loadouts := make([]*Loadout, 0, len(m.Loadouts))
+	for _, loadout := range m.Loadouts {
+		loadouts = append(loadouts, loadout)
+	}
+	sort.Slice(loadouts, func(i, j int) bool {
+		if loadouts[i].Order != loadouts[j].Order {
+			return loadouts[i].Order < loadouts[j].Order
+		}
+		return loadouts[i].ID < loadouts[j].ID
+	})
+	return loadouts
+}
@@ -0,0 +1,118 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+// RecipeIngredient is an item stack consumed by a recipe
+type RecipeIngredient struct {
+	TemplateID string `json:"template_id"` // Item template ID
+	Count      int    `json:"count"`       // Number of items needed, defaults to 1
+}
+
+// Recipe describes an item that can be crafted at a station from other items
+type Recipe struct {
+	ID          string             `json:"id"`           // Unique identifier
+	Name        string             `json:"name"`         // Display name, defaults to the output item's name
+	Description string             `json:"description"`  // Description text
+	Order       int                `json:"order"`        // Position in the crafting list
+	Inputs      []RecipeIngredient `json:"inputs"`       // Items consumed by crafting
+	Output      string             `json:"output"`       // Item template ID produced
+	OutputCount int                `json:"output_count"` // Number of items produced, defaults to 1
+}
+
+// ValidateRecipe ensures that the recipe has all required fields
+func ValidateRecipe(recipe *Recipe) error {
+	if recipe.ID == "" {
+		return fmt.Errorf("recipe missing ID")
+	}
+	if recipe.Output == "" {
+		return fmt.Errorf("recipe '%s' missing output", recipe.ID)
+	}
+	if len(recipe.Inputs) == 0 {
+		return fmt.Errorf("recipe '%s' has no inputs", recipe.ID)
+	}
+	for _, input := range recipe.Inputs {
+		if input.TemplateID == "" {
+			return fmt.Errorf("recipe '%s' has an input without a template ID", recipe.ID)
+		}
+	}
+	return nil
+}
+
+// LoadRecipeFromFile loads a single recipe from a JSON file
+func (m *EntityTemplateManager) LoadRecipeFromFile(filePath string) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var recipe Recipe
+	if err := json.Unmarshal(data, &recipe); err != nil {
+		return err
+	}
+
+	// Validate required fields
+	if err := ValidateRecipe(&recipe); err != nil {
+		return fmt.Errorf("invalid recipe in %s: %w", filePath, err)
+	}
+
+	// Fill in defaults
+	for i := range recipe.Inputs {
+		if recipe.Inputs[i].Count <= 0 {
+			recipe.Inputs[i].Count = 1
+		}
+	}
+	if recipe.OutputCount <= 0 {
+		recipe.OutputCount = 1
+	}
+
+	m.Recipes[recipe.ID] = &recipe
+	return nil
+}
+
+// LoadRecipesFromDirectory loads all JSON recipe files from a directory
+func (m *EntityTemplateManager) LoadRecipesFromDirectory(dirPath string) error {
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return fmt.Errorf("failed to read recipe directory: %w", err)
+	}
+
+	for _, file := range files {
+		if filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		fullPath := filepath.Join(dirPath, file.Name())
+		if err := m.LoadRecipeFromFile(fullPath); err != nil {
+			return fmt.Errorf("failed to load recipe from %s: %w", file.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// GetRecipe returns a recipe by ID
+func (m *EntityTemplateManager) GetRecipe(id string) (*Recipe, bool) {
+	recipe, ok := m.Recipes[id]
+	return recipe, ok
+}
+
+// GetRecipes returns all recipes in list order
+func (m *EntityTemplateManager) GetRecipes() []*Recipe {
+	recipes := make([]*Recipe, 0, len(m.Recipes))
+	for _, recipe := range m.Recipes {
+		recipes = append(recipes, recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		if recipes[i].Order != recipes[j].Order {
+			return recipes[i].Order < recipes[j].Order
+		}
+		return recipes[i].ID < recipes[j].ID
+	})
+	return recipes
+}
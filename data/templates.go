@@ -26,34 +26,76 @@ type EntityTemplate struct {
 	Defense         int `json:"defense"`
 	Level           int `json:"level"`
 	XP              int `json:"xp"`              // XP awarded when killed
+	Threat          int `json:"threat"`          // Threat rating spent from a room's encounter budget
 	Recovery        int `json:"recovery"`        // Recovery points for action point regeneration
 	ActionPoints    int `json:"actionPoints"`    // Action points for the entity
 	MaxActionPoints int `json:"maxActionPoints"` // Maximum action points
 	HealingFactor   int `json:"healingFactor"`   // Healing factor for health regeneration
+	Evasion         int `json:"evasion"`         // Bonus to dodging attacks
+	Accuracy        int `json:"accuracy"`        // Bonus to landing attacks
 
 	// Behavior
 	AIType      string   `json:"aiType"`      // Type of AI behavior
+	SightRange  int      `json:"sightRange"`  // How far it can spot the player in full light, 0 for the default
 	Tags        []string `json:"tags"`        // Tags for categorization (e.g. "enemy", "npc", "boss")
 	BlocksPath  bool     `json:"blocksPath"`  // Whether it blocks movement
 	SpawnWeight int      `json:"spawnWeight"` // Relative chance of spawning (higher = more common)
 
+	// Packs: monsters with a pack size spawn in groups that share morale
+	Pack struct {
+		Min    int    `json:"min"`    // Fewest members, leader included
+		Max    int    `json:"max"`    // Most members, leader included
+		Leader string `json:"leader"` // Template of the pack leader, a member of the same kind if empty
+	} `json:"pack"`
+	Leadership int `json:"leadership"` // Morale the monster lends a pack it leads
+
+	// Defenses
+	Resistances map[string]float64 `json:"resistances"` // Multiplier per damage or status school, 0 for immunity
+
+	// Effects the monster carries from the start, such as a conditional enrage
+	Effects []struct {
+		Type      string      `json:"type"`
+		Operation string      `json:"operation"`
+		Value     interface{} `json:"value"`
+		Duration  int         `json:"duration"`
+		School    string      `json:"school"`
+		Condition struct {
+			HealthBelow float64 `json:"health_below"` // Fraction of max health the effect switches on below
+		} `json:"condition"`
+		Message string `json:"message"` // Logged after the monster's name when a conditional effect takes hold
+		Target  struct {
+			Component string `json:"component"`
+			Property  string `json:"property"`
+		} `json:"target"`
+	} `json:"effects"`
+
 	// Components
 	Components struct {
 		MonsterAbility struct {
 			Abilities []struct {
-				Name        string `json:"name"`
-				Description string `json:"description"`
-				Type        string `json:"type"`
-				Cooldown    int    `json:"cooldown"`
-				CurrentCD   int    `json:"currentCD"`
-				Range       int    `json:"range"`
-				Cost        int    `json:"cost"`
-				Trigger     string `json:"trigger"`
+				Name        string   `json:"name"`
+				Description string   `json:"description"`
+				Type        string   `json:"type"`
+				Cooldown    int      `json:"cooldown"`
+				CurrentCD   int      `json:"currentCD"`
+				Range       int      `json:"range"`
+				Cost        int      `json:"cost"`
+				Trigger     string   `json:"trigger"`
+				Action      string   `json:"action"`   // Extra behaviour such as "split", empty for effects only
+				Chance      float64  `json:"chance"`   // Chance the ability fires when triggered, 0 for always
+				Summons     []string `json:"summons"`  // Templates a call for help brings in, the monster's own when empty
+				Count       int      `json:"count"`    // Monsters each call brings in
+				MaxUses     int      `json:"max_uses"` // Times the ability can be used, 0 for no limit
+				Area        string   `json:"area"`     // Shape a charged ability hits: burst or line
+				Radius      int      `json:"radius"`   // Size of a charged burst
+				Duration    int      `json:"duration"` // Turns a phase lasts
 				Effects     []struct {
 					Type      string      `json:"type"`
 					Operation string      `json:"operation"`
 					Value     interface{} `json:"value"` // Can be float64 or string for dice roll notation
 					Duration  int         `json:"duration"`
+					School    string      `json:"school"`   // Damage or status school, checked against resistances
+					Stacking  string      `json:"stacking"` // stack, refresh, ignore or strongest, empty for the type's default
 					Target    struct {
 						Component string `json:"component"`
 						Property  string `json:"property"`
@@ -69,6 +111,8 @@ type EntityTemplateManager struct {
 	Templates          map[string]*EntityTemplate
 	ItemTemplates      map[string]*ItemTemplate
 	ContainerTemplates map[string]*ContainerTemplate
+	Loadouts           map[string]*Loadout
+	Recipes            map[string]*Recipe
 }
 
 // NewEntityTemplateManager creates a new template manager
@@ -77,6 +121,8 @@ func NewEntityTemplateManager() *EntityTemplateManager {
 		Templates:          make(map[string]*EntityTemplate),
 		ItemTemplates:      make(map[string]*ItemTemplate),
 		ContainerTemplates: make(map[string]*ContainerTemplate),
+		Loadouts:           make(map[string]*Loadout),
+		Recipes:            make(map[string]*Recipe),
 	}
 }
 
@@ -139,6 +185,14 @@ func (m *EntityTemplateManager) LoadTemplateFromFile(filePath string) error {
 		return fmt.Errorf("template ID cannot be empty: %s", filePath)
 	}
 
+	// Templates without a threat rating are rated by their level
+	if template.Threat <= 0 {
+		template.Threat = template.Level
+		if template.Threat < 1 {
+			template.Threat = 1
+		}
+	}
+
 	// Add to templates map
 	m.Templates[template.ID] = &template
 	return nil
@@ -197,18 +251,36 @@ func ParseHexColor(hex string) (c color.RGBA) {
 
 // ItemTemplate defines a template for creating items
 type ItemTemplate struct {
-	ID          string                   `json:"id"`          // Unique identifier for the item type
-	Name        string                   `json:"name"`        // Display name
-	Description string                   `json:"description"` // Item description
-	ItemType    string                   `json:"item_type"`   // Type of item: "weapon", "armor", "potion", etc.
-	TileX       int                      `json:"tile_x"`      // X position in the tileset
-	TileY       int                      `json:"tile_y"`      // Y position in the tileset
-	Color       string                   `json:"color"`       // Item color in hex format
-	Value       int                      `json:"value"`       // Base value/power of the item
-	Weight      int                      `json:"weight"`      // Weight of the item
-	Tags        []string                 `json:"tags"`        // Additional tags for the item
-	EquipSlot   string                   `json:"equip_slot"`  // Optional slot for equippable items
-	Effects     []map[string]interface{} `json:"effects"`     // Optional effects when equipped
+	ID          string                   `json:"id"`           // Unique identifier for the item type
+	Name        string                   `json:"name"`         // Display name
+	Description string                   `json:"description"`  // Item description
+	ItemType    string                   `json:"item_type"`    // Type of item: "weapon", "armor", "potion", etc.
+	TileX       int                      `json:"tile_x"`       // X position in the tileset
+	TileY       int                      `json:"tile_y"`       // Y position in the tileset
+	Color       string                   `json:"color"`        // Item color in hex format
+	Value       int                      `json:"value"`        // Base value/power of the item
+	Weight      int                      `json:"weight"`       // Weight of the item
+	Tags        []string                 `json:"tags"`         // Additional tags for the item
+	EquipSlot   string                   `json:"equip_slot"`   // Optional slot for equippable items
+	Effects     []map[string]interface{} `json:"effects"`      // Optional effects when equipped
+	Fuse        int                      `json:"fuse"`         // Turns until detonation once armed, makes the item a bomb
+	BlastRadius int                      `json:"blast_radius"` // Radius of the explosion, or of an area scroll, in tiles
+	Sockets     int                      `json:"sockets"`      // Number of gem sockets on equipment
+	Rarity      string                   `json:"rarity"`       // Rarity tier, defaults to "common"
+	TwoHanded   bool                     `json:"two_handed"`   // Weapon takes both hands, leaving no room for a shield or second weapon
+	Scroll      string                   `json:"scroll"`       // What reading the scroll does: "magic_mapping", "teleport", "area", "scan", "chain", "freeze" or "cool"
+
+	// Chain scrolls
+	ChainJumps   int     `json:"chain_jumps"`   // Monsters struck after the first
+	ChainRange   int     `json:"chain_range"`   // Furthest a jump reaches in tiles
+	ChainFalloff float64 `json:"chain_falloff"` // Share of the strength lost at each jump, 0 to 1
+
+	// Terrain scrolls
+	TerrainDuration int `json:"terrain_duration"` // Turns until transformed tiles revert, 0 for good
+
+	// Torches
+	BurnTurns  int `json:"burn_turns"`  // Turns a torch burns once lit, makes the item a torch
+	LightRange int `json:"light_range"` // Radius of the light a lit torch casts in tiles
 }
 
 // ValidateItemTemplate ensures that the item template has all required fields
@@ -65,3 +65,35 @@ func (em *EventManager) Emit(event Event) {
 		handler(event)
 	}
 }
+
+// EventEntityRemoved is the type of the event World.RemoveEntity emits
+const EventEntityRemoved EventType = "entity_removed"
+
+// EntityRemovedEvent is emitted once an entity has been removed from the world. Its
+// components are already gone, so listeners only get the ID and the tags it had.
+type EntityRemovedEvent struct {
+	EntityID EntityID
+	Tags     []string
+}
+
+// Type returns the event type
+func (e EntityRemovedEvent) Type() EventType {
+	return EventEntityRemoved
+}
+
+// EventComponentRemoved is the type of the event World.RemoveComponent emits
+const EventComponentRemoved EventType = "component_removed"
+
+// ComponentRemovedEvent is emitted once a component has been detached from an entity
+// that stays in the world. Removing the whole entity emits an EntityRemovedEvent
+// instead.
+type ComponentRemovedEvent struct {
+	EntityID    EntityID
+	ComponentID ComponentID
+	Component   Component // The component that was detached
+}
+
+// Type returns the event type
+func (e ComponentRemovedEvent) Type() EventType {
+	return EventComponentRemoved
+}
@@ -0,0 +1,102 @@
+package ecs
+
+// Query selects the entities that have every one of a set of components. It walks the
+// index of the rarest of those components, so asking for something few entities have
+// is cheap however many entities the world holds.
+type Query struct {
+	world        *World
+	componentIDs []ComponentID
+}
+
+// Query returns a query for the entities that have all of the given components. With
+// no components it matches every entity.
+func (w *World) Query(componentIDs ...ComponentID) Query {
+	return Query{world: w, componentIDs: componentIDs}
+}
+
+// Each calls fn for every matching entity, in no particular order, without building a
+// slice. fn may remove entities, and removed ones aren't visited; entities added during
+// the walk may or may not be.
+func (q Query) Each(fn func(entity *Entity)) {
+	if len(q.componentIDs) == 0 {
+		for _, entity := range q.world.entities {
+			fn(entity)
+		}
+		return
+	}
+
+	for entityID := range q.world.smallestIndex(q.componentIDs) {
+		if !q.matches(entityID) {
+			continue
+		}
+		if entity, ok := q.world.entities[entityID]; ok {
+			fn(entity)
+		}
+	}
+}
+
+// Entities returns the matching entities, in no particular order
+func (q Query) Entities() []*Entity {
+	capacity := len(q.world.entities)
+	if len(q.componentIDs) > 0 {
+		capacity = len(q.world.smallestIndex(q.componentIDs))
+	}
+	entities := make([]*Entity, 0, capacity)
+	q.Each(func(entity *Entity) {
+		entities = append(entities, entity)
+	})
+	return entities
+}
+
+// Count returns how many entities match
+func (q Query) Count() int {
+	count := 0
+	q.Each(func(*Entity) {
+		count++
+	})
+	return count
+}
+
+// matches returns true if the entity has every component of the query
+func (q Query) matches(entityID EntityID) bool {
+	componentMap := q.world.components[entityID]
+	for _, componentID := range q.componentIDs {
+		if _, exists := componentMap[componentID]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// smallestIndex returns the index of whichever of the components the fewest entities
+// have, or nil if one of them isn't on any entity
+func (w *World) smallestIndex(componentIDs []ComponentID) map[EntityID]struct{} {
+	var smallest map[EntityID]struct{}
+	for i, componentID := range componentIDs {
+		index := w.componentIndex[componentID]
+		if len(index) == 0 {
+			return nil
+		}
+		if i == 0 || len(index) < len(smallest) {
+			smallest = index
+		}
+	}
+	return smallest
+}
+
+// indexComponent records that an entity has a component
+func (w *World) indexComponent(entityID EntityID, componentID ComponentID) {
+	index, exists := w.componentIndex[componentID]
+	if !exists {
+		index = make(map[EntityID]struct{})
+		w.componentIndex[componentID] = index
+	}
+	index[entityID] = struct{}{}
+}
+
+// unindexComponent forgets that an entity has a component
+func (w *World) unindexComponent(entityID EntityID, componentID ComponentID) {
+	if index, exists := w.componentIndex[componentID]; exists {
+		delete(index, entityID)
+	}
+}
@@ -0,0 +1,119 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+const testSpeed ComponentID = testHealth + 1
+
+// entityIDs returns the IDs of entities in ascending order
+func entityIDs(entities []*Entity) []EntityID {
+	ids := make([]EntityID, len(entities))
+	for i, entity := range entities {
+		ids[i] = entity.ID
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestQuery(t *testing.T) {
+	world := NewWorld()
+	both := world.CreateEntity()
+	positionOnly := world.CreateEntity()
+	healthOnly := world.CreateEntity()
+	bare := world.CreateEntity()
+	world.AddComponent(both.ID, testPosition, "here")
+	world.AddComponent(both.ID, testHealth, 10)
+	world.AddComponent(positionOnly.ID, testPosition, "there")
+	world.AddComponent(healthOnly.ID, testHealth, 5)
+
+	tests := []struct {
+		name       string
+		components []ComponentID
+		want       []EntityID
+	}{
+		{"one component", []ComponentID{testPosition}, []EntityID{both.ID, positionOnly.ID}},
+		{"two components", []ComponentID{testPosition, testHealth}, []EntityID{both.ID}},
+		{"order doesn't matter", []ComponentID{testHealth, testPosition}, []EntityID{both.ID}},
+		{"a component nobody has", []ComponentID{testPosition, testSpeed}, []EntityID{}},
+		{"no components", nil, []EntityID{both.ID, positionOnly.ID, healthOnly.ID, bare.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := world.Query(tt.components...)
+			if got := entityIDs(query.Entities()); !slices.Equal(got, tt.want) {
+				t.Errorf("Entities() = %v, want %v", got, tt.want)
+			}
+			if got := query.Count(); got != len(tt.want) {
+				t.Errorf("Count() = %d, want %d", got, len(tt.want))
+			}
+			var visited []*Entity
+			query.Each(func(entity *Entity) {
+				visited = append(visited, entity)
+			})
+			if got := entityIDs(visited); !slices.Equal(got, tt.want) {
+				t.Errorf("Each visited %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryEachRemovingEntities(t *testing.T) {
+	world := NewWorld()
+	for range 10 {
+		entity := world.CreateEntity()
+		world.AddComponent(entity.ID, testHealth, 1)
+	}
+
+	// Whichever entity comes first removes all the others, so none of them are visited
+	visited := 0
+	world.Query(testHealth).Each(func(entity *Entity) {
+		visited++
+		for _, other := range world.Query(testHealth).Entities() {
+			if other.ID != entity.ID {
+				world.RemoveEntity(other.ID)
+			}
+		}
+	})
+	if visited != 1 {
+		t.Errorf("Each visited %d entities after the first removed the rest, want 1", visited)
+	}
+
+	// An entity may remove itself as it's visited
+	for range 5 {
+		entity := world.CreateEntity()
+		world.AddComponent(entity.ID, testHealth, 1)
+	}
+	visited = 0
+	world.Query(testHealth).Each(func(entity *Entity) {
+		visited++
+		world.RemoveEntity(entity.ID)
+	})
+	if visited != 6 {
+		t.Errorf("Each visited %d entities that removed themselves, want 6", visited)
+	}
+	if count := world.Query(testHealth).Count(); count != 0 {
+		t.Errorf("%d entities left after each removed itself, want 0", count)
+	}
+}
+
+// BenchmarkQueryRareComponent asks for a component few of many entities have, which
+// should cost the few rather than the many
+func BenchmarkQueryRareComponent(b *testing.B) {
+	world := NewWorld()
+	for i := range 10000 {
+		entity := world.CreateEntity()
+		world.AddComponent(entity.ID, testPosition, i)
+		if i%100 == 0 {
+			world.AddComponent(entity.ID, testSpeed, i)
+		}
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		if count := world.Query(testPosition, testSpeed).Count(); count != 100 {
+			b.Fatalf("Count() = %d, want 100", count)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package ecs
 
+import "sort"
+
 // GenericEventListener is a function that handles all types of events
 type GenericEventListener func(*World, interface{})
 
@@ -8,6 +10,8 @@ type World struct {
 	entities map[EntityID]*Entity
 	// Store components as map[EntityID]map[ComponentID]Component
 	components map[EntityID]ComponentMap
+	// Entities that have each component, for queries
+	componentIndex map[ComponentID]map[EntityID]struct{}
 	// Systems slice to store all systems
 	systems []System
 	// Tag-based entity lookup for quick access
@@ -23,6 +27,7 @@ func NewWorld() *World {
 	return &World{
 		entities:         make(map[EntityID]*Entity),
 		components:       make(map[EntityID]ComponentMap),
+		componentIndex:   make(map[ComponentID]map[EntityID]struct{}),
 		systems:          make([]System, 0),
 		entityTags:       make(map[string]map[EntityID]bool),
 		eventManager:     NewEventManager(),
@@ -38,21 +43,33 @@ func (w *World) CreateEntity() *Entity {
 	return entity
 }
 
-// RemoveEntity removes an entity and all its components from the world
+// RemoveEntity removes an entity and all its components from the world, then emits an
+// EntityRemovedEvent so systems can drop whatever they remember about it
 func (w *World) RemoveEntity(entityID EntityID) {
-	if entity, exists := w.entities[entityID]; exists {
-		// Remove entity from tag lookups
-		for tag := range entity.Tags {
-			delete(w.entityTags[tag], entityID)
-			if len(w.entityTags[tag]) == 0 {
-				delete(w.entityTags, tag)
-			}
+	entity, exists := w.entities[entityID]
+	if !exists {
+		return
+	}
+
+	// Remove entity from tag lookups
+	tags := make([]string, 0, len(entity.Tags))
+	for tag := range entity.Tags {
+		tags = append(tags, tag)
+		delete(w.entityTags[tag], entityID)
+		if len(w.entityTags[tag]) == 0 {
+			delete(w.entityTags, tag)
 		}
+	}
+	sort.Strings(tags)
 
-		// Remove components and entity
-		delete(w.components, entityID)
-		delete(w.entities, entityID)
+	// Remove components and entity
+	for componentID := range w.components[entityID] {
+		w.unindexComponent(entityID, componentID)
 	}
+	delete(w.components, entityID)
+	delete(w.entities, entityID)
+
+	w.EmitEvent(EntityRemovedEvent{EntityID: entityID, Tags: tags})
 }
 
 // AddComponent adds a component to an entity
@@ -66,6 +83,7 @@ func (w *World) AddComponent(entityID EntityID, componentID ComponentID, compone
 	}
 
 	w.components[entityID][componentID] = component
+	w.indexComponent(entityID, componentID)
 }
 
 // GetComponent retrieves a component from an entity
@@ -86,11 +104,22 @@ func (w *World) HasComponent(entityID EntityID, componentID ComponentID) bool {
 	return false
 }
 
-// RemoveComponent removes a component from an entity
+// RemoveComponent detaches a component from an entity and drops it from the query
+// index, then emits a ComponentRemovedEvent. Removing a component the entity doesn't
+// have does nothing.
 func (w *World) RemoveComponent(entityID EntityID, componentID ComponentID) {
-	if componentMap, exists := w.components[entityID]; exists {
-		delete(componentMap, componentID)
+	componentMap, exists := w.components[entityID]
+	if !exists {
+		return
 	}
+	component, exists := componentMap[componentID]
+	if !exists {
+		return
+	}
+	delete(componentMap, componentID)
+	w.unindexComponent(entityID, componentID)
+
+	w.EmitEvent(ComponentRemovedEvent{EntityID: entityID, ComponentID: componentID, Component: component})
 }
 
 // AddSystem adds a system to the world
@@ -186,15 +215,5 @@ func (w *World) GetEntity(entityID EntityID) *Entity {
 
 // GetEntitiesWithComponent returns all entities that have a specific component
 func (w *World) GetEntitiesWithComponent(componentID ComponentID) []*Entity {
-	entities := make([]*Entity, 0)
-
-	for id, componentMap := range w.components {
-		if _, hasComponent := componentMap[componentID]; hasComponent {
-			if entity, ok := w.entities[id]; ok {
-				entities = append(entities, entity)
-			}
-		}
-	}
-
-	return entities
+	return w.Query(componentID).Entities()
 }
@@ -0,0 +1,125 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+const (
+	testPosition ComponentID = iota
+	testHealth
+)
+
+func TestRemoveComponent(t *testing.T) {
+	world := NewWorld()
+	entity := world.CreateEntity()
+	world.AddComponent(entity.ID, testPosition, "here")
+	world.AddComponent(entity.ID, testHealth, 10)
+
+	var removed []ComponentRemovedEvent
+	world.GetEventManager().Subscribe(EventComponentRemoved, func(event Event) {
+		removed = append(removed, event.(ComponentRemovedEvent))
+	})
+
+	world.RemoveComponent(entity.ID, testPosition)
+
+	if world.HasComponent(entity.ID, testPosition) {
+		t.Error("HasComponent is still true after RemoveComponent")
+	}
+	if _, exists := world.GetComponent(entity.ID, testPosition); exists {
+		t.Error("GetComponent still finds the removed component")
+	}
+	if !world.HasComponent(entity.ID, testHealth) {
+		t.Error("RemoveComponent took another component with it")
+	}
+	if world.GetEntity(entity.ID) == nil {
+		t.Error("RemoveComponent removed the entity")
+	}
+	if count := world.Query(testPosition).Count(); count != 0 {
+		t.Errorf("query for the removed component matched %d entities, want 0", count)
+	}
+	if count := world.Query(testHealth).Count(); count != 1 {
+		t.Errorf("query for the remaining component matched %d entities, want 1", count)
+	}
+
+	if len(removed) != 1 {
+		t.Fatalf("got %d ComponentRemovedEvents, want 1", len(removed))
+	}
+	if got := removed[0]; got.EntityID != entity.ID || got.ComponentID != testPosition || got.Component != "here" {
+		t.Errorf("ComponentRemovedEvent = %+v, want entity %d, component %d holding %q", got, entity.ID, testPosition, "here")
+	}
+
+	// Removing what isn't there is a no-op and tells nobody
+	world.RemoveComponent(entity.ID, testPosition)
+	world.RemoveComponent(EntityID(9999), testPosition)
+	if len(removed) != 1 {
+		t.Errorf("removing a missing component emitted %d more events", len(removed)-1)
+	}
+}
+
+func TestRemoveComponentThenAddAgain(t *testing.T) {
+	world := NewWorld()
+	entity := world.CreateEntity()
+	world.AddComponent(entity.ID, testPosition, "here")
+	world.RemoveComponent(entity.ID, testPosition)
+	world.AddComponent(entity.ID, testPosition, "there")
+
+	component, exists := world.GetComponent(entity.ID, testPosition)
+	if !exists || component != "there" {
+		t.Errorf("GetComponent = %v, %v after adding the component back", component, exists)
+	}
+	if count := world.Query(testPosition).Count(); count != 1 {
+		t.Errorf("query matched %d entities after adding the component back, want 1", count)
+	}
+}
+
+func TestRemoveEntity(t *testing.T) {
+	world := NewWorld()
+	entity := world.CreateEntity()
+	other := world.CreateEntity()
+	world.AddComponent(entity.ID, testPosition, "here")
+	world.AddComponent(entity.ID, testHealth, 10)
+	world.AddComponent(other.ID, testHealth, 5)
+	world.TagEntity(entity.ID, "monster")
+	world.TagEntity(entity.ID, "ai")
+	world.TagEntity(other.ID, "monster")
+
+	var removed []EntityRemovedEvent
+	world.GetEventManager().Subscribe(EventEntityRemoved, func(event Event) {
+		removed = append(removed, event.(EntityRemovedEvent))
+	})
+
+	world.RemoveEntity(entity.ID)
+
+	if world.GetEntity(entity.ID) != nil {
+		t.Error("GetEntity still finds the removed entity")
+	}
+	if world.HasComponent(entity.ID, testPosition) || world.HasComponent(entity.ID, testHealth) {
+		t.Error("the removed entity still has components")
+	}
+	if count := world.Query(testPosition).Count(); count != 0 {
+		t.Errorf("query for a component only the removed entity had matched %d entities, want 0", count)
+	}
+	if entities := world.Query(testHealth).Entities(); len(entities) != 1 || entities[0].ID != other.ID {
+		t.Errorf("query for a shared component matched %v, want only the other entity", entities)
+	}
+	if entities := world.GetEntitiesWithTag("ai"); len(entities) != 0 {
+		t.Errorf("the ai tag still lists %d entities", len(entities))
+	}
+	if entities := world.GetEntitiesWithTag("monster"); len(entities) != 1 || entities[0].ID != other.ID {
+		t.Errorf("the monster tag lists %v, want only the other entity", entities)
+	}
+
+	if len(removed) != 1 {
+		t.Fatalf("got %d EntityRemovedEvents, want 1", len(removed))
+	}
+	if got := removed[0]; got.EntityID != entity.ID || !slices.Equal(got.Tags, []string{"ai", "monster"}) {
+		t.Errorf("EntityRemovedEvent = %+v, want entity %d with tags ai and monster", got, entity.ID)
+	}
+
+	// Removing it again tells nobody
+	world.RemoveEntity(entity.ID)
+	if len(removed) != 1 {
+		t.Errorf("removing a missing entity emitted %d more events", len(removed)-1)
+	}
+}
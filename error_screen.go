@@ -0,0 +1,88 @@
+package main
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	errorScreenWidth   = 640
+	errorScreenHeight  = 240
+	errorScreenColumns = 76 // Characters of the debug font that fit across the screen
+)
+
+// ErrorScreen implements ebiten.Game to tell the player why the game couldn't start,
+// in place of a crash
+type ErrorScreen struct {
+	lines []string
+}
+
+// NewErrorScreen creates a screen explaining a startup failure
+func NewErrorScreen(title string, err error) *ErrorScreen {
+	lines := []string{title, ""}
+	lines = append(lines, wrapText(err.Error(), errorScreenColumns)...)
+	lines = append(lines, "",
+		"Check that the game's files sit next to the program",
+		"and that the working directory is the game folder.",
+		"",
+		"Press Escape or Enter to quit")
+	return &ErrorScreen{lines: lines}
+}
+
+// Update quits once the player has read the message
+func (s *ErrorScreen) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+// Draw prints the message with the debug font, which needs no assets to load
+func (s *ErrorScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{40, 0, 0, 255})
+	ebitenutil.DebugPrintAt(screen, strings.Join(s.lines, "\n"), 10, 10)
+}
+
+// Layout keeps the screen small so the debug font stays readable when scaled up
+func (s *ErrorScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return errorScreenWidth, errorScreenHeight
+}
+
+// runErrorScreen shows a startup error in a window of its own until the player closes it
+func runErrorScreen(title string, err error) error {
+	ebiten.SetWindowSize(errorScreenWidth*2, errorScreenHeight*2)
+	ebiten.SetWindowTitle("Ebiten Roguelike - " + title)
+	return ebiten.RunGame(NewErrorScreen(title, err))
+}
+
+// wrapText breaks text into lines of at most width characters, at spaces where it can
+func wrapText(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for len(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		if line == "" {
+			line = word
+		} else if len(line)+1+len(word) <= width {
+			line += " " + word
+		} else {
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"ebiten-rogue/components"
 	"ebiten-rogue/config"
@@ -41,18 +41,44 @@ type Game struct {
 	containerSystem           *systems.ContainerSystem
 	deathSystem               *systems.DeathSystem
 	monsterAbilitySystem      *systems.MonsterAbilitySystem
+	autoExploreSystem         *systems.AutoExploreSystem
+	bestiarySystem            *systems.BestiarySystem
+	weatherSystem             *systems.WeatherSystem
+	mechanismSystem           *systems.MechanismSystem
+	bombSystem                *systems.BombSystem
+	torchSystem               *systems.TorchSystem
+	targetingSystem           *systems.TargetingSystem
+	craftingSystem            *systems.CraftingSystem
+	timeSystem                *systems.TimeSystem
+	graveyardSystem           *systems.GraveyardSystem
+	shrineSystem              *systems.ShrineSystem
+	tutorialSystem            *systems.TutorialSystem
+	scanSystem                *systems.ScanSystem
+	moraleSystem              *systems.MoraleSystem
+	companionSystem           *systems.CompanionSystem
+	floorClearSystem          *systems.FloorClearSystem
+	campSystem                *systems.CampSystem
+	healingBalanceSystem      *systems.HealingBalanceSystem
+	keybindings               *systems.Keybindings
+
+	seed      int64            // Master seed for the next run, 0 to pick one from the clock
+	practice  bool             // Whether the next run is in practice mode, where death isn't final
+	companion bool             // Whether new runs start with a companion at the player's side
+	rng       *systems.GameRNG // Random streams of the current run
 }
 
-// NewGame creates a new game instance
-func NewGame() *Game {
+// NewGame creates a new game instance. It fails if the game's assets can't be loaded.
+func NewGame() (*Game, error) {
 	// Initialize ECS world
 	world := ecs.NewWorld()
 
 	// Create systems
 	tileset, err := systems.NewTileset("Nice_curses_12x12.png", config.TileSize)
 	if err != nil {
-		panic(err)
-	} // Initialize all systems
+		return nil, err
+	}
+
+	// Initialize all systems
 	mapSystem := systems.NewMapSystem()
 	mapRegistrySystem := systems.NewMapRegistrySystem()
 	movementSystem := systems.NewMovementSystem()
@@ -69,6 +95,26 @@ func NewGame() *Game {
 	containerSystem := systems.NewContainerSystem(world)
 	deathSystem := systems.NewDeathSystem()
 	monsterAbilitySystem := systems.NewMonsterAbilitySystem()
+	autoExploreSystem := systems.NewAutoExploreSystem()
+	bestiarySystem := systems.NewBestiarySystem()
+	weatherSystem := systems.NewWeatherSystem()
+	timeSystem := systems.NewTimeSystem()
+	mechanismSystem := systems.NewMechanismSystem()
+	bombSystem := systems.NewBombSystem()
+	torchSystem := systems.NewTorchSystem()
+	targetingSystem := systems.NewTargetingSystem()
+	craftingSystem := systems.NewCraftingSystem()
+	graveyardSystem := systems.NewGraveyardSystem()
+	shrineSystem := systems.NewShrineSystem()
+	tutorialSystem := systems.NewTutorialSystem()
+	scanSystem := systems.NewScanSystem()
+	moraleSystem := systems.NewMoraleSystem()
+	companionSystem := systems.NewCompanionSystem()
+	floorClearSystem := systems.NewFloorClearSystem()
+	terrainSystem := systems.NewTerrainSystem()
+	campSystem := systems.NewCampSystem()
+	pointOfInterestSystem := systems.NewPointOfInterestSystem()
+	healingBalanceSystem := systems.NewHealingBalanceSystem()
 
 	// Initialize the entity template manager
 	templateManager := data.NewEntityTemplateManager()
@@ -91,12 +137,74 @@ func NewGame() *Game {
 		fmt.Printf("Warning: Failed to load container templates: %v\n", err)
 	}
 
+	// Load starting class loadouts
+	err = templateManager.LoadLoadoutsFromDirectory("data/loadouts")
+	if err != nil {
+		fmt.Printf("Warning: Failed to load loadouts: %v\n", err)
+	}
+
+	// Load crafting recipes
+	err = templateManager.LoadRecipesFromDirectory("data/recipes")
+	if err != nil {
+		fmt.Printf("Warning: Failed to load recipes: %v\n", err)
+	}
+
+	// The bestiary persists between runs
+	bestiarySystem.SetTemplateManager(templateManager)
+	combatSystem.SetTemplateManager(templateManager)
+	targetingSystem.SetTemplateManager(templateManager)
+	if err := bestiarySystem.LoadFromFile("bestiary.json"); err != nil {
+		fmt.Printf("Warning: Failed to load bestiary: %v\n", err)
+	}
+
+	// So do the player's keybindings, which the options screen changes
+	keybindings := systems.NewKeybindings()
+	if err := keybindings.LoadFromFile("keybindings.json"); err != nil {
+		fmt.Printf("Warning: Failed to load keybindings: %v\n", err)
+	}
+	playerTurnProcessorSystem.SetKeybindings(keybindings)
+	targetingSystem.SetKeybindings(keybindings)
+	autoExploreSystem.SetKeybindings(keybindings)
+	cameraSystem.SetKeybindings(keybindings)
+
 	// Create entity spawner
 	entitySpawner := spawners.NewEntitySpawner(world, templateManager, systems.GetMessageLog().Add)
 
+	// Monsters that split in combat or call for help spawn through the entity spawner
+	createEnemy := func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error) {
+		entitySpawner.SetSpawnMapID(mapID)
+		enemy, err := entitySpawner.CreateEnemy(x, y, templateID)
+		if err != nil {
+			return 0, err
+		}
+		return enemy.ID, nil
+	}
+	combatSystem.SetEnemyCreator(createEnemy)
+	monsterAbilitySystem.SetEnemyCreator(createEnemy)
+
 	// Create item spawner
 	itemSpawner := spawners.NewItemSpawner(world, templateManager)
 
+	// Broken crates and barrels drop their loot through the item spawner
+	combatSystem.SetItemCreator(func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error) {
+		itemSpawner.SetSpawnMapID(mapID)
+		item, err := itemSpawner.CreateItem(x, y, templateID, false)
+		if err != nil {
+			return 0, err
+		}
+		return item.ID, nil
+	})
+
+	// Crafted items go straight into the inventory
+	craftingSystem.SetTemplateManager(templateManager)
+	craftingSystem.SetItemCreator(func(templateID string) (ecs.EntityID, error) {
+		item, err := itemSpawner.CreateItem(0, 0, templateID, true)
+		if err != nil {
+			return 0, err
+		}
+		return item.ID, nil
+	})
+
 	// Create audio system first since it needs to be shared
 	audioSystem := systems.NewAudioSystem()
 
@@ -116,6 +224,26 @@ func NewGame() *Game {
 	world.AddSystem(containerSystem)
 	world.AddSystem(deathSystem)
 	world.AddSystem(monsterAbilitySystem)
+	world.AddSystem(autoExploreSystem)
+	world.AddSystem(bestiarySystem)
+	world.AddSystem(weatherSystem)
+	world.AddSystem(timeSystem)
+	world.AddSystem(mechanismSystem)
+	world.AddSystem(bombSystem)
+	world.AddSystem(torchSystem)
+	world.AddSystem(targetingSystem)
+	world.AddSystem(craftingSystem)
+	world.AddSystem(graveyardSystem)
+	world.AddSystem(shrineSystem)
+	world.AddSystem(tutorialSystem)
+	world.AddSystem(scanSystem)
+	world.AddSystem(moraleSystem)
+	world.AddSystem(companionSystem)
+	world.AddSystem(floorClearSystem)
+	world.AddSystem(terrainSystem)
+	world.AddSystem(campSystem)
+	world.AddSystem(pointOfInterestSystem)
+	world.AddSystem(healingBalanceSystem)
 	world.AddSystem(renderSystem) // Render system should be last to see all changes
 
 	// Create the game instance
@@ -142,6 +270,25 @@ func NewGame() *Game {
 		containerSystem:           containerSystem,
 		deathSystem:               deathSystem,
 		monsterAbilitySystem:      monsterAbilitySystem,
+		autoExploreSystem:         autoExploreSystem,
+		bestiarySystem:            bestiarySystem,
+		weatherSystem:             weatherSystem,
+		mechanismSystem:           mechanismSystem,
+		bombSystem:                bombSystem,
+		torchSystem:               torchSystem,
+		targetingSystem:           targetingSystem,
+		craftingSystem:            craftingSystem,
+		timeSystem:                timeSystem,
+		graveyardSystem:           graveyardSystem,
+		shrineSystem:              shrineSystem,
+		tutorialSystem:            tutorialSystem,
+		scanSystem:                scanSystem,
+		moraleSystem:              moraleSystem,
+		companionSystem:           companionSystem,
+		floorClearSystem:          floorClearSystem,
+		campSystem:                campSystem,
+		healingBalanceSystem:      healingBalanceSystem,
+		keybindings:               keybindings,
 	}
 
 	// Initialize event listeners
@@ -153,19 +300,48 @@ func NewGame() *Game {
 	inventorySystem.Initialize(world)
 	equipmentSystem.Initialize(world)
 	fovSystem.Initialize(world)
+	cameraSystem.Initialize(world)
+	targetingSystem.Initialize(world)
 	renderSystem.Initialize(world)
 	containerSystem.Initialize(world)
 	deathSystem.Initialize(world)
 	monsterAbilitySystem.Initialize(world)
+	bestiarySystem.Initialize(world)
+	weatherSystem.Initialize(world)
+	timeSystem.Initialize(world)
+	mechanismSystem.Initialize(world)
+	bombSystem.Initialize(world)
+	torchSystem.Initialize(world)
+	craftingSystem.Initialize(world)
+	graveyardSystem.Initialize(world)
+	shrineSystem.Initialize(world)
+	scanSystem.Initialize(world)
+	moraleSystem.Initialize(world)
+	companionSystem.Initialize(world)
+	floorClearSystem.Initialize(world)
+	terrainSystem.Initialize(world)
+	campSystem.Initialize(world)
+	pointOfInterestSystem.Initialize(world)
+	healingBalanceSystem.Initialize(world)
+	playerTurnProcessorSystem.Initialize(world)
+	audioSystem.Initialize(world)
 
 	// Push the start screen onto the stack
 	game.screenStack.Push(screens.NewStartScreen(audioSystem))
 
-	return game
+	return game, nil
 }
 
 // Update updates the game state.
 func (g *Game) Update() error {
+	// Sample the keys once, before anything asks about them
+	systems.BeginInputTick()
+
+	// Screens take no input while they crossfade
+	if g.screenStack.IsFading() {
+		return g.screenStack.Update()
+	}
+
 	// Get the current screen
 	currentScreen := g.screenStack.Peek()
 
@@ -176,56 +352,78 @@ func (g *Game) Update() error {
 		if err := screen.Update(); err != nil {
 			switch err {
 			case screens.ErrNewGame:
-				// Stop the background music
-				g.audioSystem.StopBGM()
-
-				// Initialize the game world
-				g.initialize()
-
-				// Create and push the game screen
-				gameScreen := screens.NewGameScreen(
-					g.world,
-					g.renderSystem,
-					g.mapSystem,
-					g.mapRegistrySystem,
-					g.movementSystem,
-					g.playerTurnProcessorSystem,
-					g.combatSystem,
-					g.cameraSystem,
-					g.aiPathfindingSystem,
-					g.aiTurnProcessorSystem,
-					g.effectsSystem,
-					g.inventorySystem,
-					g.equipmentSystem,
-					g.fovSystem,
-					g.containerSystem,
-					g.audioSystem,
-					g.deathSystem,
-				)
-
-				// Pop the start screen and push the game screen
-				g.screenStack.Pop()
-				g.screenStack.Push(gameScreen)
+				// Pick a class before the world is generated
+				classSelect := screens.NewClassSelectScreen(g.templateManager)
+				classSelect.SetAdaptiveHealing(g.healingBalanceSystem.IsEnabled())
+				g.screenStack.Push(classSelect)
+				return nil
 			case screens.ErrLoadGame:
 				// TODO: Implement load game functionality
 				systems.GetMessageLog().Add("Load game not implemented yet")
 			case screens.ErrOptions:
-				// TODO: Implement options screen
-				systems.GetMessageLog().Add("Options not implemented yet")
+				g.screenStack.Push(screens.NewOptionsScreen(g.keybindings))
+				return nil
 			case screens.ErrQuit:
 				return ebiten.Termination
 			}
 		}
+	case *screens.OptionsScreen:
+		if err := screen.Update(); err == screens.ErrCloseScreen {
+			// Back to the start menu
+			g.screenStack.Pop()
+		}
+		return nil
+	case *screens.ClassSelectScreen:
+		switch err := screen.Update(); err {
+		case screens.ErrLoadoutSelected:
+			// Stop the background music
+			g.audioSystem.StopBGM()
+
+			// Initialize the game world with the chosen class and mode
+			g.practice = screen.Practice()
+			g.healingBalanceSystem.SetEnabled(screen.AdaptiveHealing())
+			g.initialize(screen.Selected())
+
+			// Create and push the game screen
+			gameScreen := screens.NewGameScreen(
+				g.world,
+				g.renderSystem,
+				g.mapSystem,
+				g.mapRegistrySystem,
+				g.movementSystem,
+				g.playerTurnProcessorSystem,
+				g.combatSystem,
+				g.cameraSystem,
+				g.aiPathfindingSystem,
+				g.aiTurnProcessorSystem,
+				g.effectsSystem,
+				g.inventorySystem,
+				g.equipmentSystem,
+				g.fovSystem,
+				g.containerSystem,
+				g.audioSystem,
+				g.deathSystem,
+			)
+
+			// Pop the class and start screens and push the game screen
+			g.screenStack.Pop()
+			g.screenStack.Pop()
+			g.screenStack.Push(gameScreen)
+		case screens.ErrCloseScreen:
+			// Back to the start menu
+			g.screenStack.Pop()
+		}
+		return nil
 	case *screens.GameScreen:
 		// Check for game over event
 		g.world.GetEventManager().Subscribe(systems.EventGameOver, func(event ecs.Event) {
 			// Pop the game screen and push the game over screen
 			g.screenStack.Pop()
-			g.screenStack.Push(screens.NewGameOverScreen())
+			g.screenStack.Push(screens.NewGameOverScreen(g.world, g.renderSystem, g.deathSystem.LastDeath()))
 		})
 	case *screens.GameOverScreen:
 		// Return to start screen on Escape key
-		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		if systems.KeyJustPressed(ebiten.KeyEscape) {
 			// Stop any background music
 			g.audioSystem.StopBGM()
 
@@ -244,7 +442,7 @@ func (g *Game) Update() error {
 
 			// Reinitialize the game
 			systems.GetDebugLog().Add("Reinitializing game...")
-			g.initialize()
+			g.initialize(nil)
 			systems.GetDebugLog().Add("Game reinitialized")
 
 			// Pop the game over screen and push the start screen
@@ -269,8 +467,47 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return g.screenStack.Layout(outsideWidth, outsideHeight)
 }
 
-// initialize sets up the initial game state
-func (g *Game) initialize() {
+// SetSeed fixes the master seed runs are generated from, 0 for a new seed every run
+func (g *Game) SetSeed(seed int64) {
+	g.seed = seed
+}
+
+// RecordReplay records the session's keys and a checksum of every turn to a replay.
+// The seed must already be fixed with SetSeed for the replay to be played back.
+func (g *Game) RecordReplay(replay *systems.Replay) {
+	systems.RecordInput(replay)
+	g.checkReplayTurns()
+}
+
+// PlayReplay plays a recorded session back from its seed and keys, reporting the
+// first turn that doesn't come out the same as it was recorded
+func (g *Game) PlayReplay(replay *systems.Replay) {
+	g.seed = replay.Seed
+	systems.PlayInput(replay)
+	g.checkReplayTurns()
+}
+
+// checkReplayTurns hands the replay a checksum of the game after every turn
+func (g *Game) checkReplayTurns() {
+	g.world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		systems.CheckReplay(systems.ReplayChecksum(g.world, g.rng.State()))
+	})
+}
+
+// SetCompanion sets whether new runs start with a companion that follows the player
+func (g *Game) SetCompanion(enabled bool) {
+	g.companion = enabled
+}
+
+// RNGState returns the seed and stream positions of the current run, to be saved with
+// the turn count so a loaded game continues the same sequence of rolls
+func (g *Game) RNGState() systems.RNGState {
+	return g.rng.State()
+}
+
+// initialize sets up the initial game state. The loadout is the player's starting
+// class; nil gives the default setup.
+func (g *Game) initialize(loadout *data.Loadout) {
 	// Clear the world and map registry
 	systems.GetDebugLog().Add("Clearing world and map registry...")
 
@@ -286,16 +523,44 @@ func (g *Game) initialize() {
 	g.mapRegistrySystem.Clear()
 	systems.GetDebugLog().Add("World and map registry cleared")
 
+	// New maps reuse entity IDs, so every floor should play its reveal again
+	g.renderSystem.ResetMapReveal()
+	g.targetingSystem.Stop()
+	g.campSystem.Reset()
+	g.healingBalanceSystem.Reset()
+	g.combatSystem.Reset()
+
 	// Create the tile mapping entity
 	g.entitySpawner.CreateTileMapping()
 
 	// Initialize the map registry system
 	g.mapRegistrySystem.Initialize(g.world)
 
+	// Every random roll of the run comes from streams derived from one master seed,
+	// which is kept in the world state next to the turn count
+	seed := g.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g.rng = systems.NewGameRNG(seed)
+	worldState := components.NewWorldStateComponent()
+	worldState.Seed = seed
+	worldState.Practice = g.practice
+	worldStateEntity := g.world.CreateEntity()
+	g.world.TagEntity(worldStateEntity.ID, "world_state")
+	g.world.AddComponent(worldStateEntity.ID, components.WorldState, worldState)
+	systems.GetDebugLog().Add(fmt.Sprintf("Run seed: %d", seed))
+
 	// First, generate a world map
-	worldMapGenerator := generation.NewWorldMapGenerator(time.Now().UnixNano())
+	worldMapGenerator := generation.NewWorldMapGenerator(g.rng.StreamSeed(systems.RNGWorld))
 	worldMapEntity := worldMapGenerator.CreateWorldMapEntity(g.world, 200, 200)
 
+	g.weatherSystem.Reset()
+	g.weatherSystem.SetRNG(g.rng.Stream(systems.RNGWeather))
+	g.combatSystem.SetRNG(g.rng.Stream(systems.RNGCombat))
+	g.monsterAbilitySystem.SetRNG(g.rng.Stream(systems.RNGSummons))
+	systems.SetPlayRNG(g.rng.Stream(systems.RNGPlay))
+
 	// Make sure the world map is properly tagged
 	worldMapEntity.AddTag("map")
 	worldMapEntity.AddTag("worldmap")
@@ -320,11 +585,24 @@ func (g *Game) initialize() {
 		systems.GetMessageLog().Add,
 	)
 
+	dungeonThemer.SetRNG(g.rng.Stream(systems.RNGDungeon), g.rng.Stream(systems.RNGPopulation))
+
+	// Previous characters' graves only turn up when the graveyard is enabled
+	if g.graveyardSystem.IsEnabled() {
+		dungeonThemer.SetGraveyard(g.graveyardSystem)
+	}
+	if loadout != nil {
+		g.graveyardSystem.SetCharacterName(loadout.Name)
+	}
+
 	// Load themes from the data/themes directory
 	err := dungeonThemer.LoadThemesFromDirectory("data/themes")
 	if err != nil {
 		systems.GetMessageLog().Add(fmt.Sprintf("Error loading dungeon themes: %v", err))
 	}
+	if err := dungeonThemer.LoadPrefabsFromDirectory("data/prefabs"); err != nil {
+		systems.GetMessageLog().Add(fmt.Sprintf("Error loading prefabs: %v", err))
+	}
 
 	// Configure the dungeon (level 1, abandoned theme, large size)
 	config := generation.DungeonConfiguration{
@@ -359,6 +637,22 @@ func (g *Game) initialize() {
 		g.mapRegistrySystem.RegisterMap(floorEntity)
 	}
 
+	// Each biome hides a dungeon of its own kind somewhere on the surface
+	for _, floorEntity := range g.addBiomeDungeons(dungeonThemer, worldMapEntity) {
+		g.mapRegistrySystem.RegisterMap(floorEntity)
+	}
+
+	// Ruins, caches and lairs are scattered between them
+	for _, floorEntity := range dungeonThemer.AddPointsOfInterest(worldMapEntity, g.rng.Stream(systems.RNGPointsOfInterest)) {
+		g.mapRegistrySystem.RegisterMap(floorEntity)
+	}
+
+	// Every substation keeps a workbench outside, for crafting on the surface
+	dungeonThemer.AddSubstationWorkbenches(worldMapEntity, g.rng.Stream(systems.RNGPointsOfInterest))
+
+	// Name the surface's regions and stations once its tiles are settled
+	generation.NameWorldMap(g.world, worldMapEntity, g.rng.StreamSeed(systems.RNGNames))
+
 	// Get the first floor entity (where the player starts)
 	startingFloorEntity := dungeonFloors[0]
 
@@ -382,20 +676,41 @@ func (g *Game) initialize() {
 	playerX, playerY := g.mapSystem.FindEmptyPosition(mapComp)
 
 	// Create the player entity
-	playerEntity := g.entitySpawner.CreatePlayer(playerX, playerY)
+	playerEntity := g.entitySpawner.CreatePlayer(playerX, playerY, loadout)
 
 	// Add map context component to the player
 	g.world.AddComponent(playerEntity.ID, components.MapContextID,
 		components.NewMapContextComponent(startingFloorEntity.ID))
 
-	// Create starter chest next to player
-	chestX, chestY := playerX+1, playerY
-	g.itemSpawner.SetSpawnMapID(startingFloorEntity.ID)
-	g.itemSpawner.CreateContainer(chestX, chestY, "starter_chest")
+	// The starting theme decides whether a chest waits next to the player and what is
+	// in it, and the class adds its own supplies on top
+	if theme := dungeonThemer.GetTheme(config.ThemeID); theme != nil && theme.StartingChest != nil {
+		contents := slices.Clone(theme.StartingChest.Items)
+		if loadout != nil {
+			contents = append(contents, loadout.ChestItems...)
+		}
+		g.itemSpawner.SetSpawnMapID(startingFloorEntity.ID)
+		if _, err := g.itemSpawner.CreateContainer(playerX+1, playerY, theme.StartingChest.ContainerID(), contents); err != nil {
+			systems.GetDebugLog().Add(fmt.Sprintf("Failed to create starting chest: %v", err))
+		}
+	}
+
+	// The companion starts on a free tile near the player
+	if g.companion {
+		g.entitySpawner.SetSpawnMapID(startingFloorEntity.ID)
+		if x, y, found := systems.FindFreeTileNear(g.world, startingFloorEntity.ID, playerX, playerY, 3, playerEntity.ID); found {
+			g.entitySpawner.CreateCompanion(x, y, playerEntity.ID)
+		}
+	}
 
 	// Create a camera entity for the player
 	g.entitySpawner.CreateCamera(uint64(playerEntity.ID), playerX, playerY)
 
+	// The starting floor walks new players through the basics
+	if theme := dungeonThemer.GetTheme(config.ThemeID); theme != nil && g.tutorialSystem.IsEnabled() {
+		g.tutorialSystem.Start(startingFloorEntity.ID, theme.TutorialHints)
+	}
+
 	// Print a summary of all maps and their IDs
 	g.printMapSummary()
 
@@ -405,6 +720,52 @@ func (g *Game) initialize() {
 	systems.GetMessageLog().AddEnvironment("The chamber is dimly lit, and something scuttles in the dark")
 }
 
+// addBiomeDungeons places an entrance in the mountains, the dark forest and the desert,
+// each leading to a dungeon generated to suit its biome. Entrances further from the
+// central station lead to deeper dungeons. Returns the floors of every dungeon added.
+func (g *Game) addBiomeDungeons(themer *generation.DungeonThemer, worldMapEntity *ecs.Entity) []*ecs.Entity {
+	mapComp, exists := g.world.GetComponent(worldMapEntity.ID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	worldMap := mapComp.(*components.MapComponent)
+	centerX, centerY := worldMap.Width/2, worldMap.Height/2
+	rng := g.rng.Stream(systems.RNGDungeon)
+	minDistance := 15 // Keep entrances out of sight of the central station
+
+	var floors []*ecs.Entity
+	for _, biome := range []int{components.TileMountains, components.TileDarkForest, components.TileDesert} {
+		var candidates [][2]int
+		for y := 0; y < worldMap.Height; y++ {
+			for x := 0; x < worldMap.Width; x++ {
+				if worldMap.Tiles[y][x] != biome || max(abs(x-centerX), abs(y-centerY)) < minDistance {
+					continue
+				}
+				if _, isTransition := worldMap.GetTransition(x, y); !isTransition {
+					candidates = append(candidates, [2]int{x, y})
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			systems.GetDebugLog().Add(fmt.Sprintf("No room for a dungeon entrance in biome %d", biome))
+			continue
+		}
+
+		spot := candidates[rng.Intn(len(candidates))]
+		level := 1 + max(abs(spot[0]-centerX), abs(spot[1]-centerY))/50
+		floors = append(floors, themer.GenerateBiomeDungeon(worldMapEntity, spot[0], spot[1], level)...)
+	}
+	return floors
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // Flag to track if we need to redraw the screen
 var needsRedraw = true
 
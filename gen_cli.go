@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/generation"
+	"ebiten-rogue/spawners"
+	"ebiten-rogue/systems"
+)
+
+// generatorNames maps the -generator flag values to dungeon generators
+var generatorNames = map[string]generation.GeneratorType{
+	"bsp":      generation.GeneratorBSP,
+	"cellular": generation.GeneratorCellular,
+	"random":   generation.GeneratorRandom,
+	"hybrid":   generation.GeneratorHybrid,
+}
+
+// sizeNames maps the -size flag values to dungeon sizes
+var sizeNames = map[string]generation.DungeonSize{
+	"small":  generation.SizeSmall,
+	"normal": generation.SizeNormal,
+	"large":  generation.SizeLarge,
+	"huge":   generation.SizeHuge,
+}
+
+// genEntityTags are the entity kinds counted for each floor
+var genEntityTags = []string{"enemy", "item", "container", "crafting_station", "shrine", "lever"}
+
+// runGenerator generates a dungeon the way a new game does, without opening a window,
+// and writes every floor to out as ASCII followed by its stats. The same seed,
+// generator, size, theme and level always give the same output.
+func runGenerator(out io.Writer, seed int64, generatorName, sizeName, themeID string, level int) error {
+	generator, ok := generatorNames[generatorName]
+	if !ok {
+		return fmt.Errorf("unknown generator %q, expected bsp, cellular, random or hybrid", generatorName)
+	}
+	size, ok := sizeNames[sizeName]
+	if !ok {
+		return fmt.Errorf("unknown size %q, expected small, normal, large or huge", sizeName)
+	}
+
+	world := ecs.NewWorld()
+
+	// Floors are populated from the same templates the game uses
+	templateManager := data.NewEntityTemplateManager()
+	if err := templateManager.LoadTemplatesFromDirectory("data/monsters"); err != nil {
+		log.Printf("Warning: Failed to load monster templates: %v", err)
+	}
+	if err := templateManager.LoadItemTemplatesFromDirectory("data/items"); err != nil {
+		log.Printf("Warning: Failed to load item templates: %v", err)
+	}
+	if err := templateManager.LoadContainerTemplatesFromDirectory("data/containers"); err != nil {
+		log.Printf("Warning: Failed to load container templates: %v", err)
+	}
+
+	// Generation chatter would mix with the dump, so it's dropped
+	quiet := func(string) {}
+	entitySpawner := spawners.NewEntitySpawner(world, templateManager, quiet)
+	dungeonThemer := generation.NewDungeonThemer(world, templateManager, entitySpawner, quiet)
+
+	rng := systems.NewGameRNG(seed)
+	dungeonThemer.SetRNG(rng.Stream(systems.RNGDungeon), rng.Stream(systems.RNGPopulation))
+	if err := dungeonThemer.LoadThemesFromDirectory("data/themes"); err != nil {
+		return fmt.Errorf("loading themes: %v", err)
+	}
+	if err := dungeonThemer.LoadPrefabsFromDirectory("data/prefabs"); err != nil {
+		return fmt.Errorf("loading prefabs: %v", err)
+	}
+	if dungeonThemer.GetTheme(themeID) == nil {
+		return fmt.Errorf("unknown theme %q", themeID)
+	}
+
+	floors := dungeonThemer.GenerateThemedDungeon(generation.DungeonConfiguration{
+		Level:         level,
+		Size:          size,
+		Generator:     generator,
+		AddStairsUp:   true,
+		ThemeID:       themeID,
+		DensityFactor: 1.0,
+	})
+	if len(floors) == 0 {
+		return fmt.Errorf("no floors were generated")
+	}
+
+	fmt.Fprintf(out, "seed %d, generator %s, size %s, theme %s, level %d\n", seed, generatorName, sizeName, themeID, level)
+	for i, floor := range floors {
+		mapComp, exists := world.GetComponent(floor.ID, components.MapComponentID)
+		if !exists {
+			return fmt.Errorf("floor %d has no map", i+1)
+		}
+		fmt.Fprintf(out, "\nFloor %d\n", i+1)
+		writeFloorDump(out, mapComp.(*components.MapComponent))
+		writeFloorStats(out, world, floor.ID, mapComp.(*components.MapComponent))
+	}
+	return nil
+}
+
+// writeFloorDump writes the tile grid of a floor, one character per tile
+func writeFloorDump(out io.Writer, mapComp *components.MapComponent) {
+	line := make([]byte, mapComp.Width)
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			line[x] = byte(components.GetTileProperties(mapComp.Tiles[y][x]).Glyph)
+		}
+		fmt.Fprintf(out, "%s\n", line)
+	}
+}
+
+// writeFloorStats writes the room count, connectivity, tile features and entity counts
+// of a floor
+func writeFloorStats(out io.Writer, world *ecs.World, floorID ecs.EntityID, mapComp *components.MapComponent) {
+	fmt.Fprintf(out, "size: %dx%d\n", mapComp.Width, mapComp.Height)
+	fmt.Fprintf(out, "rooms: %d\n", countRooms(mapComp))
+
+	// Every open tile should be reachable from every other
+	open := func(tileType int) bool { return components.GetTileProperties(tileType).IsWalkable }
+	regions := generation.ConnectedRegions(mapComp, open)
+	total, largest := 0, 0
+	for _, region := range regions {
+		total += len(region)
+		largest = max(largest, len(region))
+	}
+	if len(regions) <= 1 {
+		fmt.Fprintf(out, "connectivity: connected (%d open tiles)\n", total)
+	} else {
+		fmt.Fprintf(out, "connectivity: %d regions, largest holds %d of %d open tiles\n", len(regions), largest, total)
+	}
+
+	// Tile features, in a fixed order so dumps diff cleanly
+	counts := make(map[byte]int)
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			if c := byte(components.GetTileProperties(mapComp.Tiles[y][x]).Glyph); c != '.' && c != '#' {
+				counts[c]++
+			}
+		}
+	}
+	features := []struct {
+		name string
+		char byte
+	}{
+		{"doors", '+'}, {"stairs down", '>'}, {"stairs up", '<'}, {"water", '~'},
+		{"lava", '='}, {"grass", '"'}, {"trees", 'T'},
+	}
+	var parts []string
+	for _, feature := range features {
+		if counts[feature.char] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", feature.name, counts[feature.char]))
+		}
+	}
+	fmt.Fprintf(out, "features: %s\n", joinOrNone(parts))
+
+	parts = nil
+	for _, tag := range genEntityTags {
+		count := 0
+		for _, entity := range world.GetEntitiesWithTag(tag) {
+			if mapContextComp, exists := world.GetComponent(entity.ID, components.MapContextID); exists &&
+				mapContextComp.(*components.MapContextComponent).MapID == floorID {
+				count++
+			}
+		}
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", tag, count))
+		}
+	}
+	fmt.Fprintf(out, "entities: %s\n", joinOrNone(parts))
+}
+
+// countRooms counts the open areas of a floor. Generators don't all keep track of
+// their rooms, so rooms are found from the tiles instead: a tile is inside a room
+// when it and its eight neighbours are all open, which leaves out narrow corridors,
+// and each connected patch of such tiles at least two wide and tall is one room.
+func countRooms(mapComp *components.MapComponent) int {
+	interior := components.NewMapComponent(mapComp.Width, mapComp.Height)
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			interior.Tiles[y][x] = components.TileWall
+			if x == 0 || y == 0 || x == mapComp.Width-1 || y == mapComp.Height-1 {
+				continue
+			}
+			inside := true
+			for dy := -1; dy <= 1 && inside; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					tile := mapComp.Tiles[y+dy][x+dx]
+					if generation.IsAnyWallType(tile) || tile == components.TileDoor {
+						inside = false
+						break
+					}
+				}
+			}
+			if inside {
+				interior.Tiles[y][x] = components.TileFloor
+			}
+		}
+	}
+
+	rooms := 0
+	for _, region := range generation.ConnectedRegions(interior, func(tileType int) bool { return tileType == components.TileFloor }) {
+		if bounds := region.Bounds(interior.Width); bounds.Width >= 2 && bounds.Height >= 2 {
+			rooms++
+		}
+	}
+	return rooms
+}
+
+// joinOrNone joins stat parts with commas, or gives "none" when there are none
+func joinOrNone(parts []string) string {
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,88 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// biomeDungeons maps world map biomes to the kind of dungeon found beneath them
+var biomeDungeons = map[int]DungeonConfiguration{
+	components.TileMountains:  {Size: SizeLarge, Generator: GeneratorBSP, ThemeID: "mountain_stronghold", CorridorWidth: 3, MixedCorridors: true},
+	components.TileDarkForest: {Size: SizeNormal, Generator: GeneratorCellular, ThemeID: "forest_caves"},
+	components.TileDesert:     {Size: SizeLarge, Generator: GeneratorRandom, ThemeID: "desert_ruins"},
+	components.TileWasteland:  {Size: SizeNormal, Generator: GeneratorBSP, ThemeID: "abandoned"},
+}
+
+// BiomeDungeonConfiguration returns the configuration for a dungeon beneath a world
+// map tile. The biome picks the size, generator and theme; deeper levels grow a size
+// every two levels and are more crowded.
+func (t *DungeonThemer) BiomeDungeonConfiguration(biome, level int) DungeonConfiguration {
+	config, exists := biomeDungeons[biome]
+	if !exists {
+		config = biomeDungeons[components.TileWasteland]
+	}
+	if level < 1 {
+		level = 1
+	}
+
+	config.Level = level
+	config.AddStairsUp = true
+	config.DensityFactor = 1.0 + 0.1*float64(level-1)
+	for step := 2; step <= level && config.Size < SizeHuge; step += 2 {
+		config.Size++
+	}
+	return config
+}
+
+// GenerateBiomeDungeon generates the dungeon beneath a world map tile and turns the
+// tile into its entrance. Returns the dungeon's floors, first floor first.
+func (t *DungeonThemer) GenerateBiomeDungeon(worldMapEntity *ecs.Entity, x, y, level int) []*ecs.Entity {
+	mapComp, exists := t.world.GetComponent(worldMapEntity.ID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	worldMap := mapComp.(*components.MapComponent)
+	if x < 0 || x >= worldMap.Width || y < 0 || y >= worldMap.Height {
+		return nil
+	}
+	return t.generateSurfaceDungeon(worldMapEntity, x, y, t.BiomeDungeonConfiguration(worldMap.Tiles[y][x], level))
+}
+
+// generateSurfaceDungeon generates a dungeon from a configuration and turns a world
+// map tile into its entrance. Returns the dungeon's floors, first floor first.
+func (t *DungeonThemer) generateSurfaceDungeon(worldMapEntity *ecs.Entity, x, y int, config DungeonConfiguration) []*ecs.Entity {
+	mapComp, exists := t.world.GetComponent(worldMapEntity.ID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	worldMap := mapComp.(*components.MapComponent)
+
+	config.SurfaceX, config.SurfaceY = x, y
+	floors := t.GenerateThemedDungeon(config)
+	if len(floors) == 0 {
+		return nil
+	}
+
+	// Link the entrance to the first floor's stairs back up
+	floorComp, exists := t.world.GetComponent(floors[0].ID, components.MapComponentID)
+	if !exists {
+		return floors
+	}
+	firstFloor := floorComp.(*components.MapComponent)
+	for stairsX, column := range firstFloor.Transitions {
+		for stairsY, transition := range column {
+			if transition.TargetMapID != worldMapEntity.ID {
+				continue
+			}
+			worldMap.SetTile(x, y, components.TileStairsDown)
+			worldMap.AddTransition(x, y, floors[0].ID, stairsX, stairsY, true)
+			if t.logMessage != nil {
+				t.logMessage(fmt.Sprintf("Added %s entrance at (%d,%d)", config.ThemeID, x, y))
+			}
+			return floors
+		}
+	}
+	return floors
+}
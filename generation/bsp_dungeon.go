@@ -2,6 +2,7 @@ package generation
 
 import (
 	"ebiten-rogue/components"
+	"ebiten-rogue/systems"
 	"fmt"
 )
 
@@ -31,7 +32,7 @@ func (node *BSPNode) CorridorEnd() []int {
 
 // GenerateBSPDungeon creates a dungeon using binary space partitioning
 func (g *DungeonGenerator) GenerateBSPDungeon(mapComp *components.MapComponent) {
-	fmt.Printf("DEBUG: Starting BSP dungeon generation for map %dx%d\n", mapComp.Width, mapComp.Height)
+	systems.GetDebugLog().Add(fmt.Sprintf("DEBUG: Starting BSP dungeon generation for map %dx%d", mapComp.Width, mapComp.Height))
 
 	// Fill the map with walls initially
 	for y := 0; y < mapComp.Height; y++ {
@@ -60,8 +61,8 @@ func (g *DungeonGenerator) GenerateBSPDungeon(mapComp *components.MapComponent)
 	countRooms = func(node *BSPNode) {
 		if node.Room != nil {
 			roomCount++
-			fmt.Printf("DEBUG: Found room at (%d,%d) size %dx%d\n",
-				node.Room.X, node.Room.Y, node.Room.Width, node.Room.Height)
+			systems.GetDebugLog().Add(fmt.Sprintf("DEBUG: Found room at (%d,%d) size %dx%d",
+				node.Room.X, node.Room.Y, node.Room.Width, node.Room.Height))
 		}
 		if node.Left != nil {
 			countRooms(node.Left)
@@ -71,7 +72,7 @@ func (g *DungeonGenerator) GenerateBSPDungeon(mapComp *components.MapComponent)
 		}
 	}
 	countRooms(root)
-	fmt.Printf("DEBUG: Created %d rooms\n", roomCount)
+	systems.GetDebugLog().Add(fmt.Sprintf("DEBUG: Created %d rooms", roomCount))
 
 	// Connect rooms together
 	g.connectRooms(root)
@@ -88,7 +89,11 @@ func (g *DungeonGenerator) GenerateBSPDungeon(mapComp *components.MapComponent)
 			}
 		}
 	}
-	fmt.Printf("DEBUG: Map has %d floor tiles\n", floorTiles)
+	systems.GetDebugLog().Add(fmt.Sprintf("DEBUG: Map has %d floor tiles", floorTiles))
+
+	// Keep the rooms for features that are fitted into them later
+	g.rooms = nil
+	g.collectRooms(root, &g.rooms)
 
 	// Verify room connectivity and fix orphaned rooms
 	g.ensureRoomConnectivity(root, mapComp)
@@ -314,31 +319,8 @@ func (g *DungeonGenerator) drawBSPDungeon(node *BSPNode, mapComp *components.Map
 		x1, y1 := node.CorridorStart()[0], node.CorridorStart()[1]
 		x2, y2 := node.CorridorEnd()[0], node.CorridorEnd()[1]
 
-		// Create corridors (either horizontal-then-vertical or vertical-then-horizontal)
-		if g.rng.Intn(2) == 0 {
-			// Horizontal then vertical
-			g.createHorizontalCorridor(mapComp, x1, x2, y1)
-			g.createVerticalCorridor(mapComp, y1, y2, x2)
-		} else {
-			// Vertical then horizontal
-			g.createVerticalCorridor(mapComp, y1, y2, x1)
-			g.createHorizontalCorridor(mapComp, x1, x2, y2)
-		}
-
-		// Add a door at one end of the corridor (20% chance)
-		if g.rng.Intn(100) < 20 {
-			doorX, doorY := x1, y1
-			if g.rng.Intn(2) == 0 {
-				doorX, doorY = x2, y2
-			}
-
-			// Place the door if it's within bounds and on a floor tile
-			if doorX >= 0 && doorX < mapComp.Width && doorY >= 0 && doorY < mapComp.Height {
-				if mapComp.Tiles[doorY][doorX] == components.TileFloor {
-					mapComp.SetTile(doorX, doorY, components.TileDoor)
-				}
-			}
-		}
+		// L-shaped corridor, sometimes with a door at one end
+		g.CreateCorridor(mapComp, x1, y1, x2, y2)
 	}
 
 	// Recursively draw children
@@ -414,6 +396,8 @@ func (g *DungeonGenerator) GenerateLargeBSPDungeon(mapComp *components.MapCompon
 		g.collectRoomsFromNode(section, &allRooms)
 	}
 
+	g.rooms = allRooms
+
 	// Connect adjacent macro sections
 	g.connectMacroSections(macroSections, mapComp)
 
@@ -652,31 +636,8 @@ func (g *DungeonGenerator) drawRooms(node *BSPNode, mapComp *components.MapCompo
 		x1, y1 := node.corridorStart[0], node.corridorStart[1]
 		x2, y2 := node.corridorEnd[0], node.corridorEnd[1]
 
-		// Use L-shaped corridors (more reliable than direct corridors)
-		// Randomly decide whether to go horizontal-then-vertical or vertical-then-horizontal
-		if g.rng.Intn(2) == 0 {
-			// Horizontal then vertical
-			g.createHorizontalCorridor(mapComp, x1, x2, y1)
-			g.createVerticalCorridor(mapComp, y1, y2, x2)
-		} else {
-			// Vertical then horizontal
-			g.createVerticalCorridor(mapComp, y1, y2, x1)
-			g.createHorizontalCorridor(mapComp, x1, x2, y2)
-		}
-
-		// Add doors occasionally (20% chance at corridor junctions)
-		if g.rng.Intn(100) < 20 {
-			doorX, doorY := x1, y1
-			if g.rng.Intn(2) == 0 {
-				doorX, doorY = x2, y2
-			}
-			// Make sure position is valid and is a corridor (floor)
-			if doorX >= 0 && doorX < mapComp.Width && doorY >= 0 && doorY < mapComp.Height {
-				if mapComp.Tiles[doorY][doorX] == components.TileFloor {
-					mapComp.SetTile(doorX, doorY, components.TileDoor)
-				}
-			}
-		}
+		// Use L-shaped corridors (more reliable than direct corridors), with occasional doors
+		g.CreateCorridor(mapComp, x1, y1, x2, y2)
 	}
 
 	// Recursively draw child nodes
@@ -737,8 +698,9 @@ func (g *DungeonGenerator) connectMacroSections(sections []*BSPNode, mapComp *co
 				y2 := room2.Y + room2.Height/2
 
 				// Use L-shaped corridor
-				g.createHorizontalCorridor(mapComp, x1, x2, y1)
-				g.createVerticalCorridor(mapComp, y1, y2, x2)
+				width := g.nextCorridorWidth()
+				g.createHorizontalCorridor(mapComp, x1, x2, y1, width)
+				g.createVerticalCorridor(mapComp, y1, y2, x2, width)
 			}
 		}
 
@@ -751,8 +713,9 @@ func (g *DungeonGenerator) connectMacroSections(sections []*BSPNode, mapComp *co
 				y2 := room2.Y + room2.Height/2
 
 				// Use L-shaped corridor
-				g.createVerticalCorridor(mapComp, y1, y2, x1)
-				g.createHorizontalCorridor(mapComp, x1, x2, y2)
+				width := g.nextCorridorWidth()
+				g.createVerticalCorridor(mapComp, y1, y2, x1, width)
+				g.createHorizontalCorridor(mapComp, x1, x2, y2, width)
 			}
 		}
 	}
@@ -802,39 +765,8 @@ func (g *DungeonGenerator) verifyGlobalConnectivity(mapComp *components.MapCompo
 
 // floodFillConnectivity marks all connected floor tiles as visited
 func (g *DungeonGenerator) floodFillConnectivity(mapComp *components.MapComponent, x, y int, visited [][]bool) {
-	// Simple BFS flood fill
-	queue := [][2]int{{x, y}}
-	visited[y][x] = true
-
-	// Four principal directions
-	dirs := [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
-
-	for len(queue) > 0 {
-		curr := queue[0]
-		queue = queue[1:]
-
-		for _, dir := range dirs {
-			nx, ny := curr[0]+dir[0], curr[1]+dir[1]
-
-			// Check bounds
-			if nx < 0 || nx >= mapComp.Width || ny < 0 || ny >= mapComp.Height {
-				continue
-			}
-
-			// Check if this is a walkable tile and not visited
-			if !visited[ny][nx] && isWalkable(mapComp.Tiles[ny][nx]) {
-				visited[ny][nx] = true
-				queue = append(queue, [2]int{nx, ny})
-			}
-		}
-	}
-}
-
-// isWalkable checks if a tile can be walked on
-func isWalkable(tileType int) bool {
-	return tileType == components.TileFloor ||
-		tileType == components.TileGrass ||
-		tileType == components.TileDoor
+	region, _ := FloodFill(mapComp, x, y, isWalkable)
+	markVisited(visited, region, mapComp.Width)
 }
 
 // connectToMainDungeon connects a disconnected region to the main dungeon
@@ -921,18 +853,18 @@ func (g *DungeonGenerator) calculateWallConnectionMask(mapComp *components.MapCo
 	mask := 0
 
 	// Check in which directions this wall connects to other walls
-	// We're treating out-of-bounds as wall tiles
+	// Out-of-bounds counts as wall unless edge walls are turned off
 	// We also treat ALL wall types as connected walls
-	if y == 0 || (y > 0 && IsAnyWallType(mapComp.Tiles[y-1][x])) {
+	if connectsTo(mapComp, x, y-1, IsAnyWallType) {
 		mask |= WallConnectTop
 	}
-	if x == mapComp.Width-1 || (x < mapComp.Width-1 && IsAnyWallType(mapComp.Tiles[y][x+1])) {
+	if connectsTo(mapComp, x+1, y, IsAnyWallType) {
 		mask |= WallConnectRight
 	}
-	if y == mapComp.Height-1 || (y < mapComp.Height-1 && IsAnyWallType(mapComp.Tiles[y+1][x])) {
+	if connectsTo(mapComp, x, y+1, IsAnyWallType) {
 		mask |= WallConnectBottom
 	}
-	if x == 0 || (x > 0 && IsAnyWallType(mapComp.Tiles[y][x-1])) {
+	if connectsTo(mapComp, x-1, y, IsAnyWallType) {
 		mask |= WallConnectLeft
 	}
 
@@ -998,21 +930,21 @@ func (g *DungeonGenerator) GenerateGiantBSPDungeon(mapComp *components.MapCompon
 					// Connect to both previous sections occasionally
 					if g.rng.Intn(100) < 30 { // 30% chance to connect to both
 						// Connect to left section
-						g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY)
+						g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY, g.nextCorridorWidth())
 						// Connect to above section
-						g.createVerticalCorridor(mapComp, startY-1, thisY, thisX)
+						g.createVerticalCorridor(mapComp, startY-1, thisY, thisX, g.nextCorridorWidth())
 					} else {
 						// Choose one direction randomly
 						if g.rng.Intn(2) == 0 {
-							g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY)
+							g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY, g.nextCorridorWidth())
 						} else {
-							g.createVerticalCorridor(mapComp, startY-1, thisY, thisX)
+							g.createVerticalCorridor(mapComp, startY-1, thisY, thisX, g.nextCorridorWidth())
 						}
 					}
 				} else if gridX > 0 {
-					g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY)
+					g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY, g.nextCorridorWidth())
 				} else if gridY > 0 {
-					g.createVerticalCorridor(mapComp, startY-1, thisY, thisX)
+					g.createVerticalCorridor(mapComp, startY-1, thisY, thisX, g.nextCorridorWidth())
 				}
 			}
 		}
@@ -1023,6 +955,11 @@ func (g *DungeonGenerator) GenerateGiantBSPDungeon(mapComp *components.MapCompon
 		g.ensureRoomConnectivity(allSectionNodes[i], mapComp)
 	}
 
+	g.rooms = nil
+	for _, section := range allSectionNodes {
+		g.collectRooms(section, &g.rooms)
+	}
+
 	// Apply improved box drawing characters to the walls
 	g.applyImprovedBoxDrawingWalls(mapComp)
 }
@@ -1114,48 +1051,8 @@ func (g *DungeonGenerator) ensureRoomConnectivity(rootNode *BSPNode, mapComp *co
 
 // roomsAreConnected checks if there is a valid path between two room centers
 func (g *DungeonGenerator) roomsAreConnected(mapComp *components.MapComponent, x1, y1, x2, y2 int) bool {
-	// Breadth-first search to find a path
-	queue := [][2]int{{x1, y1}}
-	visited := make(map[int]bool)
-
-	// Mark starting position as visited
-	visited[y1*mapComp.Width+x1] = true
-
-	// Define the four directions we can move
-	directions := [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
-
-	// BFS loop
-	for len(queue) > 0 {
-		// Get the next position
-		pos := queue[0]
-		queue = queue[1:]
-
-		x, y := pos[0], pos[1]
-
-		// Check if we've reached the destination
-		if x == x2 && y == y2 {
-			return true
-		}
-
-		// Try each direction
-		for _, dir := range directions {
-			nx, ny := x+dir[0], y+dir[1]
-
-			// Check if the new position is valid
-			if nx < 0 || nx >= mapComp.Width || ny < 0 || ny >= mapComp.Height {
-				continue
-			}
-
-			// Check if it's a floor tile and not visited
-			if mapComp.Tiles[ny][nx] != components.TileWall && !visited[ny*mapComp.Width+nx] {
-				queue = append(queue, [2]int{nx, ny})
-				visited[ny*mapComp.Width+nx] = true
-			}
-		}
-	}
-
-	// If we get here, no path was found
-	return false
+	region, _ := FloodFill(mapComp, x1, y1, isOpen)
+	return region.Contains(x2, y2, mapComp.Width)
 }
 
 // abs returns the absolute value of x
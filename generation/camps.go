@@ -0,0 +1,37 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// campMinSize is the smallest floor that gets a camp; anything smaller is quick
+// enough to cross without a breather
+const campMinSize = SizeLarge
+
+// addCamp sets up a camp against a room wall, on the same kind of spot a workbench
+// would use so it can't block a corridor
+func (t *DungeonThemer) addCamp(mapComp *components.MapComponent, floorID ecs.EntityID) bool {
+	var candidates [][2]int
+	for y := 1; y < mapComp.Height-1; y++ {
+		for x := 1; x < mapComp.Width-1; x++ {
+			if t.isStationSpot(mapComp, x, y) && t.isSpotFree(floorID, x, y) {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	spot := candidates[t.rng.Intn(len(candidates))]
+	t.entitySpawner.SetSpawnMapID(floorID)
+	t.entitySpawner.CreateCamp(spot[0], spot[1])
+
+	if t.logMessage != nil {
+		t.logMessage(fmt.Sprintf("Added camp at (%d,%d)", spot[0], spot[1]))
+	}
+	return true
+}
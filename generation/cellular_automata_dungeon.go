@@ -115,21 +115,21 @@ func (g *DungeonGenerator) GenerateGiantCellularDungeon(mapComp *components.MapC
 					// 40% chance to connect to both previous sections
 					if g.rng.Intn(100) < 40 {
 						// Connect to left section
-						g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY)
+						g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY, g.nextCorridorWidth())
 						// Connect to above section
-						g.createVerticalCorridor(mapComp, startY-1, thisY, thisX)
+						g.createVerticalCorridor(mapComp, startY-1, thisY, thisX, g.nextCorridorWidth())
 					} else {
 						// Choose one direction randomly
 						if g.rng.Intn(2) == 0 {
-							g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY)
+							g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY, g.nextCorridorWidth())
 						} else {
-							g.createVerticalCorridor(mapComp, startY-1, thisY, thisX)
+							g.createVerticalCorridor(mapComp, startY-1, thisY, thisX, g.nextCorridorWidth())
 						}
 					}
 				} else if gridX > 0 {
-					g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY)
+					g.createHorizontalCorridor(mapComp, startX-1, thisX, thisY, g.nextCorridorWidth())
 				} else if gridY > 0 {
-					g.createVerticalCorridor(mapComp, startY-1, thisY, thisX)
+					g.createVerticalCorridor(mapComp, startY-1, thisY, thisX, g.nextCorridorWidth())
 				}
 			}
 		}
@@ -263,28 +263,13 @@ func (g *DungeonGenerator) cleanupIsolatedTiles(mapComp *components.MapComponent
 
 // findLargestOpenArea finds the largest contiguous floor area in a section
 func (g *DungeonGenerator) findLargestOpenArea(mapComp *components.MapComponent, startX, startY, width, height int) *Room {
-	visited := make([][]bool, height)
-	for i := range visited {
-		visited[i] = make([]bool, width)
-	}
-
 	var largestRoom *Room
 	maxSize := 0
-
-	for y := startY; y < startY+height; y++ {
-		for x := startX; x < startX+width; x++ {
-			if y >= mapComp.Height || x >= mapComp.Width {
-				continue
-			}
-
-			if !visited[y-startY][x-startX] && mapComp.Tiles[y][x] == components.TileFloor {
-				room := g.floodFill(mapComp, x, y, visited, startX, startY, width, height)
-				size := room.Width * room.Height
-				if size > maxSize {
-					maxSize = size
-					largestRoom = room
-				}
-			}
+	for _, region := range connectedRegionsWithin(mapComp, Bounds{X: startX, Y: startY, Width: width, Height: height}, isFloor) {
+		bounds := region.Bounds(mapComp.Width)
+		if size := bounds.Width * bounds.Height; size > maxSize {
+			maxSize = size
+			largestRoom = &Room{X: bounds.X, Y: bounds.Y, Width: bounds.Width, Height: bounds.Height}
 		}
 	}
 
@@ -293,65 +278,15 @@ func (g *DungeonGenerator) findLargestOpenArea(mapComp *components.MapComponent,
 
 // findAllOpenAreas identifies all significant open areas in the map
 func (g *DungeonGenerator) findAllOpenAreas(mapComp *components.MapComponent) [][4]int {
-	visited := make([][]bool, mapComp.Height)
-	for i := range visited {
-		visited[i] = make([]bool, mapComp.Width)
-	}
-
 	var rooms [][4]int
 	minRoomSize := 16 // Minimum area to consider as a room
 
-	for y := 0; y < mapComp.Height; y++ {
-		for x := 0; x < mapComp.Width; x++ {
-			if !visited[y][x] && mapComp.Tiles[y][x] == components.TileFloor {
-				room := g.floodFill(mapComp, x, y, visited, 0, 0, mapComp.Width, mapComp.Height)
-				if room.Width*room.Height >= minRoomSize {
-					rooms = append(rooms, [4]int{room.X, room.Y, room.Width, room.Height})
-				}
-			}
+	for _, region := range ConnectedRegions(mapComp, isFloor) {
+		bounds := region.Bounds(mapComp.Width)
+		if bounds.Width*bounds.Height >= minRoomSize {
+			rooms = append(rooms, bounds.Rect())
 		}
 	}
 
 	return rooms
 }
-
-// floodFill performs a flood fill to find a contiguous area of floor tiles
-func (g *DungeonGenerator) floodFill(mapComp *components.MapComponent, x, y int, visited [][]bool, startX, startY, width, height int) *Room {
-	if x < startX || x >= startX+width || y < startY || y >= startY+height {
-		return &Room{X: x, Y: y, Width: 0, Height: 0}
-	}
-
-	if visited[y-startY][x-startX] || mapComp.Tiles[y][x] != components.TileFloor {
-		return &Room{X: x, Y: y, Width: 0, Height: 0}
-	}
-
-	visited[y-startY][x-startX] = true
-
-	minX, maxX := x, x
-	minY, maxY := y, y
-
-	// Check adjacent tiles
-	for dy := -1; dy <= 1; dy++ {
-		for dx := -1; dx <= 1; dx++ {
-			if dx == 0 && dy == 0 {
-				continue
-			}
-
-			newX, newY := x+dx, y+dy
-			if newX >= startX && newX < startX+width && newY >= startY && newY < startY+height {
-				room := g.floodFill(mapComp, newX, newY, visited, startX, startY, width, height)
-				minX = min(minX, room.X)
-				maxX = max(maxX, room.X+room.Width)
-				minY = min(minY, room.Y)
-				maxY = max(maxY, room.Y+room.Height)
-			}
-		}
-	}
-
-	return &Room{
-		X:      minX,
-		Y:      minY,
-		Width:  maxX - minX,
-		Height: maxY - minY,
-	}
-}
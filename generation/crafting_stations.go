@@ -0,0 +1,129 @@
+package generation
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// workbenchGround is the open ground a workbench can stand on beside a station.
+// Railway, bridges and mountains are left clear so the station's approaches stay open.
+var workbenchGround = []int{components.TileWasteland, components.TileDesert, components.TileDarkForest}
+
+// addCraftingStation places a workbench against a room wall. Stations block
+// movement, so only tiles that can't cut off a corridor or doorway are used.
+func (t *DungeonThemer) addCraftingStation(mapComp *components.MapComponent, floorID ecs.EntityID) bool {
+	var candidates [][2]int
+	for y := 1; y < mapComp.Height-1; y++ {
+		for x := 1; x < mapComp.Width-1; x++ {
+			if t.isStationSpot(mapComp, x, y) {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	spot := candidates[t.rng.Intn(len(candidates))]
+	t.entitySpawner.SetSpawnMapID(floorID)
+	t.entitySpawner.CreateCraftingStation(spot[0], spot[1])
+
+	if t.logMessage != nil {
+		t.logMessage(fmt.Sprintf("Added crafting station at (%d,%d)", spot[0], spot[1]))
+	}
+	return true
+}
+
+// isStationSpot returns true for a floor tile with a wall on exactly one side and
+// open floor on the other three, away from doors and stairs
+func (t *DungeonThemer) isStationSpot(mapComp *components.MapComponent, x, y int) bool {
+	if mapComp.Tiles[y][x] != components.TileFloor {
+		return false
+	}
+
+	walls := 0
+	for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		tile := mapComp.Tiles[y+dir[1]][x+dir[0]]
+		if mapComp.IsWall(x+dir[0], y+dir[1]) {
+			walls++
+		} else if tile != components.TileFloor {
+			return false
+		}
+	}
+	if walls != 1 {
+		return false
+	}
+
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			tile := mapComp.Tiles[y+dy][x+dx]
+			if tile == components.TileDoor || tile == components.TileStairsDown || tile == components.TileStairsUp {
+				return false
+			}
+			if _, isTransition := mapComp.GetTransition(x+dx, y+dy); isTransition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// PlaceSubstationWorkbenches picks a spot next to every substation on the world map
+// for a workbench, on open ground that isn't a dungeon entrance. A station boxed in by
+// mountains, water and track gets none.
+func PlaceSubstationWorkbenches(worldMap *components.MapComponent, rng *rand.Rand) [][2]int {
+	var spots [][2]int
+	for y := 0; y < worldMap.Height; y++ {
+		for x := 0; x < worldMap.Width; x++ {
+			if worldMap.Tiles[y][x] != components.TileSubstation {
+				continue
+			}
+
+			var candidates [][2]int
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= worldMap.Width || ny >= worldMap.Height {
+						continue
+					}
+					if !slices.Contains(workbenchGround, worldMap.Tiles[ny][nx]) || slices.Contains(spots, [2]int{nx, ny}) {
+						continue
+					}
+					if _, isTransition := worldMap.GetTransition(nx, ny); isTransition {
+						continue
+					}
+					candidates = append(candidates, [2]int{nx, ny})
+				}
+			}
+			if len(candidates) > 0 {
+				spots = append(spots, candidates[rng.Intn(len(candidates))])
+			}
+		}
+	}
+	return spots
+}
+
+// AddSubstationWorkbenches puts a workbench beside every substation on the world map,
+// so materials found on the surface can be crafted without going underground. Returns
+// how many were placed.
+func (t *DungeonThemer) AddSubstationWorkbenches(worldMapEntity *ecs.Entity, rng *rand.Rand) int {
+	mapComp, exists := t.world.GetComponent(worldMapEntity.ID, components.MapComponentID)
+	if !exists {
+		return 0
+	}
+
+	spots := PlaceSubstationWorkbenches(mapComp.(*components.MapComponent), rng)
+	t.entitySpawner.SetSpawnMapID(worldMapEntity.ID)
+	for _, spot := range spots {
+		t.entitySpawner.CreateCraftingStation(spot[0], spot[1])
+	}
+
+	if t.logMessage != nil {
+		t.logMessage(fmt.Sprintf("Added %d workbenches beside the substations", len(spots)))
+	}
+	return len(spots)
+}
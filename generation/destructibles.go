@@ -0,0 +1,51 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// addDestructibles lines room walls with crates and barrels that can be broken, some
+// of them powder kegs that explode. Props block movement, so they go on the same kind
+// of spot as a workbench, where they can't cut off a corridor or doorway.
+func (t *DungeonThemer) addDestructibles(mapComp *components.MapComponent, floorID ecs.EntityID, count int, kegChance float64) int {
+	var candidates [][2]int
+	for y := 1; y < mapComp.Height-1; y++ {
+		for x := 1; x < mapComp.Width-1; x++ {
+			if t.isStationSpot(mapComp, x, y) && t.isSpotFree(floorID, x, y) {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+	}
+
+	t.entitySpawner.SetSpawnMapID(floorID)
+	placed := 0
+	for _, i := range t.rng.Perm(len(candidates)) {
+		if placed >= count {
+			break
+		}
+		spot := candidates[i]
+
+		kind := "crate"
+		if t.rng.Float64() < kegChance {
+			kind = "powder_keg"
+		} else if t.rng.Intn(2) == 0 {
+			kind = "barrel"
+		}
+
+		if _, err := t.entitySpawner.CreateDestructible(spot[0], spot[1], kind); err != nil {
+			if t.logMessage != nil {
+				t.logMessage(fmt.Sprintf("Warning: failed to place %s at (%d,%d): %v", kind, spot[0], spot[1], err))
+			}
+			continue
+		}
+		placed++
+	}
+
+	if t.logMessage != nil && placed > 0 {
+		t.logMessage(fmt.Sprintf("Added %d destructible props", placed))
+	}
+	return placed
+}
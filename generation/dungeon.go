@@ -18,23 +18,57 @@ const (
 	DungeonTypeLargeCellular
 )
 
+// MaxCorridorWidth is the widest corridor the generators will carve
+const MaxCorridorWidth = 3
+
 // DungeonGenerator handles procedural generation of dungeon layouts
 type DungeonGenerator struct {
-	rng *rand.Rand
+	rng            *rand.Rand
+	corridorWidth  int      // Width of carved corridors in tiles, the widest when mixed
+	mixedCorridors bool     // Whether each corridor rolls its own width up to corridorWidth
+	rooms          [][4]int // Rooms of the last BSP layout as {x, y, width, height}
 }
 
 // NewDungeonGenerator creates a new dungeon generator
 func NewDungeonGenerator() *DungeonGenerator {
 	return &DungeonGenerator{
-		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		corridorWidth: 1,
 	}
 }
 
+// SetCorridorWidth sets how wide carved corridors are, from 1 to MaxCorridorWidth.
+// When mixed, each corridor gets a random width up to the given one.
+func (g *DungeonGenerator) SetCorridorWidth(width int, mixed bool) {
+	g.corridorWidth = max(1, min(width, MaxCorridorWidth))
+	g.mixedCorridors = mixed
+}
+
+// nextCorridorWidth returns the width for the next corridor. Only mixed widths use
+// the random source, so single-width layouts come out the same for a seed as before.
+func (g *DungeonGenerator) nextCorridorWidth() int {
+	if g.mixedCorridors && g.corridorWidth > 1 {
+		return 1 + g.rng.Intn(g.corridorWidth)
+	}
+	return g.corridorWidth
+}
+
 // SetSeed allows setting a specific seed for reproducible dungeons
 func (g *DungeonGenerator) SetSeed(seed int64) {
 	g.rng = rand.New(rand.NewSource(seed))
 }
 
+// SetRNG sets the random source layouts are rolled from
+func (g *DungeonGenerator) SetRNG(rng *rand.Rand) {
+	g.rng = rng
+}
+
+// Rooms returns the room rectangles of the last BSP layout, as {x, y, width, height}.
+// Corridors and features may have been carved through them since.
+func (g *DungeonGenerator) Rooms() [][4]int {
+	return g.rooms
+}
+
 // GenerateRoomsAndCorridors creates random rooms and connects them with corridors
 func (g *DungeonGenerator) GenerateRoomsAndCorridors(mapComp *components.MapComponent) {
 	// Create a few random rooms
@@ -105,54 +139,19 @@ func (g *DungeonGenerator) FindEmptyPosition(mapComp *components.MapComponent) (
 
 // FindFirstRoomInMap returns the coordinates of the first room found in the map
 func (g *DungeonGenerator) FindFirstRoomInMap(mapComp *components.MapComponent) [][4]int {
-	// Create a visited array to track which tiles we've checked
-	visited := make([][]bool, mapComp.Height)
-	for i := range visited {
-		visited[i] = make([]bool, mapComp.Width)
-	}
-
 	// Scan the map for floor tiles
 	for y := 0; y < mapComp.Height; y++ {
 		for x := 0; x < mapComp.Width; x++ {
-			if !visited[y][x] && mapComp.Tiles[y][x] == components.TileFloor {
+			if mapComp.Tiles[y][x] == components.TileFloor {
 				// Found a floor tile, flood fill to find room boundaries
-				minX, minY := x, y
-				maxX, maxY := x, y
-				g.floodFillRoom(mapComp, visited, x, y, &minX, &minY, &maxX, &maxY)
-				return [][4]int{{minX, minY, maxX, maxY}}
+				_, bounds := FloodFill(mapComp, x, y, isFloor)
+				return [][4]int{{bounds.X, bounds.Y, bounds.X + bounds.Width - 1, bounds.Y + bounds.Height - 1}}
 			}
 		}
 	}
 	return nil
 }
 
-// floodFillRoom performs a flood fill to find room boundaries
-func (g *DungeonGenerator) floodFillRoom(mapComp *components.MapComponent, visited [][]bool, x, y int, minX, minY, maxX, maxY *int) {
-	if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height || visited[y][x] || mapComp.Tiles[y][x] != components.TileFloor {
-		return
-	}
-
-	visited[y][x] = true
-	if x < *minX {
-		*minX = x
-	}
-	if x > *maxX {
-		*maxX = x
-	}
-	if y < *minY {
-		*minY = y
-	}
-	if y > *maxY {
-		*maxY = y
-	}
-
-	// Recursively flood fill in all directions
-	g.floodFillRoom(mapComp, visited, x+1, y, minX, minY, maxX, maxY)
-	g.floodFillRoom(mapComp, visited, x-1, y, minX, minY, maxX, maxY)
-	g.floodFillRoom(mapComp, visited, x, y+1, minX, minY, maxX, maxY)
-	g.floodFillRoom(mapComp, visited, x, y-1, minX, minY, maxX, maxY)
-}
-
 // Generate creates a new dungeon layout in the provided map component
 func (g *DungeonGenerator) Generate(mapComp *components.MapComponent, size DungeonSize) [][4]int {
 	// Fill the map with walls initially
@@ -216,22 +215,11 @@ func (g *DungeonGenerator) generateNormalBSP(mapComp *components.MapComponent) [
 // findRooms finds all rooms in the generated dungeon
 func (d *DungeonGenerator) findRooms(mapComp *components.MapComponent) [][4]int {
 	var rooms [][4]int
-	visited := make([][]bool, mapComp.Height)
-	for i := range visited {
-		visited[i] = make([]bool, mapComp.Width)
-	}
 
 	// Find rooms by flood filling floor tiles
-	for y := 0; y < mapComp.Height; y++ {
-		for x := 0; x < mapComp.Width; x++ {
-			if !visited[y][x] && mapComp.Tiles[y][x] == components.TileFloor {
-				// Found a new room, flood fill to find its bounds
-				minX, minY := x, y
-				maxX, maxY := x, y
-				d.floodFillRoom(mapComp, visited, x, y, &minX, &minY, &maxX, &maxY)
-				rooms = append(rooms, [4]int{minX, minY, maxX, maxY})
-			}
-		}
+	for _, region := range ConnectedRegions(mapComp, isFloor) {
+		bounds := region.Bounds(mapComp.Width)
+		rooms = append(rooms, [4]int{bounds.X, bounds.Y, bounds.X + bounds.Width - 1, bounds.Y + bounds.Height - 1})
 	}
 	return rooms
 }
@@ -14,6 +14,7 @@ func (g *DungeonGenerator) AddFeatures(mapComp *components.MapComponent, rooms [
 	g.addPools(mapComp)
 	g.addStairs(mapComp, rooms)
 	g.addVegetation(mapComp)
+	RepairHazardLockedAreas(mapComp)
 }
 
 // addPools adds water and lava pools to the dungeon
@@ -99,49 +100,85 @@ func (g *DungeonGenerator) addVegetation(mapComp *components.MapComponent) {
 	}
 }
 
-// CreateCorridor creates a corridor between two points
+// CreateCorridor creates an L-shaped corridor between two points, sometimes with a
+// door at one end. A door on a wide corridor spans its full width.
 func (g *DungeonGenerator) CreateCorridor(mapComp *components.MapComponent, x1, y1, x2, y2 int) {
+	width := g.nextCorridorWidth()
+
 	// Randomly choose between horizontal-first or vertical-first
-	if g.rng.Intn(2) == 0 {
-		g.createHorizontalCorridor(mapComp, x1, x2, y1)
-		g.createVerticalCorridor(mapComp, y1, y2, x2)
+	horizontalFirst := g.rng.Intn(2) == 0
+	if horizontalFirst {
+		g.createHorizontalCorridor(mapComp, x1, x2, y1, width)
+		g.createVerticalCorridor(mapComp, y1, y2, x2, width)
 	} else {
-		g.createVerticalCorridor(mapComp, y1, y2, x1)
-		g.createHorizontalCorridor(mapComp, x1, x2, y2)
+		g.createVerticalCorridor(mapComp, y1, y2, x1, width)
+		g.createHorizontalCorridor(mapComp, x1, x2, y2, width)
 	}
 
 	// Add a door at one end (20% chance)
 	if g.rng.Intn(100) < 20 {
-		doorX, doorY := x1, y1
+		// The door runs across the leg of the corridor that ends there
+		doorX, doorY, across := x1, y1, horizontalFirst
 		if g.rng.Intn(2) == 0 {
-			doorX, doorY = x2, y2
+			doorX, doorY, across = x2, y2, !horizontalFirst
 		}
 
-		// Place the door if it's within bounds and on a floor tile
-		if doorX >= 0 && doorX < mapComp.Width && doorY >= 0 && doorY < mapComp.Height {
-			if mapComp.Tiles[doorY][doorX] == components.TileFloor {
-				mapComp.SetTile(doorX, doorY, components.TileDoor)
+		lo, hi := corridorSpan(width)
+		for offset := lo; offset <= hi; offset++ {
+			x, y := doorX+offset, doorY
+			if across {
+				x, y = doorX, doorY+offset
+			}
+
+			// Place the door if it's within bounds and on a floor tile
+			if x >= 0 && x < mapComp.Width && y >= 0 && y < mapComp.Height {
+				if mapComp.Tiles[y][x] == components.TileFloor {
+					mapComp.SetTile(x, y, components.TileDoor)
+				}
 			}
 		}
 	}
 }
 
-// createHorizontalCorridor creates a horizontal corridor from x1 to x2 at y
-func (g *DungeonGenerator) createHorizontalCorridor(mapComp *components.MapComponent, x1, x2, y int) {
-	for x := min(x1, x2); x <= max(x1, x2); x++ {
-		// Check map bounds
-		if x >= 0 && x < mapComp.Width && y >= 0 && y < mapComp.Height {
-			mapComp.SetTile(x, y, components.TileFloor)
+// corridorSpan returns the offsets from a corridor's center line that a corridor of
+// the given width covers, so 3 covers one tile either side and 2 adds one below or right
+func corridorSpan(width int) (lo, hi int) {
+	return -(width - 1) / 2, width / 2
+}
+
+// createHorizontalCorridor creates a horizontal corridor from x1 to x2 centered on y.
+// The center line is always carved, so widening a corridor never breaks a connection;
+// the extra rows only cut into walls and stay off the map's outer edge.
+func (g *DungeonGenerator) createHorizontalCorridor(mapComp *components.MapComponent, x1, x2, y, width int) {
+	lo, hi := corridorSpan(width)
+	for x := min(x1, x2) + lo; x <= max(x1, x2)+hi; x++ {
+		for offset := lo; offset <= hi; offset++ {
+			g.carveCorridorTile(mapComp, x, y+offset, offset == 0 && x >= min(x1, x2) && x <= max(x1, x2))
 		}
 	}
 }
 
-// createVerticalCorridor creates a vertical corridor from y1 to y2 at x
-func (g *DungeonGenerator) createVerticalCorridor(mapComp *components.MapComponent, y1, y2, x int) {
-	for y := min(y1, y2); y <= max(y1, y2); y++ {
+// createVerticalCorridor creates a vertical corridor from y1 to y2 centered on x
+func (g *DungeonGenerator) createVerticalCorridor(mapComp *components.MapComponent, y1, y2, x, width int) {
+	lo, hi := corridorSpan(width)
+	for y := min(y1, y2) + lo; y <= max(y1, y2)+hi; y++ {
+		for offset := lo; offset <= hi; offset++ {
+			g.carveCorridorTile(mapComp, x+offset, y, offset == 0 && y >= min(y1, y2) && y <= max(y1, y2))
+		}
+	}
+}
+
+// carveCorridorTile turns a tile into floor. Center line tiles are always carved;
+// widening tiles only replace walls inside the map's border.
+func (g *DungeonGenerator) carveCorridorTile(mapComp *components.MapComponent, x, y int, centerLine bool) {
+	if centerLine {
 		// Check map bounds
 		if x >= 0 && x < mapComp.Width && y >= 0 && y < mapComp.Height {
 			mapComp.SetTile(x, y, components.TileFloor)
 		}
+		return
+	}
+	if x >= 1 && x < mapComp.Width-1 && y >= 1 && y < mapComp.Height-1 && IsAnyWallType(mapComp.Tiles[y][x]) {
+		mapComp.SetTile(x, y, components.TileFloor)
 	}
 }
@@ -5,8 +5,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+
+	"ebiten-rogue/data"
+	"ebiten-rogue/systems"
 )
 
+// DefaultStartingContainer is the container template a starting chest uses when its
+// theme doesn't name one
+const DefaultStartingContainer = "starter_chest"
+
+// StartingChest is the chest put beside the player when a run starts on a theme
+type StartingChest struct {
+	Container string             `json:"container"` // Container template it looks like (empty = starter_chest)
+	Items     []data.LoadoutItem `json:"items"`     // What is in it, before the class adds its own
+}
+
+// ContainerID returns the container template the chest is made from
+func (c *StartingChest) ContainerID() string {
+	if c.Container == "" {
+		return DefaultStartingContainer
+	}
+	return c.Container
+}
+
 // DungeonThemeDefinition defines a complete theme for a dungeon
 type DungeonThemeDefinition struct {
 	ID          string   `json:"id"`           // Unique identifier for the theme
@@ -26,13 +48,43 @@ type DungeonThemeDefinition struct {
 		TileType string  `json:"tile_type"` // Type of special tile
 		Chance   float64 `json:"chance"`    // Chance of this tile appearing (0.0-1.0)
 	} `json:"special_tiles"` // Special tiles specific to this theme
+	PuzzleRoomChance      float64  `json:"puzzle_room_chance"`      // Chance of a lever-sealed side room per floor (0.0-1.0)
+	CraftingStationChance float64  `json:"crafting_station_chance"` // Chance of a workbench per floor (0.0-1.0)
+	ShrineChance          float64  `json:"shrine_chance"`           // Chance of an altar per floor (0.0-1.0)
+	PrefabChance          float64  `json:"prefab_chance"`           // Chance of a prefab vault per floor (0.0-1.0)
+	Prefabs               []string `json:"prefabs"`                 // Prefab IDs this theme uses (empty = any)
+	PropCount             int      `json:"prop_count"`              // Crates and barrels against room walls per floor (0 = none)
+	ExplosivePropChance   float64  `json:"explosive_prop_chance"`   // Chance each prop is an exploding powder keg (0.0-1.0)
 
 	// Monster population
 	DensityFactor         float64  `json:"density_factor"`           // Monster density (0.0-2.0, 1.0 = standard)
+	ThreatBudget          float64  `json:"threat_budget"`            // Monster threat per room at level 1, scaled up by level and difficulty (0 = use density)
 	HigherLevelChance     float64  `json:"higher_level_chance"`      // Chance for monsters from next level up (0.0-1.0)
 	EvenHigherLevelChance float64  `json:"even_higher_level_chance"` // Chance for monsters two levels up (0.0-1.0)
 	BossChance            float64  `json:"boss_chance"`              // Chance of a boss monster (0.0-1.0)
 	BossTypes             []string `json:"boss_types"`               // Possible boss monster types
+	SafeRadius            int      `json:"safe_radius"`              // Monster-free radius around the stairs (0 = default)
+
+	// Starting floor
+	TutorialHints []systems.TutorialHint `json:"tutorial_hints"` // Hints shown once each when this is the starting floor
+	StartingChest *StartingChest         `json:"starting_chest"` // Chest beside the player when this is the starting floor (nil = none)
+}
+
+// validateStartingChest checks that the starting chest only names templates that exist,
+// so a typo shows up when the theme loads rather than as a missing item in game
+func (d *DungeonThemeDefinition) validateStartingChest(templates *data.EntityTemplateManager) error {
+	if d.StartingChest == nil {
+		return nil
+	}
+	if _, exists := templates.GetContainerTemplate(d.StartingChest.ContainerID()); !exists {
+		return fmt.Errorf("theme %s starting chest uses unknown container '%s'", d.ID, d.StartingChest.ContainerID())
+	}
+	for _, item := range d.StartingChest.Items {
+		if _, exists := templates.GetItemTemplate(item.TemplateID); !exists {
+			return fmt.Errorf("theme %s starting chest holds unknown item '%s'", d.ID, item.TemplateID)
+		}
+	}
+	return nil
 }
 
 // DungeonThemeManager handles loading and managing dungeon themes from JSON files
@@ -90,16 +142,18 @@ func (m *DungeonThemeManager) GetTheme(id string) *DungeonThemeDefinition {
 	return m.themes[id]
 }
 
-// GetAllThemes returns all loaded themes
+// GetAllThemes returns all loaded themes, sorted by ID so picking one at random
+// doesn't depend on map order
 func (m *DungeonThemeManager) GetAllThemes() []*DungeonThemeDefinition {
 	result := make([]*DungeonThemeDefinition, 0, len(m.themes))
 	for _, theme := range m.themes {
 		result = append(result, theme)
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
 	return result
 }
 
-// GetThemesByDifficulty returns themes with the given difficulty level
+// GetThemesByDifficulty returns themes with the given difficulty level, sorted by ID
 func (m *DungeonThemeManager) GetThemesByDifficulty(difficulty int) []*DungeonThemeDefinition {
 	var result []*DungeonThemeDefinition
 	for _, theme := range m.themes {
@@ -107,5 +161,6 @@ func (m *DungeonThemeManager) GetThemesByDifficulty(difficulty int) []*DungeonTh
 			result = append(result, theme)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
 	return result
 }
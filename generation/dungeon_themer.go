@@ -8,6 +8,7 @@ import (
 	"ebiten-rogue/data"
 	"ebiten-rogue/ecs"
 	"ebiten-rogue/spawners"
+	"ebiten-rogue/systems"
 )
 
 type GeneratorType int
@@ -16,6 +17,7 @@ const (
 	GeneratorBSP      GeneratorType = iota // Binary Space Partitioning generator
 	GeneratorCellular                      // Cellular Automata generator
 	GeneratorRandom                        // Simple random rooms with corridors
+	GeneratorHybrid                        // BSP rooms with cellular caves grown in the large ones
 )
 
 // DungeonConfiguration defines a complete configuration for a dungeon
@@ -30,6 +32,11 @@ type DungeonConfiguration struct {
 	ThemeID               string        // ID of the JSON theme definition to use
 	TotalFloors           int           // Total number of floors to generate (default: 1)
 	CurrentFloor          int           // Current floor being generated (1-based)
+	SurfaceX              int           // World map tile the first floor's stairs up lead to,
+	SurfaceY              int           // the central station if both are 0
+	CorridorWidth         int           // Corridor width in tiles, 1 to MaxCorridorWidth (0 = 1)
+	MixedCorridors        bool          // Give each corridor a random width up to CorridorWidth
+	BossType              string        // Monster that always waits on each floor as its boss, "" for none
 }
 
 // DungeonSize defines the size category of a dungeon
@@ -59,8 +66,10 @@ type DungeonThemer struct {
 	templateManager *data.EntityTemplateManager
 	entitySpawner   *spawners.EntitySpawner
 	themeManager    *DungeonThemeManager
+	prefabManager   *PrefabManager
 	rng             *rand.Rand
-	logMessage      func(string) // Function for logging messages
+	graveyard       *systems.GraveyardSystem // Source of previous characters' graves, nil for none
+	logMessage      func(string)             // Function for logging messages
 }
 
 // NewDungeonThemer creates a new dungeon theme manager
@@ -73,6 +82,7 @@ func NewDungeonThemer(world *ecs.World, templateManager *data.EntityTemplateMana
 		templateManager: templateManager,
 		entitySpawner:   entitySpawner,
 		themeManager:    NewDungeonThemeManager(),
+		prefabManager:   NewPrefabManager(),
 		rng:             rand.New(rand.NewSource(0)), // Will be seeded via SetSeed
 		logMessage:      logFunc,
 	}
@@ -85,9 +95,41 @@ func (t *DungeonThemer) SetSeed(seed int64) {
 	t.populator.SetSeed(seed)
 }
 
-// LoadThemesFromDirectory loads dungeon themes from JSON files
+// SetRNG sets the random sources used for generation: one for layouts and themed
+// features, one for populating the floors
+func (t *DungeonThemer) SetRNG(layout, population *rand.Rand) {
+	t.rng = layout
+	t.dungeonGen.SetRNG(layout)
+	t.populator.SetRNG(population)
+}
+
+// SetGraveyard sets the graveyard that graves of previous characters are taken from
+func (t *DungeonThemer) SetGraveyard(graveyard *systems.GraveyardSystem) {
+	t.graveyard = graveyard
+}
+
+// LoadThemesFromDirectory loads dungeon themes from JSON files, checking that their
+// starting chests hold items that exist
 func (t *DungeonThemer) LoadThemesFromDirectory(directory string) error {
-	return t.themeManager.LoadThemesFromDirectory(directory)
+	if err := t.themeManager.LoadThemesFromDirectory(directory); err != nil {
+		return err
+	}
+	for _, theme := range t.themeManager.GetAllThemes() {
+		if err := theme.validateStartingChest(t.templateManager); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadPrefabsFromDirectory loads the prefab rooms themes can stamp into their floors
+func (t *DungeonThemer) LoadPrefabsFromDirectory(directory string) error {
+	return t.prefabManager.LoadPrefabsFromDirectory(directory)
+}
+
+// GetTheme returns a loaded theme definition by ID, or nil if there is none
+func (t *DungeonThemer) GetTheme(id string) *DungeonThemeDefinition {
+	return t.themeManager.GetTheme(id)
 }
 
 // GenerateThemedDungeon creates a new dungeon entity with the specified configuration
@@ -145,7 +187,8 @@ func (t *DungeonThemer) generateFloor(config DungeonConfiguration, themeDef *Dun
 	mapComp := components.NewMapComponent(width, height)
 
 	// Generate the layout
-	var rooms [][4]int
+	t.dungeonGen.SetCorridorWidth(config.CorridorWidth, config.MixedCorridors)
+	var rooms, prefabRooms [][4]int
 	switch config.Generator {
 	case GeneratorBSP:
 		switch config.Size {
@@ -159,15 +202,22 @@ func (t *DungeonThemer) generateFloor(config DungeonConfiguration, themeDef *Dun
 			t.dungeonGen.GenerateBSPDungeon(mapComp)
 		}
 		rooms = t.dungeonGen.FindFirstRoomInMap(mapComp)
+		prefabRooms = t.dungeonGen.Rooms()
 	case GeneratorCellular:
 		rooms = t.dungeonGen.Generate(mapComp, config.Size)
 	case GeneratorRandom:
 		rooms = t.generateRandomRoomsAndCorridors(mapComp, config.Size)
+		prefabRooms = rooms
+	case GeneratorHybrid:
+		t.dungeonGen.GenerateHybridDungeon(mapComp, config.Size)
+		rooms = t.dungeonGen.FindFirstRoomInMap(mapComp)
+		prefabRooms = t.dungeonGen.Rooms()
 	}
 
 	// Apply theme
 	if themeDef != nil {
 		t.applyThemeDefinition(mapComp, themeDef, rooms)
+		RepairHazardLockedAreas(mapComp)
 	} else {
 		t.logMessage("Warning: No theme definition provided")
 	}
@@ -220,6 +270,9 @@ func (t *DungeonThemer) generateFloor(config DungeonConfiguration, themeDef *Dun
 				worldMap := worldMapComp.(*components.MapComponent)
 				// Find the central station tile (usually in the middle of the world map)
 				centerX, centerY := worldMap.Width/2, worldMap.Height/2
+				if config.SurfaceX != 0 || config.SurfaceY != 0 {
+					centerX, centerY = config.SurfaceX, config.SurfaceY
+				}
 				mapComp.AddTransition(x, y, worldMapEntities[0].ID, centerX, centerY, true)
 			} else {
 				t.logMessage("Warning: Could not find world map component")
@@ -237,12 +290,23 @@ func (t *DungeonThemer) generateFloor(config DungeonConfiguration, themeDef *Dun
 	mapType := components.NewMapTypeComponent("dungeon", config.CurrentFloor)
 	t.world.AddComponent(floorEntity.ID, components.MapType, mapType)
 
+	// Remember the theme so the stairs above can hint at it
+	if themeDef != nil {
+		t.world.AddComponent(floorEntity.ID, components.FloorTheme, &components.FloorThemeComponent{
+			ThemeID:      themeDef.ID,
+			Name:         themeDef.Name,
+			Difficulty:   themeDef.Difficulty,
+			DungeonLevel: config.Level,
+		})
+	}
+
 	// Populate the dungeon with monsters and items
 	options := PopulationOptions{
 		DungeonLevel:          config.Level,
 		DensityFactor:         config.DensityFactor,
 		HigherLevelChance:     config.HigherLevelChance,
 		EvenHigherLevelChance: config.EvenHigherLevelChance,
+		Rooms:                 prefabRooms,
 	}
 
 	// If using a JSON theme, use its tags
@@ -250,12 +314,56 @@ func (t *DungeonThemer) generateFloor(config DungeonConfiguration, themeDef *Dun
 		options.PreferredTags = themeDef.Tags
 		options.ExcludeTags = themeDef.ExcludeTags
 		options.DensityFactor = themeDef.DensityFactor
+		options.ThreatBudget = themeDef.ThreatBudget
+		options.Difficulty = themeDef.Difficulty
+		options.SafeRadius = themeDef.SafeRadius
 		options.HigherLevelChance = themeDef.HigherLevelChance
 		options.EvenHigherLevelChance = themeDef.EvenHigherLevelChance
 	}
 
+	// Stamp a hand-authored vault into an empty room
+	if themeDef != nil && themeDef.PrefabChance > 0 && t.rng.Float64() < themeDef.PrefabChance {
+		t.addPrefab(mapComp, floorEntity.ID, prefabRooms, themeDef, config.Level)
+	}
+
+	// Seal off a side area behind a lever-operated gate
+	if themeDef != nil && themeDef.PuzzleRoomChance > 0 && t.rng.Float64() < themeDef.PuzzleRoomChance {
+		t.addPuzzleRoom(mapComp, floorEntity.ID)
+	}
+
+	// Set up a workbench for crafting
+	if themeDef != nil && themeDef.CraftingStationChance > 0 && t.rng.Float64() < themeDef.CraftingStationChance {
+		t.addCraftingStation(mapComp, floorEntity.ID)
+	}
+
+	// Raise an altar to sacrifice items at
+	if themeDef != nil && themeDef.ShrineChance > 0 && t.rng.Float64() < themeDef.ShrineChance {
+		t.addShrine(mapComp, floorEntity.ID)
+	}
+
+	// Long floors get a camp to catch a breather at
+	if config.Size >= campMinSize {
+		t.addCamp(mapComp, floorEntity.ID)
+	}
+
+	// Stack breakable crates and barrels against the walls
+	if themeDef != nil && themeDef.PropCount > 0 {
+		t.addDestructibles(mapComp, floorEntity.ID, themeDef.PropCount, themeDef.ExplosivePropChance)
+	}
+
+	// Sometimes a previous character lies buried here
+	if t.graveyard != nil && t.rng.Float64() < gravestoneChance {
+		t.addGravestone(mapComp, floorEntity.ID, config.CurrentFloor)
+	}
+
 	t.populator.PopulateDungeon(mapComp, floorEntity.ID, options)
 
+	// A boss waits on floors that are built around one
+	if config.BossType != "" {
+		t.entitySpawner.SetSpawnMapID(floorEntity.ID)
+		t.addBossMonster(mapComp, []string{config.BossType})
+	}
+
 	return floorEntity
 }
 
@@ -341,28 +449,18 @@ func (t *DungeonThemer) addStairsUpNearPlayerSpawn(mapComp *components.MapCompon
 	// Find a suitable empty position for stairs
 	x, y := t.findPlayerSpawnLocation(mapComp)
 
-	// Try to find another empty spot nearby
-	for dx := -3; dx <= 3; dx++ {
-		for dy := -3; dy <= 3; dy++ {
-			nx, ny := x+dx, y+dy
-			// Skip the exact spawn position
-			if dx == 0 && dy == 0 {
-				continue
-			}
+	// Try to find another empty spot nearby, keeping the spawn itself clear
+	mapComp.SetTile(x, y, components.TileWall)
+	nx, ny, found := FindNearestWalkable(mapComp, x, y, isFloor)
+	mapComp.SetTile(x, y, components.TileFloor)
+	if found && abs(nx-x) <= 3 && abs(ny-y) <= 3 {
+		mapComp.SetTile(nx, ny, components.TileStairsUp)
+		t.logMessage(fmt.Sprintf("Added stairs up at (%d,%d)", nx, ny))
 
-			// Check if position is valid and not a wall
-			if nx >= 0 && nx < mapComp.Width && ny >= 0 && ny < mapComp.Height &&
-				mapComp.Tiles[ny][nx] == components.TileFloor {
-				// Found a spot, place stairs up
-				mapComp.SetTile(nx, ny, components.TileStairsUp)
-				t.logMessage(fmt.Sprintf("Added stairs up at (%d,%d)", nx, ny))
+		// Since we don't have mapEntity.ID available here, just place the tile
+		// and skip creating a dedicated stairs entity for now
 
-				// Since we don't have mapEntity.ID available here, just place the tile
-				// and skip creating a dedicated stairs entity for now
-
-				return
-			}
-		}
+		return
 	}
 
 	// If all else fails, just place stairs at the spawn location
@@ -424,40 +522,20 @@ func (t *DungeonThemer) applyThemeDefinition(mapComp *components.MapComponent, t
 		stairsPlaced := false
 
 		// Try several positions within the room
-		for attempts := 0; attempts < 20 && !stairsPlaced; attempts++ {
+		for attempts := 0; attempts < 20 && !stairsPlaced && lastRoom[2] > 0 && lastRoom[3] > 0; attempts++ {
 			testX := lastRoom[0] + t.rng.Intn(lastRoom[2])
 			testY := lastRoom[1] + t.rng.Intn(lastRoom[3])
 
-			if mapComp.Tiles[testY][testX] == components.TileFloor {
+			if testX < mapComp.Width && testY < mapComp.Height && mapComp.Tiles[testY][testX] == components.TileFloor {
 				stairsX, stairsY = testX, testY
 				stairsPlaced = true
 			}
 		}
 
-		// If we couldn't find a floor tile after several attempts, use center of room
+		// If we couldn't find a floor tile after several attempts, use the one nearest
+		// the center of the room
 		if !stairsPlaced {
-			stairsX = lastRoom[0] + lastRoom[2]/2
-			stairsY = lastRoom[1] + lastRoom[3]/2
-
-			// Make sure it's a floor tile
-			if mapComp.IsWall(stairsX, stairsY) {
-				// Find the nearest floor tile
-				for r := 1; r < 5 && !stairsPlaced; r++ {
-					for dy := -r; dy <= r && !stairsPlaced; dy++ {
-						for dx := -r; dx <= r && !stairsPlaced; dx++ {
-							nx, ny := stairsX+dx, stairsY+dy
-							if nx >= lastRoom[0] && nx < lastRoom[0]+lastRoom[2] &&
-								ny >= lastRoom[1] && ny < lastRoom[1]+lastRoom[3] &&
-								mapComp.Tiles[ny][nx] == components.TileFloor {
-								stairsX, stairsY = nx, ny
-								stairsPlaced = true
-							}
-						}
-					}
-				}
-			} else {
-				stairsPlaced = true
-			}
+			stairsX, stairsY, stairsPlaced = FindNearestWalkable(mapComp, lastRoom[0]+lastRoom[2]/2, lastRoom[1]+lastRoom[3]/2, isFloor)
 		}
 
 		// Place the stairs if we found a valid position
@@ -550,19 +628,15 @@ func (t *DungeonThemer) placeFeaturePools(mapComp *components.MapComponent, feat
 
 	for i := 0; i < poolCount; i++ {
 		// Find an empty spot for the pool
-		var poolX, poolY int
-		for attempts := 0; attempts < 50; attempts++ {
-			poolX = t.rng.Intn(mapComp.Width-5) + 2
-			poolY = t.rng.Intn(mapComp.Height-5) + 2
-			if !mapComp.IsWall(poolX, poolY) {
-				break
-			}
+		poolX, poolY, found := t.findPoolSpot(mapComp)
+		if !found {
+			continue
 		}
 
 		// Create a small pool (3x3 to 5x5)
 		poolSize := 3 + t.rng.Intn(3)
-		for y := poolY; y < poolY+poolSize && y < mapComp.Height; y++ {
-			for x := poolX; x < poolX+poolSize && x < mapComp.Width; x++ {
+		for y := poolY; y < poolY+poolSize && y < mapComp.Height-1; y++ {
+			for x := poolX; x < poolX+poolSize && x < mapComp.Width-1; x++ {
 				if !mapComp.IsWall(x, y) && t.rng.Intn(100) < 70 { // Make pools irregular
 					mapComp.SetTile(x, y, featureType)
 				}
@@ -571,18 +645,28 @@ func (t *DungeonThemer) placeFeaturePools(mapComp *components.MapComponent, feat
 	}
 }
 
-// addPool adds a water or lava pool to the dungeon
-// DEPRECATED: Use placeFeaturePools instead
-func (t *DungeonThemer) addPool(mapComp *components.MapComponent, tileType int) {
-	// Find an empty spot for the pool
+// findPoolSpot picks a random open tile away from the map's edge to start a pool
+// from, falling back to the open tile nearest the last try
+func (t *DungeonThemer) findPoolSpot(mapComp *components.MapComponent) (int, int, bool) {
 	var poolX, poolY int
 	for attempts := 0; attempts < 50; attempts++ {
 		poolX = t.rng.Intn(mapComp.Width-5) + 2
 		poolY = t.rng.Intn(mapComp.Height-5) + 2
 		if !mapComp.IsWall(poolX, poolY) {
-			break
+			return poolX, poolY, true
 		}
 	}
+	return FindNearestWalkable(mapComp, poolX, poolY, isOpen)
+}
+
+// addPool adds a water or lava pool to the dungeon
+// DEPRECATED: Use placeFeaturePools instead
+func (t *DungeonThemer) addPool(mapComp *components.MapComponent, tileType int) {
+	// Find an empty spot for the pool
+	poolX, poolY, found := t.findPoolSpot(mapComp)
+	if !found {
+		return
+	}
 
 	// Create a small pool (3x3 to 5x5)
 	poolSize := 3 + t.rng.Intn(3)
@@ -689,94 +773,17 @@ func (t *DungeonThemer) generateRandomRoomsAndCorridors(mapComp *components.MapC
 func (t *DungeonThemer) findRoomsInBSPDungeon(mapComp *components.MapComponent) [][4]int {
 	var rooms [][4]int
 
-	// We'll use a "visited" grid to track areas we've already processed
-	visited := make([][]bool, mapComp.Height)
-	for i := range visited {
-		visited[i] = make([]bool, mapComp.Width)
-	}
-
-	// Scan the map for floor tiles that haven't been visited
-	for y := 0; y < mapComp.Height; y++ {
-		for x := 0; x < mapComp.Width; x++ {
-			// Look for floor tiles that haven't been visited yet
-			if (mapComp.Tiles[y][x] == components.TileFloor ||
-				mapComp.Tiles[y][x] == components.TileGrass) && !visited[y][x] {
-				// Found a new room, perform flood fill to find its extent
-				room := t.floodFillRoom(mapComp, x, y, visited)
-
-				// Add this room to our collection if it's a reasonable size
-				if room[2] >= 4 && room[3] >= 4 {
-					rooms = append(rooms, room)
-				}
-			}
+	// Each walkable area is a room candidate
+	for _, region := range ConnectedRegions(mapComp, isWalkable) {
+		// Add this room to our collection if it's a reasonable size
+		if bounds := region.Bounds(mapComp.Width); bounds.Width >= 4 && bounds.Height >= 4 {
+			rooms = append(rooms, bounds.Rect())
 		}
 	}
 
 	return rooms
 }
 
-// floodFillRoom identifies a room's dimensions using flood fill
-func (t *DungeonThemer) floodFillRoom(mapComp *components.MapComponent, startX, startY int, visited [][]bool) [4]int {
-	// Queue for BFS flood fill
-	queue := [][2]int{{startX, startY}}
-	visited[startY][startX] = true
-
-	// Track the room's bounds
-	minX, minY := startX, startY
-	maxX, maxY := startX, startY
-
-	// Directions for neighbor checks (4-way connectivity)
-	dirs := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
-
-	// Process the queue
-	for len(queue) > 0 {
-		// Get the next position
-		pos := queue[0]
-		queue = queue[1:]
-		x, y := pos[0], pos[1]
-
-		// Update bounds
-		if x < minX {
-			minX = x
-		}
-		if x > maxX {
-			maxX = x
-		}
-		if y < minY {
-			minY = y
-		}
-		if y > maxY {
-			maxY = y
-		}
-
-		// Check neighbors
-		for _, dir := range dirs {
-			nx, ny := x+dir[0], y+dir[1]
-
-			// Check bounds
-			if nx < 0 || nx >= mapComp.Width || ny < 0 || ny >= mapComp.Height {
-				continue
-			}
-
-			// If not visited and is a walkable tile, add to queue
-			if !visited[ny][nx] && t.isWalkableForRoomFinding(mapComp.Tiles[ny][nx]) {
-				visited[ny][nx] = true
-				queue = append(queue, [2]int{nx, ny})
-			}
-		}
-	}
-
-	// Return room dimensions [x, y, width, height]
-	return [4]int{minX, minY, maxX - minX + 1, maxY - minY + 1}
-}
-
-// isWalkableForRoomFinding checks if a tile can be considered part of a room
-func (t *DungeonThemer) isWalkableForRoomFinding(tileType int) bool {
-	return tileType == components.TileFloor ||
-		tileType == components.TileGrass ||
-		tileType == components.TileDoor
-}
-
 // addBossMonster adds a boss monster to the dungeon
 func (t *DungeonThemer) addBossMonster(mapComp *components.MapComponent, bossTypes []string) {
 	if len(bossTypes) == 0 {
@@ -0,0 +1,182 @@
+package generation
+
+import (
+	"math"
+
+	"ebiten-rogue/components"
+)
+
+// Region is a set of connected tiles found by a flood fill, keyed by y*width+x
+type Region map[int]bool
+
+// Contains returns whether the region holds the tile at x, y on a map of the given width
+func (r Region) Contains(x, y, width int) bool {
+	return r[y*width+x]
+}
+
+// Bounds returns the smallest rectangle holding every tile of the region
+func (r Region) Bounds(width int) Bounds {
+	if len(r) == 0 {
+		return Bounds{}
+	}
+	minX, minY := math.MaxInt, math.MaxInt
+	maxX, maxY := -1, -1
+	for key := range r {
+		x, y := key%width, key/width
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	return Bounds{X: minX, Y: minY, Width: maxX - minX + 1, Height: maxY - minY + 1}
+}
+
+// Bounds is the bounding rectangle of a region
+type Bounds struct {
+	X, Y, Width, Height int
+}
+
+// Rect returns the bounds as {x, y, width, height}, the form room lists use
+func (b Bounds) Rect() [4]int {
+	return [4]int{b.X, b.Y, b.Width, b.Height}
+}
+
+// Contains returns whether the tile at x, y lies within the bounds
+func (b Bounds) Contains(x, y int) bool {
+	return x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height
+}
+
+// Passable predicates for flood fills. Generators used to each carry their own idea of
+// what counts as open ground; these are the only four that are actually needed.
+
+// isWalkable checks if a tile is easy ground: walkable at plain cost and harmless, so
+// pools don't count as joining the rooms around them
+func isWalkable(tileType int) bool {
+	props := components.GetTileProperties(tileType)
+	return props.IsWalkable && !props.IsHazard && props.MoveCost == 1
+}
+
+// isFloor checks if a tile is bare floor, for finding rooms before features are added
+func isFloor(tileType int) bool {
+	return tileType == components.TileFloor
+}
+
+// isSafe checks if a tile can be walked on without harm, however slowly, so water
+// counts but lava doesn't
+func isSafe(tileType int) bool {
+	props := components.GetTileProperties(tileType)
+	return props.IsWalkable && !props.IsHazard
+}
+
+// isOpen checks if a tile can be walked on at all, including water, lava and stairs
+func isOpen(tileType int) bool {
+	return components.GetTileProperties(tileType).IsWalkable
+}
+
+// FloodFill returns every tile reachable from the start through passable tiles, moving
+// in the four principal directions, along with the region's bounds. The region is
+// empty if the start itself isn't passable.
+func FloodFill(mapComp *components.MapComponent, startX, startY int, passable func(tileType int) bool) (Region, Bounds) {
+	return floodFillWithin(mapComp, startX, startY, Bounds{Width: mapComp.Width, Height: mapComp.Height}, passable)
+}
+
+// floodFillWithin is FloodFill limited to an area of the map
+func floodFillWithin(mapComp *components.MapComponent, startX, startY int, area Bounds, passable func(tileType int) bool) (Region, Bounds) {
+	inArea := func(x, y int) bool {
+		return x >= max(area.X, 0) && x < min(area.X+area.Width, mapComp.Width) &&
+			y >= max(area.Y, 0) && y < min(area.Y+area.Height, mapComp.Height)
+	}
+
+	region := make(Region)
+	if !inArea(startX, startY) || !passable(mapComp.Tiles[startY][startX]) {
+		return region, Bounds{}
+	}
+
+	minX, minY := startX, startY
+	maxX, maxY := startX, startY
+	region[startY*mapComp.Width+startX] = true
+	queue := [][2]int{{startX, startY}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		minX, maxX = min(minX, current[0]), max(maxX, current[0])
+		minY, maxY = min(minY, current[1]), max(maxY, current[1])
+
+		for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			nx, ny := current[0]+dir[0], current[1]+dir[1]
+			key := ny*mapComp.Width + nx
+			if !inArea(nx, ny) || region[key] || !passable(mapComp.Tiles[ny][nx]) {
+				continue
+			}
+			region[key] = true
+			queue = append(queue, [2]int{nx, ny})
+		}
+	}
+
+	return region, Bounds{X: minX, Y: minY, Width: maxX - minX + 1, Height: maxY - minY + 1}
+}
+
+// ConnectedRegions splits the passable tiles of a map into connected regions, in the
+// order their top-left-most tiles are found scanning row by row
+func ConnectedRegions(mapComp *components.MapComponent, passable func(tileType int) bool) []Region {
+	return connectedRegionsWithin(mapComp, Bounds{Width: mapComp.Width, Height: mapComp.Height}, passable)
+}
+
+// connectedRegionsWithin is ConnectedRegions limited to an area of the map
+func connectedRegionsWithin(mapComp *components.MapComponent, area Bounds, passable func(tileType int) bool) []Region {
+	var regions []Region
+	seen := make(Region)
+	for y := max(area.Y, 0); y < min(area.Y+area.Height, mapComp.Height); y++ {
+		for x := max(area.X, 0); x < min(area.X+area.Width, mapComp.Width); x++ {
+			if seen[y*mapComp.Width+x] || !passable(mapComp.Tiles[y][x]) {
+				continue
+			}
+			region, _ := floodFillWithin(mapComp, x, y, area, passable)
+			for key := range region {
+				seen[key] = true
+			}
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// FindNearestWalkable returns the matching tile closest to x, y in a straight line,
+// x, y itself included, searching outward ring by ring. The start may lie off the map;
+// ok is false if no tile matches.
+func FindNearestWalkable(mapComp *components.MapComponent, x, y int, matches func(tileType int) bool) (nearestX, nearestY int, ok bool) {
+	// The farthest ring that still touches the map
+	maxRadius := max(abs(x), abs(mapComp.Width-1-x), abs(y), abs(mapComp.Height-1-y))
+
+	bestDist := math.MaxInt
+	for r := 0; r <= maxRadius; r++ {
+		// No tile on this ring or beyond is nearer than r
+		if r*r >= bestDist {
+			break
+		}
+		for ty := y - r; ty <= y+r; ty++ {
+			if ty < 0 || ty >= mapComp.Height {
+				continue
+			}
+			// Inner rows of the ring only have their two end tiles
+			step := 2 * r
+			if ty == y-r || ty == y+r || r == 0 {
+				step = 1
+			}
+			for tx := x - r; tx <= x+r; tx += step {
+				if tx < 0 || tx >= mapComp.Width || !matches(mapComp.Tiles[ty][tx]) {
+					continue
+				}
+				if dist := (tx-x)*(tx-x) + (ty-y)*(ty-y); dist < bestDist {
+					nearestX, nearestY, bestDist, ok = tx, ty, dist, true
+				}
+			}
+		}
+	}
+	return nearestX, nearestY, ok
+}
+
+// markVisited flags every tile of a region in a visited grid
+func markVisited(visited [][]bool, region Region, width int) {
+	for key := range region {
+		visited[key/width][key%width] = true
+	}
+}
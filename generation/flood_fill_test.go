@@ -0,0 +1,193 @@
+package generation
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+// testTiles maps the characters of a drawn test map to tile types
+var testTiles = map[rune]int{
+	'.': components.TileFloor,
+	'#': components.TileWall,
+	'+': components.TileDoor,
+	'<': components.TileStairsUp,
+	'>': components.TileStairsDown,
+	'~': components.TileWater,
+	'=': components.TileLava,
+}
+
+// parseTestMap builds a map from rows drawn with the characters of testTiles
+func parseTestMap(t *testing.T, rows ...string) *components.MapComponent {
+	t.Helper()
+	mapComp := components.NewMapComponent(len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != mapComp.Width {
+			t.Fatalf("row %d is %d tiles wide, want %d", y, len(row), mapComp.Width)
+		}
+		for x, char := range row {
+			tile, known := testTiles[char]
+			if !known {
+				t.Fatalf("unknown tile %q at (%d,%d)", char, x, y)
+			}
+			mapComp.Tiles[y][x] = tile
+		}
+	}
+	return mapComp
+}
+
+func TestFloodFill(t *testing.T) {
+	mapComp := parseTestMap(t,
+		"...#....",
+		"...#.##.",
+		"####.#..",
+		"#.#..###",
+		"...#.#.#",
+	)
+
+	tests := []struct {
+		name         string
+		x, y         int
+		passable     func(int) bool
+		wantTiles    int
+		wantBounds   Bounds
+		wantContains [][2]int
+		wantMissing  [][2]int
+	}{
+		{
+			name: "corner pocket", x: 0, y: 0, passable: isFloor,
+			wantTiles: 6, wantBounds: Bounds{X: 0, Y: 0, Width: 3, Height: 2},
+			wantContains: [][2]int{{2, 1}},
+			wantMissing:  [][2]int{{4, 0}},
+		},
+		{
+			name: "winding passage from the far corner", x: 7, y: 0, passable: isFloor,
+			wantTiles: 12, wantBounds: Bounds{X: 3, Y: 0, Width: 5, Height: 5},
+			wantContains: [][2]int{{4, 0}, {3, 3}, {6, 2}},
+			wantMissing:  [][2]int{{2, 4}, {6, 4}},
+		},
+		{
+			name: "diagonals don't join", x: 1, y: 3, passable: isFloor,
+			wantTiles: 4, wantBounds: Bounds{X: 0, Y: 3, Width: 3, Height: 2},
+			wantMissing: [][2]int{{0, 1}},
+		},
+		{name: "walled in", x: 6, y: 4, passable: isFloor, wantTiles: 1, wantBounds: Bounds{X: 6, Y: 4, Width: 1, Height: 1}},
+		{name: "start in a wall", x: 3, y: 0, passable: isFloor},
+		{name: "start off the map", x: -1, y: 2, passable: isFloor},
+		{name: "start beyond the far edge", x: 8, y: 4, passable: isFloor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, bounds := FloodFill(mapComp, tt.x, tt.y, tt.passable)
+			if len(region) != tt.wantTiles {
+				t.Errorf("region has %d tiles, want %d", len(region), tt.wantTiles)
+			}
+			if bounds != tt.wantBounds {
+				t.Errorf("bounds = %+v, want %+v", bounds, tt.wantBounds)
+			}
+			if got := region.Bounds(mapComp.Width); got != tt.wantBounds {
+				t.Errorf("Region.Bounds() = %+v, want %+v", got, tt.wantBounds)
+			}
+			for _, tile := range tt.wantContains {
+				if !region.Contains(tile[0], tile[1], mapComp.Width) {
+					t.Errorf("region doesn't contain (%d,%d)", tile[0], tile[1])
+				}
+			}
+			for _, tile := range tt.wantMissing {
+				if region.Contains(tile[0], tile[1], mapComp.Width) {
+					t.Errorf("region contains (%d,%d)", tile[0], tile[1])
+				}
+			}
+		})
+	}
+}
+
+func TestFloodFillPassable(t *testing.T) {
+	mapComp := parseTestMap(t,
+		"..~..=..",
+	)
+	for _, tt := range []struct {
+		name      string
+		passable  func(int) bool
+		wantTiles int
+	}{
+		{"easy ground stops at water", isWalkable, 2},
+		{"safe ground wades through water", isSafe, 5},
+		{"open ground crosses lava", isOpen, 8},
+	} {
+		if region, _ := FloodFill(mapComp, 0, 0, tt.passable); len(region) != tt.wantTiles {
+			t.Errorf("%s: region has %d tiles, want %d", tt.name, len(region), tt.wantTiles)
+		}
+	}
+}
+
+func TestConnectedRegions(t *testing.T) {
+	mapComp := parseTestMap(t,
+		"..#..",
+		"..#.#",
+		"###..",
+		".#...",
+	)
+	regions := ConnectedRegions(mapComp, isFloor)
+
+	want := []struct {
+		tiles  int
+		bounds Bounds
+	}{
+		{4, Bounds{X: 0, Y: 0, Width: 2, Height: 2}},
+		{8, Bounds{X: 2, Y: 0, Width: 3, Height: 4}},
+		{1, Bounds{X: 0, Y: 3, Width: 1, Height: 1}},
+	}
+	if len(regions) != len(want) {
+		t.Fatalf("found %d regions, want %d", len(regions), len(want))
+	}
+	for i, region := range regions {
+		if len(region) != want[i].tiles || region.Bounds(mapComp.Width) != want[i].bounds {
+			t.Errorf("region %d has %d tiles in %+v, want %d in %+v", i, len(region), region.Bounds(mapComp.Width), want[i].tiles, want[i].bounds)
+		}
+	}
+
+	if regions := ConnectedRegions(parseTestMap(t, "###", "###"), isFloor); len(regions) != 0 {
+		t.Errorf("a map of solid rock has %d regions, want none", len(regions))
+	}
+}
+
+func TestFindNearestWalkable(t *testing.T) {
+	// Solid rock with floor only where each case puts it
+	rockWith := func(floors ...[2]int) *components.MapComponent {
+		mapComp := components.NewMapComponent(11, 11)
+		for y := 0; y < mapComp.Height; y++ {
+			for x := 0; x < mapComp.Width; x++ {
+				mapComp.Tiles[y][x] = components.TileWall
+			}
+		}
+		for _, floor := range floors {
+			mapComp.Tiles[floor[1]][floor[0]] = components.TileFloor
+		}
+		return mapComp
+	}
+
+	tests := []struct {
+		name   string
+		floors [][2]int
+		x, y   int
+		want   [2]int
+		wantOK bool
+	}{
+		{"start matches", [][2]int{{5, 5}, {5, 6}}, 5, 5, [2]int{5, 5}, true},
+		{"nothing matches", nil, 5, 5, [2]int{}, false},
+		{"diagonal neighbour beats two steps straight", [][2]int{{5, 7}, {6, 6}}, 5, 5, [2]int{6, 6}, true},
+		{"straight line on a farther ring beats a nearer ring's corner", [][2]int{{8, 8}, {5, 9}}, 5, 5, [2]int{5, 9}, true},
+		{"ties go to the first found scanning row by row", [][2]int{{6, 5}, {4, 5}, {5, 6}}, 5, 5, [2]int{4, 5}, true},
+		{"start off the map", [][2]int{{0, 3}, {0, 9}}, -3, 2, [2]int{0, 3}, true},
+		{"far corner of the map", [][2]int{{10, 10}}, 0, 0, [2]int{10, 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, ok := FindNearestWalkable(rockWith(tt.floors...), tt.x, tt.y, isFloor)
+			if ok != tt.wantOK || (ok && [2]int{x, y} != tt.want) {
+				t.Errorf("FindNearestWalkable = (%d,%d) %v, want %v %v", x, y, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
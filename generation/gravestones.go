@@ -0,0 +1,33 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// gravestoneChance is how often a floor gets the grave of a previous character
+const gravestoneChance = 0.3
+
+// addGravestone places the grave of a character who died around this depth, if the
+// graveyard has one to offer
+func (t *DungeonThemer) addGravestone(mapComp *components.MapComponent, floorID ecs.EntityID, depth int) bool {
+	grave := t.graveyard.PickGrave(depth, t.rng)
+	if grave == nil {
+		return false
+	}
+
+	// Bury them where they fell if that spot is open floor on this map too
+	x, y := grave.X, grave.Y
+	if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height || mapComp.Tiles[y][x] != components.TileFloor {
+		x, y = t.findEmptyPosition(mapComp)
+	}
+	t.entitySpawner.SetSpawnMapID(floorID)
+	t.entitySpawner.CreateGravestone(x, y, grave)
+
+	if t.logMessage != nil {
+		t.logMessage(fmt.Sprintf("Added gravestone at (%d,%d): %s", x, y, grave.Epitaph()))
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/systems"
+)
+
+// RepairHazardLockedAreas finds ground that can only be reached by crossing hazards,
+// such as a room ringed by lava pools, and paves the fewest hazard tiles needed to join
+// it to the largest safe area. Areas that are walled off entirely are left alone, since
+// crossing a hazard wouldn't reach them either. Returns how many areas were repaired.
+func RepairHazardLockedAreas(mapComp *components.MapComponent) int {
+	regions := ConnectedRegions(mapComp, isSafe)
+	if len(regions) < 2 {
+		return 0
+	}
+
+	mainIndex := 0
+	for i, region := range regions {
+		if len(region) > len(regions[mainIndex]) {
+			mainIndex = i
+		}
+	}
+	reachable := make(Region, len(regions[mainIndex]))
+	for key := range regions[mainIndex] {
+		reachable[key] = true
+	}
+
+	repaired := 0
+	for i, region := range regions {
+		if i == mainIndex || reachable[anyKey(region)] {
+			continue
+		}
+		crossing, found := hazardCrossing(mapComp, region, reachable)
+		if !found {
+			continue
+		}
+		for _, key := range crossing {
+			mapComp.SetTile(key%mapComp.Width, key/mapComp.Width, components.TileFloor)
+			reachable[key] = true
+		}
+		// Anything the repaired area touched is now reachable too, so later areas can
+		// join through it
+		joined, _ := FloodFill(mapComp, anyKey(region)%mapComp.Width, anyKey(region)/mapComp.Width, isSafe)
+		for key := range joined {
+			reachable[key] = true
+		}
+
+		bounds := region.Bounds(mapComp.Width)
+		systems.GetDebugLog().Add(fmt.Sprintf("Hazard-locked area of %d tiles at (%d,%d) joined by paving %d hazard tiles",
+			len(region), bounds.X, bounds.Y, len(crossing)))
+		repaired++
+	}
+	return repaired
+}
+
+// hazardCrossing returns the hazard tiles on the path from a region to the reachable
+// tiles that crosses the fewest of them, as keys like a Region's. It searches open tiles
+// breadth first one hazard count at a time: safe steps stay in the current wave, hazard
+// steps wait for the next. Returns false if no open path exists at all.
+func hazardCrossing(mapComp *components.MapComponent, region, reachable Region) ([]int, bool) {
+	const unvisited = -1
+	size := mapComp.Width * mapComp.Height
+	cost := make([]int, size)
+	parent := make([]int, size)
+	for i := range cost {
+		cost[i], parent[i] = unvisited, unvisited
+	}
+
+	var wave, nextWave []int
+	for key := range region {
+		cost[key] = 0
+		wave = append(wave, key)
+	}
+
+	for len(wave) > 0 || len(nextWave) > 0 {
+		if len(wave) == 0 {
+			wave, nextWave = nextWave, nil
+		}
+		current := wave[0]
+		wave = wave[1:]
+		if reachable[current] {
+			var crossing []int
+			for key := current; parent[key] != unvisited; key = parent[key] {
+				if !isSafe(mapComp.Tiles[key/mapComp.Width][key%mapComp.Width]) {
+					crossing = append(crossing, key)
+				}
+			}
+			return crossing, true
+		}
+
+		x, y := current%mapComp.Width, current/mapComp.Width
+		for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if !mapComp.InBounds(nx, ny) || !isOpen(mapComp.Tiles[ny][nx]) {
+				continue
+			}
+			next := ny*mapComp.Width + nx
+			step := 0
+			if !isSafe(mapComp.Tiles[ny][nx]) {
+				step = 1
+			}
+			if cost[next] != unvisited && cost[next] <= cost[current]+step {
+				continue
+			}
+			cost[next], parent[next] = cost[current]+step, current
+			if step == 0 {
+				wave = append(wave, next)
+			} else {
+				nextWave = append(nextWave, next)
+			}
+		}
+	}
+	return nil, false
+}
+
+// anyKey returns one of a region's tiles
+func anyKey(region Region) int {
+	for key := range region {
+		return key
+	}
+	return -1
+}
@@ -0,0 +1,135 @@
+package generation
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+// countTiles returns how many tiles of a type a map has
+func countTiles(mapComp *components.MapComponent, tileType int) int {
+	count := 0
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			if mapComp.Tiles[y][x] == tileType {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// safelyReachable returns whether one tile can be walked to from another without
+// stepping on a hazard
+func safelyReachable(mapComp *components.MapComponent, fromX, fromY, toX, toY int) bool {
+	region, _ := FloodFill(mapComp, fromX, fromY, isSafe)
+	return region.Contains(toX, toY, mapComp.Width)
+}
+
+func TestRepairHazardLockedAreas(t *testing.T) {
+	tests := []struct {
+		name          string
+		rows          []string
+		wantRepaired  int
+		wantPaved     int  // Lava tiles turned to floor
+		wantReachable bool // Whether the room can be reached afterwards
+	}{
+		{
+			name: "room ringed by lava",
+			rows: []string{
+				"############",
+				"#..........#",
+				"#..=====...#",
+				"#..=...=...#",
+				"#..=...=...#",
+				"#..=====...#",
+				"#..........#",
+				"############",
+			},
+			wantRepaired: 1, wantPaved: 1, wantReachable: true,
+		},
+		{
+			name: "the thinnest side of the ring is paved",
+			rows: []string{
+				"############",
+				"#..........#",
+				"#.=======..#",
+				"#.==...==..#",
+				"#.==...=...#",
+				"#.=======..#",
+				"#..........#",
+				"############",
+			},
+			wantRepaired: 1, wantPaved: 1, wantReachable: true,
+		},
+		{
+			name: "room ringed by water is already reachable",
+			rows: []string{
+				"############",
+				"#..........#",
+				"#..~~~~~...#",
+				"#..~...~...#",
+				"#..~...~...#",
+				"#..~~~~~...#",
+				"#..........#",
+				"############",
+			},
+			wantReachable: true,
+		},
+		{
+			name: "walled off room is left alone",
+			rows: []string{
+				"############",
+				"#..........#",
+				"#..#####...#",
+				"#..#...#...#",
+				"#..#...#...#",
+				"#..#####...#",
+				"#..........#",
+				"############",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapComp := parseTestMap(t, tt.rows...)
+			lavaBefore := countTiles(mapComp, components.TileLava)
+
+			if repaired := RepairHazardLockedAreas(mapComp); repaired != tt.wantRepaired {
+				t.Errorf("repaired %d areas, want %d", repaired, tt.wantRepaired)
+			}
+			if paved := lavaBefore - countTiles(mapComp, components.TileLava); paved != tt.wantPaved {
+				t.Errorf("paved %d lava tiles, want %d", paved, tt.wantPaved)
+			}
+			if reachable := safelyReachable(mapComp, 1, 1, 5, 3); reachable != tt.wantReachable {
+				t.Errorf("room reachable from outside = %v, want %v", reachable, tt.wantReachable)
+			}
+		})
+	}
+}
+
+func TestRepairHazardLockedAreasJoinsThroughRepairedAreas(t *testing.T) {
+	// The inner room can only be reached through the outer one, which is itself locked
+	mapComp := parseTestMap(t,
+		"##############",
+		"#............#",
+		"#.=========..#",
+		"#.=.......=..#",
+		"#.=.=====.=..#",
+		"#.=.=...=.=..#",
+		"#.=.=====.=..#",
+		"#.=.......=..#",
+		"#.=========..#",
+		"#............#",
+		"##############",
+	)
+	if repaired := RepairHazardLockedAreas(mapComp); repaired != 2 {
+		t.Errorf("repaired %d areas, want 2", repaired)
+	}
+	if !safelyReachable(mapComp, 1, 1, 6, 5) {
+		t.Error("the inner room can't be reached after the repair")
+	}
+	if !safelyReachable(mapComp, 1, 1, 3, 3) {
+		t.Error("the outer room can't be reached after the repair")
+	}
+}
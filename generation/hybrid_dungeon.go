@@ -0,0 +1,135 @@
+package generation
+
+import (
+	"ebiten-rogue/components"
+)
+
+const (
+	hybridCaveMinSize    = 10   // Rooms at least this wide and tall get a cave interior
+	hybridCaveFill       = 0.45 // Chance a tile of a cave room starts out as rock
+	hybridCaveIterations = 4    // Smoothing passes run over each cave room
+	hybridMinPocket      = 8    // Open areas smaller than this are filled in rather than joined up
+)
+
+// GenerateHybridDungeon lays out rooms and corridors with BSP for the size, then
+// grows caves inside the large rooms with cellular automata, so structured halls
+// open into organic caverns. The small rooms are left as they are and are the only
+// ones Rooms returns afterwards, as the caves have no room for prefabs.
+func (g *DungeonGenerator) GenerateHybridDungeon(mapComp *components.MapComponent, size DungeonSize) {
+	switch size {
+	case SizeSmall:
+		g.GenerateSmallBSPDungeon(mapComp)
+	case SizeLarge:
+		g.GenerateLargeBSPDungeon(mapComp)
+	case SizeHuge:
+		g.GenerateGiantBSPDungeon(mapComp)
+	default: // SizeNormal
+		g.GenerateBSPDungeon(mapComp)
+	}
+
+	// The automata only count plain walls, so the box drawing comes off until the end
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			if IsAnyWallType(mapComp.Tiles[y][x]) {
+				mapComp.Tiles[y][x] = components.TileWall
+			}
+		}
+	}
+
+	var structured [][4]int
+	for _, room := range g.rooms {
+		if room[2] >= hybridCaveMinSize && room[3] >= hybridCaveMinSize {
+			g.caveRoom(mapComp, room)
+		} else {
+			structured = append(structured, room)
+		}
+	}
+	g.rooms = structured
+
+	// Fill the crumbs of floor the automata left behind, so the connectivity pass
+	// doesn't tunnel out to every one of them
+	for _, region := range ConnectedRegions(mapComp, isWalkable) {
+		if len(region) >= hybridMinPocket {
+			continue
+		}
+		for index := range region {
+			x, y := index%mapComp.Width, index/mapComp.Width
+			if mapComp.Tiles[y][x] == components.TileFloor {
+				mapComp.Tiles[y][x] = components.TileWall
+			}
+		}
+	}
+
+	g.verifyGlobalConnectivity(mapComp)
+	g.applyWallTypes(mapComp)
+}
+
+// caveRoom turns the inside of a room into a cave. Tiles on the room's edge where a
+// corridor comes in are kept open, so the corridors still lead somewhere.
+func (g *DungeonGenerator) caveRoom(mapComp *components.MapComponent, room [4]int) {
+	roomX, roomY, width, height := room[0], room[1], room[2], room[3]
+	inRoom := func(x, y int) bool {
+		return x >= roomX && x < roomX+width && y >= roomY && y < roomY+height
+	}
+
+	// Entrances are edge tiles with open ground just outside the room
+	spared := make(map[[2]int]bool)
+	for y := roomY; y < roomY+height; y++ {
+		for x := roomX; x < roomX+width; x++ {
+			for _, d := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+				nx, ny := x+d[0], y+d[1]
+				if inRoom(nx, ny) || nx < 0 || nx >= mapComp.Width || ny < 0 || ny >= mapComp.Height {
+					continue
+				}
+				if isWalkable(mapComp.Tiles[ny][nx]) {
+					spared[[2]int{x, y}] = true
+				}
+			}
+		}
+	}
+
+	// Seed the room with rock, leaving doors and anything else that isn't floor
+	carvable := func(x, y int) bool {
+		if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height || spared[[2]int{x, y}] {
+			return false
+		}
+		tile := mapComp.Tiles[y][x]
+		return tile == components.TileFloor || tile == components.TileWall
+	}
+	for y := roomY; y < roomY+height; y++ {
+		for x := roomX; x < roomX+width; x++ {
+			if carvable(x, y) && mapComp.Tiles[y][x] == components.TileFloor && g.rng.Float64() < hybridCaveFill {
+				mapComp.Tiles[y][x] = components.TileWall
+			}
+		}
+	}
+
+	// Smooth it into caves with the same rule as the cellular generator
+	next := make([][]int, height)
+	for i := range next {
+		next[i] = make([]int, width)
+	}
+	for iteration := 0; iteration < hybridCaveIterations; iteration++ {
+		for y := roomY; y < roomY+height; y++ {
+			for x := roomX; x < roomX+width; x++ {
+				if !carvable(x, y) {
+					continue
+				}
+				next[y-roomY][x-roomX] = mapComp.Tiles[y][x]
+				walls := g.countAdjacentWalls(mapComp, x, y)
+				if walls > 4 {
+					next[y-roomY][x-roomX] = components.TileWall
+				} else if walls < 4 {
+					next[y-roomY][x-roomX] = components.TileFloor
+				}
+			}
+		}
+		for y := roomY; y < roomY+height; y++ {
+			for x := roomX; x < roomX+width; x++ {
+				if carvable(x, y) {
+					mapComp.Tiles[y][x] = next[y-roomY][x-roomX]
+				}
+			}
+		}
+	}
+}
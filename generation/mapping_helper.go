@@ -2,7 +2,7 @@ package generation
 
 import (
 	"ebiten-rogue/components"
-	"fmt"
+	"ebiten-rogue/systems"
 )
 
 // init sets up function references to avoid import cycles between packages
@@ -14,7 +14,7 @@ func init() {
 	components.IsFloorTypeFunc = IsFloorType
 
 	// Log that we've initialized the mapping helper
-	fmt.Println("INFO: mapping_helper.go initialized - Wall detection functions are now available")
+	systems.GetDebugLog().Add("INFO: mapping_helper.go initialized - Wall detection functions are now available")
 }
 
 // Wall connection constants used for box drawing walls
@@ -25,6 +25,26 @@ const (
 	WallConnectLeft   = 8
 )
 
+// edgeWalls is whether the space past the edge of the map counts as wall when
+// working out how walls connect
+var edgeWalls = true
+
+// SetEdgeWalls sets whether walls on the border of a map connect into the space past
+// its edge. On, they join it as tees and crosses; off, they end cleanly at the edge,
+// which looks better on dungeons that fill the whole map.
+func SetEdgeWalls(connect bool) {
+	edgeWalls = connect
+}
+
+// connectsTo reports whether a wall connects towards a tile, by the given test for
+// tiles and the edge wall setting for positions off the map
+func connectsTo(mapComp *components.MapComponent, x, y int, isWall func(tileType int) bool) bool {
+	if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height {
+		return edgeWalls
+	}
+	return isWall(mapComp.Tiles[y][x])
+}
+
 // Box drawing wall tile lookup table
 var WallTileLookup = map[int]int{
 	0:  components.TileWall,            // No connections (isolated wall)
@@ -97,16 +117,19 @@ func CalculateWallMask(mapComp *components.MapComponent, x, y int) int {
 	mask := 0
 
 	// Check for walls in each direction and set appropriate bits
-	if y > 0 && IsWallOrDoor(mapComp, x, y-1) { // Top
+	isWallOrDoor := func(tileType int) bool {
+		return IsAnyWallType(tileType) || tileType == components.TileDoor
+	}
+	if connectsTo(mapComp, x, y-1, isWallOrDoor) { // Top
 		mask |= WallConnectTop
 	}
-	if x < mapComp.Width-1 && IsWallOrDoor(mapComp, x+1, y) { // Right
+	if connectsTo(mapComp, x+1, y, isWallOrDoor) { // Right
 		mask |= WallConnectRight
 	}
-	if y < mapComp.Height-1 && IsWallOrDoor(mapComp, x, y+1) { // Bottom
+	if connectsTo(mapComp, x, y+1, isWallOrDoor) { // Bottom
 		mask |= WallConnectBottom
 	}
-	if x > 0 && IsWallOrDoor(mapComp, x-1, y) { // Left
+	if connectsTo(mapComp, x-1, y, isWallOrDoor) { // Left
 		mask |= WallConnectLeft
 	}
 
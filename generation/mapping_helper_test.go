@@ -0,0 +1,87 @@
+package generation
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+func TestConnectsTo(t *testing.T) {
+	defer SetEdgeWalls(edgeWalls)
+	mapComp := parseTestMap(t,
+		"##",
+		"#.",
+	)
+	isWall := func(tileType int) bool { return tileType == components.TileWall }
+
+	for _, connect := range []bool{true, false} {
+		SetEdgeWalls(connect)
+		for _, off := range [][2]int{{-1, 0}, {0, -1}, {2, 1}, {1, 2}} {
+			if got := connectsTo(mapComp, off[0], off[1], isWall); got != connect {
+				t.Errorf("with edge walls %v, connectsTo off the map at (%d,%d) = %v", connect, off[0], off[1], got)
+			}
+		}
+		if !connectsTo(mapComp, 1, 0, isWall) {
+			t.Errorf("with edge walls %v, a wall on the map doesn't connect", connect)
+		}
+		if connectsTo(mapComp, 1, 1, isWall) {
+			t.Errorf("with edge walls %v, floor on the map connects", connect)
+		}
+	}
+}
+
+func TestEdgeWallsOnABorderedMap(t *testing.T) {
+	defer SetEdgeWalls(edgeWalls)
+	rows := []string{
+		"#####",
+		"#...#",
+		"#...#",
+		"#####",
+	}
+
+	tests := []struct {
+		name    string
+		connect bool
+		want    map[[2]int]int
+	}{
+		{
+			name: "joined into the edge", connect: true,
+			want: map[[2]int]int{
+				{2, 0}: components.TileWallTeeBottom,
+				{0, 1}: components.TileWallTeeRight,
+				{4, 2}: components.TileWallTeeLeft,
+				{2, 3}: components.TileWallTeeTop,
+			},
+		},
+		{
+			name: "ending at the edge", connect: false,
+			want: map[[2]int]int{
+				{2, 0}: components.TileWallHorizontal,
+				{0, 1}: components.TileWallVertical,
+				{4, 2}: components.TileWallVertical,
+				{2, 3}: components.TileWallHorizontal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetEdgeWalls(tt.connect)
+			mapComp := parseTestMap(t, rows...)
+			ApplyBoxDrawingWalls(mapComp)
+			for tile, want := range tt.want {
+				if got := mapComp.Tiles[tile[1]][tile[0]]; got != want {
+					t.Errorf("wall at (%d,%d) = tile %d, want %d", tile[0], tile[1], got, want)
+				}
+			}
+			// Corners only touch the floor diagonally, so they stay plain walls
+			for _, corner := range [][2]int{{0, 0}, {4, 0}, {0, 3}, {4, 3}} {
+				if got := mapComp.Tiles[corner[1]][corner[0]]; got != components.TileWall {
+					t.Errorf("corner at (%d,%d) = tile %d, want a plain wall", corner[0], corner[1], got)
+				}
+			}
+			if mapComp.Tiles[1][2] != components.TileFloor {
+				t.Error("the floor was drawn over")
+			}
+		})
+	}
+}
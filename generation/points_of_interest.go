@@ -0,0 +1,179 @@
+package generation
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/spawners"
+)
+
+// POISpacing is the fewest tiles between two points of interest, or between one and a
+// dungeon entrance or station
+const POISpacing = 12
+
+// poiMinDistance keeps points of interest out of sight of the central station
+const poiMinDistance = 15
+
+// poiRule says how many points of interest of a kind to place, and on which biomes.
+// Mountains and rivers are in no rule, so nothing is ever placed on them.
+type poiRule struct {
+	kind   components.PointOfInterestKind
+	biomes []int
+	count  int
+}
+
+// poiRules are placed in order, so the desert's buried cache goes first and always
+// finds room
+var poiRules = []poiRule{
+	{components.POICache, []int{components.TileDesert}, 1},
+	{components.POIRuin, []int{components.TileWasteland, components.TileDesert, components.TileDarkForest}, 3},
+	{components.POICache, []int{components.TileWasteland}, 2},
+	{components.POILair, []int{components.TileDarkForest, components.TileWasteland}, 2},
+}
+
+// poiCache is the container a cache on a biome is filled from, and what it's called
+type poiCache struct {
+	TemplateID string
+	Name       string
+}
+
+// poiCaches are the caches found on each biome. The desert's is buried with early
+// gear, the wasteland's are supply drops.
+var poiCaches = map[int]poiCache{
+	components.TileDesert:    {TemplateID: "buried_cache", Name: "Buried Cache"},
+	components.TileWasteland: {TemplateID: "supply_cache", Name: "Supply Cache"},
+}
+
+// lairBosses are the world bosses lairs on each biome are built around
+var lairBosses = map[int]string{
+	components.TileWasteland:  "dragon",
+	components.TileDarkForest: "troll",
+}
+
+// PlacePointsOfInterest picks the spots for the world map's ruins, caches and lairs.
+// Each kind goes on the biomes its rule allows, out of sight of the central station
+// and at least POISpacing tiles from every other point of interest, dungeon entrance
+// and station. A kind that runs out of room gets fewer places.
+func PlacePointsOfInterest(worldMap *components.MapComponent, rng *rand.Rand) []components.PointOfInterest {
+	names := NewNameGenerator(rng.Int63())
+	centerX, centerY := worldMap.Width/2, worldMap.Height/2
+
+	// Entrances and stations already on the map need room too
+	var taken [][2]int
+	for y := 0; y < worldMap.Height; y++ {
+		for x := 0; x < worldMap.Width; x++ {
+			_, isTransition := worldMap.GetTransition(x, y)
+			if isTransition || worldMap.Tiles[y][x] == components.TileSubstation {
+				taken = append(taken, [2]int{x, y})
+			}
+		}
+	}
+	spaced := func(x, y int) bool {
+		for _, spot := range taken {
+			if max(abs(x-spot[0]), abs(y-spot[1])) < POISpacing {
+				return false
+			}
+		}
+		return true
+	}
+
+	var places []components.PointOfInterest
+	for _, rule := range poiRules {
+		for i := 0; i < rule.count; i++ {
+			var candidates [][2]int
+			for y := 0; y < worldMap.Height; y++ {
+				for x := 0; x < worldMap.Width; x++ {
+					if !slices.Contains(rule.biomes, worldMap.Tiles[y][x]) || max(abs(x-centerX), abs(y-centerY)) < poiMinDistance {
+						continue
+					}
+					if spaced(x, y) {
+						candidates = append(candidates, [2]int{x, y})
+					}
+				}
+			}
+			if len(candidates) == 0 {
+				break
+			}
+
+			spot := candidates[rng.Intn(len(candidates))]
+			taken = append(taken, spot)
+			biome := worldMap.Tiles[spot[1]][spot[0]]
+			places = append(places, components.PointOfInterest{
+				Kind:  rule.kind,
+				Name:  poiName(rule.kind, biome, names),
+				X:     spot[0],
+				Y:     spot[1],
+				Biome: biome,
+			})
+		}
+	}
+	return places
+}
+
+// poiName names a point of interest of a kind on a biome
+func poiName(kind components.PointOfInterestKind, biome int, names *NameGenerator) string {
+	switch kind {
+	case components.POIRuin:
+		return "Ruins of " + names.Name()
+	case components.POILair:
+		return names.Name() + " Lair"
+	default:
+		return poiCaches[biome].Name
+	}
+}
+
+// AddPointsOfInterest places ruins, caches and lairs on the world map and stores them
+// on the map entity in a PointsOfInterestComponent. Ruins lead down to a small dungeon
+// of their own and lairs to a world boss's den; caches are a container on the surface
+// that's empty once looted. Returns the floors of every dungeon added.
+func (t *DungeonThemer) AddPointsOfInterest(worldMapEntity *ecs.Entity, rng *rand.Rand) []*ecs.Entity {
+	mapComp, exists := t.world.GetComponent(worldMapEntity.ID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	worldMap := mapComp.(*components.MapComponent)
+	centerX, centerY := worldMap.Width/2, worldMap.Height/2
+	itemSpawner := spawners.NewItemSpawner(t.world, t.templateManager)
+	itemSpawner.SetSpawnMapID(worldMapEntity.ID)
+
+	pois := &components.PointsOfInterestComponent{Places: PlacePointsOfInterest(worldMap, rng)}
+	var floors []*ecs.Entity
+	for _, place := range pois.Places {
+		// Like biome dungeons, places further out are more dangerous
+		level := 1 + max(abs(place.X-centerX), abs(place.Y-centerY))/50
+		switch place.Kind {
+		case components.POIRuin:
+			floors = append(floors, t.generateSite(worldMapEntity, place, level, "")...)
+		case components.POILair:
+			floors = append(floors, t.generateSite(worldMapEntity, place, level+1, lairBosses[place.Biome])...)
+		case components.POICache:
+			_, err := itemSpawner.CreateContainer(place.X, place.Y, poiCaches[place.Biome].TemplateID, nil)
+			if err != nil && t.logMessage != nil {
+				t.logMessage(fmt.Sprintf("Warning: %s at (%d,%d) failed: %v", place.Name, place.X, place.Y, err))
+			}
+		}
+	}
+
+	t.world.AddComponent(worldMapEntity.ID, components.PointsOfInterest, pois)
+	return floors
+}
+
+// generateSite generates the single floor beneath a ruin or lair, named after the
+// place so the stairs down announce it
+func (t *DungeonThemer) generateSite(worldMapEntity *ecs.Entity, place components.PointOfInterest, level int, bossType string) []*ecs.Entity {
+	config := t.BiomeDungeonConfiguration(place.Biome, level)
+	config.Size = SizeSmall
+	config.TotalFloors = 1
+	config.BossType = bossType
+
+	floors := t.generateSurfaceDungeon(worldMapEntity, place.X, place.Y, config)
+	if len(floors) > 0 {
+		if themeComp, exists := t.world.GetComponent(floors[0].ID, components.FloorTheme); exists {
+			themeComp.(*components.FloorThemeComponent).Name = place.Name
+		}
+	}
+	return floors
+}
@@ -0,0 +1,124 @@
+package generation
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+// poiTestMap is a world with every biome on it: desert to the west, wasteland to the
+// east, dark forest along the south, a mountain range to the north and a river down
+// the middle. A station sits in the wasteland.
+func poiTestMap() *components.MapComponent {
+	worldMap := components.NewMapComponent(160, 160)
+	for y := 0; y < worldMap.Height; y++ {
+		for x := 0; x < worldMap.Width; x++ {
+			switch {
+			case y < 30:
+				worldMap.Tiles[y][x] = components.TileMountains
+			case y >= 120:
+				worldMap.Tiles[y][x] = components.TileDarkForest
+			case x < 80:
+				worldMap.Tiles[y][x] = components.TileDesert
+			default:
+				worldMap.Tiles[y][x] = components.TileWasteland
+			}
+		}
+		worldMap.Tiles[y][80] = components.TileRiver
+	}
+	worldMap.Tiles[60][130] = components.TileSubstation
+	return worldMap
+}
+
+func TestPlacePointsOfInterest(t *testing.T) {
+	worldMap := poiTestMap()
+	places := PlacePointsOfInterest(worldMap, rand.New(rand.NewSource(7)))
+
+	want := 0
+	for _, rule := range poiRules {
+		want += rule.count
+	}
+	if len(places) != want {
+		t.Fatalf("placed %d points of interest, want %d", len(places), want)
+	}
+	if first := places[0]; first.Kind != components.POICache || first.Biome != components.TileDesert || first.Name != "Buried Cache" {
+		t.Errorf("first place = %+v, want the desert's buried cache", first)
+	}
+
+	allowed := make(map[components.PointOfInterestKind][]int)
+	for _, rule := range poiRules {
+		allowed[rule.kind] = append(allowed[rule.kind], rule.biomes...)
+	}
+	centerX, centerY := worldMap.Width/2, worldMap.Height/2
+	for i, place := range places {
+		tile := worldMap.Tiles[place.Y][place.X]
+		if tile == components.TileMountains || tile == components.TileRiver {
+			t.Errorf("%s placed on tile type %d", place.Name, tile)
+		}
+		if tile != place.Biome || !slices.Contains(allowed[place.Kind], tile) {
+			t.Errorf("%s placed on biome %d (recorded %d), not one its kind allows", place.Name, tile, place.Biome)
+		}
+		if max(abs(place.X-centerX), abs(place.Y-centerY)) < poiMinDistance {
+			t.Errorf("%s at (%d,%d) is in sight of the central station", place.Name, place.X, place.Y)
+		}
+		if max(abs(place.X-130), abs(place.Y-60)) < POISpacing {
+			t.Errorf("%s at (%d,%d) crowds the station", place.Name, place.X, place.Y)
+		}
+		for _, other := range places[i+1:] {
+			if max(abs(place.X-other.X), abs(place.Y-other.Y)) < POISpacing {
+				t.Errorf("%s at (%d,%d) and %s at (%d,%d) are closer than %d tiles",
+					place.Name, place.X, place.Y, other.Name, other.X, other.Y, POISpacing)
+			}
+		}
+	}
+}
+
+func TestPlacePointsOfInterestSameSeed(t *testing.T) {
+	first := PlacePointsOfInterest(poiTestMap(), rand.New(rand.NewSource(42)))
+	second := PlacePointsOfInterest(poiTestMap(), rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("the same seed placed points of interest differently:\n%+v\n%+v", first, second)
+	}
+}
+
+func TestPlacePointsOfInterestOutOfRoom(t *testing.T) {
+	// A world of nothing but mountains has nowhere to put anything
+	worldMap := components.NewMapComponent(60, 60)
+	for y := range worldMap.Tiles {
+		for x := range worldMap.Tiles[y] {
+			worldMap.Tiles[y][x] = components.TileMountains
+		}
+	}
+	if places := PlacePointsOfInterest(worldMap, rand.New(rand.NewSource(1))); len(places) != 0 {
+		t.Errorf("placed %d points of interest on bare mountains, want none", len(places))
+	}
+}
+
+func TestPlaceSubstationWorkbenches(t *testing.T) {
+	worldMap := poiTestMap()
+
+	// A second station hemmed in by mountains to the north, track to the west and
+	// east and the river below has no ground to spare
+	worldMap.Tiles[30][40] = components.TileSubstation
+	for x := 39; x <= 41; x++ {
+		worldMap.Tiles[29][x] = components.TileMountains
+		worldMap.Tiles[31][x] = components.TileRiver
+	}
+	worldMap.Tiles[30][39] = components.TileRailwayHorizontal
+	worldMap.Tiles[30][41] = components.TileRailwayHorizontal
+
+	spots := PlaceSubstationWorkbenches(worldMap, rand.New(rand.NewSource(3)))
+	if len(spots) != 1 {
+		t.Fatalf("placed %d workbenches, want 1 beside the open station", len(spots))
+	}
+	x, y := spots[0][0], spots[0][1]
+	if max(abs(x-130), abs(y-60)) != 1 {
+		t.Errorf("workbench at (%d,%d) isn't next to the station at (130,60)", x, y)
+	}
+	if worldMap.Tiles[y][x] != components.TileWasteland {
+		t.Errorf("workbench at (%d,%d) stands on tile %d, want wasteland", x, y, worldMap.Tiles[y][x])
+	}
+}
@@ -3,6 +3,7 @@ package generation
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 
 	"ebiten-rogue/components"
@@ -30,17 +31,29 @@ type DungeonPopulator struct {
 	entitySpawner   *spawners.EntitySpawner
 	templateManager *data.EntityTemplateManager
 	rng             *rand.Rand
-	logMessage      func(string) // Function for logging messages
+	logMessage      func(string)    // Function for logging messages
+	safeSpots       []systems.Point // Where the player arrives on the floor being populated
+	safeRadius      int             // No monster spawns this close to a safe spot
 }
 
+const (
+	defaultSafeRadius = 4 // Tiles kept clear of monsters around the stairs
+	minSafeRadius     = 2 // Even the hardest themes keep this much clear
+	packSpread        = 2 // Pack members spawn within this many tiles of their leader
+)
+
 // PopulationOptions defines options for populating a dungeon
 type PopulationOptions struct {
 	DungeonLevel          int      // Dungeon depth/level (affects monster difficulty)
 	DensityFactor         float64  // How many monsters per room (1.0 = standard)
+	ThreatBudget          float64  // Threat points to spend per room at level 1 (0 = place by density instead)
+	Difficulty            int      // Theme difficulty (1-10), scales the threat budget
 	HigherLevelChance     float64  // Chance of spawning monsters from next level (0.0-1.0)
 	EvenHigherLevelChance float64  // Chance of spawning monsters from two levels higher (0.0-1.0)
 	PreferredTags         []string // Tags to prefer when choosing monsters
 	ExcludeTags           []string // Tags to avoid when choosing monsters
+	SafeRadius            int      // Monster-free radius around the stairs, shrunk by difficulty (0 = default)
+	Rooms                 [][4]int // Rooms to spend the threat budget in as {x, y, width, height}, found from the open areas if empty
 }
 
 // NewDungeonPopulator creates a new dungeon populator
@@ -59,7 +72,17 @@ func (p *DungeonPopulator) SetSeed(seed int64) {
 	p.rng = rand.New(rand.NewSource(seed))
 }
 
-// PopulateDungeon adds monsters and items to the dungeon based on the given options
+// SetRNG sets the random source monsters and items are placed with
+func (p *DungeonPopulator) SetRNG(rng *rand.Rand) {
+	p.rng = rng
+}
+
+// PopulateDungeon adds monsters and items to the dungeon based on the given options.
+//
+// Placement never depends on map iteration order, so a seed always populates a floor
+// the same way: eligible templates are sorted by ID before any roll picks one, rooms
+// are taken in the order ConnectedRegions finds them (row by row from the top left),
+// and tiles are rolled at random or scanned row by row.
 func (p *DungeonPopulator) PopulateDungeon(mapComp *components.MapComponent, mapEntityID ecs.EntityID, options PopulationOptions) {
 	p.entitySpawner.SetSpawnMapID(mapEntityID)
 	systems.GetDebugLog().Add(fmt.Sprintf("Populating dungeon with map ID %d", mapEntityID))
@@ -75,10 +98,32 @@ func (p *DungeonPopulator) PopulateDungeon(mapComp *components.MapComponent, map
 	}
 	systems.GetDebugLog().Add(fmt.Sprintf("Map has %d floor tiles", floorTiles))
 
+	// Keep the stairs clear so the player never arrives next to a monster
+	p.safeSpots = p.findSafeSpots(mapComp)
+	p.safeRadius = p.spawnSafeRadius(options)
+	systems.GetDebugLog().Add(fmt.Sprintf("Keeping monsters %d tiles away from %d stairs", p.safeRadius, len(p.safeSpots)))
+
 	// Count rooms to estimate how many monsters to place
 	roomCount := p.countRooms(mapComp)
 	systems.GetDebugLog().Add(fmt.Sprintf("Found %d rooms in dungeon", roomCount))
 
+	// Get eligible monster templates based on theme and level
+	eligibleTemplates := p.getEligibleMonsterTemplates(options)
+	systems.GetDebugLog().Add(fmt.Sprintf("Found %d eligible monster templates", len(eligibleTemplates)))
+	for _, t := range eligibleTemplates {
+		systems.GetDebugLog().Add(fmt.Sprintf("- Eligible monster: %s (level %d, threat %d, tags: %v)", t.ID, t.Level, t.Threat, t.Tags))
+	}
+
+	// Themes with a threat budget spend it room by room, otherwise place a flat count
+	if options.ThreatBudget > 0 {
+		rooms := options.Rooms
+		if len(rooms) == 0 {
+			rooms = p.findRooms(mapComp)
+		}
+		p.populateByThreat(mapComp, rooms, eligibleTemplates, options)
+		return
+	}
+
 	// Determine number of monsters based on room count and density factor
 	monsterCount := int(float64(roomCount) * options.DensityFactor)
 	if monsterCount < 1 && roomCount > 0 && options.DensityFactor > 0 {
@@ -86,23 +131,9 @@ func (p *DungeonPopulator) PopulateDungeon(mapComp *components.MapComponent, map
 	}
 	systems.GetDebugLog().Add(fmt.Sprintf("Placing %d monsters (rooms: %d * density: %.2f)", monsterCount, roomCount, options.DensityFactor))
 
-	// Get eligible monster templates based on theme and level
-	eligibleTemplates := p.getEligibleMonsterTemplates(options)
-	systems.GetDebugLog().Add(fmt.Sprintf("Found %d eligible monster templates", len(eligibleTemplates)))
-	for _, t := range eligibleTemplates {
-		systems.GetDebugLog().Add(fmt.Sprintf("- Eligible monster: %s (level %d, tags: %v)", t.ID, t.Level, t.Tags))
-	}
-
 	// Place monsters throughout the dungeon
 	monstersPlaced := 0
 	for i := 0; i < monsterCount; i++ {
-		// Find an empty position
-		x, y := p.findEmptyPosition(mapComp)
-		if x == -1 || y == -1 {
-			systems.GetDebugLog().Add("No more empty positions found for monsters")
-			break
-		}
-
 		// Select a monster template
 		template := p.selectMonsterTemplate(eligibleTemplates, options)
 		if template == nil {
@@ -110,102 +141,290 @@ func (p *DungeonPopulator) PopulateDungeon(mapComp *components.MapComponent, map
 			continue
 		}
 
-		// Create the monster
-		_, err := p.entitySpawner.CreateEnemy(x, y, template.ID)
-		if err != nil {
-			systems.GetDebugLog().Add(fmt.Sprintf("Failed to create monster at %d,%d: %v", x, y, err))
-			continue
+		// A pack takes one monster's place
+		placed := p.placeMonster(mapComp, template, 0, wholeMap(mapComp))
+		if placed == 0 {
+			break
 		}
-		monstersPlaced++
-		systems.GetDebugLog().Add(fmt.Sprintf("Created monster %s at %d,%d (%d/%d)", template.ID, x, y, monstersPlaced, monsterCount))
+		monstersPlaced += placed
+		systems.GetDebugLog().Add(fmt.Sprintf("Placed monster %s x%d (%d/%d)", template.ID, placed, i+1, monsterCount))
 	}
 	systems.GetDebugLog().Add(fmt.Sprintf("Finished populating dungeon. Placed %d/%d monsters", monstersPlaced, monsterCount))
 }
 
-// countRooms counts the number of distinct rooms in the dungeon
-func (p *DungeonPopulator) countRooms(mapComp *components.MapComponent) int {
-	// Initialize visited grid
-	visited := make([][]bool, mapComp.Height)
-	for i := range visited {
-		visited[i] = make([]bool, mapComp.Width)
+// populateByThreat gives every room a threat budget and buys monsters for it until
+// nothing eligible fits in what is left or the room is full. Each room's monsters are
+// placed inside it, so no room ends up paying for another's.
+func (p *DungeonPopulator) populateByThreat(mapComp *components.MapComponent, rooms [][4]int, templates []*data.EntityTemplate, options PopulationOptions) {
+	roomBudget := p.roomThreatBudget(options)
+	systems.GetDebugLog().Add(fmt.Sprintf("Spending a threat budget of %d in each of %d rooms", roomBudget, len(rooms)))
+
+	monstersPlaced := 0
+	threatSpent := 0
+	for room, rect := range rooms {
+		area := Bounds{X: rect[0], Y: rect[1], Width: rect[2], Height: rect[3]}
+		remaining := roomBudget
+		for remaining > 0 {
+			// Only consider monsters the room can still afford
+			var affordable []*data.EntityTemplate
+			for _, template := range templates {
+				if template.Threat <= remaining {
+					affordable = append(affordable, template)
+				}
+			}
+
+			template := p.selectMonsterTemplate(affordable, options)
+			if template == nil {
+				break
+			}
+			// Packs only grow as large as the room can pay for, leader included. One the
+			// room can't pay a leader for comes as a lone monster.
+			leaderCost := p.leaderThreat(template)
+			maxMembers := 0
+			if template.Threat > 0 {
+				maxMembers = 1
+				if remaining >= leaderCost {
+					maxMembers += (remaining - leaderCost) / template.Threat
+				}
+			}
+			placed := p.placeMonster(mapComp, template, maxMembers, area)
+			if placed == 0 {
+				systems.GetDebugLog().Add(fmt.Sprintf("Room %d is full with %d threat left", room+1, remaining))
+				break
+			}
+
+			cost := template.Threat * placed
+			if template.Pack.Max > 1 && maxMembers != 1 {
+				cost = leaderCost + template.Threat*(placed-1)
+			}
+			remaining -= cost
+			threatSpent += cost
+			monstersPlaced += placed
+			systems.GetDebugLog().Add(fmt.Sprintf("Placed monster %s x%d (threat %d) in room %d, %d threat left", template.ID, placed, cost, room+1, remaining))
+		}
 	}
+	systems.GetDebugLog().Add(fmt.Sprintf("Finished populating dungeon. Placed %d monsters for %d threat", monstersPlaced, threatSpent))
+}
 
-	roomCount := 0
-	totalFloorTiles := 0
+// leaderThreat returns the threat of the monster leading a template's packs, which is
+// the template's own threat when it has no separate leader
+func (p *DungeonPopulator) leaderThreat(template *data.EntityTemplate) int {
+	if template.Pack.Leader == "" {
+		return template.Threat
+	}
+	if leader, exists := p.templateManager.GetTemplate(template.Pack.Leader); exists {
+		return leader.Threat
+	}
+	return template.Threat
+}
+
+// roomThreatBudget returns the threat each room may spend. Every dungeon level beyond
+// the first adds half the base budget, as does every point of theme difficulty.
+func (p *DungeonPopulator) roomThreatBudget(options PopulationOptions) int {
+	scale := 1.0
+	if options.DungeonLevel > 1 {
+		scale += 0.5 * float64(options.DungeonLevel-1)
+	}
+	if options.Difficulty > 1 {
+		scale += 0.5 * float64(options.Difficulty-1)
+	}
+
+	budget := int(options.ThreatBudget*scale + 0.5)
+	if budget < 1 {
+		budget = 1
+	}
+	return budget
+}
 
-	// Scan the map for unvisited floor tiles
+// spawnSafeRadius returns how far from the stairs monsters must spawn. Harder themes
+// lose a tile of the bubble for every four points of difficulty.
+func (p *DungeonPopulator) spawnSafeRadius(options PopulationOptions) int {
+	radius := options.SafeRadius
+	if radius <= 0 {
+		radius = defaultSafeRadius
+	}
+	if options.Difficulty > 1 {
+		radius -= (options.Difficulty - 1) / 4
+	}
+	return max(radius, minSafeRadius)
+}
+
+// findSafeSpots returns every staircase on the map, which is where the player steps
+// onto a floor whether they come down from above or back up from below
+func (p *DungeonPopulator) findSafeSpots(mapComp *components.MapComponent) []systems.Point {
+	var spots []systems.Point
 	for y := 0; y < mapComp.Height; y++ {
 		for x := 0; x < mapComp.Width; x++ {
-			if mapComp.Tiles[y][x] == components.TileFloor && !visited[y][x] {
-				// Found a new room, perform flood fill and count tiles
-				roomTiles := p.floodFillAndCount(mapComp, x, y, visited)
-				totalFloorTiles += roomTiles
-
-				// For large areas, count them as multiple rooms based on size
-				if roomTiles >= 9 {
-					// Each 100 tiles counts as a room, with a minimum of 1 room
-					roomsInArea := roomTiles / 100
-					if roomsInArea < 1 {
-						roomsInArea = 1
-					}
-					roomCount += roomsInArea
-					systems.GetDebugLog().Add(fmt.Sprintf("Found area with %d floor tiles at (%d,%d), counting as %d rooms", roomTiles, x, y, roomsInArea))
-				}
+			if tile := mapComp.Tiles[y][x]; tile == components.TileStairsUp || tile == components.TileStairsDown {
+				spots = append(spots, systems.Point{X: x, Y: y})
 			}
 		}
 	}
+	return spots
+}
 
-	systems.GetDebugLog().Add(fmt.Sprintf("Total floor tiles: %d, Found %d rooms", totalFloorTiles, roomCount))
+// isInSafeZone returns whether a tile is inside the monster-free bubble around the stairs
+func (p *DungeonPopulator) isInSafeZone(x, y int) bool {
+	for _, spot := range p.safeSpots {
+		if max(abs(x-spot.X), abs(y-spot.Y)) <= p.safeRadius {
+			return true
+		}
+	}
+	return false
+}
 
-	// If we found no rooms but have floor tiles, count it as one room
-	if roomCount == 0 && totalFloorTiles > 0 {
-		roomCount = 1
-		systems.GetDebugLog().Add("No distinct rooms found, but have floor tiles. Treating as one room.")
+// placeMonster creates a monster from a template at an empty position within an area
+// of the map, with the rest of its pack around it if the template runs in packs.
+// maxMembers caps the pack size, 0 for no cap. Returns how many monsters count towards
+// the placement, 0 once the area has no room left.
+func (p *DungeonPopulator) placeMonster(mapComp *components.MapComponent, template *data.EntityTemplate, maxMembers int, area Bounds) int {
+	x, y := p.findEmptyPosition(mapComp, area)
+	if x == -1 || y == -1 {
+		systems.GetDebugLog().Add("No more empty positions found for monsters")
+		return 0
+	}
+
+	if template.Pack.Max > 1 && maxMembers != 1 {
+		return p.placePack(mapComp, template, x, y, maxMembers, area)
 	}
 
-	return roomCount
+	if _, err := p.entitySpawner.CreateEnemy(x, y, template.ID); err != nil {
+		systems.GetDebugLog().Add(fmt.Sprintf("Failed to create monster at %d,%d: %v", x, y, err))
+		return 1
+	}
+	systems.GetDebugLog().Add(fmt.Sprintf("Created monster %s at %d,%d", template.ID, x, y))
+	return 1
 }
 
-// floodFillAndCount marks all connected floor tiles as visited and returns the count
-func (p *DungeonPopulator) floodFillAndCount(mapComp *components.MapComponent, x, y int, visited [][]bool) int {
-	// Check bounds
-	if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height {
-		return 0
+// placePack creates a pack of the template's monsters around a position, led by the
+// template's pack leader if it has one, keeping every member inside the area. Every
+// member shares the ID of the first one created, and the leader lends the pack its
+// leadership.
+func (p *DungeonPopulator) placePack(mapComp *components.MapComponent, template *data.EntityTemplate, x, y, maxMembers int, area Bounds) int {
+	size := template.Pack.Max
+	if minSize := max(template.Pack.Min, 1); minSize < size {
+		size = minSize + p.rng.Intn(size-minSize+1)
+	}
+	if maxMembers > 0 {
+		size = min(size, maxMembers)
 	}
 
-	// Check if already visited or not a floor tile
-	if visited[y][x] || mapComp.Tiles[y][x] != components.TileFloor {
-		return 0
+	leaderTemplate := template
+	if template.Pack.Leader != "" {
+		if leader, exists := p.templateManager.GetTemplate(template.Pack.Leader); exists {
+			leaderTemplate = leader
+		} else {
+			systems.GetDebugLog().Add(fmt.Sprintf("Unknown pack leader %s for %s", template.Pack.Leader, template.ID))
+		}
 	}
 
-	// Mark as visited
-	visited[y][x] = true
-	count := 1
+	leader, err := p.entitySpawner.CreateEnemy(x, y, leaderTemplate.ID)
+	if err != nil {
+		systems.GetDebugLog().Add(fmt.Sprintf("Failed to create pack leader at %d,%d: %v", x, y, err))
+		return 1
+	}
+	p.world.AddComponent(leader.ID, components.Pack, components.NewPackComponent(leader.ID, true, leaderTemplate.Leadership))
+	systems.GetDebugLog().Add(fmt.Sprintf("Created pack leader %s at %d,%d", leaderTemplate.ID, x, y))
+
+	// The rest of the pack gathers around the leader
+	var spots []systems.Point
+	for dy := -packSpread; dy <= packSpread; dy++ {
+		for dx := -packSpread; dx <= packSpread; dx++ {
+			if area.Contains(x+dx, y+dy) && p.isValidMonsterPosition(mapComp, x+dx, y+dy) {
+				spots = append(spots, systems.Point{X: x + dx, Y: y + dy})
+			}
+		}
+	}
+	p.rng.Shuffle(len(spots), func(i, j int) { spots[i], spots[j] = spots[j], spots[i] })
 
-	// Recursively visit neighbors
-	count += p.floodFillAndCount(mapComp, x-1, y, visited)
-	count += p.floodFillAndCount(mapComp, x+1, y, visited)
-	count += p.floodFillAndCount(mapComp, x, y-1, visited)
-	count += p.floodFillAndCount(mapComp, x, y+1, visited)
+	placed := 1
+	for _, spot := range spots {
+		if placed >= size {
+			break
+		}
+		member, err := p.entitySpawner.CreateEnemy(spot.X, spot.Y, template.ID)
+		if err != nil {
+			systems.GetDebugLog().Add(fmt.Sprintf("Failed to create pack member at %d,%d: %v", spot.X, spot.Y, err))
+			break
+		}
+		p.world.AddComponent(member.ID, components.Pack, components.NewPackComponent(leader.ID, false, 0))
+		placed++
+	}
+	systems.GetDebugLog().Add(fmt.Sprintf("Placed a pack of %d %s", placed, template.ID))
+	return placed
+}
 
-	return count
+// countRooms counts the number of distinct rooms in the dungeon
+func (p *DungeonPopulator) countRooms(mapComp *components.MapComponent) int {
+	return len(p.findRooms(mapComp))
 }
 
-// findEmptyPosition finds an empty floor tile in the map
-func (p *DungeonPopulator) findEmptyPosition(mapComp *components.MapComponent) (int, int) {
+// findRooms splits the floor into rooms when the generator didn't say where they are.
+// Each connected floor area of 9 tiles or more is a room, except that every 100 tiles
+// of a larger area count as one, cut in strips across its longer side. A floor with
+// only scraps of open ground is one room the size of the map.
+func (p *DungeonPopulator) findRooms(mapComp *components.MapComponent) [][4]int {
+	var rooms [][4]int
+	totalFloorTiles := 0
+
+	for _, region := range ConnectedRegions(mapComp, isFloor) {
+		roomTiles := len(region)
+		totalFloorTiles += roomTiles
+		if roomTiles < 9 {
+			continue
+		}
+
+		roomsInArea := max(roomTiles/100, 1)
+		bounds := region.Bounds(mapComp.Width)
+		for i := 0; i < roomsInArea; i++ {
+			if bounds.Width >= bounds.Height {
+				from, to := bounds.X+i*bounds.Width/roomsInArea, bounds.X+(i+1)*bounds.Width/roomsInArea
+				rooms = append(rooms, [4]int{from, bounds.Y, to - from, bounds.Height})
+			} else {
+				from, to := bounds.Y+i*bounds.Height/roomsInArea, bounds.Y+(i+1)*bounds.Height/roomsInArea
+				rooms = append(rooms, [4]int{bounds.X, from, bounds.Width, to - from})
+			}
+		}
+		systems.GetDebugLog().Add(fmt.Sprintf("Found area with %d floor tiles at (%d,%d), counting as %d rooms", roomTiles, bounds.X, bounds.Y, roomsInArea))
+	}
+
+	systems.GetDebugLog().Add(fmt.Sprintf("Total floor tiles: %d, Found %d rooms", totalFloorTiles, len(rooms)))
+
+	// If we found no rooms but have floor tiles, count it as one room
+	if len(rooms) == 0 && totalFloorTiles > 0 {
+		rooms = append(rooms, wholeMap(mapComp).Rect())
+		systems.GetDebugLog().Add("No distinct rooms found, but have floor tiles. Treating as one room.")
+	}
+
+	return rooms
+}
+
+// wholeMap returns the bounds of the entire map, for placing monsters anywhere on it
+func wholeMap(mapComp *components.MapComponent) Bounds {
+	return Bounds{Width: mapComp.Width, Height: mapComp.Height}
+}
+
+// findEmptyPosition finds an empty floor tile within an area of the map
+func (p *DungeonPopulator) findEmptyPosition(mapComp *components.MapComponent, area Bounds) (int, int) {
+	minX, minY := max(area.X, 0), max(area.Y, 0)
+	maxX, maxY := min(area.X+area.Width, mapComp.Width), min(area.Y+area.Height, mapComp.Height)
+	if minX >= maxX || minY >= maxY {
+		return -1, -1
+	}
+
 	// Try to find a good spot (floor tile)
 	for attempts := 0; attempts < 100; attempts++ {
-		x := p.rng.Intn(mapComp.Width)
-		y := p.rng.Intn(mapComp.Height)
+		x := minX + p.rng.Intn(maxX-minX)
+		y := minY + p.rng.Intn(maxY-minY)
 
 		if p.isValidMonsterPosition(mapComp, x, y) {
 			return x, y
 		}
 	}
 
-	// Fallback: scan the map systematically
-	for y := 0; y < mapComp.Height; y++ {
-		for x := 0; x < mapComp.Width; x++ {
+	// Fallback: scan the area systematically
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
 			if p.isValidMonsterPosition(mapComp, x, y) {
 				return x, y
 			}
@@ -228,6 +447,11 @@ func (p *DungeonPopulator) isValidMonsterPosition(mapComp *components.MapCompone
 		return false
 	}
 
+	// Keep clear of where the player arrives
+	if p.isInSafeZone(x, y) {
+		return false
+	}
+
 	// Check if position is already occupied by an entity
 	entities := p.world.GetEntitiesWithComponent(components.Position)
 	for _, entity := range entities {
@@ -322,6 +546,8 @@ func (p *DungeonPopulator) getEligibleMonsters(options PopulationOptions) []mons
 		})
 	}
 
+	// Templates come out of a map, so fix their order before they are rolled for
+	sort.Slice(eligibleMonsters, func(i, j int) bool { return eligibleMonsters[i].ID < eligibleMonsters[j].ID })
 	return eligibleMonsters
 }
 
@@ -429,6 +655,9 @@ func (p *DungeonPopulator) getEligibleMonsterTemplates(options PopulationOptions
 		templates = append(templates, template)
 	}
 
+	// Templates come out of a map, so fix their order before they are rolled for
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
 	systems.GetDebugLog().Add(fmt.Sprintf("Found %d eligible monster templates", len(templates)))
 	for _, t := range templates {
 		systems.GetDebugLog().Add(fmt.Sprintf("- %s (level %d, tags: %v)", t.ID, t.Level, t.Tags))
@@ -456,6 +685,9 @@ func (p *DungeonPopulator) selectMonsterTemplate(templates []*data.EntityTemplat
 
 		totalWeight += weight
 	}
+	if totalWeight <= 0 {
+		return templates[p.rng.Intn(len(templates))]
+	}
 
 	// Select a template based on weight
 	roll := p.rng.Intn(totalWeight)
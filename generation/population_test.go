@@ -0,0 +1,234 @@
+package generation
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/spawners"
+)
+
+// placement is where a monster was put and what it was made from
+type placement struct {
+	templateID string
+	x, y       int
+}
+
+// populationTestMap is a walled 60x40 floor split by a wall with a gap in it, with the
+// stairs up in the top left room and the stairs down in the bottom right one
+func populationTestMap() *components.MapComponent {
+	mapComp := components.NewMapComponent(60, 40)
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			mapComp.Tiles[y][x] = components.TileFloor
+			if x == 0 || y == 0 || x == mapComp.Width-1 || y == mapComp.Height-1 || (x == 30 && y != 20) {
+				mapComp.Tiles[y][x] = components.TileWall
+			}
+		}
+	}
+	mapComp.Tiles[5][5] = components.TileStairsUp
+	mapComp.Tiles[34][54] = components.TileStairsDown
+	return mapComp
+}
+
+// loadMonsterTemplates loads the monster templates the game ships with
+func loadMonsterTemplates(t *testing.T) *data.EntityTemplateManager {
+	t.Helper()
+	templates := data.NewEntityTemplateManager()
+	if err := templates.LoadTemplatesFromDirectory("../data/monsters"); err != nil {
+		t.Fatalf("loading monster templates: %v", err)
+	}
+	return templates
+}
+
+// populate fills a fresh copy of the test map from a seed and returns the monsters
+// placed, in the order they were created
+func populate(t *testing.T, seed int64, options PopulationOptions) []placement {
+	t.Helper()
+	return populateMap(t, populationTestMap(), seed, options)
+}
+
+// populateMap fills a map from a seed and returns the monsters placed, in the order
+// they were created
+func populateMap(t *testing.T, mapComp *components.MapComponent, seed int64, options PopulationOptions) []placement {
+	t.Helper()
+	world := ecs.NewWorld()
+	templates := loadMonsterTemplates(t)
+
+	mapEntity := world.CreateEntity()
+	world.AddComponent(mapEntity.ID, components.MapComponentID, mapComp)
+
+	quiet := func(string) {}
+	populator := NewDungeonPopulator(world, spawners.NewEntitySpawner(world, templates, quiet), templates, quiet)
+	populator.SetSeed(seed)
+	populator.PopulateDungeon(mapComp, mapEntity.ID, options)
+
+	monsters := world.Query(components.AI, components.Position).Entities()
+	slices.SortFunc(monsters, func(a, b *ecs.Entity) int { return cmp.Compare(a.ID, b.ID) })
+	placements := make([]placement, len(monsters))
+	for i, monster := range monsters {
+		aiComp, _ := world.GetComponent(monster.ID, components.AI)
+		posComp, _ := world.GetComponent(monster.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		placements[i] = placement{aiComp.(*components.AIComponent).TemplateID, pos.X, pos.Y}
+	}
+	return placements
+}
+
+func TestPopulateDungeonFollowsTheSeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		options PopulationOptions
+	}{
+		{"by density", PopulationOptions{DungeonLevel: 2, DensityFactor: 1.5, PreferredTags: []string{"enemy"}, HigherLevelChance: 0.3, EvenHigherLevelChance: 0.1}},
+		{"by threat", PopulationOptions{DungeonLevel: 2, ThreatBudget: 6, PreferredTags: []string{"enemy"}, Difficulty: 3, HigherLevelChance: 0.3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := populate(t, 42, tt.options)
+			if len(first) == 0 {
+				t.Fatal("no monsters were placed")
+			}
+			for run := 0; run < 3; run++ {
+				again := populate(t, 42, tt.options)
+				if len(again) != len(first) {
+					t.Fatalf("the same seed placed %d monsters, then %d", len(first), len(again))
+				}
+				for i := range first {
+					if again[i] != first[i] {
+						t.Fatalf("monster %d of the same seed was %+v, then %+v", i, first[i], again[i])
+					}
+				}
+			}
+			if other := populate(t, 43, tt.options); slices.Equal(other, first) {
+				t.Error("a different seed placed exactly the same monsters")
+			}
+		})
+	}
+}
+
+func TestPopulateDungeonKeepsTheStairsClear(t *testing.T) {
+	stairs := [][2]int{{5, 5}, {54, 34}}
+	for _, tt := range []struct {
+		name    string
+		options PopulationOptions
+		radius  int
+	}{
+		{"default radius", PopulationOptions{DungeonLevel: 1, DensityFactor: 4, PreferredTags: []string{"enemy"}}, defaultSafeRadius},
+		{"wider radius", PopulationOptions{DungeonLevel: 1, DensityFactor: 4, SafeRadius: 7, PreferredTags: []string{"enemy"}}, 7},
+		{"hard theme", PopulationOptions{DungeonLevel: 1, ThreatBudget: 8, Difficulty: 9, PreferredTags: []string{"enemy"}}, defaultSafeRadius - 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			placements := populate(t, 7, tt.options)
+			if len(placements) == 0 {
+				t.Fatal("no monsters were placed")
+			}
+			closest := 1000
+			for _, placed := range placements {
+				for _, stair := range stairs {
+					closest = min(closest, max(abs(placed.x-stair[0]), abs(placed.y-stair[1])))
+				}
+			}
+			if closest <= tt.radius {
+				t.Errorf("a monster spawned %d tiles from the stairs, want more than %d", closest, tt.radius)
+			}
+		})
+	}
+}
+
+func TestSpawnSafeRadius(t *testing.T) {
+	populator := &DungeonPopulator{}
+	for _, tt := range []struct {
+		options PopulationOptions
+		want    int
+	}{
+		{PopulationOptions{}, defaultSafeRadius},
+		{PopulationOptions{SafeRadius: 6}, 6},
+		{PopulationOptions{Difficulty: 4}, defaultSafeRadius},
+		{PopulationOptions{Difficulty: 5}, defaultSafeRadius - 1},
+		{PopulationOptions{SafeRadius: 6, Difficulty: 9}, 4},
+		{PopulationOptions{Difficulty: 10}, minSafeRadius},
+		{PopulationOptions{SafeRadius: 1}, minSafeRadius},
+	} {
+		if got := populator.spawnSafeRadius(tt.options); got != tt.want {
+			t.Errorf("spawnSafeRadius(%+v) = %d, want %d", tt.options, got, tt.want)
+		}
+	}
+}
+
+// roomsTestMap has three rooms of different sizes along a corridor, as
+// {x, y, width, height}, with walls around each and the stairs far from all of them
+func roomsTestMap(t *testing.T) (*components.MapComponent, [][4]int) {
+	t.Helper()
+	mapComp := parseTestMap(t,
+		"####################################",
+		"#.........#####.......##############",
+		"#.........#####.......####......####",
+		"#.........#####.......####......####",
+		"#.........#####.......####......####",
+		"#.........#####.......####......####",
+		"#.................................>#",
+		"#.........#####.......##############",
+		"####################################",
+	)
+	return mapComp, [][4]int{{1, 1, 9, 7}, {15, 1, 7, 7}, {26, 2, 6, 5}}
+}
+
+func TestPopulateByThreatKeepsEachRoomToItsBudget(t *testing.T) {
+	templates := loadMonsterTemplates(t)
+	threatOf := func(templateID string) int {
+		template, _ := templates.GetTemplate(templateID)
+		return template.Threat
+	}
+
+	mapComp, rooms := roomsTestMap(t)
+	options := PopulationOptions{DungeonLevel: 1, ThreatBudget: 5, SafeRadius: 2, PreferredTags: []string{"enemy"}, Rooms: rooms}
+	budget := (&DungeonPopulator{}).roomThreatBudget(options)
+
+	for seed := int64(1); seed <= 20; seed++ {
+		spent := make([]int, len(rooms))
+		for _, placed := range populateMap(t, mapComp, seed, options) {
+			room := slices.IndexFunc(rooms, func(rect [4]int) bool {
+				return Bounds{X: rect[0], Y: rect[1], Width: rect[2], Height: rect[3]}.Contains(placed.x, placed.y)
+			})
+			if room < 0 {
+				t.Fatalf("seed %d: %s placed at (%d,%d), outside every room", seed, placed.templateID, placed.x, placed.y)
+			}
+			spent[room] += threatOf(placed.templateID)
+		}
+		for room, threat := range spent {
+			if threat > budget {
+				t.Errorf("seed %d: room %d holds %d threat, over its budget of %d", seed, room, threat, budget)
+			}
+			if threat == 0 {
+				t.Errorf("seed %d: room %d was left empty", seed, room)
+			}
+		}
+	}
+}
+
+func TestFindRooms(t *testing.T) {
+	mapComp, _ := roomsTestMap(t)
+	populator := &DungeonPopulator{}
+
+	// One connected floor of 153 tiles is a single room across its bounds
+	if rooms := populator.findRooms(mapComp); !slices.Equal(rooms, [][4]int{{1, 1, 33, 7}}) {
+		t.Errorf("findRooms = %v, want the whole connected floor as one room", rooms)
+	}
+
+	// 2165 tiles of open floor are cut into 21 strips across its width
+	rooms := populator.findRooms(populationTestMap())
+	if len(rooms) != 21 {
+		t.Fatalf("found %d rooms on the open floor, want 21", len(rooms))
+	}
+	covered := 0
+	for _, room := range rooms {
+		covered += room[2]
+	}
+	if covered != 58 {
+		t.Errorf("the strips cover %d columns, want the 58 of the floor", covered)
+	}
+}
@@ -0,0 +1,181 @@
+package generation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Prefab layout characters
+const (
+	prefabKeep    = ' ' // Leave the room's own tile as it is
+	prefabWall    = '#' // Wall
+	prefabFloor   = '.' // Floor
+	prefabDoor    = '+' // Open door
+	prefabWater   = '~' // Water
+	prefabLava    = '=' // Lava
+	prefabMonster = 'M' // Floor with a monster from the prefab's monster list
+	prefabItem    = 'I' // Floor with an item from the prefab's item list
+	prefabChest   = 'C' // Floor with a container
+	prefabLever   = 'L' // Wall with a lever that opens the prefab's gates
+	prefabGate    = 'G' // Wall that the prefab's levers open into a door
+)
+
+// prefabLegend holds every character a prefab layout may use
+const prefabLegend = " #.+~=MICLG"
+
+// defaultPrefabContainer is the container a prefab's chests hold when it names none
+const defaultPrefabContainer = "wooden_chest"
+
+// PrefabDefinition is a hand-authored room, a "vault", stamped into generated dungeons.
+// Its layout is an ASCII grid of the characters above, one string per row.
+type PrefabDefinition struct {
+	ID          string   `json:"id"`          // Unique identifier for the prefab
+	Name        string   `json:"name"`        // Display name for the prefab
+	Description string   `json:"description"` // Description of the prefab
+	MinLevel    int      `json:"min_level"`   // Shallowest dungeon level it turns up on (0 = any)
+	MaxLevel    int      `json:"max_level"`   // Deepest dungeon level it turns up on (0 = any)
+	Layout      []string `json:"layout"`      // Rows of the ASCII grid, all the same length
+	Monsters    []string `json:"monsters"`    // Monster templates the M spawn points pick from
+	Items       []string `json:"items"`       // Item templates the I spawn points pick from
+	Container   string   `json:"container"`   // Container template for C spawn points (default wooden_chest)
+	OneShot     bool     `json:"one_shot"`    // Whether the levers stay pulled once used
+}
+
+// Width returns the width of the prefab's layout
+func (p *PrefabDefinition) Width() int {
+	if len(p.Layout) == 0 {
+		return 0
+	}
+	return len(p.Layout[0])
+}
+
+// Height returns the height of the prefab's layout
+func (p *PrefabDefinition) Height() int {
+	return len(p.Layout)
+}
+
+// FitsLevel returns true if the prefab may turn up on the given dungeon level
+func (p *PrefabDefinition) FitsLevel(level int) bool {
+	return (p.MinLevel == 0 || level >= p.MinLevel) && (p.MaxLevel == 0 || level <= p.MaxLevel)
+}
+
+// validate checks that the layout is a rectangle of known characters and that every
+// spawn point it marks has something to spawn
+func (p *PrefabDefinition) validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("prefab is missing ID")
+	}
+	if p.Height() == 0 || p.Width() == 0 {
+		return fmt.Errorf("prefab %s has an empty layout", p.ID)
+	}
+
+	counts := make(map[rune]int)
+	for y, row := range p.Layout {
+		if len(row) != p.Width() {
+			return fmt.Errorf("prefab %s row %d is %d wide, expected %d", p.ID, y, len(row), p.Width())
+		}
+		for _, c := range row {
+			if !strings.ContainsRune(prefabLegend, c) {
+				return fmt.Errorf("prefab %s row %d has unknown character %q", p.ID, y, c)
+			}
+			counts[c]++
+		}
+	}
+
+	if counts[prefabMonster] > 0 && len(p.Monsters) == 0 {
+		return fmt.Errorf("prefab %s marks monster spawns but lists no monsters", p.ID)
+	}
+	if counts[prefabItem] > 0 && len(p.Items) == 0 {
+		return fmt.Errorf("prefab %s marks item spawns but lists no items", p.ID)
+	}
+	if counts[prefabGate] > 0 && counts[prefabLever] == 0 {
+		return fmt.Errorf("prefab %s has gates but no lever to open them", p.ID)
+	}
+	return nil
+}
+
+// PrefabManager handles loading prefabs from JSON files
+type PrefabManager struct {
+	prefabs map[string]*PrefabDefinition
+}
+
+// NewPrefabManager creates a new prefab manager
+func NewPrefabManager() *PrefabManager {
+	return &PrefabManager{
+		prefabs: make(map[string]*PrefabDefinition),
+	}
+}
+
+// LoadPrefabsFromDirectory loads all prefab files from a directory
+func (m *PrefabManager) LoadPrefabsFromDirectory(directory string) error {
+	files, err := filepath.Glob(filepath.Join(directory, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read prefab directory: %v", err)
+	}
+
+	for _, file := range files {
+		if err := m.LoadPrefabFromFile(file); err != nil {
+			return fmt.Errorf("failed to load prefab from %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+// LoadPrefabFromFile loads a single prefab from a JSON file
+func (m *PrefabManager) LoadPrefabFromFile(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read prefab file: %v", err)
+	}
+
+	var prefab PrefabDefinition
+	if err := json.Unmarshal(data, &prefab); err != nil {
+		return fmt.Errorf("failed to parse prefab JSON: %v", err)
+	}
+
+	if err := prefab.validate(); err != nil {
+		return err
+	}
+	if prefab.Container == "" {
+		prefab.Container = defaultPrefabContainer
+	}
+
+	m.prefabs[prefab.ID] = &prefab
+	return nil
+}
+
+// GetPrefab retrieves a prefab by ID
+func (m *PrefabManager) GetPrefab(id string) *PrefabDefinition {
+	return m.prefabs[id]
+}
+
+// GetPrefabs returns the prefabs that may turn up on a level, limited to the given IDs
+// unless there are none. They're sorted by ID so generation doesn't depend on map order.
+func (m *PrefabManager) GetPrefabs(ids []string, level int) []*PrefabDefinition {
+	var result []*PrefabDefinition
+	for id, prefab := range m.prefabs {
+		if len(ids) > 0 && !containsString(ids, id) {
+			continue
+		}
+		if prefab.FitsLevel(level) {
+			result = append(result, prefab)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// containsString returns true if the slice holds the string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/spawners"
+)
+
+// addPrefab stamps one of the theme's prefabs into an empty room. The prefab goes
+// inside the room with a ring of the room's floor left around it, so the corridors
+// that reach the room still reach the prefab's doors. A placement that would cut
+// anything off is undone and the next room tried.
+func (t *DungeonThemer) addPrefab(mapComp *components.MapComponent, floorID ecs.EntityID, rooms [][4]int, themeDef *DungeonThemeDefinition, level int) bool {
+	prefabs := t.prefabManager.GetPrefabs(themeDef.Prefabs, level)
+	if len(prefabs) == 0 || len(rooms) == 0 {
+		return false
+	}
+	prefab := prefabs[t.rng.Intn(len(prefabs))]
+	width, height := prefab.Width(), prefab.Height()
+
+	regionsBefore := len(ConnectedRegions(mapComp, isOpen))
+	for _, i := range t.rng.Perm(len(rooms)) {
+		room := rooms[i]
+		if room[2] < width+2 || room[3] < height+2 {
+			continue
+		}
+
+		// Anywhere in the room that leaves the ring around it
+		x0 := room[0] + 1 + t.rng.Intn(room[2]-width-1)
+		y0 := room[1] + 1 + t.rng.Intn(room[3]-height-1)
+		if !t.isPrefabSpotEmpty(mapComp, x0-1, y0-1, width+2, height+2) {
+			continue
+		}
+
+		// Gates count as open while checking, since the levers open them
+		saved := t.stampPrefab(mapComp, prefab, x0, y0)
+		if len(ConnectedRegions(mapComp, isOpen)) > regionsBefore {
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					mapComp.SetTile(x0+x, y0+y, saved[y][x])
+				}
+			}
+			continue
+		}
+
+		t.spawnPrefabEntities(mapComp, prefab, floorID, x0, y0)
+		mapComp.ApplyBoxDrawingWalls()
+
+		if t.logMessage != nil {
+			t.logMessage(fmt.Sprintf("Added prefab %s at (%d,%d)", prefab.ID, x0, y0))
+		}
+		return true
+	}
+
+	return false
+}
+
+// isPrefabSpotEmpty returns true if every tile of the rectangle is bare floor inside
+// the map's border, so nothing already generated is stamped over
+func (t *DungeonThemer) isPrefabSpotEmpty(mapComp *components.MapComponent, x0, y0, width, height int) bool {
+	if x0 < 1 || y0 < 1 || x0+width > mapComp.Width-1 || y0+height > mapComp.Height-1 {
+		return false
+	}
+	for y := y0; y < y0+height; y++ {
+		for x := x0; x < x0+width; x++ {
+			if mapComp.Tiles[y][x] != components.TileFloor {
+				return false
+			}
+			if _, isTransition := mapComp.GetTransition(x, y); isTransition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stampPrefab writes the prefab's tiles onto the map, with gates open, and returns
+// the tiles it replaced
+func (t *DungeonThemer) stampPrefab(mapComp *components.MapComponent, prefab *PrefabDefinition, x0, y0 int) [][]int {
+	saved := make([][]int, prefab.Height())
+	for y, row := range prefab.Layout {
+		saved[y] = make([]int, len(row))
+		for x, c := range row {
+			saved[y][x] = mapComp.Tiles[y0+y][x0+x]
+			switch c {
+			case prefabWall, prefabLever:
+				mapComp.SetTile(x0+x, y0+y, components.TileWall)
+			case prefabDoor, prefabGate:
+				mapComp.SetTile(x0+x, y0+y, components.TileDoor)
+			case prefabWater:
+				mapComp.SetTile(x0+x, y0+y, components.TileWater)
+			case prefabLava:
+				mapComp.SetTile(x0+x, y0+y, components.TileLava)
+			case prefabFloor, prefabMonster, prefabItem, prefabChest:
+				mapComp.SetTile(x0+x, y0+y, components.TileFloor)
+			}
+		}
+	}
+	return saved
+}
+
+// spawnPrefabEntities closes the prefab's gates and fills its spawn points
+func (t *DungeonThemer) spawnPrefabEntities(mapComp *components.MapComponent, prefab *PrefabDefinition, floorID ecs.EntityID, x0, y0 int) {
+	var gates []components.MechanismTarget
+	var levers [][2]int
+	for y, row := range prefab.Layout {
+		for x, c := range row {
+			switch c {
+			case prefabGate:
+				mapComp.SetTile(x0+x, y0+y, components.TileWall)
+				gates = append(gates, components.MechanismTarget{
+					X:       x0 + x,
+					Y:       y0 + y,
+					OffTile: components.TileWall,
+					OnTile:  components.TileDoor,
+				})
+			case prefabLever:
+				levers = append(levers, [2]int{x0 + x, y0 + y})
+			}
+		}
+	}
+
+	t.entitySpawner.SetSpawnMapID(floorID)
+	itemSpawner := spawners.NewItemSpawner(t.world, t.templateManager)
+	itemSpawner.SetSpawnMapID(floorID)
+
+	for _, lever := range levers {
+		t.entitySpawner.CreateLever(lever[0], lever[1], gates, prefab.OneShot)
+	}
+
+	for y, row := range prefab.Layout {
+		for x, c := range row {
+			var err error
+			switch c {
+			case prefabMonster:
+				_, err = t.entitySpawner.CreateEnemy(x0+x, y0+y, prefab.Monsters[t.rng.Intn(len(prefab.Monsters))])
+			case prefabItem:
+				_, err = itemSpawner.CreateItem(x0+x, y0+y, prefab.Items[t.rng.Intn(len(prefab.Items))], false)
+			case prefabChest:
+				_, err = itemSpawner.CreateContainer(x0+x, y0+y, prefab.Container, nil)
+			}
+			if err != nil && t.logMessage != nil {
+				t.logMessage(fmt.Sprintf("Warning: prefab %s spawn at (%d,%d) failed: %v", prefab.ID, x0+x, y0+y, err))
+			}
+		}
+	}
+}
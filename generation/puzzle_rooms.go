@@ -0,0 +1,141 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// addPuzzleRoom seals off a small side area of the map behind a gate and places
+// levers on both sides of it. The gate is a corridor tile whose removal splits
+// the map in two, so the sealed area is only reachable by pulling a lever.
+func (t *DungeonThemer) addPuzzleRoom(mapComp *components.MapComponent, floorID ecs.EntityID) bool {
+	candidates := t.findGateCandidates(mapComp)
+	if len(candidates) == 0 {
+		return false
+	}
+
+	totalWalkable := 0
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			if !mapComp.IsWall(x, y) {
+				totalWalkable++
+			}
+		}
+	}
+
+	// Try a limited number of gates in random order
+	order := t.rng.Perm(len(candidates))
+	for attempt := 0; attempt < len(order) && attempt < 50; attempt++ {
+		gate := candidates[order[attempt]]
+		sideA, sideB := gate[2], gate[3]
+
+		// Block the gate and see whether it splits off a suitably small area
+		originalTile := mapComp.Tiles[gate[1]][gate[0]]
+		mapComp.SetTile(gate[0], gate[1], components.TileWall)
+
+		regionA, _ := FloodFill(mapComp, sideA%mapComp.Width, sideA/mapComp.Width, isOpen)
+		if regionA[sideB] {
+			mapComp.SetTile(gate[0], gate[1], originalTile)
+			continue
+		}
+		regionB, _ := FloodFill(mapComp, sideB%mapComp.Width, sideB/mapComp.Width, isOpen)
+
+		sealed := regionA
+		if len(regionB) < len(regionA) {
+			sealed = regionB
+		}
+		if len(sealed) < 6 || len(sealed) > totalWalkable/4 || t.containsTransition(mapComp, sealed) {
+			mapComp.SetTile(gate[0], gate[1], originalTile)
+			continue
+		}
+
+		// Each side of the gate needs a wall to mount a lever on
+		leverAX, leverAY, okA := t.findLeverWall(mapComp, sideA%mapComp.Width, sideA/mapComp.Width, gate[0], gate[1])
+		leverBX, leverBY, okB := t.findLeverWall(mapComp, sideB%mapComp.Width, sideB/mapComp.Width, gate[0], gate[1])
+		if !okA || !okB || (leverAX == leverBX && leverAY == leverBY) {
+			mapComp.SetTile(gate[0], gate[1], originalTile)
+			continue
+		}
+
+		mapComp.ApplyBoxDrawingWalls()
+
+		targets := []components.MechanismTarget{{
+			X:       gate[0],
+			Y:       gate[1],
+			OffTile: components.TileWall,
+			OnTile:  components.TileDoor,
+		}}
+		oneShot := t.rng.Intn(2) == 0
+
+		t.entitySpawner.SetSpawnMapID(floorID)
+		t.entitySpawner.CreateLever(leverAX, leverAY, targets, oneShot)
+		t.entitySpawner.CreateLever(leverBX, leverBY, targets, oneShot)
+
+		if t.logMessage != nil {
+			t.logMessage(fmt.Sprintf("Added puzzle gate at (%d,%d) sealing %d tiles", gate[0], gate[1], len(sealed)))
+		}
+		return true
+	}
+
+	return false
+}
+
+// findGateCandidates returns corridor tiles with walkable tiles on two opposite
+// sides and walls on the other two, as {x, y, sideA, sideB} where the sides are
+// encoded as y*width+x
+func (t *DungeonThemer) findGateCandidates(mapComp *components.MapComponent) [][4]int {
+	var candidates [][4]int
+	for y := 1; y < mapComp.Height-1; y++ {
+		for x := 1; x < mapComp.Width-1; x++ {
+			if !isWalkable(mapComp.Tiles[y][x]) {
+				continue
+			}
+			if _, isTransition := mapComp.GetTransition(x, y); isTransition {
+				continue
+			}
+
+			horizontal := !mapComp.IsWall(x-1, y) && !mapComp.IsWall(x+1, y) && mapComp.IsWall(x, y-1) && mapComp.IsWall(x, y+1)
+			vertical := !mapComp.IsWall(x, y-1) && !mapComp.IsWall(x, y+1) && mapComp.IsWall(x-1, y) && mapComp.IsWall(x+1, y)
+			if horizontal {
+				candidates = append(candidates, [4]int{x, y, y*mapComp.Width + x - 1, y*mapComp.Width + x + 1})
+			} else if vertical {
+				candidates = append(candidates, [4]int{x, y, (y-1)*mapComp.Width + x, (y+1)*mapComp.Width + x})
+			}
+		}
+	}
+	return candidates
+}
+
+// containsTransition returns true if any tile in the region is stairs or another map transition
+func (t *DungeonThemer) containsTransition(mapComp *components.MapComponent, region Region) bool {
+	for key := range region {
+		x, y := key%mapComp.Width, key/mapComp.Width
+		tile := mapComp.Tiles[y][x]
+		if tile == components.TileStairsDown || tile == components.TileStairsUp {
+			return true
+		}
+		if _, isTransition := mapComp.GetTransition(x, y); isTransition {
+			return true
+		}
+	}
+	return false
+}
+
+// findLeverWall finds a wall tile next to (x, y) to mount a lever on, skipping the gate itself
+func (t *DungeonThemer) findLeverWall(mapComp *components.MapComponent, x, y, gateX, gateY int) (int, int, bool) {
+	for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		wx, wy := x+dir[0], y+dir[1]
+		if wx <= 0 || wx >= mapComp.Width-1 || wy <= 0 || wy >= mapComp.Height-1 {
+			continue
+		}
+		if wx == gateX && wy == gateY {
+			continue
+		}
+		if mapComp.IsWall(wx, wy) {
+			return wx, wy, true
+		}
+	}
+	return 0, 0, false
+}
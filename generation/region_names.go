@@ -0,0 +1,131 @@
+package generation
+
+import (
+	"math/rand"
+	"strings"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// MinNamedRegionSize is the fewest tiles a biome region needs to be given a name
+const MinNamedRegionSize = 150
+
+// Syllables names are built from: an opening, optional middles and an ending
+var (
+	nameOpenings = []string{"ash", "bran", "cor", "dun", "ev", "fen", "gal", "har", "ir", "kel", "mor", "ost", "rav", "sal", "tor", "ul", "vey", "wrek"}
+	nameMiddles  = []string{"a", "e", "i", "o", "en", "ar", "il", "or"}
+	nameEndings  = []string{"beck", "dale", "don", "fall", "gate", "holm", "mere", "moor", "rin", "stead", "ton", "vane", "wick"}
+)
+
+// regionKinds is what a named region of each biome is called
+var regionKinds = map[int]string{
+	components.TileWasteland:  "Wastes",
+	components.TileDesert:     "Sands",
+	components.TileDarkForest: "Woods",
+	components.TileMountains:  "Peaks",
+}
+
+// NameGenerator makes up place names from syllables. Names from the same seed always
+// come out in the same order, and none repeats.
+type NameGenerator struct {
+	rng  *rand.Rand
+	used map[string]bool
+}
+
+// NewNameGenerator creates a name generator from a seed
+func NewNameGenerator(seed int64) *NameGenerator {
+	return &NameGenerator{
+		rng:  rand.New(rand.NewSource(seed)),
+		used: make(map[string]bool),
+	}
+}
+
+// Name returns a new capitalized place name, like "Kelamoor"
+func (n *NameGenerator) Name() string {
+	for {
+		name := nameOpenings[n.rng.Intn(len(nameOpenings))]
+		if n.rng.Intn(2) == 0 {
+			name += nameMiddles[n.rng.Intn(len(nameMiddles))]
+		}
+		name += nameEndings[n.rng.Intn(len(nameEndings))]
+		// Very rarely every short name is taken; a second ending still reads as a name
+		if n.used[name] {
+			name += nameEndings[n.rng.Intn(len(nameEndings))]
+		}
+		if !n.used[name] {
+			n.used[name] = true
+			return strings.ToUpper(name[:1]) + name[1:]
+		}
+	}
+}
+
+// NameWorldMap names the world map's larger biome regions and its substations, and
+// stores the names on the map entity in a RegionNamesComponent
+func NameWorldMap(world *ecs.World, mapEntity *ecs.Entity, seed int64) *components.RegionNamesComponent {
+	mapComp, exists := world.GetComponent(mapEntity.ID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	worldMap := mapComp.(*components.MapComponent)
+	names := NewNameGenerator(seed)
+
+	regionNames := &components.RegionNamesComponent{RegionAt: make([][]int, worldMap.Height)}
+	for y := range regionNames.RegionAt {
+		regionNames.RegionAt[y] = make([]int, worldMap.Width)
+		for x := range regionNames.RegionAt[y] {
+			regionNames.RegionAt[y][x] = -1
+		}
+	}
+
+	// Biomes are named in a fixed order so the same seed gives the same names
+	for _, biome := range []int{components.TileWasteland, components.TileDesert, components.TileDarkForest, components.TileMountains} {
+		for _, region := range ConnectedRegions(worldMap, func(tileType int) bool { return tileType == biome }) {
+			if len(region) < MinNamedRegionSize {
+				continue
+			}
+			index := len(regionNames.Regions)
+			for key := range region {
+				regionNames.RegionAt[key/worldMap.Width][key%worldMap.Width] = index
+			}
+			labelX, labelY := regionLabelTile(region, worldMap.Width)
+			regionNames.Regions = append(regionNames.Regions, components.NamedPlace{
+				Name: names.Name() + " " + regionKinds[biome],
+				X:    labelX,
+				Y:    labelY,
+			})
+		}
+	}
+
+	for y := 0; y < worldMap.Height; y++ {
+		for x := 0; x < worldMap.Width; x++ {
+			if worldMap.Tiles[y][x] == components.TileSubstation {
+				regionNames.Stations = append(regionNames.Stations, components.NamedPlace{
+					Name: names.Name() + " Station",
+					X:    x,
+					Y:    y,
+				})
+			}
+		}
+	}
+
+	world.AddComponent(mapEntity.ID, components.RegionNames, regionNames)
+	return regionNames
+}
+
+// regionLabelTile returns the tile of a region closest to the middle of its bounds,
+// so the label sits inside the region even when it's oddly shaped. Ties go to the
+// first tile in reading order, keeping the choice stable.
+func regionLabelTile(region Region, width int) (int, int) {
+	bounds := region.Bounds(width)
+	centerX, centerY := bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2
+	bestKey, bestDistance := -1, 0
+	for key := range region {
+		x, y := key%width, key/width
+		distance := (x-centerX)*(x-centerX) + (y-centerY)*(y-centerY)
+		if bestKey < 0 || distance < bestDistance || (distance == bestDistance && key < bestKey) {
+			bestKey, bestDistance = key, distance
+		}
+	}
+	return bestKey % width, bestKey / width
+}
@@ -0,0 +1,49 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// addShrine raises an altar against a room wall, on the same kind of spot a
+// workbench would use so it can't block a corridor
+func (t *DungeonThemer) addShrine(mapComp *components.MapComponent, floorID ecs.EntityID) bool {
+	var candidates [][2]int
+	for y := 1; y < mapComp.Height-1; y++ {
+		for x := 1; x < mapComp.Width-1; x++ {
+			if t.isStationSpot(mapComp, x, y) && t.isSpotFree(floorID, x, y) {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	spot := candidates[t.rng.Intn(len(candidates))]
+	t.entitySpawner.SetSpawnMapID(floorID)
+	t.entitySpawner.CreateShrine(spot[0], spot[1])
+
+	if t.logMessage != nil {
+		t.logMessage(fmt.Sprintf("Added shrine at (%d,%d)", spot[0], spot[1]))
+	}
+	return true
+}
+
+// isSpotFree returns true if nothing on the floor has been placed on the tile yet,
+// such as a workbench taking the same spot
+func (t *DungeonThemer) isSpotFree(floorID ecs.EntityID, x, y int) bool {
+	for _, entity := range t.world.GetEntitiesWithComponent(components.Position) {
+		contextComp, exists := t.world.GetComponent(entity.ID, components.MapContextID)
+		if !exists || contextComp.(*components.MapContextComponent).MapID != floorID {
+			continue
+		}
+		posComp, _ := t.world.GetComponent(entity.ID, components.Position)
+		if pos := posComp.(*components.PositionComponent); pos.X == x && pos.Y == y {
+			return false
+		}
+	}
+	return true
+}
@@ -149,6 +149,11 @@ func (g *WorldMapGenerator) SetSeed(seed int64) {
 	g.noiseGen = NewPerlinNoise(seed, 6, 20.0)
 }
 
+// RNG returns the generator's random source so other systems can share the world seed
+func (g *WorldMapGenerator) RNG() *rand.Rand {
+	return g.rng
+}
+
 // GenerateWorldMap creates a world map using Perlin noise for biome distribution
 func (g *WorldMapGenerator) GenerateWorldMap(mapComp *components.MapComponent) {
 	// Initialize the map with a basic floor
@@ -226,8 +231,8 @@ func (g *WorldMapGenerator) PlaceAdditionalSubstations(mapComp *components.MapCo
 		x := g.rng.Intn(mapComp.Width)
 		y := g.rng.Intn(mapComp.Height)
 
-		// Skip if this is a mountain tile
-		if mapComp.Tiles[y][x] == components.TileMountains {
+		// Skip if this is a mountain or river tile
+		if mapComp.Tiles[y][x] == components.TileMountains || isRiver(mapComp.Tiles[y][x]) {
 			continue
 		}
 
@@ -271,8 +276,9 @@ func NewNode(x, y int) *Node {
 	}
 }
 
-// getNeighbors returns valid neighboring nodes for pathfinding
-func (g *WorldMapGenerator) getNeighbors(mapComp *components.MapComponent, node *Node) []*Node {
+// getNeighbors returns valid neighboring nodes for pathfinding. Rivers block the way
+// unless bridging, when the path may cross them to have bridges built.
+func (g *WorldMapGenerator) getNeighbors(mapComp *components.MapComponent, node *Node, bridging bool) []*Node {
 	neighbors := make([]*Node, 0, 4) // Changed from 8 to 4 for orthogonal only
 	directions := [][2]int{
 		{0, -1}, // North
@@ -283,9 +289,10 @@ func (g *WorldMapGenerator) getNeighbors(mapComp *components.MapComponent, node
 
 	for _, dir := range directions {
 		nx, ny := node.x+dir[0], node.y+dir[1]
-		// Check bounds and if the tile is walkable (not a mountain)
+		// Check bounds and if the tile is walkable (not a mountain, nor a river unless bridging)
 		if nx >= 0 && nx < mapComp.Width && ny >= 0 && ny < mapComp.Height &&
-			mapComp.Tiles[ny][nx] != components.TileMountains {
+			mapComp.Tiles[ny][nx] != components.TileMountains &&
+			(bridging || !isRiver(mapComp.Tiles[ny][nx])) {
 			neighbors = append(neighbors, NewNode(nx, ny))
 		}
 	}
@@ -300,8 +307,9 @@ func heuristic(a, b *Node) float64 {
 	return math.Abs(dx) + math.Abs(dy)
 }
 
-// findPath finds a path from start to end avoiding mountains
-func (g *WorldMapGenerator) findPath(mapComp *components.MapComponent, startX, startY, endX, endY int) []*Node {
+// findPath finds a path from start to end avoiding mountains. Rivers are avoided too,
+// unless bridging, when crossing one costs extra so the path crosses as few as it can.
+func (g *WorldMapGenerator) findPath(mapComp *components.MapComponent, startX, startY, endX, endY int, bridging bool) []*Node {
 	start := NewNode(startX, startY)
 	end := NewNode(endX, endY)
 
@@ -335,7 +343,7 @@ func (g *WorldMapGenerator) findPath(mapComp *components.MapComponent, startX, s
 		closedSet[fmt.Sprintf("%d,%d", current.x, current.y)] = current
 
 		// Check neighbors
-		for _, neighbor := range g.getNeighbors(mapComp, current) {
+		for _, neighbor := range g.getNeighbors(mapComp, current, bridging) {
 			// Skip if already in closed set
 			if _, exists := closedSet[fmt.Sprintf("%d,%d", neighbor.x, neighbor.y)]; exists {
 				continue
@@ -343,6 +351,9 @@ func (g *WorldMapGenerator) findPath(mapComp *components.MapComponent, startX, s
 
 			// Calculate tentative g score
 			tentativeG := current.g + 1.0
+			if isRiver(mapComp.Tiles[neighbor.y][neighbor.x]) {
+				tentativeG += riverCrossingCost
+			}
 
 			// If neighbor not in open set or found a better path
 			if _, exists := openSet[fmt.Sprintf("%d,%d", neighbor.x, neighbor.y)]; !exists || tentativeG < neighbor.g {
@@ -364,8 +375,8 @@ func (g *WorldMapGenerator) findPath(mapComp *components.MapComponent, startX, s
 
 // drawRailwayLine draws a railway line between two points using box drawing characters
 func (g *WorldMapGenerator) drawRailwayLine(mapComp *components.MapComponent, x0, y0, x1, y1 int) {
-	// Find a path that avoids mountains
-	path := g.findPath(mapComp, x0, y0, x1, y1)
+	// Find a path that avoids mountains, bridging any river in the way
+	path := g.findPath(mapComp, x0, y0, x1, y1, true)
 	if path == nil {
 		return
 	}
@@ -380,6 +391,12 @@ func (g *WorldMapGenerator) drawRailwayLine(mapComp *components.MapComponent, x0
 			continue
 		}
 
+		// Track over a river runs on a bridge
+		if isRiver(mapComp.Tiles[current.y][current.x]) || mapComp.Tiles[current.y][current.x] == components.TileBridge {
+			mapComp.SetTile(current.x, current.y, components.TileBridge)
+			continue
+		}
+
 		// Check if next tile is a substation
 		isNextSubstation := mapComp.Tiles[next.y][next.x] == components.TileSubstation
 
@@ -391,6 +408,7 @@ func (g *WorldMapGenerator) drawRailwayLine(mapComp *components.MapComponent, x0
 			mapComp.Tiles[current.y-1][current.x] == components.TileRailwayTeeRight ||
 			mapComp.Tiles[current.y-1][current.x] == components.TileRailwayTeeBottom ||
 			mapComp.Tiles[current.y-1][current.x] == components.TileRailwayCross ||
+			mapComp.Tiles[current.y-1][current.x] == components.TileBridge ||
 			(isNextSubstation && next.y == current.y-1))
 
 		connRight := current.x < mapComp.Width-1 && (mapComp.Tiles[current.y][current.x+1] == components.TileRailwayHorizontal ||
@@ -400,6 +418,7 @@ func (g *WorldMapGenerator) drawRailwayLine(mapComp *components.MapComponent, x0
 			mapComp.Tiles[current.y][current.x+1] == components.TileRailwayTeeTop ||
 			mapComp.Tiles[current.y][current.x+1] == components.TileRailwayTeeBottom ||
 			mapComp.Tiles[current.y][current.x+1] == components.TileRailwayCross ||
+			mapComp.Tiles[current.y][current.x+1] == components.TileBridge ||
 			(isNextSubstation && next.x == current.x+1))
 
 		connBottom := current.y < mapComp.Height-1 && (mapComp.Tiles[current.y+1][current.x] == components.TileRailwayVertical ||
@@ -409,6 +428,7 @@ func (g *WorldMapGenerator) drawRailwayLine(mapComp *components.MapComponent, x0
 			mapComp.Tiles[current.y+1][current.x] == components.TileRailwayTeeRight ||
 			mapComp.Tiles[current.y+1][current.x] == components.TileRailwayTeeTop ||
 			mapComp.Tiles[current.y+1][current.x] == components.TileRailwayCross ||
+			mapComp.Tiles[current.y+1][current.x] == components.TileBridge ||
 			(isNextSubstation && next.y == current.y+1))
 
 		connLeft := current.x > 0 && (mapComp.Tiles[current.y][current.x-1] == components.TileRailwayHorizontal ||
@@ -418,6 +438,7 @@ func (g *WorldMapGenerator) drawRailwayLine(mapComp *components.MapComponent, x0
 			mapComp.Tiles[current.y][current.x-1] == components.TileRailwayTeeTop ||
 			mapComp.Tiles[current.y][current.x-1] == components.TileRailwayTeeBottom ||
 			mapComp.Tiles[current.y][current.x-1] == components.TileRailwayCross ||
+			mapComp.Tiles[current.y][current.x-1] == components.TileBridge ||
 			(isNextSubstation && next.x == current.x-1))
 
 		// Determine direction of movement
@@ -487,6 +508,7 @@ func (g *WorldMapGenerator) CreateWorldMapEntity(world *ecs.World, width, height
 
 	// Generate the world map
 	g.GenerateWorldMap(mapComp)
+	g.carveRivers(mapComp, worldRiverCount)
 
 	// Calculate center coordinates
 	centerX := mapComp.Width / 2
@@ -516,7 +538,8 @@ func (g *WorldMapGenerator) CreateWorldMapEntity(world *ecs.World, width, height
 		// Check bounds and if the tile is walkable
 		if alphaX >= 0 && alphaX < mapComp.Width &&
 			alphaY >= 0 && alphaY < mapComp.Height &&
-			mapComp.Tiles[alphaY][alphaX] != components.TileMountains {
+			mapComp.Tiles[alphaY][alphaX] != components.TileMountains &&
+			!isRiver(mapComp.Tiles[alphaY][alphaX]) {
 			alphaStation.x = alphaX
 			alphaStation.y = alphaY
 			mapComp.SetTile(alphaX, alphaY, components.TileSubstation)
@@ -536,8 +559,8 @@ func (g *WorldMapGenerator) CreateWorldMapEntity(world *ecs.World, width, height
 		x := g.rng.Intn(mapComp.Width)
 		y := g.rng.Intn(mapComp.Height)
 
-		// Skip if this is a mountain tile
-		if mapComp.Tiles[y][x] == components.TileMountains {
+		// Skip if this is a mountain or river tile
+		if mapComp.Tiles[y][x] == components.TileMountains || isRiver(mapComp.Tiles[y][x]) {
 			continue
 		}
 
@@ -576,13 +599,17 @@ func (g *WorldMapGenerator) CreateWorldMapEntity(world *ecs.World, width, height
 	}
 
 	// Connect center to alpha station with broken railway
-	path := g.findPath(mapComp, centerX, centerY, alphaStation.x, alphaStation.y)
+	path := g.findPath(mapComp, centerX, centerY, alphaStation.x, alphaStation.y, true)
 	if path != nil {
 		// Randomly remove two tiles from the path
 		if len(path) > 2 {
 			removeIndices := make(map[int]bool)
-			for len(removeIndices) < 2 {
+			for tries := 0; len(removeIndices) < 2 && tries < 100; tries++ {
 				idx := g.rng.Intn(len(path)-2) + 1 // Don't remove first or last tile
+				// Gaps never fall on a bridge, or the river would cut the line
+				if isRiver(mapComp.Tiles[path[idx].y][path[idx].x]) {
+					continue
+				}
 				removeIndices[idx] = true
 			}
 
@@ -597,5 +624,9 @@ func (g *WorldMapGenerator) CreateWorldMapEntity(world *ecs.World, width, height
 		}
 	}
 
+	// However the rivers ran, every station can be reached from the center
+	stations := append([]struct{ x, y int }{alphaStation}, additionalStations...)
+	g.bridgeCutOffStations(mapComp, centerX, centerY, stations)
+
 	return mapEntity
 }
@@ -0,0 +1,141 @@
+package generation
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/systems"
+)
+
+const (
+	worldRiverCount   = 6   // Rivers traced across the world map
+	maxRiverLength    = 300 // Tiles a river runs before it is cut short
+	riverMeander      = 0.03
+	riverCrossingCost = 8.0 // Extra path cost for laying track over water, so rails cross rivers rarely
+)
+
+// isRiver returns true for world map tiles that can only be crossed by bridge
+func isRiver(tileType int) bool {
+	return tileType == components.TileRiver
+}
+
+// carveRivers traces rivers from the foot of the mountains downhill across the world
+// map, following the same elevation noise the biomes were made from
+func (g *WorldMapGenerator) carveRivers(mapComp *components.MapComponent, count int) {
+	carved := 0
+	for attempts := 0; attempts < count*50 && carved < count; attempts++ {
+		x := g.rng.Intn(mapComp.Width)
+		y := g.rng.Intn(mapComp.Height)
+		if mapComp.Tiles[y][x] != components.TileMountains {
+			continue
+		}
+
+		// Springs rise beside the mountains rather than on them
+		for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			sx, sy := x+dir[0], y+dir[1]
+			if sx < 0 || sx >= mapComp.Width || sy < 0 || sy >= mapComp.Height {
+				continue
+			}
+			tile := mapComp.Tiles[sy][sx]
+			if tile == components.TileMountains || isRiver(tile) {
+				continue
+			}
+			length := g.traceRiver(mapComp, sx, sy)
+			systems.GetDebugLog().Add(fmt.Sprintf("Traced river of %d tiles from (%d, %d)", length, sx, sy))
+			carved++
+			break
+		}
+	}
+}
+
+// traceRiver runs a river from (x,y), always stepping to the lowest neighbouring tile
+// it hasn't been on, until it joins another river, leaves the map or runs out of length.
+// It returns the number of tiles carved.
+func (g *WorldMapGenerator) traceRiver(mapComp *components.MapComponent, x, y int) int {
+	visited := make(map[[2]int]bool)
+	length := 0
+	for length < maxRiverLength {
+		mapComp.SetTile(x, y, components.TileRiver)
+		visited[[2]int{x, y}] = true
+		length++
+
+		if x == 0 || y == 0 || x == mapComp.Width-1 || y == mapComp.Height-1 {
+			return length
+		}
+
+		bestX, bestY := -1, -1
+		lowest := 0.0
+		for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			nx, ny := x+dir[0], y+dir[1]
+			if visited[[2]int{nx, ny}] || mapComp.Tiles[ny][nx] == components.TileMountains {
+				continue
+			}
+			// A little jitter keeps rivers from running dead straight down a slope
+			elevation := g.noiseGen.Noise2D(float64(nx), float64(ny)) + g.rng.Float64()*riverMeander
+			if bestX < 0 || elevation < lowest {
+				bestX, bestY, lowest = nx, ny, elevation
+			}
+		}
+		if bestX < 0 {
+			return length
+		}
+		if isRiver(mapComp.Tiles[bestY][bestX]) {
+			// Flowed into another river
+			return length
+		}
+		x, y = bestX, bestY
+	}
+	return length
+}
+
+// bridgeCutOffStations makes sure every station can be reached from the hub on foot.
+// A station the rivers have cut off gets bridges along the cheapest way to it, one
+// for each river in between.
+func (g *WorldMapGenerator) bridgeCutOffStations(mapComp *components.MapComponent, hubX, hubY int, stations []struct{ x, y int }) {
+	reachable := reachableOverland(mapComp, hubX, hubY)
+	for _, station := range stations {
+		if reachable[station.y][station.x] {
+			continue
+		}
+		path := g.findPath(mapComp, hubX, hubY, station.x, station.y, true)
+		if path == nil {
+			continue
+		}
+		bridges := 0
+		for _, node := range path {
+			if isRiver(mapComp.Tiles[node.y][node.x]) {
+				mapComp.SetTile(node.x, node.y, components.TileBridge)
+				bridges++
+			}
+		}
+		systems.GetDebugLog().Add(fmt.Sprintf("Bridged %d river crossings to station at (%d, %d)", bridges, station.x, station.y))
+		reachable = reachableOverland(mapComp, hubX, hubY)
+	}
+}
+
+// reachableOverland flood fills the tiles that can be walked to from (x,y) without
+// crossing a river
+func reachableOverland(mapComp *components.MapComponent, x, y int) [][]bool {
+	reachable := make([][]bool, mapComp.Height)
+	for row := range reachable {
+		reachable[row] = make([]bool, mapComp.Width)
+	}
+	reachable[y][x] = true
+	queue := [][2]int{{x, y}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dir := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			nx, ny := current[0]+dir[0], current[1]+dir[1]
+			if nx < 0 || nx >= mapComp.Width || ny < 0 || ny >= mapComp.Height {
+				continue
+			}
+			if reachable[ny][nx] || mapComp.BlocksMovement(nx, ny) {
+				continue
+			}
+			reachable[ny][nx] = true
+			queue = append(queue, [2]int{nx, ny})
+		}
+	}
+	return reachable
+}
@@ -4,11 +4,13 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"ebiten-rogue/config"
+	"ebiten-rogue/generation"
 	"ebiten-rogue/systems"
 )
 
@@ -33,14 +35,69 @@ func setupFileLogging(filepath string) error {
 	return nil
 }
 
+// setupMessageLogging mirrors the game message log to a file
+func setupMessageLogging(filepath string) error {
+	// Create the log file (append if exists, create if it doesn't)
+	logFile, err := os.OpenFile(filepath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+
+	systems.SetMessageLogWriter(logFile)
+
+	// Write initial timestamp and header
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	logFile.WriteString("===== MESSAGE LOG STARTED AT " + timestamp + " =====\n")
+
+	log.Printf("Message logging enabled. Writing messages to: %s", filepath)
+
+	return nil
+}
+
 func main() {
 	// Define command-line flags
 	debugLogFile := flag.String("log", "", "Filename to write debug logs to")
+	messageLogFile := flag.String("message-log", "", "Filename to mirror the game message log to, with timestamps and categories")
+	messageLogSize := flag.Int("message-log-size", 500, "Number of game messages kept in memory for scrolling back")
 	viewTileset := flag.Bool("view-tileset", false, "Run the tileset viewer")
 	worldMap := flag.Bool("world-map", false, "Run the world map tester")
+	reduceMotion := flag.Bool("reduce-motion", false, "Disable map reveal animations and screen fades")
+	highContrast := flag.Bool("high-contrast", false, "Draw the panels in bright, high-contrast colors")
+	colorblind := flag.Bool("colorblind", false, "Use colors that stay apart for red-green colorblindness, with glyph cues for effects and lava")
+	noVignette := flag.Bool("no-vignette", false, "Don't redden the screen edges while your health is low")
+	lowHealth := flag.Int("low-health", 25, "Percent of max health below which you are warned that your health is low")
+	aggroRadius := flag.Bool("aggro-radius", false, "Tint where the monster being examined would notice you (R toggles it while targeting)")
+	turnOrder := flag.Bool("turn-order", false, "Show the turn order of the actors in sight over the map (F3 toggles it in game)")
+	smoothCamera := flag.Bool("smooth-camera", false, "Glide the camera after the player instead of snapping")
+	recordFile := flag.String("record", "", "Record the seed and every key pressed to this file when the game exits, for -replay")
+	replayFile := flag.String("replay", "", "Play back a session recorded with -record, warning where it stops matching the recording")
+	autosave := flag.String("autosave", "", "Save a checkpoint of the run to this file whenever you finish resting at a camp")
+	adaptiveHealing := flag.Bool("adaptive-healing", false, "Start with adaptive healing on: broken props drop healing items more often while your health has been low, less while it has been high (H toggles it when choosing a class)")
+	healingBiasMin := flag.Float64("adaptive-healing-min", systems.MinHealingBias, "Lowest weight adaptive healing gives healing drops, where other drops weigh 1")
+	healingBiasMax := flag.Float64("adaptive-healing-max", systems.MaxHealingBias, "Highest weight adaptive healing gives healing drops, where other drops weigh 1")
+	undo := flag.Bool("undo", false, "Let Z take back the last step while nothing else has happened")
+	noStairsReveal := flag.Bool("no-stairs-reveal", false, "Don't reveal a floor's stairs once most of it is explored")
+	stairsRevealAt := flag.Int("stairs-reveal-at", 90, "Percent of a floor to explore before its unfound stairs are revealed")
+	weather := flag.Bool("weather", true, "Roll weather on the world map that limits your sight (-weather=false for clear skies)")
+	noTutorial := flag.Bool("no-tutorial", false, "Skip the hints shown on the first floor")
+	companion := flag.Bool("companion", false, "Start runs with a brass hound that follows you and fights at your side")
+	targetPref := flag.String("target", "nearest", "Hostile targeting starts on: nearest, dangerous (highest threat) or weakest (least health)")
+	clearReward := flag.String("clear-reward", "xp", "Reward for killing every hostile on a floor: xp, heal, stairs or none")
+	deathMap := flag.Bool("death-map", false, "Show the floor you died on fully revealed on the game over screen")
+	graveyard := flag.Bool("graveyard", false, "Record deaths and bury previous characters on new maps")
+	seed := flag.Int64("seed", 0, "Master seed for reproducible runs, 0 for a random one")
+	monsterHP := flag.String("monster-hp", "off", "Show monster health on the map: off, number or tint")
+	glyphs := flag.String("glyphs", "mixed", "Draw the map and entities as mixed, ascii or tiles")
+	cleanEdges := flag.Bool("clean-edges", false, "End walls on the map border cleanly instead of joining them into the edge")
+	gen := flag.Bool("gen", false, "Generate a dungeon without opening a window and print it as ASCII with its stats")
+	genGenerator := flag.String("generator", "bsp", "Generator for -gen: bsp, cellular, random or hybrid")
+	genSize := flag.String("size", "normal", "Dungeon size for -gen: small, normal, large or huge")
+	genTheme := flag.String("theme", "abandoned", "Theme ID for -gen")
+	genLevel := flag.Int("level", 1, "Dungeon level for -gen")
 
 	// Parse the command line flags
 	flag.Parse()
+	generation.SetEdgeWalls(!*cleanEdges)
 
 	// Set up debug file logging if enabled
 	if *debugLogFile != "" {
@@ -49,10 +106,37 @@ func main() {
 		}
 	}
 
+	// Keep the configured number of game messages, optionally mirrored to a file
+	systems.GetMessageLog().SetRetention(*messageLogSize)
+	if *messageLogFile != "" {
+		if err := setupMessageLogging(*messageLogFile); err != nil {
+			log.Printf("Error setting up message logging: %v", err)
+		}
+	}
+
+	// Headless generation prints a dungeon and exits before anything opens a window
+	if *gen {
+		genSeed := *seed
+		if genSeed == 0 {
+			genSeed = time.Now().UnixNano()
+		}
+		if err := runGenerator(os.Stdout, genSeed, *genGenerator, *genSize, *genTheme, *genLevel); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Handle the special modes
 	if *viewTileset {
 		// Run the tileset viewer
-		viewer := NewTilesetViewer("Nice_curses_12x12.png", 36) // Use a larger tile size for better visibility
+		viewer, err := NewTilesetViewer("Nice_curses_12x12.png", 36) // Use a larger tile size for better visibility
+		if err != nil {
+			log.Printf("Error loading tileset: %v", err)
+			if err := runErrorScreen("Could not load the tileset", err); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
 		ebiten.SetWindowSize(800, 600)
 		ebiten.SetWindowTitle("Tileset Viewer - Nice_curses_12x12.png")
 		if err := ebiten.RunGame(viewer); err != nil {
@@ -79,7 +163,14 @@ func main() {
 		firstArg := flag.Arg(0)
 		if firstArg == "--view-tileset" {
 			// Run the tileset viewer
-			viewer := NewTilesetViewer("Nice_curses_12x12.png", 36)
+			viewer, err := NewTilesetViewer("Nice_curses_12x12.png", 36)
+			if err != nil {
+				log.Printf("Error loading tileset: %v", err)
+				if err := runErrorScreen("Could not load the tileset", err); err != nil {
+					log.Fatal(err)
+				}
+				return
+			}
 			ebiten.SetWindowSize(800, 600)
 			ebiten.SetWindowTitle("Tileset Viewer - Nice_curses_12x12.png")
 			if err := ebiten.RunGame(viewer); err != nil {
@@ -99,8 +190,87 @@ func main() {
 		}
 	}
 
-	// Create the main game instance
-	game := NewGame()
+	// Create the main game instance, explaining rather than crashing if its assets are missing
+	game, err := NewGame()
+	if err != nil {
+		log.Printf("Error starting game: %v", err)
+		if err := runErrorScreen("Could not start the game", err); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	game.renderSystem.SetReduceMotion(*reduceMotion)
+	if *reduceMotion {
+		game.screenStack.SetFadeDuration(0)
+	}
+	if *highContrast {
+		game.renderSystem.SetPalette(config.HighContrastPalette)
+	}
+	if *colorblind {
+		game.renderSystem.SetPalette(config.ColorblindPalette)
+	}
+	game.renderSystem.SetLowHealthWarning(!*noVignette, float64(*lowHealth)/100)
+	game.renderSystem.SetTurnOrderHUD(*turnOrder)
+	game.targetingSystem.SetShowAggro(*aggroRadius)
+	game.cameraSystem.SetSmoothFollow(*smoothCamera)
+	game.playerTurnProcessorSystem.SetUndoEnabled(*undo)
+	game.tutorialSystem.SetEnabled(!*noTutorial)
+	game.weatherSystem.SetEnabled(*weather)
+	game.fovSystem.SetStairsReveal(!*noStairsReveal, float64(*stairsRevealAt)/100)
+	game.SetSeed(*seed)
+	var recording *systems.Replay
+	if *recordFile != "" {
+		// A replay is only as good as its seed, so fix one if none was given
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+			game.SetSeed(*seed)
+		}
+		recording = systems.NewReplay(*seed, os.Args[1:])
+		game.RecordReplay(recording)
+		log.Printf("Recording input to %s (seed %d)", *recordFile, *seed)
+	}
+	if *replayFile != "" {
+		replay, err := systems.LoadReplay(*replayFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		game.PlayReplay(replay)
+		log.Printf("Playing back %s (seed %d), recorded with flags: %s", *replayFile, replay.Seed, strings.Join(replay.Args, " "))
+	}
+	game.SetCompanion(*companion)
+	game.SetAutosave(*autosave)
+	game.healingBalanceSystem.SetEnabled(*adaptiveHealing)
+	game.healingBalanceSystem.SetBounds(*healingBiasMin, *healingBiasMax)
+	game.deathSystem.SetRevealMap(*deathMap)
+	switch mode := systems.MonsterHPDisplay(*monsterHP); mode {
+	case systems.MonsterHPOff, systems.MonsterHPNumber, systems.MonsterHPTint:
+		game.renderSystem.SetMonsterHPDisplay(mode)
+	default:
+		log.Printf("Warning: Unknown monster HP display %q, expected off, number or tint", *monsterHP)
+	}
+	switch preference := systems.TargetPreference(*targetPref); preference {
+	case systems.TargetNearest, systems.TargetDangerous, systems.TargetWeakest:
+		game.targetingSystem.SetPreference(preference)
+	default:
+		log.Printf("Warning: Unknown target preference %q, expected nearest, dangerous or weakest", *targetPref)
+	}
+	switch reward := systems.FloorClearReward(*clearReward); reward {
+	case systems.ClearRewardXP, systems.ClearRewardHeal, systems.ClearRewardStairs, systems.ClearRewardNone:
+		game.floorClearSystem.SetReward(reward)
+	default:
+		log.Printf("Warning: Unknown floor clear reward %q, expected xp, heal, stairs or none", *clearReward)
+	}
+	switch mode := systems.GlyphMode(*glyphs); mode {
+	case systems.GlyphsMixed, systems.GlyphsASCII, systems.GlyphsTiles:
+		game.renderSystem.SetGlyphMode(mode)
+	default:
+		log.Printf("Warning: Unknown glyph mode %q, expected mixed, ascii or tiles", *glyphs)
+	}
+	if *graveyard {
+		if err := game.graveyardSystem.LoadFromFile("graveyard.json"); err != nil {
+			log.Printf("Warning: Failed to load graveyard: %v", err)
+		}
+	}
 
 	// Get window size from config
 	windowWidth, windowHeight := config.GetWindowSize()
@@ -110,7 +280,14 @@ func main() {
 	ebiten.SetFullscreen(true)
 
 	ebiten.SetWindowTitle("Ebiten Roguelike")
-	if err := ebiten.RunGame(game); err != nil {
+	err = ebiten.RunGame(game)
+	game.bestiarySystem.Flush()
+	if recording != nil {
+		if err := recording.SaveToFile(*recordFile); err != nil {
+			log.Printf("Error saving recording: %v", err)
+		}
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 }
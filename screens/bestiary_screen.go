@@ -0,0 +1,153 @@
+package screens
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"ebiten-rogue/systems"
+)
+
+// BestiaryScreen shows the monsters the player has encountered in a scrollable modal
+type BestiaryScreen struct {
+	*BaseScreen
+	bestiary     *systems.BestiarySystem
+	selected     int
+	scrollOffset int
+	width        int
+	height       int
+	background   color.Color
+}
+
+// NewBestiaryScreen creates a new bestiary screen
+func NewBestiaryScreen(bestiary *systems.BestiarySystem) *BestiaryScreen {
+	return &BestiaryScreen{
+		BaseScreen: NewBaseScreen(),
+		bestiary:   bestiary,
+		width:      600,
+		height:     400,
+		background: color.RGBA{0, 0, 0, 255},
+	}
+}
+
+// Update handles input for the bestiary screen
+func (s *BestiaryScreen) Update() error {
+	entries := s.bestiary.GetEntries()
+
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) && s.selected > 0 {
+		s.selected--
+	}
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) && s.selected < len(entries)-1 {
+		s.selected++
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEscape) {
+		return ErrCloseScreen
+	}
+
+	return nil
+}
+
+// Draw renders the bestiary list on the left and the selected entry on the right
+func (s *BestiaryScreen) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := screen.Size()
+	x := (screenWidth - s.width) / 2
+	y := (screenHeight - s.height) / 2
+
+	modal := ebiten.NewImage(s.width, s.height)
+	modal.Fill(s.background)
+
+	// Draw frame
+	frameWidth := 2.0
+	ebitenutil.DrawRect(modal, 0, 0, frameWidth, float64(s.height), color.White)                           // Left
+	ebitenutil.DrawRect(modal, float64(s.width)-frameWidth, 0, frameWidth, float64(s.height), color.White) // Right
+	ebitenutil.DrawRect(modal, 0, 0, float64(s.width), frameWidth, color.White)                            // Top
+	ebitenutil.DrawRect(modal, 0, float64(s.height)-frameWidth, float64(s.width), frameWidth, color.White) // Bottom
+
+	title := "BESTIARY"
+	ebitenutil.DebugPrintAt(modal, title, (s.width-len(title)*6)/2, 8)
+
+	entries := s.bestiary.GetEntries()
+	if len(entries) == 0 {
+		ebitenutil.DebugPrintAt(modal, "You haven't encountered any monsters yet.", 10, 40)
+	} else {
+		if s.selected >= len(entries) {
+			s.selected = len(entries) - 1
+		}
+
+		// Keep the selection inside the visible window
+		startY := 30
+		lineHeight := 16
+		maxLines := (s.height - startY - 30) / lineHeight
+		if s.selected < s.scrollOffset {
+			s.scrollOffset = s.selected
+		}
+		if s.selected >= s.scrollOffset+maxLines {
+			s.scrollOffset = s.selected - maxLines + 1
+		}
+
+		for i := 0; i < maxLines && s.scrollOffset+i < len(entries); i++ {
+			entry := entries[s.scrollOffset+i]
+			prefix := "  "
+			if s.scrollOffset+i == s.selected {
+				prefix = "> "
+			}
+			ebitenutil.DebugPrintAt(modal, prefix+entry.Name, 10, startY+i*lineHeight)
+		}
+
+		// Selected entry details
+		entry := entries[s.selected]
+		detailX := 220
+		lines := []string{
+			entry.Name,
+			"",
+			fmt.Sprintf("Level:   %d", entry.Level),
+			fmt.Sprintf("Health:  %d", entry.Health),
+			fmt.Sprintf("Attack:  %d", entry.Attack),
+			fmt.Sprintf("Defense: %d", entry.Defense),
+			fmt.Sprintf("XP:      %d", entry.XP),
+			"",
+			fmt.Sprintf("Sighted: %d", entry.Seen),
+			fmt.Sprintf("Killed:  %d", entry.Kills),
+			"",
+		}
+		lines = append(lines, wrapText(entry.Description, (s.width-detailX-10)/6)...)
+		for i, line := range lines {
+			ebitenutil.DebugPrintAt(modal, line, detailX, startY+i*lineHeight)
+		}
+	}
+
+	ebitenutil.DebugPrintAt(modal, "Up/Down: Select  ESC: Close", 10, s.height-20)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(modal, op)
+}
+
+// Layout implements the Screen interface
+func (s *BestiaryScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
+
+// wrapText splits text into lines of at most width characters
+func wrapText(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
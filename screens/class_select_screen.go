@@ -0,0 +1,182 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"ebiten-rogue/config"
+	"ebiten-rogue/data"
+	"ebiten-rogue/systems"
+)
+
+// ErrLoadoutSelected is returned when the player has picked a class
+var ErrLoadoutSelected = errors.New("loadout selected")
+
+// ClassSelectScreen lets the player pick a starting class before a new game
+type ClassSelectScreen struct {
+	*BaseScreen
+	templateManager *data.EntityTemplateManager
+	loadouts        []*data.Loadout
+	selected        int
+	practice        bool // Death returns the player to the floor's entrance instead of ending the run
+	adaptiveHealing bool // Healing items drop more often while the player struggles, less while they thrive
+	background      color.Color
+}
+
+// NewClassSelectScreen creates a new class selection screen for the loaded loadouts
+func NewClassSelectScreen(templateManager *data.EntityTemplateManager) *ClassSelectScreen {
+	return &ClassSelectScreen{
+		BaseScreen:      NewBaseScreen(),
+		templateManager: templateManager,
+		loadouts:        templateManager.GetLoadouts(),
+		background:      color.RGBA{0, 0, 0, 255},
+	}
+}
+
+// Selected returns the highlighted loadout, or nil if there are none
+func (s *ClassSelectScreen) Selected() *data.Loadout {
+	if s.selected < 0 || s.selected >= len(s.loadouts) {
+		return nil
+	}
+	return s.loadouts[s.selected]
+}
+
+// Practice returns whether the player chose practice mode over permadeath
+func (s *ClassSelectScreen) Practice() bool {
+	return s.practice
+}
+
+// SetAdaptiveHealing sets whether adaptive healing drops start out on
+func (s *ClassSelectScreen) SetAdaptiveHealing(enabled bool) {
+	s.adaptiveHealing = enabled
+}
+
+// AdaptiveHealing returns whether the player chose adaptive healing drops
+func (s *ClassSelectScreen) AdaptiveHealing() bool {
+	return s.adaptiveHealing
+}
+
+// Update handles input for the class selection screen
+func (s *ClassSelectScreen) Update() error {
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) && len(s.loadouts) > 0 {
+		s.selected = (s.selected - 1 + len(s.loadouts)) % len(s.loadouts)
+	}
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) && len(s.loadouts) > 0 {
+		s.selected = (s.selected + 1) % len(s.loadouts)
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyP) {
+		s.practice = !s.practice
+	}
+	if systems.KeyJustPressed(ebiten.KeyH) {
+		s.adaptiveHealing = !s.adaptiveHealing
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEnter) {
+		return ErrLoadoutSelected
+	}
+	if systems.KeyJustPressed(ebiten.KeyEscape) {
+		return ErrCloseScreen
+	}
+
+	return nil
+}
+
+// Draw renders the class list on the left and the selected class on the right
+func (s *ClassSelectScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(s.background)
+	screenWidth, screenHeight := screen.Size()
+
+	title := "CHOOSE YOUR CLASS"
+	ebitenutil.DebugPrintAt(screen, title, (screenWidth-len(title)*6)/2, 40)
+
+	startY := 90
+	lineHeight := 16
+	listX := screenWidth/2 - 260
+	detailX := screenWidth/2 - 100
+
+	if len(s.loadouts) == 0 {
+		ebitenutil.DebugPrintAt(screen, "No classes found, press Enter to start.", listX, startY)
+	}
+
+	for i, loadout := range s.loadouts {
+		prefix := "  "
+		if i == s.selected {
+			prefix = "> "
+		}
+		ebitenutil.DebugPrintAt(screen, prefix+loadout.Name, listX, startY+i*lineHeight*2)
+	}
+
+	if loadout := s.Selected(); loadout != nil {
+		lines := []string{
+			loadout.Name,
+			"",
+		}
+		lines = append(lines, wrapText(loadout.Description, 60)...)
+		lines = append(lines,
+			"",
+			fmt.Sprintf("Health:   %d", loadout.Stats.Health),
+			fmt.Sprintf("Attack:   %d", loadout.Stats.Attack),
+			fmt.Sprintf("Defense:  %d", loadout.Stats.Defense),
+			fmt.Sprintf("Acc/Eva:  %d/%d", loadout.Stats.Accuracy, loadout.Stats.Evasion),
+		)
+		if loadout.Stats.SightRange > 0 {
+			lines = append(lines, fmt.Sprintf("Sight:    %d", loadout.Stats.SightRange))
+		}
+		if len(loadout.Equipment) > 0 {
+			items := make([]string, 0, len(loadout.Equipment))
+			for _, templateID := range loadout.Equipment {
+				items = append(items, s.itemName(templateID))
+			}
+			lines = append(lines, "", "Equipped: "+strings.Join(items, ", "))
+		}
+		if len(loadout.Inventory) > 0 {
+			items := make([]string, 0, len(loadout.Inventory))
+			for _, entry := range loadout.Inventory {
+				if entry.Count > 1 {
+					items = append(items, fmt.Sprintf("%s x%d", s.itemName(entry.TemplateID), entry.Count))
+				} else {
+					items = append(items, s.itemName(entry.TemplateID))
+				}
+			}
+			lines = append(lines, "Carrying: "+strings.Join(items, ", "))
+		}
+
+		for i, line := range lines {
+			ebitenutil.DebugPrintAt(screen, line, detailX, startY+i*lineHeight)
+		}
+	}
+
+	mode := "Mode: Permadeath, death ends the run"
+	if s.practice {
+		mode = "Mode: Practice, death sends you back to the floor's entrance"
+	}
+	ebitenutil.DebugPrintAt(screen, mode, (screenWidth-len(mode)*6)/2, screenHeight-76)
+
+	healing := "Healing drops: Fixed"
+	if s.adaptiveHealing {
+		healing = "Healing drops: Adaptive, more often while you struggle, less while you thrive"
+	}
+	ebitenutil.DebugPrintAt(screen, healing, (screenWidth-len(healing)*6)/2, screenHeight-60)
+
+	help := "Up/Down: Select  P: Toggle practice mode  H: Toggle adaptive healing  Enter: Start  ESC: Back"
+	ebitenutil.DebugPrintAt(screen, help, (screenWidth-len(help)*6)/2, screenHeight-40)
+}
+
+// itemName returns the display name of an item template, falling back to its ID
+func (s *ClassSelectScreen) itemName(templateID string) string {
+	if template, ok := s.templateManager.GetItemTemplate(templateID); ok {
+		return template.Name
+	}
+	return templateID
+}
+
+// Layout implements the Screen interface
+func (s *ClassSelectScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return config.ScreenWidth * config.TileSize, config.ScreenHeight * config.TileSize
+}
@@ -0,0 +1,104 @@
+package screens
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/systems"
+)
+
+// ContextMenuScreen lists what the player can do on their tile and to their neighbours.
+// Enter carries out the selected action and closes the menu, Escape closes it.
+type ContextMenuScreen struct {
+	*BaseScreen
+	world      *ecs.World
+	turns      *systems.PlayerTurnProcessorSystem
+	playerID   ecs.EntityID
+	actions    []systems.ContextAction
+	selected   int
+	width      int
+	height     int
+	background color.Color
+}
+
+// NewContextMenuScreen creates a context menu with the given actions
+func NewContextMenuScreen(world *ecs.World, turns *systems.PlayerTurnProcessorSystem, playerID ecs.EntityID, actions []systems.ContextAction) *ContextMenuScreen {
+	// Size the menu to its longest entry
+	width := len("Up/Down: Select  Enter: Do  ESC: Close")*6 + 20
+	for _, action := range actions {
+		width = max(width, (len(action.Label)+2)*6+20)
+	}
+	return &ContextMenuScreen{
+		BaseScreen: NewBaseScreen(),
+		world:      world,
+		turns:      turns,
+		playerID:   playerID,
+		actions:    actions,
+		width:      width,
+		height:     len(actions)*16 + 60,
+		background: color.RGBA{0, 0, 0, 230},
+	}
+}
+
+// Update handles input for the context menu
+func (s *ContextMenuScreen) Update() error {
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) && s.selected > 0 {
+		s.selected--
+	}
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) && s.selected < len(s.actions)-1 {
+		s.selected++
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEnter) && s.selected < len(s.actions) {
+		s.turns.PerformContextAction(s.world, s.playerID, s.actions[s.selected])
+		return ErrCloseScreen
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEscape) {
+		return ErrCloseScreen
+	}
+
+	return nil
+}
+
+// Draw renders the action list in a small framed box
+func (s *ContextMenuScreen) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := screen.Size()
+	x := (screenWidth - s.width) / 2
+	y := (screenHeight - s.height) / 2
+
+	modal := ebiten.NewImage(s.width, s.height)
+	modal.Fill(s.background)
+
+	// Draw frame
+	frameWidth := 2.0
+	ebitenutil.DrawRect(modal, 0, 0, frameWidth, float64(s.height), color.White)                           // Left
+	ebitenutil.DrawRect(modal, float64(s.width)-frameWidth, 0, frameWidth, float64(s.height), color.White) // Right
+	ebitenutil.DrawRect(modal, 0, 0, float64(s.width), frameWidth, color.White)                            // Top
+	ebitenutil.DrawRect(modal, 0, float64(s.height)-frameWidth, float64(s.width), frameWidth, color.White) // Bottom
+
+	title := "ACTIONS"
+	ebitenutil.DebugPrintAt(modal, title, (s.width-len(title)*6)/2, 8)
+
+	for i, action := range s.actions {
+		prefix := "  "
+		if i == s.selected {
+			prefix = "> "
+		}
+		ebitenutil.DebugPrintAt(modal, prefix+action.Label, 10, 30+i*16)
+	}
+
+	ebitenutil.DebugPrintAt(modal, "Up/Down: Select  Enter: Do  ESC: Close", 10, s.height-20)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(modal, op)
+}
+
+// Layout implements the Screen interface
+func (s *ContextMenuScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
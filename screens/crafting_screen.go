@@ -0,0 +1,183 @@
+package screens
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/systems"
+)
+
+// CraftingScreen lists the recipes that can be made at a crafting station. Recipes the
+// player lacks ingredients for are grayed out with the missing ingredients listed.
+type CraftingScreen struct {
+	*BaseScreen
+	world        *ecs.World
+	crafting     *systems.CraftingSystem
+	playerID     ecs.EntityID
+	stationName  string
+	selected     int
+	scrollOffset int
+	width        int
+	height       int
+	background   color.Color
+	textColor    color.RGBA
+	grayedColor  color.RGBA
+}
+
+// NewCraftingScreen creates a new crafting screen for the player at a station
+func NewCraftingScreen(world *ecs.World, crafting *systems.CraftingSystem, playerID, stationID ecs.EntityID) *CraftingScreen {
+	stationName := "Workbench"
+	if nameComp, exists := world.GetComponent(stationID, components.Name); exists {
+		stationName = nameComp.(*components.NameComponent).Name
+	}
+	return &CraftingScreen{
+		BaseScreen:  NewBaseScreen(),
+		world:       world,
+		crafting:    crafting,
+		playerID:    playerID,
+		stationName: stationName,
+		width:       600,
+		height:      400,
+		background:  color.RGBA{0, 0, 0, 255},
+		textColor:   color.RGBA{255, 255, 255, 255},
+		grayedColor: color.RGBA{110, 110, 110, 255},
+	}
+}
+
+// Update handles input for the crafting screen
+func (s *CraftingScreen) Update() error {
+	recipes := s.crafting.GetRecipes()
+
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) && s.selected > 0 {
+		s.selected--
+	}
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) && s.selected < len(recipes)-1 {
+		s.selected++
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEnter) && s.selected < len(recipes) {
+		s.crafting.Craft(s.world, s.playerID, recipes[s.selected])
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEscape) {
+		return ErrCloseScreen
+	}
+
+	return nil
+}
+
+// Draw renders the recipe list on the left and the selected recipe on the right
+func (s *CraftingScreen) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := screen.Size()
+	x := (screenWidth - s.width) / 2
+	y := (screenHeight - s.height) / 2
+
+	modal := ebiten.NewImage(s.width, s.height)
+	modal.Fill(s.background)
+
+	// Draw frame
+	frameWidth := 2.0
+	ebitenutil.DrawRect(modal, 0, 0, frameWidth, float64(s.height), color.White)                           // Left
+	ebitenutil.DrawRect(modal, float64(s.width)-frameWidth, 0, frameWidth, float64(s.height), color.White) // Right
+	ebitenutil.DrawRect(modal, 0, 0, float64(s.width), frameWidth, color.White)                            // Top
+	ebitenutil.DrawRect(modal, 0, float64(s.height)-frameWidth, float64(s.width), frameWidth, color.White) // Bottom
+
+	title := strings.ToUpper(s.stationName)
+	ebitenutil.DebugPrintAt(modal, title, (s.width-len(title)*6)/2, 8)
+
+	recipes := s.crafting.GetRecipes()
+	if len(recipes) == 0 {
+		ebitenutil.DebugPrintAt(modal, "You don't know how to make anything yet.", 10, 40)
+	} else {
+		if s.selected >= len(recipes) {
+			s.selected = len(recipes) - 1
+		}
+
+		// Keep the selection inside the visible window
+		startY := 30
+		lineHeight := 16
+		maxLines := (s.height - startY - 30) / lineHeight
+		if s.selected < s.scrollOffset {
+			s.scrollOffset = s.selected
+		}
+		if s.selected >= s.scrollOffset+maxLines {
+			s.scrollOffset = s.selected - maxLines + 1
+		}
+
+		for i := 0; i < maxLines && s.scrollOffset+i < len(recipes); i++ {
+			recipe := recipes[s.scrollOffset+i]
+			prefix := "  "
+			if s.scrollOffset+i == s.selected {
+				prefix = "> "
+			}
+			lineColor := s.textColor
+			if len(s.crafting.MissingIngredients(s.world, s.playerID, recipe)) > 0 {
+				lineColor = s.grayedColor
+			}
+			s.printAt(modal, prefix+s.crafting.RecipeName(recipe), 10, startY+i*lineHeight, lineColor)
+		}
+
+		s.drawRecipeDetails(modal, recipes[s.selected], 220, startY, lineHeight)
+	}
+
+	ebitenutil.DebugPrintAt(modal, "Up/Down: Select  Enter: Craft  ESC: Close", 10, s.height-20)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(modal, op)
+}
+
+// drawRecipeDetails shows what a recipe makes, what it needs and what is missing
+func (s *CraftingScreen) drawRecipeDetails(modal *ebiten.Image, recipe *data.Recipe, detailX, startY, lineHeight int) {
+	lines := []string{s.crafting.RecipeName(recipe)}
+	if recipe.OutputCount > 1 {
+		lines[0] = fmt.Sprintf("%s x%d", lines[0], recipe.OutputCount)
+	}
+	lines = append(lines, "")
+	lines = append(lines, wrapText(recipe.Description, (s.width-detailX-10)/6)...)
+	lines = append(lines, "", "Requires:")
+	for _, input := range recipe.Inputs {
+		lines = append(lines, fmt.Sprintf("  %s x%d", s.crafting.ItemName(input.TemplateID), input.Count))
+	}
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(modal, line, detailX, startY+i*lineHeight)
+	}
+
+	missing := s.crafting.MissingIngredients(s.world, s.playerID, recipe)
+	if len(missing) == 0 {
+		return
+	}
+	missingY := startY + (len(lines)+1)*lineHeight
+	s.printAt(modal, "Missing:", detailX, missingY, s.grayedColor)
+	for i, ingredient := range missing {
+		s.printAt(modal, "  "+ingredient, detailX, missingY+(i+1)*lineHeight, s.grayedColor)
+	}
+}
+
+// printAt draws a line of debug text in the given color
+func (s *CraftingScreen) printAt(target *ebiten.Image, text string, x, y int, textColor color.RGBA) {
+	lineImg := ebiten.NewImage(s.width, 16)
+	ebitenutil.DebugPrintAt(lineImg, text, 0, 0)
+
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(
+		float64(textColor.R)/255.0,
+		float64(textColor.G)/255.0,
+		float64(textColor.B)/255.0,
+		1.0,
+	)
+	op.GeoM.Translate(float64(x), float64(y))
+	target.DrawImage(lineImg, op)
+}
+
+// Layout implements the Screen interface
+func (s *CraftingScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
@@ -1,11 +1,11 @@
 package screens
 
 import (
+	"errors"
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"ebiten-rogue/systems"
 )
@@ -35,15 +35,15 @@ func NewDebugScreen() *DebugScreen {
 // Update handles input for the debug screen
 func (s *DebugScreen) Update() error {
 	// Handle scrolling through debug messages with arrow keys
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) {
 		s.scrollUp()
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) {
 		s.scrollDown()
 	}
 
 	// ESC to close debug window
-	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
+	if systems.KeyPressed(ebiten.KeyEscape) {
 		return ErrCloseScreen
 	}
 
@@ -173,4 +173,4 @@ func (s *DebugScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 // ErrCloseScreen is returned when the screen should be closed
-var ErrCloseScreen = error(nil)
+var ErrCloseScreen = errors.New("close screen")
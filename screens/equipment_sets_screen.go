@@ -0,0 +1,142 @@
+package screens
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/systems"
+)
+
+// EquipmentSetsScreen lists the player's saved equipment sets. S saves what is worn
+// into the selected set, Enter puts the selected set on as a single turn.
+type EquipmentSetsScreen struct {
+	*BaseScreen
+	world      *ecs.World
+	equipment  *systems.EquipmentSystem
+	playerID   ecs.EntityID
+	selected   int
+	width      int
+	height     int
+	background color.Color
+}
+
+// NewEquipmentSetsScreen creates the equipment sets screen for the player
+func NewEquipmentSetsScreen(world *ecs.World, equipment *systems.EquipmentSystem, playerID ecs.EntityID) *EquipmentSetsScreen {
+	return &EquipmentSetsScreen{
+		BaseScreen: NewBaseScreen(),
+		world:      world,
+		equipment:  equipment,
+		playerID:   playerID,
+		width:      420,
+		height:     components.EquipmentSetCount*48 + 70,
+		background: color.RGBA{0, 0, 0, 230},
+	}
+}
+
+// Update handles input for the equipment sets screen
+func (s *EquipmentSetsScreen) Update() error {
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) && s.selected > 0 {
+		s.selected--
+	}
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) && s.selected < components.EquipmentSetCount-1 {
+		s.selected++
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyS) {
+		if err := s.equipment.SaveEquipmentSet(s.playerID, s.selected); err != nil {
+			systems.GetMessageLog().Add(err.Error())
+		}
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEnter) {
+		changed, err := s.equipment.ApplyEquipmentSet(s.playerID, s.selected)
+		if err != nil {
+			systems.GetMessageLog().Add(err.Error())
+			return nil
+		}
+		if changed {
+			s.world.EmitEvent(systems.TurnCompletedEvent{EntityID: s.playerID})
+		}
+		return ErrCloseScreen
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEscape) || systems.KeyJustPressed(ebiten.KeyW) {
+		return ErrCloseScreen
+	}
+
+	return nil
+}
+
+// Draw renders each set with the items it holds
+func (s *EquipmentSetsScreen) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := screen.Size()
+	x := (screenWidth - s.width) / 2
+	y := (screenHeight - s.height) / 2
+
+	modal := ebiten.NewImage(s.width, s.height)
+	modal.Fill(s.background)
+
+	// Draw frame
+	frameWidth := 2.0
+	ebitenutil.DrawRect(modal, 0, 0, frameWidth, float64(s.height), color.White)                           // Left
+	ebitenutil.DrawRect(modal, float64(s.width)-frameWidth, 0, frameWidth, float64(s.height), color.White) // Right
+	ebitenutil.DrawRect(modal, 0, 0, float64(s.width), frameWidth, color.White)                            // Top
+	ebitenutil.DrawRect(modal, 0, float64(s.height)-frameWidth, float64(s.width), frameWidth, color.White) // Bottom
+
+	title := "EQUIPMENT SETS"
+	ebitenutil.DebugPrintAt(modal, title, (s.width-len(title)*6)/2, 8)
+
+	sets := systems.GetEquipmentSets(s.world, s.playerID)
+	for i := 0; i < components.EquipmentSetCount; i++ {
+		prefix := "  "
+		if i == s.selected {
+			prefix = "> "
+		}
+		name, contents := fmt.Sprintf("Set %d", i+1), "(empty)"
+		if sets != nil && !sets.Sets[i].IsEmpty() {
+			name = fmt.Sprintf("%d) %s", i+1, sets.Sets[i].Name)
+			contents = s.describeSet(sets.Sets[i])
+		}
+		ebitenutil.DebugPrintAt(modal, prefix+name, 10, 30+i*48)
+		ebitenutil.DebugPrintAt(modal, "    "+contents, 10, 46+i*48)
+	}
+
+	ebitenutil.DebugPrintAt(modal, "Up/Down: Select  S: Save worn  Enter: Wear  ESC: Close", 10, s.height-20)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(modal, op)
+}
+
+// describeSet lists the names of a set's items in slot order, cut to fit the screen
+func (s *EquipmentSetsScreen) describeSet(set components.EquipmentSet) string {
+	var names []string
+	for _, slot := range components.EquipmentSlots {
+		if itemID := set.Items[slot]; itemID != 0 {
+			name := "(gone)"
+			if nameComp, exists := s.world.GetComponent(itemID, components.Name); exists {
+				name = nameComp.(*components.NameComponent).Name
+			}
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "(nothing worn)"
+	}
+	text := strings.Join(names, ", ")
+	if maxChars := (s.width - 50) / 6; len(text) > maxChars {
+		text = text[:maxChars-3] + "..."
+	}
+	return text
+}
+
+// Layout implements the Screen interface
+func (s *EquipmentSetsScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
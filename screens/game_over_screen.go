@@ -5,17 +5,27 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 
 	"ebiten-rogue/config"
+	"ebiten-rogue/ecs"
+	"ebiten-rogue/systems"
 )
 
-// GameOverScreen displays the game over message
+// GameOverScreen displays the game over message, or the floor the player died on
+// when a death snapshot was captured
 type GameOverScreen struct {
 	*BaseScreen
+	world        *ecs.World
+	renderSystem *systems.RenderSystem
+	snapshot     *systems.DeathSnapshot
 }
 
-// NewGameOverScreen creates a new game over screen
-func NewGameOverScreen() *GameOverScreen {
+// NewGameOverScreen creates a new game over screen. A nil snapshot shows only the
+// game over message.
+func NewGameOverScreen(world *ecs.World, renderSystem *systems.RenderSystem, snapshot *systems.DeathSnapshot) *GameOverScreen {
 	return &GameOverScreen{
-		BaseScreen: NewBaseScreen(),
+		BaseScreen:   NewBaseScreen(),
+		world:        world,
+		renderSystem: renderSystem,
+		snapshot:     snapshot,
 	}
 }
 
@@ -26,6 +36,11 @@ func (s *GameOverScreen) Update() error {
 
 // Draw draws the game over screen
 func (s *GameOverScreen) Draw(screen *ebiten.Image) {
+	if s.snapshot != nil {
+		s.renderSystem.DrawDeathMap(s.world, screen, s.snapshot)
+		return
+	}
+
 	// Draw game over message
 	screenWidth, screenHeight := screen.Size()
 	text := "Game Over!\n\nPress Escape to return to the start screen"
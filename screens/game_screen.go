@@ -2,7 +2,6 @@ package screens
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"ebiten-rogue/config"
 	"ebiten-rogue/ecs"
@@ -74,14 +73,24 @@ func NewGameScreen(
 		audioSystem:               audioSystem,
 		deathSystem:               deathSystem,
 		needsRedraw:               true,
-		screenStack:               NewScreenStack(),
+		screenStack:               newModalStack(),
 	}
 }
 
+// newModalStack creates the stack of modals shown over the game. Modals open and close
+// on a single key press over the live map, so they switch instantly instead of fading.
+func newModalStack() *ScreenStack {
+	stack := NewScreenStack()
+	stack.SetFadeDuration(0)
+	return stack
+}
+
 // Update handles game updates
 func (s *GameScreen) Update() error {
+	keybindings := s.playerTurnProcessorSystem.Keybindings()
+
 	// Toggle debug message window with F1 key
-	if inpututil.IsKeyJustPressed(ebiten.KeyF1) {
+	if keybindings.JustPressed(systems.ActionDebugLog) {
 		if s.screenStack.Peek() != nil {
 			// If there's a screen on the stack, pop it (close debug screen)
 			s.screenStack.Pop()
@@ -93,6 +102,37 @@ func (s *GameScreen) Update() error {
 		s.needsRedraw = true
 	}
 
+	// Toggle the bestiary with F2
+	if keybindings.JustPressed(systems.ActionBestiary) {
+		if s.screenStack.Peek() != nil {
+			s.screenStack.Pop()
+		} else {
+			for _, system := range s.world.GetSystems() {
+				if bestiary, ok := system.(*systems.BestiarySystem); ok {
+					s.screenStack.Push(NewBestiaryScreen(bestiary))
+					break
+				}
+			}
+		}
+		s.needsRedraw = true
+	}
+
+	// Toggle the turn order HUD with F3
+	if keybindings.JustPressed(systems.ActionTurnOrder) {
+		s.renderSystem.ToggleTurnOrderHUD()
+		s.needsRedraw = true
+	}
+
+	// Toggle the debug overlay with F4 and switch its view with F5
+	if keybindings.JustPressed(systems.ActionDebugOverlay) {
+		s.renderSystem.ToggleDebugOverlay()
+		s.needsRedraw = true
+	}
+	if keybindings.JustPressed(systems.ActionDebugView) {
+		s.renderSystem.CycleDebugOverlay()
+		s.needsRedraw = true
+	}
+
 	// Don't process input if a map transition is in progress
 	if s.mapRegistrySystem.IsTransitionInProgress() {
 		systems.GetMessageLog().Add("Update skipped: map transition in progress")
@@ -105,6 +145,10 @@ func (s *GameScreen) Update() error {
 			s.screenStack.Pop()
 		}
 		s.needsRedraw = true
+
+		// The key that closed the screen, such as Enter in the context menu, shouldn't
+		// act on the game as well
+		return nil
 	}
 
 	// Only update the game world if no modal is open
@@ -113,6 +157,35 @@ func (s *GameScreen) Update() error {
 		s.world.Update(1.0 / 60.0)
 		// Always redraw after updating systems
 		s.needsRedraw = true
+
+		// Open the crafting screen when the player bumps into a station
+		for _, system := range s.world.GetSystems() {
+			if crafting, ok := system.(*systems.CraftingSystem); ok {
+				if stationID := crafting.TakePendingStation(); stationID != 0 {
+					playerEntities := s.world.GetEntitiesWithTag("player")
+					if len(playerEntities) > 0 {
+						s.screenStack.Push(NewCraftingScreen(s.world, crafting, playerEntities[0].ID, stationID))
+					}
+				}
+				break
+			}
+		}
+
+		// Open the equipment sets with W
+		if keybindings.JustPressed(systems.ActionEquipmentSets) && s.screenStack.Peek() == nil {
+			playerEntities := s.world.GetEntitiesWithTag("player")
+			if len(playerEntities) > 0 {
+				s.screenStack.Push(NewEquipmentSetsScreen(s.world, s.equipmentSystem, playerEntities[0].ID))
+			}
+		}
+
+		// Open the context menu when the player asks for it
+		if actions := s.playerTurnProcessorSystem.TakeContextMenu(); len(actions) > 0 {
+			playerEntities := s.world.GetEntitiesWithTag("player")
+			if len(playerEntities) > 0 {
+				s.screenStack.Push(NewContextMenuScreen(s.world, s.playerTurnProcessorSystem, playerEntities[0].ID, actions))
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,182 @@
+package screens
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"ebiten-rogue/systems"
+)
+
+// optionsRowHeight is the height of one action's line in pixels
+const optionsRowHeight = 16
+
+// OptionsScreen lists the player's map actions with the keys bound to each. Enter on
+// an action waits for a key to add to it, Backspace clears its keys, and the row
+// below the actions puts every action back on its default keys. A key already bound
+// to another action is only moved over once it is pressed a second time. Changes are
+// saved to the keybindings file as they are made.
+type OptionsScreen struct {
+	*BaseScreen
+	keybindings *systems.Keybindings
+	selected    int
+	listening   bool       // Waiting for a key to bind to the selected action
+	pending     ebiten.Key // Key bound elsewhere that was pressed once, while hasPending
+	hasPending  bool
+	status      string // Outcome of the last change, or a conflict warning
+	width       int
+	height      int
+	background  color.Color
+}
+
+// NewOptionsScreen creates the options screen for a set of keybindings
+func NewOptionsScreen(keybindings *systems.Keybindings) *OptionsScreen {
+	return &OptionsScreen{
+		BaseScreen:  NewBaseScreen(),
+		keybindings: keybindings,
+		width:       520,
+		height:      (len(systems.KeyActions)+1)*optionsRowHeight + 100,
+		background:  color.RGBA{0, 0, 0, 230},
+	}
+}
+
+// resetRow is the index of the reset-to-defaults row, after the actions
+func (s *OptionsScreen) resetRow() int {
+	return len(systems.KeyActions)
+}
+
+// Update handles input for the options screen
+func (s *OptionsScreen) Update() error {
+	if s.listening {
+		s.listen()
+		return nil
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) && s.selected > 0 {
+		s.selected--
+	}
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) && s.selected < s.resetRow() {
+		s.selected++
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEnter) {
+		if s.selected == s.resetRow() {
+			s.keybindings.ResetToDefaults()
+			s.status = "Every action is back on its default keys."
+		} else {
+			s.listening = true
+			s.status = fmt.Sprintf("Press a key for %s, Esc to cancel.", systems.KeyActions[s.selected].Name)
+		}
+		return nil
+	}
+
+	if (systems.KeyJustPressed(ebiten.KeyBackspace) || systems.KeyJustPressed(ebiten.KeyDelete)) && s.selected < s.resetRow() {
+		entry := systems.KeyActions[s.selected]
+		s.keybindings.Clear(entry.Action)
+		s.status = fmt.Sprintf("%s has no keys now.", entry.Name)
+	}
+
+	if systems.KeyJustPressed(ebiten.KeyEscape) {
+		return ErrCloseScreen
+	}
+
+	return nil
+}
+
+// listen binds the next key pressed to the selected action, warning first if another
+// action already has it
+func (s *OptionsScreen) listen() {
+	keys := systems.JustPressedKeys()
+	if len(keys) == 0 {
+		return
+	}
+	key := keys[0]
+	entry := systems.KeyActions[s.selected]
+
+	if key == ebiten.KeyEscape {
+		s.listening = false
+		s.hasPending = false
+		s.status = "Nothing rebound."
+		return
+	}
+
+	conflicts := s.keybindings.Conflicts(entry.Action, key)
+	if len(conflicts) > 0 && !(s.hasPending && s.pending == key) {
+		names := make([]string, len(conflicts))
+		for i, action := range conflicts {
+			names[i] = systems.ActionName(action)
+		}
+		s.pending, s.hasPending = key, true
+		s.status = fmt.Sprintf("%s is already bound to %s. Press it again to move it, or another key.", key, strings.Join(names, " and "))
+		return
+	}
+
+	s.listening = false
+	s.hasPending = false
+	if s.keybindings.Bind(entry.Action, key) {
+		s.status = fmt.Sprintf("%s is bound to %s.", key, entry.Name)
+	} else {
+		s.status = fmt.Sprintf("%s already does that.", key)
+	}
+}
+
+// Draw renders each action with its keys, the reset row and the last status
+func (s *OptionsScreen) Draw(screen *ebiten.Image) {
+	screenWidth, screenHeight := screen.Size()
+	x := (screenWidth - s.width) / 2
+	y := (screenHeight - s.height) / 2
+
+	modal := ebiten.NewImage(s.width, s.height)
+	modal.Fill(s.background)
+
+	// Draw frame
+	frameWidth := 2.0
+	ebitenutil.DrawRect(modal, 0, 0, frameWidth, float64(s.height), color.White)                           // Left
+	ebitenutil.DrawRect(modal, float64(s.width)-frameWidth, 0, frameWidth, float64(s.height), color.White) // Right
+	ebitenutil.DrawRect(modal, 0, 0, float64(s.width), frameWidth, color.White)                            // Top
+	ebitenutil.DrawRect(modal, 0, float64(s.height)-frameWidth, float64(s.width), frameWidth, color.White) // Bottom
+
+	title := "OPTIONS - KEYS"
+	ebitenutil.DebugPrintAt(modal, title, (s.width-len(title)*6)/2, 8)
+
+	for i, entry := range systems.KeyActions {
+		prefix := "  "
+		if i == s.selected {
+			prefix = "> "
+		}
+		keys := "(none)"
+		if bound := s.keybindings.Keys(entry.Action); len(bound) > 0 {
+			names := make([]string, len(bound))
+			for j, key := range bound {
+				names[j] = key.String()
+			}
+			keys = strings.Join(names, ", ")
+		}
+		if s.listening && i == s.selected {
+			keys += " + ..."
+		}
+		ebitenutil.DebugPrintAt(modal, prefix+entry.Name, 10, 30+i*optionsRowHeight)
+		ebitenutil.DebugPrintAt(modal, keys, 190, 30+i*optionsRowHeight)
+	}
+
+	prefix := "  "
+	if s.selected == s.resetRow() {
+		prefix = "> "
+	}
+	ebitenutil.DebugPrintAt(modal, prefix+"[ Reset to defaults ]", 10, 38+s.resetRow()*optionsRowHeight)
+
+	ebitenutil.DebugPrintAt(modal, s.status, 10, s.height-44)
+	ebitenutil.DebugPrintAt(modal, "Up/Down: Select  Enter: Add key  Backspace: Clear  ESC: Close", 10, s.height-20)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(modal, op)
+}
+
+// Layout implements the Screen interface
+func (s *OptionsScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return outsideWidth, outsideHeight
+}
@@ -14,20 +14,55 @@ type Screen interface {
 	Layout(outsideWidth, outsideHeight int) (int, int)
 }
 
-// ScreenStack manages a stack of screens
+// DefaultFadeDuration is how long, in seconds, a screen stack crossfades between its
+// old and new screens
+const DefaultFadeDuration = 0.3
+
+// ScreenStack manages a stack of screens. Pushing or popping crossfades from the
+// screens shown before to the ones shown after, and input waits until the fade is over.
 type ScreenStack struct {
 	screens []Screen
+
+	fadeDuration float64       // Length of a crossfade in seconds, 0 for instant changes
+	fadeTimer    float64       // Time into the current crossfade
+	fadeScreens  []Screen      // Screens being faded out, nil when not fading
+	fadeImage    *ebiten.Image // Where the faded-out screens are drawn
 }
 
 // NewScreenStack creates a new screen stack
 func NewScreenStack() *ScreenStack {
 	return &ScreenStack{
-		screens: make([]Screen, 0),
+		screens:      make([]Screen, 0),
+		fadeDuration: DefaultFadeDuration,
+	}
+}
+
+// SetFadeDuration sets how long screen changes crossfade for, 0 to switch instantly
+func (s *ScreenStack) SetFadeDuration(seconds float64) {
+	s.fadeDuration = max(seconds, 0)
+	if s.fadeDuration == 0 {
+		s.fadeScreens = nil
 	}
 }
 
+// IsFading returns whether a crossfade is running, during which the screens get no input
+func (s *ScreenStack) IsFading() bool {
+	return s.fadeScreens != nil
+}
+
+// startFade remembers the screens shown now so they can be faded out. Several changes
+// made at once, like popping two screens and pushing one, make up a single fade.
+func (s *ScreenStack) startFade() {
+	if s.fadeDuration == 0 || s.IsFading() || len(s.screens) == 0 {
+		return
+	}
+	s.fadeScreens = append([]Screen{}, s.screens...)
+	s.fadeTimer = 0
+}
+
 // Push adds a new screen to the top of the stack
 func (s *ScreenStack) Push(screen Screen) {
+	s.startFade()
 	s.screens = append(s.screens, screen)
 }
 
@@ -36,6 +71,7 @@ func (s *ScreenStack) Pop() Screen {
 	if len(s.screens) == 0 {
 		return nil
 	}
+	s.startFade()
 	top := s.screens[len(s.screens)-1]
 	s.screens = s.screens[:len(s.screens)-1]
 	return top
@@ -49,19 +85,46 @@ func (s *ScreenStack) Peek() Screen {
 	return s.screens[len(s.screens)-1]
 }
 
-// Update updates the top screen
+// Update advances any crossfade, then updates the top screen once it's over
 func (s *ScreenStack) Update() error {
+	if s.IsFading() {
+		s.fadeTimer += 1.0 / float64(ebiten.TPS())
+		if s.fadeTimer < s.fadeDuration {
+			return nil
+		}
+		s.fadeScreens = nil
+	}
+
 	if top := s.Peek(); top != nil {
 		return top.Update()
 	}
 	return nil
 }
 
-// Draw draws all screens from bottom to top
+// Draw draws all screens from bottom to top, with the screens being faded out on top
 func (s *ScreenStack) Draw(screen *ebiten.Image) {
 	for _, scr := range s.screens {
 		scr.Draw(screen)
 	}
+	if !s.IsFading() {
+		return
+	}
+
+	width, height := screen.Size()
+	if s.fadeImage == nil {
+		s.fadeImage = ebiten.NewImage(width, height)
+	} else if w, h := s.fadeImage.Size(); w != width || h != height {
+		s.fadeImage.Dispose()
+		s.fadeImage = ebiten.NewImage(width, height)
+	}
+	s.fadeImage.Clear()
+	for _, scr := range s.fadeScreens {
+		scr.Draw(s.fadeImage)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(1, 1, 1, 1-s.fadeTimer/s.fadeDuration)
+	screen.DrawImage(s.fadeImage, op)
 }
 
 // Layout handles layout for the top screen
@@ -7,7 +7,6 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"ebiten-rogue/config"
 	"ebiten-rogue/systems"
@@ -66,15 +65,15 @@ func (s *StartScreen) Update() error {
 	}
 
 	// Handle arrow key navigation
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if systems.KeyJustPressed(ebiten.KeyArrowUp) {
 		s.selectedOption = (s.selectedOption - 1 + len(s.options)) % len(s.options)
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	if systems.KeyJustPressed(ebiten.KeyArrowDown) {
 		s.selectedOption = (s.selectedOption + 1) % len(s.options)
 	}
 
 	// Handle selection
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if systems.KeyJustPressed(ebiten.KeyEnter) {
 		switch s.selectedOption {
 		case 0: // New Game
 			return ErrNewGame
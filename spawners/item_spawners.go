@@ -29,13 +29,26 @@ func (s *ItemSpawner) SetSpawnMapID(mapID ecs.EntityID) {
 	s.spawnMapID = mapID
 }
 
-// CreateContainer creates a container from a template
-func (s *ItemSpawner) CreateContainer(x, y int, templateID string) (*ecs.Entity, error) {
+// CreateContainer creates a container from a template. Given contents replace the
+// template's initial items, and the container is made big enough to hold them.
+func (s *ItemSpawner) CreateContainer(x, y int, templateID string, contents []data.LoadoutItem) (*ecs.Entity, error) {
 	// Get the container template
 	template, exists := s.templateManager.GetContainerTemplate(templateID)
 	if !exists {
 		return nil, fmt.Errorf("no container template found with ID '%s'", templateID)
 	}
+	capacity := template.Capacity
+	if contents == nil {
+		for _, initialItem := range template.InitialItems {
+			contents = append(contents, data.LoadoutItem(initialItem))
+		}
+	} else {
+		total := 0
+		for _, entry := range contents {
+			total += max(entry.Count, 1)
+		}
+		capacity = max(capacity, total)
+	}
 
 	// Create the container entity
 	container := s.world.CreateEntity()
@@ -56,14 +69,14 @@ func (s *ItemSpawner) CreateContainer(x, y int, templateID string) (*ecs.Entity,
 	))
 
 	// Create the container component
-	containerComp := components.NewContainerComponent(template.Capacity)
+	containerComp := components.NewContainerComponent(capacity)
 	containerComp.Locked = template.Locked
 
 	// Add initial items if specified
 	itemsCreated := 0
-	for _, initialItem := range template.InitialItems {
+	for _, initialItem := range contents {
 		systems.GetDebugLog().Add(fmt.Sprintf("Processing initial item entry: template_id=%s, count=%d", initialItem.TemplateID, initialItem.Count))
-		for i := 0; i < initialItem.Count; i++ {
+		for i := 0; i < max(initialItem.Count, 1); i++ {
 			// Create item (position doesn't matter since it's going in container)
 			item, err := s.CreateItem(0, 0, initialItem.TemplateID, true)
 			if err != nil {
@@ -171,12 +184,44 @@ func (s *ItemSpawner) CreateItem(x, y int, templateID string, addToContainer boo
 						Property:  effectMap["target"].(map[string]interface{})["property"].(string),
 					},
 				}
+				if school, ok := effectMap["school"].(string); ok {
+					effect.School = school
+				}
+				if stacking, ok := effectMap["stacking"].(string); ok {
+					effect.Stacking = components.StackingPolicy(stacking)
+				}
 				effects = append(effects, effect)
 			}
 
 			// Store the effects in the item's Data field
 			itemComp.Data = effects
 		}
+
+		itemComp.Sockets = template.Sockets
+		itemComp.TwoHanded = template.TwoHanded
+		itemComp.Rarity = template.Rarity
+		if itemComp.Rarity == "" {
+			itemComp.Rarity = "common"
+		}
+
+		// Items with a fuse are bombs
+		if template.Fuse > 0 {
+			s.world.AddComponent(itemEntity.ID, components.Bomb, components.NewBombComponent(template.Fuse, template.BlastRadius))
+		}
+
+		// Items that burn are torches
+		if template.BurnTurns > 0 {
+			s.world.AddComponent(itemEntity.ID, components.Torch, components.NewTorchComponent(template.BurnTurns, template.LightRange))
+		}
+
+		if template.Scroll != "" {
+			scroll := components.NewScrollComponent(template.Scroll, template.BlastRadius)
+			scroll.Jumps = template.ChainJumps
+			scroll.JumpRange = template.ChainRange
+			scroll.Falloff = template.ChainFalloff
+			scroll.Duration = template.TerrainDuration
+			s.world.AddComponent(itemEntity.ID, components.Scroll, scroll)
+		}
 	} else {
 		// Apply any provided options
 		options := defaultItemOptions()
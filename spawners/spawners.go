@@ -9,6 +9,7 @@ import (
 	"ebiten-rogue/components"
 	"ebiten-rogue/data"
 	"ebiten-rogue/ecs"
+	"ebiten-rogue/systems"
 )
 
 // EntitySpawner manages the creation of game entities
@@ -34,8 +35,9 @@ func (s *EntitySpawner) SetSpawnMapID(mapID ecs.EntityID) {
 	s.spawnMapID = mapID
 }
 
-// CreatePlayer creates a player entity at the given position
-func (s *EntitySpawner) CreatePlayer(x, y int) *ecs.Entity {
+// CreatePlayer creates a player entity at the given position. The loadout sets the
+// starting stats, equipment and inventory; nil gives the default setup.
+func (s *EntitySpawner) CreatePlayer(x, y int, loadout *data.Loadout) *ecs.Entity {
 	// Create the player entity
 	playerEntity := s.world.CreateEntity()
 	playerEntity.AddTag("player")
@@ -55,7 +57,7 @@ func (s *EntitySpawner) CreatePlayer(x, y int) *ecs.Entity {
 
 	s.world.AddComponent(playerEntity.ID, components.Player, &components.PlayerComponent{})
 
-	s.world.AddComponent(playerEntity.ID, components.Stats, &components.StatsComponent{
+	stats := &components.StatsComponent{
 		Health:        100,
 		MaxHealth:     100,
 		Attack:        5,
@@ -63,20 +65,42 @@ func (s *EntitySpawner) CreatePlayer(x, y int) *ecs.Entity {
 		Level:         1,
 		Exp:           0,
 		HealingFactor: 1,
-	})
+	}
+	sightRange := 4
+	if loadout != nil {
+		stats.Health = loadout.Stats.Health
+		stats.MaxHealth = loadout.Stats.Health
+		stats.Attack = loadout.Stats.Attack
+		stats.Defense = loadout.Stats.Defense
+		stats.Evasion = loadout.Stats.Evasion
+		stats.Accuracy = loadout.Stats.Accuracy
+		stats.Stealth = loadout.Stats.Stealth
+		if loadout.Stats.HealingFactor > 0 {
+			stats.HealingFactor = loadout.Stats.HealingFactor
+		}
+		if loadout.Stats.SightRange > 0 {
+			sightRange = loadout.Stats.SightRange
+		}
+	}
+	s.world.AddComponent(playerEntity.ID, components.Stats, stats)
 
 	s.world.AddComponent(playerEntity.ID, components.Collision, &components.CollisionComponent{
 		Blocks: true,
 	})
 
 	// Add inventory component to the player
-	s.world.AddComponent(playerEntity.ID, components.Inventory, components.NewInventoryComponent(20))
+	inventory := components.NewInventoryComponent(20)
+	s.world.AddComponent(playerEntity.ID, components.Inventory, inventory)
 
 	// Add equipment component to the player
 	s.world.AddComponent(playerEntity.ID, components.Equipment, components.NewEquipmentComponent())
 
 	// Add FOV component to the player - default vision range of 4 tiles
-	s.world.AddComponent(playerEntity.ID, components.FOV, components.NewFOVComponent(4))
+	s.world.AddComponent(playerEntity.ID, components.FOV, components.NewFOVComponent(sightRange))
+
+	if loadout != nil {
+		s.giveLoadoutItems(playerEntity.ID, inventory, loadout)
+	}
 
 	if s.logMessage != nil {
 		s.logMessage("Player created at " + strconv.Itoa(x) + "," + strconv.Itoa(y))
@@ -85,6 +109,81 @@ func (s *EntitySpawner) CreatePlayer(x, y int) *ecs.Entity {
 	return playerEntity
 }
 
+// giveLoadoutItems creates the loadout's items in the player's inventory and equips its gear
+func (s *EntitySpawner) giveLoadoutItems(playerID ecs.EntityID, inventory *components.InventoryComponent, loadout *data.Loadout) {
+	itemSpawner := NewItemSpawner(s.world, s.templateManager)
+
+	addItem := func(templateID string) ecs.EntityID {
+		item, err := itemSpawner.CreateItem(0, 0, templateID, true)
+		if err != nil {
+			systems.GetDebugLog().Add(fmt.Sprintf("Failed to create loadout item %s: %v", templateID, err))
+			return 0
+		}
+		if !inventory.AddItem(item.ID) {
+			systems.GetDebugLog().Add(fmt.Sprintf("No room for loadout item %s", templateID))
+			s.world.RemoveEntity(item.ID)
+			return 0
+		}
+		return item.ID
+	}
+
+	for _, templateID := range loadout.Equipment {
+		if itemID := addItem(templateID); itemID != 0 {
+			s.world.EmitEvent(systems.EquipItemRequestEvent{
+				EntityID: playerID,
+				ItemID:   itemID,
+			})
+		}
+	}
+
+	for _, entry := range loadout.Inventory {
+		count := entry.Count
+		if count <= 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			addItem(entry.TemplateID)
+		}
+	}
+}
+
+// CreateCompanion creates the mechanical hound that follows its owner from floor to
+// floor and fights the monsters that come near
+func (s *EntitySpawner) CreateCompanion(x, y int, ownerID ecs.EntityID) *ecs.Entity {
+	companionEntity := s.world.CreateEntity()
+	companionEntity.AddTag("companion")
+	s.world.TagEntity(companionEntity.ID, "companion")
+
+	// Add position component
+	s.world.AddComponent(companionEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	// A lowercase d, in brass
+	s.world.AddComponent(companionEntity.ID, components.Renderable, components.NewRenderableComponentByPos(4, 6, color.RGBA{220, 180, 90, 255}))
+	s.world.AddComponent(companionEntity.ID, components.Name, components.NewNameComponent("Brass Hound"))
+	s.world.AddComponent(companionEntity.ID, components.Stats, &components.StatsComponent{
+		Health:        30,
+		MaxHealth:     30,
+		Attack:        3,
+		Defense:       2,
+		Level:         1,
+		HealingFactor: 1,
+	})
+	s.world.AddComponent(companionEntity.ID, components.Collision, &components.CollisionComponent{
+		Blocks: true,
+	})
+	s.world.AddComponent(companionEntity.ID, components.Companion, components.NewCompanionComponent(ownerID))
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(companionEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return companionEntity
+}
+
 // CreateCamera creates a camera entity that follows the given target entity
 func (s *EntitySpawner) CreateCamera(targetEntityID uint64, x, y int) *ecs.Entity {
 	// Create camera entity
@@ -151,23 +250,62 @@ func (s *EntitySpawner) CreateEnemy(x, y int, enemyType string) (*ecs.Entity, er
 		ActionPoints:    template.ActionPoints,
 		MaxActionPoints: template.MaxActionPoints,
 		Recovery:        template.Recovery,
+		Evasion:         template.Evasion,
+		Accuracy:        template.Accuracy,
 	}
 
 	// Add any entity-specific tags from the template
 	for _, tag := range template.Tags {
 		s.world.TagEntity(enemyEntity.ID, tag)
 	}
+
+	sightRange := 8
+	if template.SightRange > 0 {
+		sightRange = template.SightRange
+	}
+
 	// Add components
 	s.world.AddComponent(enemyEntity.ID, components.Renderable, renderable)
 	s.world.AddComponent(enemyEntity.ID, components.Stats, stats)
 	s.world.AddComponent(enemyEntity.ID, components.AI, &components.AIComponent{
 		Type:       template.AIType,
-		SightRange: 8,                       // How far the zombie can see
+		TemplateID: template.ID,
+		SightRange: sightRange,              // How far it can see in full light
 		Path:       []components.PathNode{}, // Initialize empty path
 	})
 	// Add name component for display in messages
 	s.world.AddComponent(enemyEntity.ID, components.Name, components.NewNameComponent(template.Name))
 
+	// Add resistances if the template has any
+	if len(template.Resistances) > 0 {
+		resistances := make(map[string]float64, len(template.Resistances))
+		for school, multiplier := range template.Resistances {
+			resistances[school] = multiplier
+		}
+		s.world.AddComponent(enemyEntity.ID, components.Resistance, components.NewResistanceComponent(resistances))
+	}
+
+	// Add the effects the monster starts with, such as an enrage below some health
+	if len(template.Effects) > 0 {
+		effectComp := &components.EffectComponent{Effects: make([]components.GameEffect, 0, len(template.Effects))}
+		for _, templateEffect := range template.Effects {
+			effect := components.NewGameEffect(
+				components.EffectType(templateEffect.Type),
+				components.EffectOperation(templateEffect.Operation),
+				templateEffect.Value,
+				templateEffect.Duration,
+				enemyEntity.ID,
+				templateEffect.Target.Component,
+				templateEffect.Target.Property,
+			)
+			effect.School = templateEffect.School
+			effect.Condition = components.EffectCondition{HealthBelow: templateEffect.Condition.HealthBelow}
+			effect.Message = templateEffect.Message
+			effectComp.AddEffect(effect)
+		}
+		s.world.AddComponent(enemyEntity.ID, components.Effect, effectComp)
+	}
+
 	// Set collision based on template
 	s.world.AddComponent(enemyEntity.ID, components.Collision, &components.CollisionComponent{
 		Blocks: template.BlocksPath,
@@ -218,6 +356,8 @@ func (s *EntitySpawner) CreateEnemy(x, y int, enemyType string) (*ecs.Entity, er
 					effect.Target.Component,
 					effect.Target.Property,
 				)
+				effects[i].School = effect.School
+				effects[i].Stacking = components.StackingPolicy(effect.Stacking)
 			}
 
 			// Create the ability definition
@@ -230,6 +370,14 @@ func (s *EntitySpawner) CreateEnemy(x, y int, enemyType string) (*ecs.Entity, er
 				Range:       ability.Range,
 				Cost:        ability.Cost,
 				Trigger:     components.MonsterAbilityTrigger(ability.Trigger),
+				Action:      components.MonsterAbilityAction(ability.Action),
+				Chance:      ability.Chance,
+				Summons:     ability.Summons,
+				Count:       ability.Count,
+				MaxUses:     ability.MaxUses,
+				Area:        components.AbilityArea(ability.Area),
+				Radius:      ability.Radius,
+				Duration:    ability.Duration,
 				Effects:     effects,
 			}
 
@@ -345,3 +493,228 @@ func (s *EntitySpawner) CreateStairsEntity(x, y int, isUp bool) *ecs.Entity {
 
 	return stairsEntity
 }
+
+// CreateLever creates a lever at the given position that switches the target tiles
+func (s *EntitySpawner) CreateLever(x, y int, targets []components.MechanismTarget, oneShot bool) *ecs.Entity {
+	// Create the lever entity
+	leverEntity := s.world.CreateEntity()
+	leverEntity.AddTag("lever")
+	s.world.TagEntity(leverEntity.ID, "lever")
+
+	// Add position component
+	s.world.AddComponent(leverEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	// Levers are drawn leaning left while off and right while on
+	s.world.AddComponent(leverEntity.ID, components.Renderable, components.NewRenderableComponent('/', color.RGBA{230, 200, 80, 255}))
+
+	name := "Lever"
+	if oneShot {
+		name = "Rusted Lever"
+	}
+	s.world.AddComponent(leverEntity.ID, components.Name, components.NewNameComponent(name))
+	s.world.AddComponent(leverEntity.ID, components.Mechanism, components.NewMechanismComponent(targets, oneShot))
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(leverEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return leverEntity
+}
+
+// CreateCraftingStation creates a workbench the player can bump into to craft items
+func (s *EntitySpawner) CreateCraftingStation(x, y int) *ecs.Entity {
+	stationEntity := s.world.CreateEntity()
+	stationEntity.AddTag("crafting_station")
+	s.world.TagEntity(stationEntity.ID, "crafting_station")
+
+	// Add position component
+	s.world.AddComponent(stationEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	// Pi (CP437 227) looks like a bench
+	s.world.AddComponent(stationEntity.ID, components.Renderable, components.NewRenderableComponentByPos(3, 14, color.RGBA{90, 200, 255, 255}))
+	s.world.AddComponent(stationEntity.ID, components.Name, components.NewNameComponent("Workbench"))
+
+	// Stations block movement so walking into one uses it
+	s.world.AddComponent(stationEntity.ID, components.Collision, &components.CollisionComponent{
+		Blocks: true,
+	})
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(stationEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return stationEntity
+}
+
+// CreateShrine creates an altar the player can sacrifice items at
+func (s *EntitySpawner) CreateShrine(x, y int) *ecs.Entity {
+	shrineEntity := s.world.CreateEntity()
+	shrineEntity.AddTag("shrine")
+	s.world.TagEntity(shrineEntity.ID, "shrine")
+
+	// Add position component
+	s.world.AddComponent(shrineEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	// Omega (CP437 234) for the altar
+	s.world.AddComponent(shrineEntity.ID, components.Renderable, components.NewRenderableComponentByPos(10, 14, color.RGBA{255, 215, 90, 255}))
+	s.world.AddComponent(shrineEntity.ID, components.Name, components.NewNameComponent("Altar"))
+
+	// Altars block movement so walking into one approaches it
+	s.world.AddComponent(shrineEntity.ID, components.Collision, &components.CollisionComponent{
+		Blocks: true,
+	})
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(shrineEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return shrineEntity
+}
+
+// CreateCamp creates a camp the player can rest at in safety
+func (s *EntitySpawner) CreateCamp(x, y int) *ecs.Entity {
+	campEntity := s.world.CreateEntity()
+	campEntity.AddTag("camp")
+	s.world.TagEntity(campEntity.ID, "camp")
+
+	// Add position component
+	s.world.AddComponent(campEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	// Sun (CP437 15) for the banked campfire
+	s.world.AddComponent(campEntity.ID, components.Renderable, components.NewRenderableComponentByPos(15, 0, color.RGBA{255, 150, 60, 255}))
+	s.world.AddComponent(campEntity.ID, components.Name, components.NewNameComponent("Camp"))
+	s.world.AddComponent(campEntity.ID, components.Camp, components.NewCampComponent(50, 30))
+
+	// Camps block movement so walking into one settles in to rest
+	s.world.AddComponent(campEntity.ID, components.Collision, &components.CollisionComponent{
+		Blocks: true,
+	})
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(campEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return campEntity
+}
+
+// CreateGravestone creates the grave of a previous character, holding what they
+// were carrying when they died
+func (s *EntitySpawner) CreateGravestone(x, y int, grave *systems.Grave) *ecs.Entity {
+	graveEntity := s.world.CreateEntity()
+	graveEntity.AddTag("container")
+	s.world.TagEntity(graveEntity.ID, "container")
+	s.world.TagEntity(graveEntity.ID, "gravestone")
+
+	// Add position component
+	s.world.AddComponent(graveEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	// Intersection (CP437 239) looks like a headstone
+	s.world.AddComponent(graveEntity.ID, components.Renderable, components.NewRenderableComponentByPos(15, 14, color.RGBA{170, 170, 180, 255}))
+	s.world.AddComponent(graveEntity.ID, components.Name, components.NewNameComponent(
+		fmt.Sprintf("the grave of a level %d %s", grave.Level, grave.Name)))
+
+	// Whatever they were carrying is buried with them
+	itemSpawner := NewItemSpawner(s.world, s.templateManager)
+	containerComp := components.NewContainerComponent(len(grave.Items))
+	for _, templateID := range grave.Items {
+		item, err := itemSpawner.CreateItem(0, 0, templateID, true)
+		if err != nil {
+			systems.GetDebugLog().Add(fmt.Sprintf("Failed to create grave item %s: %v", templateID, err))
+			continue
+		}
+		containerComp.AddItem(item.ID)
+	}
+	s.world.AddComponent(graveEntity.ID, components.Container, containerComp)
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(graveEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return graveEntity
+}
+
+// destructibleKind describes a prop CreateDestructible can place
+type destructibleKind struct {
+	name            string
+	tileX, tileY    int
+	color           color.RGBA
+	health          int
+	loot            []string
+	lootChance      float64
+	explosionRadius int
+	explosionDamage int
+}
+
+// destructibleKinds are the props that can be broken, by the kind passed to
+// CreateDestructible
+var destructibleKinds = map[string]destructibleKind{
+	// Filled square (CP437 254)
+	"crate": {name: "Crate", tileX: 14, tileY: 15, color: color.RGBA{170, 120, 70, 255}, health: 10,
+		loot: []string{"bandage", "scrap_metal", "copper_wire", "health_potion"}, lootChance: 0.5},
+	// Phi (CP437 232) looks like a banded barrel
+	"barrel": {name: "Barrel", tileX: 8, tileY: 14, color: color.RGBA{150, 100, 60, 255}, health: 7,
+		loot: []string{"bandage", "scrap_metal"}, lootChance: 0.3},
+	"powder_keg": {name: "Powder Keg", tileX: 8, tileY: 14, color: color.RGBA{230, 60, 40, 255}, health: 4,
+		explosionRadius: 2, explosionDamage: 12},
+}
+
+// CreateDestructible creates a prop such as a crate or barrel that blocks movement
+// until it is broken
+func (s *EntitySpawner) CreateDestructible(x, y int, kind string) (*ecs.Entity, error) {
+	def, exists := destructibleKinds[kind]
+	if !exists {
+		return nil, fmt.Errorf("unknown destructible kind '%s'", kind)
+	}
+
+	propEntity := s.world.CreateEntity()
+	propEntity.AddTag("destructible")
+	s.world.TagEntity(propEntity.ID, "destructible")
+
+	// Add position component
+	s.world.AddComponent(propEntity.ID, components.Position, &components.PositionComponent{
+		X: x,
+		Y: y,
+	})
+
+	s.world.AddComponent(propEntity.ID, components.Renderable, components.NewRenderableComponentByPos(def.tileX, def.tileY, def.color))
+	s.world.AddComponent(propEntity.ID, components.Name, components.NewNameComponent(def.name))
+
+	destructible := components.NewDestructibleComponent(def.health)
+	destructible.Loot = def.loot
+	destructible.LootChance = def.lootChance
+	destructible.ExplosionRadius = def.explosionRadius
+	destructible.ExplosionDamage = def.explosionDamage
+	s.world.AddComponent(propEntity.ID, components.Destructible, destructible)
+
+	// Props block movement so walking into one attacks it
+	s.world.AddComponent(propEntity.ID, components.Collision, &components.CollisionComponent{
+		Blocks: true,
+	})
+
+	// Add map context component
+	if s.spawnMapID != 0 {
+		s.world.AddComponent(propEntity.ID, components.MapContextID, components.NewMapContextComponent(s.spawnMapID))
+	}
+
+	return propEntity, nil
+}
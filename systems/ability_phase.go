@@ -0,0 +1,190 @@
+package systems
+
+import (
+	"fmt"
+	"strings"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// startPhase fades the monster out of solid form when the way round to its target is
+// blocked or much longer than the straight line, spending its turn on it so the
+// player sees it coming. Returns true if the monster spent its turn phasing.
+func (s *AITurnProcessorSystem) startPhase(world *ecs.World, entityID ecs.EntityID, ai *components.AIComponent, pos *components.PositionComponent, path []components.PathNode, stats *components.StatsComponent) bool {
+	if IsPhased(world, entityID) {
+		return false
+	}
+	goal, hasGoal := phaseGoal(world, ai)
+	if !hasGoal {
+		return false
+	}
+	abilityComp, exists := world.GetComponent(entityID, components.MonsterAbility)
+	if !exists {
+		return false
+	}
+	distance := max(abs(goal.X-pos.X), abs(goal.Y-pos.Y))
+	if len(path) > 0 && len(path) <= distance*2 {
+		return false
+	}
+
+	abilities := abilityComp.(*components.MonsterAbilityComponent).Abilities
+	for i := range abilities {
+		ability := &abilities[i]
+		if ability.Action != components.AbilityActionPhase || ability.Trigger != components.TriggerInRange {
+			continue
+		}
+		if ability.CurrentCD > 0 || (ability.MaxUses > 0 && ability.Uses >= ability.MaxUses) || stats.ActionPoints < ability.Cost {
+			continue
+		}
+		if distance > max(ability.Range, 1) {
+			continue
+		}
+		if ability.Chance > 0 && playRNG.Float64() >= ability.Chance {
+			continue
+		}
+
+		world.AddComponent(entityID, components.Phase, components.NewPhaseComponent(ability.Duration))
+		ability.Uses++
+		ability.CurrentCD = ability.Cooldown
+		stats.ActionPoints -= ability.Cost
+
+		name := strings.ToLower(getEntityName(world, entityID))
+		if isVisibleToPlayer(world, entityID, pos) {
+			GetMessageLog().AddAlert(fmt.Sprintf("The %s uses %s and fades until you can see through it. Walls won't stop it now!", name, ability.Name))
+		} else {
+			GetMessageLog().AddAlert("A chill runs through the walls around you.")
+		}
+		GetDebugLog().Add(fmt.Sprintf("AI: %s phasing for %d turns", name, ability.Duration))
+		return true
+	}
+	return false
+}
+
+// phaseStep moves a phased monster a tile straight towards its target, through
+// walls if need be. Returns true if it moved.
+func (s *AITurnProcessorSystem) phaseStep(world *ecs.World, entityID ecs.EntityID, ai *components.AIComponent, pos *components.PositionComponent, stats *components.StatsComponent) bool {
+	if !IsPhased(world, entityID) || stats.ActionPoints < MoveCost {
+		return false
+	}
+	goal, hasGoal := phaseGoal(world, ai)
+	if !hasGoal || (goal.X == pos.X && goal.Y == pos.Y) {
+		return false
+	}
+	movement := s.getMovementSystem(world)
+	if movement == nil {
+		return false
+	}
+	next := LinePoints(pos.X, pos.Y, goal.X, goal.Y)[1]
+	if !movement.CanPhaseTo(world, getEntityMapID(world, entityID), entityID, next.X, next.Y) {
+		return false
+	}
+
+	oldX, oldY := pos.X, pos.Y
+	pos.X, pos.Y = next.X, next.Y
+	stats.ActionPoints -= MoveCost
+	world.EmitEvent(EntityMoveEvent{
+		EntityID: entityID,
+		FromX:    oldX,
+		FromY:    oldY,
+		ToX:      pos.X,
+		ToY:      pos.Y,
+	})
+	GetDebugLog().Add(fmt.Sprintf("AI: %s phased from %d,%d to %d,%d", getEntityName(world, entityID), oldX, oldY, pos.X, pos.Y))
+	return true
+}
+
+// phaseGoal returns where a monster is heading: its target while it hunts, or where
+// it last knew its target to be while it investigates
+func phaseGoal(world *ecs.World, ai *components.AIComponent) (Point, bool) {
+	switch {
+	case isHunting(ai.State):
+		playerEntities := world.GetEntitiesWithTag("player")
+		if len(playerEntities) == 0 {
+			return Point{}, false
+		}
+		return entityPoint(world, playerEntities[0].ID)
+	case ai.State == components.AIStateInvestigate:
+		return Point{ai.LastKnownTargetX, ai.LastKnownTargetY}, true
+	}
+	return Point{}, false
+}
+
+// tickPhases counts down phases and the cooldowns of the abilities that start them.
+// A monster whose phase runs out turns solid again, and is pushed out onto open
+// ground if it is still inside a wall.
+func (s *AITurnProcessorSystem) tickPhases(world *ecs.World) {
+	world.Query(components.MonsterAbility).Each(func(entity *ecs.Entity) {
+		abilityComp, _ := world.GetComponent(entity.ID, components.MonsterAbility)
+		abilities := abilityComp.(*components.MonsterAbilityComponent).Abilities
+		for i := range abilities {
+			if abilities[i].Action == components.AbilityActionPhase && abilities[i].CurrentCD > 0 {
+				abilities[i].CurrentCD--
+			}
+		}
+	})
+
+	var ended []ecs.EntityID
+	world.Query(components.Phase).Each(func(entity *ecs.Entity) {
+		phaseComp, _ := world.GetComponent(entity.ID, components.Phase)
+		phase := phaseComp.(*components.PhaseComponent)
+		phase.TurnsLeft--
+		if phase.TurnsLeft <= 0 {
+			ended = append(ended, entity.ID)
+		}
+	})
+
+	movement := s.getMovementSystem(world)
+	for _, entityID := range ended {
+		world.RemoveComponent(entityID, components.Phase)
+		if movement != nil {
+			movement.LeaveRock(world, entityID)
+		}
+		if posComp, exists := world.GetComponent(entityID, components.Position); exists && isVisibleToPlayer(world, entityID, posComp.(*components.PositionComponent)) {
+			GetMessageLog().Add(fmt.Sprintf("The %s turns solid again.", strings.ToLower(getEntityName(world, entityID))))
+		}
+	}
+}
+
+// highlightPhases marks the phased monsters the player can see, walls and all
+func (s *AITurnProcessorSystem) highlightPhases(world *ecs.World) {
+	renderSys := s.getRenderSystem(world)
+	if renderSys == nil {
+		return
+	}
+	world.Query(components.Phase, components.Position).Each(func(entity *ecs.Entity) {
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		if isVisibleToPlayer(world, entity.ID, pos) {
+			renderSys.HighlightTile(pos.X, pos.Y, renderSys.Palette().Telegraph)
+		}
+	})
+}
+
+// isVisibleToPlayer returns whether an entity stands on a tile the player can see
+func isVisibleToPlayer(world *ecs.World, entityID ecs.EntityID, pos *components.PositionComponent) bool {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return false
+	}
+	mapID := getEntityMapID(world, entityID)
+	if getEntityMapID(world, playerEntities[0].ID) != mapID {
+		return false
+	}
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return false
+	}
+	gameMap := mapComp.(*components.MapComponent)
+	return gameMap.InBounds(pos.X, pos.Y) && gameMap.Visible[pos.Y][pos.X]
+}
+
+// getMovementSystem finds the movement system phased steps are checked with
+func (s *AITurnProcessorSystem) getMovementSystem(world *ecs.World) *MovementSystem {
+	for _, system := range world.GetSystems() {
+		if movement, ok := system.(*MovementSystem); ok {
+			return movement
+		}
+	}
+	return nil
+}
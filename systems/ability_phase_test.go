@@ -0,0 +1,180 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// phaseFixture is a floor with a wall three tiles thick across it at x 5 to 7, a
+// ghost hunting on the west side and the player on the east side
+type phaseFixture struct {
+	*testWorld
+	turns     *AITurnProcessorSystem
+	movement  *MovementSystem
+	ghostID   ecs.EntityID
+	ghost     *components.PositionComponent
+	ai        *components.AIComponent
+	stats     *components.StatsComponent
+	abilities *components.MonsterAbilityComponent
+}
+
+func newPhaseFixture(t *testing.T) *phaseFixture {
+	t.Helper()
+	w := newTestWorld(t, 15, 7, 10, 3)
+	for y := 0; y < w.gameMap.Height; y++ {
+		for x := 5; x <= 7; x++ {
+			w.gameMap.Tiles[y][x] = components.TileWall
+		}
+	}
+	w.world.AddComponent(w.playerID, components.Collision, &components.CollisionComponent{Blocks: true})
+
+	ghostID := w.place(3, 3)
+	w.world.TagEntity(ghostID, "ai")
+	w.world.AddComponent(ghostID, components.Name, components.NewNameComponent("Static Ghost"))
+	w.world.AddComponent(ghostID, components.Collision, &components.CollisionComponent{Blocks: true})
+	ai := &components.AIComponent{Type: "aggressive", State: components.AIStateChase}
+	w.world.AddComponent(ghostID, components.AI, ai)
+	stats := &components.StatsComponent{Health: 10, MaxHealth: 10, ActionPoints: 4, MaxActionPoints: 4, Recovery: 2}
+	w.world.AddComponent(ghostID, components.Stats, stats)
+	abilities := components.NewMonsterAbilityComponent()
+	abilities.AddAbility(components.MonsterAbilityDef{
+		Name:     "Phase Shift",
+		Trigger:  components.TriggerInRange,
+		Action:   components.AbilityActionPhase,
+		Range:    8,
+		Duration: 5,
+		Cost:     2,
+		Cooldown: 12,
+	})
+	w.world.AddComponent(ghostID, components.MonsterAbility, abilities)
+	posComp, _ := w.world.GetComponent(ghostID, components.Position)
+
+	movement := NewMovementSystem()
+	w.world.AddSystem(movement)
+	turns := NewAITurnProcessorSystem()
+	w.world.AddSystem(turns)
+	turns.Initialize(w.world)
+
+	return &phaseFixture{
+		testWorld: w,
+		turns:     turns,
+		movement:  movement,
+		ghostID:   ghostID,
+		ghost:     posComp.(*components.PositionComponent),
+		ai:        ai,
+		stats:     stats,
+		abilities: abilities,
+	}
+}
+
+// takeTurn gives the ghost a turn with no walking path to the player, then ends the
+// player's turn
+func (f *phaseFixture) takeTurn() {
+	f.stats.ActionPoints = f.stats.MaxActionPoints
+	f.turns.processTurn(f.world, uint64(f.ghostID), f.ai, f.ghost, nil, f.stats.Recovery)
+	f.world.EmitEvent(TurnCompletedEvent{})
+}
+
+func TestPhaseStartsWhenTheWayIsBlocked(t *testing.T) {
+	f := newPhaseFixture(t)
+
+	f.takeTurn()
+	if !IsPhased(f.world, f.ghostID) {
+		t.Fatal("the ghost didn't phase with no way round the wall")
+	}
+	if f.ghost.X != 3 {
+		t.Errorf("the ghost moved to x %d on the turn it phased, want it to stay at 3", f.ghost.X)
+	}
+	if cd := f.abilities.Abilities[0].CurrentCD; cd != 11 {
+		t.Errorf("cooldown after a turn = %d, want 11", cd)
+	}
+
+	// A short walk round doesn't need a phase
+	g := newPhaseFixture(t)
+	path := []components.PathNode{{X: 4, Y: 3}, {X: 4, Y: 2}, {X: 4, Y: 1}}
+	g.turns.processTurn(g.world, uint64(g.ghostID), g.ai, g.ghost, path, g.stats.Recovery)
+	if IsPhased(g.world, g.ghostID) {
+		t.Error("the ghost phased though it could walk there in about the straight-line distance")
+	}
+}
+
+func TestPhasedGhostCrossesWall(t *testing.T) {
+	f := newPhaseFixture(t)
+	f.ghost.X = 4
+	f.takeTurn()
+
+	for turn := 1; turn <= 3; turn++ {
+		f.takeTurn()
+		if f.ghost.X != 4+turn {
+			t.Fatalf("ghost at x %d after %d phased turns, want %d", f.ghost.X, turn, 4+turn)
+		}
+	}
+	f.takeTurn()
+	if f.ghost.X != 8 || f.ghost.Y != 3 {
+		t.Errorf("ghost at (%d,%d) after four phased turns, want through the wall at (8,3)", f.ghost.X, f.ghost.Y)
+	}
+	if IsPhased(f.world, f.ghostID) {
+		t.Error("the ghost was still phased after its duration ran out")
+	}
+}
+
+func TestPhasedGhostWaitsAtThickWalls(t *testing.T) {
+	f := newPhaseFixture(t)
+	f.takeTurn()
+
+	// From two tiles back the phase has too few turns left to clear the wall
+	f.takeTurn()
+	f.takeTurn()
+	if f.ghost.X != 4 {
+		t.Errorf("ghost at x %d, want it waiting at the wall's edge rather than stepping in", f.ghost.X)
+	}
+}
+
+func TestCanPhaseTo(t *testing.T) {
+	f := newPhaseFixture(t)
+	f.ghost.X = 4
+	if f.movement.CanPhaseTo(f.world, f.mapID, f.ghostID, 5, 3) {
+		t.Error("a solid ghost can step into a wall")
+	}
+
+	// Three tiles of rock need four turns to clear after stepping in
+	phase := components.NewPhaseComponent(4)
+	f.world.AddComponent(f.ghostID, components.Phase, phase)
+	if !f.movement.CanPhaseTo(f.world, f.mapID, f.ghostID, 5, 3) {
+		t.Error("a ghost phased for 4 turns can't step into 3 tiles of rock")
+	}
+	phase.TurnsLeft = 3
+	if f.movement.CanPhaseTo(f.world, f.mapID, f.ghostID, 5, 3) {
+		t.Error("a ghost phased for 3 turns may step into rock it can't get through")
+	}
+
+	// Whatever waits on the far side blocks the way out
+	blockerID := f.place(8, 3)
+	f.world.AddComponent(blockerID, components.Collision, &components.CollisionComponent{Blocks: true})
+	phase.TurnsLeft = 4
+	if f.movement.CanPhaseTo(f.world, f.mapID, f.ghostID, 5, 3) {
+		t.Error("a ghost may step into rock whose only way out is occupied")
+	}
+	if f.movement.CanPhaseTo(f.world, f.mapID, f.ghostID, -1, 3) {
+		t.Error("a ghost may phase off the map")
+	}
+}
+
+func TestPhaseEndingInRockLeavesIt(t *testing.T) {
+	f := newPhaseFixture(t)
+	f.ghost.X = 5
+	f.world.AddComponent(f.ghostID, components.Phase, components.NewPhaseComponent(1))
+
+	f.world.EmitEvent(TurnCompletedEvent{})
+	if IsPhased(f.world, f.ghostID) {
+		t.Fatal("the phase didn't end")
+	}
+	if f.gameMap.BlocksMovement(f.ghost.X, f.ghost.Y) {
+		t.Fatalf("the ghost turned solid inside the wall at (%d,%d)", f.ghost.X, f.ghost.Y)
+	}
+	if f.ghost.X != 4 || f.ghost.Y != 3 {
+		t.Errorf("ghost left the rock at (%d,%d), want the nearest floor (4,3)", f.ghost.X, f.ghost.Y)
+	}
+}
@@ -0,0 +1,244 @@
+package systems
+
+import (
+	"fmt"
+	"strings"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// chargeFlashSeconds is how long the tiles a charged ability hit stay lit
+const chargeFlashSeconds = 0.4
+
+// abilityCharge is a charged ability a monster has started and will release on its
+// next turn
+type abilityCharge struct {
+	ability int // Index of the ability in the monster's abilities
+	mapID   ecs.EntityID
+	tiles   []Point // Tiles it will hit, fixed when the charge starts
+}
+
+// startCharge begins the first charged ability the monster can use on its target,
+// marking the tiles it will hit so the player has a turn to get out of them. Returns
+// true if the monster spent its turn charging.
+func (s *AITurnProcessorSystem) startCharge(world *ecs.World, entityID ecs.EntityID, ai *components.AIComponent, pos *components.PositionComponent, stats *components.StatsComponent) bool {
+	if !isHunting(ai.State) {
+		return false
+	}
+	abilityComp, exists := world.GetComponent(entityID, components.MonsterAbility)
+	if !exists {
+		return false
+	}
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return false
+	}
+	mapID := getEntityMapID(world, entityID)
+	target, found := entityPoint(world, playerEntities[0].ID)
+	if !found || getEntityMapID(world, playerEntities[0].ID) != mapID {
+		return false
+	}
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return false
+	}
+	gameMap := mapComp.(*components.MapComponent)
+	distance := max(abs(target.X-pos.X), abs(target.Y-pos.Y))
+
+	abilities := abilityComp.(*components.MonsterAbilityComponent).Abilities
+	for i := range abilities {
+		ability := &abilities[i]
+		if ability.Action != components.AbilityActionCharge || ability.Trigger != components.TriggerInRange {
+			continue
+		}
+		if ability.CurrentCD > 0 || (ability.MaxUses > 0 && ability.Uses >= ability.MaxUses) || stats.ActionPoints < ability.Cost {
+			continue
+		}
+		if distance > max(ability.Range, 1) || !HasLineOfSight(gameMap, pos.X, pos.Y, target.X, target.Y) {
+			continue
+		}
+		if ability.Chance > 0 && playRNG.Float64() >= ability.Chance {
+			continue
+		}
+
+		tiles := chargeArea(gameMap, ability, Point{pos.X, pos.Y}, target)
+		if len(tiles) == 0 {
+			continue
+		}
+		s.charges[entityID] = &abilityCharge{ability: i, mapID: mapID, tiles: tiles}
+		ability.Uses++
+		ability.CurrentCD = ability.Cooldown
+		stats.ActionPoints -= ability.Cost
+
+		name := strings.ToLower(getEntityName(world, entityID))
+		if gameMap.Visible[pos.Y][pos.X] {
+			GetMessageLog().AddAlert(fmt.Sprintf("The %s gathers itself for %s! Get out of the marked area.", name, ability.Name))
+		} else {
+			GetMessageLog().AddAlert("Something out of sight gathers itself. Get out of the marked area!")
+		}
+		GetDebugLog().Add(fmt.Sprintf("AI: %s charging %s over %d tiles", name, ability.Name, len(tiles)))
+		return true
+	}
+	return false
+}
+
+// releaseCharge fires the ability the monster charged last turn at the tiles it
+// marked, hitting whoever is still standing in them. Returns true if the monster had
+// a charge, which takes its turn whether or not anything was hit.
+func (s *AITurnProcessorSystem) releaseCharge(world *ecs.World, entityID ecs.EntityID, stats *components.StatsComponent) bool {
+	charge, charging := s.charges[entityID]
+	if !charging {
+		return false
+	}
+	delete(s.charges, entityID)
+	stats.ActionPoints = max(0, stats.ActionPoints-AttackCost)
+
+	abilityComp, exists := world.GetComponent(entityID, components.MonsterAbility)
+	if !exists || getEntityMapID(world, entityID) != charge.mapID {
+		return true
+	}
+	abilities := abilityComp.(*components.MonsterAbilityComponent).Abilities
+	if charge.ability >= len(abilities) {
+		return true
+	}
+	ability := abilities[charge.ability]
+
+	inArea := make(map[Point]bool, len(charge.tiles))
+	for _, tile := range charge.tiles {
+		inArea[tile] = true
+	}
+	var victims []ecs.EntityID
+	for _, entity := range world.Query(components.Position, components.Stats).Entities() {
+		if entity.ID == entityID || getEntityMapID(world, entity.ID) != charge.mapID {
+			continue
+		}
+		if pos, found := entityPoint(world, entity.ID); found && inArea[pos] {
+			victims = append(victims, entity.ID)
+		}
+	}
+
+	GetMessageLog().AddAlert(fmt.Sprintf("The %s unleashes %s!", strings.ToLower(getEntityName(world, entityID)), ability.Name))
+	if renderSys := s.getRenderSystem(world); renderSys != nil {
+		renderSys.FlashTiles(charge.tiles, renderSys.Palette().HealthLow, chargeFlashSeconds)
+	}
+
+	// Whoever is caught takes it like a blast, so deaths and kill credit are handled
+	// the same way
+	var bombSystem *BombSystem
+	for _, system := range world.GetSystems() {
+		if bombSys, ok := system.(*BombSystem); ok {
+			bombSystem = bombSys
+			break
+		}
+	}
+	if bombSystem == nil {
+		return true
+	}
+	effectsSystem := bombSystem.getEffectsSystem(world)
+	for _, victimID := range victims {
+		if world.GetEntity(victimID) != nil {
+			bombSystem.applyBlast(world, effectsSystem, victimID, entityID, ability.Effects)
+		}
+	}
+	return true
+}
+
+// InterruptCharge cancels a charge the monster has under way, for when it is knocked
+// out of it before it can release
+func (s *AITurnProcessorSystem) InterruptCharge(world *ecs.World, entityID ecs.EntityID) {
+	if _, charging := s.charges[entityID]; !charging {
+		return
+	}
+	delete(s.charges, entityID)
+	if world.GetEntity(entityID) != nil {
+		GetMessageLog().Add(fmt.Sprintf("The %s's attack fizzles out.", strings.ToLower(getEntityName(world, entityID))))
+	}
+}
+
+// tickCharges counts down the cooldowns of charged abilities
+func (s *AITurnProcessorSystem) tickCharges(world *ecs.World) {
+	world.Query(components.MonsterAbility).Each(func(entity *ecs.Entity) {
+		abilityComp, _ := world.GetComponent(entity.ID, components.MonsterAbility)
+		abilities := abilityComp.(*components.MonsterAbilityComponent).Abilities
+		for i := range abilities {
+			if abilities[i].Action == components.AbilityActionCharge && abilities[i].CurrentCD > 0 {
+				abilities[i].CurrentCD--
+			}
+		}
+	})
+}
+
+// highlightCharges tints the tiles charging monsters on the active map are about to
+// hit, where the player can see them
+func (s *AITurnProcessorSystem) highlightCharges(world *ecs.World) {
+	if len(s.charges) == 0 {
+		return
+	}
+	renderSys := s.getRenderSystem(world)
+	if renderSys == nil {
+		return
+	}
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	activeMapID := getEntityMapID(world, playerEntities[0].ID)
+	for _, charge := range s.charges {
+		if charge.mapID != activeMapID {
+			continue
+		}
+		mapComp, exists := world.GetComponent(charge.mapID, components.MapComponentID)
+		if !exists {
+			continue
+		}
+		gameMap := mapComp.(*components.MapComponent)
+		for _, tile := range charge.tiles {
+			if gameMap.Visible[tile.Y][tile.X] {
+				renderSys.HighlightTile(tile.X, tile.Y, renderSys.Palette().Telegraph)
+			}
+		}
+	}
+}
+
+// chargeArea returns the tiles a charged ability will hit: a burst around the monster
+// or a line from it through its target, stopped by walls either way
+func chargeArea(gameMap *components.MapComponent, ability *components.MonsterAbilityDef, from, target Point) []Point {
+	if ability.Area != components.AreaLine {
+		var tiles []Point
+		for _, tile := range TilesInRadius(gameMap, from.X, from.Y, max(ability.Radius, 1)) {
+			if tile != from && !gameMap.BlocksMovement(tile.X, tile.Y) {
+				tiles = append(tiles, tile)
+			}
+		}
+		return tiles
+	}
+
+	// Carry the line on past the target to the end of the range
+	dx, dy := target.X-from.X, target.Y-from.Y
+	steps := max(abs(dx), abs(dy))
+	if steps == 0 {
+		return nil
+	}
+	reach := max(ability.Range, 1)
+	end := Point{from.X + dx*reach/steps, from.Y + dy*reach/steps}
+
+	var tiles []Point
+	for _, tile := range LinePoints(from.X, from.Y, end.X, end.Y)[1:] {
+		if !gameMap.InBounds(tile.X, tile.Y) || gameMap.BlocksMovement(tile.X, tile.Y) {
+			break
+		}
+		tiles = append(tiles, tile)
+	}
+	return tiles
+}
+
+// getRenderSystem finds the render system the telegraphs are drawn with
+func (s *AITurnProcessorSystem) getRenderSystem(world *ecs.World) *RenderSystem {
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok {
+			return renderSys
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package systems
+
+import (
+	"image/color"
+	"math"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// aggroZone is how a monster would react to the player standing on a tile this turn
+type aggroZone int
+
+const (
+	aggroNone    aggroZone = iota
+	aggroSight             // In sight, but too far or too dark for the player to be noticed
+	aggroChance            // Noticed some of the time, at the edge of the detection range
+	aggroCertain           // Always noticed
+)
+
+// aggroColors tint the tiles of each zone, translucent so the map shows through
+var aggroColors = map[aggroZone]color.Color{
+	aggroSight:   color.NRGBA{200, 200, 120, 50},
+	aggroChance:  color.NRGBA{230, 150, 30, 100},
+	aggroCertain: color.NRGBA{210, 40, 40, 110},
+}
+
+// aggroZones works out, for every tile the player could stand on around a monster,
+// whether the monster would notice them there. It uses the same sight range, line of
+// sight and stealth and light aware detection range as the AI, for the monster's
+// current state and the player's current stealth and light, so it changes as they do.
+func aggroZones(world *ecs.World, monsterID, playerID ecs.EntityID) map[Point]aggroZone {
+	aiComp, hasAI := world.GetComponent(monsterID, components.AI)
+	posComp, hasPos := world.GetComponent(monsterID, components.Position)
+	mapComp, hasMap := world.GetComponent(getEntityMapID(world, monsterID), components.MapComponentID)
+	if !hasAI || !hasPos || !hasMap {
+		return nil
+	}
+	ai := aiComp.(*components.AIComponent)
+	pos := posComp.(*components.PositionComponent)
+	mapData := mapComp.(*components.MapComponent)
+
+	detectionRange := DetectionRange(world, ai, playerID)
+	zones := make(map[Point]aggroZone)
+	for y := pos.Y - ai.SightRange; y <= pos.Y+ai.SightRange; y++ {
+		for x := pos.X - ai.SightRange; x <= pos.X+ai.SightRange; x++ {
+			// Only ground the player has seen, so the overlay gives no layout away
+			if mapData.BlocksMovement(x, y) || !mapData.Explored[y][x] || (x == pos.X && y == pos.Y) {
+				continue
+			}
+			distance := math.Hypot(float64(x-pos.X), float64(y-pos.Y))
+			if int(distance) > ai.SightRange || !HasLineOfSight(mapData, pos.X, pos.Y, x, y) {
+				continue
+			}
+			// rollDetection's chance runs from certain to none across detectionSpread
+			switch chance := (detectionRange-distance)/detectionSpread + 0.5; {
+			case chance >= 1:
+				zones[Point{x, y}] = aggroCertain
+			case chance > 0:
+				zones[Point{x, y}] = aggroChance
+			default:
+				zones[Point{x, y}] = aggroSight
+			}
+		}
+	}
+	return zones
+}
+
+// SetShowAggro sets whether examining a monster shows its aggro radius
+func (s *TargetingSystem) SetShowAggro(show bool) {
+	s.showAggro = show
+}
+
+// highlightAggro tints the examined monster's aggro zones through the highlight API.
+// Highlights only last a tick, so the zones go away as soon as examining stops.
+func (s *TargetingSystem) highlightAggro(world *ecs.World, renderSys *RenderSystem, targetID ecs.EntityID) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if !s.showAggro || len(playerEntities) == 0 {
+		return
+	}
+	for tile, zone := range aggroZones(world, targetID, playerEntities[0].ID) {
+		renderSys.HighlightTile(tile.X, tile.Y, aggroColors[zone])
+	}
+}
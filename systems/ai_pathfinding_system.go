@@ -4,7 +4,6 @@ import (
 	"container/heap"
 	"fmt"
 	"math"
-	"math/rand"
 	"strconv"
 
 	"ebiten-rogue/components"
@@ -27,13 +26,15 @@ func (e AIPathEvent) Type() ecs.EventType {
 
 // AIPathfindingSystem handles AI vision and path calculation
 type AIPathfindingSystem struct {
-	turnProcessed bool // Flag to track if AI paths have been processed this game turn
+	turnProcessed bool            // Flag to track if AI paths have been processed this game turn
+	stateMachine  *AIStateMachine // Decides what each AI entity does with its turn
 }
 
 // NewAIPathfindingSystem creates a new AI pathfinding system
 func NewAIPathfindingSystem() *AIPathfindingSystem {
 	return &AIPathfindingSystem{
 		turnProcessed: false,
+		stateMachine:  NewAIStateMachine(),
 	}
 }
 
@@ -48,6 +49,65 @@ func (s *AIPathfindingSystem) Initialize(world *ecs.World) {
 	world.GetEventManager().Subscribe(EventRest, func(event ecs.Event) {
 		s.HandleEvent(world, event)
 	})
+
+	// Monsters that hear a noise go to see what made it
+	world.GetEventManager().Subscribe(EventNoise, func(event ecs.Event) {
+		s.hearNoise(world, event.(NoiseEvent))
+	})
+
+	// Paths planned over terrain that has since changed are dropped
+	world.GetEventManager().Subscribe(EventMapMutated, func(event ecs.Event) {
+		s.forgetPaths(world, event.(MapMutatedEvent).MapID)
+	})
+}
+
+// forgetPaths drops the stored paths of the monsters on a map whose terrain changed,
+// so none of them is followed over a tile that is no longer what it was. Paths are
+// planned afresh from the map each turn.
+func (s *AIPathfindingSystem) forgetPaths(world *ecs.World, mapID ecs.EntityID) {
+	for _, entity := range world.GetEntitiesWithTag("ai") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		if aiComp, exists := world.GetComponent(entity.ID, components.AI); exists {
+			aiComp.(*components.AIComponent).Path = nil
+		}
+	}
+}
+
+// hearNoise sends the monsters in earshot of a noise that aren't already hunting or
+// fleeing to investigate where it came from
+func (s *AIPathfindingSystem) hearNoise(world *ecs.World, noise NoiseEvent) {
+	for _, entity := range world.GetEntitiesWithTag("ai") {
+		if getEntityMapID(world, entity.ID) != noise.MapID {
+			continue
+		}
+		posComp, hasPos := world.GetComponent(entity.ID, components.Position)
+		aiComp, hasAI := world.GetComponent(entity.ID, components.AI)
+		if !hasPos || !hasAI {
+			continue
+		}
+		pos := posComp.(*components.PositionComponent)
+		ai := aiComp.(*components.AIComponent)
+
+		dx, dy := pos.X-noise.X, pos.Y-noise.Y
+		if dx*dx+dy*dy > noise.Radius*noise.Radius {
+			continue
+		}
+
+		switch ai.State {
+		case "":
+			// Hasn't had a turn yet, so where it stands is home
+			ai.HomeX, ai.HomeY = pos.X, pos.Y
+		case components.AIStateIdle, components.AIStateReturn, components.AIStateInvestigate:
+		default:
+			continue
+		}
+		ai.State = components.AIStateInvestigate
+		ai.StateTurns = 0
+		ai.LastKnownTargetX, ai.LastKnownTargetY = noise.X, noise.Y
+		GetDebugLog().Add(fmt.Sprintf("%s heard a noise at (%d,%d)", getEntityName(world, entity.ID), noise.X, noise.Y))
+	}
 }
 
 // HandleEvent processes events that the AI system is interested in
@@ -120,8 +180,9 @@ func (s *AIPathfindingSystem) Update(world *ecs.World, dt float64) {
 	}
 	gameMap := mapComp.(*components.MapComponent)
 
-	// Process all entities with AI components
-	aiEntities := world.GetEntitiesWithTag("ai")
+	// Process all entities with AI components, in a fixed order since their turns
+	// draw from the play RNG
+	aiEntities := byID(world.GetEntitiesWithTag("ai"))
 	for _, entity := range aiEntities {
 		// Skip entities that aren't on the active map
 		if world.HasComponent(entity.ID, components.MapContextID) {
@@ -147,86 +208,87 @@ func (s *AIPathfindingSystem) Update(world *ecs.World, dt float64) {
 		}
 		pos := posComp.(*components.PositionComponent)
 
-		// Process AI based on type
-		switch ai.Type {
-		case "slow_chase", "slow_wander", "aggressive":
-			s.processPathfinding(world, entity.ID, ai, pos, playerPos, gameMap)
-			// Add other AI types here as needed
+		// Unknown AI types are left alone
+		behavior, known := GetAIBehavior(ai.Type)
+		if !known {
+			continue
 		}
+		s.processPathfinding(world, entity.ID, ai, behavior, pos, playerID, playerPos, gameMap)
 	}
 
 	// Mark turn as processed
 	s.turnProcessed = true
 }
 
-// processPathfinding handles pathfinding for AI entities
-func (s *AIPathfindingSystem) processPathfinding(world *ecs.World, entityID ecs.EntityID, ai *components.AIComponent, pos *components.PositionComponent, playerPos *components.PositionComponent, gameMap *components.MapComponent) {
-	// Check if player is in sight
-	playerVisible := s.canSee(pos.X, pos.Y, playerPos.X, playerPos.Y, ai.SightRange, gameMap)
-	// GetMessageLog().Add(fmt.Sprintf("DEBUG: AI at %d,%d checking for player at %d,%d (visible: %v)", pos.X, pos.Y, playerPos.X, playerPos.Y, playerVisible))
-
-	var targetX, targetY int
-	var path []components.PathNode
-
-	if playerVisible {
-		// Player is visible, update last known position
-		ai.LastKnownTargetX = playerPos.X
-		ai.LastKnownTargetY = playerPos.Y
-		targetX, targetY = playerPos.X, playerPos.Y
-		// GetMessageLog().Add(fmt.Sprintf("DEBUG: Updated target pos to %d,%d", playerPos.X, playerPos.Y))
-
-		// Calculate path to player
-		path = s.findPath(pos.X, pos.Y, targetX, targetY, gameMap)
-	} else if ai.Type == "slow_wander" {
-		// For slow_wander AI, generate a random direction when player not visible
-		directions := []struct{ dx, dy int }{
-			{1, 0},  // Right
-			{-1, 0}, // Left
-			{0, 1},  // Down
-			{0, -1}, // Up
-		}
-
-		// Try to find a valid random direction
-		validMoves := []components.PathNode{}
-		for _, dir := range directions {
-			newX, newY := pos.X+dir.dx, pos.Y+dir.dy
-			if s.isValidMove(world, newX, newY, gameMap) {
-				validMoves = append(validMoves, components.PathNode{X: newX, Y: newY})
-			}
-		}
+// processPathfinding runs an entity's state machine and hands the resulting path to the turn processor
+func (s *AIPathfindingSystem) processPathfinding(world *ecs.World, entityID ecs.EntityID, ai *components.AIComponent, behavior AIBehavior, pos *components.PositionComponent, playerID ecs.EntityID, playerPos *components.PositionComponent, gameMap *components.MapComponent) {
+	wasHunting := ai.State == components.AIStateChase || ai.State == components.AIStateAttack
+	before := ai.State
+	ctx := &AIContext{
+		World:         world,
+		EntityID:      entityID,
+		AI:            ai,
+		Pos:           pos,
+		TargetPos:     playerPos,
+		Map:           gameMap,
+		Behavior:      behavior,
+		TargetVisible: s.canDetect(world, ai, pos, playerID, playerPos, gameMap),
+		Routed:        s.isRouted(world, entityID),
+		FindPath: func(fromX, fromY, toX, toY int) []components.PathNode {
+			return s.findPath(fromX, fromY, toX, toY, gameMap)
+		},
+		IsValidMove: func(x, y int) bool {
+			return s.isValidMove(world, x, y, gameMap)
+		},
+	}
+	s.stateMachine.Tick(ctx)
+	if ai.State != before {
+		world.EmitEvent(AIStateChangedEvent{EntityID: entityID, From: before, To: ai.State})
+	}
 
-		// If there are valid moves, choose one randomly
-		if len(validMoves) > 0 {
-			randomMove := validMoves[rand.Intn(len(validMoves))]
-			path = []components.PathNode{randomMove}
-			targetX, targetY = randomMove.X, randomMove.Y
-			GetMessageLog().Add(fmt.Sprintf("DEBUG: AI wandering to random direction: %d,%d", targetX, targetY))
-		} else {
-			// No valid moves, just stay in place
-			path = []components.PathNode{}
-		}
-	} else if ai.LastKnownTargetX != 0 || ai.LastKnownTargetY != 0 {
-		// Use last known player position (for slow_chase and default behavior)
-		targetX, targetY = ai.LastKnownTargetX, ai.LastKnownTargetY
-		path = s.findPath(pos.X, pos.Y, targetX, targetY, gameMap)
-	} else {
-		// No target
-		return
+	// Let a sneaking player know when they have been spotted
+	isHunting := ai.State == components.AIStateChase || ai.State == components.AIStateAttack
+	if isHunting && !wasHunting && IsSneaking(world, playerID) && gameMap.Visible[pos.Y][pos.X] {
+		GetMessageLog().AddAlert(fmt.Sprintf("%s notices you!", getEntityName(world, entityID)))
 	}
 
 	// Store path in AI component for reference
-	ai.Path = path
+	ai.Path = ctx.Path
+
+	targetX, targetY := ai.LastKnownTargetX, ai.LastKnownTargetY
+	if len(ctx.Path) > 0 {
+		last := ctx.Path[len(ctx.Path)-1]
+		targetX, targetY = last.X, last.Y
+	}
 
 	// Emit path event for the turn processor to handle
 	world.EmitEvent(AIPathEvent{
 		EntityID: entityID,
-		Path:     path,
+		Path:     ctx.Path,
 		TargetX:  targetX,
 		TargetY:  targetY,
-		Visible:  playerVisible,
+		Visible:  ctx.TargetVisible,
 	})
+}
 
-	// GetMessageLog().Add(fmt.Sprintf("DEBUG: AI path calculated, length: %d", len(path)))
+// canDetect checks whether a monster spots the player this turn. The player has to be in
+// sight, then the light and their stealth decide whether they are noticed.
+func (s *AIPathfindingSystem) canDetect(world *ecs.World, ai *components.AIComponent, pos *components.PositionComponent, playerID ecs.EntityID, playerPos *components.PositionComponent, gameMap *components.MapComponent) bool {
+	if !s.canSee(pos.X, pos.Y, playerPos.X, playerPos.Y, ai.SightRange, gameMap) {
+		return false
+	}
+	distance := math.Hypot(float64(playerPos.X-pos.X), float64(playerPos.Y-pos.Y))
+	return rollDetection(DetectionRange(world, ai, playerID), distance)
+}
+
+// isRouted checks with the morale system whether the entity's pack has broken
+func (s *AIPathfindingSystem) isRouted(world *ecs.World, entityID ecs.EntityID) bool {
+	for _, system := range world.GetSystems() {
+		if moraleSystem, ok := system.(*MoraleSystem); ok {
+			return moraleSystem.CheckMorale(world, entityID)
+		}
+	}
+	return false
 }
 
 // canSee checks if there's a clear line of sight between two points
@@ -244,8 +306,8 @@ func (s *AIPathfindingSystem) canSee(x1, y1, x2, y2, sightRange int, gameMap *co
 		if point.X == x1 && point.Y == y1 {
 			continue
 		}
-		// If we hit a wall, line of sight is blocked
-		if gameMap.IsWall(point.X, point.Y) {
+		// If we hit something opaque, line of sight is blocked
+		if gameMap.BlocksSight(point.X, point.Y) {
 			return false
 		}
 	}
@@ -291,13 +353,13 @@ func (s *AIPathfindingSystem) getLinePoints(x1, y1, x2, y2 int) []components.Pat
 
 // isValidMove checks if a position is a valid movement destination
 func (s *AIPathfindingSystem) isValidMove(world *ecs.World, x, y int, gameMap *components.MapComponent) bool {
-	// Check for walls
-	if gameMap.IsWall(x, y) {
+	// Check for walls and other impassable tiles
+	if gameMap.BlocksMovement(x, y) {
 		return false
 	}
 
 	// Check for entity collision
-	for _, entity := range world.GetAllEntities() {
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
 		posComp, hasPos := world.GetComponent(entity.ID, components.Position)
 		if !hasPos {
 			continue
@@ -363,15 +425,13 @@ func (s *AIPathfindingSystem) findPath(startX, startY, targetX, targetY int, gam
 		}
 
 		for _, neighbor := range neighbors {
-			// Skip if out of bounds or wall
-			if neighbor.X < 0 || neighbor.X >= gameMap.Width ||
-				neighbor.Y < 0 || neighbor.Y >= gameMap.Height ||
-				gameMap.IsWall(neighbor.X, neighbor.Y) {
+			// Skip if out of bounds or impassable
+			if gameMap.BlocksMovement(neighbor.X, neighbor.Y) {
 				continue
 			}
 
-			// Calculate score
-			tentativeGScore := gScore[current] + 1 // Cost is always 1 for adjacent cells
+			// Calculate score, slow and dangerous tiles costing more to step onto
+			tentativeGScore := gScore[current] + gameMap.MoveCost(neighbor.X, neighbor.Y)
 
 			_, neighborExists := gScore[neighbor]
 			if !neighborExists {
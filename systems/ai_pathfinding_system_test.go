@@ -0,0 +1,62 @@
+package systems
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+// wanderPositions runs a crowd of wandering monsters for some turns off a seeded play
+// RNG and returns where each of them ends up, in creation order
+func wanderPositions(t *testing.T, seed int64, turns int) []Point {
+	t.Helper()
+
+	// The player is far out of every monster's sight
+	w := newTestWorld(t, 12, 12, 11, 11)
+	world := w.world
+
+	var monsters []*components.PositionComponent
+	for i := 0; i < 10; i++ {
+		monsterID := w.place(1+i%5, 1+i/5)
+		world.TagEntity(monsterID, "ai")
+		world.AddComponent(monsterID, components.Collision, &components.CollisionComponent{Blocks: true})
+		world.AddComponent(monsterID, components.AI, &components.AIComponent{Type: "cowardly", SightRange: 1})
+		world.AddComponent(monsterID, components.Stats, &components.StatsComponent{Health: 5, MaxHealth: 5, ActionPoints: 2, MaxActionPoints: 2, Recovery: 2})
+		posComp, _ := world.GetComponent(monsterID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		monsters = append(monsters, pos)
+	}
+
+	w.activate("dungeon", 1)
+	pathfinding := NewAIPathfindingSystem()
+	world.AddSystem(pathfinding)
+	pathfinding.Initialize(world)
+	turnProcessor := NewAITurnProcessorSystem()
+	world.AddSystem(turnProcessor)
+	turnProcessor.Initialize(world)
+
+	SetPlayRNG(rand.New(rand.NewSource(seed)))
+	for turn := 0; turn < turns; turn++ {
+		world.EmitEvent(RestEvent{EntityID: w.playerID})
+		pathfinding.Update(world, 0)
+	}
+
+	positions := make([]Point, len(monsters))
+	for i, pos := range monsters {
+		positions[i] = Point{pos.X, pos.Y}
+	}
+	return positions
+}
+
+func TestMonsterTurnsFollowTheSeed(t *testing.T) {
+	defer SetPlayRNG(playRNG)
+
+	first := wanderPositions(t, 42, 15)
+	for run := 1; run < 8; run++ {
+		if again := wanderPositions(t, 42, 15); !slices.Equal(again, first) {
+			t.Fatalf("run %d with the same seed ended with monsters at %v, want %v", run, again, first)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package systems
+
+import (
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// AIBehavior tunes how the shared AI states play out for one AI type. New behaviors are
+// made by combining these settings rather than adding cases to the AI systems.
+type AIBehavior struct {
+	Wander      bool    // Idle by wandering randomly instead of standing still
+	SkipChance  int     // 1 in SkipChance moves is skipped, 0 to never skip
+	LeashRange  int     // Stop chasing beyond this distance from home, 0 for no limit
+	FleeBelow   float64 // Flee once health drops below this fraction, 0 to never flee
+	GiveUpTurns int     // Turns spent investigating before heading home
+}
+
+// aiBehaviors maps the AI type names used in monster templates to their behavior
+var aiBehaviors = map[string]AIBehavior{
+	"aggressive":  {GiveUpTurns: 10},
+	"slow_chase":  {SkipChance: 6, GiveUpTurns: 6},
+	"slow_wander": {Wander: true, SkipChance: 6, GiveUpTurns: 4},
+	"territorial": {LeashRange: 6, GiveUpTurns: 3},
+	"cowardly":    {Wander: true, FleeBelow: 0.3, GiveUpTurns: 4},
+}
+
+// GetAIBehavior returns the behavior for an AI type, or false if the type is unknown
+func GetAIBehavior(aiType string) (AIBehavior, bool) {
+	behavior, ok := aiBehaviors[aiType]
+	return behavior, ok
+}
+
+// AIContext is what a state sees of the world during one tick
+type AIContext struct {
+	World         *ecs.World
+	EntityID      ecs.EntityID
+	AI            *components.AIComponent
+	Pos           *components.PositionComponent
+	TargetPos     *components.PositionComponent
+	Map           *components.MapComponent
+	Behavior      AIBehavior
+	TargetVisible bool
+	Routed        bool // The entity's pack has broken, so it flees whatever its health
+
+	// Pathing helpers, normally provided by the AIPathfindingSystem
+	FindPath    func(fromX, fromY, toX, toY int) []components.PathNode
+	IsValidMove func(x, y int) bool
+
+	Path []components.PathNode // Steps the state wants to take, set by Tick
+}
+
+// AIStateHandler is one state of the AI state machine. Tick decides what the entity
+// does this turn by setting ctx.Path, and returns the state to be in next turn.
+type AIStateHandler interface {
+	Tick(ctx *AIContext) components.AIState
+}
+
+// AIStateMachine runs an entity's current state and applies its transitions
+type AIStateMachine struct {
+	states map[components.AIState]AIStateHandler
+}
+
+// maxTransitionsPerTick stops states that hand over to each other from looping forever
+const maxTransitionsPerTick = 3
+
+// alertTurns is how many turns an entity shows it has spotted its target
+const alertTurns = 3
+
+// NewAIStateMachine creates a state machine with the standard states
+func NewAIStateMachine() *AIStateMachine {
+	return &AIStateMachine{
+		states: map[components.AIState]AIStateHandler{
+			components.AIStateIdle:        idleState{},
+			components.AIStateInvestigate: investigateState{},
+			components.AIStateChase:       chaseState{},
+			components.AIStateAttack:      attackState{},
+			components.AIStateFlee:        fleeState{},
+			components.AIStateReturn:      returnState{},
+		},
+	}
+}
+
+// SetState registers or replaces the handler for a state
+func (m *AIStateMachine) SetState(state components.AIState, handler AIStateHandler) {
+	m.states[state] = handler
+}
+
+// Tick runs the entity's current state. When the state hands over to another, the new
+// state runs straight away so the entity doesn't lose a turn switching.
+func (m *AIStateMachine) Tick(ctx *AIContext) {
+	if ctx.AI.State == "" {
+		// First tick: wherever the entity starts is home
+		ctx.AI.HomeX, ctx.AI.HomeY = ctx.Pos.X, ctx.Pos.Y
+		ctx.AI.State = components.AIStateIdle
+	}
+	defer noteAlert(ctx.AI, ctx.AI.State)
+
+	for i := 0; i < maxTransitionsPerTick; i++ {
+		handler, ok := m.states[ctx.AI.State]
+		if !ok {
+			ctx.AI.State = components.AIStateIdle
+			continue
+		}
+
+		ctx.Path = nil
+		next := handler.Tick(ctx)
+		if next == ctx.AI.State {
+			ctx.AI.StateTurns++
+			return
+		}
+		ctx.AI.State = next
+		ctx.AI.StateTurns = 0
+	}
+}
+
+// noteAlert starts the alert shown over an entity that has just gone from minding its
+// own business to going after its target, and counts down one already showing
+func noteAlert(ai *components.AIComponent, before components.AIState) {
+	if isHunting(ai.State) && !isHunting(before) && before != components.AIStateFlee {
+		ai.AlertTurns = alertTurns
+	} else if ai.AlertTurns > 0 {
+		ai.AlertTurns--
+	}
+}
+
+// isHunting returns true for the states an entity is in while going after its target
+func isHunting(state components.AIState) bool {
+	return state == components.AIStateChase || state == components.AIStateAttack
+}
+
+// shouldFlee returns true if the entity's pack has routed or it is hurt badly enough to run
+func (ctx *AIContext) shouldFlee() bool {
+	if ctx.Routed {
+		return true
+	}
+	if ctx.Behavior.FleeBelow <= 0 {
+		return false
+	}
+	statsComp, exists := ctx.World.GetComponent(ctx.EntityID, components.Stats)
+	if !exists {
+		return false
+	}
+	stats := statsComp.(*components.StatsComponent)
+	return stats.MaxHealth > 0 && float64(stats.Health) < float64(stats.MaxHealth)*ctx.Behavior.FleeBelow
+}
+
+// adjacentToTarget returns true if the target is in one of the eight surrounding tiles
+func (ctx *AIContext) adjacentToTarget() bool {
+	dx, dy := abs(ctx.TargetPos.X-ctx.Pos.X), abs(ctx.TargetPos.Y-ctx.Pos.Y)
+	return dx <= 1 && dy <= 1 && dx+dy > 0
+}
+
+// beyondLeash returns true if a tile is further from home than the entity will chase
+func (ctx *AIContext) beyondLeash(x, y int) bool {
+	if ctx.Behavior.LeashRange <= 0 {
+		return false
+	}
+	return max(abs(x-ctx.AI.HomeX), abs(y-ctx.AI.HomeY)) > ctx.Behavior.LeashRange
+}
+
+// canEngage returns true if the entity sees its target and is willing to go after it
+func (ctx *AIContext) canEngage() bool {
+	return ctx.TargetVisible && !ctx.beyondLeash(ctx.TargetPos.X, ctx.TargetPos.Y)
+}
+
+// idleState waits at home, or wanders for behaviors that do, until a target shows up
+type idleState struct{}
+
+func (idleState) Tick(ctx *AIContext) components.AIState {
+	if ctx.canEngage() {
+		return components.AIStateChase
+	}
+	if !ctx.Behavior.Wander {
+		return components.AIStateIdle
+	}
+
+	var moves []components.PathNode
+	for _, dir := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		x, y := ctx.Pos.X+dir.X, ctx.Pos.Y+dir.Y
+		if ctx.IsValidMove(x, y) {
+			moves = append(moves, components.PathNode{X: x, Y: y})
+		}
+	}
+	if len(moves) > 0 {
+		ctx.Path = []components.PathNode{moves[playRNG.Intn(len(moves))]}
+	}
+	return components.AIStateIdle
+}
+
+// chaseState paths towards the visible target, remembering where it was seen
+type chaseState struct{}
+
+func (chaseState) Tick(ctx *AIContext) components.AIState {
+	if ctx.shouldFlee() {
+		return components.AIStateFlee
+	}
+	if !ctx.TargetVisible {
+		return components.AIStateInvestigate
+	}
+	if !ctx.canEngage() {
+		return components.AIStateReturn
+	}
+
+	ctx.AI.LastKnownTargetX, ctx.AI.LastKnownTargetY = ctx.TargetPos.X, ctx.TargetPos.Y
+	if ctx.adjacentToTarget() {
+		return components.AIStateAttack
+	}
+	ctx.Path = ctx.FindPath(ctx.Pos.X, ctx.Pos.Y, ctx.TargetPos.X, ctx.TargetPos.Y)
+	return components.AIStateChase
+}
+
+// attackState holds position next to the target; the turn processor makes the attack
+type attackState struct{}
+
+func (attackState) Tick(ctx *AIContext) components.AIState {
+	if ctx.shouldFlee() {
+		return components.AIStateFlee
+	}
+	if !ctx.TargetVisible || !ctx.adjacentToTarget() {
+		return components.AIStateChase
+	}
+	ctx.AI.LastKnownTargetX, ctx.AI.LastKnownTargetY = ctx.TargetPos.X, ctx.TargetPos.Y
+	return components.AIStateAttack
+}
+
+// investigateState heads to where the target was last seen, giving up after a while
+type investigateState struct{}
+
+func (investigateState) Tick(ctx *AIContext) components.AIState {
+	if ctx.canEngage() {
+		return components.AIStateChase
+	}
+
+	arrived := ctx.Pos.X == ctx.AI.LastKnownTargetX && ctx.Pos.Y == ctx.AI.LastKnownTargetY
+	if arrived || ctx.AI.StateTurns >= ctx.Behavior.GiveUpTurns {
+		if ctx.Behavior.Wander {
+			return components.AIStateIdle
+		}
+		return components.AIStateReturn
+	}
+
+	ctx.Path = ctx.FindPath(ctx.Pos.X, ctx.Pos.Y, ctx.AI.LastKnownTargetX, ctx.AI.LastKnownTargetY)
+	if len(ctx.Path) == 0 {
+		return components.AIStateReturn
+	}
+	return components.AIStateInvestigate
+}
+
+// fleeState steps away from the target until it is out of sight or the entity recovers
+type fleeState struct{}
+
+func (fleeState) Tick(ctx *AIContext) components.AIState {
+	if !ctx.TargetVisible {
+		return components.AIStateReturn
+	}
+	if !ctx.shouldFlee() {
+		return components.AIStateChase
+	}
+
+	best := -1
+	for _, dir := range []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		x, y := ctx.Pos.X+dir.X, ctx.Pos.Y+dir.Y
+		if !ctx.IsValidMove(x, y) {
+			continue
+		}
+		if dist := abs(x-ctx.TargetPos.X) + abs(y-ctx.TargetPos.Y); dist > best {
+			best = dist
+			ctx.Path = []components.PathNode{{X: x, Y: y}}
+		}
+	}
+	return components.AIStateFlee
+}
+
+// returnState walks back home and settles there
+type returnState struct{}
+
+func (returnState) Tick(ctx *AIContext) components.AIState {
+	if ctx.canEngage() && !ctx.shouldFlee() {
+		return components.AIStateChase
+	}
+	if ctx.Pos.X == ctx.AI.HomeX && ctx.Pos.Y == ctx.AI.HomeY {
+		return components.AIStateIdle
+	}
+
+	ctx.Path = ctx.FindPath(ctx.Pos.X, ctx.Pos.Y, ctx.AI.HomeX, ctx.AI.HomeY)
+	if len(ctx.Path) == 0 {
+		// Home is unreachable, so make wherever we are home
+		ctx.AI.HomeX, ctx.AI.HomeY = ctx.Pos.X, ctx.Pos.Y
+		return components.AIStateIdle
+	}
+	return components.AIStateReturn
+}
+
+// abs returns the absolute value of an integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// sign returns -1, 0 or 1 for the sign of an integer
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
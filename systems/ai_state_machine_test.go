@@ -0,0 +1,158 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// newStateContext builds the context a monster at (2,5) sees with its target at
+// (9,5), on open floor where every path is the straight line
+func newStateContext(aiType string) *AIContext {
+	world := ecs.NewWorld()
+	monster := world.CreateEntity()
+	stats := &components.StatsComponent{Health: 10, MaxHealth: 10}
+	world.AddComponent(monster.ID, components.Stats, stats)
+	behavior, _ := GetAIBehavior(aiType)
+
+	return &AIContext{
+		World:     world,
+		EntityID:  monster.ID,
+		AI:        &components.AIComponent{Type: aiType},
+		Pos:       &components.PositionComponent{X: 2, Y: 5},
+		TargetPos: &components.PositionComponent{X: 9, Y: 5},
+		Behavior:  behavior,
+		FindPath: func(fromX, fromY, toX, toY int) []components.PathNode {
+			var path []components.PathNode
+			for _, point := range LinePoints(fromX, fromY, toX, toY)[1:] {
+				path = append(path, components.PathNode{X: point.X, Y: point.Y})
+			}
+			return path
+		},
+		IsValidMove: func(x, y int) bool { return true },
+	}
+}
+
+// tick runs the state machine and takes the first step of the path it chose
+func tick(m *AIStateMachine, ctx *AIContext) {
+	m.Tick(ctx)
+	if len(ctx.Path) > 0 {
+		ctx.Pos.X, ctx.Pos.Y = ctx.Path[0].X, ctx.Path[0].Y
+	}
+}
+
+func TestAIStateMachineChaseLoseInvestigateReturn(t *testing.T) {
+	m := NewAIStateMachine()
+	ctx := newStateContext("aggressive")
+
+	// Unseen, it waits where it started, which becomes home
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateIdle || ctx.AI.HomeX != 2 || ctx.AI.HomeY != 5 {
+		t.Fatalf("first tick: state %s home (%d,%d), want idle at home (2,5)", ctx.AI.State, ctx.AI.HomeX, ctx.AI.HomeY)
+	}
+
+	// Spotting the target starts the chase that same tick
+	ctx.TargetVisible = true
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateChase {
+		t.Fatalf("state after spotting the target = %s, want chase", ctx.AI.State)
+	}
+	if ctx.Pos.X != 3 {
+		t.Errorf("chaser at x %d, want it to have stepped to 3", ctx.Pos.X)
+	}
+	if ctx.AI.AlertTurns != alertTurns {
+		t.Errorf("alert turns = %d, want %d on spotting the target", ctx.AI.AlertTurns, alertTurns)
+	}
+
+	// Losing sight sends it to the last place it saw the target
+	ctx.TargetPos.X, ctx.TargetPos.Y = 6, 5
+	tick(m, ctx)
+	ctx.TargetVisible = false
+	ctx.TargetPos.X, ctx.TargetPos.Y = 6, 9
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateInvestigate {
+		t.Fatalf("state after losing sight = %s, want investigate", ctx.AI.State)
+	}
+	if ctx.AI.LastKnownTargetX != 6 || ctx.AI.LastKnownTargetY != 5 {
+		t.Errorf("last known target (%d,%d), want (6,5)", ctx.AI.LastKnownTargetX, ctx.AI.LastKnownTargetY)
+	}
+	for i := 0; i < 10 && ctx.AI.State == components.AIStateInvestigate; i++ {
+		tick(m, ctx)
+	}
+	if ctx.AI.State != components.AIStateReturn {
+		t.Fatalf("state after reaching an empty last known spot = %s, want return", ctx.AI.State)
+	}
+
+	// With nothing found it walks home and settles
+	for i := 0; i < 10 && ctx.AI.State == components.AIStateReturn; i++ {
+		tick(m, ctx)
+	}
+	if ctx.AI.State != components.AIStateIdle || ctx.Pos.X != 2 || ctx.Pos.Y != 5 {
+		t.Errorf("state %s at (%d,%d) after heading home, want idle at (2,5)", ctx.AI.State, ctx.Pos.X, ctx.Pos.Y)
+	}
+}
+
+func TestAIStateMachineAttacksWhenAdjacent(t *testing.T) {
+	m := NewAIStateMachine()
+	ctx := newStateContext("aggressive")
+	ctx.TargetVisible = true
+	ctx.TargetPos.X = 3
+
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateAttack {
+		t.Fatalf("state next to the target = %s, want attack", ctx.AI.State)
+	}
+	if ctx.Pos.X != 2 {
+		t.Errorf("attacker moved to x %d, want it to hold at 2", ctx.Pos.X)
+	}
+
+	// The target stepping away turns the attack back into a chase
+	ctx.TargetPos.X = 6
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateChase {
+		t.Errorf("state with the target out of reach = %s, want chase", ctx.AI.State)
+	}
+}
+
+func TestAIStateMachineGivesUpInvestigating(t *testing.T) {
+	m := NewAIStateMachine()
+	ctx := newStateContext("territorial")
+	ctx.AI.State = components.AIStateInvestigate
+	ctx.AI.HomeX, ctx.AI.HomeY = 2, 5
+	ctx.AI.LastKnownTargetX, ctx.AI.LastKnownTargetY = 30, 5
+
+	for range ctx.Behavior.GiveUpTurns {
+		tick(m, ctx)
+		if ctx.AI.State != components.AIStateInvestigate {
+			t.Fatalf("gave up investigating after %d turns, want %d", ctx.AI.StateTurns, ctx.Behavior.GiveUpTurns)
+		}
+	}
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateReturn {
+		t.Errorf("state after %d turns investigating = %s, want return", ctx.Behavior.GiveUpTurns, ctx.AI.State)
+	}
+}
+
+func TestAIStateMachineFleesWhenHurt(t *testing.T) {
+	m := NewAIStateMachine()
+	ctx := newStateContext("cowardly")
+	ctx.TargetVisible = true
+	statsComp, _ := ctx.World.GetComponent(ctx.EntityID, components.Stats)
+	statsComp.(*components.StatsComponent).Health = 2
+
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateFlee {
+		t.Fatalf("state when badly hurt = %s, want flee", ctx.AI.State)
+	}
+	if ctx.Pos.X != 1 {
+		t.Errorf("fleer at x %d, want it to have stepped away to 1", ctx.Pos.X)
+	}
+
+	// Out of sight it heads home again
+	ctx.TargetVisible = false
+	tick(m, ctx)
+	if ctx.AI.State != components.AIStateReturn {
+		t.Errorf("state once the target is out of sight = %s, want return", ctx.AI.State)
+	}
+}
@@ -3,7 +3,6 @@ package systems
 import (
 	"fmt"
 	"math"
-	"math/rand"
 
 	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
@@ -13,7 +12,9 @@ import (
 const EventAIPath ecs.EventType = "ai_path_event"
 
 // AITurnProcessorSystem handles AI movement based on calculated paths
-type AITurnProcessorSystem struct{}
+type AITurnProcessorSystem struct {
+	charges map[ecs.EntityID]*abilityCharge // Charged abilities to be released next turn, by monster
+}
 
 // Define action costs
 const (
@@ -24,7 +25,9 @@ const (
 
 // NewAITurnProcessorSystem creates a new AI turn processor system
 func NewAITurnProcessorSystem() *AITurnProcessorSystem {
-	return &AITurnProcessorSystem{}
+	return &AITurnProcessorSystem{
+		charges: make(map[ecs.EntityID]*abilityCharge),
+	}
 }
 
 // Initialize sets up event listeners for the AI turn processor system
@@ -33,6 +36,20 @@ func (s *AITurnProcessorSystem) Initialize(world *ecs.World) {
 	world.GetEventManager().Subscribe(EventAIPath, func(event ecs.Event) {
 		s.HandlePathEvent(world, event)
 	})
+
+	// A charge ends with its monster, or when its nerve breaks and it runs
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		delete(s.charges, event.(ecs.EntityRemovedEvent).EntityID)
+	})
+	world.GetEventManager().Subscribe(EventAIStateChanged, func(event ecs.Event) {
+		if stateEvent := event.(AIStateChangedEvent); stateEvent.To == components.AIStateFlee {
+			s.InterruptCharge(world, stateEvent.EntityID)
+		}
+	})
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.tickCharges(world)
+		s.tickPhases(world)
+	})
 }
 
 // HandlePathEvent processes AI path events
@@ -100,9 +117,11 @@ func (s *AITurnProcessorSystem) HandlePathEvent(world *ecs.World, event ecs.Even
 	}
 }
 
-// Update doesn't need to do anything since we work through event handling
+// Update marks the tiles charging monsters are about to hit and the monsters that
+// have phased; turns themselves are processed through event handling
 func (s *AITurnProcessorSystem) Update(world *ecs.World, dt float64) {
-	// The system is event-driven, no need for regular updates
+	s.highlightCharges(world)
+	s.highlightPhases(world)
 }
 
 // isAdjacentToPlayer checks if the given position is adjacent to the player
@@ -142,32 +161,30 @@ func (s *AITurnProcessorSystem) processTurn(world *ecs.World, entityID uint64, a
 		return
 	}
 	stats := statsComp.(*components.StatsComponent)
-	// Check if we're adjacent to the player and can attack
-	if adjacent, playerID := s.isAdjacentToPlayer(world, pos.X, pos.Y); adjacent && stats.ActionPoints >= AttackCost { // Process attack based on AI type
-		switch ai.Type {
-		case "slow_chase", "slow_wander":
-			// Both slow_chase and slow_wander attack when adjacent to player
-			world.GetEventManager().Emit(EnemyAttackEvent{
-				AttackerID: ecs.EntityID(entityID),
-				TargetID:   playerID,
-				X:          pos.X,
-				Y:          pos.Y,
-			})
-			stats.ActionPoints -= AttackCost
-			GetMessageLog().Add(fmt.Sprintf("DEBUG: AI attacked player (AP: %d)", stats.ActionPoints))
-			return
-		case "aggressive":
-			// Aggressive AI always attacks when adjacent
-			world.GetEventManager().Emit(EnemyAttackEvent{
-				AttackerID: ecs.EntityID(entityID),
-				TargetID:   playerID,
-				X:          pos.X,
-				Y:          pos.Y,
-			})
-			stats.ActionPoints -= AttackCost
-			GetMessageLog().Add(fmt.Sprintf("DEBUG: Aggressive AI attacked player (AP: %d)", stats.ActionPoints))
-			return
-		}
+
+	// A charged ability goes off on the turn after it was started, and a monster
+	// that can start one does so rather than attack
+	if s.releaseCharge(world, ecs.EntityID(entityID), stats) || s.startCharge(world, ecs.EntityID(entityID), ai, pos, stats) {
+		return
+	}
+
+	// Attack when next to the player, unless the state machine has us running away
+	if adjacent, playerID := s.isAdjacentToPlayer(world, pos.X, pos.Y); adjacent && ai.State != components.AIStateFlee && stats.ActionPoints >= AttackCost {
+		world.GetEventManager().Emit(EnemyAttackEvent{
+			AttackerID: ecs.EntityID(entityID),
+			TargetID:   playerID,
+			X:          pos.X,
+			Y:          pos.Y,
+		})
+		stats.ActionPoints -= AttackCost
+		GetMessageLog().Add(fmt.Sprintf("DEBUG: AI (%s) attacked player (AP: %d)", ai.State, stats.ActionPoints))
+		return
+	}
+
+	// Ghosts go straight through walls once phased, and phase when the way round is
+	// too long
+	if s.phaseStep(world, ecs.EntityID(entityID), ai, pos, stats) || s.startPhase(world, ecs.EntityID(entityID), ai, pos, path, stats) {
+		return
 	}
 
 	// Process movement or waiting based on action points and path
@@ -179,18 +196,12 @@ func (s *AITurnProcessorSystem) processTurn(world *ecs.World, entityID uint64, a
 		// Check if we can move there
 		canMove := s.isValidMove(world, nextStep.X, nextStep.Y)
 
-		if canMove && stats.ActionPoints >= MoveCost { // Handle AI type specific movement
-			switch ai.Type {
-			case "slow_chase", "slow_wander":
-				// 1 in 6 chance to skip movement
-				if rand.Intn(6) == 0 {
-					GetMessageLog().Add("DEBUG: AI skipped movement")
-					stats.ActionPoints -= WaitCost
-					return
-				}
-			case "aggressive":
-				// Aggressive AI never skips movement
-				// Always moves toward the player
+		if canMove && stats.ActionPoints >= MoveCost {
+			// Slow behaviors sometimes skip their move
+			if behavior, _ := GetAIBehavior(ai.Type); behavior.SkipChance > 0 && playRNG.Intn(behavior.SkipChance) == 0 {
+				GetMessageLog().Add("DEBUG: AI skipped movement")
+				stats.ActionPoints -= WaitCost
+				return
 			}
 
 			// Move to the next step
@@ -257,13 +268,13 @@ func (s *AITurnProcessorSystem) isValidMove(world *ecs.World, x, y int) bool {
 	}
 	gameMap := mapComp.(*components.MapComponent)
 
-	// Check for walls
-	if gameMap.IsWall(x, y) {
+	// Check for walls and other impassable tiles
+	if gameMap.BlocksMovement(x, y) {
 		return false
 	}
 
 	// Check for entity collision, only on the active map
-	for _, entity := range world.GetAllEntities() {
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
 		// Skip entities not on the active map
 		if world.HasComponent(entity.ID, components.MapContextID) {
 			mapContextComp, _ := world.GetComponent(entity.ID, components.MapContextID)
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+
+	"ebiten-rogue/ecs"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
@@ -17,8 +20,14 @@ type AudioSystem struct {
 	bgmStream    io.ReadSeeker
 	volume       float64
 	sampleRate   int
+	sounds       map[string][]byte // Decoded sound effects by name, nil entries are missing files
+	soundDir     string            // Directory sound effects are loaded from
 }
 
+// GameSounds are the sound effects the game plays, each loaded from <name>.ogg or
+// <name>.mp3 in the sound directory
+var GameSounds = []string{"lever", "break", "explosion", "low_health", "craft"}
+
 // NewAudioSystem creates a new audio system
 func NewAudioSystem() *AudioSystem {
 	sampleRate := 44100
@@ -26,7 +35,90 @@ func NewAudioSystem() *AudioSystem {
 		audioContext: audio.NewContext(sampleRate),
 		volume:       1.0, // Default volume
 		sampleRate:   sampleRate,
+		sounds:       make(map[string][]byte),
+		soundDir:     "assets/audio",
+	}
+}
+
+// Initialize loads the game's sound effects and subscribes the audio system to sound
+// events emitted by the game
+func (s *AudioSystem) Initialize(world *ecs.World) {
+	s.loadGameSounds()
+	world.GetEventManager().Subscribe(EventSound, func(event ecs.Event) {
+		s.PlaySound(event.(SoundEvent).Name)
+	})
+}
+
+// loadGameSounds loads every sound in GameSounds up front, noting the missing ones in
+// the debug log in a single line. They stay silent rather than being looked for again
+// each time they play.
+func (s *AudioSystem) loadGameSounds() {
+	var missing []string
+	for _, name := range GameSounds {
+		if _, loaded := s.sounds[name]; loaded {
+			continue
+		}
+		s.sounds[name] = s.loadSound(name)
+		if s.sounds[name] == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		GetDebugLog().Add(fmt.Sprintf("No sound files in %s for: %s. The game plays on without them.",
+			s.soundDir, strings.Join(missing, ", ")))
+	}
+}
+
+// PlaySound plays a sound effect from the sound directory (<name>.ogg or <name>.mp3).
+// Missing sounds are only noted in the debug log, once, so the game runs without them.
+func (s *AudioSystem) PlaySound(name string) {
+	data, loaded := s.sounds[name]
+	if !loaded {
+		data = s.loadSound(name)
+		s.sounds[name] = data
+		if data == nil {
+			GetDebugLog().Add(fmt.Sprintf("No sound file for %q", name))
+		}
 	}
+	if data == nil {
+		return
+	}
+
+	player := s.audioContext.NewPlayerFromBytes(data)
+	player.SetVolume(s.volume)
+	player.Play()
+}
+
+// loadSound decodes a sound effect into memory, returning nil if there is no file for
+// it or it can't be decoded
+func (s *AudioSystem) loadSound(name string) []byte {
+	for _, ext := range []string{".ogg", ".mp3"} {
+		path := s.soundDir + "/" + name + ext
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		defer file.Close()
+
+		var stream io.Reader
+		if ext == ".mp3" {
+			stream, err = mp3.DecodeWithSampleRate(s.sampleRate, file)
+		} else {
+			stream, err = vorbis.DecodeWithSampleRate(s.sampleRate, file)
+		}
+		if err != nil {
+			GetDebugLog().Add(fmt.Sprintf("Failed to decode sound %s: %v", path, err))
+			return nil
+		}
+
+		data, err := io.ReadAll(stream)
+		if err != nil {
+			GetDebugLog().Add(fmt.Sprintf("Failed to read sound %s: %v", path, err))
+			return nil
+		}
+		return data
+	}
+	return nil
 }
 
 // PlayBGM starts playing background music
@@ -0,0 +1,414 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// Auto-movement modes
+const (
+	AutoMoveNone    = ""
+	AutoMoveExplore = "explore" // Walk towards the nearest unexplored tile
+	AutoMoveTravel  = "travel"  // Walk to a known destination (stairs down)
+)
+
+// AutoExploreSystem walks the player along a computed route, either exploring
+// the current map or travelling to a known destination. The planned route is
+// exposed so the render system can preview it.
+type AutoExploreSystem struct {
+	mode         string                // Current auto-movement mode
+	path         []components.PathNode // Remaining route, first node is the next step
+	stepTimer    float64               // Time until the next step is taken
+	previewDelay float64               // How long the route is shown before the first step
+	stepDelay    float64               // Delay between steps while moving
+	lastHealth   int                   // Player health when the last step was taken
+	stuck        stuckGuard            // Halts the movement when it stops getting anywhere
+	keybindings  *Keybindings          // Keys that start exploring and travelling
+	initialized  bool
+}
+
+// NewAutoExploreSystem creates a new auto-explore system
+func NewAutoExploreSystem() *AutoExploreSystem {
+	return &AutoExploreSystem{
+		mode:         AutoMoveNone,
+		previewDelay: 0.35,
+		stepDelay:    0.08,
+		keybindings:  NewKeybindings(),
+	}
+}
+
+// SetKeybindings sets the keys auto-explore and travel are started with
+func (s *AutoExploreSystem) SetKeybindings(keybindings *Keybindings) {
+	s.keybindings = keybindings
+}
+
+// Initialize sets up event listeners
+func (s *AutoExploreSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// A route planned over terrain that has since changed is planned again
+	world.GetEventManager().Subscribe(EventMapMutated, func(event ecs.Event) {
+		s.replan(world, event.(MapMutatedEvent).MapID)
+	})
+
+	s.initialized = true
+}
+
+// IsActive returns whether auto-explore or travel is currently running
+func (s *AutoExploreSystem) IsActive() bool {
+	return s.mode != AutoMoveNone
+}
+
+// GetPath returns the remaining planned route, nearest step first
+func (s *AutoExploreSystem) GetPath() []components.PathNode {
+	return s.path
+}
+
+// Stop interrupts any auto-movement and clears the planned route
+func (s *AutoExploreSystem) Stop(reason string) {
+	if s.mode == AutoMoveNone {
+		return
+	}
+	s.mode = AutoMoveNone
+	s.path = nil
+	if reason != "" {
+		GetMessageLog().Add(reason)
+	}
+}
+
+// Update handles the explore/travel keys and advances the player along the route
+func (s *AutoExploreSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+
+	playerID, pos := s.getPlayer(world)
+	if playerID == 0 {
+		s.Stop("")
+		return
+	}
+
+	// Don't start or continue while the inventory is open
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok && renderSys.IsInventoryOpen() {
+			return
+		}
+	}
+
+	mapComp := s.getActiveMapComponent(world)
+	if mapComp == nil {
+		s.Stop("")
+		return
+	}
+
+	// X starts auto-explore, T travels to the stairs down
+	if s.keybindings.JustPressed(ActionAutoExplore) {
+		s.start(world, AutoMoveExplore, pos, mapComp)
+		return
+	}
+	if s.keybindings.JustPressed(ActionTravelStairs) {
+		s.start(world, AutoMoveTravel, pos, mapComp)
+		return
+	}
+
+	if s.mode == AutoMoveNone {
+		return
+	}
+
+	// Any other key press interrupts the movement
+	if len(JustPressedKeys()) > 0 {
+		s.Stop("Auto-movement interrupted.")
+		return
+	}
+
+	// Stop if a hostile comes into view or the player got hurt
+	if s.hostileInView(world, mapComp) {
+		s.Stop("You spot a hostile and stop.")
+		return
+	}
+	if stats := s.getStats(world, playerID); stats != nil && stats.Health < s.lastHealth {
+		s.Stop("You are hurt and stop.")
+		return
+	}
+
+	s.stepTimer -= dt
+	if s.stepTimer > 0 {
+		return
+	}
+	s.stepTimer = s.stepDelay
+
+	// Refresh the exploration target once it has been revealed
+	if s.mode == AutoMoveExplore && (len(s.path) == 0 || mapComp.Explored[s.path[len(s.path)-1].Y][s.path[len(s.path)-1].X]) {
+		s.path = s.findRoute(world, pos.X, pos.Y, mapComp, s.goalFunc(AutoMoveExplore, mapComp))
+		if len(s.path) == 0 {
+			s.Stop("Nothing left to explore here.")
+			return
+		}
+	}
+
+	if len(s.path) == 0 {
+		s.Stop("You have arrived.")
+		return
+	}
+
+	next := s.path[0]
+	world.EmitEvent(PlayerMoveAttemptEvent{
+		EntityID: playerID,
+		FromX:    pos.X,
+		FromY:    pos.Y,
+		ToX:      next.X,
+		ToY:      next.Y,
+	})
+
+	// If the move was blocked the route is no longer valid
+	if pos.X != next.X || pos.Y != next.Y {
+		s.Stop("Your path is blocked.")
+		return
+	}
+	s.path = s.path[1:]
+
+	if stats := s.getStats(world, playerID); stats != nil {
+		s.lastHealth = stats.Health
+	}
+
+	world.EmitEvent(TurnCompletedEvent{
+		EntityID: playerID,
+	})
+
+	// Routes to changing targets can end up pacing back and forth
+	if s.stuck.Record("Auto-"+s.mode, Point{pos.X, pos.Y}) {
+		s.Stop("You seem to be going in circles and stop.")
+		return
+	}
+
+	if s.mode == AutoMoveTravel && len(s.path) == 0 {
+		s.Stop("You have arrived.")
+	}
+}
+
+// start computes a route for the given mode and shows it before moving
+func (s *AutoExploreSystem) start(world *ecs.World, mode string, pos *components.PositionComponent, mapComp *components.MapComponent) {
+	if s.mode == mode {
+		s.Stop("Auto-movement stopped.")
+		return
+	}
+
+	if s.hostileInView(world, mapComp) {
+		GetMessageLog().Add("Not with enemies in view!")
+		return
+	}
+
+	path := s.findRoute(world, pos.X, pos.Y, mapComp, s.goalFunc(mode, mapComp))
+	if len(path) == 0 {
+		if mode == AutoMoveExplore {
+			GetMessageLog().Add("Nothing left to explore here.")
+		} else {
+			GetMessageLog().Add("You don't know a way down yet.")
+		}
+		return
+	}
+
+	s.mode = mode
+	s.path = path
+	s.stepTimer = s.previewDelay
+	s.stuck.Reset()
+	if stats := s.getStats(world, s.playerIDFromWorld(world)); stats != nil {
+		s.lastHealth = stats.Health
+	}
+
+	if mode == AutoMoveExplore {
+		GetMessageLog().Add("You begin exploring.")
+	} else {
+		GetMessageLog().Add(fmt.Sprintf("You set off towards the stairs (%d steps).", len(path)))
+	}
+}
+
+// replan plans the remaining route again after the terrain of the player's map
+// changed, stopping if there is no longer a way to the goal
+func (s *AutoExploreSystem) replan(world *ecs.World, mapID ecs.EntityID) {
+	if s.mode == AutoMoveNone {
+		return
+	}
+	playerID, pos := s.getPlayer(world)
+	mapComp := s.getActiveMapComponent(world)
+	if playerID == 0 || mapComp == nil || getEntityMapID(world, playerID) != mapID {
+		return
+	}
+
+	s.path = s.findRoute(world, pos.X, pos.Y, mapComp, s.goalFunc(s.mode, mapComp))
+	if len(s.path) == 0 {
+		s.Stop("The way ahead has changed and you stop.")
+	}
+}
+
+// goalFunc returns the destination test for a movement mode
+func (s *AutoExploreSystem) goalFunc(mode string, mapComp *components.MapComponent) func(x, y int) bool {
+	if mode == AutoMoveTravel {
+		return func(x, y int) bool {
+			return mapComp.Explored[y][x] && mapComp.Tiles[y][x] == components.TileStairsDown
+		}
+	}
+	return func(x, y int) bool {
+		return !mapComp.Explored[y][x]
+	}
+}
+
+// findRoute runs a breadth-first search from the start over explored, walkable
+// tiles and returns the route to the nearest tile satisfying isGoal
+func (s *AutoExploreSystem) findRoute(world *ecs.World, startX, startY int, mapComp *components.MapComponent, isGoal func(x, y int) bool) []components.PathNode {
+	// 8-directional movement, cardinal directions first for straighter routes
+	dirs := [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}}
+
+	blocked := s.blockingPositions(world)
+	cameFrom := make(map[Point]Point)
+	start := Point{startX, startY}
+	cameFrom[start] = start
+	queue := []Point{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current != start && isGoal(current.X, current.Y) {
+			// Walk back to the start to build the route
+			var path []components.PathNode
+			for n := current; n != start; n = cameFrom[n] {
+				path = append([]components.PathNode{{X: n.X, Y: n.Y}}, path...)
+			}
+			return path
+		}
+
+		// Only expand through tiles we actually know about
+		if current != start && !mapComp.Explored[current.Y][current.X] {
+			continue
+		}
+
+		for _, dir := range dirs {
+			next := Point{current.X + dir[0], current.Y + dir[1]}
+			if next.X < 0 || next.X >= mapComp.Width || next.Y < 0 || next.Y >= mapComp.Height {
+				continue
+			}
+			if _, seen := cameFrom[next]; seen {
+				continue
+			}
+			if mapComp.BlocksMovement(next.X, next.Y) || mapComp.IsHazard(next.X, next.Y) {
+				continue
+			}
+			if blocked[next] {
+				continue
+			}
+			cameFrom[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// blockingPositions returns the positions of blocking entities on the active map
+func (s *AutoExploreSystem) blockingPositions(world *ecs.World) map[Point]bool {
+	blocked := make(map[Point]bool)
+	activeMap := s.getActiveMap(world)
+	if activeMap == nil {
+		return blocked
+	}
+
+	for _, entity := range world.GetEntitiesWithComponent(components.Collision) {
+		if entity.HasTag("player") {
+			continue
+		}
+		if contextComp, ok := world.GetComponent(entity.ID, components.MapContextID); !ok ||
+			contextComp.(*components.MapContextComponent).MapID != activeMap.ID {
+			continue
+		}
+		collisionComp, _ := world.GetComponent(entity.ID, components.Collision)
+		if !collisionComp.(*components.CollisionComponent).Blocks {
+			continue
+		}
+		if posComp, ok := world.GetComponent(entity.ID, components.Position); ok {
+			pos := posComp.(*components.PositionComponent)
+			blocked[Point{pos.X, pos.Y}] = true
+		}
+	}
+	return blocked
+}
+
+// hostileInView returns true if any enemy on the active map is on a visible tile
+func (s *AutoExploreSystem) hostileInView(world *ecs.World, mapComp *components.MapComponent) bool {
+	activeMap := s.getActiveMap(world)
+	if activeMap == nil {
+		return false
+	}
+
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if contextComp, ok := world.GetComponent(entity.ID, components.MapContextID); !ok ||
+			contextComp.(*components.MapContextComponent).MapID != activeMap.ID {
+			continue
+		}
+		posComp, ok := world.GetComponent(entity.ID, components.Position)
+		if !ok {
+			continue
+		}
+		pos := posComp.(*components.PositionComponent)
+		if pos.X >= 0 && pos.X < mapComp.Width && pos.Y >= 0 && pos.Y < mapComp.Height && mapComp.Visible[pos.Y][pos.X] {
+			return true
+		}
+	}
+	return false
+}
+
+// getPlayer returns the player ID and position
+func (s *AutoExploreSystem) getPlayer(world *ecs.World) (ecs.EntityID, *components.PositionComponent) {
+	playerID := s.playerIDFromWorld(world)
+	if playerID == 0 {
+		return 0, nil
+	}
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return 0, nil
+	}
+	return playerID, posComp.(*components.PositionComponent)
+}
+
+// playerIDFromWorld returns the player entity ID or 0 if not found
+func (s *AutoExploreSystem) playerIDFromWorld(world *ecs.World) ecs.EntityID {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return 0
+	}
+	return playerEntities[0].ID
+}
+
+// getStats returns the stats component of an entity, or nil
+func (s *AutoExploreSystem) getStats(world *ecs.World, entityID ecs.EntityID) *components.StatsComponent {
+	if statsComp, exists := world.GetComponent(entityID, components.Stats); exists {
+		return statsComp.(*components.StatsComponent)
+	}
+	return nil
+}
+
+// getActiveMap returns the active map entity from the map registry
+func (s *AutoExploreSystem) getActiveMap(world *ecs.World) *ecs.Entity {
+	for _, system := range world.GetSystems() {
+		if mapRegistry, ok := system.(*MapRegistrySystem); ok {
+			return mapRegistry.GetActiveMap()
+		}
+	}
+	return nil
+}
+
+// getActiveMapComponent returns the map component of the active map
+func (s *AutoExploreSystem) getActiveMapComponent(world *ecs.World) *components.MapComponent {
+	activeMap := s.getActiveMap(world)
+	if activeMap == nil {
+		return nil
+	}
+	if comp, exists := world.GetComponent(activeMap.ID, components.MapComponentID); exists {
+		return comp.(*components.MapComponent)
+	}
+	return nil
+}
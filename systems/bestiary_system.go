@@ -0,0 +1,216 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+	"ebiten-rogue/ecs"
+)
+
+// BestiaryEntry records what the player knows about a monster type
+type BestiaryEntry struct {
+	TemplateID  string `json:"template_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Health      int    `json:"health"`
+	Attack      int    `json:"attack"`
+	Defense     int    `json:"defense"`
+	Level       int    `json:"level"`
+	XP          int    `json:"xp"`
+	Seen        int    `json:"seen"`  // Number of times this monster type was sighted
+	Kills       int    `json:"kills"` // Number of times the player killed this monster type
+}
+
+// BestiarySystem records every monster type the player has seen or killed
+type BestiarySystem struct {
+	entries         map[string]*BestiaryEntry
+	order           []string // Template IDs in the order they were discovered
+	templateManager *data.EntityTemplateManager
+	savePath        string // File the bestiary is persisted to, empty for per-run only
+	dirty           bool   // Repeat sightings not yet written to the file
+	initialized     bool
+}
+
+// NewBestiarySystem creates a new bestiary system
+func NewBestiarySystem() *BestiarySystem {
+	return &BestiarySystem{
+		entries: make(map[string]*BestiaryEntry),
+	}
+}
+
+// SetTemplateManager sets the template manager used to look up monster flavor text
+func (s *BestiarySystem) SetTemplateManager(templateManager *data.EntityTemplateManager) {
+	s.templateManager = templateManager
+}
+
+// Initialize sets up event listeners
+func (s *BestiarySystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Record monsters as they come into view
+	world.GetEventManager().Subscribe(EventEntityRevealed, func(event ecs.Event) {
+		revealed := event.(EntityRevealedEvent)
+		s.RecordSighting(world, revealed.EntityID)
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *BestiarySystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// RecordSighting notes that the player has seen the given monster
+func (s *BestiarySystem) RecordSighting(world *ecs.World, entityID ecs.EntityID) {
+	entry, isNew := s.getOrCreateEntry(world, entityID)
+	if entry == nil {
+		return
+	}
+	entry.Seen++
+
+	// Monsters are sighted all the time, so a repeat sighting waits for the next save
+	// instead of writing the file on every one
+	if !isNew {
+		s.dirty = true
+		return
+	}
+	GetMessageLog().AddSystem(fmt.Sprintf("New bestiary entry: %s", entry.Name))
+	s.save()
+}
+
+// RecordKill notes that the player has killed the given monster
+func (s *BestiarySystem) RecordKill(world *ecs.World, entityID ecs.EntityID) {
+	entry, isNew := s.getOrCreateEntry(world, entityID)
+	if entry == nil {
+		return
+	}
+	entry.Kills++
+
+	if isNew {
+		GetMessageLog().AddSystem(fmt.Sprintf("New bestiary entry: %s", entry.Name))
+	}
+	s.save()
+}
+
+// GetEntries returns all bestiary entries in discovery order
+func (s *BestiarySystem) GetEntries() []*BestiaryEntry {
+	entries := make([]*BestiaryEntry, 0, len(s.order))
+	for _, id := range s.order {
+		entries = append(entries, s.entries[id])
+	}
+	return entries
+}
+
+// IsScanned returns true once the player has killed a monster type, revealing its exact stats
+func (s *BestiarySystem) IsScanned(templateID string) bool {
+	entry, exists := s.entries[templateID]
+	return exists && entry.Kills > 0
+}
+
+// getOrCreateEntry returns the entry for a monster's template, creating it on first encounter
+func (s *BestiarySystem) getOrCreateEntry(world *ecs.World, entityID ecs.EntityID) (*BestiaryEntry, bool) {
+	aiComp, exists := world.GetComponent(entityID, components.AI)
+	if !exists {
+		return nil, false
+	}
+	templateID := aiComp.(*components.AIComponent).TemplateID
+	if templateID == "" {
+		return nil, false
+	}
+
+	if entry, exists := s.entries[templateID]; exists {
+		return entry, false
+	}
+
+	entry := &BestiaryEntry{
+		TemplateID: templateID,
+		Name:       getEntityName(world, entityID),
+	}
+
+	// Prefer the template's base values so buffs on this particular monster don't leak in
+	if s.templateManager != nil {
+		if template, ok := s.templateManager.GetTemplate(templateID); ok {
+			entry.Name = template.Name
+			entry.Description = template.Description
+			entry.Health = template.Health
+			entry.Attack = template.Attack
+			entry.Defense = template.Defense
+			entry.Level = template.Level
+			entry.XP = template.XP
+		}
+	} else if statsComp, ok := world.GetComponent(entityID, components.Stats); ok {
+		stats := statsComp.(*components.StatsComponent)
+		entry.Health = stats.MaxHealth
+		entry.Attack = stats.Attack
+		entry.Defense = stats.Defense
+		entry.Level = stats.Level
+		entry.XP = stats.Exp
+	}
+
+	s.entries[templateID] = entry
+	s.order = append(s.order, templateID)
+	return entry, true
+}
+
+// LoadFromFile loads a persisted bestiary and keeps saving to the same file.
+// A missing file is not an error; the bestiary simply starts empty.
+func (s *BestiarySystem) LoadFromFile(path string) error {
+	s.savePath = path
+
+	fileData, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read bestiary: %w", err)
+	}
+
+	var entries []*BestiaryEntry
+	if err := json.Unmarshal(fileData, &entries); err != nil {
+		return fmt.Errorf("failed to parse bestiary: %w", err)
+	}
+
+	s.entries = make(map[string]*BestiaryEntry)
+	s.order = nil
+	for _, entry := range entries {
+		if entry.TemplateID == "" {
+			continue
+		}
+		s.entries[entry.TemplateID] = entry
+		s.order = append(s.order, entry.TemplateID)
+	}
+	return nil
+}
+
+// Flush writes repeat sightings that haven't been saved yet, when the player changes
+// level or leaves the game
+func (s *BestiarySystem) Flush() {
+	if s.dirty {
+		s.save()
+	}
+}
+
+// save writes the bestiary to disk if persistence is enabled
+func (s *BestiarySystem) save() {
+	s.dirty = false
+	if s.savePath == "" {
+		return
+	}
+
+	fileData, err := json.MarshalIndent(s.GetEntries(), "", "  ")
+	if err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Failed to encode bestiary: %v", err))
+		return
+	}
+	if err := os.WriteFile(s.savePath, fileData, 0644); err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Failed to save bestiary: %v", err))
+	}
+}
@@ -0,0 +1,365 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// blastNoiseRadius is how far away monsters hear an explosion
+const blastNoiseRadius = 15
+
+// BombSystem burns down the fuses of armed bombs and resolves their explosions
+type BombSystem struct {
+	throwRange  int // Furthest a bomb can be thrown in tiles
+	initialized bool
+}
+
+// NewBombSystem creates a new bomb system
+func NewBombSystem() *BombSystem {
+	return &BombSystem{
+		throwRange: 5,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *BombSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Fuses burn down with the player's turns
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.tick(world)
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *BombSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// Throw arms a bomb and throws it at the nearest hostile in view, or drops it at the
+// thrower's feet if there is none
+func (s *BombSystem) Throw(world *ecs.World, throwerID, bombID ecs.EntityID) bool {
+	posComp, exists := world.GetComponent(throwerID, components.Position)
+	if !exists {
+		return false
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	mapID := getEntityMapID(world, throwerID)
+	mapComp := s.getMapComponent(world, mapID)
+	if mapComp == nil {
+		return false
+	}
+
+	targetX, targetY := pos.X, pos.Y
+	if targetID := s.findThrowTarget(world, mapComp, mapID, pos); targetID != 0 {
+		targetPosComp, _ := world.GetComponent(targetID, components.Position)
+		targetPos := targetPosComp.(*components.PositionComponent)
+
+		// The bomb flies along the line until something solid stops it
+		for _, point := range LinePoints(pos.X, pos.Y, targetPos.X, targetPos.Y)[1:] {
+			if mapComp.IsWall(point.X, point.Y) {
+				break
+			}
+			targetX, targetY = point.X, point.Y
+		}
+	}
+
+	if !s.Arm(world, bombID, throwerID, mapID, targetX, targetY) {
+		return false
+	}
+
+	bombName := getEntityName(world, bombID)
+	if targetX == pos.X && targetY == pos.Y {
+		GetMessageLog().AddCombat(fmt.Sprintf("You light the %s and drop it at your feet. Run!", bombName))
+	} else {
+		GetMessageLog().AddCombat(fmt.Sprintf("You light the %s and throw it.", bombName))
+	}
+	return true
+}
+
+// Arm lights a bomb's fuse and places it on the map
+func (s *BombSystem) Arm(world *ecs.World, bombID, ownerID, mapID ecs.EntityID, x, y int) bool {
+	bombComp, exists := world.GetComponent(bombID, components.Bomb)
+	if !exists {
+		return false
+	}
+	bomb := bombComp.(*components.BombComponent)
+
+	bomb.Armed = true
+	bomb.OwnerID = ownerID
+
+	world.AddComponent(bombID, components.Position, &components.PositionComponent{X: x, Y: y})
+	world.AddComponent(bombID, components.MapContextID, components.NewMapContextComponent(mapID))
+	world.AddComponent(bombID, components.Renderable, components.NewRenderableComponent(fuseGlyph(bomb.Fuse), color.RGBA{255, 120, 40, 255}))
+	return true
+}
+
+// tick burns down every armed fuse and detonates the bombs that run out
+func (s *BombSystem) tick(world *ecs.World) {
+	var expired []ecs.EntityID
+	for _, entity := range world.GetEntitiesWithComponent(components.Bomb) {
+		bombComp, _ := world.GetComponent(entity.ID, components.Bomb)
+		bomb := bombComp.(*components.BombComponent)
+		if !bomb.Armed {
+			continue
+		}
+
+		bomb.Fuse--
+		if bomb.Fuse <= 0 {
+			expired = append(expired, entity.ID)
+			continue
+		}
+
+		// The remaining fuse is shown on the map
+		if rendComp, exists := world.GetComponent(entity.ID, components.Renderable); exists {
+			rendComp.(*components.RenderableComponent).Char = fuseGlyph(bomb.Fuse)
+		}
+	}
+
+	for _, bombID := range expired {
+		// An earlier blast may already have set this one off
+		if world.GetEntity(bombID) != nil {
+			s.Detonate(world, bombID)
+		}
+	}
+}
+
+// Detonate explodes a bomb, applying its effects to everything in the blast and
+// setting off any other bombs caught in it
+func (s *BombSystem) Detonate(world *ecs.World, bombID ecs.EntityID) {
+	queue := []ecs.EntityID{bombID}
+	exploded := make(map[ecs.EntityID]bool)
+
+	for len(queue) > 0 {
+		currentID := queue[0]
+		queue = queue[1:]
+		if exploded[currentID] {
+			continue
+		}
+		exploded[currentID] = true
+
+		for _, chainedID := range s.explode(world, currentID) {
+			if !exploded[chainedID] {
+				GetMessageLog().AddCombat(fmt.Sprintf("The blast sets off the %s!", getEntityName(world, chainedID)))
+				queue = append(queue, chainedID)
+			}
+		}
+
+		world.RemoveEntity(currentID)
+	}
+}
+
+// explode applies a single bomb's blast and returns the other bombs caught in it
+func (s *BombSystem) explode(world *ecs.World, bombID ecs.EntityID) []ecs.EntityID {
+	bombComp, exists := world.GetComponent(bombID, components.Bomb)
+	if !exists {
+		return nil
+	}
+	bomb := bombComp.(*components.BombComponent)
+
+	posComp, exists := world.GetComponent(bombID, components.Position)
+	if !exists {
+		return nil
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	mapID := getEntityMapID(world, bombID)
+	if s.getMapComponent(world, mapID) == nil {
+		return nil
+	}
+
+	GetMessageLog().AddAlert(fmt.Sprintf("The %s explodes!", getEntityName(world, bombID)))
+
+	var blastEffects []components.GameEffect
+	if itemComp, exists := world.GetComponent(bombID, components.Item); exists {
+		if effects, ok := itemComp.(*components.ItemComponent).Data.([]components.GameEffect); ok {
+			blastEffects = effects
+		}
+	}
+
+	return s.Blast(world, bombID, bomb.OwnerID, mapID, pos.X, pos.Y, bomb.Radius, blastEffects)
+}
+
+// Blast applies an explosion's effects to everything within the radius of a tile,
+// with walls sheltering whatever is behind them. Props caught in it are broken. It
+// returns the armed bombs caught in it, which the caller sets off in turn.
+func (s *BombSystem) Blast(world *ecs.World, sourceID, ownerID, mapID ecs.EntityID, x, y, radius int, effects []components.GameEffect) []ecs.EntityID {
+	mapComp := s.getMapComponent(world, mapID)
+	if mapComp == nil {
+		return nil
+	}
+
+	world.EmitEvent(SoundEvent{Name: "explosion"})
+	world.EmitEvent(NoiseEvent{MapID: mapID, X: x, Y: y, Radius: blastNoiseRadius})
+
+	// Walls shelter whatever is behind them
+	inBlast := make(map[Point]bool)
+	for _, tile := range TilesInRadius(mapComp, x, y, radius) {
+		inBlast[tile] = true
+	}
+
+	var chained []ecs.EntityID
+	var victims []ecs.EntityID
+	var props []ecs.EntityID
+	for _, entity := range world.GetEntitiesWithComponent(components.Position) {
+		if entity.ID == sourceID || getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		targetPosComp, _ := world.GetComponent(entity.ID, components.Position)
+		targetPos := targetPosComp.(*components.PositionComponent)
+		if !inBlast[Point{targetPos.X, targetPos.Y}] {
+			continue
+		}
+
+		if world.HasComponent(entity.ID, components.Bomb) {
+			chained = append(chained, entity.ID)
+		} else if world.HasComponent(entity.ID, components.Destructible) {
+			props = append(props, entity.ID)
+		} else if world.HasComponent(entity.ID, components.Stats) {
+			victims = append(victims, entity.ID)
+		}
+	}
+
+	effectsSystem := s.getEffectsSystem(world)
+	for _, victimID := range victims {
+		s.applyBlast(world, effectsSystem, victimID, ownerID, effects)
+	}
+
+	// Props are too flimsy to survive a blast. One may already have gone up in the
+	// blast of another that was caught before it, and the bombs an exploding one
+	// catches are set off along with the rest.
+	if combatSystem := s.getCombatSystem(world); combatSystem != nil {
+		for _, propID := range props {
+			if world.GetEntity(propID) != nil {
+				chained = append(chained, combatSystem.BreakDestructible(world, propID, ownerID)...)
+			}
+		}
+	}
+
+	return chained
+}
+
+// applyBlast applies a bomb's effects to one entity caught in the explosion
+func (s *BombSystem) applyBlast(world *ecs.World, effectsSystem *EffectsSystem, victimID, ownerID ecs.EntityID, effects []components.GameEffect) {
+	statsComp, _ := world.GetComponent(victimID, components.Stats)
+	stats := statsComp.(*components.StatsComponent)
+	healthBefore := stats.Health
+
+	if effectsSystem != nil {
+		for _, effect := range effects {
+			effectsSystem.applyEffect(world, victimID, effect)
+		}
+	}
+
+	victimName := getEntityName(world, victimID)
+	if damage := healthBefore - stats.Health; damage > 0 {
+		GetMessageLog().AddCombat(fmt.Sprintf("%s is caught in the blast for %d damage! %s has %d/%d HP remaining.",
+			victimName, damage, victimName, stats.Health, stats.MaxHealth))
+	}
+
+	if stats.Health > 0 {
+		return
+	}
+
+	if combatSystem := s.getCombatSystem(world); combatSystem != nil {
+		combatSystem.killEntity(world, ownerID, victimID)
+	}
+}
+
+// findThrowTarget returns the target picked in targeting mode if it is within throwing
+// range, otherwise the nearest hostile the thrower can see
+func (s *BombSystem) findThrowTarget(world *ecs.World, mapComp *components.MapComponent, mapID ecs.EntityID, pos *components.PositionComponent) ecs.EntityID {
+	if targeting := s.getTargetingSystem(world); targeting != nil {
+		if targetID := targeting.GetTarget(world); targetID != 0 {
+			targetPosComp, _ := world.GetComponent(targetID, components.Position)
+			targetPos := targetPosComp.(*components.PositionComponent)
+			if math.Hypot(float64(targetPos.X-pos.X), float64(targetPos.Y-pos.Y)) <= float64(s.throwRange) {
+				return targetID
+			}
+		}
+	}
+
+	var bestID ecs.EntityID
+	bestDist := math.MaxFloat64
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		posComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		enemyPos := posComp.(*components.PositionComponent)
+
+		dist := math.Hypot(float64(enemyPos.X-pos.X), float64(enemyPos.Y-pos.Y))
+		if dist > float64(s.throwRange) || dist >= bestDist {
+			continue
+		}
+		if !mapComp.Visible[enemyPos.Y][enemyPos.X] || !HasLineOfSight(mapComp, pos.X, pos.Y, enemyPos.X, enemyPos.Y) {
+			continue
+		}
+		bestID = entity.ID
+		bestDist = dist
+	}
+	return bestID
+}
+
+// getMapComponent returns the map component of a map entity
+func (s *BombSystem) getMapComponent(world *ecs.World, mapID ecs.EntityID) *components.MapComponent {
+	if comp, exists := world.GetComponent(mapID, components.MapComponentID); exists {
+		return comp.(*components.MapComponent)
+	}
+	return nil
+}
+
+// getEffectsSystem returns the effects system used to apply blast effects
+func (s *BombSystem) getEffectsSystem(world *ecs.World) *EffectsSystem {
+	for _, system := range world.GetSystems() {
+		if effectsSystem, ok := system.(*EffectsSystem); ok {
+			return effectsSystem
+		}
+	}
+	return nil
+}
+
+// getCombatSystem returns the combat system blast kills and broken props go through
+func (s *BombSystem) getCombatSystem(world *ecs.World) *CombatSystem {
+	for _, system := range world.GetSystems() {
+		if combatSystem, ok := system.(*CombatSystem); ok {
+			return combatSystem
+		}
+	}
+	return nil
+}
+
+// getTargetingSystem returns the targeting system holding the player's chosen target
+func (s *BombSystem) getTargetingSystem(world *ecs.World) *TargetingSystem {
+	for _, system := range world.GetSystems() {
+		if targeting, ok := system.(*TargetingSystem); ok {
+			return targeting
+		}
+	}
+	return nil
+}
+
+// fuseGlyph returns the digit shown on a bomb with the given fuse
+func fuseGlyph(fuse int) rune {
+	if fuse > 9 {
+		return '*'
+	}
+	if fuse < 0 {
+		fuse = 0
+	}
+	return rune('0' + fuse)
+}
@@ -0,0 +1,49 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// newBombPickupWorld puts the player alone on an open floor with an empty pack and a
+// bomb
+func newBombPickupWorld(t *testing.T) (*testWorld, *components.InventoryComponent, ecs.EntityID) {
+	t.Helper()
+	w := newTestWorld(t, 10, 10, 5, 5)
+	pack := components.NewInventoryComponent(10)
+	w.world.AddComponent(w.playerID, components.Inventory, pack)
+
+	bomb := w.world.CreateEntity()
+	w.world.TagEntity(bomb.ID, "item")
+	w.world.AddComponent(bomb.ID, components.Name, components.NewNameComponent("Bomb"))
+	w.world.AddComponent(bomb.ID, components.Bomb, components.NewBombComponent(3, 1))
+	return w, pack, bomb.ID
+}
+
+func TestArmedBombAtFeetIsNotPickedUp(t *testing.T) {
+	w, pack, bombID := newBombPickupWorld(t)
+	pack.AddItem(bombID)
+
+	// With nobody to throw it at, the bomb is dropped where the player stands
+	if !NewBombSystem().Throw(w.world, w.playerID, bombID) {
+		t.Fatal("Throw failed")
+	}
+	pack.RemoveItem(bombID)
+
+	NewInventorySystem().checkItemPickups(w.world, w.playerID, w.player)
+	if pack.Contains(bombID) {
+		t.Error("the player picked their lit bomb back up")
+	}
+}
+
+func TestUnarmedBombIsPickedUp(t *testing.T) {
+	w, pack, bombID := newBombPickupWorld(t)
+	w.world.AddComponent(bombID, components.Position, &components.PositionComponent{X: w.player.X, Y: w.player.Y})
+
+	NewInventorySystem().checkItemPickups(w.world, w.playerID, w.player)
+	if !pack.Contains(bombID) {
+		t.Error("an unlit bomb on the floor wasn't picked up")
+	}
+}
@@ -0,0 +1,241 @@
+package systems
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+const (
+	CallForHelpDelay   = 2  // Turns between a call for help and the reinforcements arriving
+	CallNoiseRadius    = 10 // How far a call is heard when the ability doesn't give a range
+	ReinforcementRange = 15 // How far from the caller reinforcements look for unexplored ground
+)
+
+// pendingCall is a call for help whose reinforcements are still on their way
+type pendingCall struct {
+	mapID     ecs.EntityID
+	summons   []string
+	count     int
+	turnsLeft int
+}
+
+// SetEnemyCreator sets how reinforcements are spawned; without one, calls for help
+// still alert nearby monsters but bring nobody new
+func (s *MonsterAbilitySystem) SetEnemyCreator(createEnemy func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error)) {
+	s.createEnemy = createEnemy
+}
+
+// handleStateChange fires the on_aggro abilities of a monster that has just spotted
+// its target and the on_flee abilities of one that has just broken and run
+func (s *MonsterAbilitySystem) handleStateChange(world *ecs.World, event AIStateChangedEvent) {
+	var trigger components.MonsterAbilityTrigger
+	switch {
+	case isHunting(event.To) && !isHunting(event.From) && event.From != components.AIStateFlee:
+		trigger = components.TriggerOnAggro
+	case event.To == components.AIStateFlee:
+		trigger = components.TriggerOnFlee
+	default:
+		return
+	}
+
+	abilityComp, exists := world.GetComponent(event.EntityID, components.MonsterAbility)
+	if !exists {
+		return
+	}
+	abilities := abilityComp.(*components.MonsterAbilityComponent)
+	for i := range abilities.Abilities {
+		ability := &abilities.Abilities[i]
+		if ability.Trigger != trigger || ability.Action != components.AbilityActionCallForHelp {
+			continue
+		}
+		if ability.CurrentCD > 0 || (ability.MaxUses > 0 && ability.Uses >= ability.MaxUses) {
+			continue
+		}
+		if ability.Chance > 0 && s.rng.Float64() >= ability.Chance {
+			continue
+		}
+		s.callForHelp(world, event.EntityID, ability)
+		return
+	}
+}
+
+// callForHelp spends the caller's turn on the call, alerts the monsters in earshot and
+// sends for reinforcements, which arrive after CallForHelpDelay turns if the caller
+// is still alive
+func (s *MonsterAbilitySystem) callForHelp(world *ecs.World, callerID ecs.EntityID, ability *components.MonsterAbilityDef) {
+	posComp, hasPos := world.GetComponent(callerID, components.Position)
+	mapID := getEntityMapID(world, callerID)
+	if !hasPos || mapID == 0 {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	ability.Uses++
+	ability.CurrentCD = ability.Cooldown
+
+	// Calling takes the whole turn
+	if statsComp, exists := world.GetComponent(callerID, components.Stats); exists {
+		statsComp.(*components.StatsComponent).ActionPoints = 0
+	}
+
+	radius := ability.Range
+	if radius <= 0 {
+		radius = CallNoiseRadius
+	}
+	world.EmitEvent(NoiseEvent{MapID: mapID, X: pos.X, Y: pos.Y, Radius: radius})
+
+	name := strings.ToLower(getEntityName(world, callerID))
+	if mapComp, exists := world.GetComponent(mapID, components.MapComponentID); exists && mapComp.(*components.MapComponent).Visible[pos.Y][pos.X] {
+		GetMessageLog().AddAlert(fmt.Sprintf("The %s uses %s and calls for help! Reinforcements are coming.", name, ability.Name))
+	} else {
+		GetMessageLog().AddAlert("Somewhere nearby, something calls for help.")
+	}
+
+	summons := ability.Summons
+	if len(summons) == 0 {
+		if aiComp, exists := world.GetComponent(callerID, components.AI); exists {
+			summons = []string{aiComp.(*components.AIComponent).TemplateID}
+		}
+	}
+	s.calls[callerID] = &pendingCall{
+		mapID:     mapID,
+		summons:   summons,
+		count:     max(1, ability.Count),
+		turnsLeft: CallForHelpDelay,
+	}
+	GetDebugLog().Add(fmt.Sprintf("MonsterAbilitySystem: %s called for %d of %v (use %d)", name, max(1, ability.Count), summons, ability.Uses))
+}
+
+// tickCalls counts down the calls for help under way and the cooldowns of the
+// abilities that make them, bringing in the reinforcements of calls that are due
+func (s *MonsterAbilitySystem) tickCalls(world *ecs.World) {
+	world.Query(components.MonsterAbility).Each(func(entity *ecs.Entity) {
+		abilityComp, _ := world.GetComponent(entity.ID, components.MonsterAbility)
+		for i := range abilityComp.(*components.MonsterAbilityComponent).Abilities {
+			ability := &abilityComp.(*components.MonsterAbilityComponent).Abilities[i]
+			if ability.Action == components.AbilityActionCallForHelp && ability.CurrentCD > 0 {
+				ability.CurrentCD--
+			}
+		}
+	})
+
+	// Reinforcements are placed with the ability RNG, so calls are answered in a fixed
+	// order
+	callers := make([]ecs.EntityID, 0, len(s.calls))
+	for callerID := range s.calls {
+		callers = append(callers, callerID)
+	}
+	slices.Sort(callers)
+	for _, callerID := range callers {
+		call := s.calls[callerID]
+		call.turnsLeft--
+		if call.turnsLeft > 0 {
+			continue
+		}
+		delete(s.calls, callerID)
+		s.bringReinforcements(world, callerID, call)
+	}
+}
+
+// bringReinforcements spawns a call's reinforcements out of the player's sight, on
+// unexplored ground near the caller or else towards the edge of the map, and sends
+// them to where the player is
+func (s *MonsterAbilitySystem) bringReinforcements(world *ecs.World, callerID ecs.EntityID, call *pendingCall) {
+	if s.createEnemy == nil || len(call.summons) == 0 {
+		return
+	}
+
+	// Nobody turns up on a floor the player is camped on
+	for _, system := range world.GetSystems() {
+		if campSystem, ok := system.(*CampSystem); ok && campSystem.SuppressesSpawns(call.mapID) {
+			GetDebugLog().Add("MonsterAbilitySystem: Reinforcements held off by the player's camp")
+			return
+		}
+	}
+	caller, ok := entityPoint(world, callerID)
+	if !ok || getEntityMapID(world, callerID) != call.mapID {
+		return
+	}
+	mapComp, exists := world.GetComponent(call.mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	spots := s.reinforcementSpots(world, gameMap, call.mapID, caller)
+	target := caller
+	if playerEntities := world.GetEntitiesWithTag("player"); len(playerEntities) > 0 {
+		if playerPos, found := entityPoint(world, playerEntities[0].ID); found && getEntityMapID(world, playerEntities[0].ID) == call.mapID {
+			target = playerPos
+		}
+	}
+
+	arrived := 0
+	for i := 0; i < call.count && len(spots) > 0; i++ {
+		pick := s.rng.Intn(len(spots))
+		spot := spots[pick]
+		spots = append(spots[:pick], spots[pick+1:]...)
+
+		templateID := call.summons[s.rng.Intn(len(call.summons))]
+		enemyID, err := s.createEnemy(call.mapID, spot.X, spot.Y, templateID)
+		if err != nil {
+			GetDebugLog().Add(fmt.Sprintf("MonsterAbilitySystem: Failed to bring reinforcement %q: %v", templateID, err))
+			continue
+		}
+		if aiComp, exists := world.GetComponent(enemyID, components.AI); exists {
+			ai := aiComp.(*components.AIComponent)
+			ai.HomeX, ai.HomeY = spot.X, spot.Y
+			ai.State = components.AIStateInvestigate
+			ai.LastKnownTargetX, ai.LastKnownTargetY = target.X, target.Y
+		}
+		arrived++
+	}
+
+	if arrived > 0 {
+		GetMessageLog().AddAlert(fmt.Sprintf("Reinforcements arrive for the %s!", strings.ToLower(getEntityName(world, callerID))))
+	}
+}
+
+// reinforcementSpots lists the free tiles out of the player's sight that reinforcements
+// can arrive on: unexplored ones near the caller, or failing that the ones closest to
+// the edge of the map
+func (s *MonsterAbilitySystem) reinforcementSpots(world *ecs.World, gameMap *components.MapComponent, mapID ecs.EntityID, caller Point) []Point {
+	occupied := make(map[Point]bool)
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		if pos, found := entityPoint(world, entity.ID); found {
+			occupied[pos] = true
+		}
+	}
+
+	var near, edge []Point
+	edgeDistance := -1
+	for y := 0; y < gameMap.Height; y++ {
+		for x := 0; x < gameMap.Width; x++ {
+			if gameMap.BlocksMovement(x, y) || gameMap.Visible[y][x] || occupied[Point{x, y}] {
+				continue
+			}
+			if !gameMap.Explored[y][x] && max(abs(x-caller.X), abs(y-caller.Y)) <= ReinforcementRange {
+				near = append(near, Point{x, y})
+			}
+			distance := min(x, y, gameMap.Width-1-x, gameMap.Height-1-y)
+			if edgeDistance < 0 || distance < edgeDistance {
+				edge, edgeDistance = nil, distance
+			}
+			if distance == edgeDistance {
+				edge = append(edge, Point{x, y})
+			}
+		}
+	}
+
+	if len(near) > 0 {
+		return near
+	}
+	return edge
+}
@@ -1,6 +1,13 @@
 package systems
 
 import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
 	"ebiten-rogue/components"
 	"ebiten-rogue/config"
 	"ebiten-rogue/ecs"
@@ -8,15 +15,79 @@ import (
 
 // CameraSystem handles viewport positioning and scrolling
 type CameraSystem struct {
+	smoothFollow bool                           // Glide towards the target instead of snapping
+	followSpeed  float64                        // How quickly the smooth camera closes the gap, per second
+	follow       map[ecs.EntityID]*cameraFollow // Smoothed position of each camera
+	focusID      ecs.EntityID                   // Visible hostile the camera looks at instead of its target, 0 for none
+	keybindings  *Keybindings                   // Key that looks from one hostile to the next
+	initialized  bool
+}
+
+// cameraFollow tracks a smoothed camera position in fractional tiles
+type cameraFollow struct {
+	x, y  float64
+	mapID ecs.EntityID // Map the target was on, a change snaps the camera
 }
 
 // NewCameraSystem creates a new camera system
 func NewCameraSystem() *CameraSystem {
-	return &CameraSystem{}
+	return &CameraSystem{
+		followSpeed: 12.0,
+		follow:      make(map[ecs.EntityID]*cameraFollow),
+		keybindings: NewKeybindings(),
+	}
+}
+
+// SetKeybindings sets the key the camera looks around with
+func (s *CameraSystem) SetKeybindings(keybindings *Keybindings) {
+	s.keybindings = keybindings
+}
+
+// SetSmoothFollow turns smooth camera following on or off. When off the camera snaps
+// to the target every frame.
+func (s *CameraSystem) SetSmoothFollow(smooth bool) {
+	s.smoothFollow = smooth
+}
+
+// Initialize sets up event listeners
+func (s *CameraSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Cameras stop following targets that are removed, and removed cameras are forgotten
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		removedID := event.(ecs.EntityRemovedEvent).EntityID
+		delete(s.follow, removedID)
+		if removedID == s.focusID {
+			s.focusID = 0
+		}
+		for _, cameraEntity := range world.GetEntitiesWithTag("camera") {
+			if cameraComp, exists := world.GetComponent(cameraEntity.ID, components.Camera); exists {
+				camera := cameraComp.(*components.CameraComponent)
+				if ecs.EntityID(camera.Target) == removedID {
+					camera.Target = 0
+				}
+			}
+		}
+	})
+
+	// Any action the player takes, movement included, hands the view back to them
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.focusID = 0
+	})
+
+	s.initialized = true
 }
 
 // Update updates the camera position to follow the target entity
 func (s *CameraSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+
+	s.processFocusInput(world)
+
 	// Find all camera entities
 	cameraEntities := world.GetEntitiesWithTag("camera")
 	if len(cameraEntities) == 0 {
@@ -36,8 +107,14 @@ func (s *CameraSystem) Update(world *ecs.World, dt float64) {
 			continue
 		}
 
+		// Look at the focused hostile if there is one, otherwise the target
+		viewID := ecs.EntityID(camera.Target)
+		if s.focusID != 0 {
+			viewID = s.focusID
+		}
+
 		// Get target position
-		targetPosComp, exists := world.GetComponent(ecs.EntityID(camera.Target), components.Position)
+		targetPosComp, exists := world.GetComponent(viewID, components.Position)
 		if !exists {
 			continue
 		}
@@ -45,8 +122,14 @@ func (s *CameraSystem) Update(world *ecs.World, dt float64) {
 
 		// Update camera position to center the player in the map panel
 		oldX, oldY := camera.X, camera.Y
-		camera.X = targetPos.X - config.GameScreenWidth/2
-		camera.Y = targetPos.Y - config.GameScreenHeight/2
+		if s.smoothFollow {
+			s.followTarget(world, cameraEntity.ID, camera, viewID, targetPos, dt)
+		} else if s.focusID != 0 {
+			camera.X, camera.Y = s.clampedView(world, viewID, targetPos)
+		} else {
+			camera.X = targetPos.X - config.GameScreenWidth/2
+			camera.Y = targetPos.Y - config.GameScreenHeight/2
+		}
 
 		// If the camera position changed, emit an event
 		if oldX != camera.X || oldY != camera.Y {
@@ -62,6 +145,194 @@ func (s *CameraSystem) Update(world *ecs.World, dt float64) {
 	}
 }
 
+// followTarget moves the camera part of the way towards centering the viewed entity,
+// clamped to the map bounds. The camera snaps instead on the first frame and after the
+// entity changes maps.
+func (s *CameraSystem) followTarget(world *ecs.World, cameraID ecs.EntityID, camera *components.CameraComponent, viewID ecs.EntityID, targetPos *components.PositionComponent, dt float64) {
+	mapID := getEntityMapID(world, viewID)
+	idealX, idealY := s.clampedView(world, viewID, targetPos)
+
+	follow, exists := s.follow[cameraID]
+	if !exists || follow.mapID != mapID {
+		s.follow[cameraID] = &cameraFollow{x: float64(idealX), y: float64(idealY), mapID: mapID}
+		camera.X, camera.Y = idealX, idealY
+		return
+	}
+
+	// Exponential easing keeps the glide the same speed regardless of frame rate
+	t := 1 - math.Exp(-s.followSpeed*dt)
+	follow.x += (float64(idealX) - follow.x) * t
+	follow.y += (float64(idealY) - follow.y) * t
+
+	// Finish the glide once the camera is within a fraction of a tile
+	if math.Abs(float64(idealX)-follow.x) < 0.05 {
+		follow.x = float64(idealX)
+	}
+	if math.Abs(float64(idealY)-follow.y) < 0.05 {
+		follow.y = float64(idealY)
+	}
+
+	camera.X = int(math.Round(follow.x))
+	camera.Y = int(math.Round(follow.y))
+}
+
+// clampedView returns the camera position that centers an entity in the map panel,
+// kept inside the bounds of its map
+func (s *CameraSystem) clampedView(world *ecs.World, entityID ecs.EntityID, pos *components.PositionComponent) (int, int) {
+	x, y := pos.X-config.GameScreenWidth/2, pos.Y-config.GameScreenHeight/2
+	if mapComp, exists := world.GetComponent(getEntityMapID(world, entityID), components.MapComponentID); exists {
+		mapData := mapComp.(*components.MapComponent)
+		x = clampCamera(x, mapData.Width-config.GameScreenWidth)
+		y = clampCamera(y, mapData.Height-config.GameScreenHeight)
+	}
+	return x, y
+}
+
+// processFocusInput handles the keys that look around at visible hostiles. V centers
+// the camera on the nearest one and then each next one, and after the last hands the
+// view back to the player, as does Escape. None of it takes a turn.
+func (s *CameraSystem) processFocusInput(world *ecs.World) {
+	// Don't look around while the inventory is open
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok && renderSys.IsInventoryOpen() {
+			return
+		}
+	}
+
+	if s.keybindings.JustPressed(ActionLookAround) {
+		s.cycleFocus(world)
+	} else if s.focusID != 0 && KeyJustPressed(ebiten.KeyEscape) {
+		s.focusID = 0
+		GetMessageLog().AddSystem("View back on you.")
+	}
+
+	if s.focusID == 0 {
+		return
+	}
+
+	// A hostile that slips out of sight can't be watched any more
+	if !s.isFocusable(world, s.focusID) {
+		s.focusID = 0
+		GetMessageLog().AddSystem("Lost sight of it. View back on you.")
+		return
+	}
+
+	if posComp, exists := world.GetComponent(s.focusID, components.Position); exists {
+		pos := posComp.(*components.PositionComponent)
+		for _, system := range world.GetSystems() {
+			if renderSys, ok := system.(*RenderSystem); ok {
+				renderSys.HighlightTile(pos.X, pos.Y, color.RGBA{150, 110, 30, 255})
+				break
+			}
+		}
+	}
+}
+
+// cycleFocus moves the camera on to the next visible hostile, nearest first, and back
+// to the player after the last one
+func (s *CameraSystem) cycleFocus(world *ecs.World) {
+	hostiles := s.visibleHostiles(world)
+	if len(hostiles) == 0 {
+		s.focusID = 0
+		GetMessageLog().AddSystem("No enemies in sight.")
+		return
+	}
+
+	next := 0
+	if s.focusID != 0 {
+		next = len(hostiles)
+		for i, hostileID := range hostiles {
+			if hostileID == s.focusID {
+				next = i + 1
+				break
+			}
+		}
+	}
+
+	if next >= len(hostiles) {
+		s.focusID = 0
+		GetMessageLog().AddSystem("View back on you.")
+		return
+	}
+
+	s.focusID = hostiles[next]
+	GetMessageLog().AddSystem(fmt.Sprintf("Looking at %s (%d of %d). V: next, Esc: back to you.",
+		getEntityName(world, s.focusID), next+1, len(hostiles)))
+}
+
+// isFocusable reports whether an entity is still one of the visible hostiles
+func (s *CameraSystem) isFocusable(world *ecs.World, entityID ecs.EntityID) bool {
+	for _, hostileID := range s.visibleHostiles(world) {
+		if hostileID == entityID {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleHostiles returns the hostiles on the player's map standing in the player's
+// field of view, nearest first
+func (s *CameraSystem) visibleHostiles(world *ecs.World) []ecs.EntityID {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return nil
+	}
+	playerID := playerEntities[0].ID
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return nil
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	mapID := getEntityMapID(world, playerID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	mapComponent := mapComp.(*components.MapComponent)
+
+	var hostiles []ecs.EntityID
+	distances := make(map[ecs.EntityID]int)
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		enemyPosComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		enemyPos := enemyPosComp.(*components.PositionComponent)
+		if enemyPos.X < 0 || enemyPos.X >= mapComponent.Width || enemyPos.Y < 0 || enemyPos.Y >= mapComponent.Height ||
+			!mapComponent.Visible[enemyPos.Y][enemyPos.X] {
+			continue
+		}
+
+		hostiles = append(hostiles, entity.ID)
+		dx, dy := enemyPos.X-pos.X, enemyPos.Y-pos.Y
+		distances[entity.ID] = dx*dx + dy*dy
+	}
+
+	// Nearest first, with the entity ID as a tie-breaker so the order is stable
+	sort.Slice(hostiles, func(i, j int) bool {
+		if distances[hostiles[i]] != distances[hostiles[j]] {
+			return distances[hostiles[i]] < distances[hostiles[j]]
+		}
+		return hostiles[i] < hostiles[j]
+	})
+	return hostiles
+}
+
+// clampCamera keeps a camera coordinate between 0 and max
+func clampCamera(value, max int) int {
+	if value > max {
+		value = max
+	}
+	if value < 0 {
+		value = 0
+	}
+	return value
+}
+
 // updateCameraForStandardMap centers the camera on the player with boundary constraints
 func (s *CameraSystem) updateCameraForStandardMap(world *ecs.World, playerPos *components.PositionComponent, camera *components.CameraComponent, mapID ecs.EntityID) {
 	mapComp, hasMap := world.GetComponent(mapID, components.MapComponentID)
@@ -0,0 +1,232 @@
+package systems
+
+import (
+	"fmt"
+	"strings"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// CampSystem lets the player rest at camps. Bumping a camp with no hostile in view
+// settles in: turns pass one after another, healing the player a little each turn so
+// they are at full health when the rest is over. No reinforcements reach the floor
+// while the player is camped, nor for the camp's quiet spell after. A monster that
+// wanders into view anyway, getting hurt or pressing any key breaks camp early. A
+// full rest saves the game when a save handler is set.
+type CampSystem struct {
+	campID      ecs.EntityID // Camp the player is resting at, 0 when not camped
+	mapID       ecs.EntityID // Floor of that camp
+	turnsLeft   int          // Turns of rest still to go
+	justStarted bool         // The key that bumped the camp is still down this tick
+	lastHealth  int          // Player health after the last turn of rest
+	stepTimer   float64      // Time until the next turn of rest
+	stepDelay   float64      // Delay between turns of rest
+	quietMapID  ecs.EntityID // Floor reinforcements stay away from after breaking camp
+	quietTurns  int          // Turns they still stay away
+	save        func() error // Saves the game after a full rest, nil for no autosave
+	initialized bool
+}
+
+// NewCampSystem creates a new camp system
+func NewCampSystem() *CampSystem {
+	return &CampSystem{
+		stepDelay: 0.02,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *CampSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Bumping into a camp settles in to rest
+	world.GetEventManager().Subscribe(EventCollision, func(event ecs.Event) {
+		collision := event.(CollisionEvent)
+		if !isPlayer(world, collision.EntityID1) {
+			return
+		}
+		if world.HasComponent(collision.EntityID2, components.Camp) {
+			s.start(world, collision.EntityID1, collision.EntityID2)
+		}
+	})
+
+	// The quiet spell runs down once the player is up again
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		if s.campID == 0 && s.quietTurns > 0 {
+			s.quietTurns--
+		}
+	})
+
+	// A camp that's gone can't be rested at
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		if event.(ecs.EntityRemovedEvent).EntityID == s.campID {
+			s.Stop("")
+		}
+	})
+
+	s.initialized = true
+}
+
+// SetSaveHandler sets how the game is saved when the player finishes a rest; nil
+// turns autosaving off
+func (s *CampSystem) SetSaveHandler(save func() error) {
+	s.save = save
+}
+
+// IsCamping returns whether the player is resting at a camp
+func (s *CampSystem) IsCamping() bool {
+	return s.campID != 0
+}
+
+// SuppressesSpawns returns whether reinforcements are kept from arriving on a map,
+// because the player is camped there or only just broke camp
+func (s *CampSystem) SuppressesSpawns(mapID ecs.EntityID) bool {
+	if s.campID != 0 {
+		return s.mapID == mapID
+	}
+	return s.quietTurns > 0 && s.quietMapID == mapID
+}
+
+// Stop breaks camp, starting the quiet spell of the camp that was left
+func (s *CampSystem) Stop(reason string) {
+	if s.campID == 0 {
+		return
+	}
+	s.campID = 0
+	s.turnsLeft = 0
+	if reason != "" {
+		GetMessageLog().Add(reason)
+	}
+}
+
+// Reset breaks camp and ends any quiet spell, for a new game
+func (s *CampSystem) Reset() {
+	s.Stop("")
+	s.quietMapID = 0
+	s.quietTurns = 0
+}
+
+// Update rests one turn at a time while the player is camped
+func (s *CampSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+	if s.campID == 0 {
+		return
+	}
+
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		s.Stop("")
+		return
+	}
+	playerID := playerEntities[0].ID
+	statsComp, exists := world.GetComponent(playerID, components.Stats)
+	if !exists || getEntityMapID(world, playerID) != s.mapID {
+		s.Stop("")
+		return
+	}
+	stats := statsComp.(*components.StatsComponent)
+
+	// Any key press gets the player up
+	if s.justStarted {
+		s.justStarted = false
+	} else if len(JustPressedKeys()) > 0 {
+		s.Stop("You break camp.")
+		return
+	}
+
+	// The camp only keeps new arrivals away; whatever was already roaming the floor
+	// can still stumble on it
+	if hostileID := visibleHostile(world, s.mapID); hostileID != 0 {
+		s.Stop(fmt.Sprintf("A %s wanders into camp! You leap to your feet.", strings.ToLower(getEntityName(world, hostileID))))
+		return
+	}
+	if stats.Health < s.lastHealth {
+		s.Stop("You are hurt and break camp.")
+		return
+	}
+
+	s.stepTimer -= dt
+	if s.stepTimer > 0 {
+		return
+	}
+	s.stepTimer = s.stepDelay
+
+	// Heal an even share of what's missing so the last turn tops the player up
+	if missing := stats.MaxHealth - stats.Health; missing > 0 {
+		stats.Health += (missing + s.turnsLeft - 1) / s.turnsLeft
+	}
+	s.lastHealth = stats.Health
+	s.turnsLeft--
+
+	world.EmitEvent(RestEvent{EntityID: playerID})
+	world.EmitEvent(TurnCompletedEvent{EntityID: playerID})
+
+	if s.campID != 0 && s.turnsLeft <= 0 {
+		s.Stop("You wake by the embers, fully rested.")
+		s.autosave()
+	}
+}
+
+// autosave saves the game through the save handler, if there is one
+func (s *CampSystem) autosave() {
+	if s.save == nil {
+		return
+	}
+	if err := s.save(); err != nil {
+		GetMessageLog().AddSystem("Autosave failed.")
+		GetDebugLog().Add(fmt.Sprintf("CampSystem: %v", err))
+		return
+	}
+	GetMessageLog().AddSystem("Game saved.")
+}
+
+// start settles the player in at a camp, unless a hostile is in view
+func (s *CampSystem) start(world *ecs.World, playerID, campID ecs.EntityID) {
+	if s.campID != 0 {
+		return
+	}
+	mapID := getEntityMapID(world, campID)
+	if visibleHostile(world, mapID) != 0 {
+		GetMessageLog().Add("You can't rest with enemies in view!")
+		return
+	}
+	campComp, _ := world.GetComponent(campID, components.Camp)
+	camp := campComp.(*components.CampComponent)
+	statsComp, exists := world.GetComponent(playerID, components.Stats)
+	if !exists {
+		return
+	}
+
+	s.campID = campID
+	s.mapID = mapID
+	s.turnsLeft = camp.RestTurns
+	s.justStarted = true
+	s.lastHealth = statsComp.(*components.StatsComponent).Health
+	s.stepTimer = s.stepDelay
+	s.quietMapID = mapID
+	s.quietTurns = camp.QuietTurns
+
+	GetMessageLog().AddEnvironment("You settle in by the camp to rest. Press any key to break camp.")
+}
+
+// visibleHostile returns a hostile on the map that the player can see, or 0 if none
+func visibleHostile(world *ecs.World, mapID ecs.EntityID) ecs.EntityID {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return 0
+	}
+	mapData := mapComp.(*components.MapComponent)
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		if pos, found := entityPoint(world, entity.ID); found && isTileVisible(mapData, pos.X, pos.Y) {
+			return entity.ID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,196 @@
+package systems
+
+import (
+	"math/rand"
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// campFixture is a small open floor with the player standing next to a camp
+type campFixture struct {
+	*testWorld
+	camps  *CampSystem
+	campID ecs.EntityID
+	stats  *components.StatsComponent
+}
+
+func newCampFixture(t *testing.T) *campFixture {
+	t.Helper()
+	w := newTestWorld(t, 20, 20, 5, 5)
+	stats := &components.StatsComponent{Health: 10, MaxHealth: 40}
+	w.world.AddComponent(w.playerID, components.Stats, stats)
+
+	campID := w.place(6, 5)
+	w.world.TagEntity(campID, "camp")
+	w.world.AddComponent(campID, components.Camp, components.NewCampComponent(10, 3))
+
+	camps := NewCampSystem()
+	w.world.AddSystem(camps)
+	camps.Initialize(w.world)
+
+	return &campFixture{testWorld: w, camps: camps, campID: campID, stats: stats}
+}
+
+// bump walks the player into the camp
+func (f *campFixture) bump() {
+	f.world.EmitEvent(CollisionEvent{EntityID1: f.playerID, EntityID2: f.campID, X: 6, Y: 5})
+}
+
+// rest runs the camp system for a number of turns of rest
+func (f *campFixture) rest(turns int) {
+	for i := 0; i < turns; i++ {
+		f.camps.Update(f.world, f.camps.stepDelay)
+	}
+}
+
+// addHostile puts a monster on the map, in the player's view or not
+func (f *campFixture) addHostile(x, y int, visible bool) ecs.EntityID {
+	monsterID := f.place(x, y)
+	f.world.TagEntity(monsterID, "enemy")
+	f.world.AddComponent(monsterID, components.Name, components.NewNameComponent("Rust Zombie"))
+	f.gameMap.Visible[y][x] = visible
+	return monsterID
+}
+
+func TestCampFullRestHeals(t *testing.T) {
+	f := newCampFixture(t)
+	f.bump()
+	if !f.camps.IsCamping() {
+		t.Fatal("bumping the camp should settle in to rest")
+	}
+
+	saves := 0
+	f.camps.SetSaveHandler(func() error {
+		saves++
+		return nil
+	})
+	f.rest(10)
+
+	if f.camps.IsCamping() {
+		t.Error("the rest should be over after the camp's rest turns")
+	}
+	if f.stats.Health != f.stats.MaxHealth {
+		t.Errorf("health after a full rest = %d, want %d", f.stats.Health, f.stats.MaxHealth)
+	}
+	if saves != 1 {
+		t.Errorf("saves after a full rest = %d, want 1", saves)
+	}
+}
+
+func TestCampRefusedWithHostileInView(t *testing.T) {
+	f := newCampFixture(t)
+	f.addHostile(10, 10, true)
+	f.bump()
+	if f.camps.IsCamping() {
+		t.Error("the player shouldn't be able to camp with a hostile in view")
+	}
+}
+
+func TestCampInterrupted(t *testing.T) {
+	tests := []struct {
+		name      string
+		interrupt func(f *campFixture)
+	}{
+		{"hostile wanders into view", func(f *campFixture) { f.addHostile(9, 5, true) }},
+		{"player is hurt", func(f *campFixture) { f.stats.Health -= 3 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCampFixture(t)
+			saves := 0
+			f.camps.SetSaveHandler(func() error {
+				saves++
+				return nil
+			})
+			f.bump()
+			f.rest(3)
+			if !f.camps.IsCamping() {
+				t.Fatal("the rest should still be under way")
+			}
+			healthBefore := f.stats.Health
+
+			tt.interrupt(f)
+			f.rest(1)
+			if f.camps.IsCamping() {
+				t.Error("the rest should have been interrupted")
+			}
+			if f.stats.Health > healthBefore {
+				t.Error("an interrupted turn of rest shouldn't heal")
+			}
+
+			f.rest(10)
+			if saves != 0 {
+				t.Errorf("saves after an interrupted rest = %d, want 0", saves)
+			}
+		})
+	}
+}
+
+func TestCampHostileOutOfViewDoesNotInterrupt(t *testing.T) {
+	f := newCampFixture(t)
+	f.bump()
+	f.addHostile(15, 15, false)
+	f.rest(1)
+	if !f.camps.IsCamping() {
+		t.Error("a hostile out of view shouldn't break camp")
+	}
+}
+
+func TestCampSuppressesSpawns(t *testing.T) {
+	f := newCampFixture(t)
+	otherMapID := f.world.CreateEntity().ID
+
+	if f.camps.SuppressesSpawns(f.mapID) {
+		t.Fatal("spawns shouldn't be suppressed before camping")
+	}
+	f.bump()
+	if !f.camps.SuppressesSpawns(f.mapID) {
+		t.Error("spawns should be suppressed on the camp's floor while camped")
+	}
+	if f.camps.SuppressesSpawns(otherMapID) {
+		t.Error("spawns on other floors shouldn't be suppressed")
+	}
+
+	// The quiet spell only starts running down once camp is broken
+	f.world.EmitEvent(TurnCompletedEvent{EntityID: f.playerID})
+	f.camps.Stop("")
+	for turn := 0; turn < 3; turn++ {
+		if !f.camps.SuppressesSpawns(f.mapID) {
+			t.Fatalf("spawns should still be suppressed %d turns after breaking camp", turn)
+		}
+		f.world.EmitEvent(TurnCompletedEvent{EntityID: f.playerID})
+	}
+	if f.camps.SuppressesSpawns(f.mapID) {
+		t.Error("spawns should resume once the quiet spell is over")
+	}
+}
+
+func TestCampHoldsOffReinforcements(t *testing.T) {
+	for _, camped := range []bool{false, true} {
+		f := newCampFixture(t)
+		callerID := f.addHostile(18, 18, false)
+
+		abilities := NewMonsterAbilitySystem()
+		abilities.SetRNG(rand.New(rand.NewSource(1)))
+		arrived := 0
+		abilities.SetEnemyCreator(func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error) {
+			arrived++
+			return f.world.CreateEntity().ID, nil
+		})
+
+		if camped {
+			f.bump()
+		}
+		abilities.bringReinforcements(f.world, callerID, &pendingCall{mapID: f.mapID, summons: []string{"gremlin"}, count: 2})
+
+		if camped && arrived != 0 {
+			t.Errorf("%d reinforcements arrived while the player was camped", arrived)
+		}
+		if !camped && arrived != 2 {
+			t.Errorf("reinforcements without a camp = %d, want 2", arrived)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package systems
+
+import (
+	"fmt"
+	"math"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// chainArcSeconds is how long the path of a chain of lightning stays lit
+const chainArcSeconds = 0.4
+
+// castChain strikes a target with a chain scroll's effects, then jumps to the nearest
+// monster not yet struck within range of the last one, up to the scroll's jump count.
+// Jumps pass through walls, and each one is weaker than the last by the scroll's
+// falloff.
+func (s *InventorySystem) castChain(world *ecs.World, readerID, itemID, targetID ecs.EntityID, scroll *components.ScrollComponent) {
+	effectsSystem := s.getEffectsSystem(world)
+	if effectsSystem == nil {
+		return
+	}
+	itemComp, exists := world.GetComponent(itemID, components.Item)
+	if !exists {
+		return
+	}
+	effects, ok := itemComp.(*components.ItemComponent).Data.([]components.GameEffect)
+	if !ok {
+		return
+	}
+	mapID := getEntityMapID(world, targetID)
+
+	// Pick the whole chain before striking, so monsters killed along the way can
+	// still be jumped from
+	chain := []ecs.EntityID{targetID}
+	struck := map[ecs.EntityID]bool{readerID: true, targetID: true}
+	from, ok := entityPoint(world, targetID)
+	if !ok {
+		return
+	}
+	points := []Point{from}
+	for jump := 0; jump < scroll.Jumps; jump++ {
+		nextID, next, found := nearestChainTarget(world, mapID, from, scroll.JumpRange, struck)
+		if !found {
+			break
+		}
+		chain = append(chain, nextID)
+		points = append(points, next)
+		struck[nextID] = true
+		from = next
+	}
+
+	var arcs []Point
+	if readerPos, ok := entityPoint(world, readerID); ok {
+		arcs = append(arcs, LinePoints(readerPos.X, readerPos.Y, points[0].X, points[0].Y)...)
+	}
+	for i := 1; i < len(points); i++ {
+		arcs = append(arcs, LinePoints(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y)...)
+	}
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok {
+			renderSys.FlashTiles(arcs, renderSys.Palette().LightningArc, chainArcSeconds)
+			break
+		}
+	}
+
+	strength := 1.0
+	for i, victimID := range chain {
+		if i > 0 {
+			GetMessageLog().AddCombat(fmt.Sprintf("The lightning leaps to %s!", getEntityName(world, victimID)))
+		}
+		s.strikeWithEffects(world, effectsSystem, readerID, victimID, weakenedEffects(effectsSystem, effects, strength))
+		strength *= 1 - scroll.Falloff
+	}
+}
+
+// nearestChainTarget finds the closest monster on a map within range of a tile that
+// hasn't been struck yet. Walls don't matter, the lightning jumps through them.
+func nearestChainTarget(world *ecs.World, mapID ecs.EntityID, from Point, jumpRange int, struck map[ecs.EntityID]bool) (ecs.EntityID, Point, bool) {
+	var bestID ecs.EntityID
+	var best Point
+	bestDistance := math.Inf(1)
+	world.Query(components.AI, components.Stats, components.Position).Each(func(entity *ecs.Entity) {
+		if struck[entity.ID] || getEntityMapID(world, entity.ID) != mapID {
+			return
+		}
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		distance := math.Hypot(float64(pos.X-from.X), float64(pos.Y-from.Y))
+		if distance > float64(jumpRange)+0.5 {
+			return
+		}
+		// Ties go to the lower ID so the chain doesn't depend on map iteration order
+		if distance < bestDistance || (distance == bestDistance && entity.ID < bestID) {
+			bestID, best, bestDistance = entity.ID, Point{X: pos.X, Y: pos.Y}, distance
+		}
+	})
+	return bestID, best, bestID != 0
+}
+
+// weakenedEffects copies effects with their values scaled by a strength from 0 to 1.
+// Dice are rolled first, and nothing drops below 1 so every jump still lands.
+func weakenedEffects(effectsSystem *EffectsSystem, effects []components.GameEffect, strength float64) []components.GameEffect {
+	if strength >= 1 {
+		return effects
+	}
+	weakened := make([]components.GameEffect, len(effects))
+	for i, effect := range effects {
+		weakened[i] = effect
+		value := effectsSystem.calculateEffectValue(effect.Value)
+		weakened[i].Value = math.Max(1, math.Round(value*strength))
+	}
+	return weakened
+}
+
+// entityPoint returns the tile an entity stands on
+func entityPoint(world *ecs.World, entityID ecs.EntityID) (Point, bool) {
+	posComp, exists := world.GetComponent(entityID, components.Position)
+	if !exists {
+		return Point{}, false
+	}
+	pos := posComp.(*components.PositionComponent)
+	return Point{X: pos.X, Y: pos.Y}, true
+}
+
+// inSight reports whether the reader has a clear line to a target on the same map
+func (s *InventorySystem) inSight(world *ecs.World, readerID, targetID ecs.EntityID) bool {
+	mapID := getEntityMapID(world, readerID)
+	if mapID == 0 || getEntityMapID(world, targetID) != mapID {
+		return false
+	}
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return false
+	}
+	from, ok := entityPoint(world, readerID)
+	to, ok2 := entityPoint(world, targetID)
+	if !ok || !ok2 {
+		return false
+	}
+	return HasLineOfSight(mapComp.(*components.MapComponent), from.X, from.Y, to.X, to.Y)
+}
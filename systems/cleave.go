@@ -0,0 +1,83 @@
+package systems
+
+import (
+	"fmt"
+	"sort"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// cleaveTargets returns the monsters beside the defender that a swing of the
+// attacker's weapon sweeps through, in the order the arc reaches them. Weapons
+// without cleave strike their target alone.
+func (s *CombatSystem) cleaveTargets(world *ecs.World, attackerID, defenderID ecs.EntityID) []ecs.EntityID {
+	statsComp, exists := world.GetComponent(attackerID, components.Stats)
+	if !exists || statsComp.(*components.StatsComponent).Cleave <= 0 {
+		return nil
+	}
+	width := statsComp.(*components.StatsComponent).Cleave
+
+	attackerPosComp, exists := world.GetComponent(attackerID, components.Position)
+	if !exists {
+		return nil
+	}
+	defenderPosComp, exists := world.GetComponent(defenderID, components.Position)
+	if !exists {
+		return nil
+	}
+	attackerPos := attackerPosComp.(*components.PositionComponent)
+	defenderPos := defenderPosComp.(*components.PositionComponent)
+	dx, dy := defenderPos.X-attackerPos.X, defenderPos.Y-attackerPos.Y
+	if max(dx, -dx) > 1 || max(dy, -dy) > 1 {
+		return nil
+	}
+
+	order := make(map[Point]int)
+	for i, cell := range ArcCells(attackerPos.X, attackerPos.Y, dx, dy, width) {
+		order[cell] = i
+	}
+
+	mapID := getEntityMapID(world, attackerID)
+	var targets []ecs.EntityID
+	for _, entity := range world.GetEntitiesWithComponent(components.AI) {
+		if entity.ID == defenderID || !world.HasComponent(entity.ID, components.Stats) {
+			continue
+		}
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		posComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		pos := posComp.(*components.PositionComponent)
+		if _, inArc := order[Point{pos.X, pos.Y}]; inArc {
+			targets = append(targets, entity.ID)
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		pi, _ := world.GetComponent(targets[i], components.Position)
+		pj, _ := world.GetComponent(targets[j], components.Position)
+		a, b := pi.(*components.PositionComponent), pj.(*components.PositionComponent)
+		return order[Point{a.X, a.Y}] < order[Point{b.X, b.Y}]
+	})
+	return targets
+}
+
+// cleave carries a swing on into the other monsters in its arc. Each one is
+// attacked in turn and rolls to dodge and take damage on its own.
+func (s *CombatSystem) cleave(world *ecs.World, attackerID ecs.EntityID, targets []ecs.EntityID) {
+	if len(targets) == 0 {
+		return
+	}
+	GetMessageLog().AddCombat(fmt.Sprintf("%s's swing sweeps on!", getEntityName(world, attackerID)))
+	for _, targetID := range targets {
+		// An earlier blow may have set off something that took this one with it
+		if world.GetEntity(targetID) == nil {
+			continue
+		}
+		s.ProcessCombat(world, attackerID, targetID)
+	}
+}
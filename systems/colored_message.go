@@ -22,6 +22,24 @@ const (
 	MessageTypeSystem
 )
 
+// String returns the category name of the message type, as written to log files
+func (t MessageType) String() string {
+	switch t {
+	case MessageTypeEnvironment:
+		return "environment"
+	case MessageTypeCombat:
+		return "combat"
+	case MessageTypeItem:
+		return "item"
+	case MessageTypeAlert:
+		return "alert"
+	case MessageTypeSystem:
+		return "system"
+	default:
+		return "normal"
+	}
+}
+
 // ColoredMessage stores a message with its associated color
 type ColoredMessage struct {
 	Text string
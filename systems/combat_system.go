@@ -3,21 +3,80 @@ package systems
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"ebiten-rogue/components"
+	"ebiten-rogue/data"
 	"ebiten-rogue/ecs"
 )
 
+// Hit chance tuning for the accuracy/evasion model
+const (
+	BaseHitChance     = 0.85 // Chance to hit when accuracy and evasion are equal
+	HitChancePerPoint = 0.05 // Change in hit chance per point of accuracy over evasion
+	MinHitChance      = 0.10 // High-evasion targets can always be hit sometimes
+	MaxHitChance      = 0.95 // Nothing is a guaranteed hit
+)
+
+// CriticalMultiplier scales the damage of an attack roll of a natural 20
+const CriticalMultiplier = 2
+
+// AttackResult is the outcome of one attack's rolls, before it is applied
+type AttackResult struct {
+	Hit    bool // Whether the attack got past the defender's evasion
+	Roll   int  // The d20 rolled, 0 for a miss
+	Total  int  // The roll plus the attacker's attack
+	Bonus  int  // The extra die a two-handed weapon rolls, 0 for none
+	Damage int  // Damage dealt after defense and any critical, never below 0
+	Crit   bool // Whether the roll was a natural 20
+}
+
+// Limits on monsters that split when hit
+const (
+	SplitMinHealth       = 4 // Monsters with less health than this are too small to split
+	MaxSplitsPerOriginal = 6 // Copies one spawned monster and all its offspring can make
+)
+
 // CombatSystem handles combat interactions between entities
 type CombatSystem struct {
-	initialized bool
+	templateManager *data.EntityTemplateManager
+	rng             *rand.Rand // Source of hit and critical rolls
+	initialized     bool
+
+	// Splitting monsters
+	createEnemy func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error)
+	splitRoots  map[ecs.EntityID]ecs.EntityID // Each split copy's original monster
+	splitCounts map[ecs.EntityID]int          // Copies made so far, by original monster
+
+	// Loot dropped by broken props
+	createItem func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error)
 }
 
 // NewCombatSystem creates a new combat system
 func NewCombatSystem() *CombatSystem {
-	return &CombatSystem{}
+	return &CombatSystem{
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		splitRoots:  make(map[ecs.EntityID]ecs.EntityID),
+		splitCounts: make(map[ecs.EntityID]int),
+	}
+}
+
+// SetRNG sets the random source hit and critical rolls are made with
+func (s *CombatSystem) SetRNG(rng *rand.Rand) {
+	s.rng = rng
+}
+
+// SetTemplateManager sets the template manager used to look up kill XP
+func (s *CombatSystem) SetTemplateManager(templateManager *data.EntityTemplateManager) {
+	s.templateManager = templateManager
+}
+
+// SetEnemyCreator sets the function used to spawn the copies of monsters that split
+func (s *CombatSystem) SetEnemyCreator(createEnemy func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error)) {
+	s.createEnemy = createEnemy
 }
 
 // Initialize sets up event listeners
@@ -38,9 +97,22 @@ func (s *CombatSystem) Initialize(world *ecs.World) {
 		s.handleEnemyAttack(world, attackEvent)
 	})
 
+	// Removed copies no longer need their lineage; originals keep their split count
+	// so the cap still holds for the copies they leave behind
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		delete(s.splitRoots, event.(ecs.EntityRemovedEvent).EntityID)
+	})
+
 	s.initialized = true
 }
 
+// Reset forgets which monsters split from which, for a new game whose entity IDs
+// start over
+func (s *CombatSystem) Reset() {
+	s.splitRoots = make(map[ecs.EntityID]ecs.EntityID)
+	s.splitCounts = make(map[ecs.EntityID]int)
+}
+
 // handleCollision processes a collision event
 func (s *CombatSystem) handleCollision(world *ecs.World, event CollisionEvent) {
 	// Check if this collision should trigger combat
@@ -70,8 +142,19 @@ func (s *CombatSystem) handleCollision(world *ecs.World, event CollisionEvent) {
 			defenderID = entityID1
 		}
 
-		// Process combat
+		// Props are smashed rather than fought
+		if world.HasComponent(defenderID, components.Destructible) {
+			s.attackDestructible(world, attackerID, defenderID)
+			return
+		}
+
+		// A cleaving weapon's arc is found before the first blow can kill its target
+		cleaved := s.cleaveTargets(world, attackerID, defenderID)
+
+		// Process combat, with a second blow from a dual wielder's off hand
 		s.ProcessCombat(world, attackerID, defenderID)
+		s.offhandStrike(world, attackerID, defenderID)
+		s.cleave(world, attackerID, cleaved)
 	}
 }
 
@@ -132,16 +215,23 @@ func (s *CombatSystem) ProcessCombat(world *ecs.World, attackerID, defenderID ec
 	attackerName := getEntityName(world, attackerID)
 	defenderName := getEntityName(world, defenderID)
 
-	// Roll d20 and add attacker's attack bonus
-	d20Roll := rand.Intn(20) + 1 // 1-20
-	attackRoll := d20Roll + attackerStats.Attack
+	result := s.resolveAttack(attackerStats, defenderStats, getWeaponGrip(world, attackerID))
+	if !result.Hit {
+		GetMessageLog().AddCombat(fmt.Sprintf("%s dodges %s's attack!", defenderName, attackerName))
+		return false
+	}
+	damage := result.Damage
 
-	// Calculate damage (attack roll minus defender's defense)
-	damage := attackRoll - defenderStats.Defense
 	// Log the attack roll
 	rollMsg := fmt.Sprintf("%s attacks %s! (Roll: %d + %d = %d)",
-		attackerName, defenderName, d20Roll, attackerStats.Attack, attackRoll)
+		attackerName, defenderName, result.Roll, result.Total-result.Roll, result.Total)
+	if result.Bonus > 0 {
+		rollMsg += fmt.Sprintf(" +%d two-handed", result.Bonus)
+	}
 	GetMessageLog().AddCombat(rollMsg)
+	if result.Crit && damage > 0 {
+		GetMessageLog().AddCombat("Critical hit!")
+	}
 
 	// Handle the outcome
 	if damage <= 0 {
@@ -154,6 +244,7 @@ func (s *CombatSystem) ProcessCombat(world *ecs.World, attackerID, defenderID ec
 		damageMsg := fmt.Sprintf("%s hit %s for %d damage! %s has %d/%d HP remaining.",
 			attackerName, defenderName, damage, defenderName, defenderStats.Health, defenderStats.MaxHealth)
 		GetMessageLog().AddCombat(damageMsg)
+		s.applyBonusDamage(world, attackerStats, defenderID, defenderStats)
 
 		// Emit combat attack event for ability system
 		world.GetEventManager().Emit(CombatAttackEvent{
@@ -161,30 +252,249 @@ func (s *CombatSystem) ProcessCombat(world *ecs.World, attackerID, defenderID ec
 			DefenderID: defenderID,
 		})
 
+		// Monsters that survive the hit may react to it
+		if defenderStats.Health > 0 {
+			s.triggerOnHit(world, defenderID, defenderName, defenderStats)
+		}
+
 		// Check if defender is defeated
 		if defenderStats.Health <= 0 {
-			GetMessageLog().AddAlert(fmt.Sprintf("%s was defeated!", defenderName))
-
-			// Emit death event before handling the entity
-			world.GetEventManager().Emit(DeathEvent{
-				EntityID: defenderID,
-				KillerID: attackerID,
-			})
-
-			// Handle player death
-			if isPlayer(world, defenderID) {
-				GetMessageLog().AddAlert("Game Over! You died.")
-				// Could trigger game over state here
-			} else {
-				// Remove the defeated entity
-				world.RemoveEntity(defenderID)
-			}
+			s.killEntity(world, attackerID, defenderID)
 		}
 
 		return true
 	}
 }
 
+// resolveAttack makes the rolls of one attack without touching the world: whether it
+// hits, then a d20 plus the attacker's attack, less the defender's defense. Dual
+// wielding takes DualWieldPenalty off the attack, and a two-handed weapon adds a
+// TwoHandedDamageDie to the damage. A natural 20 is a critical that multiplies the
+// damage by CriticalMultiplier. With a seeded source the same stats and grip always
+// give the same result.
+func (s *CombatSystem) resolveAttack(attacker, defender *components.StatsComponent, grip weaponGrip) AttackResult {
+	// Check whether the defender dodges before any damage is rolled
+	if s.rng.Float64() >= HitChance(attacker, defender) {
+		return AttackResult{}
+	}
+
+	attack := attacker.Attack
+	if grip.DualWield {
+		attack -= DualWieldPenalty
+	}
+
+	roll := s.rng.Intn(20) + 1 // 1-20
+	result := AttackResult{
+		Hit:   true,
+		Roll:  roll,
+		Total: roll + attack,
+		Crit:  roll == 20,
+	}
+	if grip.TwoHanded {
+		result.Bonus = s.rng.Intn(TwoHandedDamageDie) + 1
+	}
+	result.Damage = max(0, result.Total+result.Bonus-defender.Defense)
+	if result.Crit {
+		result.Damage *= CriticalMultiplier
+	}
+	return result
+}
+
+// triggerOnHit runs the defender's on_hit abilities after it survives a hit
+func (s *CombatSystem) triggerOnHit(world *ecs.World, defenderID ecs.EntityID, defenderName string, defenderStats *components.StatsComponent) {
+	abilityComp, exists := world.GetComponent(defenderID, components.MonsterAbility)
+	if !exists {
+		return
+	}
+
+	for _, ability := range abilityComp.(*components.MonsterAbilityComponent).Abilities {
+		if ability.Trigger != components.TriggerOnHit {
+			continue
+		}
+		if ability.Chance > 0 && s.rng.Float64() >= ability.Chance {
+			continue
+		}
+		if ability.Action == components.AbilityActionSplit {
+			s.split(world, defenderID, defenderName, defenderStats)
+		}
+	}
+}
+
+// split buds a copy of a monster off onto a free adjacent tile, sharing the monster's
+// remaining health between the two. Copies count against the monster they all came from,
+// so a single slime can only ever become so many.
+func (s *CombatSystem) split(world *ecs.World, entityID ecs.EntityID, name string, stats *components.StatsComponent) {
+	if s.createEnemy == nil || stats.Health < SplitMinHealth {
+		return
+	}
+
+	root, isCopy := s.splitRoots[entityID]
+	if !isCopy {
+		root = entityID
+	}
+	if s.splitCounts[root] >= MaxSplitsPerOriginal {
+		return
+	}
+
+	aiComp, hasAI := world.GetComponent(entityID, components.AI)
+	posComp, hasPos := world.GetComponent(entityID, components.Position)
+	mapID := getEntityMapID(world, entityID)
+	if !hasAI || !hasPos || mapID == 0 {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	x, y, found := s.findFreeAdjacentTile(world, mapID, pos.X, pos.Y)
+	if !found {
+		return
+	}
+
+	copyID, err := s.createEnemy(mapID, x, y, aiComp.(*components.AIComponent).TemplateID)
+	if err != nil {
+		GetMessageLog().AddSystem(fmt.Sprintf("Failed to split %s: %v", name, err))
+		return
+	}
+
+	// The copy takes half of what the original had left
+	copyHealth := stats.Health / 2
+	stats.Health -= copyHealth
+	if copyStatsComp, exists := world.GetComponent(copyID, components.Stats); exists {
+		copyStats := copyStatsComp.(*components.StatsComponent)
+		copyStats.Health = copyHealth
+		copyStats.MaxHealth = copyHealth
+	}
+
+	s.splitRoots[copyID] = root
+	s.splitCounts[root]++
+	GetMessageLog().AddCombat(fmt.Sprintf("The %s splits!", strings.ToLower(name)))
+}
+
+// findFreeAdjacentTile picks a random tile next to x, y that isn't a wall and isn't
+// taken by a blocking entity on the same map
+func (s *CombatSystem) findFreeAdjacentTile(world *ecs.World, mapID ecs.EntityID, x, y int) (int, int, bool) {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return 0, 0, false
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	occupied := make(map[Point]bool)
+	for _, entity := range world.GetEntitiesWithComponent(components.Position) {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		collComp, hasCol := world.GetComponent(entity.ID, components.Collision)
+		if !hasCol || !collComp.(*components.CollisionComponent).Blocks {
+			continue
+		}
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		occupied[Point{X: pos.X, Y: pos.Y}] = true
+	}
+
+	offsets := [][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+	for _, i := range s.rng.Perm(len(offsets)) {
+		nx, ny := x+offsets[i][0], y+offsets[i][1]
+		if mapData.BlocksMovement(nx, ny) || occupied[Point{X: nx, Y: ny}] {
+			continue
+		}
+		return nx, ny, true
+	}
+	return 0, 0, false
+}
+
+// applyBonusDamage deals the attacker's extra damage of each school, such as fire from a
+// socketed gem, scaled by the defender's resistances
+func (s *CombatSystem) applyBonusDamage(world *ecs.World, attackerStats *components.StatsComponent, defenderID ecs.EntityID, defenderStats *components.StatsComponent) {
+	schools := make([]string, 0, len(attackerStats.BonusDamage))
+	for school := range attackerStats.BonusDamage {
+		schools = append(schools, school)
+	}
+	sort.Strings(schools)
+
+	defenderName := getEntityName(world, defenderID)
+	for _, school := range schools {
+		multiplier := resistanceMultiplier(world, defenderID, school)
+		if multiplier <= 0 {
+			GetMessageLog().AddCombat(fmt.Sprintf("%s is immune to %s.", defenderName, school))
+			continue
+		}
+		bonus := max(1, int(float64(attackerStats.BonusDamage[school])*multiplier))
+		defenderStats.Health -= bonus
+		GetMessageLog().AddCombat(fmt.Sprintf("The %s deals %d extra damage to %s! %s has %d/%d HP remaining.",
+			school, bonus, defenderName, defenderName, defenderStats.Health, defenderStats.MaxHealth))
+	}
+}
+
+// killEntity announces a defeated entity, gives its killer the XP and emits its death.
+// Every kill, whether by a blow, a blast or a scroll, goes through here.
+func (s *CombatSystem) killEntity(world *ecs.World, killerID, victimID ecs.EntityID) {
+	GetMessageLog().AddAlert(fmt.Sprintf("%s was defeated!", getEntityName(world, victimID)))
+	s.AwardKillXP(world, killerID, victimID)
+
+	// Emit death event before handling the entity
+	world.GetEventManager().Emit(DeathEvent{
+		EntityID: victimID,
+		KillerID: killerID,
+	})
+
+	// The death system decides what becomes of the player
+	if !isPlayer(world, victimID) {
+		world.RemoveEntity(victimID)
+	}
+}
+
+// AwardKillXP gives the player the XP of the monster they killed. The XP comes from the
+// monster's template, falling back to its stats for monsters not made from one.
+func (s *CombatSystem) AwardKillXP(world *ecs.World, killerID, victimID ecs.EntityID) int {
+	if !isPlayer(world, killerID) || isPlayer(world, victimID) {
+		return 0
+	}
+	killerStatsComp, exists := world.GetComponent(killerID, components.Stats)
+	if !exists {
+		return 0
+	}
+
+	xp := 0
+	if victimStatsComp, exists := world.GetComponent(victimID, components.Stats); exists {
+		xp = victimStatsComp.(*components.StatsComponent).Exp
+	}
+	if aiComp, exists := world.GetComponent(victimID, components.AI); exists && s.templateManager != nil {
+		if template, ok := s.templateManager.GetTemplate(aiComp.(*components.AIComponent).TemplateID); ok {
+			xp = template.XP
+		}
+	}
+	if xp <= 0 {
+		return 0
+	}
+
+	killerStatsComp.(*components.StatsComponent).Exp += xp
+	GetMessageLog().AddAlert(fmt.Sprintf("You gained %d XP!", xp))
+	return xp
+}
+
+// EffectiveEvasion returns an entity's evasion, derived from level plus equipment bonuses
+func EffectiveEvasion(stats *components.StatsComponent) int {
+	return stats.Level + stats.Evasion
+}
+
+// EffectiveAccuracy returns an entity's accuracy, derived from level plus equipment bonuses
+func EffectiveAccuracy(stats *components.StatsComponent) int {
+	return stats.Level + stats.Accuracy
+}
+
+// HitChance returns the probability that an attacker lands a blow on a defender
+func HitChance(attacker, defender *components.StatsComponent) float64 {
+	chance := BaseHitChance + float64(EffectiveAccuracy(attacker)-EffectiveEvasion(defender))*HitChancePerPoint
+	if chance < MinHitChance {
+		return MinHitChance
+	}
+	if chance > MaxHitChance {
+		return MaxHitChance
+	}
+	return chance
+}
+
 // Helper function to get an entity's name or description
 func getEntityName(world *ecs.World, entityID ecs.EntityID) string {
 	if isPlayer(world, entityID) {
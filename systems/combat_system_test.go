@@ -0,0 +1,94 @@
+package systems
+
+import (
+	"math/rand"
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// newSplitFixture sets up a combat system that spawns copies of a slime on an open
+// floor, and returns the slime that splits
+func newSplitFixture(t *testing.T) (*ecs.World, *CombatSystem, ecs.EntityID) {
+	t.Helper()
+	w := newTestWorld(t, 10, 10, 0, 0)
+
+	spawn := func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error) {
+		entityID := w.place(x, y)
+		w.world.AddComponent(entityID, components.AI, &components.AIComponent{TemplateID: templateID})
+		w.world.AddComponent(entityID, components.Stats, &components.StatsComponent{Health: 100, MaxHealth: 100})
+		return entityID, nil
+	}
+
+	combat := NewCombatSystem()
+	combat.SetRNG(rand.New(rand.NewSource(1)))
+	combat.SetEnemyCreator(spawn)
+	combat.Initialize(w.world)
+
+	slimeID, _ := spawn(w.mapID, 5, 5, "slime")
+	return w.world, combat, slimeID
+}
+
+// splitStats returns a monster's stats, topped up so it is always big enough to split
+func splitStats(world *ecs.World, entityID ecs.EntityID) *components.StatsComponent {
+	statsComp, _ := world.GetComponent(entityID, components.Stats)
+	stats := statsComp.(*components.StatsComponent)
+	stats.Health = 100
+	return stats
+}
+
+func TestSplitCapSurvivesOnlyUntilReset(t *testing.T) {
+	world, combat, slimeID := newSplitFixture(t)
+	monsters := func() int { return len(world.GetEntitiesWithComponent(components.AI)) }
+
+	for i := 0; i < MaxSplitsPerOriginal+2; i++ {
+		combat.split(world, slimeID, "Slime", splitStats(world, slimeID))
+	}
+	if got := monsters(); got != MaxSplitsPerOriginal+1 {
+		t.Fatalf("%d monsters after splitting past the cap, want %d", got, MaxSplitsPerOriginal+1)
+	}
+
+	// A new game starts its entity IDs over, so the old counts must not carry into it
+	combat.Reset()
+	before := monsters()
+	combat.split(world, slimeID, "Slime", splitStats(world, slimeID))
+	if monsters() != before+1 {
+		t.Error("a monster couldn't split after Reset because of the last game's count")
+	}
+	if len(combat.splitCounts) != 1 || len(combat.splitRoots) != 1 {
+		t.Errorf("after Reset and one split, %d counts and %d roots are tracked, want 1 each", len(combat.splitCounts), len(combat.splitRoots))
+	}
+}
+
+func TestResolveAttack(t *testing.T) {
+	attacker := &components.StatsComponent{Attack: 5}
+	tests := []struct {
+		name    string
+		seed    int64
+		defense int
+		grip    weaponGrip
+		want    AttackResult
+	}{
+		{"miss", 7, 8, weaponGrip{}, AttackResult{}},
+		{"hit", 1, 8, weaponGrip{}, AttackResult{Hit: true, Roll: 8, Total: 13, Damage: 5}},
+		{"natural 20", 22, 8, weaponGrip{}, AttackResult{Hit: true, Roll: 20, Total: 25, Damage: 17 * CriticalMultiplier, Crit: true}},
+		{"damage floors at zero", 9, 8, weaponGrip{}, AttackResult{Hit: true, Roll: 1, Total: 6, Damage: 0}},
+		{"critical on zero damage", 22, 40, weaponGrip{}, AttackResult{Hit: true, Roll: 20, Total: 25, Damage: 0, Crit: true}},
+		{"dual wield", 1, 8, weaponGrip{DualWield: true}, AttackResult{Hit: true, Roll: 8, Total: 13 - DualWieldPenalty, Damage: 5 - DualWieldPenalty}},
+		{"dual wield natural 20", 22, 8, weaponGrip{DualWield: true}, AttackResult{Hit: true, Roll: 20, Total: 23, Damage: 15 * CriticalMultiplier, Crit: true}},
+		{"two-handed", 1, 8, weaponGrip{TwoHanded: true}, AttackResult{Hit: true, Roll: 8, Total: 13, Bonus: 6, Damage: 11}},
+		{"two-handed natural 20", 22, 8, weaponGrip{TwoHanded: true}, AttackResult{Hit: true, Roll: 20, Total: 25, Bonus: 5, Damage: 22 * CriticalMultiplier, Crit: true}},
+		{"two-handed below defense", 18, 8, weaponGrip{TwoHanded: true}, AttackResult{Hit: true, Roll: 1, Total: 6, Bonus: 2, Damage: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combat := NewCombatSystem()
+			combat.SetRNG(rand.New(rand.NewSource(tt.seed)))
+			defender := &components.StatsComponent{Defense: tt.defense}
+			if got := combat.resolveAttack(attacker, defender, tt.grip); got != tt.want {
+				t.Errorf("resolveAttack = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// companionArrivalRadius is how far from the owner a companion may land after a map
+// transition when the tiles next to them are taken
+const companionArrivalRadius = 5
+
+// CompanionSystem runs the turns of companions: they keep close to their owner, go
+// after monsters that come near and fight them. Map transitions bring them along.
+type CompanionSystem struct {
+	initialized bool
+}
+
+// NewCompanionSystem creates a new companion system
+func NewCompanionSystem() *CompanionSystem {
+	return &CompanionSystem{}
+}
+
+// Initialize sets up event listeners
+func (s *CompanionSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Companions act once for every turn the player takes, in a fixed order since
+	// their fights draw from the combat RNG
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		for _, entity := range byID(world.Query(components.Companion, components.Position).Entities()) {
+			// One companion's fight may have taken another out
+			if world.HasComponent(entity.ID, components.Companion) {
+				s.takeTurn(world, entity.ID)
+			}
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *CompanionSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// takeTurn attacks a monster next to the companion, or steps towards the nearest one
+// in reach, or back towards the owner if it has strayed too far
+func (s *CompanionSystem) takeTurn(world *ecs.World, companionID ecs.EntityID) {
+	companionComp, _ := world.GetComponent(companionID, components.Companion)
+	companion := companionComp.(*components.CompanionComponent)
+	posComp, _ := world.GetComponent(companionID, components.Position)
+	pos := posComp.(*components.PositionComponent)
+
+	mapID := getEntityMapID(world, companionID)
+	owner, ok := entityPoint(world, companion.OwnerID)
+	if !ok || mapID == 0 || getEntityMapID(world, companion.OwnerID) != mapID {
+		return
+	}
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	if targetID, target, found := s.nearestHostile(world, companion, pos, owner, gameMap, mapID); found {
+		if max(abs(target.X-pos.X), abs(target.Y-pos.Y)) <= 1 {
+			for _, system := range world.GetSystems() {
+				if combatSystem, ok := system.(*CombatSystem); ok {
+					combatSystem.ProcessCombat(world, companionID, targetID)
+					break
+				}
+			}
+			return
+		}
+		s.stepTowards(world, companionID, pos, target, gameMap, mapID)
+		return
+	}
+
+	if max(abs(owner.X-pos.X), abs(owner.Y-pos.Y)) > companion.FollowDistance {
+		s.stepTowards(world, companionID, pos, owner, gameMap, mapID)
+	}
+}
+
+// nearestHostile finds the closest monster the companion can see that is within its
+// leash of the owner
+func (s *CompanionSystem) nearestHostile(world *ecs.World, companion *components.CompanionComponent, pos *components.PositionComponent, owner Point, gameMap *components.MapComponent, mapID ecs.EntityID) (ecs.EntityID, Point, bool) {
+	var bestID ecs.EntityID
+	var best Point
+	bestDistance := 0
+	world.Query(components.AI, components.Stats, components.Position).Each(func(entity *ecs.Entity) {
+		if getEntityMapID(world, entity.ID) != mapID {
+			return
+		}
+		target, _ := entityPoint(world, entity.ID)
+		distance := max(abs(target.X-pos.X), abs(target.Y-pos.Y))
+		if distance > companion.SightRange || max(abs(target.X-owner.X), abs(target.Y-owner.Y)) > companion.LeashRange {
+			return
+		}
+		if !HasLineOfSight(gameMap, pos.X, pos.Y, target.X, target.Y) {
+			return
+		}
+		if bestID == 0 || distance < bestDistance || (distance == bestDistance && entity.ID < bestID) {
+			bestID, best, bestDistance = entity.ID, target, distance
+		}
+	})
+	return bestID, best, bestID != 0
+}
+
+// stepTowards moves the companion one tile along a path to a goal, if that tile is free
+func (s *CompanionSystem) stepTowards(world *ecs.World, companionID ecs.EntityID, pos *components.PositionComponent, goal Point, gameMap *components.MapComponent, mapID ecs.EntityID) {
+	var pathfinding *AIPathfindingSystem
+	for _, system := range world.GetSystems() {
+		if found, ok := system.(*AIPathfindingSystem); ok {
+			pathfinding = found
+			break
+		}
+	}
+	if pathfinding == nil {
+		return
+	}
+
+	path := pathfinding.findPath(pos.X, pos.Y, goal.X, goal.Y, gameMap)
+	if len(path) == 0 {
+		return
+	}
+	next := path[0]
+	if gameMap.BlocksMovement(next.X, next.Y) || blockingEntityAt(world, mapID, next.X, next.Y) != 0 {
+		return
+	}
+
+	fromX, fromY := pos.X, pos.Y
+	pos.X, pos.Y = next.X, next.Y
+	world.EmitEvent(EntityMoveEvent{
+		EntityID: companionID,
+		FromX:    fromX,
+		FromY:    fromY,
+		ToX:      pos.X,
+		ToY:      pos.Y,
+	})
+}
+
+// bringCompanions moves an owner's companions on the map they are leaving to free
+// tiles next to where the owner arrives, so none are left behind
+func bringCompanions(world *ecs.World, ownerID, fromMapID, toMapID ecs.EntityID, x, y int) {
+	for _, entity := range world.Query(components.Companion, components.Position).Entities() {
+		companionComp, _ := world.GetComponent(entity.ID, components.Companion)
+		if companionComp.(*components.CompanionComponent).OwnerID != ownerID || getEntityMapID(world, entity.ID) != fromMapID {
+			continue
+		}
+
+		arrivalX, arrivalY, found := FindFreeTileNear(world, toMapID, x, y, companionArrivalRadius, entity.ID)
+		if !found {
+			GetDebugLog().Add(fmt.Sprintf("TRANSITION: No room near (%d,%d) for companion %d, it stays behind", x, y, entity.ID))
+			continue
+		}
+
+		if mapContextComp, exists := world.GetComponent(entity.ID, components.MapContextID); exists {
+			mapContextComp.(*components.MapContextComponent).MapID = toMapID
+		} else {
+			world.AddComponent(entity.ID, components.MapContextID, components.NewMapContextComponent(toMapID))
+		}
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		pos.X, pos.Y = arrivalX, arrivalY
+		GetDebugLog().Add(fmt.Sprintf("TRANSITION: Companion %d followed to map %d at (%d,%d)", entity.ID, toMapID, arrivalX, arrivalY))
+	}
+}
+
+// blockingEntityAt returns an entity on a map that blocks movement into a tile, or 0
+func blockingEntityAt(world *ecs.World, mapID ecs.EntityID, x, y int) ecs.EntityID {
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		if pos, _ := entityPoint(world, entity.ID); pos.X != x || pos.Y != y {
+			continue
+		}
+		collComp, _ := world.GetComponent(entity.ID, components.Collision)
+		if collComp.(*components.CollisionComponent).Blocks {
+			return entity.ID
+		}
+	}
+	return 0
+}
+
+// FindFreeTileNear returns the closest tile to a point on a map that can be walked on
+// and has nothing solid on it, searching rings out to a radius. The ignored entity,
+// such as the one being placed, doesn't count as solid.
+func FindFreeTileNear(world *ecs.World, mapID ecs.EntityID, centerX, centerY, radius int, ignoreID ecs.EntityID) (int, int, bool) {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return 0, 0, false
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	occupied := make(map[Point]bool)
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
+		if entity.ID == ignoreID || getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		collComp, _ := world.GetComponent(entity.ID, components.Collision)
+		if !collComp.(*components.CollisionComponent).Blocks {
+			continue
+		}
+		pos, _ := entityPoint(world, entity.ID)
+		occupied[pos] = true
+	}
+
+	// Search outward in growing rings
+	for r := 0; r <= radius; r++ {
+		for y := centerY - r; y <= centerY+r; y++ {
+			for x := centerX - r; x <= centerX+r; x++ {
+				if max(abs(x-centerX), abs(y-centerY)) != r {
+					continue
+				}
+				if x < 0 || x >= gameMap.Width || y < 0 || y >= gameMap.Height {
+					continue
+				}
+				if gameMap.BlocksMovement(x, y) || occupied[Point{x, y}] {
+					continue
+				}
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
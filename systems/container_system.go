@@ -105,19 +105,19 @@ func (s *ContainerSystem) handleContainerInteraction(container *ecs.Entity) {
 	}
 	containerData := containerComp.(*components.ContainerComponent)
 
-	// Check if container is locked
-	if containerData.Locked {
-		log.Printf("Container is locked")
-		// TODO: Handle locked container logic
-		return
-	}
-
 	// Get container name for messages
 	var containerName string = "a container"
 	if nameComp, exists := s.world.GetComponent(container.ID, components.Name); exists {
 		containerName = nameComp.(*components.NameComponent).Name
 	}
 
+	// Check if container is locked
+	if containerData.Locked {
+		log.Printf("Container is locked")
+		GetMessageLog().AddEnvironment(fmt.Sprintf("You try %s, but it is locked.", containerName))
+		return
+	}
+
 	// If container hasn't been looted yet, show what's inside
 	if !containerData.Looted {
 		// Get list of items in container
@@ -0,0 +1,233 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// ContextActionKind is what a context menu entry does when picked
+type ContextActionKind string
+
+const (
+	ContextAttack  ContextActionKind = "attack"  // Bump into an adjacent hostile
+	ContextBump    ContextActionKind = "bump"    // Bump into an adjacent workbench, altar, camp or lever
+	ContextOpen    ContextActionKind = "open"    // Examine an adjacent container
+	ContextExamine ContextActionKind = "examine" // Target an adjacent hostile to see its details
+	ContextEquip   ContextActionKind = "equip"   // Equip the item underfoot
+	ContextStairs  ContextActionKind = "stairs"  // Take the stairs underfoot
+)
+
+// ContextAction is one entry of the context menu: something the player can do right
+// now on their own tile or to a neighbouring entity
+type ContextAction struct {
+	Kind     ContextActionKind
+	Label    string       // Text shown in the menu
+	TargetID ecs.EntityID // Entity acted on, 0 for the player's own tile
+	X, Y     int          // Tile acted on
+}
+
+// ContextActions lists what the player can do where they stand. Actions come from the
+// same things bumping and the action keys respond to, and only valid ones are listed.
+// When the player has targeted an adjacent hostile, only that hostile's actions and
+// those of the player's own tile are offered.
+func (s *PlayerTurnProcessorSystem) ContextActions(world *ecs.World, playerID ecs.EntityID) []ContextAction {
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return nil
+	}
+	pos := posComp.(*components.PositionComponent)
+	mapID := getEntityMapID(world, playerID)
+
+	// Hostiles can be examined when targeting would accept them
+	var focusID ecs.EntityID
+	examinable := make(map[ecs.EntityID]bool)
+	for _, system := range world.GetSystems() {
+		if targeting, ok := system.(*TargetingSystem); ok {
+			focusID = targeting.GetTarget(world)
+			for _, targetID := range targeting.ValidTargets(world) {
+				examinable[targetID] = true
+			}
+			break
+		}
+	}
+	if focusID != 0 && !s.isEntityAdjacent(world, pos, focusID) {
+		focusID = 0
+	}
+
+	var actions []ContextAction
+	for _, entity := range world.GetAllEntities() {
+		if entity.ID == playerID || getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		if focusID != 0 && entity.ID != focusID {
+			continue
+		}
+		entityPosComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		entityPos := entityPosComp.(*components.PositionComponent)
+		if entityPos.X == pos.X && entityPos.Y == pos.Y {
+			continue
+		}
+		if !s.isAdjacent(pos.X, pos.Y, entityPos.X, entityPos.Y) {
+			continue
+		}
+		actions = append(actions, s.entityActions(world, entity, entityPos, examinable[entity.ID])...)
+	}
+
+	return append(actions, s.tileActions(world, pos, mapID)...)
+}
+
+// entityActions returns the actions for one entity next to the player
+func (s *PlayerTurnProcessorSystem) entityActions(world *ecs.World, entity *ecs.Entity, pos *components.PositionComponent, examinable bool) []ContextAction {
+	name := getEntityName(world, entity.ID)
+	action := func(kind ContextActionKind, label string) ContextAction {
+		return ContextAction{Kind: kind, Label: label, TargetID: entity.ID, X: pos.X, Y: pos.Y}
+	}
+
+	switch {
+	case entity.HasTag("enemy"):
+		if !isBlocking(world, entity.ID) {
+			return nil
+		}
+		actions := []ContextAction{action(ContextAttack, "Attack "+name)}
+		if examinable {
+			actions = append(actions, action(ContextExamine, "Examine "+name))
+		}
+		return actions
+	case entity.HasTag("container"):
+		if entity.HasTag("gravestone") {
+			return []ContextAction{action(ContextOpen, "Loot "+name)}
+		}
+		return []ContextAction{action(ContextOpen, "Open "+name)}
+	case entity.HasTag("crafting_station") && isBlocking(world, entity.ID):
+		return []ContextAction{action(ContextBump, "Craft at "+name)}
+	case entity.HasTag("shrine") && isBlocking(world, entity.ID):
+		return []ContextAction{action(ContextBump, "Make an offering at "+name)}
+	case entity.HasTag("camp") && isBlocking(world, entity.ID):
+		return []ContextAction{action(ContextBump, "Rest at "+name)}
+	case world.HasComponent(entity.ID, components.Mechanism):
+		return []ContextAction{action(ContextBump, "Pull "+name)}
+	}
+	return nil
+}
+
+// tileActions returns the actions for the player's own tile
+func (s *PlayerTurnProcessorSystem) tileActions(world *ecs.World, pos *components.PositionComponent, mapID ecs.EntityID) []ContextAction {
+	var actions []ContextAction
+
+	if mapComp, exists := world.GetComponent(mapID, components.MapComponentID); exists {
+		mapData := mapComp.(*components.MapComponent)
+		if _, hasTransition := mapData.GetTransition(pos.X, pos.Y); hasTransition {
+			switch mapData.Tiles[pos.Y][pos.X] {
+			case components.TileStairsDown:
+				label := "Go down the stairs"
+				if name, danger, ok := floorPreview(world, mapID, pos.X, pos.Y); ok {
+					label = fmt.Sprintf("Go down the stairs to %s (%s)", name, danger)
+				}
+				actions = append(actions, ContextAction{Kind: ContextStairs, Label: label, X: pos.X, Y: pos.Y})
+			case components.TileStairsUp:
+				actions = append(actions, ContextAction{Kind: ContextStairs, Label: "Go up the stairs", X: pos.X, Y: pos.Y})
+			}
+		}
+	}
+
+	// Only gear can be equipped straight off the floor
+	var equipSystem *EquipmentSystem
+	for _, system := range world.GetSystems() {
+		if equipSys, ok := system.(*EquipmentSystem); ok {
+			equipSystem = equipSys
+			break
+		}
+	}
+	if equipSystem != nil {
+		for _, item := range world.GetEntitiesWithTag("item") {
+			itemPosComp, exists := world.GetComponent(item.ID, components.Position)
+			if !exists || getEntityMapID(world, item.ID) != mapID {
+				continue
+			}
+			itemPos := itemPosComp.(*components.PositionComponent)
+			if itemPos.X != pos.X || itemPos.Y != pos.Y {
+				continue
+			}
+			if _, err := equipSystem.SlotForItem(item.ID); err == nil {
+				actions = append(actions, ContextAction{Kind: ContextEquip, Label: "Equip " + getEntityName(world, item.ID), TargetID: item.ID, X: pos.X, Y: pos.Y})
+				break
+			}
+		}
+	}
+
+	return actions
+}
+
+// PerformContextAction carries out a context menu entry the same way its key or bump
+// would, ending the player's turn if the action takes one
+func (s *PlayerTurnProcessorSystem) PerformContextAction(world *ecs.World, playerID ecs.EntityID, action ContextAction) {
+	acted := false
+	switch action.Kind {
+	case ContextAttack, ContextBump:
+		posComp, exists := world.GetComponent(playerID, components.Position)
+		if !exists {
+			return
+		}
+		pos := posComp.(*components.PositionComponent)
+		if dir := s.getDirectionFromDelta(action.X-pos.X, action.Y-pos.Y); dir != DirNone {
+			acted = s.processMovementAction(world, playerID, dir)
+		}
+	case ContextOpen:
+		world.EmitEvent(ExamineEvent{TargetID: action.TargetID})
+		acted = true
+	case ContextExamine:
+		for _, system := range world.GetSystems() {
+			if targeting, ok := system.(*TargetingSystem); ok {
+				targeting.Select(world, action.TargetID)
+				break
+			}
+		}
+	case ContextEquip:
+		if invSystem := s.getInventorySystem(world); invSystem != nil {
+			acted = invSystem.EquipFromGround(world, playerID)
+		}
+	case ContextStairs:
+		for _, system := range world.GetSystems() {
+			if mapRegistry, ok := system.(*MapRegistrySystem); ok {
+				mapRegistry.handleMapTransitions(world)
+				break
+			}
+		}
+	}
+
+	if acted {
+		world.EmitEvent(TurnCompletedEvent{EntityID: playerID})
+	}
+	s.stepping = false
+}
+
+// getDirectionFromDelta returns the direction of a one-tile step, or DirNone
+func (s *PlayerTurnProcessorSystem) getDirectionFromDelta(dx, dy int) int {
+	for dir := DirUp; dir <= DirDownRight; dir++ {
+		if ddx, ddy := s.getDeltaFromDirection(dir); ddx == dx && ddy == dy {
+			return dir
+		}
+	}
+	return DirNone
+}
+
+// isEntityAdjacent returns whether an entity stands next to the given position
+func (s *PlayerTurnProcessorSystem) isEntityAdjacent(world *ecs.World, pos *components.PositionComponent, entityID ecs.EntityID) bool {
+	posComp, exists := world.GetComponent(entityID, components.Position)
+	if !exists {
+		return false
+	}
+	entityPos := posComp.(*components.PositionComponent)
+	return s.isAdjacent(pos.X, pos.Y, entityPos.X, entityPos.Y) && (entityPos.X != pos.X || entityPos.Y != pos.Y)
+}
+
+// isBlocking returns whether walking into an entity bumps it rather than stepping onto it
+func isBlocking(world *ecs.World, entityID ecs.EntityID) bool {
+	collisionComp, exists := world.GetComponent(entityID, components.Collision)
+	return exists && collisionComp.(*components.CollisionComponent).Blocks
+}
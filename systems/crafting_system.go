@@ -0,0 +1,193 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+	"ebiten-rogue/ecs"
+)
+
+// CraftingSystem turns items in the player's inventory into new items at crafting
+// stations, following the recipes loaded from data/recipes
+type CraftingSystem struct {
+	templateManager *data.EntityTemplateManager
+	createItem      func(templateID string) (ecs.EntityID, error) // Spawns an item with no position, for the inventory
+	pendingStation  ecs.EntityID                                  // Station the player just bumped into
+	initialized     bool
+}
+
+// NewCraftingSystem creates a new crafting system
+func NewCraftingSystem() *CraftingSystem {
+	return &CraftingSystem{}
+}
+
+// SetTemplateManager sets the template manager holding the recipes and item names
+func (s *CraftingSystem) SetTemplateManager(templateManager *data.EntityTemplateManager) {
+	s.templateManager = templateManager
+}
+
+// SetItemCreator sets the function used to spawn crafted items
+func (s *CraftingSystem) SetItemCreator(createItem func(templateID string) (ecs.EntityID, error)) {
+	s.createItem = createItem
+}
+
+// Initialize sets up event listeners
+func (s *CraftingSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Bumping into a station opens the crafting screen
+	world.GetEventManager().Subscribe(EventCollision, func(event ecs.Event) {
+		collision := event.(CollisionEvent)
+		if !isPlayer(world, collision.EntityID1) {
+			return
+		}
+		if entity := world.GetEntity(collision.EntityID2); entity != nil && entity.HasTag("crafting_station") {
+			s.pendingStation = collision.EntityID2
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *CraftingSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// TakePendingStation returns the station the player bumped into since the last call,
+// or 0 if there is none
+func (s *CraftingSystem) TakePendingStation() ecs.EntityID {
+	stationID := s.pendingStation
+	s.pendingStation = 0
+	return stationID
+}
+
+// GetRecipes returns every known recipe in list order
+func (s *CraftingSystem) GetRecipes() []*data.Recipe {
+	if s.templateManager == nil {
+		return nil
+	}
+	return s.templateManager.GetRecipes()
+}
+
+// ItemName returns the display name of an item template, falling back to its ID
+func (s *CraftingSystem) ItemName(templateID string) string {
+	if s.templateManager != nil {
+		if template, ok := s.templateManager.GetItemTemplate(templateID); ok {
+			return template.Name
+		}
+	}
+	return templateID
+}
+
+// RecipeName returns the display name of a recipe, which defaults to its output's name
+func (s *CraftingSystem) RecipeName(recipe *data.Recipe) string {
+	if recipe.Name != "" {
+		return recipe.Name
+	}
+	return s.ItemName(recipe.Output)
+}
+
+// MissingIngredients lists the ingredients the entity is short of for a recipe, such
+// as "Scrap Metal x2". The list is empty if the recipe can be crafted.
+func (s *CraftingSystem) MissingIngredients(world *ecs.World, entityID ecs.EntityID, recipe *data.Recipe) []string {
+	var missing []string
+	if s.templateManager != nil {
+		if _, ok := s.templateManager.GetItemTemplate(recipe.Output); !ok {
+			missing = append(missing, "unknown item "+recipe.Output)
+		}
+	}
+
+	counts := s.countIngredients(world, entityID)
+	for _, input := range recipe.Inputs {
+		if short := input.Count - len(counts[input.TemplateID]); short > 0 {
+			missing = append(missing, fmt.Sprintf("%s x%d", s.ItemName(input.TemplateID), short))
+		}
+	}
+	return missing
+}
+
+// Craft consumes a recipe's inputs from the entity's inventory and adds the output.
+// Returns false if the recipe can't be crafted.
+func (s *CraftingSystem) Craft(world *ecs.World, entityID ecs.EntityID, recipe *data.Recipe) bool {
+	if s.createItem == nil {
+		return false
+	}
+	invComp, exists := world.GetComponent(entityID, components.Inventory)
+	if !exists {
+		return false
+	}
+	inventory := invComp.(*components.InventoryComponent)
+
+	if missing := s.MissingIngredients(world, entityID, recipe); len(missing) > 0 {
+		GetMessageLog().AddSystem("You don't have everything you need.")
+		return false
+	}
+
+	// Consume the inputs before making room for the output
+	counts := s.countIngredients(world, entityID)
+	for _, input := range recipe.Inputs {
+		for _, itemID := range counts[input.TemplateID][:input.Count] {
+			inventory.RemoveItem(itemID)
+			world.RemoveEntity(itemID)
+		}
+	}
+
+	for i := 0; i < recipe.OutputCount; i++ {
+		itemID, err := s.createItem(recipe.Output)
+		if err != nil {
+			GetDebugLog().Add(fmt.Sprintf("Failed to craft %s: %v", recipe.Output, err))
+			continue
+		}
+		if !inventory.AddItem(itemID) {
+			// No room left, so leave it on the ground at the crafter's feet
+			if posComp, exists := world.GetComponent(entityID, components.Position); exists {
+				pos := posComp.(*components.PositionComponent)
+				world.AddComponent(itemID, components.Position, &components.PositionComponent{X: pos.X, Y: pos.Y})
+				world.AddComponent(itemID, components.MapContextID, components.NewMapContextComponent(getEntityMapID(world, entityID)))
+			}
+			GetMessageLog().Add(fmt.Sprintf("Your inventory is full, the %s drops to the floor.", s.ItemName(recipe.Output)))
+		}
+	}
+
+	if recipe.OutputCount > 1 {
+		GetMessageLog().Add(fmt.Sprintf("You craft %d %s.", recipe.OutputCount, s.RecipeName(recipe)))
+	} else {
+		GetMessageLog().Add(fmt.Sprintf("You craft a %s.", s.RecipeName(recipe)))
+	}
+	world.EmitEvent(SoundEvent{Name: "craft"})
+	return true
+}
+
+// countIngredients groups the unequipped items in an entity's inventory by template
+func (s *CraftingSystem) countIngredients(world *ecs.World, entityID ecs.EntityID) map[string][]ecs.EntityID {
+	counts := make(map[string][]ecs.EntityID)
+	invComp, exists := world.GetComponent(entityID, components.Inventory)
+	if !exists {
+		return counts
+	}
+
+	// Equipped items are never used up
+	equipped := make(map[ecs.EntityID]bool)
+	if equipComp, exists := world.GetComponent(entityID, components.Equipment); exists {
+		for _, itemID := range equipComp.(*components.EquipmentComponent).EquippedItems {
+			equipped[itemID] = true
+		}
+	}
+
+	for _, itemID := range invComp.(*components.InventoryComponent).Items {
+		if equipped[itemID] {
+			continue
+		}
+		if itemComp, exists := world.GetComponent(itemID, components.Item); exists {
+			templateID := itemComp.(*components.ItemComponent).TemplateID
+			counts[templateID] = append(counts[templateID], itemID)
+		}
+	}
+	return counts
+}
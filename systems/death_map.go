@@ -0,0 +1,177 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/config"
+	"ebiten-rogue/ecs"
+)
+
+// DeathSnapshotRadius is how far from the player's body monsters are recorded
+const DeathSnapshotRadius = 10
+
+// DeathSnapshot is the floor the player died on as it was at the moment of death,
+// kept for the game over screen to show in full
+type DeathSnapshot struct {
+	MapID      ecs.EntityID
+	Level      int // Depth of the floor, 0 off the dungeon
+	Tiles      [][]int
+	Explored   [][]bool // What the player had seen, so the rest can be told apart
+	X, Y       int      // Where the player fell
+	KillerName string
+	Monsters   []DeathSnapshotMonster // Monsters within DeathSnapshotRadius of the body
+}
+
+// DeathSnapshotMonster is a monster near the player when they died
+type DeathSnapshotMonster struct {
+	EntityID   ecs.EntityID
+	Name       string
+	X, Y       int
+	Appearance components.RenderableComponent
+}
+
+// captureDeathSnapshot copies the player's floor, position and nearby monsters. The
+// tiles are copied so later changes to the map can't alter what the snapshot shows.
+func captureDeathSnapshot(world *ecs.World, playerID, killerID ecs.EntityID) *DeathSnapshot {
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return nil
+	}
+	pos := posComp.(*components.PositionComponent)
+	mapID := getEntityMapID(world, playerID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	snapshot := &DeathSnapshot{
+		MapID:      mapID,
+		Tiles:      make([][]int, mapData.Height),
+		Explored:   make([][]bool, mapData.Height),
+		X:          pos.X,
+		Y:          pos.Y,
+		KillerName: getEntityName(world, killerID),
+	}
+	if level, isDungeon := dungeonLevel(world, mapID); isDungeon {
+		snapshot.Level = level
+	}
+	for y := 0; y < mapData.Height; y++ {
+		snapshot.Tiles[y] = append([]int(nil), mapData.Tiles[y]...)
+		snapshot.Explored[y] = append([]bool(nil), mapData.Explored[y]...)
+	}
+
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		monsterPos, hasPos := world.GetComponent(entity.ID, components.Position)
+		rendComp, hasRend := world.GetComponent(entity.ID, components.Renderable)
+		if !hasPos || !hasRend {
+			continue
+		}
+		mp := monsterPos.(*components.PositionComponent)
+		if max(abs(mp.X-pos.X), abs(mp.Y-pos.Y)) > DeathSnapshotRadius {
+			continue
+		}
+		snapshot.Monsters = append(snapshot.Monsters, DeathSnapshotMonster{
+			EntityID:   entity.ID,
+			Name:       getEntityName(world, entity.ID),
+			X:          mp.X,
+			Y:          mp.Y,
+			Appearance: *rendComp.(*components.RenderableComponent),
+		})
+	}
+	return snapshot
+}
+
+// DrawDeathMap draws a death snapshot's floor fully revealed, centered on where the
+// player fell, with the monsters that were nearby and a summary in the side panel.
+// Tiles the player never saw are dimmed so the unexplored parts stand out.
+func (s *RenderSystem) DrawDeathMap(world *ecs.World, screen *ebiten.Image, snapshot *DeathSnapshot) {
+	screen.Fill(s.palette.Background)
+
+	tileMapEntities := world.GetEntitiesWithTag("tilemap")
+	if len(tileMapEntities) == 0 {
+		return
+	}
+	comp, exists := world.GetComponent(tileMapEntities[0].ID, components.Appearance)
+	if !exists {
+		return
+	}
+	tileMapping := comp.(*components.TileMappingComponent)
+
+	// Center on the body, without scrolling past the edges of the map
+	height := len(snapshot.Tiles)
+	width := 0
+	if height > 0 {
+		width = len(snapshot.Tiles[0])
+	}
+	cameraX := max(0, min(snapshot.X-config.GameScreenWidth/2, width-config.GameScreenWidth))
+	cameraY := max(0, min(snapshot.Y-config.GameScreenHeight/2, height-config.GameScreenHeight))
+
+	for y := 0; y < config.GameScreenHeight; y++ {
+		for x := 0; x < config.GameScreenWidth; x++ {
+			worldX, worldY := x+cameraX, y+cameraY
+			if worldX >= width || worldY >= height {
+				continue
+			}
+			tileType := snapshot.Tiles[worldY][worldX]
+			tileDef := tileMapping.GetTileDefinition(tileType)
+			if components.GetTileProperties(tileType).IsHazard && s.palette.HazardTile != (color.RGBA{}) {
+				tileDef.FG = s.palette.HazardTile
+			}
+			fg := tileDef.FG
+			if !snapshot.Explored[worldY][worldX] {
+				fg = tintColor(fg, s.palette.Background, 0.6)
+			}
+			s.tileset.DrawTileByID(screen, s.mapTile(tileType, tileDef), x, y, fg, 0)
+		}
+	}
+
+	for _, monster := range snapshot.Monsters {
+		x, y := monster.X-cameraX, monster.Y-cameraY
+		if x < 0 || x >= config.GameScreenWidth || y < 0 || y >= config.GameScreenHeight {
+			continue
+		}
+		look := monster.Appearance
+		tileID, _ := s.entityTile(world, monster.EntityID, look.UseTilePos, look.TileX, look.TileY, look.Char)
+		s.tileset.DrawTileByID(screen, tileID, x, y, look.FG, 0)
+	}
+
+	// Mark the spot
+	s.tileset.DrawTile(screen, 'X', snapshot.X-cameraX, snapshot.Y-cameraY, s.palette.HealthLow)
+
+	panelX := config.GameScreenWidth + 2
+	y := 1
+	s.tileset.DrawString(screen, "YOU DIED", panelX, y, s.palette.BossTitle)
+	y += 2
+	s.tileset.DrawString(screen, "Killed by "+snapshot.KillerName, panelX, y, s.palette.PanelText)
+	y++
+	if snapshot.Level > 0 {
+		s.tileset.DrawString(screen, fmt.Sprintf("on floor %d", snapshot.Level), panelX, y, s.palette.PanelText)
+		y++
+	}
+	y++
+
+	s.tileset.DrawString(screen, "Nearby when you fell:", panelX, y, s.palette.PanelHeading)
+	y++
+	if len(snapshot.Monsters) == 0 {
+		s.tileset.DrawString(screen, "Nothing", panelX, y, s.palette.PanelDim)
+		y++
+	}
+	for _, monster := range snapshot.Monsters {
+		distance := max(abs(monster.X-snapshot.X), abs(monster.Y-snapshot.Y))
+		s.tileset.DrawString(screen, fmt.Sprintf("- %s (%d away)", monster.Name, distance), panelX, y, s.palette.PanelText)
+		y++
+	}
+	y++
+
+	y += s.tileset.DrawString(screen, "X marks where you fell\nDim tiles were never seen", panelX, y, s.palette.PanelDim)
+	y++
+	s.tileset.DrawString(screen, "Escape: start screen", panelX, y, s.palette.PanelText)
+}
@@ -1,13 +1,14 @@
 package systems
 
 import (
-	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
 	"fmt"
 )
 
 // DeathSystem handles death events and their consequences
 type DeathSystem struct {
+	revealMap   bool           // Capture the floor on death for the game over screen
+	lastDeath   *DeathSnapshot // The player's floor when they last died, if captured
 	initialized bool
 }
 
@@ -16,6 +17,18 @@ func NewDeathSystem() *DeathSystem {
 	return &DeathSystem{}
 }
 
+// SetRevealMap sets whether the player's floor is captured on death so the game over
+// screen can show it fully revealed
+func (s *DeathSystem) SetRevealMap(enabled bool) {
+	s.revealMap = enabled
+}
+
+// LastDeath returns the snapshot of the player's last death, or nil if the map
+// reveal is off
+func (s *DeathSystem) LastDeath() *DeathSnapshot {
+	return s.lastDeath
+}
+
 // Initialize sets up event listeners
 func (s *DeathSystem) Initialize(world *ecs.World) {
 	if s.initialized {
@@ -40,20 +53,23 @@ func (s *DeathSystem) handleDeath(world *ecs.World, event DeathEvent) {
 	// Log the death
 	GetMessageLog().AddAlert(fmt.Sprintf("%s was killed by %s!", entityName, killerName))
 
-	// If the player died, emit game over event
-	if isPlayer(world, event.EntityID) {
+	// In practice mode the player is sent back to the entrance instead
+	if isPlayer(world, event.EntityID) && isPracticeRun(world) {
+		s.respawnPlayer(world, event.EntityID)
+	} else if isPlayer(world, event.EntityID) {
+		// If the player died, emit game over event
 		GetMessageLog().AddAlert("Game Over! You were defeated.")
+		s.lastDeath = nil
+		if s.revealMap {
+			s.lastDeath = captureDeathSnapshot(world, event.EntityID, event.KillerID)
+		}
 		world.GetEventManager().Emit(GameOverEvent{PlayerID: event.EntityID})
 	} else if isPlayer(world, event.KillerID) {
-		// Player killed something - check for XP gain
-		if monsterStatsComp, hasMonsterStats := world.GetComponent(event.EntityID, components.Stats); hasMonsterStats {
-			monsterStats := monsterStatsComp.(*components.StatsComponent)
-			// Get the player's stats component
-			if playerStatsComp, hasPlayerStats := world.GetComponent(event.KillerID, components.Stats); hasPlayerStats {
-				playerStats := playerStatsComp.(*components.StatsComponent)
-				// Add XP to player
-				playerStats.Exp += monsterStats.Exp
-				GetMessageLog().AddAlert(fmt.Sprintf("You gained %d XP!", monsterStats.Exp))
+		// Record the kill in the bestiary
+		for _, system := range world.GetSystems() {
+			if bestiary, ok := system.(*BestiarySystem); ok {
+				bestiary.RecordKill(world, event.EntityID)
+				break
 			}
 		}
 	}
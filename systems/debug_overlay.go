@@ -0,0 +1,163 @@
+package systems
+
+import (
+	"container/heap"
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/config"
+	"ebiten-rogue/ecs"
+)
+
+// DebugOverlay is a tuning view tinted over the map
+type DebugOverlay int
+
+const (
+	DebugOverlayDensity  DebugOverlay = iota // How many monsters are near each tile
+	DebugOverlayDistance                     // Path cost from each tile to the player
+)
+
+// densityRadius is how far away a monster still counts towards a tile's density
+const densityRadius = 4
+
+// Debug overlay colors, running from the low end of a view to the high end
+var (
+	overlayLow  = color.RGBA{40, 80, 255, 255}
+	overlayHigh = color.RGBA{255, 40, 40, 255}
+)
+
+// ToggleDebugOverlay shows or hides the debug overlay
+func (s *RenderSystem) ToggleDebugOverlay() {
+	s.showDebugOverlay = !s.showDebugOverlay
+}
+
+// CycleDebugOverlay switches the debug overlay between its views
+func (s *RenderSystem) CycleDebugOverlay() {
+	if s.debugOverlay == DebugOverlayDensity {
+		s.debugOverlay = DebugOverlayDistance
+	} else {
+		s.debugOverlay = DebugOverlayDensity
+	}
+}
+
+// drawDebugOverlay tints every map tile in view by the current debug view, from blue
+// for the lowest value on the map to red for the highest. Tiles without a value, with
+// no monster near or no path to the player, are left alone. It shows the whole map,
+// seen or not, as it is meant for tuning spawns and AI pathing.
+func (s *RenderSystem) drawDebugOverlay(world *ecs.World, screen *ebiten.Image, mapID ecs.EntityID, cameraX, cameraY int) {
+	if !s.showDebugOverlay {
+		return
+	}
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	var values [][]int
+	label := "DEBUG: monster density"
+	if s.debugOverlay == DebugOverlayDistance {
+		values = playerDistanceField(world, mapData)
+		label = "DEBUG: path cost to player"
+	} else {
+		values = monsterDensity(world, mapID, mapData)
+	}
+
+	highest := 0
+	for _, row := range values {
+		for _, value := range row {
+			highest = max(highest, value)
+		}
+	}
+	for y := 0; y < config.GameScreenHeight; y++ {
+		for x := 0; x < config.GameScreenWidth; x++ {
+			worldX, worldY := x+cameraX, y+cameraY
+			if !mapData.InBounds(worldX, worldY) || values[worldY][worldX] <= 0 {
+				continue
+			}
+			tint := tintColor(overlayLow, overlayHigh, float64(values[worldY][worldX])/float64(max(highest, 1))).(color.RGBA)
+			tint.A = 110
+
+			// Full block (CP437 219)
+			s.tileset.DrawTileByID(screen, NewTileID(11, 13), x, y, tint, 0)
+		}
+	}
+
+	s.tileset.DrawString(screen, fmt.Sprintf("%s, max %d (F5: switch)", label, highest), 0, config.GameScreenHeight-1, s.palette.PanelHeading)
+}
+
+// monsterDensity counts, for every tile of a map, the monsters within densityRadius
+func monsterDensity(world *ecs.World, mapID ecs.EntityID, mapData *components.MapComponent) [][]int {
+	density := make([][]int, mapData.Height)
+	for y := range density {
+		density[y] = make([]int, mapData.Width)
+	}
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		posComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		pos := posComp.(*components.PositionComponent)
+		for y := max(pos.Y-densityRadius, 0); y <= min(pos.Y+densityRadius, mapData.Height-1); y++ {
+			for x := max(pos.X-densityRadius, 0); x <= min(pos.X+densityRadius, mapData.Width-1); x++ {
+				density[y][x]++
+			}
+		}
+	}
+	return density
+}
+
+// playerDistanceField returns, for every tile of a map, what the monsters' A* pays to
+// walk from it to the player: four-way steps, each costing the tile's move cost. The
+// player's own tile is 0 and tiles with no way through are -1.
+func playerDistanceField(world *ecs.World, mapData *components.MapComponent) [][]int {
+	field := make([][]int, mapData.Height)
+	for y := range field {
+		field[y] = make([]int, mapData.Width)
+		for x := range field[y] {
+			field[y][x] = -1
+		}
+	}
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return field
+	}
+	posComp, exists := world.GetComponent(playerEntities[0].ID, components.Position)
+	if !exists {
+		return field
+	}
+	start := posComp.(*components.PositionComponent)
+	if !mapData.InBounds(start.X, start.Y) {
+		return field
+	}
+
+	// Dijkstra outward from the player; costs are symmetric enough for a debug view
+	openSet := make(PriorityQueue, 0)
+	heap.Push(&openSet, &Item{value: Point{start.X, start.Y}, priority: 0})
+	field[start.Y][start.X] = 0
+	for openSet.Len() > 0 {
+		item := heap.Pop(&openSet).(*Item)
+		current := item.value.(Point)
+		if item.priority > field[current.Y][current.X] {
+			continue
+		}
+		for _, next := range []Point{{current.X + 1, current.Y}, {current.X - 1, current.Y}, {current.X, current.Y + 1}, {current.X, current.Y - 1}} {
+			if mapData.BlocksMovement(next.X, next.Y) {
+				continue
+			}
+			cost := item.priority + mapData.MoveCost(next.X, next.Y)
+			if known := field[next.Y][next.X]; known >= 0 && known <= cost {
+				continue
+			}
+			field[next.Y][next.X] = cost
+			heap.Push(&openSet, &Item{value: next, priority: cost})
+		}
+	}
+	return field
+}
@@ -0,0 +1,159 @@
+package systems
+
+import (
+	"fmt"
+	"slices"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// breakNoiseRadius is how far away monsters hear a prop break when it sets none of
+// its own
+const breakNoiseRadius = 8
+
+// SetItemCreator sets the function used to drop the loot of broken props
+func (s *CombatSystem) SetItemCreator(createItem func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error)) {
+	s.createItem = createItem
+}
+
+// attackDestructible hits a prop the attacker bumped into. Props don't dodge or wear
+// armor, so every hit lands for the attacker's attack plus a d4.
+func (s *CombatSystem) attackDestructible(world *ecs.World, attackerID, propID ecs.EntityID) {
+	destComp, _ := world.GetComponent(propID, components.Destructible)
+	prop := destComp.(*components.DestructibleComponent)
+
+	damage := 1 + s.rng.Intn(4)
+	if statsComp, exists := world.GetComponent(attackerID, components.Stats); exists {
+		damage += max(0, statsComp.(*components.StatsComponent).Attack)
+	}
+	prop.Health -= damage
+
+	propName := getEntityName(world, propID)
+	if prop.Health > 0 {
+		GetMessageLog().AddCombat(fmt.Sprintf("%s hits the %s for %d damage. It holds, for now.",
+			getEntityName(world, attackerID), propName, damage))
+		return
+	}
+
+	for _, bombID := range s.BreakDestructible(world, propID, attackerID) {
+		// An earlier bomb's blast may already have set this one off
+		if world.GetEntity(bombID) == nil {
+			continue
+		}
+		for _, system := range world.GetSystems() {
+			if bombSystem, ok := system.(*BombSystem); ok {
+				GetMessageLog().AddCombat(fmt.Sprintf("The blast sets off the %s!", getEntityName(world, bombID)))
+				bombSystem.Detonate(world, bombID)
+				break
+			}
+		}
+	}
+}
+
+// BreakDestructible destroys a prop: it is removed from the map, may drop loot where
+// it stood, and is heard by monsters nearby. An exploding prop then goes off, and the
+// armed bombs caught in its blast are returned for the caller to set off.
+func (s *CombatSystem) BreakDestructible(world *ecs.World, propID, breakerID ecs.EntityID) []ecs.EntityID {
+	destComp, exists := world.GetComponent(propID, components.Destructible)
+	if !exists {
+		return nil
+	}
+	prop := destComp.(*components.DestructibleComponent)
+
+	posComp, exists := world.GetComponent(propID, components.Position)
+	if !exists {
+		return nil
+	}
+	pos := posComp.(*components.PositionComponent)
+	x, y := pos.X, pos.Y
+	mapID := getEntityMapID(world, propID)
+	propName := getEntityName(world, propID)
+
+	// Gone before anything else happens so a chain of blasts can't break it twice
+	world.RemoveEntity(propID)
+	world.EmitEvent(MapMutatedEvent{MapID: mapID, Tiles: []Point{{x, y}}})
+
+	if prop.Explodes() {
+		GetMessageLog().AddAlert(fmt.Sprintf("The %s explodes!", propName))
+	} else {
+		GetMessageLog().AddCombat(fmt.Sprintf("The %s breaks apart.", propName))
+		world.EmitEvent(SoundEvent{Name: "break"})
+
+		noiseRadius := prop.NoiseRadius
+		if noiseRadius <= 0 {
+			noiseRadius = breakNoiseRadius
+		}
+		world.EmitEvent(NoiseEvent{MapID: mapID, X: x, Y: y, Radius: noiseRadius})
+	}
+
+	if len(prop.Loot) > 0 && s.createItem != nil && s.rng.Float64() < prop.LootChance {
+		templateID := s.chooseLoot(world, prop.Loot)
+		if itemID, err := s.createItem(mapID, x, y, templateID); err != nil {
+			GetDebugLog().Add(fmt.Sprintf("Failed to drop %s from %s: %v", templateID, propName, err))
+		} else {
+			GetMessageLog().AddEnvironment(fmt.Sprintf("Something falls out: %s.", getEntityName(world, itemID)))
+		}
+	}
+
+	if !prop.Explodes() {
+		return nil
+	}
+	for _, system := range world.GetSystems() {
+		if bombSystem, ok := system.(*BombSystem); ok {
+			effects := []components.GameEffect{explosionEffect(prop.ExplosionDamage, propID)}
+			return bombSystem.Blast(world, propID, breakerID, mapID, x, y, prop.ExplosionRadius, effects)
+		}
+	}
+	return nil
+}
+
+// chooseLoot picks the item a broken prop drops. Every choice is as likely as the
+// next, except that healing items are weighted by the healing balance system's bias.
+func (s *CombatSystem) chooseLoot(world *ecs.World, loot []string) string {
+	bias := healingBias(world)
+	if bias == 1 {
+		return loot[s.rng.Intn(len(loot))]
+	}
+
+	weights := make([]float64, len(loot))
+	total := 0.0
+	for i, templateID := range loot {
+		weights[i] = 1
+		if s.isHealingItem(templateID) {
+			weights[i] = bias
+		}
+		total += weights[i]
+	}
+	roll := s.rng.Float64() * total
+	for i, weight := range weights {
+		if roll < weight {
+			return loot[i]
+		}
+		roll -= weight
+	}
+	return loot[len(loot)-1]
+}
+
+// isHealingItem returns whether an item template is tagged as healing
+func (s *CombatSystem) isHealingItem(templateID string) bool {
+	if s.templateManager == nil {
+		return false
+	}
+	template, exists := s.templateManager.GetItemTemplate(templateID)
+	return exists && slices.Contains(template.Tags, "healing")
+}
+
+// explosionEffect is the instant fire damage dealt by an exploding prop
+func explosionEffect(damage int, sourceID ecs.EntityID) components.GameEffect {
+	effect := components.GameEffect{
+		Type:      components.EffectTypeInstant,
+		Operation: components.EffectOpSubtract,
+		Value:     float64(damage),
+		Source:    sourceID,
+		School:    "fire",
+	}
+	effect.Target.Component = "Stats"
+	effect.Target.Property = "Health"
+	return effect
+}
@@ -4,7 +4,7 @@ import (
 	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
 	"fmt"
-	"math/rand"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -13,6 +13,12 @@ import (
 type EffectsSystem struct {
 	initialized bool
 	world       *ecs.World
+
+	// Several input paths can report the same player action as a completed turn, so
+	// turns are counted here rather than per event: every turn_completed between two
+	// updates is the same turn
+	turn     int  // Turns seen so far, starting at 1
+	turnOpen bool // A turn has completed since the last update
 }
 
 // NewEffectsSystem creates a new effects system
@@ -50,53 +56,50 @@ func (s *EffectsSystem) Initialize(world *ecs.World) {
 			if itemComp, exists := world.GetComponent(effectEvent.Source, components.Item); exists {
 				if item, ok := itemComp.(*components.ItemComponent); ok {
 					if effects, ok := item.Data.([]components.GameEffect); ok {
-						// Get or create the effect component to track applied effects
-						effectComp, exists := world.GetComponent(effectEvent.EntityID, components.Effect)
-						if !exists {
-							effectComp = &components.EffectComponent{
-								Effects: make([]components.GameEffect, 0),
-							}
-							world.AddComponent(effectEvent.EntityID, components.Effect, effectComp)
-						}
-						effectComponent := effectComp.(*components.EffectComponent)
-
-						// Check if these effects have already been applied
-						alreadyApplied := false
-						for _, existing := range effectComponent.Effects {
-							if existing.Source == effectEvent.Source {
-								alreadyApplied = true
-								break
-							}
-						}
-
-						if !alreadyApplied {
-							// Apply the item's effects
-							s.ApplyEntityEffects(world, effectEvent.EntityID, effects)
-						}
+						// Apply the item's effects, each combining with what is already
+						// there according to its stacking policy
+						s.ApplyEntityEffects(world, effectEvent.EntityID, effects)
 					}
 				}
 			}
 		}
 	})
 
+	// Hits can push a monster past a health threshold, so check its conditions
+	// straight away rather than at the end of the turn
+	world.GetEventManager().Subscribe(EventCombatAttack, func(event ecs.Event) {
+		if attackEvent, ok := event.(CombatAttackEvent); ok {
+			s.EvaluateConditions(world, attackEvent.DefenderID)
+		}
+	})
+
 	// Subscribe to turn completed events
 	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
-		if _, ok := event.(TurnCompletedEvent); ok {
+		if turnEvent, ok := event.(TurnCompletedEvent); ok {
+			if s.turnOpen {
+				GetDebugLog().Add(fmt.Sprintf("Turn %d reported again by entity %d, effects already ticked", s.turn, turnEvent.EntityID))
+			} else {
+				s.turn++
+				s.turnOpen = true
+			}
+
 			// Process effects for all entities with the Effect component
-			for _, entity := range world.GetEntitiesWithComponent(components.Effect) {
+			world.Query(components.Effect).Each(func(entity *ecs.Entity) {
 				s.ProcessEffects(world, entity.ID)
-			}
+			})
 		}
 	})
 
 	s.initialized = true
 }
 
-// Update ensures the system is initialized but doesn't process effects every frame
+// Update ensures the system is initialized but doesn't process effects every frame.
+// The player acts at most once per update, so the next turn_completed is a new turn.
 func (s *EffectsSystem) Update(world *ecs.World, dt float64) {
 	if !s.initialized {
 		s.Initialize(world)
 	}
+	s.turnOpen = false
 }
 
 // ApplyEntityEffects applies a list of effects to an entity
@@ -139,32 +142,19 @@ func (s *EffectsSystem) ApplyEntityEffects(world *ecs.World, entityID ecs.Entity
 
 	// Apply each effect
 	for _, effect := range effects {
-		// Check for duplicate effects
-		isDuplicate := false
-		for i, existing := range effectComponent.Effects {
-			if existing.Type == effect.Type &&
-				existing.Operation == effect.Operation &&
-				existing.Target.Component == effect.Target.Component &&
-				existing.Target.Property == effect.Target.Property &&
-				existing.Source == effect.Source {
-				// Update existing effect
-				effectComponent.Effects[i] = effect
-				isDuplicate = true
-				GetDebugLog().Add(fmt.Sprintf("  - Updated existing effect"))
-				break
-			}
+		// Immune entities never pick up the effect, so no stacks build up
+		if isImmune(world, entityID, effect.School) {
+			GetMessageLog().AddCombat(fmt.Sprintf("%s is immune to %s.", getEntityName(world, entityID), effect.School))
+			continue
 		}
 
-		if !isDuplicate {
-			// Add new effect
-			effectComponent.Effects = append(effectComponent.Effects, effect)
-			GetDebugLog().Add(fmt.Sprintf("  - Added new effect"))
+		// Combine with any matching effect according to the stacking policy. Instant
+		// effects aren't applied here, they go off when the turn ends.
+		if effectComponent.AddEffect(effect) {
+			GetDebugLog().Add(fmt.Sprintf("  - Added effect (%s)", effect.StackingPolicy()))
+		} else {
+			GetDebugLog().Add(fmt.Sprintf("  - Dropped effect (%s)", effect.StackingPolicy()))
 		}
-
-		// Don't apply instant effects here since they are handled by the event system
-		// if effect.Type == components.EffectTypeInstant && !isDuplicate {
-		// 	s.applyEffect(world, entityID, effect)
-		// }
 	}
 
 	// Log stats after effects
@@ -255,10 +245,15 @@ func (s *EffectsSystem) CreateGameEffect(effectType components.EffectType, opera
 	}
 }
 
-// ProcessEffects processes all active effects on an entity
+// ProcessEffects processes all active effects on an entity for the current turn. An
+// entity whose effects already ticked this turn is left alone.
 func (s *EffectsSystem) ProcessEffects(world *ecs.World, entityID ecs.EntityID) {
 	if comp, exists := world.GetComponent(entityID, components.Effect); exists {
 		if effectComp, ok := comp.(*components.EffectComponent); ok {
+			if effectComp.LastTurn == s.turn {
+				return
+			}
+			effectComp.LastTurn = s.turn
 			// Create a new slice to store effects that should remain
 			remainingEffects := make([]components.GameEffect, 0)
 
@@ -289,17 +284,132 @@ func (s *EffectsSystem) ProcessEffects(world *ecs.World, entityID ecs.EntityID)
 					}
 
 				case components.EffectTypeConditional:
-					// Keep conditional effects
+					// Keep conditional effects, switching them on or off as needed
+					s.evaluateCondition(world, entityID, &effect)
 					remainingEffects = append(remainingEffects, effect)
 				}
 			}
 
-			// Update the effects list
+			// Update the effects list, dropping the component once nothing is left on it
 			effectComp.Effects = remainingEffects
+			if len(remainingEffects) == 0 {
+				world.RemoveComponent(entityID, components.Effect)
+			}
+		}
+	}
+}
+
+// EvaluateConditions switches an entity's conditional effects on or off to match
+// their conditions
+func (s *EffectsSystem) EvaluateConditions(world *ecs.World, entityID ecs.EntityID) {
+	comp, exists := world.GetComponent(entityID, components.Effect)
+	if !exists {
+		return
+	}
+	effectComp := comp.(*components.EffectComponent)
+	for i := range effectComp.Effects {
+		if effectComp.Effects[i].Type == components.EffectTypeConditional {
+			s.evaluateCondition(world, entityID, &effectComp.Effects[i])
+		}
+	}
+}
+
+// evaluateCondition applies a conditional effect once when its condition starts to
+// hold and undoes exactly what it did when the condition stops, so the effect never
+// builds up however many turns the condition lasts
+func (s *EffectsSystem) evaluateCondition(world *ecs.World, entityID ecs.EntityID, effect *components.GameEffect) {
+	holds := conditionHolds(world, entityID, effect.Condition)
+	switch {
+	case holds && !effect.Active:
+		before := effectPropertyValue(world, entityID, *effect)
+		s.applyEffect(world, entityID, *effect)
+		effect.Applied = effectPropertyValue(world, entityID, *effect) - before
+		effect.Active = true
+		if effect.Message != "" {
+			GetMessageLog().AddAlert(fmt.Sprintf("%s %s", getEntityName(world, entityID), effect.Message))
 		}
+	case !holds && effect.Active:
+		undo := *effect
+		undo.Operation = components.EffectOpAdd
+		undo.Value = -effect.Applied
+		s.applyEffect(world, entityID, undo)
+		effect.Applied = 0
+		effect.Active = false
+		GetDebugLog().Add(fmt.Sprintf("Conditional effect on %s.%s wore off for entity %d", effect.Target.Component, effect.Target.Property, entityID))
 	}
 }
 
+// conditionHolds checks a conditional effect's condition against the entity
+func conditionHolds(world *ecs.World, entityID ecs.EntityID, condition components.EffectCondition) bool {
+	if condition.Weather != "" {
+		for _, system := range world.GetSystems() {
+			if weatherSystem, ok := system.(*WeatherSystem); ok {
+				current := weatherSystem.CurrentWeather()
+				return current != nil && current.ID == condition.Weather
+			}
+		}
+		return false
+	}
+	if condition.HealthBelow <= 0 {
+		return false
+	}
+	statsComp, exists := world.GetComponent(entityID, components.Stats)
+	if !exists {
+		return false
+	}
+	stats := statsComp.(*components.StatsComponent)
+	return stats.Health > 0 && float64(stats.Health) < condition.HealthBelow*float64(stats.MaxHealth)
+}
+
+// effectPropertyValue reads the property an effect changes
+func effectPropertyValue(world *ecs.World, entityID ecs.EntityID, effect components.GameEffect) float64 {
+	switch effect.Target.Component {
+	case "Stats":
+		comp, exists := world.GetComponent(entityID, components.Stats)
+		if !exists {
+			return 0
+		}
+		stats := comp.(*components.StatsComponent)
+		switch effect.Target.Property {
+		case "Health":
+			return float64(stats.Health)
+		case "MaxHealth":
+			return float64(stats.MaxHealth)
+		case "Attack":
+			return float64(stats.Attack)
+		case "Defense":
+			return float64(stats.Defense)
+		case "Evasion":
+			return float64(stats.Evasion)
+		case "Accuracy":
+			return float64(stats.Accuracy)
+		case "Stealth":
+			return float64(stats.Stealth)
+		case "Cleave":
+			return float64(stats.Cleave)
+		case "Recovery":
+			return float64(stats.Recovery)
+		case "Damage":
+			return float64(stats.BonusDamage[effect.School])
+		}
+	case "FOV":
+		comp, exists := world.GetComponent(entityID, components.FOV)
+		if !exists {
+			return 0
+		}
+		fov := comp.(*components.FOVComponent)
+		switch effect.Target.Property {
+		case "Range":
+			return float64(fov.Range)
+		case "LightRange":
+			return float64(fov.LightRange)
+		case "SightLimit":
+			return float64(fov.SightLimit)
+		}
+	}
+	return 0
+}
+
 // applyEffect applies a single effect to an entity
 func (s *EffectsSystem) applyEffect(world *ecs.World, entityID ecs.EntityID, effect components.GameEffect) {
 	// Get the target component based on the effect's target info
@@ -320,6 +430,9 @@ func (s *EffectsSystem) applyEffect(world *ecs.World, entityID ecs.EntityID, eff
 			if stats, ok := comp.(*components.StatsComponent); ok {
 				// Calculate the effect value, handling dice roll notation
 				value := s.calculateEffectValue(effect.Value)
+				if effect.Operation == components.EffectOpSubtract && effect.Target.Property == "Health" {
+					value = s.mitigateEffectValue(world, entityID, effect, value)
+				}
 
 				// Apply effect based on the target property
 				switch effect.Target.Property {
@@ -363,6 +476,63 @@ func (s *EffectsSystem) applyEffect(world *ecs.World, entityID ecs.EntityID, eff
 					case components.EffectOpSet:
 						stats.Defense = int(value)
 					}
+				case "Evasion":
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						stats.Evasion += int(value)
+					case components.EffectOpSubtract:
+						stats.Evasion -= int(value)
+					case components.EffectOpMultiply:
+						stats.Evasion = int(float64(stats.Evasion) * value)
+					case components.EffectOpSet:
+						stats.Evasion = int(value)
+					}
+				case "Accuracy":
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						stats.Accuracy += int(value)
+					case components.EffectOpSubtract:
+						stats.Accuracy -= int(value)
+					case components.EffectOpMultiply:
+						stats.Accuracy = int(float64(stats.Accuracy) * value)
+					case components.EffectOpSet:
+						stats.Accuracy = int(value)
+					}
+				case "Stealth":
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						stats.Stealth += int(value)
+					case components.EffectOpSubtract:
+						stats.Stealth -= int(value)
+					case components.EffectOpMultiply:
+						stats.Stealth = int(float64(stats.Stealth) * value)
+					case components.EffectOpSet:
+						stats.Stealth = int(value)
+					}
+				case "Cleave":
+					// How wide a melee swing sweeps past its target
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						stats.Cleave += int(value)
+					case components.EffectOpSubtract:
+						stats.Cleave -= int(value)
+					case components.EffectOpMultiply:
+						stats.Cleave = int(float64(stats.Cleave) * value)
+					case components.EffectOpSet:
+						stats.Cleave = int(value)
+					}
+				case "Recovery":
+					// Action points regained each turn, so more is faster
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						stats.Recovery += int(value)
+					case components.EffectOpSubtract:
+						stats.Recovery -= int(value)
+					case components.EffectOpMultiply:
+						stats.Recovery = int(float64(stats.Recovery) * value)
+					case components.EffectOpSet:
+						stats.Recovery = int(value)
+					}
 				case "MaxHealth":
 					switch effect.Operation {
 					case components.EffectOpAdd:
@@ -386,6 +556,24 @@ func (s *EffectsSystem) applyEffect(world *ecs.World, entityID ecs.EntityID, eff
 							stats.Health = stats.MaxHealth
 						}
 					}
+				case "Damage":
+					// Bonus damage of the effect's school, dealt on every hit
+					if stats.BonusDamage == nil {
+						stats.BonusDamage = make(map[string]int)
+					}
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						stats.BonusDamage[effect.School] += int(value)
+					case components.EffectOpSubtract:
+						stats.BonusDamage[effect.School] -= int(value)
+					case components.EffectOpMultiply:
+						stats.BonusDamage[effect.School] = int(float64(stats.BonusDamage[effect.School]) * value)
+					case components.EffectOpSet:
+						stats.BonusDamage[effect.School] = int(value)
+					}
+					if stats.BonusDamage[effect.School] <= 0 {
+						delete(stats.BonusDamage, effect.School)
+					}
 				}
 			}
 		case "FOV":
@@ -420,12 +608,101 @@ func (s *EffectsSystem) applyEffect(world *ecs.World, entityID ecs.EntityID, eff
 						fov.LightRange = int(value)
 						fov.LightSource = fov.LightRange > 0
 					}
+				case "SightLimit":
+					// Weather and the like cap sight however far the entity could see
+					switch effect.Operation {
+					case components.EffectOpAdd:
+						fov.SightLimit += int(value)
+					case components.EffectOpSubtract:
+						fov.SightLimit -= int(value)
+					case components.EffectOpMultiply:
+						fov.SightLimit = int(float64(fov.SightLimit) * value)
+					case components.EffectOpSet:
+						fov.SightLimit = int(value)
+					}
 				}
 			}
 		}
 	}
 }
 
+// mitigateEffectValue scales a harmful effect by the entity's resistance to its school
+// and logs the difference
+func (s *EffectsSystem) mitigateEffectValue(world *ecs.World, entityID ecs.EntityID, effect components.GameEffect, value float64) float64 {
+	multiplier := resistanceMultiplier(world, entityID, effect.School)
+	if multiplier == 1 {
+		return value
+	}
+
+	mitigated := math.Max(0, value*multiplier)
+	name := getEntityName(world, entityID)
+	switch {
+	case mitigated == 0:
+		GetMessageLog().AddCombat(fmt.Sprintf("%s is immune to %s.", name, effect.School))
+	case multiplier < 1:
+		GetMessageLog().AddCombat(fmt.Sprintf("%s resists the %s, %d reduced to %d.", name, effect.School, int(value), int(mitigated)))
+	default:
+		GetMessageLog().AddCombat(fmt.Sprintf("%s is weak to %s, %d increased to %d.", name, effect.School, int(value), int(mitigated)))
+	}
+	return mitigated
+}
+
+// EffectPreview is what an effect would do to an entity once its resistances are
+// taken into account
+type EffectPreview struct {
+	School     string  // School of the effect, empty for none
+	Multiplier float64 // The entity's resistance multiplier for the school, 1 for none
+	Value      float64 // Expected size of the effect after resistance, dice averaged
+}
+
+// Immune returns true if the effect would do nothing at all
+func (p EffectPreview) Immune() bool {
+	return p.Multiplier <= 0
+}
+
+// Outcome describes how the entity takes the effect: "immune", "resists" or "weak",
+// or empty when it takes it as it is
+func (p EffectPreview) Outcome() string {
+	switch {
+	case p.Multiplier <= 0:
+		return "immune"
+	case p.Multiplier < 1:
+		return "resists"
+	case p.Multiplier > 1:
+		return "weak"
+	}
+	return ""
+}
+
+// PreviewEffect works out what an effect would do to an entity after its resistances
+// without applying it, so the interface can warn about an immunity before an item is
+// spent on it. Effects without a school are never mitigated.
+func (s *EffectsSystem) PreviewEffect(world *ecs.World, targetID ecs.EntityID, effect components.GameEffect) EffectPreview {
+	multiplier := resistanceMultiplier(world, targetID, effect.School)
+	return EffectPreview{
+		School:     effect.School,
+		Multiplier: multiplier,
+		Value:      effect.Strength() * math.Max(0, multiplier),
+	}
+}
+
+// resistanceMultiplier returns how strongly an entity is affected by a school, 1 if it
+// has no resistance to it
+func resistanceMultiplier(world *ecs.World, entityID ecs.EntityID, school string) float64 {
+	if school == "" {
+		return 1
+	}
+	if resComp, exists := world.GetComponent(entityID, components.Resistance); exists {
+		return resComp.(*components.ResistanceComponent).Multiplier(school)
+	}
+	return 1
+}
+
+// isImmune returns true if an entity ignores effects of the school entirely
+func isImmune(world *ecs.World, entityID ecs.EntityID, school string) bool {
+	return resistanceMultiplier(world, entityID, school) <= 0
+}
+
 // calculateEffectValue calculates the effect value, handling dice roll notation
 func (s *EffectsSystem) calculateEffectValue(value interface{}) float64 {
 	switch v := value.(type) {
@@ -445,7 +722,7 @@ func (s *EffectsSystem) calculateEffectValue(value interface{}) float64 {
 			}
 			var total int
 			for i := 0; i < numDice; i++ {
-				total += rand.Intn(diceSize) + 1
+				total += playRNG.Intn(diceSize) + 1
 			}
 			return float64(total)
 		}
@@ -470,8 +747,8 @@ func (s *EffectsSystem) HandleItemEquipped(world *ecs.World, entityID ecs.Entity
 		return fmt.Errorf("item %d has invalid Item component type", itemID)
 	}
 
-	effects, ok := item.Data.([]components.GameEffect)
-	if !ok {
+	effects := itemEffects(world, item, itemID)
+	if len(effects) == 0 {
 		return fmt.Errorf("item %d has invalid effects data", itemID)
 	}
 
@@ -498,27 +775,17 @@ func (s *EffectsSystem) HandleItemEquipped(world *ecs.World, entityID ecs.Entity
 	GetDebugLog().Add(fmt.Sprintf("  - Attack: %d", stats.Attack))
 	GetDebugLog().Add(fmt.Sprintf("  - Defense: %d", stats.Defense))
 
-	// Apply each effect only if it hasn't been applied yet
+	// Equipment effects are ignored if present, keyed on the item. Gems can repeat an
+	// effect of the item itself, so the whole item is checked at once and its gem
+	// effects then stack on top of its own.
+	for _, existing := range effectComponent.Effects {
+		if existing.Type == components.EffectTypeEquipment && existing.Source == itemID {
+			return nil
+		}
+	}
 	for _, effect := range effects {
-		if effect.Type == components.EffectTypeEquipment {
-			// Check if this effect is already applied
-			isDuplicate := false
-			for _, existing := range effectComponent.Effects {
-				if existing.Type == effect.Type &&
-					existing.Operation == effect.Operation &&
-					existing.Target.Component == effect.Target.Component &&
-					existing.Target.Property == effect.Target.Property &&
-					existing.Source == effect.Source {
-					isDuplicate = true
-					break
-				}
-			}
-
-			if !isDuplicate {
-				s.applyEffect(world, entityID, effect)
-				// Add the effect to the component to track it
-				effectComponent.Effects = append(effectComponent.Effects, effect)
-			}
+		if effect.Type == components.EffectTypeEquipment && effectComponent.AddEffect(effect) {
+			s.applyEffect(world, entityID, effect)
 		}
 	}
 
@@ -544,8 +811,8 @@ func (s *EffectsSystem) HandleItemUnequipped(world *ecs.World, entityID ecs.Enti
 		return fmt.Errorf("item %d has invalid Item component type", itemID)
 	}
 
-	effects, ok := item.Data.([]components.GameEffect)
-	if !ok {
+	effects := itemEffects(world, item, itemID)
+	if len(effects) == 0 {
 		return fmt.Errorf("item %d has invalid effects data", itemID)
 	}
 
@@ -589,6 +856,21 @@ func (s *EffectsSystem) HandleItemUnequipped(world *ecs.World, entityID ecs.Enti
 		}
 	}
 
+	// Stop tracking the item's effects so they apply again if it is re-equipped
+	if effectComp, exists := world.GetComponent(entityID, components.Effect); exists {
+		effectComponent := effectComp.(*components.EffectComponent)
+		remaining := effectComponent.Effects[:0]
+		for _, effect := range effectComponent.Effects {
+			if effect.Type != components.EffectTypeEquipment || effect.Source != itemID {
+				remaining = append(remaining, effect)
+			}
+		}
+		effectComponent.Effects = remaining
+		if len(remaining) == 0 {
+			world.RemoveComponent(entityID, components.Effect)
+		}
+	}
+
 	// Log stats after removal
 	GetDebugLog().Add(fmt.Sprintf("Stats after unequip:"))
 	GetDebugLog().Add(fmt.Sprintf("  - Health: %d/%d", stats.Health, stats.MaxHealth))
@@ -597,3 +879,29 @@ func (s *EffectsSystem) HandleItemUnequipped(world *ecs.World, entityID ecs.Enti
 
 	return nil
 }
+
+// itemEffects returns an item's own effects followed by those of the gems in its sockets.
+// Gem effects are credited to the item so they come and go with it.
+func itemEffects(world *ecs.World, item *components.ItemComponent, itemID ecs.EntityID) []components.GameEffect {
+	var effects []components.GameEffect
+	if own, ok := item.Data.([]components.GameEffect); ok {
+		effects = append(effects, own...)
+	}
+	for _, gemID := range item.SocketedGems {
+		gemComp, exists := world.GetComponent(gemID, components.Item)
+		if !exists {
+			continue
+		}
+		gemEffects, ok := gemComp.(*components.ItemComponent).Data.([]components.GameEffect)
+		if !ok {
+			continue
+		}
+		for _, effect := range gemEffects {
+			effect.Type = components.EffectTypeEquipment
+			effect.Source = itemID
+			effect.Stacking = components.StackingStack
+			effects = append(effects, effect)
+		}
+	}
+	return effects
+}
@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// newBleedWorld sets up the registered effects and monster ability systems, with a
+// monster whose attacks cause bleeding next to the player for it to hit
+func newBleedWorld(t *testing.T) (*testWorld, *EffectsSystem, ecs.EntityID, *components.StatsComponent) {
+	t.Helper()
+	w := newTestWorld(t, 5, 5, 2, 2)
+	stats := &components.StatsComponent{Health: 20, MaxHealth: 20}
+	w.world.AddComponent(w.playerID, components.Stats, stats)
+
+	effects := NewEffectsSystem()
+	w.world.AddSystem(effects)
+	abilities := NewMonsterAbilitySystem()
+	w.world.AddSystem(abilities)
+	effects.Initialize(w.world)
+	abilities.Initialize(w.world)
+
+	monsterID := w.place(1, 2)
+	w.world.AddComponent(monsterID, components.Stats, &components.StatsComponent{Health: 10, MaxHealth: 10, ActionPoints: 3})
+	monsterAbilities := components.NewMonsterAbilityComponent()
+	monsterAbilities.AddAbility(components.MonsterAbilityDef{
+		Name:    "Rend",
+		Trigger: components.TriggerOnAttack,
+		Effects: []components.GameEffect{
+			components.NewGameEffect(components.EffectTypePeriodic, components.EffectOpSubtract, 1.0, 5, monsterID, "Stats", "Health"),
+		},
+	})
+	w.world.AddComponent(monsterID, components.MonsterAbility, monsterAbilities)
+
+	return w, effects, monsterID, stats
+}
+
+func TestAbilityEffectsTickOncePerTurn(t *testing.T) {
+	w, effects, monsterID, stats := newBleedWorld(t)
+	world, defenderID := w.world, w.playerID
+
+	world.EmitEvent(CombatAttackEvent{AttackerID: monsterID, DefenderID: defenderID})
+	if !world.HasComponent(defenderID, components.Effect) {
+		t.Fatal("the attack didn't cause bleeding")
+	}
+	before := stats.Health
+
+	// The same turn reported twice only ticks once
+	world.EmitEvent(TurnCompletedEvent{EntityID: defenderID})
+	world.EmitEvent(TurnCompletedEvent{EntityID: defenderID})
+	if lost := before - stats.Health; lost != 1 {
+		t.Errorf("lost %d health to bleeding in one turn reported twice, want 1", lost)
+	}
+
+	// The next turn ticks again
+	effects.Update(world, 0)
+	world.EmitEvent(TurnCompletedEvent{EntityID: defenderID})
+	if lost := before - stats.Health; lost != 2 {
+		t.Errorf("lost %d health to bleeding over two turns, want 2", lost)
+	}
+}
+
+func TestEffectsTickOncePerTurn(t *testing.T) {
+	w, effects, _, stats := newBleedWorld(t)
+	world, defenderID := w.world, w.playerID
+	effects.ApplyEntityEffects(world, defenderID, []components.GameEffect{
+		components.NewGameEffect(components.EffectTypePeriodic, components.EffectOpSubtract, 2.0, 5, 0, "Stats", "Health"),
+	})
+	before := stats.Health
+
+	for turn := 1; turn <= 3; turn++ {
+		for i := 0; i < 3; i++ {
+			world.EmitEvent(TurnCompletedEvent{EntityID: defenderID})
+		}
+		effects.Update(world, 0)
+		if lost := before - stats.Health; lost != 2*turn {
+			t.Errorf("lost %d health after %d turns reported three times each, want %d", lost, turn, 2*turn)
+		}
+	}
+}
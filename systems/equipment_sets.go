@@ -0,0 +1,118 @@
+package systems
+
+import (
+	"fmt"
+	"maps"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// GetEquipmentSets returns an entity's saved equipment sets, or nil if it has never
+// saved one
+func GetEquipmentSets(world *ecs.World, entityID ecs.EntityID) *components.EquipmentSetsComponent {
+	if comp, exists := world.GetComponent(entityID, components.EquipmentSets); exists {
+		return comp.(*components.EquipmentSetsComponent)
+	}
+	return nil
+}
+
+// SaveEquipmentSet stores what the entity has equipped as one of its sets, named after
+// the main hand weapon
+func (s *EquipmentSystem) SaveEquipmentSet(entityID ecs.EntityID, index int) error {
+	if index < 0 || index >= components.EquipmentSetCount {
+		return fmt.Errorf("no equipment set %d", index+1)
+	}
+	equipComp, exists := s.world.GetComponent(entityID, components.Equipment)
+	if !exists {
+		return fmt.Errorf("entity doesn't have Equipment component")
+	}
+	equipment := equipComp.(*components.EquipmentComponent)
+
+	sets := GetEquipmentSets(s.world, entityID)
+	if sets == nil {
+		sets = &components.EquipmentSetsComponent{}
+		s.world.AddComponent(entityID, components.EquipmentSets, sets)
+	}
+
+	name := fmt.Sprintf("Set %d", index+1)
+	if mainID := equipment.GetEquippedItem(components.SlotMainHand); mainID != 0 {
+		name = s.getItemName(s.world, mainID) + " set"
+	}
+	sets.Sets[index] = components.EquipmentSet{Name: name, Items: maps.Clone(equipment.EquippedItems)}
+	if sets.Sets[index].Items == nil {
+		sets.Sets[index].Items = make(map[components.EquipmentSlot]ecs.EntityID)
+	}
+
+	GetMessageLog().AddItem(fmt.Sprintf("Saved your equipment as %s.", name))
+	return nil
+}
+
+// ApplyEquipmentSet puts on one of the entity's saved sets, slot by slot, through the
+// usual equip and unequip so item effects come and go properly. Slots the set left
+// empty are emptied. Items the entity no longer has are skipped with a message.
+// Returns whether anything changed, which counts as a single turn.
+func (s *EquipmentSystem) ApplyEquipmentSet(entityID ecs.EntityID, index int) (bool, error) {
+	sets := GetEquipmentSets(s.world, entityID)
+	if sets == nil || index < 0 || index >= components.EquipmentSetCount || sets.Sets[index].IsEmpty() {
+		return false, fmt.Errorf("no equipment saved in set %d", index+1)
+	}
+	set := sets.Sets[index]
+
+	equipComp, exists := s.world.GetComponent(entityID, components.Equipment)
+	if !exists {
+		return false, fmt.Errorf("entity doesn't have Equipment component")
+	}
+	equipment := equipComp.(*components.EquipmentComponent)
+
+	var inventory *components.InventoryComponent
+	if invComp, exists := s.world.GetComponent(entityID, components.Inventory); exists {
+		inventory = invComp.(*components.InventoryComponent)
+	}
+	owned := func(itemID ecs.EntityID) bool {
+		return s.world.GetEntity(itemID) != nil &&
+			((inventory != nil && inventory.Contains(itemID)) || s.IsItemEquipped(entityID, itemID))
+	}
+
+	changed := false
+	for _, slot := range components.EquipmentSlots {
+		want := set.Items[slot]
+		current := equipment.GetEquippedItem(slot)
+		if want == current {
+			continue
+		}
+
+		if want == 0 || !owned(want) {
+			if want != 0 {
+				missing := "an item"
+				if s.world.GetEntity(want) != nil {
+					missing = s.getItemName(s.world, want)
+				}
+				GetMessageLog().Add(fmt.Sprintf("You no longer have %s from your %s; skipped.", missing, set.Name))
+				continue
+			}
+			if err := s.UnequipItem(entityID, slot); err == nil {
+				changed = true
+			}
+			continue
+		}
+
+		// An item worn in another slot, like a sword moving between hands, comes off first
+		if s.IsItemEquipped(entityID, want) {
+			s.UnequipItemByID(entityID, want)
+			changed = true
+		}
+		if err := s.EquipItem(entityID, want, slot); err != nil {
+			GetMessageLog().Add(fmt.Sprintf("Couldn't equip %s: %v", s.getItemName(s.world, want), err))
+			continue
+		}
+		changed = true
+	}
+
+	if changed {
+		GetMessageLog().AddItem(fmt.Sprintf("You switch to your %s.", set.Name))
+	} else {
+		GetMessageLog().Add(fmt.Sprintf("You're already wearing your %s.", set.Name))
+	}
+	return changed, nil
+}
@@ -137,6 +137,16 @@ func (s *EquipmentSystem) EquipItem(entityID ecs.EntityID, itemID ecs.EntityID,
 		return fmt.Errorf("invalid Equipment component type")
 	}
 
+	// A two-handed weapon fills both hands, so nothing can share them with it
+	if slot == components.SlotOffHand {
+		if item.TwoHanded {
+			return fmt.Errorf("%s needs both hands and can't be held in the off hand", s.getItemName(s.world, itemID))
+		}
+		if mainID := equipment.GetEquippedItem(components.SlotMainHand); mainID != 0 && s.isTwoHanded(mainID) {
+			return fmt.Errorf("both hands are busy with %s", s.getItemName(s.world, mainID))
+		}
+	}
+
 	// Get the stats component
 	statsComp, exists := s.world.GetComponent(entityID, components.Stats)
 	if !exists {
@@ -172,21 +182,29 @@ func (s *EquipmentSystem) EquipItem(entityID ecs.EntityID, itemID ecs.EntityID,
 		GetMessageLog().Add(fmt.Sprintf("Unequipped previous item from %s slot", slot))
 	}
 
-	// Equip the new item
-	equipment.EquipItem(slot, itemID)
-
-	// Process the item effects
-	if item.Data != nil {
-		if _, ok := item.Data.([]components.GameEffect); ok {
-			// Emit event for effects to be applied
-			s.world.EmitEvent(ItemEquippedEvent{
-				EntityID: entityID,
-				ItemID:   itemID,
-				Slot:     string(slot),
-			})
-		} else {
-			GetMessageLog().Add(fmt.Sprintf("Item data is not []GameEffect but %T", item.Data))
+	// Taking up a two-handed weapon frees the off hand for it
+	if slot == components.SlotMainHand && item.TwoHanded {
+		if offhandID := equipment.GetEquippedItem(components.SlotOffHand); offhandID != 0 {
+			offhandName := s.getItemName(s.world, offhandID)
+			s.UnequipItem(entityID, components.SlotOffHand)
+			GetMessageLog().Add(fmt.Sprintf("You put away %s to take up %s with both hands.", offhandName, s.getItemName(s.world, itemID)))
 		}
+	}
+
+	// Equip the new item; gear in use is no longer junk
+	equipment.EquipItem(slot, itemID)
+	item.Junk = false
+
+	// Process the item effects, including those of socketed gems
+	if len(itemEffects(s.world, item, itemID)) > 0 {
+		// Emit event for effects to be applied
+		s.world.EmitEvent(ItemEquippedEvent{
+			EntityID: entityID,
+			ItemID:   itemID,
+			Slot:     string(slot),
+		})
+	} else if item.Data != nil {
+		GetMessageLog().Add(fmt.Sprintf("Item data is not []GameEffect but %T", item.Data))
 	} else {
 		GetMessageLog().Add(fmt.Sprintf("Item %s has no effects data", s.getItemName(s.world, itemID)))
 	}
@@ -204,39 +222,145 @@ func (s *EquipmentSystem) EquipItem(entityID ecs.EntityID, itemID ecs.EntityID,
 	return nil
 }
 
-// EquipItemAuto equips an item to the appropriate slot based on its type
+// EquipItemAuto equips an item to the appropriate slot based on its type. A one-handed
+// weapon goes to the off hand, for dual wielding, when the main hand already holds a
+// one-handed weapon and the off hand is free.
 func (s *EquipmentSystem) EquipItemAuto(entityID, itemID ecs.EntityID) error {
+	slot, err := s.SlotForItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	if slot == components.SlotMainHand && !s.isTwoHanded(itemID) {
+		if equipComp, exists := s.world.GetComponent(entityID, components.Equipment); exists {
+			equipment := equipComp.(*components.EquipmentComponent)
+			mainID := equipment.GetEquippedItem(components.SlotMainHand)
+			if mainID != 0 && mainID != itemID && !s.isTwoHanded(mainID) && !equipment.IsSlotOccupied(components.SlotOffHand) {
+				slot = components.SlotOffHand
+			}
+		}
+	}
+
+	// Equip to the determined slot
+	return s.EquipItem(entityID, itemID, slot)
+}
+
+// SlotForItem determines the equipment slot an item goes into based on its type
+func (s *EquipmentSystem) SlotForItem(itemID ecs.EntityID) (components.EquipmentSlot, error) {
 	// Get the item component
 	itemComp, exists := s.world.GetComponent(itemID, components.Item)
 	if !exists {
-		return fmt.Errorf("item doesn't have Item component")
+		return "", fmt.Errorf("item doesn't have Item component")
 	}
 	item, ok := itemComp.(*components.ItemComponent)
 	if !ok {
-		return fmt.Errorf("item component is not of type *ItemComponent")
+		return "", fmt.Errorf("item component is not of type *ItemComponent")
 	}
 
-	// Determine the appropriate slot based on item type
-	var slot components.EquipmentSlot
 	switch item.ItemType {
 	case "weapon":
-		slot = components.SlotMainHand
+		return components.SlotMainHand, nil
 	case "armor":
-		slot = components.SlotBody
+		return components.SlotBody, nil
 	case "shield":
-		slot = components.SlotOffHand
+		return components.SlotOffHand, nil
 	case "headgear":
-		slot = components.SlotHead
+		return components.SlotHead, nil
 	case "boots":
-		slot = components.SlotFeet
+		return components.SlotFeet, nil
 	case "accessory":
-		slot = components.SlotAccessory
+		return components.SlotAccessory, nil
 	default:
-		return fmt.Errorf("item has unknown type: %s", item.ItemType)
+		return "", fmt.Errorf("item has unknown type: %s", item.ItemType)
 	}
+}
 
-	// Equip to the determined slot
-	return s.EquipItem(entityID, itemID, slot)
+// isTwoHanded reports whether an item is a weapon that takes both hands
+func (s *EquipmentSystem) isTwoHanded(itemID ecs.EntityID) bool {
+	itemComp, exists := s.world.GetComponent(itemID, components.Item)
+	return exists && itemComp.(*components.ItemComponent).TwoHanded
+}
+
+// CompareWithEquipped returns the per-stat difference between an item and whatever
+// the entity currently has equipped in the same slot. Positive values mean the
+// item is better. Only flat Stats modifiers are compared.
+func (s *EquipmentSystem) CompareWithEquipped(entityID, itemID ecs.EntityID) (map[string]float64, error) {
+	slot, err := s.SlotForItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := itemStatModifiers(s.world, itemID)
+
+	if equipComp, exists := s.world.GetComponent(entityID, components.Equipment); exists {
+		equipment := equipComp.(*components.EquipmentComponent)
+		if equippedID := equipment.GetEquippedItem(slot); equippedID != 0 && equippedID != itemID {
+			for property, value := range itemStatModifiers(s.world, equippedID) {
+				delta[property] -= value
+			}
+		}
+	}
+
+	return delta, nil
+}
+
+// IsUpgrade returns true if the item's slot is empty or the item is strictly better
+// than the equipped one: no stat gets worse and at least one improves
+func (s *EquipmentSystem) IsUpgrade(entityID, itemID ecs.EntityID) bool {
+	slot, err := s.SlotForItem(itemID)
+	if err != nil {
+		return false
+	}
+
+	equipComp, exists := s.world.GetComponent(entityID, components.Equipment)
+	if !exists {
+		return false
+	}
+	if equipComp.(*components.EquipmentComponent).GetEquippedItem(slot) == 0 {
+		return true
+	}
+
+	delta, err := s.CompareWithEquipped(entityID, itemID)
+	if err != nil {
+		return false
+	}
+
+	improved := false
+	for _, value := range delta {
+		if value < 0 {
+			return false
+		}
+		if value > 0 {
+			improved = true
+		}
+	}
+	return improved
+}
+
+// itemStatModifiers sums an item's flat Stats modifiers by property
+func itemStatModifiers(world *ecs.World, itemID ecs.EntityID) map[string]float64 {
+	modifiers := make(map[string]float64)
+
+	itemComp, exists := world.GetComponent(itemID, components.Item)
+	if !exists {
+		return modifiers
+	}
+	for _, effect := range itemEffects(world, itemComp.(*components.ItemComponent), itemID) {
+		if effect.Target.Component != "Stats" {
+			continue
+		}
+		value, ok := effect.Value.(float64)
+		if !ok {
+			continue
+		}
+		switch effect.Operation {
+		case components.EffectOpAdd:
+			modifiers[effect.Target.Property] += value
+		case components.EffectOpSubtract:
+			modifiers[effect.Target.Property] -= value
+		}
+	}
+	return modifiers
 }
 
 // IsItemEquipped checks if an item is already equipped by the entity
@@ -337,11 +461,11 @@ func (s *EquipmentSystem) UnequipItem(entityID ecs.EntityID, slot components.Equ
 	}
 
 	// Get the item component to access its effects
-	itemComp, exists := s.world.GetComponent(entityID, components.Item)
+	itemComp, exists := s.world.GetComponent(itemID, components.Item)
 	if !exists {
 		return fmt.Errorf("equipped item lacks Item component")
 	}
-	item := itemComp.(components.ItemComponent)
+	item := itemComp.(*components.ItemComponent)
 
 	// Remove effects if the item has any
 	if item.Data != nil {
@@ -363,6 +487,9 @@ func (s *EquipmentSystem) UnequipItem(entityID ecs.EntityID, slot components.Equ
 	// Unequip the item
 	equipment.UnequipItem(slot)
 
+	// Items equipped straight from the ground have nowhere to go yet
+	s.stowUnequippedItem(entityID, itemID)
+
 	// Log stats after unequip
 	GetDebugLog().Add(fmt.Sprintf("Stats after unequip:"))
 	GetDebugLog().Add(fmt.Sprintf("  - Health: %d/%d", stats.Health, stats.MaxHealth))
@@ -373,6 +500,27 @@ func (s *EquipmentSystem) UnequipItem(entityID ecs.EntityID, slot components.Equ
 	return nil
 }
 
+// stowUnequippedItem puts an unequipped item into the entity's inventory, or drops
+// it at the entity's feet if it isn't already carried and the inventory is full
+func (s *EquipmentSystem) stowUnequippedItem(entityID, itemID ecs.EntityID) {
+	if s.world.HasComponent(itemID, components.Position) {
+		return
+	}
+
+	if invComp, exists := s.world.GetComponent(entityID, components.Inventory); exists {
+		inventory := invComp.(*components.InventoryComponent)
+		if inventory.Contains(itemID) || inventory.AddItem(itemID) {
+			return
+		}
+	}
+
+	if posComp, exists := s.world.GetComponent(entityID, components.Position); exists {
+		pos := posComp.(*components.PositionComponent)
+		s.world.AddComponent(itemID, components.Position, &components.PositionComponent{X: pos.X, Y: pos.Y})
+		GetMessageLog().Add(fmt.Sprintf("You drop %s.", s.getItemName(s.world, itemID)))
+	}
+}
+
 // RemoveAllEquipmentEffects removes all effects from all equipped items for an entity
 func (s *EquipmentSystem) RemoveAllEquipmentEffects(entityID ecs.EntityID) error {
 	// Get equipment component
@@ -434,21 +582,111 @@ func (s *EquipmentSystem) RemoveEquipmentEffects(entityID ecs.EntityID, slot com
 
 	item := itemComp.(*components.ItemComponent)
 
-	// Remove effects of the item
-	if item.Data != nil {
-		if _, ok := item.Data.([]components.GameEffect); ok {
-			// Emit event for effects to be removed
-			s.world.EmitEvent(ItemUnequippedEvent{
-				EntityID: entityID,
-				ItemID:   itemID,
-				Slot:     string(slot),
-			})
-		}
+	// Remove effects of the item and its gems
+	if len(itemEffects(s.world, item, itemID)) > 0 {
+		// Emit event for effects to be removed
+		s.world.EmitEvent(ItemUnequippedEvent{
+			EntityID: entityID,
+			ItemID:   itemID,
+			Slot:     string(slot),
+		})
 	}
 
 	return true
 }
 
+// SocketGem moves a gem from the entity's inventory into a free socket of an item. If the
+// item is equipped its effects are reapplied so the gem takes effect at once.
+func (s *EquipmentSystem) SocketGem(entityID, itemID, gemID ecs.EntityID) error {
+	itemComp, exists := s.world.GetComponent(itemID, components.Item)
+	if !exists {
+		return fmt.Errorf("item doesn't have Item component")
+	}
+	item := itemComp.(*components.ItemComponent)
+	if item.FreeSockets() <= 0 {
+		return fmt.Errorf("%s has no free sockets", s.getItemName(s.world, itemID))
+	}
+
+	gemComp, exists := s.world.GetComponent(gemID, components.Item)
+	if !exists || gemComp.(*components.ItemComponent).ItemType != "gem" {
+		return fmt.Errorf("%s is not a gem", s.getItemName(s.world, gemID))
+	}
+
+	invComp, exists := s.world.GetComponent(entityID, components.Inventory)
+	if !exists || !invComp.(*components.InventoryComponent).Contains(gemID) {
+		return fmt.Errorf("you aren't carrying %s", s.getItemName(s.world, gemID))
+	}
+
+	s.refreshItemEffects(entityID, itemID, func() {
+		invComp.(*components.InventoryComponent).RemoveItem(gemID)
+		item.SocketedGems = append(item.SocketedGems, gemID)
+	})
+
+	GetMessageLog().Add(fmt.Sprintf("You set the %s into the %s.", s.getItemName(s.world, gemID), s.getItemName(s.world, itemID)))
+	return nil
+}
+
+// UnsocketGem takes the most recently socketed gem out of an item and returns it to the
+// entity's inventory
+func (s *EquipmentSystem) UnsocketGem(entityID, itemID ecs.EntityID) error {
+	itemComp, exists := s.world.GetComponent(itemID, components.Item)
+	if !exists {
+		return fmt.Errorf("item doesn't have Item component")
+	}
+	item := itemComp.(*components.ItemComponent)
+	if len(item.SocketedGems) == 0 {
+		return fmt.Errorf("%s has no gems to remove", s.getItemName(s.world, itemID))
+	}
+
+	invComp, exists := s.world.GetComponent(entityID, components.Inventory)
+	if !exists {
+		return fmt.Errorf("entity doesn't have Inventory component")
+	}
+	inventory := invComp.(*components.InventoryComponent)
+	if inventory.IsFull() {
+		return fmt.Errorf("your inventory is full")
+	}
+
+	gemID := item.SocketedGems[len(item.SocketedGems)-1]
+	s.refreshItemEffects(entityID, itemID, func() {
+		item.SocketedGems = item.SocketedGems[:len(item.SocketedGems)-1]
+		inventory.AddItem(gemID)
+	})
+
+	GetMessageLog().Add(fmt.Sprintf("You pry the %s out of the %s.", s.getItemName(s.world, gemID), s.getItemName(s.world, itemID)))
+	return nil
+}
+
+// refreshItemEffects removes an equipped item's effects, runs change and applies the
+// item's effects again, so a change to its sockets reaches the wearer
+func (s *EquipmentSystem) refreshItemEffects(entityID, itemID ecs.EntityID, change func()) {
+	slot := components.EquipmentSlot("")
+	if equipComp, exists := s.world.GetComponent(entityID, components.Equipment); exists {
+		for equippedSlot, equippedID := range equipComp.(*components.EquipmentComponent).EquippedItems {
+			if equippedID == itemID {
+				slot = equippedSlot
+				break
+			}
+		}
+	}
+	if slot == "" {
+		change()
+		return
+	}
+
+	s.RemoveEquipmentEffects(entityID, slot)
+	change()
+
+	itemComp, _ := s.world.GetComponent(itemID, components.Item)
+	if len(itemEffects(s.world, itemComp.(*components.ItemComponent), itemID)) > 0 {
+		s.world.EmitEvent(ItemEquippedEvent{
+			EntityID: entityID,
+			ItemID:   itemID,
+			Slot:     string(slot),
+		})
+	}
+}
+
 // AddItemEffect adds an effect to an entity with the given parameters
 func (s *EquipmentSystem) AddItemEffect(entityID ecs.EntityID, effect components.GameEffect) error {
 	// Get or create the equipment component
@@ -2,6 +2,7 @@
 package systems
 
 import (
+	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
 )
 
@@ -24,6 +25,14 @@ const (
 	EventExamine           ecs.EventType = "examine"
 	EventGameOver          ecs.EventType = "game_over"
 	EventCombatAttack      ecs.EventType = "combat_attack"
+	EventEntityRevealed    ecs.EventType = "entity_revealed"
+	EventMechanism         ecs.EventType = "mechanism"
+	EventSound             ecs.EventType = "sound"
+	EventTutorialHint      ecs.EventType = "tutorial_hint"
+	EventNoise             ecs.EventType = "noise"
+	EventMapMutated        ecs.EventType = "map_mutated"
+	EventAIStateChanged    ecs.EventType = "ai_state_changed"
+	EventFloorCleared      ecs.EventType = "floor_cleared"
 )
 
 // Effect type constants
@@ -310,3 +319,97 @@ type CombatAttackEvent struct {
 func (e CombatAttackEvent) Type() ecs.EventType {
 	return EventCombatAttack
 }
+
+// EntityRevealedEvent is emitted when an enemy comes into the player's view
+type EntityRevealedEvent struct {
+	EntityID ecs.EntityID // Entity that became visible
+}
+
+// Type returns the event type
+func (e EntityRevealedEvent) Type() ecs.EventType {
+	return EventEntityRevealed
+}
+
+// MechanismEvent is emitted when a lever or switch is activated
+type MechanismEvent struct {
+	MechanismID ecs.EntityID // The lever that was pulled
+	ActivatorID ecs.EntityID // Entity that pulled it
+	Active      bool         // Whether the mechanism is now on
+}
+
+// Type returns the event type
+func (e MechanismEvent) Type() ecs.EventType {
+	return EventMechanism
+}
+
+// SoundEvent requests a sound effect to be played
+type SoundEvent struct {
+	Name string // Sound name, resolved to a file in assets/audio
+}
+
+// Type returns the event type
+func (e SoundEvent) Type() ecs.EventType {
+	return EventSound
+}
+
+// TutorialHintEvent is emitted when the tutorial shows a hint
+type TutorialHintEvent struct {
+	HintID  string // ID of the hint from the theme
+	Message string // Text of the hint
+}
+
+// Type returns the event type
+func (e TutorialHintEvent) Type() ecs.EventType {
+	return EventTutorialHint
+}
+
+// NoiseEvent is emitted when something loud happens on a map. Monsters in earshot
+// that aren't already hunting go to see what it was.
+type NoiseEvent struct {
+	MapID  ecs.EntityID // Map the noise was made on
+	X, Y   int          // Where the noise came from
+	Radius int          // How far away it can be heard in tiles
+}
+
+// Type returns the event type
+func (e NoiseEvent) Type() ecs.EventType {
+	return EventNoise
+}
+
+// MapMutatedEvent is emitted when tiles of a map change during play, such as a gate
+// opening or a prop that blocked the way being broken. Anything that planned a route
+// over the map plans it again.
+type MapMutatedEvent struct {
+	MapID ecs.EntityID // Map that changed
+	Tiles []Point      // Tiles whose walkability may have changed
+}
+
+// Type returns the event type
+func (e MapMutatedEvent) Type() ecs.EventType {
+	return EventMapMutated
+}
+
+// AIStateChangedEvent is emitted when a monster's AI moves to a new state during its
+// turn, such as spotting its target or breaking to flee
+type AIStateChangedEvent struct {
+	EntityID ecs.EntityID       // Monster whose state changed
+	From     components.AIState // State it was in before its turn
+	To       components.AIState // State it ended its turn in
+}
+
+// Type returns the event type
+func (e AIStateChangedEvent) Type() ecs.EventType {
+	return EventAIStateChanged
+}
+
+// FloorClearedEvent is emitted when the last hostile on the active dungeon floor is
+// gone. A floor that gains monsters again can be cleared, and emit this, again.
+type FloorClearedEvent struct {
+	MapID ecs.EntityID // Floor that was cleared
+	Level int          // Its depth
+}
+
+// Type returns the event type
+func (e FloorClearedEvent) Type() ecs.EventType {
+	return EventFloorCleared
+}
@@ -0,0 +1,167 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// FloorClearReward is what the player gets for clearing a floor of hostiles
+type FloorClearReward string
+
+const (
+	ClearRewardNone   FloorClearReward = "none"   // Only the message
+	ClearRewardXP     FloorClearReward = "xp"     // FloorClearXPPerLevel XP for each level of depth
+	ClearRewardHeal   FloorClearReward = "heal"   // Health restored to full
+	ClearRewardStairs FloorClearReward = "stairs" // The floor's unfound stairs revealed
+)
+
+// FloorClearXPPerLevel is the XP a cleared floor is worth for each level of depth
+const FloorClearXPPerLevel = 10
+
+// floorClear tracks whether a floor has been cleared
+type floorClear struct {
+	hadHostiles bool // Hostiles have been seen on the floor, so clearing it means something
+	cleared     bool // Every hostile is gone; new arrivals undo it
+}
+
+// FloorClearSystem notices when every hostile on the active dungeon floor is dead,
+// announces it with a FloorClearedEvent and rewards the player. Only monsters whose
+// map context is the active floor count. A cleared floor that gains monsters again,
+// such as from reinforcements, can be cleared again.
+type FloorClearSystem struct {
+	floors      map[ecs.EntityID]*floorClear // Clear state of each floor seen, by map
+	reward      FloorClearReward
+	initialized bool
+}
+
+// NewFloorClearSystem creates a new floor clear system
+func NewFloorClearSystem() *FloorClearSystem {
+	return &FloorClearSystem{
+		floors: make(map[ecs.EntityID]*floorClear),
+		reward: ClearRewardXP,
+	}
+}
+
+// SetReward sets what the player gets for clearing a floor
+func (s *FloorClearSystem) SetReward(reward FloorClearReward) {
+	s.reward = reward
+}
+
+// Initialize sets up event listeners
+func (s *FloorClearSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Kills land during the player's turn, so the count is checked once it is over
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.checkActiveFloor(world)
+	})
+
+	// A floor that's gone takes its clear state with it
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		delete(s.floors, event.(ecs.EntityRemovedEvent).EntityID)
+	})
+
+	s.initialized = true
+}
+
+// Update does nothing; floors are checked as turns complete
+func (s *FloorClearSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// IsCleared returns whether a floor has been cleared of hostiles and stayed clear
+func (s *FloorClearSystem) IsCleared(mapID ecs.EntityID) bool {
+	floor, exists := s.floors[mapID]
+	return exists && floor.cleared
+}
+
+// checkActiveFloor counts the hostiles left on the active dungeon floor and marks it
+// cleared when the last one is gone
+func (s *FloorClearSystem) checkActiveFloor(world *ecs.World) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	playerID := playerEntities[0].ID
+	mapID := getEntityMapID(world, playerID)
+	level, isDungeon := dungeonLevel(world, mapID)
+	if !isDungeon {
+		return
+	}
+
+	floor, exists := s.floors[mapID]
+	if !exists {
+		floor = &floorClear{}
+		s.floors[mapID] = floor
+	}
+
+	if countHostiles(world, mapID) > 0 {
+		if floor.cleared {
+			GetMessageLog().AddAlert("You hear movement. The floor is no longer clear.")
+		}
+		floor.hadHostiles = true
+		floor.cleared = false
+		return
+	}
+	if !floor.hadHostiles || floor.cleared {
+		return
+	}
+
+	floor.cleared = true
+	GetMessageLog().AddAlert("Floor cleared! Nothing hostile stirs here any more.")
+	s.grantReward(world, playerID, mapID, level)
+	world.EmitEvent(FloorClearedEvent{MapID: mapID, Level: level})
+}
+
+// grantReward gives the player the reward for clearing a floor
+func (s *FloorClearSystem) grantReward(world *ecs.World, playerID, mapID ecs.EntityID, level int) {
+	switch s.reward {
+	case ClearRewardXP:
+		if statsComp, exists := world.GetComponent(playerID, components.Stats); exists {
+			xp := FloorClearXPPerLevel * max(level, 1)
+			statsComp.(*components.StatsComponent).Exp += xp
+			GetMessageLog().AddAlert(fmt.Sprintf("You gained %d XP for clearing the floor!", xp))
+		}
+	case ClearRewardHeal:
+		if statsComp, exists := world.GetComponent(playerID, components.Stats); exists {
+			stats := statsComp.(*components.StatsComponent)
+			if stats.Health < stats.MaxHealth {
+				stats.Health = stats.MaxHealth
+				GetMessageLog().AddAlert("With the danger past you catch your breath, fully healed.")
+			}
+		}
+	case ClearRewardStairs:
+		if mapComp, exists := world.GetComponent(mapID, components.MapComponentID); exists {
+			if revealStairs(mapComp.(*components.MapComponent)) > 0 {
+				GetMessageLog().AddAlert("In the quiet you can work out where this floor's stairs must be.")
+			}
+		}
+	}
+}
+
+// dungeonLevel returns the depth of a map, and false if it isn't a dungeon floor
+func dungeonLevel(world *ecs.World, mapID ecs.EntityID) (int, bool) {
+	mapTypeComp, exists := world.GetComponent(mapID, components.MapType)
+	if !exists {
+		return 0, false
+	}
+	mapType := mapTypeComp.(*components.MapTypeComponent)
+	return mapType.Level, mapType.MapType == "dungeon"
+}
+
+// countHostiles returns how many hostiles have the map as their map context
+func countHostiles(world *ecs.World, mapID ecs.EntityID) int {
+	count := 0
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) == mapID {
+			count++
+		}
+	}
+	return count
+}
@@ -1,18 +1,47 @@
 package systems
 
 import (
+	"fmt"
 	"math"
 
 	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
 )
 
+// DefaultStairsRevealAt is the explored fraction of a floor past which its unfound
+// stairs are revealed
+const DefaultStairsRevealAt = 0.9
+
+// floorExploration counts how much of a floor's open ground the player has seen
+type floorExploration struct {
+	open     int  // Tiles that aren't walls
+	explored int  // Of those, tiles the player has seen
+	revealed bool // Whether the floor's stairs have been revealed
+}
+
 // FOVSystem handles field of vision calculations
-type FOVSystem struct{}
+type FOVSystem struct {
+	visibleEnemies map[ecs.EntityID]bool // Enemies that were in view after the last update
+	exploration    map[ecs.EntityID]*floorExploration
+	stairsReveal   bool    // Whether unfound stairs are revealed once most of a floor is explored
+	stairsRevealAt float64 // Explored fraction that reveals them
+}
 
 // NewFOVSystem creates a new FOV system
 func NewFOVSystem() *FOVSystem {
-	return &FOVSystem{}
+	return &FOVSystem{
+		visibleEnemies: make(map[ecs.EntityID]bool),
+		exploration:    make(map[ecs.EntityID]*floorExploration),
+		stairsReveal:   true,
+		stairsRevealAt: DefaultStairsRevealAt,
+	}
+}
+
+// SetStairsReveal sets whether the stairs of a floor are revealed once the given
+// fraction of it has been explored
+func (s *FOVSystem) SetStairsReveal(enabled bool, threshold float64) {
+	s.stairsReveal = enabled
+	s.stairsRevealAt = threshold
 }
 
 // Update calculates FOV for entities with FOV components
@@ -64,6 +93,9 @@ func (s *FOVSystem) Update(world *ecs.World, dt float64) {
 				mapComp.Explored[y][x] = true
 			}
 		}
+
+		// Bad weather and darkness limit how far the player can see
+		s.applySurfaceSight(world, mapComp)
 		return
 	}
 
@@ -99,19 +131,153 @@ func (s *FOVSystem) Update(world *ecs.World, dt float64) {
 		}
 
 		// Calculate visibility
-		s.calculateFOV(world, mapComp, pos.X, pos.Y, fov.Range)
+		sightRange := fov.Range
+		if fov.SightLimit > 0 {
+			sightRange = min(sightRange, fov.SightLimit)
+		}
+		s.calculateFOV(world, mapComp, pos.X, pos.Y, sightRange)
 
-		// If this entity is a player, mark visible tiles as explored
-		if entity.HasTag("player") {
+		// Whatever the player or a light shows is explored
+		if entity.HasTag("player") || fov.LightSource {
+			floor := s.floorExploration(activeMap.ID, mapComp)
 			for y := 0; y < mapComp.Height; y++ {
 				for x := 0; x < mapComp.Width; x++ {
-					if mapComp.Visible[y][x] {
+					if mapComp.Visible[y][x] && !mapComp.Explored[y][x] {
 						mapComp.Explored[y][x] = true
+						if !mapComp.BlocksMovement(x, y) {
+							floor.explored++
+						}
 					}
 				}
 			}
+			s.checkStairsReveal(mapComp, floor)
+		}
+	}
+
+	s.emitRevealedEnemies(world, mapComp, activeMap.ID)
+}
+
+// floorExploration returns the exploration count of a floor, counting it up from the
+// map the first time the floor is seen and keeping it up to date as tiles are explored
+func (s *FOVSystem) floorExploration(mapID ecs.EntityID, mapComp *components.MapComponent) *floorExploration {
+	if floor, exists := s.exploration[mapID]; exists {
+		return floor
+	}
+	floor := &floorExploration{}
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			if mapComp.BlocksMovement(x, y) {
+				continue
+			}
+			floor.open++
+			if mapComp.Explored[y][x] {
+				floor.explored++
+			}
+		}
+	}
+	s.exploration[mapID] = floor
+	return floor
+}
+
+// checkStairsReveal reveals a floor's unfound stairs, once, when the player has explored
+// enough of it, so they aren't left hunting for the last corridor. Revealed stairs
+// count as explored, which puts them on the map and the stairs directions.
+func (s *FOVSystem) checkStairsReveal(mapComp *components.MapComponent, floor *floorExploration) {
+	if !s.stairsReveal || floor.revealed || floor.open == 0 {
+		return
+	}
+	if float64(floor.explored)/float64(floor.open) < s.stairsRevealAt {
+		return
+	}
+	floor.revealed = true
+
+	if found := revealStairs(mapComp); found > 0 {
+		GetMessageLog().AddAlert("You know this floor well now, and can work out where its stairs must be.")
+		GetDebugLog().Add(fmt.Sprintf("FOV: Revealed %d unfound stairs at %d of %d tiles explored", found, floor.explored, floor.open))
+	}
+}
+
+// revealStairs marks a floor's unfound stairs as explored and returns how many there
+// were
+func revealStairs(mapComp *components.MapComponent) int {
+	found := 0
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			tileType := mapComp.Tiles[y][x]
+			if (tileType == components.TileStairsDown || tileType == components.TileStairsUp) && !mapComp.Explored[y][x] {
+				mapComp.Explored[y][x] = true
+				found++
+			}
 		}
 	}
+	return found
+}
+
+// emitRevealedEnemies emits an EntityRevealedEvent for every enemy that has
+// come into view since the last update
+func (s *FOVSystem) emitRevealedEnemies(world *ecs.World, mapComp *components.MapComponent, activeMapID ecs.EntityID) {
+	nowVisible := make(map[ecs.EntityID]bool)
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if !s.entityIsOnActiveMap(world, entity.ID, activeMapID) {
+			continue
+		}
+		posComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		pos := posComp.(*components.PositionComponent)
+		if pos.X < 0 || pos.X >= mapComp.Width || pos.Y < 0 || pos.Y >= mapComp.Height || !mapComp.Visible[pos.Y][pos.X] {
+			continue
+		}
+		nowVisible[entity.ID] = true
+	}
+
+	previouslyVisible := s.visibleEnemies
+	s.visibleEnemies = nowVisible
+	for entityID := range nowVisible {
+		if !previouslyVisible[entityID] {
+			world.EmitEvent(EntityRevealedEvent{EntityID: entityID})
+		}
+	}
+}
+
+// applySurfaceSight restricts world map visibility around the player while the
+// current weather or the time of day reduces their sight range
+func (s *FOVSystem) applySurfaceSight(world *ecs.World, mapComp *components.MapComponent) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	playerID := playerEntities[0].ID
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	// Sight on the surface is normally unlimited. Weather caps it through an effect
+	// on the player's FOV.
+	sightRange := mapComp.Width
+	if fovComp, exists := world.GetComponent(playerID, components.FOV); exists {
+		if limit := fovComp.(*components.FOVComponent).SightLimit; limit > 0 {
+			sightRange = min(sightRange, limit)
+		}
+	}
+	for _, system := range world.GetSystems() {
+		if timeSystem, ok := system.(*TimeSystem); ok {
+			sightRange = timeSystem.SightRange(world, sightRange)
+		}
+	}
+	if sightRange >= mapComp.Width {
+		return
+	}
+
+	for y := 0; y < mapComp.Height; y++ {
+		for x := 0; x < mapComp.Width; x++ {
+			mapComp.Visible[y][x] = false
+		}
+	}
+	s.calculateFOV(world, mapComp, pos.X, pos.Y, sightRange)
 }
 
 // entityIsOnActiveMap checks if an entity is on the active map
@@ -164,8 +330,8 @@ func (s *FOVSystem) castRay(mapComp *components.MapComponent, x, y, radius int,
 		// Mark this tile as visible
 		mapComp.Visible[tileY][tileX] = true
 
-		// Stop if we hit a wall
-		if mapComp.IsWall(tileX, tileY) {
+		// Stop if we hit something opaque
+		if mapComp.BlocksSight(tileX, tileY) {
 			break
 		}
 	}
@@ -186,5 +352,12 @@ func (s *FOVSystem) Initialize(world *ecs.World) {
 			s.Update(w, 0)
 			return
 		}
+
+		// Forget removed enemies so they aren't counted as already seen, and removed maps
+		// since their IDs are reused
+		if removed, ok := event.(ecs.EntityRemovedEvent); ok {
+			delete(s.visibleEnemies, removed.EntityID)
+			delete(s.exploration, removed.EntityID)
+		}
 	})
 }
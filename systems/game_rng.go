@@ -0,0 +1,136 @@
+package systems
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Named random streams. Each part of the game draws from its own stream so that,
+// for example, an extra combat roll never changes how the next dungeon is laid out.
+const (
+	RNGWorld            = "world"              // World map generation
+	RNGPointsOfInterest = "points_of_interest" // Ruins, caches and lairs placed on the world map
+	RNGDungeon          = "dungeon"            // Dungeon layouts and themed features
+	RNGPopulation       = "population"         // Monsters and items placed in dungeons
+	RNGWeather          = "weather"            // Weather rolls on the world map
+	RNGCombat           = "combat"             // Hit and critical rolls
+	RNGSummons          = "summons"            // Monster calls for help and where reinforcements arrive
+	RNGNames            = "names"              // Generated region and station names on the world map
+	RNGPlay             = "play"               // Everything else rolled during play, from wandering to shrine gifts
+)
+
+// playRNG is the stream for the rolls that have no system of their own to hold one.
+// It stays seeded from the clock until a run sets it from its master seed.
+var playRNG = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// SetPlayRNG sets the random source the rolls without a stream of their own are made with
+func SetPlayRNG(rng *rand.Rand) {
+	playRNG = rng
+}
+
+// RNGState is everything needed to rebuild a GameRNG: the master seed and how many
+// values each stream has handed out. Saving it alongside the turn count lets a loaded
+// game carry on with exactly the rolls it would have made.
+type RNGState struct {
+	Seed  int64             `json:"seed"`
+	Draws map[string]uint64 `json:"draws"`
+}
+
+// GameRNG owns every random stream of a run. Streams are derived from the master seed
+// and their name, so the same seed always gives the same streams.
+type GameRNG struct {
+	seed    int64
+	streams map[string]*countingSource
+	rngs    map[string]*rand.Rand
+}
+
+// NewGameRNG creates the random streams for a run from a master seed
+func NewGameRNG(seed int64) *GameRNG {
+	return &GameRNG{
+		seed:    seed,
+		streams: make(map[string]*countingSource),
+		rngs:    make(map[string]*rand.Rand),
+	}
+}
+
+// RestoreGameRNG rebuilds the random streams from a saved state, fast-forwarding each
+// stream past the values it had already handed out
+func RestoreGameRNG(state RNGState) *GameRNG {
+	g := NewGameRNG(state.Seed)
+	for name, draws := range state.Draws {
+		g.Stream(name)
+		g.streams[name].skip(draws)
+	}
+	return g
+}
+
+// Seed returns the master seed
+func (g *GameRNG) Seed() int64 {
+	return g.seed
+}
+
+// StreamSeed returns the seed a stream is derived from, for generators that need a
+// seed rather than a source
+func (g *GameRNG) StreamSeed(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return g.seed ^ int64(hash.Sum64())
+}
+
+// Stream returns the named stream, creating it on first use
+func (g *GameRNG) Stream(name string) *rand.Rand {
+	if rng, exists := g.rngs[name]; exists {
+		return rng
+	}
+	source := &countingSource{source: rand.NewSource(g.StreamSeed(name)).(rand.Source64)}
+	rng := rand.New(source)
+	g.streams[name] = source
+	g.rngs[name] = rng
+	return rng
+}
+
+// State captures the master seed and the position of every stream
+func (g *GameRNG) State() RNGState {
+	state := RNGState{
+		Seed:  g.seed,
+		Draws: make(map[string]uint64, len(g.streams)),
+	}
+	for name, source := range g.streams {
+		state.Draws[name] = source.draws
+	}
+	return state
+}
+
+// countingSource is a random source that counts the values it hands out, so a
+// stream's position can be saved as a single number
+type countingSource struct {
+	source rand.Source64
+	draws  uint64
+}
+
+// Int63 implements rand.Source
+func (c *countingSource) Int63() int64 {
+	c.draws++
+	return c.source.Int63()
+}
+
+// Uint64 implements rand.Source64
+func (c *countingSource) Uint64() uint64 {
+	c.draws++
+	return c.source.Uint64()
+}
+
+// Seed implements rand.Source and starts the count over
+func (c *countingSource) Seed(seed int64) {
+	c.draws = 0
+	c.source.Seed(seed)
+}
+
+// skip advances the source by n values. Int63 and Uint64 both take one step of the
+// underlying generator, so replaying either gets back to the same place.
+func (c *countingSource) skip(n uint64) {
+	for i := uint64(0); i < n; i++ {
+		c.Uint64()
+	}
+}
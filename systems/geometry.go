@@ -0,0 +1,115 @@
+package systems
+
+import (
+	"math"
+
+	"ebiten-rogue/components"
+)
+
+// LinePoints returns every tile on the line from (x1,y1) to (x2,y2), both ends included,
+// using Bresenham's line algorithm
+func LinePoints(x1, y1, x2, y2 int) []Point {
+	points := []Point{}
+
+	dx := int(math.Abs(float64(x2 - x1)))
+	dy := int(math.Abs(float64(y2 - y1)))
+	sx := 1
+	if x1 > x2 {
+		sx = -1
+	}
+	sy := 1
+	if y1 > y2 {
+		sy = -1
+	}
+	err := dx - dy
+
+	for {
+		points = append(points, Point{x1, y1})
+		if x1 == x2 && y1 == y2 {
+			break
+		}
+
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x1 += sx
+		}
+		if e2 < dx {
+			err += dx
+			y1 += sy
+		}
+	}
+
+	return points
+}
+
+// HasLineOfSight returns true if no wall lies between two tiles. The end tile itself
+// may be a wall, so walls can be hit but nothing behind them.
+func HasLineOfSight(mapComp *components.MapComponent, x1, y1, x2, y2 int) bool {
+	points := LinePoints(x1, y1, x2, y2)
+	if len(points) <= 2 {
+		return true
+	}
+	for _, point := range points[1 : len(points)-1] {
+		if mapComp.BlocksSight(point.X, point.Y) {
+			return false
+		}
+	}
+	return true
+}
+
+// TilesInRadius returns the tiles within a circular radius of a center tile that are
+// in line of sight of it, so walls shelter whatever is behind them
+func TilesInRadius(mapComp *components.MapComponent, centerX, centerY, radius int) []Point {
+	var tiles []Point
+	for y := centerY - radius; y <= centerY+radius; y++ {
+		for x := centerX - radius; x <= centerX+radius; x++ {
+			if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height {
+				continue
+			}
+			if math.Hypot(float64(x-centerX), float64(y-centerY)) > float64(radius)+0.5 {
+				continue
+			}
+			if !HasLineOfSight(mapComp, centerX, centerY, x, y) {
+				continue
+			}
+			tiles = append(tiles, Point{x, y})
+		}
+	}
+	return tiles
+}
+
+// ringDirections are the eight neighbouring steps in order around a tile, so that
+// directions next to each other in the list are next to each other on the map
+var ringDirections = [8]Point{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+
+// ArcCells returns the tiles next to (x,y) swept by a swing toward the neighbouring
+// direction (dx,dy), reaching width steps around the ring either side of it. The aimed
+// tile comes first; a width of 4 or more sweeps all eight neighbours.
+func ArcCells(x, y, dx, dy, width int) []Point {
+	aim := -1
+	for i, dir := range ringDirections {
+		if dir.X == dx && dir.Y == dy {
+			aim = i
+			break
+		}
+	}
+	if aim < 0 {
+		return nil
+	}
+
+	width = max(0, min(width, 4))
+	cells := []Point{{x + dx, y + dy}}
+	for step := 1; step <= width; step++ {
+		for _, i := range []int{aim + step, aim - step} {
+			dir := ringDirections[(i+len(ringDirections))%len(ringDirections)]
+			cell := Point{x + dir.X, y + dir.Y}
+			// Both sides of the swing meet behind the attacker
+			if step == 4 && i == aim-step {
+				continue
+			}
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
@@ -0,0 +1,183 @@
+package systems
+
+import (
+	"strings"
+	"unicode"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// GlyphMode is how map tiles and entities are picked from the tileset
+type GlyphMode string
+
+const (
+	GlyphsMixed GlyphMode = "mixed" // Each tile and entity is drawn the way it is defined
+	GlyphsASCII GlyphMode = "ascii" // Plain ASCII characters only, for the classic look
+	GlyphsTiles GlyphMode = "tiles" // Graphical tiles wherever a character has one
+)
+
+// asciiGlyphs stands in for the tileset's pictures and line drawing outside plain
+// ASCII, keyed by code page 437 index
+var asciiGlyphs = map[int]rune{
+	4:   '*', // Diamond, gems
+	15:  '*', // Sun, bombs and substations
+	24:  '&', // Up arrow, trees
+	30:  '^', // Triangle, mountains
+	145: '(', // Container
+	173: '!', // Inverted exclamation, potions
+	176: '.', // Light shade
+	177: ':', // Medium shade
+	178: '%', // Dark shade
+	179: '|', // Box drawing vertical
+	180: '+', // Box drawing tees, corners and crosses
+	191: '+',
+	192: '+',
+	193: '+',
+	194: '+',
+	195: '+',
+	196: '-', // Box drawing horizontal
+	197: '+',
+	205: '=', // Double horizontal
+	217: '+',
+	218: '+',
+	219: '#', // Full block
+	227: 'H', // Pi, stations
+	228: '@', // Sigma, the train
+	232: '0', // Phi, barrels
+	234: '_', // Omega, shrines
+	239: '+', // Intersection, graves
+	247: '~', // Waves, water and rivers
+	250: '.', // Middle dot
+	254: '=', // Square, crates
+}
+
+// asciiTileGlyphs overrides asciiGlyphs for tile types that share a picture with
+// something that reads differently in ASCII, such as walls drawn with railway lines
+var asciiTileGlyphs = map[int]rune{
+	components.TileWallHorizontal:  '#',
+	components.TileWallVertical:    '#',
+	components.TileWallTopLeft:     '#',
+	components.TileWallTopRight:    '#',
+	components.TileWallBottomLeft:  '#',
+	components.TileWallBottomRight: '#',
+	components.TileWallTeeLeft:     '#',
+	components.TileWallTeeRight:    '#',
+	components.TileWallTeeTop:      '#',
+	components.TileWallTeeBottom:   '#',
+	components.TileWallCross:       '#',
+	components.TileGrass:           '"',
+	components.TileDesert:          ':',
+	components.TileSubstation:      'S',
+}
+
+// asciiItemGlyphs are the usual roguelike characters for each item type
+var asciiItemGlyphs = map[string]rune{
+	"armor":         '[',
+	"headgear":      '[',
+	"weapon":        ')',
+	"potion":        '!',
+	"scroll":        '?',
+	"gem":           '*',
+	"bomb":          '0',
+	"first aid":     '+',
+	"component":     '%',
+	"miscellaneous": '&',
+}
+
+// graphicalGlyphs are the tileset pictures drawn for plain characters in tiles mode
+var graphicalGlyphs = map[rune]TileID{
+	'.': NewTileID(10, 15), // Middle dot
+	'#': NewTileID(2, 11),  // Dark shade
+	'=': NewTileID(13, 12), // Double horizontal
+	'~': NewTileID(7, 15),  // Waves
+}
+
+// SetGlyphMode sets how map tiles and entities are picked from the tileset
+func (s *RenderSystem) SetGlyphMode(mode GlyphMode) {
+	s.glyphMode = mode
+}
+
+// mapTile returns the tileset tile to draw a map tile with
+func (s *RenderSystem) mapTile(tileType int, def components.TileDefinition) TileID {
+	// A palette's hazard glyph wins over the glyph mode, so hazards stand out in any
+	if components.GetTileProperties(tileType).IsHazard && s.palette.HazardGlyph != 0 {
+		return s.glyphTile(s.palette.HazardGlyph)
+	}
+	switch s.glyphMode {
+	case GlyphsASCII:
+		if glyph, ok := asciiTileGlyphs[tileType]; ok {
+			return s.glyphTile(glyph)
+		}
+		return s.glyphTile(asciiGlyph(def.UseTilePos, def.TileX, def.TileY, def.Glyph, 0))
+	case GlyphsTiles:
+		if tileID, ok := graphicalGlyphs[def.Glyph]; ok && !def.UseTilePos {
+			return tileID
+		}
+	}
+	if def.UseTilePos {
+		return NewTileID(def.TileX, def.TileY)
+	}
+	return s.glyphTile(def.Glyph)
+}
+
+// entityTile returns the tileset tile to draw an entity with, and whether it is a
+// picture that may be rotated rather than a character
+func (s *RenderSystem) entityTile(world *ecs.World, entityID ecs.EntityID, useTilePos bool, tileX, tileY int, char rune) (TileID, bool) {
+	switch s.glyphMode {
+	case GlyphsASCII:
+		return s.glyphTile(asciiGlyph(useTilePos, tileX, tileY, char, entityASCII(world, entityID))), false
+	case GlyphsTiles:
+		if tileID, ok := graphicalGlyphs[char]; ok && !useTilePos {
+			return tileID, true
+		}
+	}
+	if useTilePos {
+		return NewTileID(tileX, tileY), true
+	}
+	return s.glyphTile(char), false
+}
+
+// glyphTile returns the tileset tile of a character
+func (s *RenderSystem) glyphTile(char rune) TileID {
+	return NewTileID(s.tileset.GetTileCoords(char))
+}
+
+// asciiGlyph picks the plain ASCII character for something defined by a character
+// or a tileset position. Tileset positions are laid out in code page 437 order, so
+// those in the ASCII range are already the character they show; the rest use the
+// fallback if there is one, then the closest character in asciiGlyphs.
+func asciiGlyph(useTilePos bool, tileX, tileY int, char rune, fallback rune) rune {
+	index := int(char)
+	if useTilePos {
+		index = tileY*16 + tileX
+	}
+	if index >= ' ' && index <= '~' {
+		return rune(index)
+	}
+	if fallback != 0 {
+		return fallback
+	}
+	if glyph, ok := asciiGlyphs[index]; ok {
+		return glyph
+	}
+	return '?'
+}
+
+// entityASCII returns the ASCII character an entity should fall back to from what it
+// is, or 0 to use the character closest to its picture
+func entityASCII(world *ecs.World, entityID ecs.EntityID) rune {
+	if isPlayer(world, entityID) {
+		return '@'
+	}
+	if world.HasComponent(entityID, components.AI) {
+		// Monsters go by the first letter of their name
+		for _, r := range strings.TrimSpace(getEntityName(world, entityID)) {
+			return unicode.ToUpper(r)
+		}
+	}
+	if itemComp, exists := world.GetComponent(entityID, components.Item); exists {
+		return asciiItemGlyphs[itemComp.(*components.ItemComponent).ItemType]
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// Grave records how and where a previous character died
+type Grave struct {
+	Name  string   `json:"name"`  // Character name or class
+	Level int      `json:"level"` // Character level at death
+	Cause string   `json:"cause"` // What killed them
+	Depth int      `json:"depth"` // Dungeon floor they died on, 0 for the surface
+	X     int      `json:"x"`
+	Y     int      `json:"y"`
+	Items []string `json:"items"` // Template IDs of what they were carrying
+}
+
+// Epitaph describes the grave, e.g. "Engineer, level 3, killed by Rust Zombie on floor 2"
+func (g *Grave) Epitaph() string {
+	where := "on the surface"
+	if g.Depth > 0 {
+		where = fmt.Sprintf("on floor %d", g.Depth)
+	}
+	return fmt.Sprintf("%s, level %d, killed by %s %s", g.Name, g.Level, g.Cause, where)
+}
+
+// GraveyardSystem remembers the deaths of previous characters between runs, so their
+// graves can turn up on new maps. Nothing is recorded unless a save file is set.
+type GraveyardSystem struct {
+	graves        []*Grave
+	placed        map[*Grave]bool // Graves already put on a map this session
+	characterName string          // Name the current character is buried under
+	savePath      string          // File the graveyard is persisted to, empty to disable
+	maxGraves     int             // Oldest graves are forgotten beyond this many
+	maxItems      int             // Most items a single grave keeps
+	initialized   bool
+}
+
+// NewGraveyardSystem creates a new graveyard system
+func NewGraveyardSystem() *GraveyardSystem {
+	return &GraveyardSystem{
+		placed:        make(map[*Grave]bool),
+		characterName: "Wanderer",
+		maxGraves:     20,
+		maxItems:      10,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *GraveyardSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe(EventDeath, func(event ecs.Event) {
+		deathEvent := event.(DeathEvent)
+		// Practice deaths aren't final, so nobody is buried for them
+		if isPlayer(world, deathEvent.EntityID) && !isPracticeRun(world) {
+			s.recordDeath(world, deathEvent)
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *GraveyardSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// IsEnabled returns whether deaths are being recorded
+func (s *GraveyardSystem) IsEnabled() bool {
+	return s.savePath != ""
+}
+
+// SetCharacterName sets the name the current character will be buried under
+func (s *GraveyardSystem) SetCharacterName(name string) {
+	if name != "" {
+		s.characterName = name
+	}
+}
+
+// PickGrave returns a random grave from around the given depth that hasn't been placed
+// this session, or nil if there is none. The grave is marked as placed.
+func (s *GraveyardSystem) PickGrave(depth int, rng *rand.Rand) *Grave {
+	var candidates []*Grave
+	for _, grave := range s.graves {
+		if s.placed[grave] || grave.Depth <= 0 || abs(grave.Depth-depth) > 1 {
+			continue
+		}
+		candidates = append(candidates, grave)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	grave := candidates[rng.Intn(len(candidates))]
+	s.placed[grave] = true
+	return grave
+}
+
+// recordDeath adds the player's grave and saves the graveyard
+func (s *GraveyardSystem) recordDeath(world *ecs.World, event DeathEvent) {
+	if !s.IsEnabled() {
+		return
+	}
+
+	grave := &Grave{
+		Name:  s.characterName,
+		Level: 1,
+		Cause: "unknown causes",
+	}
+	if event.KillerID != 0 && event.KillerID != event.EntityID {
+		grave.Cause = getEntityName(world, event.KillerID)
+	}
+	if statsComp, exists := world.GetComponent(event.EntityID, components.Stats); exists {
+		grave.Level = statsComp.(*components.StatsComponent).Level
+	}
+	if posComp, exists := world.GetComponent(event.EntityID, components.Position); exists {
+		pos := posComp.(*components.PositionComponent)
+		grave.X, grave.Y = pos.X, pos.Y
+	}
+	if typeComp, exists := world.GetComponent(getEntityMapID(world, event.EntityID), components.MapType); exists {
+		if mapType := typeComp.(*components.MapTypeComponent); mapType.MapType == "dungeon" {
+			grave.Depth = mapType.Level
+		}
+	}
+
+	// Equipped gear is in the inventory too
+	if invComp, exists := world.GetComponent(event.EntityID, components.Inventory); exists {
+		for _, itemID := range invComp.(*components.InventoryComponent).Items {
+			if len(grave.Items) >= s.maxItems {
+				break
+			}
+			if itemComp, exists := world.GetComponent(itemID, components.Item); exists {
+				if templateID := itemComp.(*components.ItemComponent).TemplateID; templateID != "" {
+					grave.Items = append(grave.Items, templateID)
+				}
+			}
+		}
+	}
+
+	s.graves = append(s.graves, grave)
+	if len(s.graves) > s.maxGraves {
+		s.graves = s.graves[len(s.graves)-s.maxGraves:]
+	}
+	s.save()
+}
+
+// LoadFromFile loads a persisted graveyard and keeps saving to the same file, which
+// also turns recording on. A missing file is not an error; the graveyard starts empty.
+func (s *GraveyardSystem) LoadFromFile(path string) error {
+	s.savePath = path
+
+	fileData, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read graveyard: %w", err)
+	}
+
+	var graves []*Grave
+	if err := json.Unmarshal(fileData, &graves); err != nil {
+		return fmt.Errorf("failed to parse graveyard: %w", err)
+	}
+	if len(graves) > s.maxGraves {
+		graves = graves[len(graves)-s.maxGraves:]
+	}
+	s.graves = graves
+	s.placed = make(map[*Grave]bool)
+	return nil
+}
+
+// save writes the graveyard to disk if persistence is enabled
+func (s *GraveyardSystem) save() {
+	if s.savePath == "" {
+		return
+	}
+
+	fileData, err := json.MarshalIndent(s.graves, "", "  ")
+	if err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Failed to encode graveyard: %v", err))
+		return
+	}
+	if err := os.WriteFile(s.savePath, fileData, 0644); err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Failed to save graveyard: %v", err))
+	}
+}
@@ -0,0 +1,133 @@
+package systems
+
+import (
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// Adaptive healing tuning
+const (
+	HealthTrendWindow  = 40   // Turns of player health the trend is averaged over
+	HealthTrendMinimum = 10   // Turns sampled before the trend counts for anything
+	HealthTrendNeutral = 0.6  // Average health fraction at which drops are left alone
+	MinHealingBias     = 0.75 // Default floor on the healing drop weight for a thriving player
+	MaxHealingBias     = 1.5  // Default ceiling on the healing drop weight for a struggling player
+)
+
+// HealingBalanceSystem keeps a rolling average of the player's health and, when
+// adaptive healing is on, biases the healing items in dropped loot by it: a player
+// who has spent a while near death finds them a little more often, one who is
+// thriving a little less. The bias moves by the distance of the average from
+// HealthTrendNeutral and always stays within its bounds. It is off by default.
+type HealingBalanceSystem struct {
+	enabled     bool
+	minBias     float64   // Lowest healing drop weight
+	maxBias     float64   // Highest healing drop weight
+	samples     []float64 // Player health fractions of recent turns, oldest first
+	initialized bool
+}
+
+// NewHealingBalanceSystem creates a new healing balance system, switched off
+func NewHealingBalanceSystem() *HealingBalanceSystem {
+	return &HealingBalanceSystem{
+		minBias: MinHealingBias,
+		maxBias: MaxHealingBias,
+	}
+}
+
+// SetEnabled turns adaptive healing drops on or off
+func (s *HealingBalanceSystem) SetEnabled(enabled bool) {
+	s.enabled = enabled
+}
+
+// IsEnabled returns whether adaptive healing drops are on
+func (s *HealingBalanceSystem) IsEnabled() bool {
+	return s.enabled
+}
+
+// SetBounds sets the lowest and highest weight healing drops can be given. Neither
+// bound crosses 1, so the bias can only ever lean the way the trend points.
+func (s *HealingBalanceSystem) SetBounds(minBias, maxBias float64) {
+	s.minBias = min(max(minBias, 0), 1)
+	s.maxBias = max(maxBias, 1)
+}
+
+// Reset forgets the health trend, for a new game
+func (s *HealingBalanceSystem) Reset() {
+	s.samples = nil
+}
+
+// Initialize sets up event listeners
+func (s *HealingBalanceSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.sample(world)
+	})
+
+	s.initialized = true
+}
+
+// Update does nothing; health is sampled as turns complete
+func (s *HealingBalanceSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// HealthTrend returns the player's average health fraction over the recent turns,
+// and whether enough turns have been seen for it to mean anything
+func (s *HealingBalanceSystem) HealthTrend() (float64, bool) {
+	if len(s.samples) < HealthTrendMinimum {
+		return 0, false
+	}
+	total := 0.0
+	for _, sample := range s.samples {
+		total += sample
+	}
+	return total / float64(len(s.samples)), true
+}
+
+// HealingBias returns the weight healing items get in dropped loot, where other items
+// weigh 1. It is 1 while adaptive healing is off or the trend is still settling.
+func (s *HealingBalanceSystem) HealingBias() float64 {
+	trend, ok := s.HealthTrend()
+	if !s.enabled || !ok {
+		return 1
+	}
+	return min(max(1+(HealthTrendNeutral-trend), s.minBias), s.maxBias)
+}
+
+// sample records the player's health for the turn just completed
+func (s *HealingBalanceSystem) sample(world *ecs.World) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	statsComp, exists := world.GetComponent(playerEntities[0].ID, components.Stats)
+	if !exists {
+		return
+	}
+	stats := statsComp.(*components.StatsComponent)
+	if stats.MaxHealth <= 0 {
+		return
+	}
+
+	s.samples = append(s.samples, min(max(float64(stats.Health)/float64(stats.MaxHealth), 0), 1))
+	if len(s.samples) > HealthTrendWindow {
+		s.samples = s.samples[len(s.samples)-HealthTrendWindow:]
+	}
+}
+
+// healingBias returns the healing drop weight of the world's healing balance system,
+// or 1 if it has none
+func healingBias(world *ecs.World) float64 {
+	for _, system := range world.GetSystems() {
+		if balance, ok := system.(*HealingBalanceSystem); ok {
+			return balance.HealingBias()
+		}
+	}
+	return 1
+}
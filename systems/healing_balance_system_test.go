@@ -0,0 +1,150 @@
+package systems
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+)
+
+// healingFixture is a player whose health is sampled by a healing balance system
+type healingFixture struct {
+	*testWorld
+	balance *HealingBalanceSystem
+	stats   *components.StatsComponent
+}
+
+func newHealingFixture(t *testing.T) *healingFixture {
+	t.Helper()
+	w := newTestWorld(t, 5, 5, 2, 2)
+	stats := &components.StatsComponent{Health: 100, MaxHealth: 100}
+	w.world.AddComponent(w.playerID, components.Stats, stats)
+
+	balance := NewHealingBalanceSystem()
+	w.world.AddSystem(balance)
+	balance.Initialize(w.world)
+	return &healingFixture{testWorld: w, balance: balance, stats: stats}
+}
+
+// play completes turns with the player at a health
+func (f *healingFixture) play(turns, health int) {
+	f.stats.Health = health
+	for i := 0; i < turns; i++ {
+		f.world.EmitEvent(TurnCompletedEvent{})
+	}
+}
+
+func TestHealingBias(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		turns   int
+		health  int
+		check   func(bias float64) bool
+		want    string
+	}{
+		{"off by default", false, HealthTrendWindow, 5, func(b float64) bool { return b == 1 }, "1"},
+		{"trend still settling", true, HealthTrendMinimum - 1, 5, func(b float64) bool { return b == 1 }, "1"},
+		{"neutral health", true, HealthTrendWindow, 60, func(b float64) bool { return math.Abs(b-1) < 1e-9 }, "1"},
+		{"struggling", true, HealthTrendWindow, 30, func(b float64) bool { return b > 1 && b < MaxHealingBias }, "between 1 and the cap"},
+		{"near death", true, HealthTrendWindow, 0, func(b float64) bool { return b == MaxHealingBias }, "the cap"},
+		{"thriving", true, HealthTrendWindow, 100, func(b float64) bool { return b == MinHealingBias }, "the floor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newHealingFixture(t)
+			f.balance.SetEnabled(tt.enabled)
+			f.play(tt.turns, tt.health)
+			if bias := f.balance.HealingBias(); !tt.check(bias) {
+				t.Errorf("HealingBias() = %v, want %s", bias, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealingBiasFollowsRecentTurns(t *testing.T) {
+	f := newHealingFixture(t)
+	f.balance.SetEnabled(true)
+	f.play(HealthTrendWindow, 5)
+	if bias := f.balance.HealingBias(); bias <= 1 {
+		t.Fatalf("HealingBias() after a rough patch = %v, want above 1", bias)
+	}
+
+	// A full window of good health pushes the rough patch out of the trend
+	f.play(HealthTrendWindow, 100)
+	if trend, _ := f.balance.HealthTrend(); trend != 1 {
+		t.Errorf("HealthTrend() = %v, want 1 once the low turns have rolled out", trend)
+	}
+
+	f.balance.Reset()
+	if _, ok := f.balance.HealthTrend(); ok {
+		t.Error("the trend should be gone after Reset")
+	}
+}
+
+func TestHealingBiasBounds(t *testing.T) {
+	f := newHealingFixture(t)
+	f.balance.SetEnabled(true)
+	f.balance.SetBounds(0.9, 1.1)
+	f.play(HealthTrendWindow, 0)
+	if bias := f.balance.HealingBias(); bias != 1.1 {
+		t.Errorf("HealingBias() near death = %v, want the 1.1 cap", bias)
+	}
+	f.play(HealthTrendWindow, 100)
+	if bias := f.balance.HealingBias(); bias != 0.9 {
+		t.Errorf("HealingBias() at full health = %v, want the 0.9 floor", bias)
+	}
+
+	// Bounds never cross 1, so the bias can't lean against the trend
+	f.balance.SetBounds(1.2, 0.8)
+	if bias := f.balance.HealingBias(); bias != 1 {
+		t.Errorf("HealingBias() with inverted bounds at full health = %v, want 1", bias)
+	}
+}
+
+func TestChooseLootFavorsHealingWhenStruggling(t *testing.T) {
+	templates := data.NewEntityTemplateManager()
+	if err := templates.LoadItemTemplatesFromDirectory("../data/items"); err != nil {
+		t.Fatalf("loading item templates: %v", err)
+	}
+	loot := []string{"bandage", "scrap_metal", "copper_wire", "health_potion"}
+
+	// healingShare is the share of drops that heal over many broken props
+	healingShare := func(health int, enabled bool) float64 {
+		f := newHealingFixture(t)
+		f.balance.SetEnabled(enabled)
+		f.play(HealthTrendWindow, health)
+		combat := NewCombatSystem()
+		combat.SetTemplateManager(templates)
+		combat.SetRNG(rand.New(rand.NewSource(3)))
+
+		healing := 0
+		const rolls = 4000
+		for i := 0; i < rolls; i++ {
+			if combat.isHealingItem(combat.chooseLoot(f.world, loot)) {
+				healing++
+			}
+		}
+		return float64(healing) / rolls
+	}
+
+	fixed := healingShare(0, false)
+	struggling := healingShare(0, true)
+	thriving := healingShare(100, true)
+	if fixed < 0.45 || fixed > 0.55 {
+		t.Errorf("healing share with adaptive healing off = %.2f, want about 0.5", fixed)
+	}
+	if struggling <= fixed+0.05 {
+		t.Errorf("healing share while struggling = %.2f, want clearly above %.2f", struggling, fixed)
+	}
+	if thriving >= fixed-0.05 {
+		t.Errorf("healing share while thriving = %.2f, want clearly below %.2f", thriving, fixed)
+	}
+	// Even at the cap, healing never crowds out everything else
+	if maxShare := MaxHealingBias * 2 / (MaxHealingBias*2 + 2); struggling > maxShare+0.03 {
+		t.Errorf("healing share while struggling = %.2f, want at most the capped %.2f", struggling, maxShare)
+	}
+}
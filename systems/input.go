@@ -0,0 +1,104 @@
+package systems
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Every key the game reacts to is read through here rather than straight from ebiten,
+// so that a run's input can be recorded and played back. The held keys are sampled
+// once per tick by BeginInputTick; presses are worked out from one tick to the next.
+var input = &inputState{
+	held:     make(map[ebiten.Key]bool),
+	previous: make(map[ebiten.Key]bool),
+}
+
+// inputState is the keyboard as the game sees it this tick
+type inputState struct {
+	held     map[ebiten.Key]bool
+	previous map[ebiten.Key]bool
+	tick     int // Ticks since the game started
+
+	recording *Replay // Replay the input is being recorded to, if any
+	playing   *Replay // Replay the input is being played back from, if any
+	next      int     // Index of the next recorded input to play back
+}
+
+// BeginInputTick samples the keys held this tick, from the keyboard or from the replay
+// being played back. The game calls it at the start of every update.
+func BeginInputTick() {
+	input.previous = input.held
+	input.held = make(map[ebiten.Key]bool)
+
+	if input.playing != nil {
+		input.playTick()
+	} else {
+		for _, key := range inpututil.AppendPressedKeys(nil) {
+			input.held[key] = true
+		}
+		if input.recording != nil && !maps.Equal(input.held, input.previous) {
+			input.recording.Inputs = append(input.recording.Inputs, ReplayInput{
+				Tick: input.tick,
+				Turn: len(input.recording.Checks),
+				Keys: sortedKeys(input.held),
+			})
+		}
+	}
+	input.tick++
+}
+
+// playTick holds the keys the replay had down at this tick. Recorded inputs only mark
+// changes, so between them the last recorded keys stay held.
+func (s *inputState) playTick() {
+	inputs := s.playing.Inputs
+	for s.next < len(inputs) && inputs[s.next].Tick <= s.tick {
+		s.next++
+	}
+	if s.next > 0 {
+		for _, key := range inputs[s.next-1].Keys {
+			s.held[key] = true
+		}
+	}
+	if s.next == len(inputs) {
+		// Out of recorded input, so the keyboard takes over from the next tick
+		s.playing = nil
+		GetMessageLog().AddAlert("Replay finished. The keyboard is yours again.")
+		GetDebugLog().Add(fmt.Sprintf("Replay finished at tick %d", s.tick))
+	}
+}
+
+// KeyJustPressed returns whether the key went down this tick
+func KeyJustPressed(key ebiten.Key) bool {
+	return input.held[key] && !input.previous[key]
+}
+
+// KeyPressed returns whether the key is held this tick
+func KeyPressed(key ebiten.Key) bool {
+	return input.held[key]
+}
+
+// JustPressedKeys returns the keys that went down this tick, in key order
+func JustPressedKeys() []ebiten.Key {
+	var keys []ebiten.Key
+	for key := range input.held {
+		if !input.previous[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// sortedKeys lists a set of keys in key order, so recordings are stable
+func sortedKeys(set map[ebiten.Key]bool) []ebiten.Key {
+	keys := make([]ebiten.Key, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,316 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// applySelfEffects hands a consumable's effects to the entity using it
+func (s *InventorySystem) applySelfEffects(world *ecs.World, entityID, itemID ecs.EntityID) {
+	itemComp, exists := world.GetComponent(itemID, components.Item)
+	if !exists {
+		return
+	}
+	if _, ok := itemComp.(*components.ItemComponent).Data.([]components.GameEffect); !ok {
+		return
+	}
+
+	// Emit a single effects event for the item
+	world.EmitEvent(EffectsEvent{
+		EntityID:    entityID,
+		EffectType:  "item",
+		Property:    "",  // Not used when applying item effects
+		Value:       nil, // Not used when applying item effects
+		Source:      itemID,
+		DisplayText: fmt.Sprintf("Used %s", s.getItemName(world, itemID)),
+	})
+}
+
+// readScroll reads a scroll from the reader's inventory. Map scrolls act straight away,
+// area scrolls go off at the target picked in targeting mode, entering it first if
+// needed. The scroll is only used up if it took effect.
+func (s *InventorySystem) readScroll(world *ecs.World, readerID, itemID ecs.EntityID, inventory *components.InventoryComponent) bool {
+	itemName := s.getItemName(world, itemID)
+
+	// Scrolls without a scroll effect work on the reader like a potion
+	effect := ""
+	radius := 0
+	var scroll *components.ScrollComponent
+	if scrollComp, exists := world.GetComponent(itemID, components.Scroll); exists {
+		scroll = scrollComp.(*components.ScrollComponent)
+		effect, radius = scroll.Effect, scroll.Radius
+	}
+
+	switch effect {
+	case components.ScrollMagicMapping:
+		if !s.revealMap(world, readerID) {
+			GetMessageLog().AddSystem(fmt.Sprintf("There is nothing here for the %s to reveal.", itemName))
+			return false
+		}
+		GetMessageLog().Add(fmt.Sprintf("You read the %s. The layout of the area floods into your mind.", itemName))
+	case components.ScrollTeleport:
+		if !s.teleport(world, readerID) {
+			GetMessageLog().AddSystem(fmt.Sprintf("The %s finds nowhere to send you.", itemName))
+			return false
+		}
+		GetMessageLog().Add(fmt.Sprintf("You read the %s. The world lurches around you.", itemName))
+	case components.ScrollScan:
+		scanSystem := s.getScanSystem(world)
+		if scanSystem == nil {
+			return false
+		}
+		switch found := scanSystem.Start(world, readerID); {
+		case found < 0:
+			GetMessageLog().AddSystem(fmt.Sprintf("The %s has nothing to scan here.", itemName))
+			return false
+		case found == 0:
+			GetMessageLog().Add(fmt.Sprintf("You feed the %s into your scanner. It picks up no movement.", itemName))
+		default:
+			GetMessageLog().Add(fmt.Sprintf("You feed the %s into your scanner. %d blips light up.", itemName, found))
+		}
+	case components.ScrollArea, components.ScrollChain:
+		targeting := s.getTargetingSystem(world)
+		if targeting == nil {
+			return false
+		}
+		targetID := targeting.GetTarget(world)
+		if targetID == 0 {
+			// Pick a target first, the scroll is kept until it is read at one
+			if !targeting.Begin(world) {
+				GetMessageLog().AddSystem(fmt.Sprintf("There is nothing to read the %s at.", itemName))
+				return false
+			}
+			GetMessageLog().AddSystem(fmt.Sprintf("Choose a target, then read the %s again.", itemName))
+			return false
+		}
+		if effect == components.ScrollChain {
+			if !s.inSight(world, readerID, targetID) {
+				GetMessageLog().AddSystem(fmt.Sprintf("You can't see %s well enough to read the %s at it.", getEntityName(world, targetID), itemName))
+				return false
+			}
+			GetMessageLog().Add(fmt.Sprintf("You read the %s at %s! Lightning leaps from the page!", itemName, getEntityName(world, targetID)))
+			s.castChain(world, readerID, itemID, targetID, scroll)
+			break
+		}
+		GetMessageLog().Add(fmt.Sprintf("You read the %s at %s!", itemName, getEntityName(world, targetID)))
+		s.castArea(world, readerID, itemID, targetID, radius)
+	case components.ScrollFreeze, components.ScrollCool:
+		transform := terrainTransforms[effect]
+		posComp, exists := world.GetComponent(readerID, components.Position)
+		if !exists {
+			return false
+		}
+		pos := posComp.(*components.PositionComponent)
+		if TransformTerrain(world, getEntityMapID(world, readerID), pos.X, pos.Y, radius, transform.from, transform.to, scroll.Duration) == 0 {
+			GetMessageLog().AddSystem(fmt.Sprintf("There is no %s close enough for the %s to work on.", transform.material, itemName))
+			return false
+		}
+		GetMessageLog().Add(fmt.Sprintf("You read the %s. %s", itemName, transform.message))
+	default:
+		s.applySelfEffects(world, readerID, itemID)
+		GetMessageLog().Add(fmt.Sprintf("You read the %s.", itemName))
+	}
+
+	inventory.RemoveItem(itemID)
+	return true
+}
+
+// revealMap marks every tile of the reader's map as explored. Returns false if the
+// map was already fully known.
+func (s *InventorySystem) revealMap(world *ecs.World, entityID ecs.EntityID) bool {
+	mapComp, exists := world.GetComponent(getEntityMapID(world, entityID), components.MapComponentID)
+	if !exists {
+		return false
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	revealed := false
+	for y := 0; y < gameMap.Height; y++ {
+		for x := 0; x < gameMap.Width; x++ {
+			if !gameMap.Explored[y][x] {
+				gameMap.Explored[y][x] = true
+				revealed = true
+			}
+		}
+	}
+	return revealed
+}
+
+// teleport moves an entity to a random open floor tile on its map. Returns false if
+// there is nowhere to go.
+func (s *InventorySystem) teleport(world *ecs.World, entityID ecs.EntityID) bool {
+	posComp, exists := world.GetComponent(entityID, components.Position)
+	if !exists {
+		return false
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	mapID := getEntityMapID(world, entityID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return false
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	// Tiles taken by something solid are out
+	occupied := make(map[Point]bool)
+	for _, entity := range world.GetEntitiesWithComponent(components.Collision) {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		collComp, _ := world.GetComponent(entity.ID, components.Collision)
+		if !collComp.(*components.CollisionComponent).Blocks {
+			continue
+		}
+		if otherPos, exists := world.GetComponent(entity.ID, components.Position); exists {
+			p := otherPos.(*components.PositionComponent)
+			occupied[Point{p.X, p.Y}] = true
+		}
+	}
+
+	var candidates []Point
+	for y := 0; y < gameMap.Height; y++ {
+		for x := 0; x < gameMap.Width; x++ {
+			if gameMap.Tiles[y][x] != components.TileFloor || occupied[Point{x, y}] || (x == pos.X && y == pos.Y) {
+				continue
+			}
+			if _, isTransition := gameMap.GetTransition(x, y); isTransition {
+				continue
+			}
+			candidates = append(candidates, Point{x, y})
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+
+	dest := candidates[playRNG.Intn(len(candidates))]
+	fromX, fromY := pos.X, pos.Y
+	pos.X, pos.Y = dest.X, dest.Y
+	world.EmitEvent(EntityMoveEvent{
+		EntityID: entityID,
+		FromX:    fromX,
+		FromY:    fromY,
+		ToX:      pos.X,
+		ToY:      pos.Y,
+	})
+	return true
+}
+
+// castArea applies a scroll's effects to everything around the target. Walls shelter
+// whatever is behind them, and the reader is never caught in their own scroll.
+func (s *InventorySystem) castArea(world *ecs.World, readerID, itemID, targetID ecs.EntityID, radius int) {
+	effectsSystem := s.getEffectsSystem(world)
+	if effectsSystem == nil {
+		return
+	}
+	itemComp, exists := world.GetComponent(itemID, components.Item)
+	if !exists {
+		return
+	}
+	effects, ok := itemComp.(*components.ItemComponent).Data.([]components.GameEffect)
+	if !ok {
+		return
+	}
+
+	targetPosComp, exists := world.GetComponent(targetID, components.Position)
+	if !exists {
+		return
+	}
+	targetPos := targetPosComp.(*components.PositionComponent)
+	mapID := getEntityMapID(world, targetID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+
+	inArea := make(map[Point]bool)
+	for _, tile := range TilesInRadius(mapComp.(*components.MapComponent), targetPos.X, targetPos.Y, radius) {
+		inArea[tile] = true
+	}
+
+	var victims []ecs.EntityID
+	for _, entity := range world.GetEntitiesWithComponent(components.Stats) {
+		if entity.ID == readerID || getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		if posComp, exists := world.GetComponent(entity.ID, components.Position); exists {
+			pos := posComp.(*components.PositionComponent)
+			if inArea[Point{pos.X, pos.Y}] {
+				victims = append(victims, entity.ID)
+			}
+		}
+	}
+
+	for _, victimID := range victims {
+		s.strikeWithEffects(world, effectsSystem, readerID, victimID, effects)
+	}
+}
+
+// strikeWithEffects applies a scroll's effects to one victim, reporting the damage and
+// handing out the kill if it dies
+func (s *InventorySystem) strikeWithEffects(world *ecs.World, effectsSystem *EffectsSystem, readerID, victimID ecs.EntityID, effects []components.GameEffect) {
+	statsComp, exists := world.GetComponent(victimID, components.Stats)
+	if !exists {
+		return
+	}
+	stats := statsComp.(*components.StatsComponent)
+	healthBefore := stats.Health
+
+	effectsSystem.ApplyEntityEffects(world, victimID, effects)
+
+	victimName := getEntityName(world, victimID)
+	if damage := healthBefore - stats.Health; damage > 0 {
+		GetMessageLog().AddCombat(fmt.Sprintf("%s takes %d damage! %s has %d/%d HP remaining.",
+			victimName, damage, victimName, stats.Health, stats.MaxHealth))
+	}
+	if stats.Health > 0 {
+		return
+	}
+
+	if combatSystem := s.getCombatSystem(world); combatSystem != nil {
+		combatSystem.killEntity(world, readerID, victimID)
+	}
+}
+
+// getTargetingSystem finds the targeting system in the world
+func (s *InventorySystem) getTargetingSystem(world *ecs.World) *TargetingSystem {
+	for _, system := range world.GetSystems() {
+		if targeting, ok := system.(*TargetingSystem); ok {
+			return targeting
+		}
+	}
+	return nil
+}
+
+// getScanSystem finds the scan system in the world
+func (s *InventorySystem) getScanSystem(world *ecs.World) *ScanSystem {
+	for _, system := range world.GetSystems() {
+		if scanSystem, ok := system.(*ScanSystem); ok {
+			return scanSystem
+		}
+	}
+	return nil
+}
+
+// getEffectsSystem finds the effects system in the world
+func (s *InventorySystem) getEffectsSystem(world *ecs.World) *EffectsSystem {
+	for _, system := range world.GetSystems() {
+		if effectsSystem, ok := system.(*EffectsSystem); ok {
+			return effectsSystem
+		}
+	}
+	return nil
+}
+
+// getCombatSystem finds the combat system in the world
+func (s *InventorySystem) getCombatSystem(world *ecs.World) *CombatSystem {
+	for _, system := range world.GetSystems() {
+		if combatSystem, ok := system.(*CombatSystem); ok {
+			return combatSystem
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ type InventorySystem struct {
 	world                   *ecs.World
 	pendingEquipmentQueries map[string]chan EquipmentQueryResponseEvent
 	queryMutex              sync.Mutex
+	autoEquip               bool // Equip upgrades as they are picked up (opt-in)
 }
 
 // NewInventorySystem creates a new inventory system
@@ -47,6 +48,16 @@ func (s *InventorySystem) Initialize(world *ecs.World) {
 	})
 }
 
+// SetAutoEquip enables or disables equipping upgrades on pickup
+func (s *InventorySystem) SetAutoEquip(enabled bool) {
+	s.autoEquip = enabled
+}
+
+// IsAutoEquipEnabled returns whether upgrades are equipped on pickup
+func (s *InventorySystem) IsAutoEquipEnabled() bool {
+	return s.autoEquip
+}
+
 // Update checks for item pickups and inventory interactions
 func (s *InventorySystem) Update(world *ecs.World, dt float64) {
 	s.world = world
@@ -93,6 +104,16 @@ func (s *InventorySystem) checkItemPickups(world *ecs.World, playerID ecs.Entity
 			continue
 		}
 
+		// Burning torches are too hot to pick up
+		if torchComp, exists := world.GetComponent(itemEntity.ID, components.Torch); exists && torchComp.(*components.TorchComponent).Lit {
+			continue
+		}
+
+		// Neither are bombs with their fuse lit, even one dropped at the thrower's feet
+		if bombComp, exists := world.GetComponent(itemEntity.ID, components.Bomb); exists && bombComp.(*components.BombComponent).Armed {
+			continue
+		}
+
 		itemPosComp, exists := world.GetComponent(itemEntity.ID, components.Position)
 		if !exists {
 			continue
@@ -128,7 +149,112 @@ func (s *InventorySystem) pickupItem(world *ecs.World, playerID ecs.EntityID, it
 
 		// Log the pickup
 		GetMessageLog().Add(fmt.Sprintf("You picked up %s.", itemName))
+
+		if s.autoEquip {
+			s.tryAutoEquip(world, playerID, itemID)
+		}
+	}
+}
+
+// tryAutoEquip equips a freshly picked up item if its slot is empty or it is
+// strictly better than what is equipped. Cursed items are never auto-equipped.
+func (s *InventorySystem) tryAutoEquip(world *ecs.World, playerID ecs.EntityID, itemID ecs.EntityID) {
+	equipSystem := s.getEquipmentSystem(world)
+	if equipSystem == nil || s.isCursed(world, itemID) {
+		return
+	}
+	if !equipSystem.IsUpgrade(playerID, itemID) {
+		return
+	}
+
+	// An upgrade replaces what it was compared against rather than joining it in
+	// the off hand
+	slot, err := equipSystem.SlotForItem(itemID)
+	if err != nil {
+		return
+	}
+	if err := equipSystem.EquipItem(playerID, itemID, slot); err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Auto-equip failed: %v", err))
+		return
+	}
+	GetMessageLog().AddItem(fmt.Sprintf("You auto-equip the %s.", s.getItemName(world, itemID)))
+}
+
+// EquipFromGround equips an item lying under the player without storing it in
+// the inventory first. Returns true if an item was equipped.
+func (s *InventorySystem) EquipFromGround(world *ecs.World, playerID ecs.EntityID) bool {
+	equipSystem := s.getEquipmentSystem(world)
+	if equipSystem == nil {
+		return false
+	}
+
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return false
+	}
+	playerPos := posComp.(*components.PositionComponent)
+
+	for _, itemEntity := range world.GetEntitiesWithTag("item") {
+		itemPosComp, exists := world.GetComponent(itemEntity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		itemPos := itemPosComp.(*components.PositionComponent)
+		if itemPos.X != playerPos.X || itemPos.Y != playerPos.Y {
+			continue
+		}
+		if _, err := equipSystem.SlotForItem(itemEntity.ID); err != nil {
+			continue
+		}
+
+		if err := equipSystem.EquipItemAuto(playerID, itemEntity.ID); err != nil {
+			GetMessageLog().Add(fmt.Sprintf("Failed to equip item: %v", err))
+			return false
+		}
+
+		// The item is now worn, so it no longer lies on the map
+		world.RemoveComponent(itemEntity.ID, components.Position)
+		return true
+	}
+
+	GetMessageLog().Add("There is nothing here to equip.")
+	return false
+}
+
+// isCursed returns true if the item is tagged as cursed
+func (s *InventorySystem) isCursed(world *ecs.World, itemID ecs.EntityID) bool {
+	entity := world.GetEntity(itemID)
+	return entity != nil && entity.HasTag("cursed")
+}
+
+// getEquipmentSystem finds the equipment system in the world
+func (s *InventorySystem) getEquipmentSystem(world *ecs.World) *EquipmentSystem {
+	for _, system := range world.GetSystems() {
+		if equipSystem, ok := system.(*EquipmentSystem); ok {
+			return equipSystem
+		}
 	}
+	return nil
+}
+
+// getBombSystem finds the bomb system in the world
+func (s *InventorySystem) getBombSystem(world *ecs.World) *BombSystem {
+	for _, system := range world.GetSystems() {
+		if bombSystem, ok := system.(*BombSystem); ok {
+			return bombSystem
+		}
+	}
+	return nil
+}
+
+// getTorchSystem finds the torch system in the world
+func (s *InventorySystem) getTorchSystem(world *ecs.World) *TorchSystem {
+	for _, system := range world.GetSystems() {
+		if torchSystem, ok := system.(*TorchSystem); ok {
+			return torchSystem
+		}
+	}
+	return nil
 }
 
 // DropItem drops an item from inventory to the map
@@ -240,26 +366,48 @@ func (s *InventorySystem) UseItem(world *ecs.World, playerID ecs.EntityID, itemI
 	}
 	item := itemComp.(*components.ItemComponent)
 
-	// Check item type and handle accordingly
-	if item.ItemType == "potion" || item.ItemType == "scroll" || item.ItemType == "food" || item.ItemType == "first aid" {
-		// This is a consumable item
-		if item.Data != nil {
-			if _, ok := item.Data.([]components.GameEffect); ok {
-				// Emit a single effects event for the item
-				world.EmitEvent(EffectsEvent{
-					EntityID:    playerID,
-					EffectType:  "item",
-					Property:    "",  // Not used when applying item effects
-					Value:       nil, // Not used when applying item effects
-					Source:      itemID,
-					DisplayText: fmt.Sprintf("Used %s", s.getItemName(world, itemID)),
-				})
-			}
+	// Bombs are lit and thrown rather than consumed
+	if world.HasComponent(itemID, components.Bomb) {
+		bombSystem := s.getBombSystem(world)
+		if bombSystem == nil {
+			return false
+		}
+		inventory.RemoveItem(itemID)
+		if !bombSystem.Throw(world, playerID, itemID) {
+			inventory.AddItem(itemID)
+			return false
+		}
+		return true
+	}
+
+	// Torches are aimed at a tile first, and the throw takes the turn once one is picked
+	if world.HasComponent(itemID, components.Torch) {
+		if torchSystem := s.getTorchSystem(world); torchSystem != nil {
+			torchSystem.Aim(world, playerID, itemID)
 		}
+		return false
+	}
+
+	// Scrolls may act on the map or need a target, and are only used up once they work
+	if item.ItemType == "scroll" {
+		return s.readScroll(world, playerID, itemID, inventory)
+	}
+
+	// Check item type and handle accordingly
+	if item.ItemType == "potion" || item.ItemType == "food" || item.ItemType == "first aid" {
+		// This is a consumable item, its effects go to whoever uses it
+		s.applySelfEffects(world, playerID, itemID)
 
 		// Remove the item from inventory
 		inventory.RemoveItem(itemID)
-		GetMessageLog().Add(fmt.Sprintf("You used the %s.", s.getItemName(world, itemID)))
+		switch item.ItemType {
+		case "potion":
+			GetMessageLog().Add(fmt.Sprintf("You drink the %s.", s.getItemName(world, itemID)))
+		case "food":
+			GetMessageLog().Add(fmt.Sprintf("You eat the %s.", s.getItemName(world, itemID)))
+		default:
+			GetMessageLog().Add(fmt.Sprintf("You used the %s.", s.getItemName(world, itemID)))
+		}
 		return true
 	} else if item.ItemType == "weapon" || item.ItemType == "armor" || item.ItemType == "headgear" ||
 		item.ItemType == "shield" || item.ItemType == "ring" || item.ItemType == "amulet" {
@@ -289,7 +437,8 @@ func (s *InventorySystem) UseItem(world *ecs.World, playerID ecs.EntityID, itemI
 	return false
 }
 
-// HandleUseKeyPress handles the 'U' key for consuming items
+// HandleUseKeyPress handles the 'U' key for consuming items. Potions are drunk on the
+// spot, while scrolls may first ask for a target.
 func (s *InventorySystem) HandleUseKeyPress(world *ecs.World, playerID ecs.EntityID, selectedItemIndex int) bool {
 	// Check if the index is valid
 	if selectedItemIndex < 0 {
@@ -301,6 +450,72 @@ func (s *InventorySystem) HandleUseKeyPress(world *ecs.World, playerID ecs.Entit
 	return s.UseItem(world, playerID, selectedItemIndex)
 }
 
+// HandleSocketKeyPress handles the 'S' key. A selected gem is set into the first free
+// socket, trying equipped items before carried ones; a selected socketed item gives up
+// its last gem. Returns true if a gem was moved.
+func (s *InventorySystem) HandleSocketKeyPress(world *ecs.World, playerID ecs.EntityID, selectedItemIndex int) bool {
+	invComp, exists := world.GetComponent(playerID, components.Inventory)
+	if !exists {
+		return false
+	}
+	inventory := invComp.(*components.InventoryComponent)
+	selectedID := inventory.GetItemByIndex(selectedItemIndex)
+	if selectedID == 0 {
+		GetMessageLog().Add("No item selected.")
+		return false
+	}
+	itemComp, exists := world.GetComponent(selectedID, components.Item)
+	if !exists {
+		return false
+	}
+	item := itemComp.(*components.ItemComponent)
+
+	equipSystem := s.getEquipmentSystem(world)
+	if equipSystem == nil {
+		return false
+	}
+
+	if item.ItemType != "gem" {
+		if len(item.SocketedGems) == 0 {
+			GetMessageLog().Add(fmt.Sprintf("The %s has no gems to remove.", s.getItemName(world, selectedID)))
+			return false
+		}
+		if err := equipSystem.UnsocketGem(playerID, selectedID); err != nil {
+			GetMessageLog().Add(fmt.Sprintf("You can't remove the gem: %v", err))
+			return false
+		}
+		return true
+	}
+
+	// Equipped items come first so the gem goes to work straight away
+	var candidates []ecs.EntityID
+	if equipComp, exists := world.GetComponent(playerID, components.Equipment); exists {
+		equipment := equipComp.(*components.EquipmentComponent)
+		for _, slot := range []components.EquipmentSlot{
+			components.SlotMainHand, components.SlotBody, components.SlotHead,
+			components.SlotOffHand, components.SlotFeet, components.SlotAccessory,
+		} {
+			if itemID := equipment.GetEquippedItem(slot); itemID != 0 {
+				candidates = append(candidates, itemID)
+			}
+		}
+	}
+	candidates = append(candidates, inventory.Items...)
+
+	for _, itemID := range candidates {
+		if targetComp, exists := world.GetComponent(itemID, components.Item); exists && targetComp.(*components.ItemComponent).FreeSockets() > 0 {
+			if err := equipSystem.SocketGem(playerID, itemID, selectedID); err != nil {
+				GetMessageLog().Add(fmt.Sprintf("You can't socket the gem: %v", err))
+				return false
+			}
+			return true
+		}
+	}
+
+	GetMessageLog().Add("Nothing you carry has a free socket.")
+	return false
+}
+
 // getItemName gets the name of an item
 func (s *InventorySystem) getItemName(world *ecs.World, itemID ecs.EntityID) string {
 	if nameComp, exists := world.GetComponent(itemID, components.Name); exists {
@@ -0,0 +1,90 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// ToggleJunk marks the item at the given inventory index as junk, or clears the mark.
+// Equipped gear is in use, so it can't be marked.
+func (s *InventorySystem) ToggleJunk(world *ecs.World, playerID ecs.EntityID, itemIndex int) bool {
+	invComp, exists := world.GetComponent(playerID, components.Inventory)
+	if !exists {
+		return false
+	}
+	inventory := invComp.(*components.InventoryComponent)
+	itemID := inventory.GetItemByIndex(itemIndex)
+	itemComp, exists := world.GetComponent(itemID, components.Item)
+	if !exists {
+		return false
+	}
+	item := itemComp.(*components.ItemComponent)
+
+	if equipSystem := s.getEquipmentSystem(world); equipSystem != nil && equipSystem.IsItemEquipped(playerID, itemID) {
+		GetMessageLog().Add("You can't mark gear you're wearing as junk.")
+		return false
+	}
+
+	item.Junk = !item.Junk
+	if item.Junk {
+		GetMessageLog().AddItem(fmt.Sprintf("Marked %s as junk.", getEntityName(world, itemID)))
+	} else {
+		GetMessageLog().AddItem(fmt.Sprintf("%s is no longer junk.", getEntityName(world, itemID)))
+	}
+	return true
+}
+
+// junkIndexes returns the inventory indexes of the items marked as junk, last first,
+// so they can be removed one by one without the rest shifting
+func (s *InventorySystem) junkIndexes(world *ecs.World, inventory *components.InventoryComponent) []int {
+	var indexes []int
+	for i := inventory.Size() - 1; i >= 0; i-- {
+		if itemComp, exists := world.GetComponent(inventory.Items[i], components.Item); exists && itemComp.(*components.ItemComponent).Junk {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// JunkValue returns the total value of the player's junk, what a vendor would pay
+// for all of it
+func (s *InventorySystem) JunkValue(world *ecs.World, playerID ecs.EntityID) int {
+	invComp, exists := world.GetComponent(playerID, components.Inventory)
+	if !exists {
+		return 0
+	}
+	inventory := invComp.(*components.InventoryComponent)
+
+	total := 0
+	for _, index := range s.junkIndexes(world, inventory) {
+		itemComp, _ := world.GetComponent(inventory.Items[index], components.Item)
+		total += itemComp.(*components.ItemComponent).Value
+	}
+	return total
+}
+
+// DropAllJunk drops every item marked as junk at the player's feet. Returns how many
+// were dropped.
+func (s *InventorySystem) DropAllJunk(world *ecs.World, playerID ecs.EntityID) int {
+	invComp, exists := world.GetComponent(playerID, components.Inventory)
+	if !exists {
+		return 0
+	}
+	inventory := invComp.(*components.InventoryComponent)
+
+	dropped := 0
+	for _, index := range s.junkIndexes(world, inventory) {
+		if s.DropItem(world, playerID, index) {
+			dropped++
+		}
+	}
+
+	if dropped == 0 {
+		GetMessageLog().Add("You have nothing marked as junk.")
+	} else {
+		GetMessageLog().AddItem(fmt.Sprintf("You dropped %d junk item(s).", dropped))
+	}
+	return dropped
+}
@@ -0,0 +1,264 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// KeyAction is something the player does on the map with a key
+type KeyAction string
+
+const (
+	ActionMoveUp        KeyAction = "move_up"
+	ActionMoveDown      KeyAction = "move_down"
+	ActionMoveLeft      KeyAction = "move_left"
+	ActionMoveRight     KeyAction = "move_right"
+	ActionMoveUpLeft    KeyAction = "move_up_left"
+	ActionMoveUpRight   KeyAction = "move_up_right"
+	ActionMoveDownLeft  KeyAction = "move_down_left"
+	ActionMoveDownRight KeyAction = "move_down_right"
+	ActionRest          KeyAction = "rest"
+	ActionInventory     KeyAction = "inventory"
+	ActionExamine       KeyAction = "examine"
+	ActionEquipGround   KeyAction = "equip_ground"
+	ActionAutoEquip     KeyAction = "auto_equip"
+	ActionContextMenu   KeyAction = "context_menu"
+	ActionUndo          KeyAction = "undo"
+	ActionSneak         KeyAction = "sneak"
+	ActionUseStairs     KeyAction = "use_stairs"
+	ActionAutoExplore   KeyAction = "auto_explore"
+	ActionTravelStairs  KeyAction = "travel_stairs"
+	ActionLookAround    KeyAction = "look_around"
+	ActionCycleTarget   KeyAction = "cycle_target"
+	ActionShowAggro     KeyAction = "show_aggro"
+	ActionSocket        KeyAction = "socket"
+	ActionOffer         KeyAction = "offer"
+	ActionEquipmentSets KeyAction = "equipment_sets"
+	ActionDebugLog      KeyAction = "debug_log"
+	ActionBestiary      KeyAction = "bestiary"
+	ActionTurnOrder     KeyAction = "turn_order"
+	ActionDebugOverlay  KeyAction = "debug_overlay"
+	ActionDebugView     KeyAction = "debug_view"
+)
+
+// KeyActions lists the bindable actions in the order the options screen shows them
+var KeyActions = []struct {
+	Action KeyAction
+	Name   string
+}{
+	{ActionMoveUp, "Move up"},
+	{ActionMoveDown, "Move down"},
+	{ActionMoveLeft, "Move left"},
+	{ActionMoveRight, "Move right"},
+	{ActionMoveUpLeft, "Move up-left"},
+	{ActionMoveUpRight, "Move up-right"},
+	{ActionMoveDownLeft, "Move down-left"},
+	{ActionMoveDownRight, "Move down-right"},
+	{ActionRest, "Rest"},
+	{ActionInventory, "Inventory"},
+	{ActionExamine, "Examine container"},
+	{ActionEquipGround, "Equip from ground"},
+	{ActionAutoEquip, "Toggle auto-equip"},
+	{ActionContextMenu, "Context menu"},
+	{ActionUndo, "Undo step"},
+	{ActionSneak, "Toggle sneaking"},
+	{ActionUseStairs, "Use stairs"},
+	{ActionAutoExplore, "Auto-explore"},
+	{ActionTravelStairs, "Travel to stairs"},
+	{ActionLookAround, "Look at hostiles"},
+	{ActionCycleTarget, "Cycle target"},
+	{ActionShowAggro, "Show target's aggro"},
+	{ActionSocket, "Socket selected item"},
+	{ActionOffer, "Offer selected item"},
+	{ActionEquipmentSets, "Equipment sets"},
+	{ActionDebugLog, "Debug messages"},
+	{ActionBestiary, "Bestiary"},
+	{ActionTurnOrder, "Turn order"},
+	{ActionDebugOverlay, "Debug overlay"},
+	{ActionDebugView, "Debug overlay view"},
+}
+
+// movementActions are the actions that step the player, with the direction of each
+var movementActions = []struct {
+	action KeyAction
+	dir    int
+}{
+	{ActionMoveUp, DirUp},
+	{ActionMoveDown, DirDown},
+	{ActionMoveLeft, DirLeft},
+	{ActionMoveRight, DirRight},
+	{ActionMoveUpLeft, DirUpLeft},
+	{ActionMoveUpRight, DirUpRight},
+	{ActionMoveDownLeft, DirDownLeft},
+	{ActionMoveDownRight, DirDownRight},
+}
+
+// defaultKeys are the keys each action starts bound to. U isn't a movement key so it
+// stays free for using items; Home steps up-right instead.
+var defaultKeys = map[KeyAction][]ebiten.Key{
+	ActionMoveUp:        {ebiten.KeyArrowUp, ebiten.KeyK, ebiten.KeyNumpad8, ebiten.Key8},
+	ActionMoveDown:      {ebiten.KeyArrowDown, ebiten.KeyJ, ebiten.KeyNumpad2, ebiten.Key2},
+	ActionMoveLeft:      {ebiten.KeyArrowLeft, ebiten.KeyH, ebiten.KeyNumpad4, ebiten.Key4},
+	ActionMoveRight:     {ebiten.KeyArrowRight, ebiten.KeyL, ebiten.KeyNumpad6, ebiten.Key6},
+	ActionMoveUpLeft:    {ebiten.KeyY, ebiten.KeyNumpad7, ebiten.Key7},
+	ActionMoveUpRight:   {ebiten.KeyHome, ebiten.KeyNumpad9, ebiten.Key9},
+	ActionMoveDownLeft:  {ebiten.KeyB, ebiten.KeyNumpad1, ebiten.Key1},
+	ActionMoveDownRight: {ebiten.KeyN, ebiten.KeyNumpad3, ebiten.Key3},
+	ActionRest:          {ebiten.KeyPeriod},
+	ActionInventory:     {ebiten.KeyI},
+	ActionExamine:       {ebiten.KeyE},
+	ActionEquipGround:   {ebiten.KeyG},
+	ActionAutoEquip:     {ebiten.KeyA},
+	ActionContextMenu:   {ebiten.KeyM},
+	ActionUndo:          {ebiten.KeyZ},
+	ActionSneak:         {ebiten.KeyC},
+	ActionUseStairs:     {ebiten.KeyEnter},
+	ActionAutoExplore:   {ebiten.KeyX},
+	ActionTravelStairs:  {ebiten.KeyT},
+	ActionLookAround:    {ebiten.KeyV},
+	ActionCycleTarget:   {ebiten.KeyTab},
+	ActionShowAggro:     {ebiten.KeyR},
+	ActionSocket:        {ebiten.KeyS},
+	ActionOffer:         {ebiten.KeyO},
+	ActionEquipmentSets: {ebiten.KeyW},
+	ActionDebugLog:      {ebiten.KeyF1},
+	ActionBestiary:      {ebiten.KeyF2},
+	ActionTurnOrder:     {ebiten.KeyF3},
+	ActionDebugOverlay:  {ebiten.KeyF4},
+	ActionDebugView:     {ebiten.KeyF5},
+}
+
+// Keybindings maps the player's map actions to the keys that trigger them. An action
+// can have any number of keys. Bindings loaded from a file are saved back to it
+// whenever they change.
+type Keybindings struct {
+	keys     map[KeyAction][]ebiten.Key
+	savePath string
+}
+
+// NewKeybindings creates keybindings with every action on its default keys
+func NewKeybindings() *Keybindings {
+	k := &Keybindings{}
+	k.setDefaults()
+	return k
+}
+
+// setDefaults puts every action back on its default keys
+func (k *Keybindings) setDefaults() {
+	k.keys = make(map[KeyAction][]ebiten.Key, len(defaultKeys))
+	for action, keys := range defaultKeys {
+		k.keys[action] = slices.Clone(keys)
+	}
+}
+
+// Keys returns the keys bound to an action, in the order they were bound
+func (k *Keybindings) Keys(action KeyAction) []ebiten.Key {
+	return k.keys[action]
+}
+
+// JustPressed returns whether a key bound to an action went down this tick
+func (k *Keybindings) JustPressed(action KeyAction) bool {
+	for _, key := range k.keys[action] {
+		if KeyJustPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflicts returns the other actions a key is already bound to, in screen order
+func (k *Keybindings) Conflicts(action KeyAction, key ebiten.Key) []KeyAction {
+	var conflicts []KeyAction
+	for _, entry := range KeyActions {
+		if entry.Action != action && slices.Contains(k.keys[entry.Action], key) {
+			conflicts = append(conflicts, entry.Action)
+		}
+	}
+	return conflicts
+}
+
+// Bind adds a key to an action, taking it off any other action it was bound to.
+// Returns false if the action already had it.
+func (k *Keybindings) Bind(action KeyAction, key ebiten.Key) bool {
+	if slices.Contains(k.keys[action], key) {
+		return false
+	}
+	for _, other := range k.Conflicts(action, key) {
+		k.keys[other] = slices.DeleteFunc(k.keys[other], func(bound ebiten.Key) bool { return bound == key })
+	}
+	k.keys[action] = append(k.keys[action], key)
+	k.save()
+	return true
+}
+
+// Clear unbinds every key from an action
+func (k *Keybindings) Clear(action KeyAction) {
+	k.keys[action] = nil
+	k.save()
+}
+
+// ResetToDefaults puts every action back on its default keys
+func (k *Keybindings) ResetToDefaults() {
+	k.setDefaults()
+	k.save()
+}
+
+// ActionName returns the name an action is shown under
+func ActionName(action KeyAction) string {
+	for _, entry := range KeyActions {
+		if entry.Action == action {
+			return entry.Name
+		}
+	}
+	return string(action)
+}
+
+// LoadFromFile loads keybindings and keeps saving to the same file. Actions the file
+// doesn't mention keep their default keys. A missing file is not an error; the
+// defaults are used until something is rebound.
+func (k *Keybindings) LoadFromFile(path string) error {
+	k.savePath = path
+
+	fileData, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read keybindings: %w", err)
+	}
+
+	var loaded map[KeyAction][]ebiten.Key
+	if err := json.Unmarshal(fileData, &loaded); err != nil {
+		return fmt.Errorf("failed to parse keybindings: %w", err)
+	}
+
+	k.setDefaults()
+	for action, keys := range loaded {
+		if _, known := defaultKeys[action]; !known {
+			GetDebugLog().Add(fmt.Sprintf("Keybindings: ignoring unknown action %q", action))
+			continue
+		}
+		k.keys[action] = keys
+	}
+	return nil
+}
+
+// save writes the keybindings to disk if persistence is enabled
+func (k *Keybindings) save() {
+	if k.savePath == "" {
+		return
+	}
+
+	fileData, err := json.MarshalIndent(k.keys, "", "  ")
+	if err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Failed to encode keybindings: %v", err))
+		return
+	}
+	if err := os.WriteFile(k.savePath, fileData, 0644); err != nil {
+		GetDebugLog().Add(fmt.Sprintf("Failed to save keybindings: %v", err))
+	}
+}
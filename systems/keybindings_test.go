@@ -0,0 +1,136 @@
+package systems
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestKeybindingsDefaults(t *testing.T) {
+	k := NewKeybindings()
+	for _, entry := range KeyActions {
+		if len(k.Keys(entry.Action)) == 0 {
+			t.Errorf("%s has no default keys", entry.Name)
+		}
+	}
+	for _, entry := range KeyActions {
+		for _, key := range k.Keys(entry.Action) {
+			if conflicts := k.Conflicts(entry.Action, key); len(conflicts) > 0 {
+				t.Errorf("default key %s of %s is also bound to %v", key, entry.Name, conflicts)
+			}
+		}
+	}
+}
+
+func TestKeybindingsBind(t *testing.T) {
+	k := NewKeybindings()
+
+	// Several keys can share an action
+	if !k.Bind(ActionRest, ebiten.KeyS) {
+		t.Fatal("Bind(rest, S) = false, want true")
+	}
+	if got := k.Keys(ActionRest); !slices.Equal(got, []ebiten.Key{ebiten.KeyPeriod, ebiten.KeyS}) {
+		t.Errorf("rest keys = %v, want Period and S", got)
+	}
+	if k.Bind(ActionRest, ebiten.KeyS) {
+		t.Error("binding a key the action already has reported a change")
+	}
+
+	// A key taken from another action moves over
+	if conflicts := k.Conflicts(ActionSneak, ebiten.KeyK); !slices.Equal(conflicts, []KeyAction{ActionMoveUp}) {
+		t.Errorf("Conflicts(sneak, K) = %v, want move up", conflicts)
+	}
+	k.Bind(ActionSneak, ebiten.KeyK)
+	if slices.Contains(k.Keys(ActionMoveUp), ebiten.KeyK) {
+		t.Error("K is still bound to move up after moving it to sneaking")
+	}
+	if !slices.Contains(k.Keys(ActionSneak), ebiten.KeyK) {
+		t.Error("K wasn't bound to sneaking")
+	}
+
+	k.Clear(ActionUndo)
+	if len(k.Keys(ActionUndo)) != 0 {
+		t.Errorf("undo keys after Clear = %v, want none", k.Keys(ActionUndo))
+	}
+
+	k.ResetToDefaults()
+	if !slices.Equal(k.Keys(ActionMoveUp), defaultKeys[ActionMoveUp]) || !slices.Equal(k.Keys(ActionUndo), defaultKeys[ActionUndo]) {
+		t.Error("ResetToDefaults didn't restore the default keys")
+	}
+}
+
+func TestKeybindingsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keybindings.json")
+
+	k := NewKeybindings()
+	if err := k.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile with no file: %v", err)
+	}
+	k.Bind(ActionInventory, ebiten.KeyTab)
+	k.Clear(ActionUndo)
+
+	loaded := NewKeybindings()
+	if err := loaded.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if got := loaded.Keys(ActionInventory); !slices.Equal(got, []ebiten.Key{ebiten.KeyI, ebiten.KeyTab}) {
+		t.Errorf("loaded inventory keys = %v, want I and Tab", got)
+	}
+	if got := loaded.Keys(ActionUndo); len(got) != 0 {
+		t.Errorf("loaded undo keys = %v, want none", got)
+	}
+	if got := loaded.Keys(ActionMoveLeft); !slices.Equal(got, defaultKeys[ActionMoveLeft]) {
+		t.Errorf("loaded move left keys = %v, want the defaults", got)
+	}
+}
+
+func TestKeybindingsLoadPartialFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keybindings.json")
+	if err := os.WriteFile(path, []byte(`{"sneak": ["KeyX", "S"], "fly": ["F"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewKeybindings().LoadFromFile(path); err == nil {
+		t.Error("a file with an unknown key name loaded without an error")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"sneak": ["X", "S"], "fly": ["F"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	k := NewKeybindings()
+	if err := k.LoadFromFile(path); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if got := k.Keys(ActionSneak); !slices.Equal(got, []ebiten.Key{ebiten.KeyX, ebiten.KeyS}) {
+		t.Errorf("sneak keys = %v, want X and S", got)
+	}
+	if got := k.Keys(ActionRest); !slices.Equal(got, defaultKeys[ActionRest]) {
+		t.Errorf("rest keys = %v, want the defaults for an action the file leaves out", got)
+	}
+}
+
+func TestKeybindingsJustPressed(t *testing.T) {
+	defer func(held, previous map[ebiten.Key]bool) {
+		input.held, input.previous = held, previous
+	}(input.held, input.previous)
+
+	k := NewKeybindings()
+	k.Bind(ActionSneak, ebiten.KeyQ)
+
+	input.previous = map[ebiten.Key]bool{}
+	input.held = map[ebiten.Key]bool{ebiten.KeyQ: true}
+	if !k.JustPressed(ActionSneak) {
+		t.Error("sneaking wasn't triggered by its new key")
+	}
+	if k.JustPressed(ActionUndo) {
+		t.Error("undo was triggered by a key it isn't bound to")
+	}
+
+	// Held from the tick before, the key isn't pressed again
+	input.previous = map[ebiten.Key]bool{ebiten.KeyQ: true}
+	if k.JustPressed(ActionSneak) {
+		t.Error("a held key counted as pressed again")
+	}
+}
@@ -0,0 +1,98 @@
+package systems
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/config"
+	"ebiten-rogue/ecs"
+)
+
+// DefaultLowHealthThreshold is the fraction of max health below which the player is
+// warned, unless set otherwise
+const DefaultLowHealthThreshold = 0.25
+
+// SetLowHealthWarning sets the fraction of max health below which the player is
+// warned, and whether the red screen-edge vignette is drawn. The one-time alert is
+// given either way.
+func (s *RenderSystem) SetLowHealthWarning(vignette bool, threshold float64) {
+	s.lowHealthVignette = vignette
+	s.lowHealthThreshold = threshold
+}
+
+// playerHealthFraction returns the player's health as a fraction of their max, and
+// false if there is no player with stats
+func playerHealthFraction(world *ecs.World) (float64, bool) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return 0, false
+	}
+	statsComp, exists := world.GetComponent(playerEntities[0].ID, components.Stats)
+	if !exists {
+		return 0, false
+	}
+	stats := statsComp.(*components.StatsComponent)
+	if stats.MaxHealth <= 0 {
+		return 0, false
+	}
+	return float64(stats.Health) / float64(stats.MaxHealth), true
+}
+
+// updateLowHealth alerts the player once when their health drops below the threshold,
+// and gets ready to alert again once it has recovered
+func (s *RenderSystem) updateLowHealth(world *ecs.World) {
+	fraction, ok := playerHealthFraction(world)
+	if !ok {
+		return
+	}
+	low := fraction > 0 && fraction < s.lowHealthThreshold
+	if low && !s.lowHealthAlerted {
+		GetMessageLog().AddAlert("Your health is low!")
+		world.EmitEvent(SoundEvent{Name: "low_health"})
+	}
+	s.lowHealthAlerted = low
+}
+
+// drawLowHealthVignette reddens the edges of the game area while the player's health
+// is below the threshold, more strongly the lower it is
+func (s *RenderSystem) drawLowHealthVignette(world *ecs.World, screen *ebiten.Image) {
+	if !s.lowHealthVignette {
+		return
+	}
+	fraction, ok := playerHealthFraction(world)
+	if !ok || fraction <= 0 || fraction >= s.lowHealthThreshold {
+		return
+	}
+	intensity := 0.35 + 0.65*(1-fraction/s.lowHealthThreshold)
+
+	if s.vignette == nil {
+		s.vignette = newVignette(config.GameScreenWidth*s.tileset.TileSize, config.GameScreenHeight*s.tileset.TileSize)
+	}
+	r, g, b, _ := s.palette.HealthLow.RGBA()
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff, intensity)
+	screen.DrawImage(s.vignette, op)
+}
+
+// newVignette builds a white image that is opaque at its edges and fades to clear
+// over the outer fifth, to be tinted when drawn
+func newVignette(width, height int) *ebiten.Image {
+	edge := float64(min(width, height)) / 5
+	pixels := make([]byte, width*height*4)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			distance := float64(min(x, y, width-1-x, height-1-y))
+			if distance >= edge {
+				continue
+			}
+			fade := 1 - distance/edge
+			alpha := byte(255 * fade * fade)
+			// Premultiplied alpha, as ebiten expects
+			i := (y*width + x) * 4
+			pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = alpha, alpha, alpha, alpha
+		}
+	}
+	image := ebiten.NewImage(width, height)
+	image.WritePixels(pixels)
+	return image
+}
@@ -5,8 +5,6 @@ import (
 	"ebiten-rogue/config"
 	"ebiten-rogue/ecs"
 	"fmt"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // MapRegistrySystem manages multiple maps and transitioning between them
@@ -129,6 +127,14 @@ func (s *MapRegistrySystem) SetActiveMap(mapEntity *ecs.Entity) {
 	// Update active map ID
 	s.activeMapID = mapEntity.ID
 
+	// A change of level is a good moment to save the sightings made on the last one
+	for _, system := range s.world.GetSystems() {
+		if bestiary, ok := system.(*BestiarySystem); ok {
+			bestiary.Flush()
+			break
+		}
+	}
+
 	// Get map type info for better logging
 	var mapType string = "unknown"
 	var mapLevel int = -1
@@ -266,20 +272,21 @@ func (s *MapRegistrySystem) handleMapTransitions(world *ecs.World) {
 	isStairsUp := tileUnderPlayer == components.TileStairsUp
 	isStairsDown := tileUnderPlayer == components.TileStairsDown
 
-	// If player is on stairs, check for transition input
-	if (isStairsUp || isStairsDown) && ebiten.IsKeyPressed(ebiten.KeyEnter) {
-		fmt.Printf("TRANSITION TRIGGERED: Player at (%d,%d) on %s pressed ENTER\n",
+	// Callers only ask when the player chose to use the stairs, with whatever key is
+	// bound to it or from the context menu
+	if isStairsUp || isStairsDown {
+		fmt.Printf("TRANSITION TRIGGERED: Player at (%d,%d) on %s\n",
 			playerPos.X, playerPos.Y,
 			map[bool]string{true: "Up Stairs", false: "Down Stairs"}[isStairsUp])
 
 		// Log the transition attempt
-		GetDebugLog().Add(fmt.Sprintf("TRANSITION TRIGGERED: Player at (%d,%d) on tile type %d pressed ENTER",
+		GetDebugLog().Add(fmt.Sprintf("TRANSITION TRIGGERED: Player at (%d,%d) on tile type %d",
 			playerPos.X, playerPos.Y, tileUnderPlayer))
 
 		// Start the transition
 		s.transitionBetweenMaps(world, tileUnderPlayer, playerPos)
-	} else if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-		fmt.Printf("ENTER pressed but player not on stairs (tile type: %d)\n", tileUnderPlayer)
+	} else {
+		fmt.Printf("Stairs used but player not on stairs (tile type: %d)\n", tileUnderPlayer)
 	}
 }
 
@@ -396,6 +403,9 @@ func (s *MapRegistrySystem) transitionBetweenMaps(world *ecs.World, tileType int
 	GetDebugLog().Add(fmt.Sprintf("TRANSITION DEBUG: Updated player position from (%d,%d) to (%d,%d)",
 		oldX, oldY, playerPos.X, playerPos.Y))
 
+	// Companions travel with the player rather than staying on the old map
+	bringCompanions(world, playerEntity.ID, oldActiveMapID, targetMap.ID, playerPos.X, playerPos.Y)
+
 	// 4. Force camera update after map change
 	GetDebugLog().Add("TRANSITION STEP 4: Updating camera position")
 	s.updateCameraPosition(world, playerPos.X, playerPos.Y)
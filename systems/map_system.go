@@ -4,8 +4,6 @@ import (
 	"ebiten-rogue/components"
 	"ebiten-rogue/config"
 	"ebiten-rogue/ecs"
-	"math/rand"
-	"time"
 )
 
 // MapSystem handles map-related operations and rendering
@@ -92,17 +90,14 @@ func (s *MapSystem) FindEmptyPosition(mapComp *components.MapComponent) (int, in
 
 	// If we found floor tiles, return a random one
 	if len(floorTiles) > 0 {
-		// Use time to seed the random number generator
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		randomIndex := r.Intn(len(floorTiles))
+		randomIndex := playRNG.Intn(len(floorTiles))
 		return floorTiles[randomIndex][0], floorTiles[randomIndex][1]
 	}
 
 	// Try a random approach as fallback
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for i := 0; i < maxAttempts; i++ {
-		x := r.Intn(mapComp.Width)
-		y := r.Intn(mapComp.Height)
+		x := playRNG.Intn(mapComp.Width)
+		y := playRNG.Intn(mapComp.Height)
 		if mapComp.Tiles[y][x] == components.TileFloor {
 			return x, y
 		}
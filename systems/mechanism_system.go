@@ -0,0 +1,187 @@
+package systems
+
+import (
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// MechanismSystem handles levers and switches that open and close parts of the map
+type MechanismSystem struct {
+	initialized bool
+}
+
+// NewMechanismSystem creates a new mechanism system
+func NewMechanismSystem() *MechanismSystem {
+	return &MechanismSystem{}
+}
+
+// Initialize sets up event listeners
+func (s *MechanismSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Bumping into a lever pulls it
+	world.GetEventManager().Subscribe("player_move_attempt", func(event ecs.Event) {
+		moveAttempt := event.(PlayerMoveAttemptEvent)
+		if mechanismID := s.getMechanismAt(world, moveAttempt.ToX, moveAttempt.ToY); mechanismID != 0 {
+			s.Activate(world, mechanismID, moveAttempt.EntityID)
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *MechanismSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// Activate pulls a lever, switching its target tiles between their off and on states.
+// Returns false if the mechanism can't be activated.
+func (s *MechanismSystem) Activate(world *ecs.World, mechanismID, activatorID ecs.EntityID) bool {
+	mechComp, exists := world.GetComponent(mechanismID, components.Mechanism)
+	if !exists {
+		return false
+	}
+	mechanism := mechComp.(*components.MechanismComponent)
+
+	mapComp := s.getMapComponent(world, mechanismID)
+	if mapComp == nil || len(mechanism.Targets) == 0 {
+		return false
+	}
+
+	// Other levers may share the same targets, so go by the map rather than our own flag
+	first := mechanism.Targets[0]
+	active := mapComp.Tiles[first.Y][first.X] != first.OnTile
+
+	// One-shot levers only ever switch on, so they can't seal the player in
+	if mechanism.OneShot && (mechanism.Used || !active) {
+		if isPlayer(world, activatorID) {
+			GetMessageLog().AddEnvironment("The lever won't budge.")
+		}
+		return false
+	}
+
+	for _, target := range mechanism.Targets {
+		if active {
+			mapComp.SetTile(target.X, target.Y, target.OnTile)
+		} else {
+			mapComp.SetTile(target.X, target.Y, target.OffTile)
+		}
+	}
+	s.retileWalls(mapComp, mechanism.Targets)
+
+	mechanism.Used = true
+	s.syncLevers(world, mapComp, mechanismID)
+
+	changed := make([]Point, 0, len(mechanism.Targets))
+	for _, target := range mechanism.Targets {
+		changed = append(changed, Point{target.X, target.Y})
+	}
+	world.EmitEvent(MapMutatedEvent{MapID: getEntityMapID(world, mechanismID), Tiles: changed})
+
+	if isPlayer(world, activatorID) {
+		if active {
+			GetMessageLog().AddEnvironment("You pull the lever. Somewhere nearby, metal grinds open.")
+		} else {
+			GetMessageLog().AddEnvironment("You pull the lever. Somewhere nearby, metal grinds shut.")
+		}
+	}
+
+	world.EmitEvent(SoundEvent{Name: "lever"})
+	world.EmitEvent(MechanismEvent{
+		MechanismID: mechanismID,
+		ActivatorID: activatorID,
+		Active:      active,
+	})
+	return true
+}
+
+// retileWalls recomputes the box drawing walls around the changed tiles
+func (s *MechanismSystem) retileWalls(mapComp *components.MapComponent, targets []components.MechanismTarget) {
+	for _, target := range targets {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				x, y := target.X+dx, target.Y+dy
+				if x < 0 || x >= mapComp.Width || y < 0 || y >= mapComp.Height {
+					continue
+				}
+				if mapComp.IsWall(x, y) {
+					mapComp.SetTile(x, y, components.TileWall)
+				}
+			}
+		}
+	}
+	mapComp.ApplyBoxDrawingWalls()
+}
+
+// syncLevers updates the state and glyph of every lever on the map to match its targets
+func (s *MechanismSystem) syncLevers(world *ecs.World, mapComp *components.MapComponent, pulledID ecs.EntityID) {
+	pulledMapID := getEntityMapID(world, pulledID)
+
+	for _, entity := range world.GetEntitiesWithComponent(components.Mechanism) {
+		if getEntityMapID(world, entity.ID) != pulledMapID {
+			continue
+		}
+		mechComp, _ := world.GetComponent(entity.ID, components.Mechanism)
+		mechanism := mechComp.(*components.MechanismComponent)
+		if len(mechanism.Targets) == 0 {
+			continue
+		}
+
+		first := mechanism.Targets[0]
+		mechanism.Active = mapComp.Tiles[first.Y][first.X] == first.OnTile
+
+		if rendComp, exists := world.GetComponent(entity.ID, components.Renderable); exists {
+			if mechanism.Active {
+				rendComp.(*components.RenderableComponent).Char = '\\'
+			} else {
+				rendComp.(*components.RenderableComponent).Char = '/'
+			}
+		}
+	}
+}
+
+// getMechanismAt returns the mechanism at a position on the active map, or 0 if there is none
+func (s *MechanismSystem) getMechanismAt(world *ecs.World, x, y int) ecs.EntityID {
+	activeMapID := s.getActiveMapID(world)
+	for _, entity := range world.GetEntitiesWithComponent(components.Mechanism) {
+		if getEntityMapID(world, entity.ID) != activeMapID {
+			continue
+		}
+		if posComp, exists := world.GetComponent(entity.ID, components.Position); exists {
+			pos := posComp.(*components.PositionComponent)
+			if pos.X == x && pos.Y == y {
+				return entity.ID
+			}
+		}
+	}
+	return 0
+}
+
+// getMapComponent returns the map component of the map an entity belongs to
+func (s *MechanismSystem) getMapComponent(world *ecs.World, entityID ecs.EntityID) *components.MapComponent {
+	mapID := getEntityMapID(world, entityID)
+	if mapID == 0 {
+		return nil
+	}
+	if comp, exists := world.GetComponent(mapID, components.MapComponentID); exists {
+		return comp.(*components.MapComponent)
+	}
+	return nil
+}
+
+// getActiveMapID returns the ID of the active map, or 0 if there is none
+func (s *MechanismSystem) getActiveMapID(world *ecs.World) ecs.EntityID {
+	for _, system := range world.GetSystems() {
+		if mapRegistry, ok := system.(*MapRegistrySystem); ok {
+			if activeMap := mapRegistry.GetActiveMap(); activeMap != nil {
+				return activeMap.ID
+			}
+		}
+	}
+	return 0
+}
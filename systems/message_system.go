@@ -21,11 +21,20 @@ var globalDebugLog *MessageLog
 // Debug log file writer
 var debugLogWriter io.Writer
 
+// Game message log file writer
+var messageLogWriter io.Writer
+
 // SetDebugLogWriter sets a writer for debug log messages (typically a file)
 func SetDebugLogWriter(writer io.Writer) {
 	debugLogWriter = writer
 }
 
+// SetMessageLogWriter sets a writer the game message log is mirrored to (typically a
+// file), so a whole session can be reviewed after the oldest messages are dropped
+func SetMessageLogWriter(writer io.Writer) {
+	messageLogWriter = writer
+}
+
 // GetMessageLog returns the global message log instance
 func GetMessageLog() *MessageLog {
 	if globalMessageLog == nil {
@@ -78,6 +87,15 @@ func (ml *MessageLog) AddWithType(message string, msgType MessageType) {
 		}
 	}
 
+	// Mirror game messages to the message log file with their category
+	if ml == globalMessageLog && messageLogWriter != nil {
+		timestamp := time.Now().Format("15:04:05.000")
+		_, err := fmt.Fprintf(messageLogWriter, "[%s] [%s] %s\n", timestamp, msgType, message)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing to message log: %v\n", err)
+		}
+	}
+
 	// Create a colored message and add it to the log
 	coloredMsg := ColoredMessage{
 		Text: message,
@@ -91,6 +109,18 @@ func (ml *MessageLog) AddWithType(message string, msgType MessageType) {
 	}
 }
 
+// SetRetention sets how many messages the log keeps, dropping the oldest ones
+// beyond that straight away
+func (ml *MessageLog) SetRetention(maxMessages int) {
+	if maxMessages < 1 {
+		maxMessages = 1
+	}
+	ml.MaxMessages = maxMessages
+	if len(ml.Messages) > ml.MaxMessages {
+		ml.Messages = ml.Messages[len(ml.Messages)-ml.MaxMessages:]
+	}
+}
+
 // AddEnvironment adds an environmental message in gold color
 func (ml *MessageLog) AddEnvironment(message string) {
 	ml.AddWithType(message, MessageTypeEnvironment)
@@ -4,6 +4,8 @@ import (
 	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
 	"fmt"
+	"math/rand"
+	"time"
 )
 
 // TurnEvent represents the start or end of a turn
@@ -17,18 +19,26 @@ func (e TurnEvent) Type() ecs.EventType {
 
 // MonsterAbilitySystem handles monster abilities and their effects
 type MonsterAbilitySystem struct {
-	world         *ecs.World
-	initialized   bool
-	effectsSystem *EffectsSystem
+	world       *ecs.World
+	initialized bool
+	createEnemy func(mapID ecs.EntityID, x, y int, templateID string) (ecs.EntityID, error)
+	calls       map[ecs.EntityID]*pendingCall // Calls for help under way, by caller
+	rng         *rand.Rand                    // Source of ability chances and where reinforcements arrive
 }
 
 // NewMonsterAbilitySystem creates a new monster ability system
 func NewMonsterAbilitySystem() *MonsterAbilitySystem {
 	return &MonsterAbilitySystem{
-		effectsSystem: NewEffectsSystem(),
+		calls: make(map[ecs.EntityID]*pendingCall),
+		rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
+// SetRNG sets the random source ability chances and reinforcements are rolled from
+func (s *MonsterAbilitySystem) SetRNG(rng *rand.Rand) {
+	s.rng = rng
+}
+
 // Initialize sets up the system with the world and registers event listeners
 func (s *MonsterAbilitySystem) Initialize(world *ecs.World) {
 	if s.initialized {
@@ -36,7 +46,6 @@ func (s *MonsterAbilitySystem) Initialize(world *ecs.World) {
 	}
 
 	s.world = world
-	s.effectsSystem.Initialize(world)
 
 	// Subscribe to combat attack events
 	world.GetEventManager().Subscribe(EventCombatAttack, func(event ecs.Event) {
@@ -45,6 +54,19 @@ func (s *MonsterAbilitySystem) Initialize(world *ecs.World) {
 		}
 	})
 
+	// Monsters that spot their target or break and run may call for help
+	world.GetEventManager().Subscribe(EventAIStateChanged, func(event ecs.Event) {
+		s.handleStateChange(world, event.(AIStateChangedEvent))
+	})
+
+	// Reinforcements arrive a few turns after the call, unless the caller is dead by then
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.tickCalls(world)
+	})
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		delete(s.calls, event.(ecs.EntityRemovedEvent).EntityID)
+	})
+
 	// Subscribe to turn events
 	world.GetEventManager().Subscribe("turn", func(event ecs.Event) {
 		if turnEvent, ok := event.(TurnEvent); ok {
@@ -61,6 +83,13 @@ func (s *MonsterAbilitySystem) handleAttack(world *ecs.World, event CombatAttack
 	defenderName := getEntityName(world, event.DefenderID)
 	GetDebugLog().Add(fmt.Sprintf("MonsterAbilitySystem: Received combat attack event - %s attacking %s", attackerName, defenderName))
 
+	// Effects go through the registered effects system, so they tick with the turn
+	// like any others
+	effectsSystem := s.getEffectsSystem(world)
+	if effectsSystem == nil {
+		return
+	}
+
 	// Get the attacker's monster ability component
 	if abilityComp, exists := world.GetComponent(event.AttackerID, components.MonsterAbility); exists {
 		if abilities, ok := abilityComp.(*components.MonsterAbilityComponent); ok {
@@ -98,7 +127,7 @@ func (s *MonsterAbilitySystem) handleAttack(world *ecs.World, event CombatAttack
 						// Apply the ability's effects to the defender
 						for _, effect := range ability.Effects {
 							// Create and apply the effect
-							gameEffect := s.effectsSystem.CreateGameEffect(
+							gameEffect := effectsSystem.CreateGameEffect(
 								effect.Type,
 								effect.Operation,
 								effect.Value,
@@ -107,7 +136,12 @@ func (s *MonsterAbilitySystem) handleAttack(world *ecs.World, event CombatAttack
 								effect.Target.Component,
 								effect.Target.Property,
 							)
-							s.effectsSystem.ApplyEntityEffects(world, event.DefenderID, []components.GameEffect{gameEffect})
+							gameEffect.School = effect.School
+							if isImmune(world, event.DefenderID, effect.School) {
+								GetMessageLog().AddCombat(fmt.Sprintf("%s's %s has no effect on %s.", attackerName, ability.Name, defenderName))
+								continue
+							}
+							effectsSystem.ApplyEntityEffects(world, event.DefenderID, []components.GameEffect{gameEffect})
 
 							// Log the ability use
 							GetMessageLog().AddCombat(fmt.Sprintf("%s's %s causes %s to start bleeding!", attackerName, ability.Name, defenderName))
@@ -202,6 +236,16 @@ func (s *MonsterAbilitySystem) handleTurnEnd(event ecs.Event) {
 	}
 }
 
+// getEffectsSystem finds the effects system ability effects are applied through
+func (s *MonsterAbilitySystem) getEffectsSystem(world *ecs.World) *EffectsSystem {
+	for _, system := range world.GetSystems() {
+		if effectsSystem, ok := system.(*EffectsSystem); ok {
+			return effectsSystem
+		}
+	}
+	return nil
+}
+
 // Update processes the system's logic
 func (s *MonsterAbilitySystem) Update(world *ecs.World, dt float64) {
 	// No-op for now
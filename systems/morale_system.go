@@ -0,0 +1,158 @@
+package systems
+
+import (
+	"fmt"
+	"slices"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+const (
+	BaseMorale      = 100 // Morale of a pack at full strength, before its leader's leadership
+	RoutMorale      = 50  // A pack whose morale falls below this routs
+	MemberDeathLoss = 20  // Morale lost when a member dies
+	LeaderDeathLoss = 40  // Morale lost when the leader dies, on top of its leadership
+)
+
+// packMorale is the morale pool a pack shares
+type packMorale struct {
+	morale  int
+	members map[ecs.EntityID]bool // Living members, leader included
+	routed  bool                  // Once broken a pack stays broken
+}
+
+// MoraleSystem keeps the shared morale of monster packs. Every death in a pack costs
+// morale, the leader's most of all, and a charismatic leader props it up while it
+// lives. Once morale falls below RoutMorale the whole pack routs: the AI state machine
+// sends routed monsters fleeing however healthy they are. A leader's death is checked
+// at once, other losses the next time a member takes its turn.
+type MoraleSystem struct {
+	packs       map[ecs.EntityID]*packMorale // Morale by pack ID
+	initialized bool
+}
+
+// NewMoraleSystem creates a new morale system
+func NewMoraleSystem() *MoraleSystem {
+	return &MoraleSystem{
+		packs: make(map[ecs.EntityID]*packMorale),
+	}
+}
+
+// Initialize sets up event listeners
+func (s *MoraleSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe(EventDeath, func(event ecs.Event) {
+		s.handleDeath(world, event.(DeathEvent).EntityID)
+	})
+
+	// Packs are forgotten once their last member is gone, since entity IDs are reused
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		removedID := event.(ecs.EntityRemovedEvent).EntityID
+		for packID, pack := range s.packs {
+			delete(pack.members, removedID)
+			if len(pack.members) == 0 {
+				delete(s.packs, packID)
+			}
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update registers with event system if not already initialized
+func (s *MoraleSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// CheckMorale returns whether the entity's pack has routed, routing it now if its
+// morale has broken. Entities outside a pack never rout.
+func (s *MoraleSystem) CheckMorale(world *ecs.World, entityID ecs.EntityID) bool {
+	packComp, exists := world.GetComponent(entityID, components.Pack)
+	if !exists {
+		return false
+	}
+	packID := packComp.(*components.PackComponent).PackID
+	pack := s.getPack(world, packID)
+	s.checkRout(world, pack)
+	return pack.routed
+}
+
+// handleDeath takes a dead member's loss out of its pack's morale
+func (s *MoraleSystem) handleDeath(world *ecs.World, entityID ecs.EntityID) {
+	packComp, exists := world.GetComponent(entityID, components.Pack)
+	if !exists {
+		return
+	}
+	member := packComp.(*components.PackComponent)
+	pack := s.getPack(world, member.PackID)
+	if !pack.members[entityID] {
+		return
+	}
+	delete(pack.members, entityID)
+
+	if !member.Leader {
+		pack.morale -= MemberDeathLoss
+		return
+	}
+
+	// Losing the leader shakes the pack straight away
+	pack.morale -= LeaderDeathLoss + member.Leadership
+	s.checkRout(world, pack)
+}
+
+// checkRout routs a pack whose morale has broken and that has members left to run
+func (s *MoraleSystem) checkRout(world *ecs.World, pack *packMorale) {
+	if pack.routed || pack.morale >= RoutMorale || len(pack.members) == 0 {
+		return
+	}
+	pack.routed = true
+
+	// The pack is named after its rank and file rather than its leader
+	memberIDs := make([]ecs.EntityID, 0, len(pack.members))
+	for memberID := range pack.members {
+		memberIDs = append(memberIDs, memberID)
+	}
+	slices.Sort(memberIDs)
+	var name string
+	for _, memberID := range memberIDs {
+		if aiComp, exists := world.GetComponent(memberID, components.AI); exists {
+			aiComp.(*components.AIComponent).State = components.AIStateFlee
+		}
+		if packComp, exists := world.GetComponent(memberID, components.Pack); name == "" || (exists && !packComp.(*components.PackComponent).Leader) {
+			name = getEntityName(world, memberID)
+		}
+	}
+	GetMessageLog().AddAlert(fmt.Sprintf("The %s pack breaks and runs!", name))
+}
+
+// getPack returns a pack's morale, setting it up from the living members the first
+// time the pack is seen
+func (s *MoraleSystem) getPack(world *ecs.World, packID ecs.EntityID) *packMorale {
+	if pack, exists := s.packs[packID]; exists {
+		return pack
+	}
+
+	pack := &packMorale{
+		morale:  BaseMorale,
+		members: make(map[ecs.EntityID]bool),
+	}
+	for _, entity := range world.GetEntitiesWithComponent(components.Pack) {
+		packComp, _ := world.GetComponent(entity.ID, components.Pack)
+		member := packComp.(*components.PackComponent)
+		if member.PackID != packID {
+			continue
+		}
+		pack.members[entity.ID] = true
+		if member.Leader {
+			pack.morale += member.Leadership
+		}
+	}
+	s.packs[packID] = pack
+	return pack
+}
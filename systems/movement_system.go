@@ -96,13 +96,13 @@ func (s *MovementSystem) isValidMoveStandard(world *ecs.World, mapID ecs.EntityI
 	}
 	mapData := mapComp.(*components.MapComponent)
 
-	// Check for walls
-	if mapData.IsWall(x, y) {
+	// Check for walls, and rivers that have no bridge
+	if mapData.BlocksMovement(x, y) {
 		return false
 	}
 
 	// Check for entity collision, only on the same map
-	for _, entity := range world.GetAllEntities() {
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
 		// Skip entities not on the same map
 		if world.HasComponent(entity.ID, components.MapContextID) {
 			mapContextComp, _ := world.GetComponent(entity.ID, components.MapContextID)
@@ -126,6 +126,11 @@ func (s *MovementSystem) isValidMoveStandard(world *ecs.World, mapID ecs.EntityI
 			if collComp, hasCol := world.GetComponent(entity.ID, components.Collision); hasCol {
 				collision := collComp.(*components.CollisionComponent)
 				if collision.Blocks {
+					// Walking into your own companion swaps places with it
+					if s.swapWithCompanion(world, entityID, entity.ID, pos) {
+						return true
+					}
+
 					// Emit a collision event
 					world.EmitEvent(CollisionEvent{
 						EntityID1: entityID,
@@ -142,10 +147,35 @@ func (s *MovementSystem) isValidMoveStandard(world *ecs.World, mapID ecs.EntityI
 	return true
 }
 
+// swapWithCompanion moves a companion into the tile its owner is leaving, so it
+// never stands in the owner's way
+func (s *MovementSystem) swapWithCompanion(world *ecs.World, moverID, blockerID ecs.EntityID, blockerPos *components.PositionComponent) bool {
+	companionComp, exists := world.GetComponent(blockerID, components.Companion)
+	if !exists || companionComp.(*components.CompanionComponent).OwnerID != moverID {
+		return false
+	}
+	moverComp, exists := world.GetComponent(moverID, components.Position)
+	if !exists {
+		return false
+	}
+	moverPos := moverComp.(*components.PositionComponent)
+
+	fromX, fromY := blockerPos.X, blockerPos.Y
+	blockerPos.X, blockerPos.Y = moverPos.X, moverPos.Y
+	world.EmitEvent(EntityMoveEvent{
+		EntityID: blockerID,
+		FromX:    fromX,
+		FromY:    fromY,
+		ToX:      blockerPos.X,
+		ToY:      blockerPos.Y,
+	})
+	return true
+}
+
 // getEntityAtPosition returns an entity ID at the specified position
 func (s *MovementSystem) getEntityAtPosition(world *ecs.World, x, y int) ecs.EntityID {
 	// Get all entities with position components
-	for _, entity := range world.GetAllEntities() {
+	for _, entity := range world.Query(components.Position).Entities() {
 		posComp, hasPos := world.GetComponent(entity.ID, components.Position)
 		if !hasPos {
 			continue
@@ -160,3 +190,114 @@ func (s *MovementSystem) getEntityAtPosition(world *ecs.World, x, y int) ecs.Ent
 
 	return 0 // No entity found (using 0 as invalid ID)
 }
+
+// IsPhased returns whether an entity has phased out and can pass through walls
+func IsPhased(world *ecs.World, entityID ecs.EntityID) bool {
+	return world.HasComponent(entityID, components.Phase)
+}
+
+// CanPhaseTo returns whether a phased entity can step to a tile. Walls don't stop
+// it, but it only steps into rock when it can come out the other side onto open
+// ground, carrying on the same way, before its phase runs out.
+func (s *MovementSystem) CanPhaseTo(world *ecs.World, mapID ecs.EntityID, entityID ecs.EntityID, x, y int) bool {
+	phaseComp, phased := world.GetComponent(entityID, components.Phase)
+	posComp, hasPos := world.GetComponent(entityID, components.Position)
+	mapComp, hasMap := world.GetComponent(mapID, components.MapComponentID)
+	if !phased || !hasPos || !hasMap {
+		return false
+	}
+	phase := phaseComp.(*components.PhaseComponent)
+	pos := posComp.(*components.PositionComponent)
+	mapData := mapComp.(*components.MapComponent)
+
+	if !mapData.InBounds(x, y) || s.isOccupied(world, mapID, entityID, x, y) {
+		return false
+	}
+	if !mapData.BlocksMovement(x, y) {
+		return true
+	}
+
+	// One step a turn, so the rock has to end within the turns left after this one
+	dx, dy := sign(x-pos.X), sign(y-pos.Y)
+	for step := 1; step < phase.TurnsLeft; step++ {
+		tx, ty := x+dx*step, y+dy*step
+		if !mapData.InBounds(tx, ty) {
+			return false
+		}
+		if !mapData.BlocksMovement(tx, ty) {
+			return !s.isOccupied(world, mapID, entityID, tx, ty)
+		}
+	}
+	return false
+}
+
+// LeaveRock puts an entity standing inside a wall onto the nearest free open tile,
+// for a phased monster whose phase ran out before it got clear. Returns true if it
+// had to be moved.
+func (s *MovementSystem) LeaveRock(world *ecs.World, entityID ecs.EntityID) bool {
+	mapID := getEntityMapID(world, entityID)
+	posComp, hasPos := world.GetComponent(entityID, components.Position)
+	mapComp, hasMap := world.GetComponent(mapID, components.MapComponentID)
+	if !hasPos || !hasMap {
+		return false
+	}
+	pos := posComp.(*components.PositionComponent)
+	mapData := mapComp.(*components.MapComponent)
+	if !mapData.BlocksMovement(pos.X, pos.Y) {
+		return false
+	}
+
+	maxRadius := max(mapData.Width, mapData.Height)
+	for r := 1; r <= maxRadius; r++ {
+		best, bestDist := Point{}, -1
+		for y := pos.Y - r; y <= pos.Y+r; y++ {
+			for x := pos.X - r; x <= pos.X+r; x++ {
+				// Only the ring r steps out
+				if max(abs(x-pos.X), abs(y-pos.Y)) != r || !mapData.InBounds(x, y) {
+					continue
+				}
+				if mapData.BlocksMovement(x, y) || s.isOccupied(world, mapID, entityID, x, y) {
+					continue
+				}
+				dist := (x-pos.X)*(x-pos.X) + (y-pos.Y)*(y-pos.Y)
+				if bestDist < 0 || dist < bestDist {
+					best, bestDist = Point{x, y}, dist
+				}
+			}
+		}
+		if bestDist < 0 {
+			continue
+		}
+
+		fromX, fromY := pos.X, pos.Y
+		pos.X, pos.Y = best.X, best.Y
+		world.EmitEvent(EntityMoveEvent{
+			EntityID: entityID,
+			FromX:    fromX,
+			FromY:    fromY,
+			ToX:      pos.X,
+			ToY:      pos.Y,
+		})
+		return true
+	}
+	return false
+}
+
+// isOccupied returns whether something solid other than the mover stands on a tile
+func (s *MovementSystem) isOccupied(world *ecs.World, mapID, moverID ecs.EntityID, x, y int) bool {
+	for _, entity := range world.Query(components.Position, components.Collision).Entities() {
+		if entity.ID == moverID || getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		if pos.X != x || pos.Y != y {
+			continue
+		}
+		collComp, _ := world.GetComponent(entity.ID, components.Collision)
+		if collComp.(*components.CollisionComponent).Blocks {
+			return true
+		}
+	}
+	return false
+}
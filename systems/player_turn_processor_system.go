@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
 	"ebiten-rogue/components"
 	"ebiten-rogue/ecs"
@@ -26,70 +25,56 @@ const (
 
 // PlayerTurnProcessorSystem handles all player input and turns
 type PlayerTurnProcessorSystem struct {
-	// Map of keys to movement directions
-	movementKeys map[ebiten.Key]int
+	// Keys bound to movement and the other map actions
+	keybindings *Keybindings
 	// Time tracking for continuous movement
 	moveDelayTimer      float64
-	initialMoveDelay    float64 // Delay before continuous movement starts
-	continuousMoveDelay float64 // Delay between continuous movements
-	lastDirection       int     // Last movement direction
+	initialMoveDelay    float64    // Delay before continuous movement starts
+	continuousMoveDelay float64    // Delay between continuous movements
+	lastDirection       int        // Last movement direction
+	heldStuck           stuckGuard // Stops a held key from walking into a wall turn after turn
 
 	// Reference to the render system for UI state changes
 	renderSystem *RenderSystem
+
+	// One-step undo for misclicked moves
+	undoEnabled bool
+	undo        moveSnapshot
+	stepping    bool // A move is resolving, so its own turn doesn't cancel the undo
+
+	pendingMenu []ContextAction // Context menu the player asked for, opened by the game screen
+	initialized bool
+}
+
+// moveSnapshot is where the player stood before their last step. It stops being
+// valid as soon as anything happens that a step back couldn't take back.
+type moveSnapshot struct {
+	valid  bool
+	mapID  ecs.EntityID
+	x, y   int
+	reason string // Why the undo was lost, shown when the player asks for it
 }
 
 // NewPlayerTurnProcessorSystem creates a new player turn processor system
 func NewPlayerTurnProcessorSystem() *PlayerTurnProcessorSystem {
-	system := &PlayerTurnProcessorSystem{
-		movementKeys:        make(map[ebiten.Key]int),
+	return &PlayerTurnProcessorSystem{
+		keybindings:         NewKeybindings(),
 		initialMoveDelay:    0.25, // Wait 0.25 seconds before continuous movement starts
 		continuousMoveDelay: 0.10, // Then move every 0.10 seconds
 		moveDelayTimer:      0,
 		lastDirection:       DirNone,
 		renderSystem:        nil,
 	}
+}
 
-	// Set up default key bindings
-	// Arrow keys
-	system.movementKeys[ebiten.KeyArrowUp] = DirUp
-	system.movementKeys[ebiten.KeyArrowDown] = DirDown
-	system.movementKeys[ebiten.KeyArrowLeft] = DirLeft
-	system.movementKeys[ebiten.KeyArrowRight] = DirRight
-
-	// Vi keys (hjkl)
-	system.movementKeys[ebiten.KeyH] = DirLeft
-	system.movementKeys[ebiten.KeyJ] = DirDown
-	system.movementKeys[ebiten.KeyK] = DirUp
-	system.movementKeys[ebiten.KeyL] = DirRight
-	system.movementKeys[ebiten.KeyY] = DirUpLeft
-	// Don't map U to movement to avoid conflicts with item usage
-	// system.movementKeys[ebiten.KeyU] = DirUpRight
-	// Use Home key for up-right movement instead
-	system.movementKeys[ebiten.KeyHome] = DirUpRight
-	system.movementKeys[ebiten.KeyB] = DirDownLeft
-	system.movementKeys[ebiten.KeyN] = DirDownRight
-
-	// Numpad (if Num Lock is on)
-	system.movementKeys[ebiten.KeyNumpad8] = DirUp
-	system.movementKeys[ebiten.KeyNumpad2] = DirDown
-	system.movementKeys[ebiten.KeyNumpad4] = DirLeft
-	system.movementKeys[ebiten.KeyNumpad6] = DirRight
-	system.movementKeys[ebiten.KeyNumpad7] = DirUpLeft
-	system.movementKeys[ebiten.KeyNumpad9] = DirUpRight
-	system.movementKeys[ebiten.KeyNumpad1] = DirDownLeft
-	system.movementKeys[ebiten.KeyNumpad3] = DirDownRight
-
-	// Regular number keys (following numpad layout)
-	system.movementKeys[ebiten.Key8] = DirUp
-	system.movementKeys[ebiten.Key2] = DirDown
-	system.movementKeys[ebiten.Key4] = DirLeft
-	system.movementKeys[ebiten.Key6] = DirRight
-	system.movementKeys[ebiten.Key7] = DirUpLeft
-	system.movementKeys[ebiten.Key9] = DirUpRight
-	system.movementKeys[ebiten.Key1] = DirDownLeft
-	system.movementKeys[ebiten.Key3] = DirDownRight
-
-	return system
+// SetKeybindings sets the keys the player's actions are bound to
+func (s *PlayerTurnProcessorSystem) SetKeybindings(keybindings *Keybindings) {
+	s.keybindings = keybindings
+}
+
+// Keybindings returns the keys the player's actions are bound to
+func (s *PlayerTurnProcessorSystem) Keybindings() *Keybindings {
+	return s.keybindings
 }
 
 // SetRenderSystem sets the reference to the render system for UI state changes
@@ -97,8 +82,71 @@ func (s *PlayerTurnProcessorSystem) SetRenderSystem(renderSystem *RenderSystem)
 	s.renderSystem = renderSystem
 }
 
+// SetUndoEnabled turns the one-step undo on or off
+func (s *PlayerTurnProcessorSystem) SetUndoEnabled(enabled bool) {
+	s.undoEnabled = enabled
+	s.undo = moveSnapshot{}
+}
+
+// Initialize sets up the event listeners that cancel a pending undo
+func (s *PlayerTurnProcessorSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		if !s.stepping {
+			s.cancelUndo("you have acted since")
+		}
+	})
+	world.GetEventManager().Subscribe(EventMovement, func(event ecs.Event) {
+		move, ok := event.(EntityMoveEvent)
+		if !ok {
+			return
+		}
+		if isPlayer(world, move.EntityID) {
+			announceStairs(world, move.EntityID, move.ToX, move.ToY)
+		} else {
+			// The monsters have had their turn by now, so stepping back after any of
+			// them moved, seen or not, would hand the player a free one
+			s.cancelUndo("a monster has moved")
+		}
+	})
+	world.GetEventManager().Subscribe(EventAIStateChanged, func(event ecs.Event) {
+		s.cancelUndo("a monster has stirred")
+	})
+	world.GetEventManager().Subscribe(EventEntityRevealed, func(event ecs.Event) {
+		s.cancelUndo("a monster came into view")
+	})
+	for _, eventType := range []ecs.EventType{EventCombat, EventCombatAttack, EventEnemyAttack} {
+		world.GetEventManager().Subscribe(eventType, func(event ecs.Event) {
+			s.cancelUndo("there has been a fight")
+		})
+	}
+	world.GetEventManager().Subscribe(EventItemPickup, func(event ecs.Event) {
+		s.cancelUndo("you picked something up")
+	})
+	world.GetEventManager().Subscribe(EventMechanism, func(event ecs.Event) {
+		s.cancelUndo("you set something off")
+	})
+
+	// An item that's used up or destroyed leaves its quick slot, so a later entity
+	// given the same ID isn't used by mistake
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		if quickSlots := getQuickSlots(world, s.getPlayerID(world)); quickSlots != nil {
+			quickSlots.Clear(event.(ecs.EntityRemovedEvent).EntityID)
+		}
+	})
+
+	s.initialized = true
+}
+
 // Update processes player input and emits appropriate events
 func (s *PlayerTurnProcessorSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+
 	// Find render system if not set
 	if s.renderSystem == nil {
 		for _, system := range world.GetSystems() {
@@ -115,8 +163,13 @@ func (s *PlayerTurnProcessorSystem) Update(world *ecs.World, dt float64) {
 	// Update movement timer
 	s.moveDelayTimer -= dt
 
+	// The movement keys steer the cursor while a throw is being aimed
+	if s.isAiming(world) {
+		return
+	}
+
 	// Check for inventory toggle first, which doesn't count as a turn
-	if inpututil.IsKeyJustPressed(ebiten.KeyI) {
+	if s.keybindings.JustPressed(ActionInventory) {
 		s.toggleInventory()
 		return
 	}
@@ -137,6 +190,7 @@ func (s *PlayerTurnProcessorSystem) Update(world *ecs.World, dt float64) {
 			EntityID: s.getPlayerID(world),
 		})
 	}
+	s.stepping = false
 }
 
 // toggleInventory toggles the inventory display
@@ -154,39 +208,58 @@ func (s *PlayerTurnProcessorSystem) processPlayerInput(world *ecs.World) bool {
 		return false
 	}
 
+	// Use a quick slot (Shift+1-9) without opening the inventory
+	if slot, ok := justPressedQuickSlot(); ok && isQuickSlotModifierHeld() {
+		return s.useQuickSlot(world, playerID, slot)
+	}
+
 	// Check for directional movement
-	for key, dir := range s.movementKeys {
-		keyPressed := false
-
-		// Check for initial key press or continuous movement
-		if inpututil.IsKeyJustPressed(key) {
-			// Key just pressed - reset and start continuous movement
-			s.lastDirection = dir
-			s.moveDelayTimer = s.initialMoveDelay
-			keyPressed = true
-		} else if ebiten.IsKeyPressed(key) && s.lastDirection == dir && s.moveDelayTimer <= 0 {
-			// Key held down and delay elapsed - continuous movement
-			s.moveDelayTimer = s.continuousMoveDelay
-			keyPressed = true
-		}
-
-		if keyPressed {
-			// TODO: Replace with proper movement handling
-			if s.processMovementAction(world, playerID, dir) {
-				return true
+	for _, movement := range movementActions {
+		dir := movement.dir
+		for _, key := range s.keybindings.Keys(movement.action) {
+			keyPressed := false
+			held := false
+
+			// The number keys belong to the quick slots while Shift is held
+			if isQuickSlotModifierHeld() && isQuickSlotKey(key) {
+				continue
+			}
+
+			// Check for initial key press or continuous movement
+			if KeyJustPressed(key) {
+				// Key just pressed - reset and start continuous movement
+				s.lastDirection = dir
+				s.moveDelayTimer = s.initialMoveDelay
+				s.heldStuck.Reset()
+				keyPressed = true
+			} else if KeyPressed(key) && s.lastDirection == dir && s.moveDelayTimer <= 0 {
+				// Key held down and delay elapsed - continuous movement
+				s.moveDelayTimer = s.continuousMoveDelay
+				keyPressed = true
+				held = true
+			}
+
+			if keyPressed {
+				// TODO: Replace with proper movement handling
+				if s.processMovementAction(world, playerID, dir) {
+					if held {
+						s.checkHeldMoveStuck(world, playerID, dir)
+					}
+					return true
+				}
 			}
 		}
 	}
 
 	// Check for other actions
 	// Rest action (.)
-	if inpututil.IsKeyJustPressed(ebiten.KeyPeriod) {
+	if s.keybindings.JustPressed(ActionRest) {
 		s.processRestAction(world, playerID)
 		return true
 	}
 
 	// Check for examine action (E)
-	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+	if s.keybindings.JustPressed(ActionExamine) {
 		// Get player position
 		posComp, exists := world.GetComponent(playerID, components.Position)
 		if !exists {
@@ -217,8 +290,59 @@ func (s *PlayerTurnProcessorSystem) processPlayerInput(world *ecs.World) bool {
 		return true // Consume the turn even if no container found
 	}
 
+	// Equip an item lying underfoot (G)
+	if s.keybindings.JustPressed(ActionEquipGround) {
+		if invSystem := s.getInventorySystem(world); invSystem != nil {
+			return invSystem.EquipFromGround(world, playerID)
+		}
+		return false
+	}
+
+	// Toggle auto-equip of upgrades on pickup (A), doesn't take a turn
+	if s.keybindings.JustPressed(ActionAutoEquip) {
+		if invSystem := s.getInventorySystem(world); invSystem != nil {
+			invSystem.SetAutoEquip(!invSystem.IsAutoEquipEnabled())
+			if invSystem.IsAutoEquipEnabled() {
+				GetMessageLog().AddSystem("Auto-equip enabled.")
+			} else {
+				GetMessageLog().AddSystem("Auto-equip disabled.")
+			}
+		}
+		return false
+	}
+
+	// Open the context menu of what can be done here (M), doesn't take a turn
+	if s.keybindings.JustPressed(ActionContextMenu) {
+		if actions := s.ContextActions(world, playerID); len(actions) > 0 {
+			s.pendingMenu = actions
+		} else {
+			GetMessageLog().AddSystem("There is nothing to do here.")
+		}
+		return false
+	}
+
+	// Undo the last step (Z), doesn't take a turn
+	if s.keybindings.JustPressed(ActionUndo) {
+		s.undoLastMove(world, playerID)
+		return false
+	}
+
+	// Toggle sneaking (C), doesn't take a turn
+	if s.keybindings.JustPressed(ActionSneak) {
+		if playerComp, exists := world.GetComponent(playerID, components.Player); exists {
+			player := playerComp.(*components.PlayerComponent)
+			player.Sneaking = !player.Sneaking
+			if player.Sneaking {
+				GetMessageLog().AddSystem("You crouch and move quietly.")
+			} else {
+				GetMessageLog().AddSystem("You stop sneaking.")
+			}
+		}
+		return false
+	}
+
 	// Check for map transition (stairs) action
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if s.keybindings.JustPressed(ActionUseStairs) {
 		// Get the map registry system to handle the map transition
 		var mapRegistry *MapRegistrySystem
 		for _, system := range world.GetSystems() {
@@ -240,6 +364,14 @@ func (s *PlayerTurnProcessorSystem) processPlayerInput(world *ecs.World) bool {
 	return false
 }
 
+// TakeContextMenu returns the context menu the player opened since the last call, or
+// nil if there is none
+func (s *PlayerTurnProcessorSystem) TakeContextMenu() []ContextAction {
+	actions := s.pendingMenu
+	s.pendingMenu = nil
+	return actions
+}
+
 // getPlayerID returns the player entity ID or 0 if not found
 func (s *PlayerTurnProcessorSystem) getPlayerID(world *ecs.World) ecs.EntityID {
 	playerEntities := world.GetEntitiesWithTag("player")
@@ -249,11 +381,31 @@ func (s *PlayerTurnProcessorSystem) getPlayerID(world *ecs.World) ecs.EntityID {
 	return playerEntities[0].ID
 }
 
+// isAiming returns whether the targeting system is aiming a throw at a tile
+func (s *PlayerTurnProcessorSystem) isAiming(world *ecs.World) bool {
+	for _, system := range world.GetSystems() {
+		if targeting, ok := system.(*TargetingSystem); ok {
+			return targeting.IsAiming()
+		}
+	}
+	return false
+}
+
+// getInventorySystem finds the inventory system in the world
+func (s *PlayerTurnProcessorSystem) getInventorySystem(world *ecs.World) *InventorySystem {
+	for _, system := range world.GetSystems() {
+		if invSystem, ok := system.(*InventorySystem); ok {
+			return invSystem
+		}
+	}
+	return nil
+}
+
 // checkRestInput returns true if the player pressed a rest key
 func (s *PlayerTurnProcessorSystem) checkRestInput() bool {
-	return inpututil.IsKeyJustPressed(ebiten.KeyNumpad5) ||
-		inpututil.IsKeyJustPressed(ebiten.Key5) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyPeriod)
+	return KeyJustPressed(ebiten.KeyNumpad5) ||
+		KeyJustPressed(ebiten.Key5) ||
+		KeyJustPressed(ebiten.KeyPeriod)
 }
 
 // processRestAction handles the rest action
@@ -288,37 +440,125 @@ func (s *PlayerTurnProcessorSystem) processMovementAction(world *ecs.World, play
 	// Calculate movement delta
 	dx, dy := s.getDeltaFromDirection(direction)
 
+	// Remember where the player stood so a misclicked step can be taken back
+	fromX, fromY := position.X, position.Y
+	if s.undoEnabled {
+		s.undo = moveSnapshot{valid: true, mapID: getEntityMapID(world, playerID), x: fromX, y: fromY}
+		s.stepping = true
+	}
+
 	// Emit player movement attempt event
 	world.EmitEvent(PlayerMoveAttemptEvent{
 		EntityID:  playerID,
-		FromX:     position.X,
-		FromY:     position.Y,
+		FromX:     fromX,
+		FromY:     fromY,
 		ToX:       position.X + dx,
 		ToY:       position.Y + dy,
 		Direction: direction,
 	})
 
+	// Bumping into something isn't a step, and may well have started a fight
+	if s.undo.valid && position.X == fromX && position.Y == fromY {
+		s.undo = moveSnapshot{}
+	}
+
 	return true
 }
 
+// checkHeldMoveStuck stops continuous movement once a held key keeps walking the player
+// into a wall. Bumping into a monster or anything else is an action, so it isn't stuck.
+func (s *PlayerTurnProcessorSystem) checkHeldMoveStuck(world *ecs.World, playerID ecs.EntityID, direction int) {
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+	mapComp, exists := world.GetComponent(getEntityMapID(world, playerID), components.MapComponentID)
+	if !exists {
+		return
+	}
+
+	dx, dy := s.getDeltaFromDirection(direction)
+	if !mapComp.(*components.MapComponent).BlocksMovement(pos.X+dx, pos.Y+dy) {
+		s.heldStuck.Reset()
+		return
+	}
+	if s.heldStuck.Record("Held movement", Point{pos.X, pos.Y}) {
+		s.lastDirection = DirNone
+		GetMessageLog().Add("You can't go any further that way.")
+	}
+}
+
+// cancelUndo drops the pending undo, remembering why for the player
+func (s *PlayerTurnProcessorSystem) cancelUndo(reason string) {
+	if s.undo.valid {
+		s.undo = moveSnapshot{reason: reason}
+	}
+}
+
+// isTileVisible returns whether a tile is inside the map and in the player's view
+func isTileVisible(mapData *components.MapComponent, x, y int) bool {
+	return x >= 0 && x < mapData.Width && y >= 0 && y < mapData.Height && mapData.Visible[y][x]
+}
+
+// undoLastMove puts the player back where they stood before their last step, as long
+// as nothing has happened since that a step back couldn't undo
+func (s *PlayerTurnProcessorSystem) undoLastMove(world *ecs.World, playerID ecs.EntityID) {
+	if !s.undoEnabled {
+		GetMessageLog().AddSystem("Undo is off. Start the game with -undo to take back misclicked steps.")
+		return
+	}
+	if !s.undo.valid {
+		if s.undo.reason != "" {
+			GetMessageLog().AddSystem("Can't undo your last step: " + s.undo.reason + ".")
+		} else {
+			GetMessageLog().AddSystem("There is no step to undo.")
+		}
+		return
+	}
+	if getEntityMapID(world, playerID) != s.undo.mapID {
+		s.undo = moveSnapshot{}
+		GetMessageLog().AddSystem("Can't undo your last step: you have left that map.")
+		return
+	}
+
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return
+	}
+	position := posComp.(*components.PositionComponent)
+	position.X, position.Y = s.undo.x, s.undo.y
+	s.undo = moveSnapshot{}
+
+	// Refresh the view without a move event, which would give the monsters a turn.
+	// The camera follows the player on its own.
+	for _, system := range world.GetSystems() {
+		if fovSystem, ok := system.(*FOVSystem); ok {
+			fovSystem.Update(world, 0)
+			break
+		}
+	}
+	GetMessageLog().AddSystem("You step back.")
+}
+
 // getMovementDirection checks for pressed keys and returns the movement direction
 func (s *PlayerTurnProcessorSystem) getMovementDirection() (int, bool) {
 	// First check for newly pressed keys - these take priority
-	for key, dir := range s.movementKeys {
-		if inpututil.IsKeyJustPressed(key) {
-			return dir, true
+	for _, movement := range movementActions {
+		if s.keybindings.JustPressed(movement.action) {
+			return movement.dir, true
 		}
 	}
 
 	// Then check for held keys - this is what enables continuous movement
-	for key, dir := range s.movementKeys {
-		if ebiten.IsKeyPressed(key) {
-			// If any key is currently pressed, check if it's a new direction
-			if dir != s.lastDirection {
-				return dir, true
-			}
-			// If it's the same direction as before, just notify that a key is being held
-			if dir == s.lastDirection {
+	for _, movement := range movementActions {
+		for _, key := range s.keybindings.Keys(movement.action) {
+			if KeyPressed(key) {
+				// If any key is currently pressed, check if it's a new direction
+				if movement.dir != s.lastDirection {
+					return movement.dir, true
+				}
+				// If it's the same direction as before, just notify that a key is being held
 				return DirNone, false
 			}
 		}
@@ -331,6 +571,11 @@ func (s *PlayerTurnProcessorSystem) getMovementDirection() (int, bool) {
 
 // getDeltaFromDirection converts a direction to dx, dy coordinates
 func (s *PlayerTurnProcessorSystem) getDeltaFromDirection(dir int) (int, int) {
+	return directionDelta(dir)
+}
+
+// directionDelta converts a direction to dx, dy coordinates
+func directionDelta(dir int) (int, int) {
 	dx, dy := 0, 0
 
 	switch dir {
@@ -358,7 +603,7 @@ func (s *PlayerTurnProcessorSystem) getDeltaFromDirection(dir int) (int, int) {
 // processInventoryInput handles keyboard input while the inventory is open
 func (s *PlayerTurnProcessorSystem) processInventoryInput(world *ecs.World) {
 	// Check for ESC to close inventory or exit item view mode
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if KeyJustPressed(ebiten.KeyEscape) {
 		if s.renderSystem.IsItemViewMode() {
 			s.renderSystem.ExitItemView()
 		} else {
@@ -387,17 +632,17 @@ func (s *PlayerTurnProcessorSystem) processInventoryInput(world *ecs.World) {
 	}
 
 	// Handle arrow key navigation
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if KeyJustPressed(ebiten.KeyArrowUp) {
 		s.renderSystem.SelectPreviousItem(world)
 		return
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	if KeyJustPressed(ebiten.KeyArrowDown) {
 		s.renderSystem.SelectNextItem(world)
 		return
 	}
 
 	// Process 'L' key or Enter key for looking at details of selected item
-	if inpututil.IsKeyJustPressed(ebiten.KeyL) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if KeyJustPressed(ebiten.KeyL) || KeyJustPressed(ebiten.KeyEnter) {
 		if s.renderSystem.IsItemViewMode() {
 			// Exit item view mode if already in it
 			s.renderSystem.ExitItemView()
@@ -421,7 +666,7 @@ func (s *PlayerTurnProcessorSystem) processInventoryInput(world *ecs.World) {
 	}
 
 	// Process 'E' key to equip the selected item
-	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+	if KeyJustPressed(ebiten.KeyE) {
 		selectedIndex := s.renderSystem.GetSelectedItemIndex()
 		if selectedIndex >= 0 && selectedIndex < inventory.Size() {
 			// Try to find the inventory system to use the item
@@ -436,7 +681,7 @@ func (s *PlayerTurnProcessorSystem) processInventoryInput(world *ecs.World) {
 	}
 
 	// Process 'U' key to use consumable items like bandages
-	if inpututil.IsKeyJustPressed(ebiten.KeyU) {
+	if KeyJustPressed(ebiten.KeyU) {
 		selectedIndex := s.renderSystem.GetSelectedItemIndex()
 		if selectedIndex >= 0 && selectedIndex < inventory.Size() {
 			// Try to find the inventory system to use the item
@@ -456,11 +701,74 @@ func (s *PlayerTurnProcessorSystem) processInventoryInput(world *ecs.World) {
 		return
 	}
 
+	// Socket the selected gem, or unsocket the selected item's gem (S)
+	if s.keybindings.JustPressed(ActionSocket) {
+		selectedIndex := s.renderSystem.GetSelectedItemIndex()
+		if selectedIndex >= 0 && selectedIndex < inventory.Size() {
+			for _, system := range world.GetSystems() {
+				if invSystem, ok := system.(*InventorySystem); ok {
+					invSystem.HandleSocketKeyPress(world, playerID, selectedIndex)
+					break
+				}
+			}
+		}
+		return
+	}
+
+	// Offer the selected item at the altar the player is next to (O)
+	if s.keybindings.JustPressed(ActionOffer) {
+		selectedIndex := s.renderSystem.GetSelectedItemIndex()
+		if selectedIndex >= 0 && selectedIndex < inventory.Size() {
+			for _, system := range world.GetSystems() {
+				if shrineSystem, ok := system.(*ShrineSystem); ok {
+					if shrineSystem.Offer(world, playerID, selectedIndex) {
+						// Close the inventory so the outcome can be read in the log
+						s.renderSystem.ToggleInventoryDisplay()
+						world.EmitEvent(TurnCompletedEvent{
+							EntityID: playerID,
+						})
+					}
+					break
+				}
+			}
+		}
+		return
+	}
+
+	// Process 'J' key to mark the selected item as junk, or Shift+J to drop all junk
+	if KeyJustPressed(ebiten.KeyJ) {
+		for _, system := range world.GetSystems() {
+			if invSystem, ok := system.(*InventorySystem); ok {
+				if KeyPressed(ebiten.KeyShift) {
+					if invSystem.DropAllJunk(world, playerID) > 0 {
+						world.EmitEvent(TurnCompletedEvent{
+							EntityID: playerID,
+						})
+					}
+				} else if selectedIndex := s.renderSystem.GetSelectedItemIndex(); selectedIndex >= 0 && selectedIndex < inventory.Size() {
+					invSystem.ToggleJunk(world, playerID, selectedIndex)
+				}
+				break
+			}
+		}
+		return
+	}
+
+	// Process 1-9 to bind the selected item to a quick slot, or unbind it if it's
+	// already there
+	if slot, ok := justPressedQuickSlot(); ok {
+		selectedIndex := s.renderSystem.GetSelectedItemIndex()
+		if selectedIndex >= 0 && selectedIndex < inventory.Size() {
+			s.bindQuickSlot(world, playerID, slot, inventory.Items[selectedIndex])
+		}
+		return
+	}
+
 	// Process item selection (keys a-z for items 0-25)
 	for i := 0; i < 26 && i < inventory.Size(); i++ {
 		// Calculate the correct key code
 		key := ebiten.Key(int(ebiten.KeyA) + i)
-		if inpututil.IsKeyJustPressed(key) {
+		if KeyJustPressed(key) {
 			// Set the selected item
 			s.renderSystem.SetSelectedItemIndex(i)
 
@@ -534,22 +842,22 @@ func (s *PlayerTurnProcessorSystem) ProcessTurn(world *ecs.World) bool {
 	var actionTaken bool
 	var newX, newY = playerPos.X, playerPos.Y
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+	if KeyJustPressed(ebiten.KeyArrowLeft) {
 		newX--
 		actionTaken = true
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+	} else if KeyJustPressed(ebiten.KeyArrowRight) {
 		newX++
 		actionTaken = true
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	} else if KeyJustPressed(ebiten.KeyArrowUp) {
 		newY--
 		actionTaken = true
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	} else if KeyJustPressed(ebiten.KeyArrowDown) {
 		newY++
 		actionTaken = true
 	}
 
 	// Check for map transition (stairs) action
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	if KeyJustPressed(ebiten.KeyEnter) {
 		// Check if player is on stairs
 		if playerPos.X >= 0 && playerPos.X < currentMap.Width &&
 			playerPos.Y >= 0 && playerPos.Y < currentMap.Height {
@@ -568,7 +876,7 @@ func (s *PlayerTurnProcessorSystem) ProcessTurn(world *ecs.World) bool {
 	}
 
 	// Check for inventory toggle
-	if inpututil.IsKeyJustPressed(ebiten.KeyI) {
+	if KeyJustPressed(ebiten.KeyI) {
 		// Toggle inventory view
 		if s.renderSystem != nil {
 			s.renderSystem.ToggleInventoryDisplay()
@@ -630,11 +938,11 @@ func (s *PlayerTurnProcessorSystem) isAdjacent(x1, y1, x2, y2 int) bool {
 // Handle message window scrolling
 func (s *PlayerTurnProcessorSystem) processInput(world *ecs.World) {
 	// Handle message window scrolling
-	if inpututil.IsKeyJustPressed(ebiten.KeyPageUp) {
+	if KeyJustPressed(ebiten.KeyPageUp) {
 		s.renderSystem.ScrollMessagesUp()
 		return
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyPageDown) {
+	if KeyJustPressed(ebiten.KeyPageDown) {
 		s.renderSystem.ScrollMessagesDown()
 		return
 	}
@@ -0,0 +1,75 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// PointOfInterestSystem tells the player about the world map's ruins, caches and
+// lairs when they first come upon one, and marks it visited on the map's
+// PointsOfInterestComponent. What's at the place is the map's own: ruins and lairs are
+// stairs down to their floor and caches are containers to open.
+type PointOfInterestSystem struct {
+	initialized bool
+}
+
+// NewPointOfInterestSystem creates a new point of interest system
+func NewPointOfInterestSystem() *PointOfInterestSystem {
+	return &PointOfInterestSystem{}
+}
+
+// Initialize sets up event listeners
+func (s *PointOfInterestSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe(EventMovement, func(event ecs.Event) {
+		if move, ok := event.(PlayerMoveEvent); ok && isPlayer(world, move.EntityID) {
+			s.arrive(world, getEntityMapID(world, move.EntityID), move.ToX, move.ToY)
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update does nothing; places are found as the player moves
+func (s *PointOfInterestSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// arrive visits every unvisited place on or next to a tile of the map
+func (s *PointOfInterestSystem) arrive(world *ecs.World, mapID ecs.EntityID, x, y int) {
+	poiComp, exists := world.GetComponent(mapID, components.PointsOfInterest)
+	if !exists {
+		return
+	}
+	pois := poiComp.(*components.PointsOfInterestComponent)
+	for i := range pois.Places {
+		place := &pois.Places[i]
+		if place.Visited || max(abs(place.X-x), abs(place.Y-y)) > 1 {
+			continue
+		}
+		place.Visited = true
+		GetMessageLog().AddEnvironment(discoveryMessage(place))
+	}
+}
+
+// discoveryMessage describes coming upon a place
+func discoveryMessage(place *components.PointOfInterest) string {
+	switch place.Kind {
+	case components.POIRuin:
+		return fmt.Sprintf("You come upon the %s. Stairs lead down into the rubble.", place.Name)
+	case components.POILair:
+		return fmt.Sprintf("Gnawed bones litter the ground around %s. Something big lives below.", place.Name)
+	default:
+		if place.Biome == components.TileDesert {
+			return fmt.Sprintf("Something glints in the sand: a %s!", place.Name)
+		}
+		return fmt.Sprintf("You find a %s.", place.Name)
+	}
+}
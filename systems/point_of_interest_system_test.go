@@ -0,0 +1,39 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+func TestPointOfInterestVisitedWhenPlayerArrives(t *testing.T) {
+	w := newTestWorld(t, 40, 40, 7, 9)
+	world, playerID := w.world, w.playerID
+	pois := &components.PointsOfInterestComponent{Places: []components.PointOfInterest{
+		{Kind: components.POICache, Name: "Buried Cache", X: 10, Y: 10, Biome: components.TileDesert},
+		{Kind: components.POIRuin, Name: "Ruins of Kelamoor", X: 30, Y: 30, Biome: components.TileWasteland},
+	}}
+	world.AddComponent(w.mapID, components.PointsOfInterest, pois)
+
+	system := NewPointOfInterestSystem()
+	system.Initialize(world)
+
+	world.EmitEvent(PlayerMoveEvent{EntityID: playerID, FromX: 7, FromY: 9, ToX: 8, ToY: 9})
+	if pois.Places[0].Visited {
+		t.Error("the cache was visited from two tiles away")
+	}
+
+	world.EmitEvent(PlayerMoveEvent{EntityID: playerID, FromX: 8, FromY: 9, ToX: 9, ToY: 9})
+	if !pois.Places[0].Visited {
+		t.Error("the cache wasn't visited when the player came up beside it")
+	}
+	if pois.Places[1].Visited {
+		t.Error("the ruins were visited without the player going near them")
+	}
+	if got := pois.At(10, 10); got == nil || got.Name != "Buried Cache" {
+		t.Errorf("At(10, 10) = %+v, want the buried cache", got)
+	}
+	if got := pois.At(11, 11); got != nil {
+		t.Errorf("At(11, 11) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,92 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+const (
+	practiceMaxHealthLoss = 0.1  // Share of max health lost each time the player dies in practice mode
+	practiceExpLoss       = 0.25 // Share of experience lost each time the player dies in practice mode
+)
+
+// findWorldState returns the world state component, or nil if there is none yet
+func findWorldState(world *ecs.World) *components.WorldStateComponent {
+	for _, entity := range world.GetEntitiesWithTag("world_state") {
+		if comp, exists := world.GetComponent(entity.ID, components.WorldState); exists {
+			return comp.(*components.WorldStateComponent)
+		}
+	}
+	return nil
+}
+
+// isPracticeRun returns whether the current run is in practice mode, where death
+// isn't final
+func isPracticeRun(world *ecs.World) bool {
+	state := findWorldState(world)
+	return state != nil && state.Practice
+}
+
+// respawnPlayer brings the player back after a practice death. They wake on the up
+// stairs of the floor they died on, weaker and less experienced, with half their
+// health back.
+func (s *DeathSystem) respawnPlayer(world *ecs.World, playerID ecs.EntityID) {
+	if state := findWorldState(world); state != nil {
+		state.PracticeDeaths++
+	}
+
+	if statsComp, exists := world.GetComponent(playerID, components.Stats); exists {
+		stats := statsComp.(*components.StatsComponent)
+		stats.MaxHealth = max(1, stats.MaxHealth-max(1, int(float64(stats.MaxHealth)*practiceMaxHealthLoss)))
+		stats.Health = max(1, stats.MaxHealth/2)
+		stats.Exp -= int(float64(stats.Exp) * practiceExpLoss)
+	}
+
+	if posComp, exists := world.GetComponent(playerID, components.Position); exists {
+		pos := posComp.(*components.PositionComponent)
+		if x, y, found := s.findEntrance(world, getEntityMapID(world, playerID), playerID); found && (x != pos.X || y != pos.Y) {
+			fromX, fromY := pos.X, pos.Y
+			pos.X, pos.Y = x, y
+			world.EmitEvent(EntityMoveEvent{
+				EntityID: playerID,
+				FromX:    fromX,
+				FromY:    fromY,
+				ToX:      pos.X,
+				ToY:      pos.Y,
+			})
+		}
+	}
+
+	GetMessageLog().AddAlert("You come to at the entrance of the floor, weaker than before. (Practice mode)")
+}
+
+// findEntrance returns the up stairs of a map, or the nearest free tile to them if
+// something solid stands there. Maps without up stairs have no entrance.
+func (s *DeathSystem) findEntrance(world *ecs.World, mapID, playerID ecs.EntityID) (int, int, bool) {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return 0, 0, false
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	stairsX, stairsY, found := 0, 0, false
+	for y := 0; y < gameMap.Height && !found; y++ {
+		for x := 0; x < gameMap.Width; x++ {
+			if gameMap.Tiles[y][x] == components.TileStairsUp {
+				stairsX, stairsY, found = x, y, true
+				break
+			}
+		}
+	}
+	if !found {
+		return 0, 0, false
+	}
+
+	x, y, free := FindFreeTileNear(world, mapID, stairsX, stairsY, 3, playerID)
+	if !free {
+		GetDebugLog().Add(fmt.Sprintf("No free tile near the entrance at (%d,%d)", stairsX, stairsY))
+	}
+	return x, y, free
+}
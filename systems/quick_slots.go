@@ -0,0 +1,124 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// quickSlotKeys are the number keys for quick slots 1-9. Outside the inventory they
+// walk the player, so using a slot needs Shift held as well.
+var quickSlotKeys = [components.QuickSlotCount]ebiten.Key{
+	ebiten.Key1, ebiten.Key2, ebiten.Key3,
+	ebiten.Key4, ebiten.Key5, ebiten.Key6,
+	ebiten.Key7, ebiten.Key8, ebiten.Key9,
+}
+
+// justPressedQuickSlot returns the quick slot whose key was just pressed
+func justPressedQuickSlot() (int, bool) {
+	for slot, key := range quickSlotKeys {
+		if KeyJustPressed(key) {
+			return slot, true
+		}
+	}
+	return 0, false
+}
+
+// isQuickSlotKey returns true if the key is one of the quick slot keys
+func isQuickSlotKey(key ebiten.Key) bool {
+	for _, slotKey := range quickSlotKeys {
+		if key == slotKey {
+			return true
+		}
+	}
+	return false
+}
+
+// isQuickSlotModifierHeld returns true while the modifier that turns the number keys
+// into quick slot keys is held
+func isQuickSlotModifierHeld() bool {
+	return KeyPressed(ebiten.KeyShift)
+}
+
+// getQuickSlots returns the player's quick slots, or nil if none have been bound yet
+func getQuickSlots(world *ecs.World, playerID ecs.EntityID) *components.QuickSlotsComponent {
+	if playerID == 0 {
+		return nil
+	}
+	if comp, exists := world.GetComponent(playerID, components.QuickSlots); exists {
+		return comp.(*components.QuickSlotsComponent)
+	}
+	return nil
+}
+
+// bindQuickSlot binds an inventory item to a quick slot, giving the player quick slots
+// the first time one is bound. Binding an item to the slot it's already in unbinds it.
+func (s *PlayerTurnProcessorSystem) bindQuickSlot(world *ecs.World, playerID ecs.EntityID, slot int, itemID ecs.EntityID) {
+	quickSlots := getQuickSlots(world, playerID)
+	if quickSlots == nil {
+		quickSlots = components.NewQuickSlotsComponent()
+		world.AddComponent(playerID, components.QuickSlots, quickSlots)
+	}
+
+	itemName := "item"
+	if nameComp, exists := world.GetComponent(itemID, components.Name); exists {
+		itemName = nameComp.(*components.NameComponent).Name
+	}
+
+	if quickSlots.Get(slot) == itemID {
+		quickSlots.Clear(itemID)
+		GetMessageLog().Add(fmt.Sprintf("Removed %s from quick slot %d", itemName, slot+1))
+		return
+	}
+	quickSlots.Bind(slot, itemID)
+	GetMessageLog().Add(fmt.Sprintf("Bound %s to quick slot %d (Shift+%d)", itemName, slot+1, slot+1))
+}
+
+// useQuickSlot uses the item bound to a quick slot as if it had been picked in the
+// inventory, returning true if it took the player's turn. A slot whose item has left
+// the inventory is cleared.
+func (s *PlayerTurnProcessorSystem) useQuickSlot(world *ecs.World, playerID ecs.EntityID, slot int) bool {
+	quickSlots := getQuickSlots(world, playerID)
+	var itemID ecs.EntityID
+	if quickSlots != nil {
+		itemID = quickSlots.Get(slot)
+	}
+	if itemID == 0 {
+		GetMessageLog().AddSystem(fmt.Sprintf("Quick slot %d is empty.", slot+1))
+		return false
+	}
+
+	invComp, exists := world.GetComponent(playerID, components.Inventory)
+	if !exists {
+		return false
+	}
+	inventory := invComp.(*components.InventoryComponent)
+
+	itemIndex := -1
+	for i, id := range inventory.Items {
+		if id == itemID {
+			itemIndex = i
+			break
+		}
+	}
+	if itemIndex < 0 {
+		quickSlots.Clear(itemID)
+		GetMessageLog().AddSystem(fmt.Sprintf("The item in quick slot %d is no longer carried.", slot+1))
+		return false
+	}
+
+	invSystem := s.getInventorySystem(world)
+	if invSystem == nil {
+		return false
+	}
+	used := invSystem.HandleUseKeyPress(world, playerID, itemIndex)
+
+	// Dropping or giving the item away doesn't remove the entity, so check again
+	if !inventory.Contains(itemID) {
+		quickSlots.Clear(itemID)
+	}
+	return used
+}
@@ -3,7 +3,10 @@ package systems
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
@@ -26,24 +29,97 @@ type RenderSystem struct {
 	initialized         bool         // Whether the system has been initialized
 	world               *ecs.World
 	messageScrollOffset int // New field for message scrolling
+
+	// Radial reveal played the first time a floor is entered
+	reduceMotion   bool                  // Disables the reveal animation
+	revealedMaps   map[ecs.EntityID]bool // Maps that have already played the reveal
+	lastMapID      ecs.EntityID          // Active map during the previous update
+	revealMapID    ecs.EntityID          // Map the reveal is playing on
+	revealTimer    float64               // Time left in the reveal, 0 when not playing
+	revealDuration float64               // Total length of the reveal in seconds
+	revealOriginX  int                   // Tile the reveal spreads out from
+	revealOriginY  int
+	revealRadius   float64 // Distance the reveal covers by the time it ends
+
+	monsterHP MonsterHPDisplay // How monster health is shown on the map
+	palette   config.Palette   // Colors the panels and overlays are drawn in
+	glyphMode GlyphMode        // Whether tiles and entities are drawn as ASCII, pictures or as defined
+
+	// Warning shown while the player's health is low
+	lowHealthVignette  bool          // Redden the screen edges, off for players who find it distracting
+	lowHealthThreshold float64       // Fraction of max health below which the warning shows
+	lowHealthAlerted   bool          // The alert has been given and health hasn't recovered since
+	vignette           *ebiten.Image // Edge mask, built on first use
+
+	showTurnOrder bool // Whether the turn order HUD is drawn over the map
+
+	showDebugOverlay bool         // Whether the debug overlay tints the map
+	debugOverlay     DebugOverlay // Which view the debug overlay shows
+
+	// Tiles other systems asked to tint. Requests made during one tick are drawn until
+	// the next tick has finished, however many frames that takes.
+	highlights      []tileHighlight // Requested during the current tick
+	drawnHighlights []tileHighlight // Requested during the last tick, drawn under the entities
+	flashes         []tileFlash     // Highlights that stay up for a while, fading out
+
+	// Radar blips from a scan, drawn on tiles out of sight until cleared
+	radarMapID    ecs.EntityID // Map the blips belong to
+	radarBlips    []Point
+	radarStrength float64 // How bright the blips are, fading from 1 to 0
+}
+
+// tileHighlight is a tint requested for one map tile
+type tileHighlight struct {
+	X, Y  int
+	Color color.Color
 }
 
+// tileFlash is a highlight that fades out over a set time without being requested again
+type tileFlash struct {
+	Tiles     []Point
+	Color     color.RGBA
+	Remaining float64 // Seconds left
+	Duration  float64
+}
+
+// MonsterHPDisplay is a way of showing monster health on the map
+type MonsterHPDisplay string
+
+const (
+	MonsterHPOff    MonsterHPDisplay = "off"    // Health is only shown while targeting
+	MonsterHPNumber MonsterHPDisplay = "number" // A small HP number next to each monster
+	MonsterHPTint   MonsterHPDisplay = "tint"   // Monsters shade from green to red as they are hurt
+)
+
 // NewRenderSystem creates a new rendering system
 func NewRenderSystem(tileset *Tileset) *RenderSystem {
 	return &RenderSystem{
-		tileset:           tileset,
-		cameraX:           0,
-		cameraY:           0,
-		cameraTargetID:    0,
-		debugWindowActive: false,
-		debugScrollOffset: 0,
-		showInventory:     false,
-		itemViewMode:      false,
-		selectedItemIndex: -1,
-		initialized:       false,
+		tileset:            tileset,
+		cameraX:            0,
+		cameraY:            0,
+		cameraTargetID:     0,
+		debugWindowActive:  false,
+		debugScrollOffset:  0,
+		showInventory:      false,
+		itemViewMode:       false,
+		selectedItemIndex:  -1,
+		initialized:        false,
+		revealedMaps:       make(map[ecs.EntityID]bool),
+		revealDuration:     0.5,
+		monsterHP:          MonsterHPOff,
+		palette:            config.DefaultPalette,
+		glyphMode:          GlyphsMixed,
+		lowHealthVignette:  true,
+		lowHealthThreshold: DefaultLowHealthThreshold,
 	}
 }
 
+// SetPalette changes the colors the interface is drawn in, such as to swap in the
+// high-contrast palette
+func (s *RenderSystem) SetPalette(palette config.Palette) {
+	s.palette = palette
+}
+
 // Initialize sets up the render system
 func (s *RenderSystem) Initialize(world *ecs.World) {
 	if s.initialized {
@@ -88,6 +164,175 @@ func (s *RenderSystem) Update(world *ecs.World, dt float64) {
 	if !s.initialized {
 		s.Initialize(world)
 	}
+
+	s.updateReveal(world, dt)
+	s.updateFlashes(dt)
+	s.updateLowHealth(world)
+
+	// Everything that runs before the renderer has had its say, so this tick's
+	// highlights are the ones to draw
+	s.drawnHighlights, s.highlights = s.highlights, s.drawnHighlights[:0]
+}
+
+// HighlightTile asks for a tile of the active map to be tinted. Systems call it from
+// Update every tick they want the highlight shown; it is drawn over the map and under
+// entities.
+func (s *RenderSystem) HighlightTile(x, y int, clr color.Color) {
+	s.highlights = append(s.highlights, tileHighlight{X: x, Y: y, Color: clr})
+}
+
+// FlashTiles highlights tiles of the active map for a number of seconds, fading as
+// the time runs out, for effects over too quickly to be requested every tick
+func (s *RenderSystem) FlashTiles(tiles []Point, clr color.RGBA, duration float64) {
+	if len(tiles) == 0 || duration <= 0 {
+		return
+	}
+	s.flashes = append(s.flashes, tileFlash{Tiles: tiles, Color: clr, Remaining: duration, Duration: duration})
+}
+
+// Palette returns the colors the interface is drawn in
+func (s *RenderSystem) Palette() config.Palette {
+	return s.palette
+}
+
+// updateFlashes counts down the flashes and requests this tick's highlights for the
+// ones still showing
+func (s *RenderSystem) updateFlashes(dt float64) {
+	kept := s.flashes[:0]
+	for _, flash := range s.flashes {
+		flash.Remaining -= dt
+		if flash.Remaining <= 0 {
+			continue
+		}
+		strength := flash.Remaining / flash.Duration
+		clr := color.RGBA{
+			R: uint8(float64(flash.Color.R) * strength),
+			G: uint8(float64(flash.Color.G) * strength),
+			B: uint8(float64(flash.Color.B) * strength),
+			A: 255,
+		}
+		for _, tile := range flash.Tiles {
+			s.HighlightTile(tile.X, tile.Y, clr)
+		}
+		kept = append(kept, flash)
+	}
+	s.flashes = kept
+}
+
+// ClearHighlights drops the highlights requested so far this tick
+func (s *RenderSystem) ClearHighlights() {
+	s.highlights = s.highlights[:0]
+}
+
+// SetRadarBlips replaces the radar blips shown on a map. Strength runs from 1 for a
+// fresh scan down to 0 and dims the blips as it falls.
+func (s *RenderSystem) SetRadarBlips(mapID ecs.EntityID, blips []Point, strength float64) {
+	s.radarMapID = mapID
+	s.radarBlips = blips
+	s.radarStrength = max(0, min(strength, 1))
+}
+
+// ClearRadarBlips removes all radar blips
+func (s *RenderSystem) ClearRadarBlips() {
+	s.radarMapID = 0
+	s.radarBlips = nil
+}
+
+// SetMonsterHPDisplay sets how monster health is shown on the map
+func (s *RenderSystem) SetMonsterHPDisplay(mode MonsterHPDisplay) {
+	s.monsterHP = mode
+}
+
+// SetReduceMotion turns the map reveal animation off or on
+func (s *RenderSystem) SetReduceMotion(reduce bool) {
+	s.reduceMotion = reduce
+	if reduce {
+		s.revealTimer = 0
+	}
+}
+
+// ResetMapReveal forgets which maps have been revealed, e.g. when a new world is generated
+func (s *RenderSystem) ResetMapReveal() {
+	s.revealedMaps = make(map[ecs.EntityID]bool)
+	s.lastMapID = 0
+	s.revealTimer = 0
+}
+
+// updateReveal advances the reveal animation and starts it when a floor is entered for the first time
+func (s *RenderSystem) updateReveal(world *ecs.World, dt float64) {
+	// Any key skips a running reveal
+	if s.revealTimer > 0 {
+		if len(JustPressedKeys()) > 0 {
+			s.revealTimer = 0
+		} else {
+			s.revealTimer = math.Max(0, s.revealTimer-dt)
+		}
+	}
+
+	activeMap := s.getActiveMap(world)
+	if activeMap == nil || activeMap.ID == s.lastMapID {
+		return
+	}
+	s.lastMapID = activeMap.ID
+
+	if s.revealedMaps[activeMap.ID] {
+		return
+	}
+	s.revealedMaps[activeMap.ID] = true
+
+	if s.reduceMotion {
+		return
+	}
+	if typeComp, exists := world.GetComponent(activeMap.ID, components.MapType); exists &&
+		typeComp.(*components.MapTypeComponent).MapType == "worldmap" {
+		return
+	}
+
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	posComp, exists := world.GetComponent(playerEntities[0].ID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	mapComp, exists := world.GetComponent(activeMap.ID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	// The reveal spreads until it reaches the furthest tile that will be drawn
+	radius := 1.0
+	for y := 0; y < mapData.Height; y++ {
+		for x := 0; x < mapData.Width; x++ {
+			if mapData.Visible[y][x] || mapData.Explored[y][x] {
+				radius = math.Max(radius, math.Hypot(float64(x-pos.X), float64(y-pos.Y)))
+			}
+		}
+	}
+
+	s.revealMapID = activeMap.ID
+	s.revealOriginX = pos.X
+	s.revealOriginY = pos.Y
+	s.revealRadius = radius
+	s.revealTimer = s.revealDuration
+}
+
+// revealAlpha returns how far the reveal has faded in a tile, from 0 (hidden) to 1 (fully drawn)
+func (s *RenderSystem) revealAlpha(mapID ecs.EntityID, x, y int) float64 {
+	if s.revealTimer <= 0 || mapID != s.revealMapID {
+		return 1
+	}
+
+	// The edge of the reveal fades in over a couple of tiles
+	const edgeWidth = 2.0
+	progress := 1 - s.revealTimer/s.revealDuration
+	front := progress * (s.revealRadius + edgeWidth)
+	dist := math.Hypot(float64(x-s.revealOriginX), float64(y-s.revealOriginY))
+	return math.Max(0, math.Min(1, (front-dist)/edgeWidth))
 }
 
 // ToggleDebugWindow toggles the visibility of the debug message window
@@ -101,9 +346,9 @@ func (s *RenderSystem) ToggleDebugWindow() {
 // ToggleInventoryDisplay toggles between stats panel and inventory panel
 func (s *RenderSystem) ToggleInventoryDisplay() {
 	s.showInventory = !s.showInventory
-	// Reset item view mode when toggling inventory
+	// Reset item view mode when toggling inventory, but keep the selection so the
+	// inventory reopens on the item last picked
 	s.itemViewMode = false
-	s.selectedItemIndex = -1
 	if s.showInventory {
 		GetMessageLog().Add("Inventory opened")
 	} else {
@@ -130,7 +375,6 @@ func (s *RenderSystem) ViewItemDetails(itemIndex int) {
 // ExitItemView returns to the normal inventory view
 func (s *RenderSystem) ExitItemView() {
 	s.itemViewMode = false
-	s.selectedItemIndex = -1
 }
 
 // No need for equipment caching - it will be rendered directly in drawStatsPanel
@@ -138,18 +382,24 @@ func (s *RenderSystem) ExitItemView() {
 // Draw renders all entities with position and renderable components
 func (s *RenderSystem) Draw(world *ecs.World, screen *ebiten.Image) {
 	// Clear the screen
-	screen.Fill(color.RGBA{0, 0, 0, 255})
+	screen.Fill(s.palette.Background)
 
 	// Check if we're in world map tester mode
 	isWorldMapTester := len(world.GetEntitiesWithTag("worldmap_tester")) > 0
 
 	// Draw the game area (map)
 	s.drawGameScreen(world, screen)
+	s.drawLowHealthVignette(world, screen)
 
 	// Only draw UI elements if not in world map tester mode
 	if !isWorldMapTester {
+		if s.showTurnOrder {
+			s.drawTurnOrder(world, screen)
+		}
 		if s.showInventory {
 			s.drawInventoryPanel(world, screen)
+		} else if targetID := s.getExaminedMonster(world); targetID != 0 {
+			s.drawMonsterDetailsPanel(world, screen, targetID)
 		} else {
 			s.drawStatsPanel(world, screen)
 		}
@@ -217,8 +467,53 @@ func (s *RenderSystem) drawGameScreen(world *ecs.World, screen *ebiten.Image) {
 	// Draw the active map
 	s.drawStandardMap(world, screen, activeMap.ID, tileMapping, cameraX, cameraY)
 
+	// Tint the tiles other systems asked for
+	s.drawHighlights(screen, cameraX, cameraY)
+
+	// Draw the planned auto-explore/travel route under the entities
+	s.drawPathPreview(world, screen, cameraX, cameraY)
+
+	// Tint the map by the debug overlay's view, also under the entities
+	s.drawDebugOverlay(world, screen, activeMap.ID, cameraX, cameraY)
+
 	// Draw all entities
 	s.drawEntities(world, screen, cameraX, cameraY)
+
+	// Radar blips mark scanned monsters the player can't see
+	s.drawRadarBlips(world, screen, activeMap.ID, cameraX, cameraY)
+
+	// Place names over the surface
+	s.drawPlaceLabels(world, screen, activeMap.ID, cameraX, cameraY)
+}
+
+// drawPlaceLabels writes the names of the world map's regions and stations over the
+// tiles in view. Station names sit just above their station; region names are
+// centered on the tile the region picked for its label.
+func (s *RenderSystem) drawPlaceLabels(world *ecs.World, screen *ebiten.Image, mapID ecs.EntityID, cameraX, cameraY int) {
+	namesComp, exists := world.GetComponent(mapID, components.RegionNames)
+	if !exists {
+		return
+	}
+	names := namesComp.(*components.RegionNamesComponent)
+
+	const scale = 0.5
+	glyphWidth := int(float64(s.tileset.TileSize) * scale)
+	draw := func(place components.NamedPlace, offsetY int, clr color.Color) {
+		x, y := place.X-cameraX, place.Y-cameraY
+		if x < 0 || x >= config.GameScreenWidth || y < 0 || y >= config.GameScreenHeight {
+			return
+		}
+		px := x*s.tileset.TileSize + s.tileset.TileSize/2 - len(place.Name)*glyphWidth/2
+		py := y*s.tileset.TileSize + offsetY
+		s.tileset.DrawSmallString(screen, place.Name, px, py, scale, clr)
+	}
+
+	for _, region := range names.Regions {
+		draw(region, s.tileset.TileSize/4, s.palette.PanelDim)
+	}
+	for _, station := range names.Stations {
+		draw(station, -s.tileset.TileSize/2, s.palette.PanelHeading)
+	}
 }
 
 // drawStandardMap draws a standard non-chunked map
@@ -253,6 +548,19 @@ func (s *RenderSystem) drawStandardMap(world *ecs.World, screen *ebiten.Image, m
 		screenHeight = config.GameScreenHeight
 	}
 
+	// Weather and the time of day on the surface limit visibility and tint the map
+	var weather *WeatherType
+	var timeOfDay *TimeOfDay
+	if isWorldMap {
+		if weatherSystem := s.getWeatherSystem(world); weatherSystem != nil {
+			weather = weatherSystem.CurrentWeather()
+		}
+		if timeSystem := s.getTimeSystem(world); timeSystem != nil {
+			timeOfDay = timeSystem.TimeOfDay(world)
+		}
+	}
+	sightLimited := weather != nil || (timeOfDay != nil && timeOfDay.SightRange > 0)
+
 	// Draw map tiles that are visible in the viewport
 	for y := 0; y < screenHeight; y++ {
 		for x := 0; x < screenWidth; x++ {
@@ -267,13 +575,13 @@ func (s *RenderSystem) drawStandardMap(world *ecs.World, screen *ebiten.Image, m
 			}
 
 			// Check tile visibility - on world maps everything is visible
-			isVisible := mapData.Visible[worldY][worldX] || isWorldMap
+			isVisible := mapData.Visible[worldY][worldX] || (isWorldMap && !sightLimited)
 			isExplored := mapData.Explored[worldY][worldX] || isWorldMap
 
 			// Only draw tiles that are visible or have been explored
 			if !isVisible && !isExplored {
 				// Draw unexplored tiles as black
-				s.tileset.DrawTile(screen, ' ', x, y, color.RGBA{0, 0, 0, 255})
+				s.tileset.DrawTile(screen, ' ', x, y, s.palette.Background)
 				continue
 			}
 
@@ -282,13 +590,22 @@ func (s *RenderSystem) drawStandardMap(world *ecs.World, screen *ebiten.Image, m
 
 			// Get the tile's visual definition from the mapping
 			tileDef := tileMapping.GetTileDefinition(tileType)
+			if components.GetTileProperties(tileType).IsHazard && s.palette.HazardTile != (color.RGBA{}) {
+				tileDef.FG = s.palette.HazardTile
+			}
 
 			// Create a modified color based on visibility
 			var fg color.Color
 
-			if isVisible || isWorldMap {
+			if isVisible {
 				// Fully visible - use normal colors
 				fg = tileDef.FG
+				if weather != nil {
+					fg = tintColor(fg, weather.Tint, 0.5)
+				}
+				if timeOfDay != nil && timeOfDay.TintAmount > 0 {
+					fg = tintColor(fg, timeOfDay.Tint, timeOfDay.TintAmount)
+				}
 			} else if isExplored {
 				// Explored but not visible - darken the colors
 				if fgRGBA, ok := tileDef.FG.(color.RGBA); ok {
@@ -301,20 +618,101 @@ func (s *RenderSystem) drawStandardMap(world *ecs.World, screen *ebiten.Image, m
 					}
 				} else {
 					// Default darkening if color conversion fails
-					fg = color.RGBA{40, 40, 40, 255}
+					fg = s.palette.Remembered
 				}
 			}
 
-			// Draw the tile using either position or glyph based on the definition
-			if tileDef.UseTilePos {
-				// Use position-based tile reference
-				tileID := NewTileID(tileDef.TileX, tileDef.TileY)
-				s.tileset.DrawTileByID(screen, tileID, x, y, fg, 0)
-			} else {
-				// Use character-based reference
-				s.tileset.DrawTile(screen, tileDef.Glyph, x, y, fg)
+			// Fade the tile in while the map reveal is playing
+			if alpha := s.revealAlpha(mapID, worldX, worldY); alpha < 1 {
+				fg = tintColor(fg, s.palette.Background, 1-alpha)
 			}
+
+			// Draw the tile by position or glyph, as the definition and glyph mode say
+			s.tileset.DrawTileByID(screen, s.mapTile(tileType, tileDef), x, y, fg, 0)
+		}
+	}
+}
+
+// drawPathPreview highlights the route planned by auto-explore or travel,
+// dimming the markers the further along the route they are
+func (s *RenderSystem) drawPathPreview(world *ecs.World, screen *ebiten.Image, cameraX, cameraY int) {
+	var path []components.PathNode
+	for _, system := range world.GetSystems() {
+		if autoExplore, ok := system.(*AutoExploreSystem); ok {
+			path = autoExplore.GetPath()
+			break
+		}
+	}
+	if len(path) == 0 {
+		return
+	}
+
+	for i, node := range path {
+		screenX := node.X - cameraX
+		screenY := node.Y - cameraY
+		if screenX < 0 || screenX >= config.GameScreenWidth || screenY < 0 || screenY >= config.GameScreenHeight {
+			continue
+		}
+
+		// Fade from full brightness at the next step down to 30% at the end
+		brightness := 1.0 - 0.7*float64(i)/float64(len(path))
+		pathColor := color.RGBA{
+			R: uint8(80 * brightness),
+			G: uint8(200 * brightness),
+			B: uint8(255 * brightness),
+			A: 255,
 		}
+
+		// Middle dot (CP437 250)
+		s.tileset.DrawTileByID(screen, NewTileID(10, 15), screenX, screenY, pathColor, 0)
+	}
+}
+
+// drawHighlights tints the requested tiles that are on screen
+func (s *RenderSystem) drawHighlights(screen *ebiten.Image, cameraX, cameraY int) {
+	for _, highlight := range s.drawnHighlights {
+		screenX := highlight.X - cameraX
+		screenY := highlight.Y - cameraY
+		if screenX < 0 || screenX >= config.GameScreenWidth || screenY < 0 || screenY >= config.GameScreenHeight {
+			continue
+		}
+
+		// Full block (CP437 219)
+		s.tileset.DrawTileByID(screen, NewTileID(11, 13), screenX, screenY, highlight.Color, 0)
+	}
+}
+
+// drawRadarBlips draws the radar blips for the active map on tiles that aren't in view.
+// Visible monsters are drawn as themselves, so they need no blip.
+func (s *RenderSystem) drawRadarBlips(world *ecs.World, screen *ebiten.Image, mapID ecs.EntityID, cameraX, cameraY int) {
+	if len(s.radarBlips) == 0 || s.radarMapID != mapID {
+		return
+	}
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	// Bright green fading towards a dim one as the scan runs down
+	blipColor := color.RGBA{
+		R: uint8(20 + 40*s.radarStrength),
+		G: uint8(80 + 175*s.radarStrength),
+		B: uint8(40 + 60*s.radarStrength),
+		A: 255,
+	}
+	for _, blip := range s.radarBlips {
+		if blip.X < 0 || blip.X >= mapData.Width || blip.Y < 0 || blip.Y >= mapData.Height || mapData.Visible[blip.Y][blip.X] {
+			continue
+		}
+		screenX := blip.X - cameraX
+		screenY := blip.Y - cameraY
+		if screenX < 0 || screenX >= config.GameScreenWidth || screenY < 0 || screenY >= config.GameScreenHeight {
+			continue
+		}
+
+		// Bullet (CP437 7)
+		s.tileset.DrawTileByID(screen, NewTileID(7, 0), screenX, screenY, blipColor, 0)
 	}
 }
 
@@ -342,6 +740,9 @@ func (s *RenderSystem) drawEntities(world *ecs.World, screen *ebiten.Image, came
 	}
 
 	entitiesRendered := 0
+	occupied := make(map[Point]bool) // Screen tiles something was drawn on
+	var hpLabels []monsterHPLabel
+	var alerts []monsterHPLabel
 
 	// First, draw the player if we're on the world map
 	if activeMapType == "worldmap" {
@@ -369,7 +770,10 @@ func (s *RenderSystem) drawEntities(world *ecs.World, screen *ebiten.Image, came
 					}
 
 					// Use the train sprite for the player on world map
-					tileID := NewTileID(4, 14) // Train sprite position
+					tileID, rotates := s.entityTile(world, entity.ID, true, 4, 14, 0)
+					if !rotates {
+						rotation = 0
+					}
 					s.tileset.DrawTileByID(screen, tileID, screenX, screenY, rend.FG, rotation)
 					entitiesRendered++
 				}
@@ -377,21 +781,16 @@ func (s *RenderSystem) drawEntities(world *ecs.World, screen *ebiten.Image, came
 		}
 	}
 
-	// Then draw all other entities
-	for _, entity := range world.GetAllEntities() {
+	// Then draw all other entities on the active map that can be drawn
+	world.Query(components.MapContextID, components.Position, components.Renderable).Each(func(entity *ecs.Entity) {
 		// Skip map and tilemap entities since we handle those separately
 		if entity.HasTag("map") || entity.HasTag("tilemap") {
-			continue
+			return
 		}
 
 		// Skip player on world map since we already drew it
 		if activeMapType == "worldmap" && entity.HasTag("player") {
-			continue
-		}
-
-		// Check if entity has a map context component
-		if !world.HasComponent(entity.ID, components.MapContextID) {
-			continue
+			return
 		}
 
 		mapContextComp, _ := world.GetComponent(entity.ID, components.MapContextID)
@@ -399,97 +798,129 @@ func (s *RenderSystem) drawEntities(world *ecs.World, screen *ebiten.Image, came
 
 		// Skip entities that don't belong to the active map
 		if mapContext.MapID != activeMapID {
-			continue
+			return
 		}
 
 		// Extra safety - if we're on the world map, don't render enemies
 		if activeMapType == "worldmap" && entity.HasTag("enemy") {
-			continue
+			return
 		}
 
-		// Only render entities that have both Position and Renderable components
-		posComp, hasPos := world.GetComponent(entity.ID, components.Position)
-		rendComp, hasRend := world.GetComponent(entity.ID, components.Renderable)
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		rendComp, _ := world.GetComponent(entity.ID, components.Renderable)
+		pos := posComp.(*components.PositionComponent)
+		rend := rendComp.(*components.RenderableComponent)
 
-		if hasPos && hasRend {
-			pos := posComp.(*components.PositionComponent)
-			rend := rendComp.(*components.RenderableComponent)
+		// Check if the entity's position is within bounds
+		if pos.X < 0 || pos.X >= mapComponent.Width || pos.Y < 0 || pos.Y >= mapComponent.Height {
+			return
+		}
 
-			// Check if the entity's position is within bounds
-			if pos.X < 0 || pos.X >= mapComponent.Width || pos.Y < 0 || pos.Y >= mapComponent.Height {
-				continue
-			}
+		// Check if the entity is in a visible tile
+		// Player is always visible
+		isVisible := mapComponent.Visible[pos.Y][pos.X] || entity.HasTag("player") || activeMapType == "worldmap"
+		isExplored := mapComponent.Explored[pos.Y][pos.X] || activeMapType == "worldmap"
+
+		// Treat certain tile types as always visible when explored
+		var tileTypeVisible bool = false
+		if isExplored && !isVisible {
+			// Get tile type at this position
+			tileType := mapComponent.Tiles[pos.Y][pos.X]
+			// Doors and stairs should remain visible when explored
+			tileTypeVisible = tileType == components.TileDoor ||
+				tileType == components.TileStairsUp ||
+				tileType == components.TileStairsDown
+		}
 
-			// Check if the entity is in a visible tile
-			// Player is always visible
-			isVisible := mapComponent.Visible[pos.Y][pos.X] || entity.HasTag("player") || activeMapType == "worldmap"
-			isExplored := mapComponent.Explored[pos.Y][pos.X] || activeMapType == "worldmap"
-
-			// Treat certain tile types as always visible when explored
-			var tileTypeVisible bool = false
-			if isExplored && !isVisible {
-				// Get tile type at this position
-				tileType := mapComponent.Tiles[pos.Y][pos.X]
-				// Doors and stairs should remain visible when explored
-				tileTypeVisible = tileType == components.TileDoor ||
-					tileType == components.TileStairsUp ||
-					tileType == components.TileStairsDown
+		// Only draw if the tile is visible or it's explored and should remain visible
+		// On world map, always draw entities
+		if !isVisible && !(isExplored && (entity.HasTag("stairs") || entity.HasTag("door") || tileTypeVisible)) && activeMapType != "worldmap" {
+			return
+		}
+
+		// If the tile is only explored but not currently visible, draw with reduced brightness
+		// No darkening on world map
+		var entityColor color.Color
+		if isVisible || activeMapType == "worldmap" {
+			entityColor = rend.FG
+		} else if isExplored {
+			// Entity is in an explored but not currently visible tile
+			if fgRGBA, ok := rend.FG.(color.RGBA); ok {
+				// Reduce brightness by 60%
+				entityColor = color.RGBA{
+					R: uint8(float64(fgRGBA.R) * 0.4),
+					G: uint8(float64(fgRGBA.G) * 0.4),
+					B: uint8(float64(fgRGBA.B) * 0.4),
+					A: fgRGBA.A,
+				}
+			} else {
+				// Default darkening if color conversion fails
+				entityColor = s.palette.Remembered
 			}
+		}
 
-			// Only draw if the tile is visible or it's explored and should remain visible
-			// On world map, always draw entities
-			if !isVisible && !(isExplored && (entity.HasTag("stairs") || entity.HasTag("door") || tileTypeVisible)) && activeMapType != "worldmap" {
-				continue
+		// Containers show whether they are locked, unopened or emptied
+		entityColor, containerMark := s.containerLook(world, entity.ID, entityColor)
+
+		// Shade hurt monsters, or note their HP for a label once everything is drawn
+		healthFraction, healthText, hasHealth := s.monsterHealth(world, entity)
+		var labelColor color.Color = s.healthColor(healthFraction)
+		if hasHealth && s.monsterHP == MonsterHPTint {
+			entityColor = tintColor(entityColor, s.healthColor(healthFraction), 0.6)
+		}
+		if !isVisible {
+			labelColor = tintColor(labelColor, s.palette.Background, 0.6)
+		}
+
+		if alpha := s.revealAlpha(activeMapID, pos.X, pos.Y); alpha < 1 && !entity.HasTag("player") {
+			entityColor = tintColor(entityColor, s.palette.Background, 1-alpha)
+			labelColor = tintColor(labelColor, s.palette.Background, 1-alpha)
+		}
+
+		// Use camera system to convert world position to screen position
+		var screenX, screenY int
+		screenX = pos.X - cameraX
+		screenY = pos.Y - cameraY
+
+		// Only draw entities within the visible game screen
+		if screenX >= 0 && screenX < config.GameScreenWidth &&
+			screenY >= 0 && screenY < config.GameScreenHeight {
+			// Get rotation if entity has a RotationComponent
+			var rotation float64
+			if rotComp, exists := world.GetComponent(entity.ID, components.Rotation); exists {
+				rotation = rotComp.(*components.RotationComponent).Angle
 			}
 
-			// If the tile is only explored but not currently visible, draw with reduced brightness
-			// No darkening on world map
-			var entityColor color.Color
-			if isVisible || activeMapType == "worldmap" {
-				entityColor = rend.FG
-			} else if isExplored {
-				// Entity is in an explored but not currently visible tile
-				if fgRGBA, ok := rend.FG.(color.RGBA); ok {
-					// Reduce brightness by 60%
-					entityColor = color.RGBA{
-						R: uint8(float64(fgRGBA.R) * 0.4),
-						G: uint8(float64(fgRGBA.G) * 0.4),
-						B: uint8(float64(fgRGBA.B) * 0.4),
-						A: fgRGBA.A,
-					}
-				} else {
-					// Default darkening if color conversion fails
-					entityColor = color.RGBA{40, 40, 40, 255}
-				}
+			// Draw the entity by position or glyph, as its renderable and the glyph mode
+			// say. Characters are never rotated.
+			tileID, rotates := s.entityTile(world, entity.ID, rend.UseTilePos, rend.TileX, rend.TileY, rend.Char)
+			if !rotates {
+				rotation = 0
 			}
+			s.tileset.DrawTileByID(screen, tileID, screenX, screenY, entityColor, rotation)
+			entitiesRendered++
+			occupied[Point{screenX, screenY}] = true
 
-			// Use camera system to convert world position to screen position
-			var screenX, screenY int
-			screenX = pos.X - cameraX
-			screenY = pos.Y - cameraY
-
-			// Only draw entities within the visible game screen
-			if screenX >= 0 && screenX < config.GameScreenWidth &&
-				screenY >= 0 && screenY < config.GameScreenHeight {
-				// Get rotation if entity has a RotationComponent
-				var rotation float64
-				if rotComp, exists := world.GetComponent(entity.ID, components.Rotation); exists {
-					rotation = rotComp.(*components.RotationComponent).Angle
+			if hasHealth && s.monsterHP == MonsterHPNumber {
+				hpLabels = append(hpLabels, monsterHPLabel{X: screenX, Y: screenY, Text: healthText, Color: labelColor})
+			}
+			if containerMark != "" {
+				alerts = append(alerts, monsterHPLabel{X: screenX, Y: screenY, Text: containerMark, Color: s.palette.ContainerLocked})
+			}
+			if mark, markColor, alerted := s.monsterAlert(world, entity); alerted {
+				if !isVisible {
+					markColor = tintColor(markColor, s.palette.Background, 0.6)
 				}
-
-				// Draw the entity using either position or glyph based approach
-				if rend.UseTilePos {
-					// Use position-based reference
-					tileID := NewTileID(rend.TileX, rend.TileY)
-					s.tileset.DrawTileByID(screen, tileID, screenX, screenY, entityColor, rotation)
-				} else {
-					// Use character-based reference
-					s.tileset.DrawTile(screen, rend.Char, screenX, screenY, entityColor)
+				if alpha := s.revealAlpha(activeMapID, pos.X, pos.Y); alpha < 1 {
+					markColor = tintColor(markColor, s.palette.Background, 1-alpha)
 				}
-				entitiesRendered++
+				alerts = append(alerts, monsterHPLabel{X: screenX, Y: screenY, Text: mark, Color: markColor})
 			}
 		}
-	}
+	})
+
+	s.drawMonsterHPLabels(screen, hpLabels, occupied)
+	s.drawMonsterAlerts(screen, alerts)
 
 	// Debug log for number of entities rendered
 	if activeMapType == "worldmap" {
@@ -505,11 +936,11 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 	// Draw stats panel border and background
 	for y := 0; y < config.GameScreenHeight; y++ {
 		// Draw vertical border
-		s.tileset.DrawTile(screen, '|', config.GameScreenWidth, y, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawTile(screen, '|', config.GameScreenWidth, y, s.palette.PanelText)
 
 		// Draw background for better readability (optional dark background)
 		for x := config.GameScreenWidth + 1; x < config.ScreenWidth; x++ {
-			s.tileset.DrawTile(screen, ' ', x, y, color.RGBA{0, 0, 0, 255})
+			s.tileset.DrawTile(screen, ' ', x, y, s.palette.Background)
 		}
 	}
 
@@ -522,10 +953,13 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 	playerID := playerEntities[0].ID
 
 	// Draw panel title
-	s.tileset.DrawString(screen, "CHARACTER INFO", config.GameScreenWidth+2, 1, color.RGBA{255, 255, 255, 255})
+	s.tileset.DrawString(screen, "CHARACTER INFO", config.GameScreenWidth+2, 1, s.palette.PanelTitle)
+	if isPracticeRun(world) {
+		s.tileset.DrawString(screen, "[PRACTICE]", config.GameScreenWidth+17, 1, s.palette.SneakingText)
+	}
 	// Draw horizontal separator under title
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, 2, color.RGBA{180, 180, 180, 255})
+		s.tileset.DrawTile(screen, '-', x, 2, s.palette.PanelSeparator)
 	}
 
 	// Get player stats
@@ -534,63 +968,74 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 		stats = comp.(*components.StatsComponent)
 
 		// Draw player stats section
-		s.tileset.DrawString(screen, "STATS", config.GameScreenWidth+2, 4, color.RGBA{255, 230, 150, 255})
+		s.tileset.DrawString(screen, "STATS", config.GameScreenWidth+2, 4, s.palette.PanelHeading)
 
 		// Health with numerical and bar representation
 		healthText := "Health: " + strconv.Itoa(stats.Health) + "/" + strconv.Itoa(stats.MaxHealth)
-		s.tileset.DrawString(screen, healthText, config.GameScreenWidth+2, 6, color.RGBA{255, 200, 200, 255})
+		s.tileset.DrawString(screen, healthText, config.GameScreenWidth+2, 6, s.palette.HealthText)
 
 		// Draw health bar
-		healthBarWidth := statsPanelWidth - 4 // Leave some margin
-		healthPercentage := float64(stats.Health) / float64(stats.MaxHealth)
-		filledWidth := int(float64(healthBarWidth) * healthPercentage)
-
-		// Draw the filled portion of the bar
-		tileID := NewTileID(12, 13)
-		for x := 0; x < filledWidth; x++ {
-			s.tileset.DrawTileByID(screen, tileID, config.GameScreenWidth+2+x, 7, color.RGBA{200, 0, 0, 255}, 0)
-		}
-		// Draw the dark portion of the bar
-		for x := filledWidth; x < healthBarWidth; x++ {
-			s.tileset.DrawTileByID(screen, tileID, config.GameScreenWidth+2+x, 7, color.RGBA{100, 0, 0, 255}, 0)
+		healthFraction := 0.0
+		if stats.MaxHealth > 0 {
+			healthFraction = float64(stats.Health) / float64(stats.MaxHealth)
 		}
+		s.drawBar(screen, config.GameScreenWidth+2, 7, statsPanelWidth-4, healthFraction,
+			s.palette.HealthBarFull, s.palette.HealthBarEmpty)
 
 		// Other stats
 		s.tileset.DrawString(screen,
 			"Attack:  "+strconv.Itoa(stats.Attack),
-			config.GameScreenWidth+2, 9, color.RGBA{200, 200, 255, 255})
+			config.GameScreenWidth+2, 9, s.palette.PanelInfo)
 		s.tileset.DrawString(screen,
 			"Defense: "+strconv.Itoa(stats.Defense),
-			config.GameScreenWidth+2, 10, color.RGBA{200, 255, 200, 255})
+			config.GameScreenWidth+2, 10, s.palette.DefenseText)
 		s.tileset.DrawString(screen,
 			"Level:   "+strconv.Itoa(stats.Level),
-			config.GameScreenWidth+2, 11, color.RGBA{255, 255, 200, 255})
+			config.GameScreenWidth+2, 11, s.palette.LevelText)
 		s.tileset.DrawString(screen,
 			"EXP:     "+strconv.Itoa(stats.Exp),
-			config.GameScreenWidth+2, 12, color.RGBA{200, 200, 255, 255})
+			config.GameScreenWidth+2, 12, s.palette.PanelInfo)
+		s.tileset.DrawString(screen,
+			"Acc/Eva: "+strconv.Itoa(EffectiveAccuracy(stats))+"/"+strconv.Itoa(EffectiveEvasion(stats)),
+			config.GameScreenWidth+2, 13, s.palette.AccuracyText)
 	}
 
 	// Draw a separator
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, 14, color.RGBA{180, 180, 180, 255})
+		s.tileset.DrawTile(screen, '-', x, 14, s.palette.PanelSeparator)
 	}
 
 	// Draw status section
-	s.tileset.DrawString(screen, "STATUS", config.GameScreenWidth+2, 16, color.RGBA{255, 230, 150, 255})
+	s.tileset.DrawString(screen, "STATUS", config.GameScreenWidth+2, 16, s.palette.PanelHeading)
+
+	// Stealth, and how lit the player is, for judging whether monsters will notice them
+	stealthText := fmt.Sprintf("Stealth: %d, Light: %d%%", EffectiveStealth(world, playerID), int(AmbientLight(world, playerID)*100))
+	stealthColor := s.palette.StealthText
+	if IsSneaking(world, playerID) {
+		stealthText += " (sneaking)"
+		stealthColor = s.palette.SneakingText
+	}
+	s.tileset.DrawString(screen, stealthText, config.GameScreenWidth+2, 17, stealthColor)
 
 	// Get player's active effects
 	if effectComp, exists := world.GetComponent(playerID, components.Effect); exists {
 		if effects, ok := effectComp.(*components.EffectComponent); ok {
 			if len(effects.Effects) == 0 {
-				s.tileset.DrawString(screen, "No active effects", config.GameScreenWidth+2, 18, color.RGBA{200, 200, 200, 255})
+				s.tileset.DrawString(screen, "No active effects", config.GameScreenWidth+2, 18, s.palette.PanelText)
 			} else {
 				y := 18
 				for _, effect := range effects.Effects {
 					effectDesc := s.formatGameEffect(effect)
 					// Use red color for negative effects like bleeding
-					effectColor := color.RGBA{200, 200, 200, 255}
+					effectColor := s.palette.PanelText
 					if effect.Operation == components.EffectOpSubtract {
-						effectColor = color.RGBA{255, 100, 100, 255}
+						effectColor = s.palette.NegativeEffect
+					}
+					if effect.School == "poison" {
+						effectColor = s.palette.PoisonEffect
+					}
+					if s.palette.EffectCues {
+						effectDesc = effectCue(effect) + effectDesc
 					}
 					s.tileset.DrawString(screen, effectDesc, config.GameScreenWidth+2, y, effectColor)
 					y++
@@ -601,13 +1046,13 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 
 	// Draw a separator
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, 22, color.RGBA{180, 180, 180, 255})
+		s.tileset.DrawTile(screen, '-', x, 22, s.palette.PanelSeparator)
 	}
 
 	// Draw equipped items section
 	if world.HasComponent(playerID, components.Equipment) {
 		// Display equipment title
-		s.tileset.DrawString(screen, "EQUIPMENT", config.GameScreenWidth+2, 24, color.RGBA{255, 230, 150, 255})
+		s.tileset.DrawString(screen, "EQUIPMENT", config.GameScreenWidth+2, 24, s.palette.PanelHeading)
 
 		// Fixed display positions for each equipment slot
 		fixedPositions := map[components.EquipmentSlot]int{
@@ -628,6 +1073,16 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 			components.SlotAccessory: "Accessory",
 		}
 
+		// Tileset glyphs for each slot: helmet, chest, sword, shield, boots, ring
+		slotGlyphs := map[components.EquipmentSlot]TileID{
+			components.SlotHead:      NewTileID(15, 14),
+			components.SlotBody:      NewTileID(11, 5),
+			components.SlotMainHand:  NewTileID(15, 2),
+			components.SlotOffHand:   NewTileID(4, 15),
+			components.SlotFeet:      NewTileID(5, 15),
+			components.SlotAccessory: NewTileID(8, 15),
+		}
+
 		// Get the equipment component directly
 		if equipComp, exists := world.GetComponent(playerID, components.Equipment); exists {
 			equipment := equipComp.(*components.EquipmentComponent)
@@ -636,33 +1091,45 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 			for slot, name := range slotNames {
 				itemID := equipment.GetEquippedItem(slot)
 				itemName := "-empty-"
-				itemColor := color.RGBA{150, 150, 150, 255}
+				itemColor := s.palette.PanelDim
+				glyphColor := s.palette.EmptySlotGlyph
 
-				// Get item name if equipped
+				// Get item name if equipped, colored by its rarity
 				if itemID != 0 {
+					itemName = fmt.Sprintf("Item #%d", itemID)
 					if nameComp, exists := world.GetComponent(itemID, components.Name); exists {
 						itemName = nameComp.(*components.NameComponent).Name
-						itemColor = color.RGBA{220, 220, 255, 255}
-					} else {
-						itemName = fmt.Sprintf("Item #%d", itemID)
-						itemColor = color.RGBA{220, 220, 255, 255}
 					}
+					rarity := ""
+					if itemComp, exists := world.GetComponent(itemID, components.Item); exists {
+						rarity = itemComp.(*components.ItemComponent).Rarity
+					}
+					itemColor = s.rarityColor(rarity)
+					glyphColor = s.palette.FilledSlotGlyph
 				}
 
 				// Use fixed position for each slot instead of incremental yPos
+				s.tileset.DrawTileByID(screen, slotGlyphs[slot], config.GameScreenWidth+2, fixedPositions[slot], glyphColor, 0)
 				slotText := fmt.Sprintf("%s: %s", name, itemName)
-				s.tileset.DrawString(screen, slotText, config.GameScreenWidth+2, fixedPositions[slot], itemColor)
+				s.tileset.DrawString(screen, slotText, config.GameScreenWidth+4, fixedPositions[slot], itemColor)
 			}
 		}
 
 		// Draw a separator after equipment section
 		for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-			s.tileset.DrawTile(screen, '-', x, 33, color.RGBA{180, 180, 180, 255})
+			s.tileset.DrawTile(screen, '-', x, 33, s.palette.PanelSeparator)
 		}
 	}
 
 	// Draw location section below equipment
-	s.tileset.DrawString(screen, "LOCATION", config.GameScreenWidth+2, 35, color.RGBA{255, 230, 150, 255})
+	s.tileset.DrawString(screen, "LOCATION", config.GameScreenWidth+2, 35, s.palette.PanelHeading)
+
+	// Display the turn counter and time of day
+	if timeSystem := s.getTimeSystem(world); timeSystem != nil {
+		s.tileset.DrawString(screen,
+			fmt.Sprintf("Turn %d, %s", timeSystem.Turn(world), timeSystem.FormatTime(world)),
+			config.GameScreenWidth+2, 36, s.palette.PanelInfo)
+	}
 
 	// Get current map type and level
 	var mapType string = "Unknown"
@@ -678,9 +1145,9 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 
 	// Display map information
 	if mapType == "worldmap" {
-		s.tileset.DrawString(screen, "Surface", config.GameScreenWidth+2, 37, color.RGBA{200, 200, 255, 255})
+		s.tileset.DrawString(screen, s.surfacePlace(world, activeMap.ID, playerID), config.GameScreenWidth+2, 37, s.palette.PanelInfo)
 	} else {
-		s.tileset.DrawString(screen, fmt.Sprintf("Dungeon Level %d", mapLevel), config.GameScreenWidth+2, 37, color.RGBA{200, 200, 255, 255})
+		s.tileset.DrawString(screen, fmt.Sprintf("Dungeon Level %d", mapLevel), config.GameScreenWidth+2, 37, s.palette.PanelInfo)
 	}
 
 	// Get player position
@@ -693,19 +1160,361 @@ func (s *RenderSystem) drawStatsPanel(world *ecs.World, screen *ebiten.Image) {
 	if position != nil {
 		s.tileset.DrawString(screen,
 			"Pos: "+strconv.Itoa(position.X)+","+strconv.Itoa(position.Y),
-			config.GameScreenWidth+2, 38, color.RGBA{200, 200, 255, 255})
+			config.GameScreenWidth+2, 38, s.palette.PanelInfo)
+	}
+
+	// Display the weather on the surface
+	if mapType == "worldmap" {
+		weatherText := "Weather: Clear"
+		weatherColor := s.palette.PanelInfo
+		if weatherSystem := s.getWeatherSystem(world); weatherSystem != nil && weatherSystem.CurrentWeather() != nil {
+			weather := weatherSystem.CurrentWeather()
+			weatherText = fmt.Sprintf("Weather: %s (sight %d)", weather.Name, weather.SightRange)
+			weatherColor = weather.Tint
+		}
+		s.tileset.DrawString(screen, weatherText, config.GameScreenWidth+2, 39, weatherColor)
+	} else if position != nil && activeMap != nil {
+		s.drawPointsOfInterest(world, screen, activeMap.ID, position, 39)
 	}
 
 	// Draw a separator before controls section
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, 40, color.RGBA{180, 180, 180, 255})
+		s.tileset.DrawTile(screen, '-', x, 43, s.palette.PanelSeparator)
 	}
 
 	// Draw game controls reminder at the bottom of the stats panel
-	s.tileset.DrawString(screen, "CONTROLS", config.GameScreenWidth+2, 42, color.RGBA{255, 230, 150, 255})
-	s.tileset.DrawString(screen, "Arrow Keys: Move", config.GameScreenWidth+2, 43, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "I: Inventory", config.GameScreenWidth+2, 44, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "PgUp/PgDn: Scroll Log", config.GameScreenWidth+2, 45, color.RGBA{200, 200, 200, 255})
+	s.tileset.DrawString(screen, "CONTROLS", config.GameScreenWidth+2, 45, s.palette.PanelHeading)
+	s.tileset.DrawString(screen, "Arrow Keys: Move", config.GameScreenWidth+2, 46, s.palette.PanelText)
+	s.tileset.DrawString(screen, "I: Inventory", config.GameScreenWidth+2, 47, s.palette.PanelText)
+	s.tileset.DrawString(screen, "PgUp/PgDn: Scroll Log", config.GameScreenWidth+2, 48, s.palette.PanelText)
+	s.tileset.DrawString(screen, "X: Explore, T: Travel", config.GameScreenWidth+2, 49, s.palette.PanelText)
+	s.tileset.DrawString(screen, "G: Equip Ground, A: Auto-Equip", config.GameScreenWidth+2, 50, s.palette.PanelText)
+	s.tileset.DrawString(screen, "C: Sneak", config.GameScreenWidth+2, 51, s.palette.PanelText)
+}
+
+// surfacePlace names where the player stands on the world map: the station they are
+// at, else the region they are in, else just the surface
+func (s *RenderSystem) surfacePlace(world *ecs.World, mapID, playerID ecs.EntityID) string {
+	namesComp, hasNames := world.GetComponent(mapID, components.RegionNames)
+	posComp, hasPos := world.GetComponent(playerID, components.Position)
+	if !hasNames || !hasPos {
+		return "Surface"
+	}
+	names := namesComp.(*components.RegionNamesComponent)
+	pos := posComp.(*components.PositionComponent)
+	if station := names.StationName(pos.X, pos.Y); station != "" {
+		return "Surface, " + station
+	}
+	if region := names.RegionName(pos.X, pos.Y); region != "" {
+		return "Surface, " + region
+	}
+	return "Surface"
+}
+
+// drawPointsOfInterest draws where the nearest discovered stairs of each kind lie from
+// the player, and how many hostiles are in sight, from the given panel row down
+func (s *RenderSystem) drawPointsOfInterest(world *ecs.World, screen *ebiten.Image, mapID ecs.EntityID, position *components.PositionComponent, row int) {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	stairs := []struct {
+		label    string
+		tileType int
+	}{
+		{"Down stairs", components.TileStairsDown},
+		{"Up stairs", components.TileStairsUp},
+	}
+	for i, kind := range stairs {
+		text := kind.label + ": unknown"
+		textColor := s.palette.PanelDim
+		if x, y, found := nearestExploredTile(gameMap, kind.tileType, position.X, position.Y); found {
+			dx, dy := x-position.X, y-position.Y
+			if dx == 0 && dy == 0 {
+				text = kind.label + ": here"
+			} else {
+				text = fmt.Sprintf("%s: %d %s", kind.label, max(abs(dx), abs(dy)), compassDirection(dx, dy))
+			}
+			textColor = s.palette.PanelInfo
+		}
+		s.tileset.DrawString(screen, text, config.GameScreenWidth+2, row+i, textColor)
+	}
+
+	hostiles := 0
+	world.Query(components.AI, components.Position).Each(func(entity *ecs.Entity) {
+		if !entity.HasTag("enemy") || getEntityMapID(world, entity.ID) != mapID {
+			return
+		}
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		if pos.X >= 0 && pos.X < gameMap.Width && pos.Y >= 0 && pos.Y < gameMap.Height && gameMap.Visible[pos.Y][pos.X] {
+			hostiles++
+		}
+	})
+	hostileColor := s.palette.PanelInfo
+	if hostiles > 0 {
+		hostileColor = s.palette.NegativeEffect
+	}
+	s.tileset.DrawString(screen, fmt.Sprintf("Hostiles in sight: %d", hostiles), config.GameScreenWidth+2, row+len(stairs), hostileColor)
+}
+
+// nearestExploredTile returns the closest tile of a type the player has already seen
+func nearestExploredTile(gameMap *components.MapComponent, tileType, fromX, fromY int) (int, int, bool) {
+	bestX, bestY, bestDist := 0, 0, -1
+	for y := 0; y < gameMap.Height; y++ {
+		for x := 0; x < gameMap.Width; x++ {
+			if gameMap.Tiles[y][x] != tileType || !gameMap.Explored[y][x] {
+				continue
+			}
+			if dist := max(abs(x-fromX), abs(y-fromY)); bestDist < 0 || dist < bestDist {
+				bestX, bestY, bestDist = x, y, dist
+			}
+		}
+	}
+	return bestX, bestY, bestDist >= 0
+}
+
+// compassDirection names the compass point closest to the direction (dx,dy), with
+// north up the screen
+func compassDirection(dx, dy int) string {
+	points := [8]string{"E", "SE", "S", "SW", "W", "NW", "N", "NE"}
+	octant := int(math.Round(math.Atan2(float64(dy), float64(dx))/(math.Pi/4))+8) % 8
+	return points[octant]
+}
+
+// getExaminedMonster returns the monster selected in targeting mode, or 0
+func (s *RenderSystem) getExaminedMonster(world *ecs.World) ecs.EntityID {
+	for _, system := range world.GetSystems() {
+		if targeting, ok := system.(*TargetingSystem); ok {
+			return targeting.GetTarget(world)
+		}
+	}
+	return 0
+}
+
+// drawMonsterDetailsPanel draws the side panel describing the monster being examined.
+// Exact stats and abilities are only shown for monster types the player has killed.
+func (s *RenderSystem) drawMonsterDetailsPanel(world *ecs.World, screen *ebiten.Image, monsterID ecs.EntityID) {
+	statsPanelWidth := config.ScreenWidth - config.GameScreenWidth
+	panelX := config.GameScreenWidth + 2
+
+	// Draw panel border and background
+	for y := 0; y < config.GameScreenHeight; y++ {
+		s.tileset.DrawTile(screen, '|', config.GameScreenWidth, y, s.palette.PanelText)
+		for x := config.GameScreenWidth + 1; x < config.ScreenWidth; x++ {
+			s.tileset.DrawTile(screen, ' ', x, y, s.palette.Background)
+		}
+	}
+
+	// Bosses get their own header
+	entity := world.GetEntity(monsterID)
+	if entity != nil && entity.HasTag("boss") {
+		s.tileset.DrawString(screen, "!! BOSS !!", panelX, 1, s.palette.BossTitle)
+	} else {
+		s.tileset.DrawString(screen, "MONSTER DETAILS", panelX, 1, s.palette.PanelTitle)
+	}
+	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
+		s.tileset.DrawTile(screen, '-', x, 2, s.palette.PanelSeparator)
+	}
+
+	scanned := false
+	if aiComp, exists := world.GetComponent(monsterID, components.AI); exists {
+		templateID := aiComp.(*components.AIComponent).TemplateID
+		for _, system := range world.GetSystems() {
+			if bestiary, ok := system.(*BestiarySystem); ok {
+				scanned = bestiary.IsScanned(templateID)
+				break
+			}
+		}
+	}
+
+	y := 4
+	s.tileset.DrawString(screen, getEntityName(world, monsterID), panelX, y, s.palette.PanelHeading)
+	y += 2
+
+	if statsComp, exists := world.GetComponent(monsterID, components.Stats); exists {
+		stats := statsComp.(*components.StatsComponent)
+
+		healthText := "Health: ??"
+		if scanned {
+			healthText = "Health: " + strconv.Itoa(stats.Health) + "/" + strconv.Itoa(stats.MaxHealth)
+		}
+		s.tileset.DrawString(screen, healthText, panelX, y, s.palette.HealthText)
+		y++
+
+		healthFraction := 0.0
+		if stats.MaxHealth > 0 {
+			healthFraction = float64(stats.Health) / float64(stats.MaxHealth)
+		}
+		s.drawBar(screen, panelX, y, statsPanelWidth-4, healthFraction,
+			s.palette.HealthBarFull, s.palette.HealthBarEmpty)
+		y += 2
+
+		if scanned {
+			s.tileset.DrawString(screen, "Level:   "+strconv.Itoa(stats.Level), panelX, y, s.palette.LevelText)
+			s.tileset.DrawString(screen, "Attack:  "+strconv.Itoa(stats.Attack), panelX, y+1, s.palette.PanelInfo)
+			s.tileset.DrawString(screen, "Defense: "+strconv.Itoa(stats.Defense), panelX, y+2, s.palette.DefenseText)
+			s.tileset.DrawString(screen,
+				"Acc/Eva: "+strconv.Itoa(EffectiveAccuracy(stats))+"/"+strconv.Itoa(EffectiveEvasion(stats)),
+				panelX, y+3, s.palette.AccuracyText)
+			y += 5
+		} else {
+			s.tileset.DrawString(screen, "Kill one to learn", panelX, y, s.palette.PanelDim)
+			s.tileset.DrawString(screen, "its exact stats.", panelX, y+1, s.palette.PanelDim)
+			y += 3
+		}
+	}
+
+	// Immunities, resistances and weaknesses from the resistance component
+	var defenses []string
+	if resComp, exists := world.GetComponent(monsterID, components.Resistance); exists {
+		for school, multiplier := range resComp.(*components.ResistanceComponent).Multipliers {
+			switch {
+			case multiplier <= 0:
+				defenses = append(defenses, "Immune: "+school)
+			case multiplier < 1:
+				defenses = append(defenses, "Resists: "+school)
+			case multiplier > 1:
+				defenses = append(defenses, "Weak: "+school)
+			}
+		}
+	}
+	sort.Strings(defenses)
+	s.tileset.DrawString(screen, "Defenses:", panelX, y, s.palette.PanelHeading)
+	y++
+	if len(defenses) == 0 {
+		s.tileset.DrawString(screen, "Nothing known", panelX, y, s.palette.PanelText)
+		y++
+	}
+	for _, defense := range defenses {
+		s.tileset.DrawString(screen, "- "+defense, panelX, y, s.palette.PanelText)
+		y++
+	}
+	y++
+
+	// Carried items whose school this monster resists, shrugs off or fears
+	if lines := s.itemOutcomes(world, monsterID); len(lines) > 0 {
+		s.tileset.DrawString(screen, "Your items:", panelX, y, s.palette.PanelHeading)
+		y++
+		for _, line := range lines {
+			s.tileset.DrawString(screen, "- "+line.Text, panelX, y, line.Color)
+			y++
+		}
+		y++
+	}
+
+	s.tileset.DrawString(screen, "Abilities:", panelX, y, s.palette.PanelHeading)
+	y++
+	abilityComp, hasAbilities := world.GetComponent(monsterID, components.MonsterAbility)
+	switch {
+	case !scanned && hasAbilities:
+		s.tileset.DrawString(screen, "Unknown", panelX, y, s.palette.PanelDim)
+	case !hasAbilities || len(abilityComp.(*components.MonsterAbilityComponent).Abilities) == 0:
+		s.tileset.DrawString(screen, "None", panelX, y, s.palette.PanelText)
+	default:
+		for _, ability := range abilityComp.(*components.MonsterAbilityComponent).Abilities {
+			status := "ready"
+			if ability.CurrentCD > 0 {
+				status = fmt.Sprintf("%d turns", ability.CurrentCD)
+			}
+			s.tileset.DrawString(screen, fmt.Sprintf("- %s (%s)", ability.Name, status), panelX, y, s.palette.PanelText)
+			y++
+		}
+	}
+
+	s.tileset.DrawString(screen, "Tab: Next target", panelX, config.GameScreenHeight-2, s.palette.PanelText)
+	s.tileset.DrawString(screen, "Esc: Stop examining", panelX, config.GameScreenHeight-1, s.palette.PanelText)
+}
+
+// effectPreviewer returns the monster selected in targeting mode and the effects system
+// that previews effects against it, or 0 when there is nothing to preview against
+func (s *RenderSystem) effectPreviewer(world *ecs.World) (ecs.EntityID, *EffectsSystem) {
+	targetID := s.getExaminedMonster(world)
+	if targetID == 0 {
+		return 0, nil
+	}
+	for _, system := range world.GetSystems() {
+		if effectsSys, ok := system.(*EffectsSystem); ok {
+			return targetID, effectsSys
+		}
+	}
+	return 0, nil
+}
+
+// itemOutcome is a line of the monster panel naming a carried item and how the monster
+// would take it
+type itemOutcome struct {
+	Text  string
+	Color color.Color
+}
+
+// itemOutcomes lists the player's carried items with effects the monster is immune to,
+// resists or is weak to. Immunities are grayed out, since the item would be wasted.
+func (s *RenderSystem) itemOutcomes(world *ecs.World, monsterID ecs.EntityID) []itemOutcome {
+	targetID, effectsSys := s.effectPreviewer(world)
+	if targetID != monsterID {
+		return nil
+	}
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return nil
+	}
+	invComp, exists := world.GetComponent(playerEntities[0].ID, components.Inventory)
+	if !exists {
+		return nil
+	}
+
+	var lines []itemOutcome
+	for _, itemID := range invComp.(*components.InventoryComponent).Items {
+		itemComp, exists := world.GetComponent(itemID, components.Item)
+		if !exists {
+			continue
+		}
+		effects, _ := itemComp.(*components.ItemComponent).Data.([]components.GameEffect)
+		for _, effect := range effects {
+			if effect.School == "" {
+				continue
+			}
+			preview := effectsSys.PreviewEffect(world, monsterID, effect)
+			outcome := preview.Outcome()
+			if outcome == "" {
+				continue
+			}
+			lineColor := s.palette.PanelText
+			if preview.Immune() {
+				lineColor = s.palette.PanelDim
+			}
+			lines = append(lines, itemOutcome{
+				Text:  fmt.Sprintf("%s (%s: %s)", getEntityName(world, itemID), effect.School, outcome),
+				Color: lineColor,
+			})
+			break
+		}
+	}
+	return lines
+}
+
+// drawBar draws a horizontal bar of tiles starting at tile (x, y), filled from the left
+// by fraction in fgColor with the rest in bgColor. Fractions are clamped to [0,1].
+func (s *RenderSystem) drawBar(screen *ebiten.Image, x, y, width int, fraction float64, fgColor, bgColor color.Color) {
+	if fraction < 0 || math.IsNaN(fraction) {
+		fraction = 0
+	} else if fraction > 1 {
+		fraction = 1
+	}
+	filledWidth := int(float64(width) * fraction)
+
+	// Lower half block (CP437 220)
+	tileID := NewTileID(12, 13)
+	for i := 0; i < width; i++ {
+		barColor := bgColor
+		if i < filledWidth {
+			barColor = fgColor
+		}
+		s.tileset.DrawTileByID(screen, tileID, x+i, y, barColor, 0)
+	}
 }
 
 // drawInventoryPanel draws the player inventory panel
@@ -716,11 +1525,11 @@ func (s *RenderSystem) drawInventoryPanel(world *ecs.World, screen *ebiten.Image
 	// Draw inventory panel border and background
 	for y := 0; y < config.GameScreenHeight; y++ {
 		// Draw vertical border
-		s.tileset.DrawTile(screen, '|', config.GameScreenWidth, y, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawTile(screen, '|', config.GameScreenWidth, y, s.palette.PanelText)
 
 		// Draw background for better readability
 		for x := config.GameScreenWidth + 1; x < config.ScreenWidth; x++ {
-			s.tileset.DrawTile(screen, ' ', x, y, color.RGBA{0, 0, 0, 255})
+			s.tileset.DrawTile(screen, ' ', x, y, s.palette.Background)
 		}
 	}
 
@@ -745,37 +1554,47 @@ func (s *RenderSystem) drawInventoryPanel(world *ecs.World, screen *ebiten.Image
 			s.drawInventoryListView(world, screen, inventory)
 		}
 	} else {
-		s.tileset.DrawString(screen, "No inventory", config.GameScreenWidth+2, 6, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawString(screen, "No inventory", config.GameScreenWidth+2, 6, s.palette.PanelText)
 	}
 }
 
 // drawInventoryListView draws the list of items in the inventory
 func (s *RenderSystem) drawInventoryListView(world *ecs.World, screen *ebiten.Image, inventory *components.InventoryComponent) {
 	// Draw panel title
-	s.tileset.DrawString(screen, "INVENTORY", config.GameScreenWidth+2, 1, color.RGBA{255, 255, 255, 255})
+	s.tileset.DrawString(screen, "INVENTORY", config.GameScreenWidth+2, 1, s.palette.PanelTitle)
 	// Draw horizontal separator under title
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, 2, color.RGBA{180, 180, 180, 255})
+		s.tileset.DrawTile(screen, '-', x, 2, s.palette.PanelSeparator)
 	}
 
 	// Display inventory info
 	s.tileset.DrawString(screen,
 		fmt.Sprintf("Items: %d/%d", inventory.Size(), inventory.MaxCapacity),
-		config.GameScreenWidth+2, 4, color.RGBA{255, 230, 150, 255})
+		config.GameScreenWidth+2, 4, s.palette.PanelHeading)
 
-	// If no item is selected yet and we have items, select the first one
+	// If no item is selected yet and we have items, select the first one. The
+	// remembered selection may be past the end if items were used up since.
 	if s.selectedItemIndex == -1 && inventory.Size() > 0 {
 		s.selectedItemIndex = 0
+	} else if s.selectedItemIndex >= inventory.Size() {
+		s.selectedItemIndex = inventory.Size() - 1
+	}
+
+	var quickSlots *components.QuickSlotsComponent
+	if playerEntities := world.GetEntitiesWithTag("player"); len(playerEntities) > 0 {
+		if comp, exists := world.GetComponent(playerEntities[0].ID, components.QuickSlots); exists {
+			quickSlots = comp.(*components.QuickSlotsComponent)
+		}
 	}
 
 	// Display items list
 	if inventory.Size() == 0 {
-		s.tileset.DrawString(screen, "No items", config.GameScreenWidth+2, 6, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawString(screen, "No items", config.GameScreenWidth+2, 6, s.palette.PanelText)
 	} else {
 		// Display the items
 		for i, itemID := range inventory.Items {
 			if i >= 15 { // Increased limit since we're not showing descriptions
-				s.tileset.DrawString(screen, "...", config.GameScreenWidth+2, 6+i, color.RGBA{200, 200, 200, 255})
+				s.tileset.DrawString(screen, "...", config.GameScreenWidth+2, 6+i, s.palette.PanelText)
 				break
 			}
 
@@ -788,16 +1607,27 @@ func (s *RenderSystem) drawInventoryListView(world *ecs.World, screen *ebiten.Im
 			// Display the item with a letter for selection
 			itemLetter := string(rune('a' + i))
 
-			// Choose color based on selection
-			itemColor := color.RGBA{200, 200, 255, 255}
+			// Choose color based on selection, dimming junk
+			itemColor := s.palette.PanelInfo
+			if itemComp, exists := world.GetComponent(itemID, components.Item); exists && itemComp.(*components.ItemComponent).Junk {
+				itemColor = s.palette.PanelDim
+				itemName += " (junk)"
+			}
 			if i == s.selectedItemIndex {
 				// Highlight the selected item
-				itemColor = color.RGBA{255, 255, 100, 255}
+				itemColor = s.palette.SelectionHighlight
 				// Draw a selection indicator
 				arrowTileID := NewTileID(0, 1)
 				s.tileset.DrawTileByID(screen, arrowTileID, config.GameScreenWidth+1, 6+i, itemColor, 0)
 			}
 
+			// Show the quick slot the item is bound to
+			if quickSlots != nil {
+				if slot := quickSlots.SlotOf(itemID); slot >= 0 {
+					itemName = fmt.Sprintf("%s [%d]", itemName, slot+1)
+				}
+			}
+
 			s.tileset.DrawString(screen,
 				fmt.Sprintf("%s) %s", itemLetter, itemName),
 				config.GameScreenWidth+2, 6+i, itemColor)
@@ -806,13 +1636,14 @@ func (s *RenderSystem) drawInventoryListView(world *ecs.World, screen *ebiten.Im
 
 	// Draw controls at bottom of panel
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, config.GameScreenHeight-6, color.RGBA{180, 180, 180, 255})
-	}
-	s.tileset.DrawString(screen, "CONTROLS", config.GameScreenWidth+2, config.GameScreenHeight-5, color.RGBA{255, 230, 150, 255})
-	s.tileset.DrawString(screen, "I/ESC: Close inventory", config.GameScreenWidth+2, config.GameScreenHeight-4, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "Up/Down: Navigate items", config.GameScreenWidth+2, config.GameScreenHeight-3, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "Enter: View details", config.GameScreenWidth+2, config.GameScreenHeight-2, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "E: Equip item, U: Use item", config.GameScreenWidth+2, config.GameScreenHeight-1, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawTile(screen, '-', x, config.GameScreenHeight-7, s.palette.PanelSeparator)
+	}
+	s.tileset.DrawString(screen, "CONTROLS", config.GameScreenWidth+2, config.GameScreenHeight-6, s.palette.PanelHeading)
+	s.tileset.DrawString(screen, "J: Junk, Shift+J: Drop all junk", config.GameScreenWidth+2, config.GameScreenHeight-5, s.palette.PanelText)
+	s.tileset.DrawString(screen, "I/ESC: Close inventory", config.GameScreenWidth+2, config.GameScreenHeight-4, s.palette.PanelText)
+	s.tileset.DrawString(screen, "Up/Down: Navigate, 1-9: Quick slot", config.GameScreenWidth+2, config.GameScreenHeight-3, s.palette.PanelText)
+	s.tileset.DrawString(screen, "Enter: View details", config.GameScreenWidth+2, config.GameScreenHeight-2, s.palette.PanelText)
+	s.tileset.DrawString(screen, "E: Equip, U: Use, S: Socket, O: Offer", config.GameScreenWidth+2, config.GameScreenHeight-1, s.palette.PanelText)
 }
 
 // drawItemDetailsView draws the detailed view of a selected item
@@ -833,17 +1664,17 @@ func (s *RenderSystem) drawItemDetailsView(world *ecs.World, screen *ebiten.Imag
 	}
 
 	// Draw panel title
-	s.tileset.DrawString(screen, "ITEM DETAILS", config.GameScreenWidth+2, 1, color.RGBA{255, 255, 255, 255})
+	s.tileset.DrawString(screen, "ITEM DETAILS", config.GameScreenWidth+2, 1, s.palette.PanelTitle)
 	// Draw horizontal separator under title
 	for x := config.GameScreenWidth + 1; x < config.ScreenWidth-1; x++ {
-		s.tileset.DrawTile(screen, '-', x, 2, color.RGBA{180, 180, 180, 255})
+		s.tileset.DrawTile(screen, '-', x, 2, s.palette.PanelSeparator)
 	}
 
 	// Draw item name with letter
 	itemLetter := string(rune('a' + s.selectedItemIndex))
 	s.tileset.DrawString(screen,
 		fmt.Sprintf("%s) %s", itemLetter, itemName),
-		config.GameScreenWidth+2, 4, color.RGBA{255, 230, 150, 255})
+		config.GameScreenWidth+2, 4, s.palette.PanelHeading)
 
 	// Get item component
 	var itemComp *components.ItemComponent
@@ -869,7 +1700,7 @@ func (s *RenderSystem) drawItemDetailsView(world *ecs.World, screen *ebiten.Imag
 
 				s.tileset.DrawString(screen,
 					description[:lineLen],
-					config.GameScreenWidth+2, y, color.RGBA{200, 200, 200, 255})
+					config.GameScreenWidth+2, y, s.palette.PanelText)
 
 				description = description[lineLen:]
 				y++
@@ -879,14 +1710,18 @@ func (s *RenderSystem) drawItemDetailsView(world *ecs.World, screen *ebiten.Imag
 		}
 
 		// Draw item stats
-		s.tileset.DrawString(screen, "Item Info:", config.GameScreenWidth+2, y, color.RGBA{255, 230, 150, 255})
+		s.tileset.DrawString(screen, "Item Info:", config.GameScreenWidth+2, y, s.palette.PanelHeading)
 		y += 1
 
 		// Show item type with a user-friendly description
 		typeDesc := ""
 		switch itemComp.ItemType {
 		case "weapon":
-			typeDesc = "Weapon (equips to main hand)"
+			if itemComp.TwoHanded {
+				typeDesc = "Two-handed weapon (takes both hands)"
+			} else {
+				typeDesc = "Weapon (main hand, or off hand to dual wield)"
+			}
 		case "armor":
 			typeDesc = "Armor (equips to body)"
 		case "helmet":
@@ -898,56 +1733,247 @@ func (s *RenderSystem) drawItemDetailsView(world *ecs.World, screen *ebiten.Imag
 		case "accessory":
 			typeDesc = "Accessory (equips to accessory slot)"
 		case "potion":
-			typeDesc = "Potion (consumable item)"
+			typeDesc = "Potion (U: drink)"
 		case "scroll":
-			typeDesc = "Scroll (consumable item)"
+			typeDesc = "Scroll (U: read)"
 		default:
 			typeDesc = itemComp.ItemType
 		}
 
 		s.tileset.DrawString(screen,
 			fmt.Sprintf("Type: %s", typeDesc),
-			config.GameScreenWidth+2, y, color.RGBA{200, 200, 200, 255})
+			config.GameScreenWidth+2, y, s.palette.PanelText)
 		y += 1
 
 		s.tileset.DrawString(screen,
 			fmt.Sprintf("Value: %d", itemComp.Value),
-			config.GameScreenWidth+2, y, color.RGBA{200, 200, 200, 255})
+			config.GameScreenWidth+2, y, s.palette.PanelText)
 		y += 1
 
 		s.tileset.DrawString(screen,
 			fmt.Sprintf("Weight: %d", itemComp.Weight),
-			config.GameScreenWidth+2, y, color.RGBA{200, 200, 200, 255})
+			config.GameScreenWidth+2, y, s.palette.PanelText)
 		y += 2
 
-		// Display item effects if any
+		// Display item effects if any, and how the examined monster would take them
 		if itemComp.Data != nil {
-			s.tileset.DrawString(screen, "Effects:", config.GameScreenWidth+2, y, color.RGBA{255, 230, 150, 255})
+			targetID, effectsSys := s.effectPreviewer(world)
+			heading := "Effects:"
+			if targetID != 0 {
+				heading = fmt.Sprintf("Effects (vs %s):", getEntityName(world, targetID))
+			}
+			s.tileset.DrawString(screen, heading, config.GameScreenWidth+2, y, s.palette.PanelHeading)
 			y += 1
 
 			if effects, ok := itemComp.Data.([]components.GameEffect); ok {
 				if len(effects) == 0 {
-					s.tileset.DrawString(screen, "None", config.GameScreenWidth+2, y, color.RGBA{200, 200, 200, 255})
+					s.tileset.DrawString(screen, "None", config.GameScreenWidth+2, y, s.palette.PanelText)
 					y += 1
 				} else {
 					for _, effect := range effects {
 						effectDesc := s.formatGameEffect(effect)
-						s.tileset.DrawString(screen, effectDesc, config.GameScreenWidth+2, y, color.RGBA{200, 200, 200, 255})
+						effectColor := s.palette.PanelText
+						if targetID != 0 && effect.School != "" {
+							preview := effectsSys.PreviewEffect(world, targetID, effect)
+							if outcome := preview.Outcome(); outcome != "" {
+								effectDesc += fmt.Sprintf(" (%s: %s)", effect.School, outcome)
+							}
+							if preview.Immune() {
+								effectColor = s.palette.PanelDim
+							}
+						}
+						s.tileset.DrawString(screen, effectDesc, config.GameScreenWidth+2, y, effectColor)
 						y += 1
 					}
 				}
 			}
 		}
+
+		// Display sockets and the gems set into them
+		if itemComp.Sockets > 0 {
+			y += 1
+			s.tileset.DrawString(screen,
+				fmt.Sprintf("Sockets (%d/%d):", len(itemComp.SocketedGems), itemComp.Sockets),
+				config.GameScreenWidth+2, y, s.palette.PanelHeading)
+			y += 1
+			for i := 0; i < itemComp.Sockets; i++ {
+				socketText := "- empty"
+				socketColor := s.palette.PanelDim
+				if i < len(itemComp.SocketedGems) {
+					socketText = "- " + getEntityName(world, itemComp.SocketedGems[i])
+					socketColor = s.palette.SocketedGem
+				}
+				s.tileset.DrawString(screen, socketText, config.GameScreenWidth+2, y, socketColor)
+				y += 1
+			}
+		}
 	} else {
-		s.tileset.DrawString(screen, "No item data available", config.GameScreenWidth+2, 6, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawString(screen, "No item data available", config.GameScreenWidth+2, 6, s.palette.PanelText)
 	}
 
 	// Draw controls at bottom of panel
-	s.tileset.DrawString(screen, "CONTROLS", config.GameScreenWidth+2, config.GameScreenHeight-5, color.RGBA{255, 230, 150, 255})
-	s.tileset.DrawString(screen, "ESC: Return to inventory", config.GameScreenWidth+2, config.GameScreenHeight-4, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "E: Equip item", config.GameScreenWidth+2, config.GameScreenHeight-3, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "U: Use item", config.GameScreenWidth+2, config.GameScreenHeight-2, color.RGBA{200, 200, 200, 255})
-	s.tileset.DrawString(screen, "Up/Down: Previous/Next item", config.GameScreenWidth+2, config.GameScreenHeight-1, color.RGBA{200, 200, 200, 255})
+	s.tileset.DrawString(screen, "CONTROLS", config.GameScreenWidth+2, config.GameScreenHeight-5, s.palette.PanelHeading)
+	s.tileset.DrawString(screen, "ESC: Return to inventory", config.GameScreenWidth+2, config.GameScreenHeight-4, s.palette.PanelText)
+	s.tileset.DrawString(screen, "E: Equip item", config.GameScreenWidth+2, config.GameScreenHeight-3, s.palette.PanelText)
+	s.tileset.DrawString(screen, "U: Use, S: (Un)socket, O: Offer", config.GameScreenWidth+2, config.GameScreenHeight-2, s.palette.PanelText)
+	s.tileset.DrawString(screen, "Up/Down: Previous/Next item", config.GameScreenWidth+2, config.GameScreenHeight-1, s.palette.PanelText)
+}
+
+// monsterHPLabel is a monster's HP number or alert mark waiting to be drawn next to it
+type monsterHPLabel struct {
+	X, Y  int // Screen tile of the monster
+	Text  string
+	Color color.Color
+}
+
+// monsterHealth returns how healthy a monster is and its HP as text, if monster
+// health is shown on the map and the entity is a monster with stats
+func (s *RenderSystem) monsterHealth(world *ecs.World, entity *ecs.Entity) (float64, string, bool) {
+	if s.monsterHP == MonsterHPOff || s.monsterHP == "" || !entity.HasTag("enemy") {
+		return 1, "", false
+	}
+	statsComp, exists := world.GetComponent(entity.ID, components.Stats)
+	if !exists {
+		return 1, "", false
+	}
+	stats := statsComp.(*components.StatsComponent)
+	if stats.MaxHealth <= 0 {
+		return 1, "", false
+	}
+	fraction := math.Max(0, math.Min(1, float64(stats.Health)/float64(stats.MaxHealth)))
+	return fraction, strconv.Itoa(stats.Health), true
+}
+
+// drawMonsterHPLabels draws each HP number at half size in the corner of a
+// neighbouring tile, trying the right, left, above and below in turn so it doesn't
+// cover another entity. Labels with no free neighbour go on the right anyway.
+func (s *RenderSystem) drawMonsterHPLabels(screen *ebiten.Image, labels []monsterHPLabel, occupied map[Point]bool) {
+	tileSize := s.tileset.TileSize
+	for _, label := range labels {
+		spot := Point{label.X + 1, label.Y}
+		for _, candidate := range []Point{{label.X + 1, label.Y}, {label.X - 1, label.Y}, {label.X, label.Y - 1}, {label.X, label.Y + 1}} {
+			if candidate.X < 0 || candidate.X >= config.GameScreenWidth || candidate.Y < 0 || candidate.Y >= config.GameScreenHeight {
+				continue
+			}
+			if !occupied[candidate] {
+				spot = candidate
+				break
+			}
+		}
+		if spot.X >= config.GameScreenWidth {
+			continue
+		}
+
+		// Hug the monster's side of the neighbouring tile
+		px, py := spot.X*tileSize, spot.Y*tileSize
+		if spot.X < label.X {
+			px += tileSize - len(label.Text)*tileSize/2
+		}
+		if spot.Y < label.Y {
+			py += tileSize / 2
+		}
+		s.tileset.DrawSmallString(screen, label.Text, px, py, 0.5, label.Color)
+		occupied[spot] = true
+	}
+}
+
+// lockMark is drawn over locked containers: CP437 12, which reads as a padlock at half size
+const lockMark = "\x0c"
+
+// containerLook returns the color a container is drawn in for its state, and the mark
+// to show over it. Locked containers are tinted and marked with a padlock, closed ones
+// not yet looted are picked out, and ones opened and emptied go gray. Opened ones with
+// items left keep the dimmed color the container system gave them, and entities that
+// aren't containers come back unchanged.
+func (s *RenderSystem) containerLook(world *ecs.World, entityID ecs.EntityID, base color.Color) (color.Color, string) {
+	containerComp, exists := world.GetComponent(entityID, components.Container)
+	if !exists {
+		return base, ""
+	}
+	container := containerComp.(*components.ContainerComponent)
+	switch {
+	case container.Locked:
+		return tintColor(base, s.palette.ContainerLocked, 0.5), lockMark
+	case !container.Looted:
+		return tintColor(base, s.palette.ContainerUnopened, 0.35), ""
+	case len(container.Items) == 0:
+		return s.palette.ContainerEmpty, ""
+	}
+	return base, ""
+}
+
+// monsterAlert returns the mark shown over a monster for what its AI is doing: "?"
+// while it investigates, and "!" for a few turns after it spots its target
+func (s *RenderSystem) monsterAlert(world *ecs.World, entity *ecs.Entity) (string, color.Color, bool) {
+	if !entity.HasTag("enemy") {
+		return "", nil, false
+	}
+	aiComp, exists := world.GetComponent(entity.ID, components.AI)
+	if !exists {
+		return "", nil, false
+	}
+	ai := aiComp.(*components.AIComponent)
+	switch {
+	case ai.AlertTurns > 0 && isHunting(ai.State):
+		return "!", s.palette.AlertSpotted, true
+	case ai.State == components.AIStateInvestigate:
+		return "?", s.palette.AlertInvestigate, true
+	}
+	return "", nil, false
+}
+
+// drawMonsterAlerts draws each alert mark at half size just above its monster, in the
+// bottom of the tile above or the top of its own tile at the edge of the screen
+func (s *RenderSystem) drawMonsterAlerts(screen *ebiten.Image, alerts []monsterHPLabel) {
+	tileSize := s.tileset.TileSize
+	for _, alert := range alerts {
+		px := alert.X*tileSize + tileSize/4
+		py := alert.Y*tileSize - tileSize/2
+		if py < 0 {
+			py = 0
+		}
+		s.tileset.DrawSmallString(screen, alert.Text, px, py, 0.5, alert.Color)
+	}
+}
+
+// healthColor shades from the palette's high health color at full health through its
+// mid color to its low one near death
+func (s *RenderSystem) healthColor(fraction float64) color.RGBA {
+	if fraction >= 0.5 {
+		return lerpColor(s.palette.HealthMid, s.palette.HealthHigh, (fraction-0.5)*2)
+	}
+	return lerpColor(s.palette.HealthLow, s.palette.HealthMid, fraction*2)
+}
+
+// effectCue marks an effect as taking something away or giving something, for
+// palettes that don't leave that to color alone
+func effectCue(effect components.GameEffect) string {
+	if effect.Operation == components.EffectOpSubtract {
+		return "- "
+	}
+	return "+ "
+}
+
+// lerpColor blends from one color to another by t (0-1)
+func lerpColor(from, to color.RGBA, t float64) color.RGBA {
+	blend := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	return color.RGBA{blend(from.R, to.R), blend(from.G, to.G), blend(from.B, to.B), 255}
+}
+
+// rarityColor returns the color item names of a rarity tier are drawn in
+func (s *RenderSystem) rarityColor(rarity string) color.RGBA {
+	switch rarity {
+	case "uncommon":
+		return s.palette.RarityUncommon
+	case "rare":
+		return s.palette.RarityRare
+	case "epic":
+		return s.palette.RarityEpic
+	default:
+		return s.palette.RarityCommon
+	}
 }
 
 // formatGameEffect formats a game effect in a user-friendly way
@@ -962,15 +1988,79 @@ func (s *RenderSystem) formatGameEffect(effect components.GameEffect) string {
 		effectDesc = fmt.Sprintf("%s by %.1f every %d turns", effect.Operation, effect.Value, effect.Duration)
 	case components.EffectTypeConditional:
 		effectDesc = fmt.Sprintf("When condition met: %s by %.1f", effect.Operation, effect.Value)
+	case components.EffectTypeEquipment:
+		effectDesc = s.formatEquipmentEffect(effect)
 	}
 	return effectDesc
 }
 
+// equipmentStatNames are the names gear effects show for the properties they change
+var equipmentStatNames = map[string]string{
+	"Health":     "Health",
+	"MaxHealth":  "Max Health",
+	"Attack":     "Attack",
+	"Defense":    "Defense",
+	"Evasion":    "Evasion",
+	"Accuracy":   "Accuracy",
+	"Stealth":    "Stealth",
+	"Cleave":     "Cleave",
+	"Recovery":   "Recovery",
+	"Damage":     "Damage",
+	"Range":      "Sight Range",
+	"LightRange": "Light Range",
+}
+
+// formatEquipmentEffect formats an effect of worn gear in combat terms, such as
+// "+3 Attack", "-1 Evasion" or "1d4 fire damage"
+func (s *RenderSystem) formatEquipmentEffect(effect components.GameEffect) string {
+	if effect.Target.Property == "LightSource" {
+		return "Gives off light"
+	}
+
+	stat, known := equipmentStatNames[effect.Target.Property]
+	if !known {
+		stat = effect.Target.Property
+	}
+	if effect.School != "" {
+		stat = effect.School + " " + strings.ToLower(stat)
+	}
+
+	// Dice read as their notation, flat amounts as whole numbers where they are
+	if dice, ok := effect.Value.(string); ok && strings.Contains(dice, "d") {
+		if effect.Operation == components.EffectOpSubtract {
+			return fmt.Sprintf("-%s %s", dice, stat)
+		}
+		return fmt.Sprintf("%s %s", dice, stat)
+	}
+	amount := strconv.FormatFloat(effect.Strength(), 'f', -1, 64)
+
+	negative := effect.Operation == components.EffectOpSubtract
+	switch v := effect.Value.(type) {
+	case float64:
+		negative = negative != (v < 0)
+	case int:
+		negative = negative != (v < 0)
+	}
+
+	switch effect.Operation {
+	case components.EffectOpAdd, components.EffectOpSubtract:
+		if negative {
+			return fmt.Sprintf("-%s %s", amount, stat)
+		}
+		return fmt.Sprintf("+%s %s", amount, stat)
+	case components.EffectOpMultiply:
+		return fmt.Sprintf("x%s %s", amount, stat)
+	case components.EffectOpSet:
+		return fmt.Sprintf("%s %s", stat, amount)
+	}
+	return fmt.Sprintf("%s %s", effect.Operation, stat)
+}
+
 // drawMessagesPanel draws the message log panel
 func (s *RenderSystem) drawMessagesPanel(screen *ebiten.Image) {
 	// Draw messages panel border
 	for x := 0; x < config.ScreenWidth; x++ {
-		s.tileset.DrawTile(screen, '-', x, config.MessageWindowStartY, color.RGBA{200, 200, 200, 255})
+		s.tileset.DrawTile(screen, '-', x, config.MessageWindowStartY, s.palette.PanelText)
 	}
 
 	// Get message log
@@ -981,10 +2071,10 @@ func (s *RenderSystem) drawMessagesPanel(screen *ebiten.Image) {
 	maxMessages := messagesAreaHeight
 
 	// Draw title for the message area
-	s.tileset.DrawString(screen, "MESSAGE LOG", 1, config.MessageWindowStartY+1, color.RGBA{255, 230, 150, 255})
+	s.tileset.DrawString(screen, "MESSAGE LOG", 1, config.MessageWindowStartY+1, s.palette.PanelHeading)
 
 	// Get visible messages based on scroll position
-	messages := messageLog.RecentMessages(100) // Get all messages
+	messages := messageLog.RecentMessages(len(messageLog.Messages)) // Get all retained messages
 	startIdx := s.messageScrollOffset
 	if startIdx > len(messages)-maxMessages {
 		startIdx = len(messages) - maxMessages
@@ -1003,10 +2093,10 @@ func (s *RenderSystem) drawMessagesPanel(screen *ebiten.Image) {
 	// Draw scroll indicators if needed
 	if len(messages) > maxMessages {
 		if s.messageScrollOffset > 0 {
-			s.tileset.DrawTile(screen, '▲', config.ScreenWidth-2, config.MessageWindowStartY+2, color.RGBA{200, 200, 200, 255})
+			s.tileset.DrawTile(screen, '▲', config.ScreenWidth-2, config.MessageWindowStartY+2, s.palette.PanelText)
 		}
 		if s.messageScrollOffset < len(messages)-maxMessages {
-			s.tileset.DrawTile(screen, '▼', config.ScreenWidth-2, config.MessageWindowStartY+maxMessages, color.RGBA{200, 200, 200, 255})
+			s.tileset.DrawTile(screen, '▼', config.ScreenWidth-2, config.MessageWindowStartY+maxMessages, s.palette.PanelText)
 		}
 	}
 
@@ -1026,7 +2116,7 @@ func (s *RenderSystem) ScrollMessagesUp() {
 // ScrollMessagesDown scrolls the message window down one line
 func (s *RenderSystem) ScrollMessagesDown() {
 	messageLog := GetMessageLog()
-	messages := messageLog.RecentMessages(100)
+	messages := messageLog.RecentMessages(len(messageLog.Messages))
 	maxVisible := config.MessageWindowHeight - 2 // Account for title and border
 
 	if s.messageScrollOffset < len(messages)-maxVisible {
@@ -1047,14 +2137,14 @@ func (s *RenderSystem) drawDebugWindow(screen *ebiten.Image) {
 	for y := 0; y < config.ScreenHeight; y++ {
 		for x := 0; x < config.ScreenWidth; x++ {
 			// Completely opaque dark background
-			s.tileset.DrawTile(screen, ' ', x, y, color.RGBA{20, 20, 30, 255})
+			s.tileset.DrawTile(screen, ' ', x, y, s.palette.DebugBackground)
 		}
 	}
 
 	// Create a subtle pattern in the background to make it look like a separate screen
 	for y := 0; y < config.ScreenHeight; y += 4 {
 		for x := 0; x < config.ScreenWidth; x += 8 {
-			s.tileset.DrawTile(screen, '·', x, y, color.RGBA{40, 40, 60, 255})
+			s.tileset.DrawTile(screen, '·', x, y, s.palette.DebugPattern)
 		}
 	}
 
@@ -1062,12 +2152,12 @@ func (s *RenderSystem) drawDebugWindow(screen *ebiten.Image) {
 	for y := 0; y < windowHeight; y++ {
 		for x := 0; x < windowWidth; x++ {
 			// Solid black background for the actual window
-			s.tileset.DrawTile(screen, ' ', startX+x, startY+y, color.RGBA{0, 0, 0, 255})
+			s.tileset.DrawTile(screen, ' ', startX+x, startY+y, s.palette.Background)
 		}
 	}
 
 	// Draw window border (white)
-	borderColor := color.RGBA{255, 255, 255, 255}
+	borderColor := s.palette.DebugText
 	for x := 0; x < windowWidth; x++ {
 		s.tileset.DrawTile(screen, '═', startX+x, startY, borderColor)
 		s.tileset.DrawTile(screen, '═', startX+x, startY+windowHeight-1, borderColor)
@@ -1084,7 +2174,7 @@ func (s *RenderSystem) drawDebugWindow(screen *ebiten.Image) {
 	s.tileset.DrawTile(screen, '╝', startX+windowWidth-1, startY+windowHeight-1, borderColor)
 
 	// Draw window title (white text)
-	titleColor := color.RGBA{255, 255, 255, 255}
+	titleColor := s.palette.DebugText
 	s.tileset.DrawString(screen, "DEBUG MESSAGES (ESC to close, ↑/↓ to scroll)", startX+2, startY+1, titleColor)
 
 	// Draw separator under title
@@ -1102,7 +2192,7 @@ func (s *RenderSystem) drawDebugWindow(screen *ebiten.Image) {
 
 	// Display visible messages with white text
 	visibleMessages := s.getVisibleDebugMessages(debugLog, scrollOffset, maxVisibleMessages)
-	messageColor := color.RGBA{255, 255, 255, 255}
+	messageColor := s.palette.DebugText
 
 	for i, msg := range visibleMessages {
 		s.tileset.DrawString(screen, msg, startX+2, startY+3+i, messageColor)
@@ -1272,6 +2362,43 @@ func (s *RenderSystem) handleEquipmentChange(world *ecs.World, event interface{}
 // Equipment rendering is now done directly in the drawStatsPanel method
 // without any caching or intermediate updates
 
+// getWeatherSystem finds the weather system in the world
+func (s *RenderSystem) getWeatherSystem(world *ecs.World) *WeatherSystem {
+	for _, system := range world.GetSystems() {
+		if weatherSystem, ok := system.(*WeatherSystem); ok {
+			return weatherSystem
+		}
+	}
+	return nil
+}
+
+// getTimeSystem finds the time system in the world
+func (s *RenderSystem) getTimeSystem(world *ecs.World) *TimeSystem {
+	for _, system := range world.GetSystems() {
+		if timeSystem, ok := system.(*TimeSystem); ok {
+			return timeSystem
+		}
+	}
+	return nil
+}
+
+// tintColor blends a colour towards a tint by the given amount (0-1)
+func tintColor(c color.Color, tint color.RGBA, amount float64) color.Color {
+	rgba, ok := c.(color.RGBA)
+	if !ok {
+		return c
+	}
+	blend := func(from, to uint8) uint8 {
+		return uint8(float64(from)*(1-amount) + float64(to)*amount)
+	}
+	return color.RGBA{
+		R: blend(rgba.R, tint.R),
+		G: blend(rgba.G, tint.G),
+		B: blend(rgba.B, tint.B),
+		A: rgba.A,
+	}
+}
+
 // getActiveMap returns the currently active map entity
 func (s *RenderSystem) getActiveMap(world *ecs.World) *ecs.Entity {
 	// Find the MapRegistrySystem
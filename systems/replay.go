@@ -0,0 +1,151 @@
+package systems
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// Replay is a recorded session: the master seed, the keys held over time and a
+// checksum of the game after every turn. Played back with the same seed the input
+// should rebuild the session exactly, and the checksums show where it doesn't.
+type Replay struct {
+	Seed   int64         `json:"seed"`
+	Args   []string      `json:"args"` // Command line it was recorded with, as other flags change play too
+	Inputs []ReplayInput `json:"inputs"`
+	Checks []ReplayCheck `json:"checks"`
+
+	checked  int  // Turns compared so far in playback
+	diverged bool // Whether playback has already reported a divergence
+}
+
+// ReplayInput is the set of keys held from a tick on. One is recorded whenever the
+// held keys change.
+type ReplayInput struct {
+	Tick int          `json:"tick"`
+	Turn int          `json:"turn"` // Turns completed by then, to find your way around the file
+	Keys []ebiten.Key `json:"keys"`
+}
+
+// ReplayCheck is a checksum of the game taken when a turn completes
+type ReplayCheck struct {
+	Tick     int    `json:"tick"`
+	Checksum uint64 `json:"checksum"`
+}
+
+// NewReplay starts an empty replay for a session on the given seed
+func NewReplay(seed int64, args []string) *Replay {
+	return &Replay{Seed: seed, Args: args}
+}
+
+// LoadReplay reads a replay saved by SaveToFile
+func LoadReplay(path string) (*Replay, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %w", err)
+	}
+	var replay Replay
+	if err := json.Unmarshal(fileData, &replay); err != nil {
+		return nil, fmt.Errorf("failed to parse replay: %w", err)
+	}
+	return &replay, nil
+}
+
+// SaveToFile writes the replay as JSON
+func (r *Replay) SaveToFile(path string) error {
+	fileData, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode replay: %w", err)
+	}
+	if err := os.WriteFile(path, fileData, 0644); err != nil {
+		return fmt.Errorf("failed to save replay: %w", err)
+	}
+	return nil
+}
+
+// RecordInput records every tick's keys to the replay from now on
+func RecordInput(replay *Replay) {
+	input.recording = replay
+}
+
+// PlayInput feeds the replay's keys to the game in place of the keyboard until they
+// run out
+func PlayInput(replay *Replay) {
+	input.playing = replay
+	input.next = 0
+}
+
+// CheckReplay takes the checksum of a completed turn. While recording it is stored;
+// while playing back it is compared with the recorded one, and the first mismatch is
+// reported, since from there on the game has gone somewhere the recording never did.
+func CheckReplay(checksum uint64) {
+	if replay := input.recording; replay != nil {
+		replay.Checks = append(replay.Checks, ReplayCheck{Tick: input.tick, Checksum: checksum})
+	}
+	replay := input.playing
+	if replay == nil || replay.diverged {
+		return
+	}
+
+	turn := replay.checked
+	replay.checked++
+	if turn >= len(replay.Checks) {
+		return
+	}
+	expected := replay.Checks[turn]
+	if expected.Tick == input.tick && expected.Checksum == checksum {
+		return
+	}
+
+	replay.diverged = true
+	GetMessageLog().AddAlert(fmt.Sprintf("Replay diverged at turn %d: the game did not play out as recorded.", turn+1))
+	GetDebugLog().Add(fmt.Sprintf("Replay diverged at turn %d, tick %d: expected checksum %016x at tick %d, got %016x",
+		turn+1, input.tick, expected.Checksum, expected.Tick, checksum))
+}
+
+// ReplayChecksum hashes what a divergence would show up in: where every creature is
+// and how healthy, and how far each random stream has got
+func ReplayChecksum(world *ecs.World, rng RNGState) uint64 {
+	hash := fnv.New64a()
+	write := func(values ...int64) {
+		for _, value := range values {
+			binary.Write(hash, binary.LittleEndian, value)
+		}
+	}
+
+	for _, entity := range byID(world.Query(components.Stats, components.Position).Entities()) {
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		statsComp, _ := world.GetComponent(entity.ID, components.Stats)
+		stats := statsComp.(*components.StatsComponent)
+		write(int64(entity.ID), int64(getEntityMapID(world, entity.ID)), int64(pos.X), int64(pos.Y), int64(stats.Health))
+	}
+
+	names := make([]string, 0, len(rng.Draws))
+	for name := range rng.Draws {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		hash.Write([]byte(name))
+		write(int64(rng.Draws[name]))
+	}
+	return hash.Sum64()
+}
+
+// byID sorts entities by ID. Queries and tags come back in map order, so anything
+// that draws random numbers or acts in turn while walking them goes through this to
+// play out the same way on every run of a seed.
+func byID(entities []*ecs.Entity) []*ecs.Entity {
+	slices.SortFunc(entities, func(a, b *ecs.Entity) int { return cmp.Compare(a.ID, b.ID) })
+	return entities
+}
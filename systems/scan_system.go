@@ -0,0 +1,121 @@
+package systems
+
+import (
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// ScanSystem runs radar scans. A scan pings every monster on the scanner's floor for
+// a few turns, showing each as an anonymous blip on the map even out of sight. The
+// blips follow the monsters and fade as the scan runs down, and vanish at once if the
+// player leaves the floor.
+type ScanSystem struct {
+	mapID       ecs.EntityID // Floor being scanned, 0 when no scan is running
+	turnsLeft   int          // Turns until the scan wears off
+	duration    int          // Length of a scan in turns
+	initialized bool
+}
+
+// NewScanSystem creates a new scan system
+func NewScanSystem() *ScanSystem {
+	return &ScanSystem{
+		duration: 10,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *ScanSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Scans run down with the player's turns
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		if s.mapID == 0 {
+			return
+		}
+		s.turnsLeft--
+		if s.turnsLeft <= 0 {
+			s.stop(world)
+		}
+	})
+
+	s.initialized = true
+}
+
+// Start begins a scan of the floor the scanner is on, replacing any scan already
+// running. Returns the number of monsters found, or -1 if the scanner is not on a map.
+func (s *ScanSystem) Start(world *ecs.World, scannerID ecs.EntityID) int {
+	mapID := getEntityMapID(world, scannerID)
+	if mapID == 0 {
+		return -1
+	}
+
+	s.mapID = mapID
+	s.turnsLeft = s.duration
+	blips := s.monsterPositions(world)
+	if renderSystem := s.getRenderSystem(world); renderSystem != nil {
+		renderSystem.SetRadarBlips(s.mapID, blips, 1)
+	}
+	return len(blips)
+}
+
+// IsActive returns whether a scan is running
+func (s *ScanSystem) IsActive() bool {
+	return s.mapID != 0
+}
+
+// Update refreshes the blips of a running scan, ending it if the player has left
+// the scanned floor
+func (s *ScanSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+	if s.mapID == 0 {
+		return
+	}
+
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 || getEntityMapID(world, playerEntities[0].ID) != s.mapID {
+		s.stop(world)
+		return
+	}
+
+	if renderSystem := s.getRenderSystem(world); renderSystem != nil {
+		renderSystem.SetRadarBlips(s.mapID, s.monsterPositions(world), float64(s.turnsLeft)/float64(s.duration))
+	}
+}
+
+// stop ends the running scan and clears its blips
+func (s *ScanSystem) stop(world *ecs.World) {
+	s.mapID = 0
+	s.turnsLeft = 0
+	if renderSystem := s.getRenderSystem(world); renderSystem != nil {
+		renderSystem.ClearRadarBlips()
+	}
+}
+
+// monsterPositions returns where every monster on the scanned floor is
+func (s *ScanSystem) monsterPositions(world *ecs.World) []Point {
+	var positions []Point
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != s.mapID {
+			continue
+		}
+		if posComp, exists := world.GetComponent(entity.ID, components.Position); exists {
+			pos := posComp.(*components.PositionComponent)
+			positions = append(positions, Point{X: pos.X, Y: pos.Y})
+		}
+	}
+	return positions
+}
+
+// getRenderSystem finds the render system in the world
+func (s *ScanSystem) getRenderSystem(world *ecs.World) *RenderSystem {
+	for _, system := range world.GetSystems() {
+		if renderSystem, ok := system.(*RenderSystem); ok {
+			return renderSystem
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// shrineBoon is a permanent stat change an altar can grant or inflict
+type shrineBoon struct {
+	Property string // Stats property that changes
+	Amount   int    // How much it changes by for a plain offering
+	Message  string // Shown in the log when it takes hold
+}
+
+var (
+	shrineBlessings = []shrineBoon{
+		{Property: "Attack", Amount: 1, Message: "Strength flows into your arms."},
+		{Property: "Defense", Amount: 1, Message: "Your skin toughens like leather."},
+		{Property: "MaxHealth", Amount: 10, Message: "You feel hale and hearty."},
+		{Property: "Evasion", Amount: 1, Message: "You feel light on your feet."},
+		{Property: "Accuracy", Amount: 1, Message: "Your eye grows keen."},
+		{Property: "Stealth", Amount: 1, Message: "Your footsteps fall silent."},
+	}
+	shrineCurses = []shrineBoon{
+		{Property: "Attack", Amount: 1, Message: "Your arms grow heavy."},
+		{Property: "Defense", Amount: 1, Message: "Your skin crawls and softens."},
+		{Property: "MaxHealth", Amount: 5, Message: "A chill settles in your bones."},
+	}
+)
+
+// ShrineSystem lets the player sacrifice items at altars. Bumping an altar readies it,
+// then offering an item from the inventory earns a random blessing or, rarely, a
+// curse. Valuable offerings make curses less likely and blessings stronger. Each
+// altar answers only once.
+type ShrineSystem struct {
+	activeShrine   ecs.EntityID          // Altar the player last bumped into
+	used           map[ecs.EntityID]bool // Altars that have already answered
+	baseCurse      float64               // Chance of a curse for a worthless offering
+	minCurse       float64               // Lowest the curse chance can go
+	cursePerValue  float64               // Curse chance taken off per point of item value
+	greaterAtValue int                   // Offerings worth this much double the blessing
+	initialized    bool
+}
+
+// NewShrineSystem creates a new shrine system
+func NewShrineSystem() *ShrineSystem {
+	return &ShrineSystem{
+		used:           make(map[ecs.EntityID]bool),
+		baseCurse:      0.25,
+		minCurse:       0.03,
+		cursePerValue:  0.005,
+		greaterAtValue: 30,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *ShrineSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Bumping into an altar readies it for an offering
+	world.GetEventManager().Subscribe(EventCollision, func(event ecs.Event) {
+		collision := event.(CollisionEvent)
+		if !isPlayer(world, collision.EntityID1) {
+			return
+		}
+		if entity := world.GetEntity(collision.EntityID2); entity != nil && entity.HasTag("shrine") {
+			s.approach(collision.EntityID2)
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *ShrineSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// approach readies an altar the player bumped into
+func (s *ShrineSystem) approach(shrineID ecs.EntityID) {
+	if s.used[shrineID] {
+		GetMessageLog().AddEnvironment("The altar is cold and silent.")
+		return
+	}
+	s.activeShrine = shrineID
+	GetMessageLog().AddEnvironment("The altar awaits an offering. Open your inventory and press O to sacrifice an item.")
+}
+
+// CurseChance returns the chance an offering of the given value brings a curse
+func (s *ShrineSystem) CurseChance(value int) float64 {
+	return math.Max(s.minCurse, s.baseCurse-float64(value)*s.cursePerValue)
+}
+
+// Offer sacrifices the item at the given inventory index to the altar the player is
+// standing next to. Returns false if nothing was sacrificed.
+func (s *ShrineSystem) Offer(world *ecs.World, entityID ecs.EntityID, itemIndex int) bool {
+	shrineID := s.activeShrine
+	if shrineID == 0 || s.used[shrineID] || !s.isNextTo(world, entityID, shrineID) {
+		GetMessageLog().AddSystem("There is no altar here to make an offering at.")
+		return false
+	}
+
+	invComp, exists := world.GetComponent(entityID, components.Inventory)
+	if !exists {
+		return false
+	}
+	inventory := invComp.(*components.InventoryComponent)
+	if itemIndex < 0 || itemIndex >= inventory.Size() {
+		return false
+	}
+	itemID := inventory.Items[itemIndex]
+
+	if equipComp, exists := world.GetComponent(entityID, components.Equipment); exists {
+		for _, equippedID := range equipComp.(*components.EquipmentComponent).EquippedItems {
+			if equippedID == itemID {
+				GetMessageLog().AddSystem("Unequip it before offering it.")
+				return false
+			}
+		}
+	}
+
+	value := 0
+	if itemComp, exists := world.GetComponent(itemID, components.Item); exists {
+		value = itemComp.(*components.ItemComponent).Value
+	}
+	itemName := getEntityName(world, itemID)
+	inventory.RemoveItem(itemID)
+	world.RemoveEntity(itemID)
+	GetMessageLog().AddItem(fmt.Sprintf("You lay the %s on the altar. It crumbles to dust.", itemName))
+
+	if playRNG.Float64() < s.CurseChance(value) {
+		curse := shrineCurses[playRNG.Intn(len(shrineCurses))]
+		s.bestow(world, entityID, shrineID, curse, components.EffectOpSubtract, curse.Amount)
+		GetMessageLog().AddCombat("The altar rejects your offering! " + curse.Message)
+	} else {
+		blessing := shrineBlessings[playRNG.Intn(len(shrineBlessings))]
+		amount := blessing.Amount
+		if value >= s.greaterAtValue {
+			amount *= 2
+		}
+		s.bestow(world, entityID, shrineID, blessing, components.EffectOpAdd, amount)
+		GetMessageLog().AddAlert("The altar glows warmly. " + blessing.Message)
+	}
+
+	s.used[shrineID] = true
+	s.activeShrine = 0
+	if renderComp, exists := world.GetComponent(shrineID, components.Renderable); exists {
+		renderComp.(*components.RenderableComponent).FG = color.RGBA{110, 110, 110, 255}
+	}
+	return true
+}
+
+// bestow hands the boon to the EffectsSystem as an instant effect, which changes the
+// stat for good when the turn ends
+func (s *ShrineSystem) bestow(world *ecs.World, entityID, shrineID ecs.EntityID, boon shrineBoon, operation components.EffectOperation, amount int) {
+	for _, system := range world.GetSystems() {
+		if effectsSystem, ok := system.(*EffectsSystem); ok {
+			effect := effectsSystem.CreateGameEffect(components.EffectTypeInstant, operation, float64(amount), 0, shrineID, "Stats", boon.Property)
+			effectsSystem.ApplyEntityEffects(world, entityID, []components.GameEffect{effect})
+			return
+		}
+	}
+}
+
+// isNextTo returns whether two entities are on the same map and touching
+func (s *ShrineSystem) isNextTo(world *ecs.World, entityID, otherID ecs.EntityID) bool {
+	if getEntityMapID(world, entityID) != getEntityMapID(world, otherID) {
+		return false
+	}
+	posComp, exists := world.GetComponent(entityID, components.Position)
+	if !exists {
+		return false
+	}
+	otherComp, exists := world.GetComponent(otherID, components.Position)
+	if !exists {
+		return false
+	}
+	pos := posComp.(*components.PositionComponent)
+	other := otherComp.(*components.PositionComponent)
+	return abs(pos.X-other.X) <= 1 && abs(pos.Y-other.Y) <= 1
+}
@@ -0,0 +1,55 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// dangerTiers name how dangerous a floor is likely to be, from calmest to deadliest
+var dangerTiers = []string{"Calm", "Risky", "Dangerous", "Deadly"}
+
+// floorPreview returns the theme name and a rough danger tier of the floor the down
+// stairs at x,y lead to. Floors are generated ahead of time, so the hint always
+// matches what is down there; it stays vague to keep some discovery. Returns false
+// for anything but down stairs to a themed floor.
+func floorPreview(world *ecs.World, mapID ecs.EntityID, x, y int) (string, string, bool) {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return "", "", false
+	}
+	mapData := mapComp.(*components.MapComponent)
+	if !mapData.InBounds(x, y) || mapData.Tiles[y][x] != components.TileStairsDown {
+		return "", "", false
+	}
+	transition, exists := mapData.GetTransition(x, y)
+	if !exists {
+		return "", "", false
+	}
+	themeComp, exists := world.GetComponent(transition.TargetMapID, components.FloorTheme)
+	if !exists {
+		return "", "", false
+	}
+	theme := themeComp.(*components.FloorThemeComponent)
+	depth, _ := dungeonLevel(world, transition.TargetMapID)
+
+	// Harder themes and deeper floors are more dangerous, a stronger player less so
+	playerLevel := 1
+	if playerEntities := world.GetEntitiesWithTag("player"); len(playerEntities) > 0 {
+		if statsComp, exists := world.GetComponent(playerEntities[0].ID, components.Stats); exists {
+			playerLevel = statsComp.(*components.StatsComponent).Level
+		}
+	}
+	score := theme.Difficulty + (theme.DungeonLevel - 1) + (depth - 1) - (playerLevel - 1)
+	tier := min(max(score/2, 0), len(dangerTiers)-1)
+
+	return theme.Name, dangerTiers[tier], true
+}
+
+// announceStairs tells the player what lies below when they step onto down stairs
+func announceStairs(world *ecs.World, playerID ecs.EntityID, x, y int) {
+	if name, danger, ok := floorPreview(world, getEntityMapID(world, playerID), x, y); ok {
+		GetMessageLog().AddEnvironment(fmt.Sprintf("Stairs lead down. Below: %s (%s). Press Enter to descend.", name, danger))
+	}
+}
@@ -0,0 +1,101 @@
+package systems
+
+import (
+	"math"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+const (
+	dungeonLight    = 0.5 // Ambient light underground, where only the odd lamp burns
+	sneakBonus      = 3   // Stealth gained while sneaking
+	weightPerNoise  = 6   // Equipped weight that costs a point of stealth
+	minDetectRange  = 1.0 // Even the dimmest monster notices something next to it some of the time
+	detectionSpread = 2.0 // Tiles over which the chance to notice fades from certain to none
+)
+
+// AmbientLight returns how well lit an entity is, from 0 for pitch dark to 1 for broad
+// daylight. The surface follows the time of day, dungeons are dim, and anything carrying
+// a light or standing in a burning torch's light is fully lit.
+func AmbientLight(world *ecs.World, entityID ecs.EntityID) float64 {
+	if fovComp, exists := world.GetComponent(entityID, components.FOV); exists {
+		if fovComp.(*components.FOVComponent).LightSource {
+			return 1
+		}
+	}
+
+	mapID := getEntityMapID(world, entityID)
+	if typeComp, exists := world.GetComponent(mapID, components.MapType); exists {
+		if typeComp.(*components.MapTypeComponent).MapType != "worldmap" {
+			if posComp, exists := world.GetComponent(entityID, components.Position); exists {
+				pos := posComp.(*components.PositionComponent)
+				if mapComp, exists := world.GetComponent(mapID, components.MapComponentID); exists &&
+					torchLightAt(world, mapID, mapComp.(*components.MapComponent), pos.X, pos.Y) {
+					return 1
+				}
+			}
+			return dungeonLight
+		}
+	}
+	for _, system := range world.GetSystems() {
+		if timeSystem, ok := system.(*TimeSystem); ok {
+			return timeSystem.TimeOfDay(world).Light
+		}
+	}
+	return 1
+}
+
+// EffectiveStealth returns an entity's stealth: its stealth stat, plus a bonus while
+// sneaking, minus a point for every few pounds of gear it has equipped
+func EffectiveStealth(world *ecs.World, entityID ecs.EntityID) int {
+	stealth := 0
+	if statsComp, exists := world.GetComponent(entityID, components.Stats); exists {
+		stealth = statsComp.(*components.StatsComponent).Stealth
+	}
+	if IsSneaking(world, entityID) {
+		stealth += sneakBonus
+	}
+
+	if equipComp, exists := world.GetComponent(entityID, components.Equipment); exists {
+		weight := 0
+		for _, itemID := range equipComp.(*components.EquipmentComponent).EquippedItems {
+			if itemComp, exists := world.GetComponent(itemID, components.Item); exists {
+				weight += itemComp.(*components.ItemComponent).Weight
+			}
+		}
+		stealth -= weight / weightPerNoise
+	}
+	return stealth
+}
+
+// IsSneaking returns whether the player entity is sneaking
+func IsSneaking(world *ecs.World, entityID ecs.EntityID) bool {
+	if playerComp, exists := world.GetComponent(entityID, components.Player); exists {
+		return playerComp.(*components.PlayerComponent).Sneaking
+	}
+	return false
+}
+
+// DetectionRange returns how far a monster can spot a target this turn. Its sight range
+// is cut by how dark it is around the target and by the target's stealth. Monsters that
+// are already hunting keep their full sight range.
+func DetectionRange(world *ecs.World, ai *components.AIComponent, targetID ecs.EntityID) float64 {
+	if ai.State == components.AIStateChase || ai.State == components.AIStateAttack {
+		return float64(ai.SightRange)
+	}
+
+	detection := float64(ai.SightRange)*AmbientLight(world, targetID) - float64(EffectiveStealth(world, targetID))
+	return math.Max(minDetectRange, math.Min(detection, float64(ai.SightRange)))
+}
+
+// rollDetection decides whether a monster notices a target at the given distance. Well
+// inside the detection range it always does, at the edge it is a coin flip, and the
+// chance runs out a tile beyond it.
+func rollDetection(detectionRange, distance float64) bool {
+	chance := (detectionRange-distance)/detectionSpread + 0.5
+	if chance >= 1 {
+		return true
+	}
+	return playRNG.Float64() < chance
+}
@@ -0,0 +1,47 @@
+package systems
+
+import (
+	"fmt"
+)
+
+// StuckLimit is how many automated moves in a row may go nowhere before the movement
+// is halted
+const StuckLimit = 6
+
+// stuckGuard watches movement that carries on without a key press per step, such as a
+// held key or auto-explore, for a lack of progress. It trips once the last StuckLimit
+// moves left the player on no more than two tiles: standing still against a wall, or
+// pacing back and forth between the same two tiles.
+type stuckGuard struct {
+	recent []Point // Where the latest moves left the player, oldest first
+}
+
+// Reset forgets the moves seen so far, for when a new movement starts
+func (g *stuckGuard) Reset() {
+	g.recent = g.recent[:0]
+}
+
+// Record notes where a move left the player and returns true if the movement is stuck.
+// The stuck condition is written to the debug log under the movement's name.
+func (g *stuckGuard) Record(movement string, pos Point) bool {
+	g.recent = append(g.recent, pos)
+	if len(g.recent) > StuckLimit {
+		g.recent = g.recent[1:]
+	}
+	if len(g.recent) < StuckLimit {
+		return false
+	}
+
+	tiles := make(map[Point]bool)
+	for _, p := range g.recent {
+		tiles[p] = true
+	}
+	if len(tiles) > 2 {
+		return false
+	}
+
+	GetDebugLog().Add(fmt.Sprintf("%s stuck: last %d moves stayed on %d tile(s), ending at (%d,%d)",
+		movement, StuckLimit, len(tiles), pos.X, pos.Y))
+	g.Reset()
+	return true
+}
@@ -0,0 +1,458 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/data"
+	"ebiten-rogue/ecs"
+)
+
+// TargetPreference is which hostile targeting starts on
+type TargetPreference string
+
+const (
+	TargetNearest   TargetPreference = "nearest"   // The closest hostile
+	TargetDangerous TargetPreference = "dangerous" // The hostile with the highest threat rating
+	TargetWeakest   TargetPreference = "weakest"   // The hostile with the least health left, for finishing blows
+)
+
+// TargetingSystem lets the player pick which hostile ranged attacks and abilities
+// are aimed at. Tab enters targeting on the preferred hostile, nearest by default,
+// and cycles outwards, Escape leaves it. Only hostiles in range and line of sight can
+// be targeted. Throws that land on a tile rather than a hostile aim with a cursor
+// moved by the movement keys instead, see AimTile.
+type TargetingSystem struct {
+	active          bool             // Whether targeting mode is on
+	targetID        ecs.EntityID     // Currently selected target, 0 for none
+	targetRange     int              // Furthest a target can be in tiles
+	preference      TargetPreference // Which hostile targeting starts on
+	showAggro       bool             // Whether the target's aggro radius is tinted on the map, R toggles it
+	aiming          bool             // Whether a tile is being aimed at
+	aimX, aimY      int              // Tile under the aim cursor
+	aimRange        int              // Furthest the aim cursor can be from the player in tiles
+	onAim           func(x, y int)   // Called with the aimed tile once it is confirmed
+	keybindings     *Keybindings     // Movement keys that move the aim cursor, and the targeting keys
+	templateManager *data.EntityTemplateManager
+	initialized     bool
+}
+
+// NewTargetingSystem creates a new targeting system
+func NewTargetingSystem() *TargetingSystem {
+	return &TargetingSystem{
+		targetRange: 5,
+		preference:  TargetNearest,
+		keybindings: NewKeybindings(),
+	}
+}
+
+// SetKeybindings sets the keys the aim cursor is moved with and targets are cycled with
+func (s *TargetingSystem) SetKeybindings(keybindings *Keybindings) {
+	s.keybindings = keybindings
+}
+
+// SetPreference sets which hostile targeting starts on
+func (s *TargetingSystem) SetPreference(preference TargetPreference) {
+	s.preference = preference
+}
+
+// SetTemplateManager sets the template manager monster threat ratings are read from
+func (s *TargetingSystem) SetTemplateManager(templateManager *data.EntityTemplateManager) {
+	s.templateManager = templateManager
+}
+
+// Initialize sets up event listeners
+func (s *TargetingSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// A removed target can't be aimed at; Update picks the next nearest
+	world.GetEventManager().Subscribe(ecs.EventEntityRemoved, func(event ecs.Event) {
+		if event.(ecs.EntityRemovedEvent).EntityID == s.targetID {
+			s.targetID = 0
+		}
+	})
+
+	s.initialized = true
+}
+
+// IsActive returns whether targeting mode is on
+func (s *TargetingSystem) IsActive() bool {
+	return s.active
+}
+
+// GetTarget returns the selected target if it is still valid, or 0
+func (s *TargetingSystem) GetTarget(world *ecs.World) ecs.EntityID {
+	if !s.active || s.targetID == 0 {
+		return 0
+	}
+	for _, targetID := range s.ValidTargets(world) {
+		if targetID == s.targetID {
+			return targetID
+		}
+	}
+	return 0
+}
+
+// IsAiming returns whether a tile is being aimed at
+func (s *TargetingSystem) IsAiming() bool {
+	return s.aiming
+}
+
+// Stop leaves targeting mode and clears the target, and drops any aim in progress
+func (s *TargetingSystem) Stop() {
+	s.active = false
+	s.targetID = 0
+	s.aiming = false
+	s.onAim = nil
+}
+
+// AimTile starts aiming at a tile within a range of the player, with the cursor on
+// the current target if there is one and on the player otherwise. The movement keys
+// move the cursor, Enter confirms the tile and passes it to onAim, Escape cancels.
+// Returns false if the player isn't on a map.
+func (s *TargetingSystem) AimTile(world *ecs.World, aimRange int, onAim func(x, y int)) bool {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return false
+	}
+	posComp, exists := world.GetComponent(playerEntities[0].ID, components.Position)
+	if !exists {
+		return false
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	s.aimX, s.aimY = pos.X, pos.Y
+	if targetID := s.GetTarget(world); targetID != 0 {
+		targetPosComp, _ := world.GetComponent(targetID, components.Position)
+		targetPos := targetPosComp.(*components.PositionComponent)
+		if math.Hypot(float64(targetPos.X-pos.X), float64(targetPos.Y-pos.Y)) <= float64(aimRange) {
+			s.aimX, s.aimY = targetPos.X, targetPos.Y
+		}
+	}
+	s.aiming = true
+	s.aimRange = aimRange
+	s.onAim = onAim
+	return true
+}
+
+// Begin enters targeting mode on the preferred hostile. Returns false if there is
+// nothing in range to target.
+func (s *TargetingSystem) Begin(world *ecs.World) bool {
+	targets := s.ValidTargets(world)
+	if len(targets) == 0 {
+		return false
+	}
+	s.active = true
+	s.selectTarget(world, s.preferredTarget(world, targets))
+	return true
+}
+
+// Select enters targeting mode on a particular hostile. Returns false if it can't be
+// targeted from where the player stands.
+func (s *TargetingSystem) Select(world *ecs.World, targetID ecs.EntityID) bool {
+	for _, validID := range s.ValidTargets(world) {
+		if validID == targetID {
+			s.active = true
+			s.selectTarget(world, targetID)
+			return true
+		}
+	}
+	return false
+}
+
+// Update handles the targeting keys and drops targets that became invalid
+func (s *TargetingSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+	defer s.highlightTarget(world)
+
+	// Don't target while the inventory is open
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok && renderSys.IsInventoryOpen() {
+			return
+		}
+	}
+
+	if s.aiming {
+		s.updateAim(world)
+		return
+	}
+
+	if s.keybindings.JustPressed(ActionCycleTarget) {
+		s.cycle(world)
+		return
+	}
+
+	if !s.active {
+		return
+	}
+
+	if KeyJustPressed(ebiten.KeyEscape) {
+		s.Stop()
+		GetMessageLog().AddSystem("Targeting cancelled.")
+		return
+	}
+
+	if s.keybindings.JustPressed(ActionShowAggro) {
+		s.showAggro = !s.showAggro
+		if s.showAggro {
+			GetMessageLog().AddSystem("Showing where the target would notice you.")
+		} else {
+			GetMessageLog().AddSystem("Aggro radius hidden.")
+		}
+	}
+
+	// Fall back to the preferred hostile when the target dies, moves away or out of sight
+	if s.GetTarget(world) == 0 {
+		targets := s.ValidTargets(world)
+		if len(targets) == 0 {
+			s.Stop()
+			GetMessageLog().AddSystem("No targets left in range.")
+			return
+		}
+		s.selectTarget(world, s.preferredTarget(world, targets))
+	}
+}
+
+// updateAim handles the keys while aiming at a tile
+func (s *TargetingSystem) updateAim(world *ecs.World) {
+	if KeyJustPressed(ebiten.KeyEscape) {
+		s.aiming = false
+		s.onAim = nil
+		GetMessageLog().AddSystem("Aim cancelled.")
+		return
+	}
+
+	if KeyJustPressed(ebiten.KeyEnter) || KeyJustPressed(ebiten.KeyNumpadEnter) {
+		onAim := s.onAim
+		s.aiming = false
+		s.onAim = nil
+		if onAim != nil {
+			onAim(s.aimX, s.aimY)
+		}
+		return
+	}
+
+	for _, movement := range movementActions {
+		if s.keybindings.JustPressed(movement.action) {
+			dx, dy := directionDelta(movement.dir)
+			s.moveAim(world, dx, dy)
+		}
+	}
+}
+
+// moveAim moves the aim cursor a step, as long as it stays on the map and in range
+func (s *TargetingSystem) moveAim(world *ecs.World, dx, dy int) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	playerID := playerEntities[0].ID
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+	mapComp, exists := world.GetComponent(getEntityMapID(world, playerID), components.MapComponentID)
+	if !exists {
+		return
+	}
+	mapComponent := mapComp.(*components.MapComponent)
+
+	x, y := s.aimX+dx, s.aimY+dy
+	if x < 0 || x >= mapComponent.Width || y < 0 || y >= mapComponent.Height {
+		return
+	}
+	if math.Hypot(float64(x-pos.X), float64(y-pos.Y)) > float64(s.aimRange) {
+		return
+	}
+	s.aimX, s.aimY = x, y
+}
+
+// highlightAim marks the line from the player to the aim cursor on the map
+func (s *TargetingSystem) highlightAim(world *ecs.World) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	posComp, exists := world.GetComponent(playerEntities[0].ID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok {
+			for _, point := range LinePoints(pos.X, pos.Y, s.aimX, s.aimY)[1:] {
+				renderSys.HighlightTile(point.X, point.Y, color.RGBA{90, 70, 30, 255})
+			}
+			renderSys.HighlightTile(s.aimX, s.aimY, color.RGBA{200, 140, 40, 255})
+			return
+		}
+	}
+}
+
+// highlightTarget marks the selected target's tile on the map, or the aim line while
+// aiming at a tile
+func (s *TargetingSystem) highlightTarget(world *ecs.World) {
+	if s.aiming {
+		s.highlightAim(world)
+		return
+	}
+	targetID := s.GetTarget(world)
+	if targetID == 0 {
+		return
+	}
+	posComp, exists := world.GetComponent(targetID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok {
+			s.highlightAggro(world, renderSys, targetID)
+			renderSys.HighlightTile(pos.X, pos.Y, color.RGBA{140, 30, 30, 255})
+			return
+		}
+	}
+}
+
+// cycle enters targeting on the preferred hostile, or moves on to the next one
+func (s *TargetingSystem) cycle(world *ecs.World) {
+	targets := s.ValidTargets(world)
+	if len(targets) == 0 {
+		s.Stop()
+		GetMessageLog().AddSystem("No targets in range.")
+		return
+	}
+
+	if !s.active {
+		s.active = true
+		s.selectTarget(world, s.preferredTarget(world, targets))
+		return
+	}
+
+	next := 0
+	for i, targetID := range targets {
+		if targetID == s.targetID {
+			next = (i + 1) % len(targets)
+			break
+		}
+	}
+	s.selectTarget(world, targets[next])
+}
+
+// preferredTarget picks the hostile targeting starts on from the valid targets, which
+// are nearest first, so ties go to the nearest
+func (s *TargetingSystem) preferredTarget(world *ecs.World, targets []ecs.EntityID) ecs.EntityID {
+	best := targets[0]
+	switch s.preference {
+	case TargetDangerous:
+		bestThreat := s.threat(world, best)
+		for _, targetID := range targets[1:] {
+			if threat := s.threat(world, targetID); threat > bestThreat {
+				best, bestThreat = targetID, threat
+			}
+		}
+	case TargetWeakest:
+		bestHealth := targetHealth(world, best)
+		for _, targetID := range targets[1:] {
+			if health := targetHealth(world, targetID); health < bestHealth {
+				best, bestHealth = targetID, health
+			}
+		}
+	}
+	return best
+}
+
+// threat rates how dangerous a hostile is: its template's threat rating, the same one
+// encounter budgets spend, or its level for monsters without a template
+func (s *TargetingSystem) threat(world *ecs.World, entityID ecs.EntityID) int {
+	if aiComp, exists := world.GetComponent(entityID, components.AI); exists && s.templateManager != nil {
+		if template, ok := s.templateManager.GetTemplate(aiComp.(*components.AIComponent).TemplateID); ok {
+			return template.Threat
+		}
+	}
+	if statsComp, exists := world.GetComponent(entityID, components.Stats); exists {
+		return statsComp.(*components.StatsComponent).Level
+	}
+	return 0
+}
+
+// targetHealth returns a hostile's current health, or a huge number if it has none
+func targetHealth(world *ecs.World, entityID ecs.EntityID) int {
+	if statsComp, exists := world.GetComponent(entityID, components.Stats); exists {
+		return statsComp.(*components.StatsComponent).Health
+	}
+	return math.MaxInt
+}
+
+// selectTarget makes an entity the current target and names it in the log
+func (s *TargetingSystem) selectTarget(world *ecs.World, targetID ecs.EntityID) {
+	s.targetID = targetID
+	GetMessageLog().AddSystem(fmt.Sprintf("Targeting %s. Tab: next target, Esc: cancel.", getEntityName(world, targetID)))
+}
+
+// ValidTargets returns the hostiles on the active map the player can see, reach and
+// has a clear line to, nearest first
+func (s *TargetingSystem) ValidTargets(world *ecs.World) []ecs.EntityID {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return nil
+	}
+	playerID := playerEntities[0].ID
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return nil
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	mapID := getEntityMapID(world, playerID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	mapComponent := mapComp.(*components.MapComponent)
+
+	var targets []ecs.EntityID
+	distances := make(map[ecs.EntityID]float64)
+	for _, entity := range world.GetEntitiesWithTag("enemy") {
+		if getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		enemyPosComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		enemyPos := enemyPosComp.(*components.PositionComponent)
+
+		dist := math.Hypot(float64(enemyPos.X-pos.X), float64(enemyPos.Y-pos.Y))
+		if dist > float64(s.targetRange) {
+			continue
+		}
+		if !mapComponent.Visible[enemyPos.Y][enemyPos.X] || !HasLineOfSight(mapComponent, pos.X, pos.Y, enemyPos.X, enemyPos.Y) {
+			continue
+		}
+
+		targets = append(targets, entity.ID)
+		distances[entity.ID] = dist
+	}
+
+	// Nearest first, with the entity ID as a tie-breaker so Tab order is stable
+	sort.Slice(targets, func(i, j int) bool {
+		if distances[targets[i]] != distances[targets[j]] {
+			return distances[targets[i]] < distances[targets[j]]
+		}
+		return targets[i] < targets[j]
+	})
+	return targets
+}
@@ -0,0 +1,140 @@
+package systems
+
+import (
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// terrainTransform is a tile change made by a terrain scroll
+type terrainTransform struct {
+	from, to int
+	material string // What the scroll works on, for when there is none in reach
+	message  string // Shown when the scroll takes effect
+	revert   string // Shown when changed tiles in sight go back, if they do
+}
+
+// terrainTransforms are the terrain scrolls' tile changes, by scroll effect
+var terrainTransforms = map[string]terrainTransform{
+	components.ScrollFreeze: {
+		from:     components.TileWater,
+		to:       components.TileIce,
+		material: "water",
+		message:  "The water around you freezes solid.",
+		revert:   "The ice cracks and thaws.",
+	},
+	components.ScrollCool: {
+		from:     components.TileLava,
+		to:       components.TileObsidian,
+		material: "lava",
+		message:  "The lava around you cools into obsidian.",
+		revert:   "The obsidian glows and melts back into lava.",
+	},
+}
+
+// TerrainSystem puts transformed tiles back once their time runs out, counting down
+// with the player's turns on every map, not just the one the player is on
+type TerrainSystem struct {
+	initialized bool
+}
+
+// NewTerrainSystem creates a new terrain system
+func NewTerrainSystem() *TerrainSystem {
+	return &TerrainSystem{}
+}
+
+// Initialize sets up event listeners
+func (s *TerrainSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.tick(world)
+	})
+
+	s.initialized = true
+}
+
+// Update does nothing; changes run down with turns
+func (s *TerrainSystem) Update(world *ecs.World, dt float64) {}
+
+// TransformTerrain turns every tile of one type within a radius of a point, and in
+// line of sight of it, into another. With a duration the tiles go back after that many
+// turns, unless something else has changed them by then. Returns how many tiles changed.
+func TransformTerrain(world *ecs.World, mapID ecs.EntityID, x, y, radius, from, to, duration int) int {
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return 0
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	var changes *components.TerrainChangesComponent
+	if duration > 0 {
+		if comp, exists := world.GetComponent(mapID, components.TerrainChanges); exists {
+			changes = comp.(*components.TerrainChangesComponent)
+		} else {
+			changes = &components.TerrainChangesComponent{}
+			world.AddComponent(mapID, components.TerrainChanges, changes)
+		}
+	}
+
+	changed := 0
+	for _, tile := range TilesInRadius(mapData, x, y, radius) {
+		if mapData.Tiles[tile.Y][tile.X] != from {
+			continue
+		}
+		mapData.SetTile(tile.X, tile.Y, to)
+		if changes != nil {
+			changes.Changes = append(changes.Changes, components.TerrainChange{
+				X: tile.X, Y: tile.Y, From: from, To: to, TurnsLeft: duration,
+			})
+		}
+		changed++
+	}
+	return changed
+}
+
+// tick counts down every map's changes and reverts the ones whose time is up,
+// telling the player about those in sight
+func (s *TerrainSystem) tick(world *ecs.World) {
+	playerMapID := ecs.EntityID(0)
+	if playerEntities := world.GetEntitiesWithTag("player"); len(playerEntities) > 0 {
+		playerMapID = getEntityMapID(world, playerEntities[0].ID)
+	}
+
+	for _, entity := range world.GetEntitiesWithComponent(components.TerrainChanges) {
+		mapComp, exists := world.GetComponent(entity.ID, components.MapComponentID)
+		if !exists {
+			continue
+		}
+		mapData := mapComp.(*components.MapComponent)
+		changesComp, _ := world.GetComponent(entity.ID, components.TerrainChanges)
+		changes := changesComp.(*components.TerrainChangesComponent)
+
+		seen := make(map[string]bool) // Revert messages already given this turn
+		remaining := changes.Changes[:0]
+		for _, change := range changes.Changes {
+			change.TurnsLeft--
+			if change.TurnsLeft > 0 {
+				remaining = append(remaining, change)
+				continue
+			}
+			if mapData.Tiles[change.Y][change.X] != change.To {
+				continue
+			}
+			mapData.SetTile(change.X, change.Y, change.From)
+			if entity.ID == playerMapID && mapData.Visible[change.Y][change.X] {
+				for _, transform := range terrainTransforms {
+					if transform.from == change.From && transform.to == change.To && !seen[transform.revert] {
+						GetMessageLog().AddEnvironment(transform.revert)
+						seen[transform.revert] = true
+					}
+				}
+			}
+		}
+		changes.Changes = remaining
+		if len(remaining) == 0 {
+			world.RemoveComponent(entity.ID, components.TerrainChanges)
+		}
+	}
+}
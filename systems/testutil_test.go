@@ -0,0 +1,65 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// testWorld is an open floor with the player standing on it, which system tests build
+// the rest of their scene on
+type testWorld struct {
+	world     *ecs.World
+	mapEntity *ecs.Entity
+	mapID     ecs.EntityID
+	gameMap   *components.MapComponent
+	playerID  ecs.EntityID
+	player    *components.PositionComponent
+}
+
+// newTestWorld creates a width by height floor with the player at (x, y)
+func newTestWorld(t *testing.T, width, height, x, y int) *testWorld {
+	t.Helper()
+	world := ecs.NewWorld()
+
+	gameMap := components.NewMapComponent(width, height)
+	mapEntity := world.CreateEntity()
+	world.AddComponent(mapEntity.ID, components.MapComponentID, gameMap)
+
+	w := &testWorld{world: world, mapEntity: mapEntity, mapID: mapEntity.ID, gameMap: gameMap}
+	w.fill(components.TileFloor)
+
+	w.playerID = w.place(x, y)
+	world.TagEntity(w.playerID, "player")
+	posComp, _ := world.GetComponent(w.playerID, components.Position)
+	w.player = posComp.(*components.PositionComponent)
+	return w
+}
+
+// fill covers the whole map with one tile
+func (w *testWorld) fill(tileType int) {
+	for y := 0; y < w.gameMap.Height; y++ {
+		for x := 0; x < w.gameMap.Width; x++ {
+			w.gameMap.Tiles[y][x] = tileType
+		}
+	}
+}
+
+// place creates an entity standing at (x, y) on the map
+func (w *testWorld) place(x, y int) ecs.EntityID {
+	entity := w.world.CreateEntity()
+	w.world.AddComponent(entity.ID, components.Position, &components.PositionComponent{X: x, Y: y})
+	w.world.AddComponent(entity.ID, components.MapContextID, components.NewMapContextComponent(w.mapID))
+	return entity.ID
+}
+
+// activate makes the map the player's current one, of the given type, for systems that
+// only act on the active map
+func (w *testWorld) activate(mapType string, level int) {
+	w.world.AddComponent(w.mapID, components.MapType, components.NewMapTypeComponent(mapType, level))
+	registry := NewMapRegistrySystem()
+	w.world.AddSystem(registry)
+	registry.Initialize(w.world)
+	registry.SetActiveMap(w.mapEntity)
+}
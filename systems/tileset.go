@@ -1,10 +1,12 @@
 package systems
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"math"
 	"os"
+	"unicode"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -22,14 +24,14 @@ func NewTileset(filename string, tileSize int) (*Tileset, error) {
 	// Open the file
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot open tileset %s: %w", filename, err)
 	}
 	defer file.Close()
 
 	// Decode the image
 	img, _, err := image.Decode(file)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot read tileset %s, the image may be corrupt: %w", filename, err)
 	}
 
 	// Convert to ebiten image
@@ -130,9 +132,69 @@ func (t *Tileset) DrawTile(target *ebiten.Image, char rune, x, y int, clr color.
 	t.DrawTileByID(target, TileID{X: tileX, Y: tileY}, x, y, clr, 0)
 }
 
-// DrawString draws a string of characters
-func (t *Tileset) DrawString(target *ebiten.Image, text string, x, y int, clr color.Color) {
+// DrawString draws text starting at a tile. A newline starts a new line back at x and a
+// tab moves on to the next multiple of TabWidth columns from x; other control
+// characters are skipped. Tiles falling outside the target are clipped. Returns the
+// number of lines the text takes, so callers can carry on below it.
+func (t *Tileset) DrawString(target *ebiten.Image, text string, x, y int, clr color.Color) int {
+	bounds := target.Bounds()
+	columns, rows := bounds.Dx()/t.TileSize, bounds.Dy()/t.TileSize
+	return layoutText(text, func(char rune, column, line int) {
+		if tileX, tileY := x+column, y+line; tileX >= 0 && tileX < columns && tileY >= 0 && tileY < rows {
+			t.DrawTile(target, char, tileX, tileY, clr)
+		}
+	})
+}
+
+// TabWidth is the number of columns between tab stops in DrawString
+const TabWidth = 4
+
+// layoutText places each printable character of text at a column and line counted
+// from where the text starts, handling newlines and tabs the way DrawString does.
+// Returns the number of lines, 0 for empty text.
+func layoutText(text string, place func(char rune, column, line int)) int {
+	if text == "" {
+		return 0
+	}
+	column, line := 0, 0
+	for _, char := range text {
+		switch {
+		case char == '\n':
+			column, line = 0, line+1
+		case char == '\t':
+			column = (column/TabWidth + 1) * TabWidth
+		case unicode.IsControl(char):
+			// Carriage returns and the like have nothing to draw
+		default:
+			place(char, column, line)
+			column++
+		}
+	}
+	return line + 1
+}
+
+// DrawSmallString draws a string shrunk by the given scale, starting at a pixel
+// position rather than a tile, for labels that sit between tiles
+func (t *Tileset) DrawSmallString(target *ebiten.Image, text string, px, py int, scale float64, clr color.Color) {
+	srcTileSize := 12 // The actual size in the PNG is 12x12
+	glyphSize := float64(t.TileSize) * scale
+
 	for i, char := range text {
-		t.DrawTile(target, char, x+i, y, clr)
+		tileX, tileY := t.GetTileCoords(char)
+		if tileX >= t.Width || tileY >= t.Height {
+			continue
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(glyphSize/float64(srcTileSize), glyphSize/float64(srcTileSize))
+		if clr != nil {
+			r, g, b, a := clr.RGBA()
+			op.ColorM.Scale(float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff, float64(a)/0xffff)
+		}
+		op.GeoM.Translate(float64(px)+float64(i)*glyphSize, float64(py))
+
+		sx, sy := tileX*srcTileSize, tileY*srcTileSize
+		rect := image.Rect(sx, sy, sx+srcTileSize, sy+srcTileSize)
+		target.DrawImage(t.Image.SubImage(rect).(*ebiten.Image), op)
 	}
 }
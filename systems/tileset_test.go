@@ -0,0 +1,47 @@
+package systems
+
+import (
+	"slices"
+	"testing"
+)
+
+// placedRune is a character and where layoutText put it
+type placedRune struct {
+	char         rune
+	column, line int
+}
+
+func TestLayoutText(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		want      []placedRune
+		wantLines int
+	}{
+		{"empty", "", nil, 0},
+		{"one line", "ab", []placedRune{{'a', 0, 0}, {'b', 1, 0}}, 1},
+		{"newline starts over at the left", "ab\nc", []placedRune{{'a', 0, 0}, {'b', 1, 0}, {'c', 0, 1}}, 2},
+		{"blank lines count", "a\n\nb", []placedRune{{'a', 0, 0}, {'b', 0, 2}}, 3},
+		{"a trailing newline adds an empty line", "a\n", []placedRune{{'a', 0, 0}}, 2},
+		{"tab moves to the next stop", "a\tb", []placedRune{{'a', 0, 0}, {'b', TabWidth, 0}}, 1},
+		{"tab on a stop moves to the one after", "abcd\te", []placedRune{{'a', 0, 0}, {'b', 1, 0}, {'c', 2, 0}, {'d', 3, 0}, {'e', 2 * TabWidth, 0}}, 1},
+		{"tabs after a newline count from the left", "abcde\n\tf", []placedRune{{'a', 0, 0}, {'b', 1, 0}, {'c', 2, 0}, {'d', 3, 0}, {'e', 4, 0}, {'f', TabWidth, 1}}, 2},
+		{"carriage returns are skipped", "a\r\nb", []placedRune{{'a', 0, 0}, {'b', 0, 1}}, 2},
+		{"other control characters take no column", "a\x00\x07\x1bb", []placedRune{{'a', 0, 0}, {'b', 1, 0}}, 1},
+		{"non-ASCII runes take one column each", "é→", []placedRune{{'é', 0, 0}, {'→', 1, 0}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var placed []placedRune
+			lines := layoutText(tt.text, func(char rune, column, line int) {
+				placed = append(placed, placedRune{char, column, line})
+			})
+			if !slices.Equal(placed, tt.want) {
+				t.Errorf("placed %v, want %v", placed, tt.want)
+			}
+			if lines != tt.wantLines {
+				t.Errorf("layoutText = %d lines, want %d", lines, tt.wantLines)
+			}
+		})
+	}
+}
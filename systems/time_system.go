@@ -0,0 +1,156 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// TimeOfDay is a phase of the day/night cycle on the surface
+type TimeOfDay struct {
+	Name       string
+	SightRange int        // Player sight range on the world map, 0 for unlimited
+	Tint       color.RGBA // Colour blended into the world map
+	TintAmount float64    // How strongly the tint is blended (0-1)
+	Light      float64    // Ambient light level, 1 for broad daylight
+}
+
+var (
+	timeDawn  = &TimeOfDay{Name: "Dawn", SightRange: 10, Tint: color.RGBA{255, 170, 120, 255}, TintAmount: 0.2, Light: 0.7}
+	timeDay   = &TimeOfDay{Name: "Day", Light: 1}
+	timeDusk  = &TimeOfDay{Name: "Dusk", SightRange: 10, Tint: color.RGBA{255, 130, 80, 255}, TintAmount: 0.25, Light: 0.7}
+	timeNight = &TimeOfDay{Name: "Night", SightRange: 5, Tint: color.RGBA{30, 40, 110, 255}, TintAmount: 0.5, Light: 0.4}
+)
+
+// TimeSystem counts the turns the player has taken and derives the time of day from them.
+// The counter is kept on a world state entity so other systems can read it.
+type TimeSystem struct {
+	turnsPerHour int // Turns that make up one in-game hour
+	startHour    int // Hour of the first day the game starts at
+	initialized  bool
+}
+
+// NewTimeSystem creates a new time system
+func NewTimeSystem() *TimeSystem {
+	return &TimeSystem{
+		turnsPerHour: 10,
+		startHour:    8,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *TimeSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		state := s.getWorldState(world)
+		previous := s.phaseAt(state.Turn)
+		state.Turn++
+
+		// Announce dusk and dawn while the player is outside
+		if phase := s.phaseAt(state.Turn); phase != previous && isOnWorldMap(world) {
+			switch phase {
+			case timeNight:
+				GetMessageLog().AddEnvironment("Night falls. You can't see far.")
+			case timeDawn:
+				GetMessageLog().AddEnvironment("The sky begins to lighten.")
+			}
+		}
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *TimeSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// Turn returns the number of turns completed so far
+func (s *TimeSystem) Turn(world *ecs.World) int {
+	return s.getWorldState(world).Turn
+}
+
+// Clock returns the current day (starting at 1) and hour (0-23)
+func (s *TimeSystem) Clock(world *ecs.World) (day, hour int) {
+	return s.clockAt(s.Turn(world))
+}
+
+// TimeOfDay returns the current phase of the day/night cycle
+func (s *TimeSystem) TimeOfDay(world *ecs.World) *TimeOfDay {
+	return s.phaseAt(s.Turn(world))
+}
+
+// SightRange applies the time of day's sight limit to a base sight range
+func (s *TimeSystem) SightRange(world *ecs.World, baseRange int) int {
+	phase := s.TimeOfDay(world)
+	if phase.SightRange == 0 || phase.SightRange >= baseRange {
+		return baseRange
+	}
+	return phase.SightRange
+}
+
+// FormatTime describes the current time, e.g. "Day 2, 21:00 (Night)"
+func (s *TimeSystem) FormatTime(world *ecs.World) string {
+	day, hour := s.Clock(world)
+	return fmt.Sprintf("Day %d, %02d:00 (%s)", day, hour, s.TimeOfDay(world).Name)
+}
+
+// clockAt converts a turn count into a day and hour
+func (s *TimeSystem) clockAt(turn int) (day, hour int) {
+	hours := s.startHour + turn/s.turnsPerHour
+	return hours/24 + 1, hours % 24
+}
+
+// phaseAt returns the phase of the day at a turn
+func (s *TimeSystem) phaseAt(turn int) *TimeOfDay {
+	_, hour := s.clockAt(turn)
+	switch {
+	case hour >= 5 && hour < 7:
+		return timeDawn
+	case hour >= 7 && hour < 18:
+		return timeDay
+	case hour >= 18 && hour < 20:
+		return timeDusk
+	default:
+		return timeNight
+	}
+}
+
+// getWorldState returns the world state component, creating its entity if needed
+func (s *TimeSystem) getWorldState(world *ecs.World) *components.WorldStateComponent {
+	for _, entity := range world.GetEntitiesWithTag("world_state") {
+		if comp, exists := world.GetComponent(entity.ID, components.WorldState); exists {
+			return comp.(*components.WorldStateComponent)
+		}
+	}
+
+	entity := world.CreateEntity()
+	world.TagEntity(entity.ID, "world_state")
+	state := components.NewWorldStateComponent()
+	world.AddComponent(entity.ID, components.WorldState, state)
+	return state
+}
+
+// isOnWorldMap returns whether the active map is the world map
+func isOnWorldMap(world *ecs.World) bool {
+	for _, system := range world.GetSystems() {
+		if mapRegistry, ok := system.(*MapRegistrySystem); ok {
+			activeMap := mapRegistry.GetActiveMap()
+			if activeMap == nil {
+				return false
+			}
+			if typeComp, exists := world.GetComponent(activeMap.ID, components.MapType); exists {
+				return typeComp.(*components.MapTypeComponent).MapType == "worldmap"
+			}
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// TorchSystem throws lit torches and burns them down. A torch lands on the tile it
+// was aimed at, or short of it against a wall, and lights the ground around it as a
+// light-source FOV of its own until it burns out and is gone.
+type TorchSystem struct {
+	throwRange  int // Furthest a torch can be thrown in tiles
+	initialized bool
+}
+
+// NewTorchSystem creates a new torch system
+func NewTorchSystem() *TorchSystem {
+	return &TorchSystem{
+		throwRange: 6,
+	}
+}
+
+// Initialize sets up event listeners
+func (s *TorchSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Torches burn down with the player's turns
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.tick(world)
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *TorchSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// Aim starts aiming a torch from the thrower's pack at a tile, closing the inventory
+// so the map can be seen. The torch is thrown once a tile is picked, which takes the
+// turn. Returns false if there is nothing to aim with.
+func (s *TorchSystem) Aim(world *ecs.World, throwerID, torchID ecs.EntityID) bool {
+	targeting := s.getTargetingSystem(world)
+	if targeting == nil || !world.HasComponent(torchID, components.Torch) {
+		return false
+	}
+
+	if !targeting.AimTile(world, s.throwRange, func(x, y int) {
+		if s.Throw(world, throwerID, torchID, x, y) {
+			world.EmitEvent(TurnCompletedEvent{
+				EntityID: throwerID,
+			})
+		}
+	}) {
+		return false
+	}
+
+	for _, system := range world.GetSystems() {
+		if renderSys, ok := system.(*RenderSystem); ok && renderSys.IsInventoryOpen() {
+			renderSys.ToggleInventoryDisplay()
+		}
+	}
+	GetMessageLog().AddSystem(fmt.Sprintf("Where do you throw the %s? Move to aim, Enter: throw, Esc: cancel.", getEntityName(world, torchID)))
+	return true
+}
+
+// Throw lights a torch from the thrower's inventory and throws it at a tile. It flies
+// along the line until something solid stops it and lands burning.
+func (s *TorchSystem) Throw(world *ecs.World, throwerID, torchID ecs.EntityID, x, y int) bool {
+	posComp, exists := world.GetComponent(throwerID, components.Position)
+	if !exists {
+		return false
+	}
+	pos := posComp.(*components.PositionComponent)
+
+	invComp, exists := world.GetComponent(throwerID, components.Inventory)
+	if !exists {
+		return false
+	}
+	inventory := invComp.(*components.InventoryComponent)
+	if !inventory.Contains(torchID) {
+		return false
+	}
+
+	mapID := getEntityMapID(world, throwerID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return false
+	}
+	mapComponent := mapComp.(*components.MapComponent)
+
+	landX, landY := pos.X, pos.Y
+	for _, point := range LinePoints(pos.X, pos.Y, x, y)[1:] {
+		if math.Hypot(float64(point.X-pos.X), float64(point.Y-pos.Y)) > float64(s.throwRange) || mapComponent.IsWall(point.X, point.Y) {
+			break
+		}
+		landX, landY = point.X, point.Y
+	}
+
+	if !s.Light(world, torchID, mapID, landX, landY) {
+		return false
+	}
+	inventory.RemoveItem(torchID)
+
+	torchName := getEntityName(world, torchID)
+	if landX == pos.X && landY == pos.Y {
+		GetMessageLog().Add(fmt.Sprintf("You light the %s and set it down at your feet.", torchName))
+	} else {
+		GetMessageLog().Add(fmt.Sprintf("You light the %s and throw it. It lands in a pool of light.", torchName))
+	}
+	return true
+}
+
+// Light sets a torch burning on a tile. While it burns it is a light rather than an
+// item, so it can't be picked back up.
+func (s *TorchSystem) Light(world *ecs.World, torchID, mapID ecs.EntityID, x, y int) bool {
+	torchComp, exists := world.GetComponent(torchID, components.Torch)
+	if !exists {
+		return false
+	}
+	torch := torchComp.(*components.TorchComponent)
+
+	torch.Lit = true
+	world.AddComponent(torchID, components.Position, &components.PositionComponent{X: x, Y: y})
+	world.AddComponent(torchID, components.MapContextID, components.NewMapContextComponent(mapID))
+	world.AddComponent(torchID, components.Renderable, components.NewRenderableComponent('|', color.RGBA{255, 176, 64, 255}))
+	world.AddComponent(torchID, components.FOV, components.NewLightSourceFOVComponent(torch.LightRange, torch.LightRange))
+	return true
+}
+
+// tick burns down every lit torch and puts out the ones that run out
+func (s *TorchSystem) tick(world *ecs.World) {
+	var burntOut []ecs.EntityID
+	for _, entity := range world.GetEntitiesWithComponent(components.Torch) {
+		torchComp, _ := world.GetComponent(entity.ID, components.Torch)
+		torch := torchComp.(*components.TorchComponent)
+		if !torch.Lit {
+			continue
+		}
+
+		torch.BurnTurns--
+		if torch.BurnTurns <= 0 {
+			burntOut = append(burntOut, entity.ID)
+		}
+	}
+
+	for _, torchID := range burntOut {
+		if s.onPlayerMap(world, torchID) {
+			GetMessageLog().AddEnvironment(fmt.Sprintf("The %s gutters and goes out.", getEntityName(world, torchID)))
+		}
+		world.RemoveEntity(torchID)
+	}
+}
+
+// onPlayerMap returns whether a torch is on the player's map
+func (s *TorchSystem) onPlayerMap(world *ecs.World, torchID ecs.EntityID) bool {
+	playerEntities := world.GetEntitiesWithTag("player")
+	return len(playerEntities) > 0 && getEntityMapID(world, playerEntities[0].ID) == getEntityMapID(world, torchID)
+}
+
+// getTargetingSystem returns the targeting system torches are aimed with
+func (s *TorchSystem) getTargetingSystem(world *ecs.World) *TargetingSystem {
+	for _, system := range world.GetSystems() {
+		if targeting, ok := system.(*TargetingSystem); ok {
+			return targeting
+		}
+	}
+	return nil
+}
+
+// torchLightAt returns whether a tile is lit by a burning torch on a map: within
+// the torch's light range and with nothing solid in between
+func torchLightAt(world *ecs.World, mapID ecs.EntityID, mapComp *components.MapComponent, x, y int) bool {
+	for _, entity := range world.GetEntitiesWithComponent(components.Torch) {
+		torchComp, _ := world.GetComponent(entity.ID, components.Torch)
+		torch := torchComp.(*components.TorchComponent)
+		if !torch.Lit || getEntityMapID(world, entity.ID) != mapID {
+			continue
+		}
+		posComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		pos := posComp.(*components.PositionComponent)
+		if math.Hypot(float64(x-pos.X), float64(y-pos.Y)) <= float64(torch.LightRange) && HasLineOfSight(mapComp, pos.X, pos.Y, x, y) {
+			return true
+		}
+	}
+	return false
+}
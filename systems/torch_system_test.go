@@ -0,0 +1,151 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// torchFixture is a dark corridor floor with the player at its west end holding a
+// torch, and a wall across the corridor at x 12
+type torchFixture struct {
+	*testWorld
+	torches *TorchSystem
+	torchID ecs.EntityID
+	pack    *components.InventoryComponent
+}
+
+func newTorchFixture(t *testing.T) *torchFixture {
+	t.Helper()
+	w := newTestWorld(t, 20, 9, 2, 4)
+	for y := 0; y < w.gameMap.Height; y++ {
+		w.gameMap.Tiles[y][12] = components.TileWall
+	}
+	w.world.AddComponent(w.playerID, components.FOV, components.NewFOVComponent(1))
+	pack := components.NewInventoryComponent(10)
+	w.world.AddComponent(w.playerID, components.Inventory, pack)
+
+	torch := w.world.CreateEntity()
+	w.world.TagEntity(torch.ID, "item")
+	w.world.AddComponent(torch.ID, components.Name, components.NewNameComponent("Torch"))
+	w.world.AddComponent(torch.ID, components.Torch, components.NewTorchComponent(3, 2))
+	pack.AddItem(torch.ID)
+
+	w.activate("dungeon", 1)
+	torches := NewTorchSystem()
+	w.world.AddSystem(torches)
+	torches.Initialize(w.world)
+
+	return &torchFixture{testWorld: w, torches: torches, torchID: torch.ID, pack: pack}
+}
+
+// torchPosition returns where the torch lies on the map
+func (f *torchFixture) torchPosition(t *testing.T) (int, int) {
+	t.Helper()
+	posComp, exists := f.world.GetComponent(f.torchID, components.Position)
+	if !exists {
+		t.Fatal("the torch isn't on the map")
+	}
+	pos := posComp.(*components.PositionComponent)
+	return pos.X, pos.Y
+}
+
+func TestTorchThrowLandsOnTarget(t *testing.T) {
+	f := newTorchFixture(t)
+	if !f.torches.Throw(f.world, f.playerID, f.torchID, 6, 4) {
+		t.Fatal("Throw failed")
+	}
+	if x, y := f.torchPosition(t); x != 6 || y != 4 {
+		t.Errorf("torch landed at (%d,%d), want (6,4)", x, y)
+	}
+	if f.pack.Contains(f.torchID) {
+		t.Error("the thrown torch is still in the pack")
+	}
+	if getEntityMapID(f.world, f.torchID) != f.mapID {
+		t.Error("the thrown torch isn't on the thrower's map")
+	}
+	fovComp, exists := f.world.GetComponent(f.torchID, components.FOV)
+	if !exists || !fovComp.(*components.FOVComponent).LightSource {
+		t.Error("the lit torch casts no light")
+	}
+}
+
+func TestTorchThrowStopsShortOfWalls(t *testing.T) {
+	f := newTorchFixture(t)
+	f.player.X = 9
+
+	if !f.torches.Throw(f.world, f.playerID, f.torchID, 14, 4) {
+		t.Fatal("Throw failed")
+	}
+	if x, y := f.torchPosition(t); x != 11 || y != 4 {
+		t.Errorf("torch landed at (%d,%d), want (11,4) against the wall", x, y)
+	}
+}
+
+func TestTorchLightsDistantGround(t *testing.T) {
+	f := newTorchFixture(t)
+	fov := NewFOVSystem()
+	f.world.AddSystem(fov)
+
+	fov.Update(f.world, 0)
+	if f.gameMap.Explored[4][8] {
+		t.Fatal("ground beyond the player's sight was explored before the throw")
+	}
+
+	f.torches.Throw(f.world, f.playerID, f.torchID, 8, 4)
+	fov.Update(f.world, 0)
+	for _, tile := range []Point{{8, 4}, {7, 4}, {9, 5}} {
+		if !f.gameMap.Visible[tile.Y][tile.X] || !f.gameMap.Explored[tile.Y][tile.X] {
+			t.Errorf("tile (%d,%d) in the torchlight isn't lit", tile.X, tile.Y)
+		}
+	}
+	if f.gameMap.Visible[4][13] {
+		t.Error("the torch lit the far side of the wall")
+	}
+
+	// Anything standing in the light is easy to see
+	monsterID := f.place(9, 4)
+	if light := AmbientLight(f.world, monsterID); light != 1 {
+		t.Errorf("AmbientLight() in the torchlight = %v, want 1", light)
+	}
+	if light := AmbientLight(f.world, f.playerID); light != dungeonLight {
+		t.Errorf("AmbientLight() out of the torchlight = %v, want %v", light, dungeonLight)
+	}
+}
+
+func TestTorchBurnsOut(t *testing.T) {
+	f := newTorchFixture(t)
+	f.torches.Throw(f.world, f.playerID, f.torchID, 6, 4)
+
+	// Lit torches can't be picked back up
+	inventory := NewInventorySystem()
+	inventory.checkItemPickups(f.world, f.playerID, &components.PositionComponent{X: 6, Y: 4})
+	if f.pack.Contains(f.torchID) {
+		t.Error("the player picked up a burning torch")
+	}
+
+	for turn := 1; turn < 3; turn++ {
+		f.world.EmitEvent(TurnCompletedEvent{EntityID: f.playerID})
+		if f.world.GetEntity(f.torchID) == nil {
+			t.Fatalf("the torch burnt out after %d turns, want 3", turn)
+		}
+	}
+	f.world.EmitEvent(TurnCompletedEvent{EntityID: f.playerID})
+	if f.world.GetEntity(f.torchID) != nil {
+		t.Error("the torch still burns after 3 turns")
+	}
+	if len(f.world.GetEntitiesWithComponent(components.FOV)) != 1 {
+		t.Error("the burnt-out torch left its light behind")
+	}
+}
+
+func TestUnlitTorchDoesNotBurn(t *testing.T) {
+	f := newTorchFixture(t)
+	for turn := 0; turn < 5; turn++ {
+		f.world.EmitEvent(TurnCompletedEvent{EntityID: f.playerID})
+	}
+	if f.world.GetEntity(f.torchID) == nil || !f.pack.Contains(f.torchID) {
+		t.Error("a torch burnt away in the pack without being lit")
+	}
+}
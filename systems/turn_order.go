@@ -0,0 +1,169 @@
+package systems
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/config"
+	"ebiten-rogue/ecs"
+)
+
+// TurnOrderLookahead is how many turns ahead the turn order HUD projects
+const TurnOrderLookahead = 6
+
+// turnOrderMaxRows caps how many actors the HUD lists, the soonest first
+const turnOrderMaxRows = 8
+
+// turnOrderEntry is one actor in the turn order HUD
+type turnOrderEntry struct {
+	entityID ecs.EntityID
+	name     string
+	nextTurn int    // Turns until it next acts, 0 for the coming turn, -1 if not within the lookahead
+	pattern  string // One character per projected turn: '#' acts, '.' waits or recovers
+	distance int    // From the player, to break ties
+}
+
+// projectTurns plays an actor's action points forward the way AITurnProcessorSystem
+// spends them, assuming it keeps coming: each turn it acts if it can pay the cost,
+// waits if it can pay for that, and otherwise recovers
+func projectTurns(stats *components.StatsComponent, cost, turns int) (int, string) {
+	points := stats.ActionPoints
+	nextTurn := -1
+	pattern := make([]byte, turns)
+	for turn := range pattern {
+		switch {
+		case points >= cost:
+			points -= cost
+			pattern[turn] = '#'
+			if nextTurn < 0 {
+				nextTurn = turn
+			}
+		case points >= WaitCost:
+			points -= WaitCost
+			pattern[turn] = '.'
+		default:
+			points = min(points+stats.Recovery, stats.MaxActionPoints)
+			pattern[turn] = '.'
+		}
+	}
+	return nextTurn, string(pattern)
+}
+
+// turnOrder lists the player and the actors they can see, in the order they will next
+// act. It is worked out from the current action points each time, so it keeps up as
+// points accrue and actors die.
+func turnOrder(world *ecs.World, playerID ecs.EntityID) []turnOrderEntry {
+	playerPos, hasPos := world.GetComponent(playerID, components.Position)
+	if !hasPos {
+		return nil
+	}
+	origin := playerPos.(*components.PositionComponent)
+	mapID := getEntityMapID(world, playerID)
+	mapComp, exists := world.GetComponent(mapID, components.MapComponentID)
+	if !exists {
+		return nil
+	}
+	gameMap := mapComp.(*components.MapComponent)
+
+	// The player acts every turn, before anything else
+	entries := []turnOrderEntry{{entityID: playerID, name: "You", pattern: strings.Repeat("#", TurnOrderLookahead)}}
+
+	world.Query(components.AI, components.Stats, components.Position).Each(func(entity *ecs.Entity) {
+		if getEntityMapID(world, entity.ID) != mapID {
+			return
+		}
+		posComp, _ := world.GetComponent(entity.ID, components.Position)
+		pos := posComp.(*components.PositionComponent)
+		if !gameMap.InBounds(pos.X, pos.Y) || !gameMap.Visible[pos.Y][pos.X] {
+			return
+		}
+		statsComp, _ := world.GetComponent(entity.ID, components.Stats)
+		distance := max(abs(pos.X-origin.X), abs(pos.Y-origin.Y))
+		cost := MoveCost
+		if distance == 1 && entity.HasTag("enemy") {
+			cost = AttackCost
+		}
+		nextTurn, pattern := projectTurns(statsComp.(*components.StatsComponent), cost, TurnOrderLookahead)
+		entries = append(entries, turnOrderEntry{
+			entityID: entity.ID,
+			name:     getEntityName(world, entity.ID),
+			nextTurn: nextTurn,
+			pattern:  pattern,
+			distance: distance,
+		})
+	})
+
+	// Soonest first; the player wins ties as it moves first, then the nearest
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.nextTurn < 0) != (b.nextTurn < 0) {
+			return b.nextTurn < 0
+		}
+		if a.nextTurn != b.nextTurn {
+			return a.nextTurn < b.nextTurn
+		}
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return a.entityID < b.entityID
+	})
+	if len(entries) > turnOrderMaxRows {
+		entries = entries[:turnOrderMaxRows]
+	}
+	return entries
+}
+
+// SetTurnOrderHUD shows or hides the turn order HUD
+func (s *RenderSystem) SetTurnOrderHUD(show bool) {
+	s.showTurnOrder = show
+}
+
+// ToggleTurnOrderHUD flips the turn order HUD on or off
+func (s *RenderSystem) ToggleTurnOrderHUD() {
+	s.showTurnOrder = !s.showTurnOrder
+}
+
+// drawTurnOrder draws the turn order HUD in the top right corner of the map: each
+// actor in sight with its next few turns, '#' for turns it acts
+func (s *RenderSystem) drawTurnOrder(world *ecs.World, screen *ebiten.Image) {
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	entries := turnOrder(world, playerEntities[0].ID)
+	if len(entries) == 0 {
+		return
+	}
+
+	const nameWidth = 12
+	width := nameWidth + TurnOrderLookahead + 5
+	left := config.GameScreenWidth - width
+	for y := 0; y < len(entries)+1; y++ {
+		for x := left; x < config.GameScreenWidth; x++ {
+			s.tileset.DrawTile(screen, ' ', x, y, s.palette.Background)
+		}
+	}
+	s.tileset.DrawString(screen, "TURN ORDER", left+1, 0, s.palette.PanelHeading)
+
+	for i, entry := range entries {
+		y := i + 1
+		if rendComp, exists := world.GetComponent(entry.entityID, components.Renderable); exists {
+			look := rendComp.(*components.RenderableComponent)
+			tileID, _ := s.entityTile(world, entry.entityID, look.UseTilePos, look.TileX, look.TileY, look.Char)
+			s.tileset.DrawTileByID(screen, tileID, left+1, y, look.FG, 0)
+		}
+		name := entry.name
+		if len(name) > nameWidth {
+			name = name[:nameWidth]
+		}
+		textColor := s.palette.PanelText
+		if entry.nextTurn != 0 {
+			textColor = s.palette.PanelDim
+		}
+		s.tileset.DrawString(screen, fmt.Sprintf("%-*s %s", nameWidth, name, entry.pattern), left+3, y, textColor)
+	}
+}
@@ -0,0 +1,164 @@
+package systems
+
+import (
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// TutorialHint is a message shown once when the player first reaches a spot on the
+// tutorial floor. Floors are generated, so spots are described by what is there rather
+// than by coordinates: standing near an entity with a tag, or on or beside a kind of
+// tile. A hint with neither fires as soon as the player arrives.
+type TutorialHint struct {
+	ID      string `json:"id"`      // Unique name, used to show the hint only once
+	Message string `json:"message"` // Text added to the message log
+	Near    string `json:"near"`    // Entity tag the player must be close to, such as "container"
+	Tile    string `json:"tile"`    // Tile the player must be on or close to, such as "stairs_down"
+	Radius  int    `json:"radius"`  // How close counts, in tiles (0 = standing on it)
+}
+
+// tutorialTiles maps the tile names hints can use to tile types
+var tutorialTiles = map[string]int{
+	"door":        components.TileDoor,
+	"stairs_down": components.TileStairsDown,
+	"stairs_up":   components.TileStairsUp,
+	"water":       components.TileWater,
+	"lava":        components.TileLava,
+	"grass":       components.TileGrass,
+}
+
+// TutorialSystem shows contextual hints on the first floor of a run. The hints come
+// from the starting floor's theme and each is shown once. The tutorial ends for good
+// when the player leaves that floor, or can be switched off entirely.
+type TutorialSystem struct {
+	enabled  bool
+	mapID    ecs.EntityID    // Floor the tutorial runs on, 0 once it's over
+	hints    []TutorialHint  // Hints for that floor, in priority order
+	shown    map[string]bool // Hints already shown, by ID
+	lastPos  Point           // Player position when hints were last checked
+	hasMoved bool            // Whether lastPos holds a real position yet
+}
+
+// NewTutorialSystem creates a new tutorial system
+func NewTutorialSystem() *TutorialSystem {
+	return &TutorialSystem{
+		enabled: true,
+		shown:   make(map[string]bool),
+	}
+}
+
+// SetEnabled turns the tutorial on or off
+func (s *TutorialSystem) SetEnabled(enabled bool) {
+	s.enabled = enabled
+}
+
+// IsEnabled returns whether the tutorial is on
+func (s *TutorialSystem) IsEnabled() bool {
+	return s.enabled
+}
+
+// Start runs the tutorial on a floor with the given hints
+func (s *TutorialSystem) Start(mapID ecs.EntityID, hints []TutorialHint) {
+	s.mapID = mapID
+	s.hints = hints
+	s.hasMoved = false
+}
+
+// Update checks the hints whenever the player has moved
+func (s *TutorialSystem) Update(world *ecs.World, dt float64) {
+	if !s.enabled || s.mapID == 0 {
+		return
+	}
+
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	playerID := playerEntities[0].ID
+
+	// Leaving the first floor ends the tutorial
+	if getEntityMapID(world, playerID) != s.mapID {
+		s.mapID = 0
+		s.hints = nil
+		return
+	}
+
+	posComp, exists := world.GetComponent(playerID, components.Position)
+	if !exists {
+		return
+	}
+	pos := posComp.(*components.PositionComponent)
+	current := Point{X: pos.X, Y: pos.Y}
+	if s.hasMoved && current == s.lastPos {
+		return
+	}
+
+	mapComp, exists := world.GetComponent(s.mapID, components.MapComponentID)
+	if !exists {
+		return
+	}
+	mapData := mapComp.(*components.MapComponent)
+
+	// Show one hint at a time and look again next frame, so several hints for the
+	// same spot arrive in order rather than all at once
+	for _, hint := range s.hints {
+		if s.shown[hint.ID] || !s.triggered(world, mapData, hint, current) {
+			continue
+		}
+		s.shown[hint.ID] = true
+		GetMessageLog().AddSystem(hint.Message)
+		world.EmitEvent(TutorialHintEvent{HintID: hint.ID, Message: hint.Message})
+		return
+	}
+	s.lastPos = current
+	s.hasMoved = true
+}
+
+// triggered checks whether the player at pos has reached a hint's spot
+func (s *TutorialSystem) triggered(world *ecs.World, mapData *components.MapComponent, hint TutorialHint, pos Point) bool {
+	if hint.Tile != "" {
+		tileType, known := tutorialTiles[hint.Tile]
+		if !known || !tileWithin(mapData, tileType, pos, hint.Radius) {
+			return false
+		}
+	}
+	if hint.Near != "" && !s.taggedWithin(world, mapData, hint.Near, pos, hint.Radius) {
+		return false
+	}
+	return true
+}
+
+// tileWithin checks for a tile of the given type within radius of pos
+func tileWithin(mapData *components.MapComponent, tileType int, pos Point, radius int) bool {
+	for y := pos.Y - radius; y <= pos.Y+radius; y++ {
+		for x := pos.X - radius; x <= pos.X+radius; x++ {
+			if x >= 0 && x < mapData.Width && y >= 0 && y < mapData.Height && mapData.Tiles[y][x] == tileType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// taggedWithin checks for a visible entity with the tag within radius of pos on the
+// tutorial floor
+func (s *TutorialSystem) taggedWithin(world *ecs.World, mapData *components.MapComponent, tag string, pos Point, radius int) bool {
+	for _, entity := range world.GetEntitiesWithTag(tag) {
+		if getEntityMapID(world, entity.ID) != s.mapID {
+			continue
+		}
+		posComp, exists := world.GetComponent(entity.ID, components.Position)
+		if !exists {
+			continue
+		}
+		entityPos := posComp.(*components.PositionComponent)
+		if max(abs(entityPos.X-pos.X), abs(entityPos.Y-pos.Y)) > radius {
+			continue
+		}
+		if entityPos.Y >= 0 && entityPos.Y < mapData.Height && entityPos.X >= 0 && entityPos.X < mapData.Width &&
+			mapData.Visible[entityPos.Y][entityPos.X] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package systems
+
+import (
+	"fmt"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// Two-handed and dual-wield tuning
+const (
+	DualWieldPenalty   = 2 // Taken off the attack of every blow while a weapon is in each hand
+	TwoHandedDamageDie = 6 // Size of the extra damage die a two-handed weapon rolls
+)
+
+// weaponGrip is how an entity holds its weapons, which changes its attack rolls
+type weaponGrip struct {
+	TwoHanded bool // A two-handed weapon is in the main hand
+	DualWield bool // A one-handed weapon is in each hand
+}
+
+// getWeaponGrip reads how an entity holds its weapons from its equipment. Entities
+// without equipment, like most monsters, fight with a plain grip.
+func getWeaponGrip(world *ecs.World, entityID ecs.EntityID) weaponGrip {
+	equipComp, exists := world.GetComponent(entityID, components.Equipment)
+	if !exists {
+		return weaponGrip{}
+	}
+	equipment := equipComp.(*components.EquipmentComponent)
+
+	mainComp, hasMain := world.GetComponent(equipment.GetEquippedItem(components.SlotMainHand), components.Item)
+	if !hasMain {
+		return weaponGrip{}
+	}
+	main := mainComp.(*components.ItemComponent)
+	if main.ItemType != "weapon" {
+		return weaponGrip{}
+	}
+	if main.TwoHanded {
+		return weaponGrip{TwoHanded: true}
+	}
+
+	offComp, hasOff := world.GetComponent(equipment.GetEquippedItem(components.SlotOffHand), components.Item)
+	return weaponGrip{DualWield: hasOff && offComp.(*components.ItemComponent).ItemType == "weapon"}
+}
+
+// offhandStrike follows a dual wielder's blow with one from the off hand weapon, as
+// long as the defender is still standing
+func (s *CombatSystem) offhandStrike(world *ecs.World, attackerID, defenderID ecs.EntityID) {
+	if !getWeaponGrip(world, attackerID).DualWield || world.GetEntity(defenderID) == nil {
+		return
+	}
+	if statsComp, exists := world.GetComponent(defenderID, components.Stats); !exists || statsComp.(*components.StatsComponent).Health <= 0 {
+		return
+	}
+	GetMessageLog().AddCombat(fmt.Sprintf("%s follows up with the off hand!", getEntityName(world, attackerID)))
+	s.ProcessCombat(world, attackerID, defenderID)
+}
@@ -0,0 +1,257 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"time"
+
+	"ebiten-rogue/components"
+	"ebiten-rogue/ecs"
+)
+
+// WeatherType describes a kind of weather and the biome it occurs in
+type WeatherType struct {
+	ID         string
+	Name       string
+	Biome      int        // World map tile type the weather occurs on
+	SightRange int        // Player sight range while caught in the weather
+	Tint       color.RGBA // Colour blended into the world map while active
+	Chance     float64    // Chance per roll that the weather starts in a region
+	MinTurns   int        // Shortest duration in turns
+	MaxTurns   int        // Longest duration in turns
+}
+
+// weatherTypes lists the weather that can roll on the world map
+var weatherTypes = []WeatherType{
+	{
+		ID: "sandstorm", Name: "Sandstorm", Biome: components.TileDesert,
+		SightRange: 2, Tint: color.RGBA{200, 160, 90, 255},
+		Chance: 0.35, MinTurns: 20, MaxTurns: 60,
+	},
+	{
+		ID: "fog", Name: "Fog", Biome: components.TileDarkForest,
+		SightRange: 4, Tint: color.RGBA{150, 150, 170, 255},
+		Chance: 0.4, MinTurns: 30, MaxTurns: 80,
+	},
+	{
+		ID: "blizzard", Name: "Blizzard", Biome: components.TileMountains,
+		SightRange: 3, Tint: color.RGBA{200, 220, 255, 255},
+		Chance: 0.25, MinTurns: 15, MaxTurns: 40,
+	},
+	{
+		ID: "ashfall", Name: "Ash Fall", Biome: components.TileWasteland,
+		SightRange: 6, Tint: color.RGBA{130, 120, 110, 255},
+		Chance: 0.15, MinTurns: 20, MaxTurns: 50,
+	},
+}
+
+// regionWeather is the weather rolled for one region of the world map
+type regionWeather struct {
+	weather   *WeatherType // nil for clear skies
+	turnsLeft int          // Turns until the weather is rolled again
+}
+
+// WeatherSystem rolls weather per region of the world map and limits the
+// player's sight while they travel through affected biomes. The limit is a
+// conditional effect on the player's FOV that holds while they are caught in the
+// weather, so it comes off exactly when the weather clears.
+type WeatherSystem struct {
+	rng         *rand.Rand
+	enabled     bool
+	regionSize  int // Width and height of a weather region in tiles
+	regions     map[Point]*regionWeather
+	current     *WeatherType // Weather affecting the player right now, nil if clear
+	initialized bool
+}
+
+// NewWeatherSystem creates a new weather system
+func NewWeatherSystem() *WeatherSystem {
+	return &WeatherSystem{
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		enabled:    true,
+		regionSize: 20,
+		regions:    make(map[Point]*regionWeather),
+	}
+}
+
+// SetEnabled sets whether weather rolls at all; with it off the skies stay clear
+func (s *WeatherSystem) SetEnabled(enabled bool) {
+	s.enabled = enabled
+}
+
+// SetRNG sets the random source used to roll weather, normally the run's weather stream
+func (s *WeatherSystem) SetRNG(rng *rand.Rand) {
+	s.rng = rng
+}
+
+// Reset clears all rolled weather, e.g. when a new world is generated
+func (s *WeatherSystem) Reset() {
+	s.regions = make(map[Point]*regionWeather)
+	s.current = nil
+}
+
+// Initialize sets up event listeners
+func (s *WeatherSystem) Initialize(world *ecs.World) {
+	if s.initialized {
+		return
+	}
+
+	// Weather advances with the player's turns
+	world.GetEventManager().Subscribe("turn_completed", func(event ecs.Event) {
+		s.advance(world)
+	})
+
+	s.initialized = true
+}
+
+// Update implements the System interface
+func (s *WeatherSystem) Update(world *ecs.World, dt float64) {
+	if !s.initialized {
+		s.Initialize(world)
+	}
+}
+
+// CurrentWeather returns the weather affecting the player, or nil if skies are clear
+func (s *WeatherSystem) CurrentWeather() *WeatherType {
+	return s.current
+}
+
+// advance counts down region weather and re-evaluates the player's weather
+func (s *WeatherSystem) advance(world *ecs.World) {
+	mapComp, playerPos := s.getWorldMapAndPlayer(world)
+	if mapComp == nil || !s.enabled {
+		// Weather only exists on the surface
+		previous := s.current
+		s.current = nil
+		s.updateSightEffect(world, previous)
+		return
+	}
+
+	for region, rolled := range s.regions {
+		rolled.turnsLeft--
+		if rolled.turnsLeft <= 0 {
+			delete(s.regions, region)
+		}
+	}
+
+	previous := s.current
+	s.current = nil
+
+	rolled := s.getRegionWeather(mapComp, playerPos.X, playerPos.Y)
+	if rolled.weather != nil && mapComp.Tiles[playerPos.Y][playerPos.X] == rolled.weather.Biome {
+		s.current = rolled.weather
+	}
+
+	if s.current != previous {
+		s.updateSightEffect(world, previous)
+		if s.current != nil {
+			GetMessageLog().AddEnvironment(fmt.Sprintf("You are caught in a %s.", s.current.Name))
+		} else if previous != nil {
+			GetMessageLog().AddEnvironment(fmt.Sprintf("The %s clears.", previous.Name))
+		}
+	}
+}
+
+// updateSightEffect swaps the player's weather sight limit for the current weather's.
+// The old effect stops holding once the weather has changed, so evaluating it undoes
+// it before it is taken off.
+func (s *WeatherSystem) updateSightEffect(world *ecs.World, previous *WeatherType) {
+	if s.current == previous {
+		return
+	}
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return
+	}
+	playerID := playerEntities[0].ID
+	var effectsSystem *EffectsSystem
+	for _, system := range world.GetSystems() {
+		if found, ok := system.(*EffectsSystem); ok {
+			effectsSystem = found
+			break
+		}
+	}
+	if effectsSystem == nil {
+		return
+	}
+
+	if previous != nil {
+		effectsSystem.EvaluateConditions(world, playerID)
+		effectsSystem.RemoveEntityEffects(world, playerID, []components.GameEffect{weatherSightEffect(previous)})
+	}
+	if s.current != nil {
+		effectsSystem.ApplyEntityEffects(world, playerID, []components.GameEffect{weatherSightEffect(s.current)})
+		effectsSystem.EvaluateConditions(world, playerID)
+	}
+}
+
+// weatherSightEffect caps the player's sight at the weather's range for as long as
+// they are caught in it
+func weatherSightEffect(weather *WeatherType) components.GameEffect {
+	effect := components.NewGameEffect(components.EffectTypeConditional, components.EffectOpSet,
+		float64(weather.SightRange), 0, 0, "FOV", "SightLimit")
+	effect.Condition.Weather = weather.ID
+	return effect
+}
+
+// getRegionWeather returns the weather of the region containing a tile, rolling it if needed
+func (s *WeatherSystem) getRegionWeather(mapComp *components.MapComponent, x, y int) *regionWeather {
+	region := Point{x / s.regionSize, y / s.regionSize}
+	if rolled, exists := s.regions[region]; exists {
+		return rolled
+	}
+
+	// The region's weather follows the biome at its centre
+	centerX := min(region.X*s.regionSize+s.regionSize/2, mapComp.Width-1)
+	centerY := min(region.Y*s.regionSize+s.regionSize/2, mapComp.Height-1)
+	biome := mapComp.Tiles[centerY][centerX]
+
+	rolled := &regionWeather{turnsLeft: 30}
+	for i := range weatherTypes {
+		weather := &weatherTypes[i]
+		if weather.Biome != biome || s.rng.Float64() >= weather.Chance {
+			continue
+		}
+		rolled.weather = weather
+		rolled.turnsLeft = weather.MinTurns + s.rng.Intn(weather.MaxTurns-weather.MinTurns+1)
+		break
+	}
+
+	s.regions[region] = rolled
+	return rolled
+}
+
+// getWorldMapAndPlayer returns the world map and player position if the world map is active
+func (s *WeatherSystem) getWorldMapAndPlayer(world *ecs.World) (*components.MapComponent, *components.PositionComponent) {
+	var activeMap *ecs.Entity
+	for _, system := range world.GetSystems() {
+		if mapRegistry, ok := system.(*MapRegistrySystem); ok {
+			activeMap = mapRegistry.GetActiveMap()
+			break
+		}
+	}
+	if activeMap == nil {
+		return nil, nil
+	}
+
+	typeComp, exists := world.GetComponent(activeMap.ID, components.MapType)
+	if !exists || typeComp.(*components.MapTypeComponent).MapType != "worldmap" {
+		return nil, nil
+	}
+	mapComp, exists := world.GetComponent(activeMap.ID, components.MapComponentID)
+	if !exists {
+		return nil, nil
+	}
+
+	playerEntities := world.GetEntitiesWithTag("player")
+	if len(playerEntities) == 0 {
+		return nil, nil
+	}
+	posComp, exists := world.GetComponent(playerEntities[0].ID, components.Position)
+	if !exists {
+		return nil, nil
+	}
+
+	return mapComp.(*components.MapComponent), posComp.(*components.PositionComponent)
+}
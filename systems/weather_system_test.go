@@ -0,0 +1,108 @@
+package systems
+
+import (
+	"testing"
+
+	"ebiten-rogue/components"
+)
+
+// weatherFixture is a desert world map with a sandstorm rolled over the region the
+// player stands in
+type weatherFixture struct {
+	*testWorld
+	weather *WeatherSystem
+	fovSys  *FOVSystem
+	fov     *components.FOVComponent
+}
+
+func newWeatherFixture(t *testing.T) *weatherFixture {
+	t.Helper()
+	w := newTestWorld(t, 20, 20, 10, 10)
+	w.fill(components.TileDesert)
+	w.world.AddComponent(w.playerID, components.Stats, &components.StatsComponent{Health: 10, MaxHealth: 10})
+	fov := components.NewFOVComponent(8)
+	w.world.AddComponent(w.playerID, components.FOV, fov)
+	w.activate("worldmap", 0)
+
+	effects := NewEffectsSystem()
+	w.world.AddSystem(effects)
+	effects.Initialize(w.world)
+
+	weather := NewWeatherSystem()
+	w.world.AddSystem(weather)
+	weather.Initialize(w.world)
+	weather.regions[Point{0, 0}] = &regionWeather{weather: &weatherTypes[0], turnsLeft: 100}
+
+	fovSys := NewFOVSystem()
+	w.world.AddSystem(fovSys)
+
+	return &weatherFixture{testWorld: w, weather: weather, fovSys: fovSys, fov: fov}
+}
+
+func TestWeatherLimitsSight(t *testing.T) {
+	f := newWeatherFixture(t)
+	sandstorm := &weatherTypes[0]
+
+	f.world.EmitEvent(TurnCompletedEvent{})
+	if f.weather.CurrentWeather() != sandstorm {
+		t.Fatalf("weather = %v, want the sandstorm", f.weather.CurrentWeather())
+	}
+	if f.fov.SightLimit != sandstorm.SightRange {
+		t.Errorf("sight limit in a sandstorm = %d, want %d", f.fov.SightLimit, sandstorm.SightRange)
+	}
+	if f.fov.Range != 8 {
+		t.Errorf("sight range = %d, want the weather to leave it at 8", f.fov.Range)
+	}
+
+	f.fovSys.Update(f.world, 0)
+	if !f.gameMap.Visible[10][10+sandstorm.SightRange-1] {
+		t.Error("a tile within the sandstorm's sight range isn't visible")
+	}
+	if f.gameMap.Visible[10][10+sandstorm.SightRange+2] {
+		t.Error("a tile beyond the sandstorm's sight range is visible")
+	}
+
+	// Another turn in the same storm doesn't stack the limit
+	f.world.EmitEvent(TurnCompletedEvent{})
+	if f.fov.SightLimit != sandstorm.SightRange {
+		t.Errorf("sight limit after a second turn = %d, want %d", f.fov.SightLimit, sandstorm.SightRange)
+	}
+}
+
+func TestWeatherClearingRestoresSight(t *testing.T) {
+	f := newWeatherFixture(t)
+	f.world.EmitEvent(TurnCompletedEvent{})
+
+	f.weather.regions[Point{0, 0}].weather = nil
+	f.world.EmitEvent(TurnCompletedEvent{})
+	if f.weather.CurrentWeather() != nil {
+		t.Fatalf("weather = %s, want clear skies", f.weather.CurrentWeather().Name)
+	}
+	if f.fov.SightLimit != 0 {
+		t.Errorf("sight limit after the storm cleared = %d, want 0", f.fov.SightLimit)
+	}
+	if effects, exists := f.world.GetComponent(f.playerID, components.Effect); exists {
+		if n := len(effects.(*components.EffectComponent).Effects); n != 0 {
+			t.Errorf("%d effects left on the player after the storm cleared, want none", n)
+		}
+	}
+
+	f.fovSys.Update(f.world, 0)
+	if !f.gameMap.Visible[0][0] {
+		t.Error("the far corner isn't visible under clear skies")
+	}
+}
+
+func TestWeatherDisabled(t *testing.T) {
+	f := newWeatherFixture(t)
+	f.world.EmitEvent(TurnCompletedEvent{})
+
+	f.weather.SetEnabled(false)
+	f.world.EmitEvent(TurnCompletedEvent{})
+	if f.weather.CurrentWeather() != nil {
+		t.Errorf("weather = %s with weather turned off, want clear skies", f.weather.CurrentWeather().Name)
+	}
+	if f.fov.SightLimit != 0 {
+		t.Errorf("sight limit with weather turned off = %d, want 0", f.fov.SightLimit)
+	}
+}
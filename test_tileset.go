@@ -24,12 +24,12 @@ type TilesetViewer struct {
 	filename      string // Tileset filename
 }
 
-// NewTilesetViewer creates a new tileset viewer
-func NewTilesetViewer(filename string, tileSize int) *TilesetViewer {
+// NewTilesetViewer creates a new tileset viewer, failing if the tileset can't be loaded
+func NewTilesetViewer(filename string, tileSize int) (*TilesetViewer, error) {
 	// Create the tileset
 	tileset, err := systems.NewTileset(filename, tileSize)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	// Calculate how many tiles we can fit on screen
@@ -46,7 +46,7 @@ func NewTilesetViewer(filename string, tileSize int) *TilesetViewer {
 		offsetX:       0,
 		offsetY:       0,
 		filename:      filename,
-	}
+	}, nil
 }
 
 // Update handles input for scrolling